@@ -1,29 +1,65 @@
 package main
 
 import (
+	"flag"
+	"os"
+
 	"go.uber.org/fx"
 
+	"powerkonnekt/ems/internal/afrr"
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/analyzer/ion7400"
 	"powerkonnekt/ems/internal/api"
+	"powerkonnekt/ems/internal/auth"
 	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/chaos"
 	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/configversion"
 	"powerkonnekt/ems/internal/control"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/declaration"
 	"powerkonnekt/ems/internal/ems"
+	"powerkonnekt/ems/internal/estimator"
+	"powerkonnekt/ems/internal/fcr"
+	"powerkonnekt/ems/internal/features"
 	"powerkonnekt/ems/internal/health"
+	"powerkonnekt/ems/internal/historian"
+	"powerkonnekt/ems/internal/inventory"
 	"powerkonnekt/ems/internal/logger"
 	"powerkonnekt/ems/internal/metrics"
 	"powerkonnekt/ems/internal/modbus"
+	"powerkonnekt/ems/internal/mqtt"
 	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/peer"
 	"powerkonnekt/ems/internal/plc"
+	"powerkonnekt/ems/internal/powerquality"
+	"powerkonnekt/ems/internal/recorder"
+	"powerkonnekt/ems/internal/rules"
+	"powerkonnekt/ems/internal/schedule"
+	"powerkonnekt/ems/internal/season"
+	"powerkonnekt/ems/internal/selfcheck"
+	"powerkonnekt/ems/internal/selftest"
+	"powerkonnekt/ems/internal/shadow"
+	"powerkonnekt/ems/internal/site"
+	"powerkonnekt/ems/internal/telemetry"
+	"powerkonnekt/ems/internal/warranty"
+	"powerkonnekt/ems/internal/weather"
 	"powerkonnekt/ems/internal/windfarm"
 )
 
 func main() {
+	check := flag.Bool("check", false, "run the startup self-test instead of starting the service")
+	checkConnect := flag.Bool("check-connect", false, "with --check, also probe device and database connectivity")
+	flag.Parse()
+
+	if *check || *checkConnect {
+		os.Exit(selfcheck.Run(*checkConnect))
+	}
+
 	app := fx.New(
 		// Configuration
 		config.Module,
+		configversion.Module,
 
 		// Logging
 		logger.Module,
@@ -35,6 +71,11 @@ func main() {
 		// Core services
 		alarm.Module,
 		metrics.Module,
+		chaos.Module,
+		features.Module,
+		telemetry.Module,
+		season.Module,
+		weather.Module,
 
 		// Device managers
 		bms.Module,
@@ -42,9 +83,35 @@ func main() {
 		plc.Module,
 		windfarm.Module,
 		ion7400.Module,
+		site.Module,
+		rules.Module,
+		shadow.Module,
+		estimator.Module,
+		powerquality.Module,
 
 		// Control logic
 		control.Module,
+		fcr.Module,
+		afrr.Module,
+		schedule.Module,
+		selftest.Module,
+		declaration.Module,
+
+		// Shared connection-point coordination
+		peer.Module,
+
+		// Flight recorder
+		recorder.Module,
+
+		// External telemetry publishing
+		mqtt.Module,
+		historian.Module,
+
+		// Asset-management registration
+		inventory.Module,
+
+		// Warranty reporting
+		warranty.Module,
 
 		// Modbus server
 		modbus.Module,
@@ -53,6 +120,7 @@ func main() {
 		health.Module,
 
 		// API server
+		auth.Module,
 		api.Module,
 
 		// EMS
@@ -0,0 +1,217 @@
+// Command emsconform runs a protocol conformance check against a real device
+// or a simulator during factory acceptance tests. It reads every register
+// block documented for the selected device profile, validates the response
+// lengths, and optionally verifies that the writable registers accept writes
+// (by writing back their current values). The result is a conformance report
+// on stdout; the exit code is non-zero when any check fails.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"powerkonnekt/ems/internal/analyzer/ion7400"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/registerdoc"
+	"powerkonnekt/ems/internal/windfarm"
+	"powerkonnekt/ems/pkg/modbus"
+)
+
+// profiles maps the selectable device profiles to their register
+// documentation
+var profiles = map[string]func() registerdoc.Device{
+	"bms":      bms.RegisterDocumentation,
+	"pcs":      pcs.RegisterDocumentation,
+	"windfarm": windfarm.RegisterDocumentation,
+	"ion7400":  ion7400.RegisterDocumentation,
+}
+
+// CheckResult is the outcome of one conformance check
+type CheckResult struct {
+	Block   string `json:"block"`
+	Check   string `json:"check"`
+	Address int    `json:"address"`
+	Length  int    `json:"length,omitempty"`
+	Passed  bool   `json:"passed"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// Report is the full conformance report
+type Report struct {
+	Device    string        `json:"device"`
+	Host      string        `json:"host"`
+	Timestamp time.Time     `json:"timestamp"`
+	Checks    []CheckResult `json:"checks"`
+	Passed    int           `json:"passed"`
+	Failed    int           `json:"failed"`
+}
+
+func main() {
+	device := flag.String("device", "", "device profile: bms, pcs, windfarm or ion7400")
+	host := flag.String("host", "", "device host or IP")
+	port := flag.Int("port", 502, "Modbus TCP port")
+	slaveID := flag.Int("slave", 1, "Modbus slave ID")
+	timeout := flag.Duration("timeout", 5*time.Second, "request timeout")
+	unit := flag.Int("unit", 1, "unit number for blocks with a per-unit address offset")
+	write := flag.Bool("write", false, "verify writable registers by writing back their current values")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	doc, ok := profiles[*device]
+	if !ok || *host == "" {
+		fmt.Fprintln(os.Stderr, "Usage: emsconform -device <bms|pcs|windfarm|ion7400> -host <address> [flags]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	client := modbus.NewClient(*host, *port, byte(*slaveID), *timeout)
+	if err := client.Connect(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Connect failed: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Disconnect()
+
+	report := runConformance(ctx, client, doc(), *unit, *write)
+	report.Host = *host
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		printReport(report)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runConformance runs every check of the device profile and collects the
+// report
+func runConformance(ctx context.Context, client *modbus.Client, doc registerdoc.Device, unit int, write bool) Report {
+	report := Report{
+		Device:    doc.Device,
+		Timestamp: time.Now(),
+	}
+
+	for _, block := range doc.Blocks {
+		startAddr := block.StartAddr + (unit-1)*block.UnitOffset
+
+		if block.Direction == registerdoc.DirectionRead && block.Length > 0 {
+			report.add(checkBlockRead(ctx, client, block, startAddr))
+		}
+
+		if !write {
+			continue
+		}
+		for _, point := range block.Points {
+			if point.Direction != registerdoc.DirectionWrite {
+				continue
+			}
+			address := point.Address + (unit-1)*block.UnitOffset
+			report.add(checkWriteBack(ctx, client, block, point, address))
+		}
+	}
+
+	return report
+}
+
+// checkBlockRead reads a documented block and validates the response length
+func checkBlockRead(ctx context.Context, client *modbus.Client, block registerdoc.Block, startAddr int) CheckResult {
+	result := CheckResult{
+		Block:   block.Name,
+		Check:   "read",
+		Address: startAddr,
+		Length:  block.Length,
+	}
+
+	data, err := readBlock(ctx, client, block.Table, startAddr, block.Length)
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+
+	if len(data) != block.Length*2 {
+		result.Detail = fmt.Sprintf("got %d bytes, want %d", len(data), block.Length*2)
+		return result
+	}
+
+	result.Passed = true
+	return result
+}
+
+// checkWriteBack verifies that a writable register accepts a write by
+// writing back its current value, so the device state is not changed
+func checkWriteBack(ctx context.Context, client *modbus.Client, block registerdoc.Block, point registerdoc.Point, address int) CheckResult {
+	result := CheckResult{
+		Block:   block.Name,
+		Check:   "write " + point.Name,
+		Address: address,
+	}
+
+	data, err := client.ReadHoldingRegisters(ctx, uint16(address), 1)
+	if err != nil {
+		result.Detail = fmt.Sprintf("read before write: %v", err)
+		return result
+	}
+	if len(data) < 2 {
+		result.Detail = fmt.Sprintf("read before write: got %d bytes, want 2", len(data))
+		return result
+	}
+
+	value := uint16(data[0])<<8 | uint16(data[1])
+	if err := client.WriteSingleRegister(ctx, uint16(address), value); err != nil {
+		result.Detail = fmt.Sprintf("write rejected: %v", err)
+		return result
+	}
+
+	result.Passed = true
+	result.Detail = fmt.Sprintf("wrote back value %d", value)
+	return result
+}
+
+// readBlock reads a register block from the documented table
+func readBlock(ctx context.Context, client *modbus.Client, table string, startAddr, length int) ([]byte, error) {
+	if table == "input" {
+		return client.ReadInputRegisters(ctx, uint16(startAddr), uint16(length))
+	}
+	return client.ReadHoldingRegisters(ctx, uint16(startAddr), uint16(length))
+}
+
+// add appends a check result and updates the counters
+func (r *Report) add(result CheckResult) {
+	r.Checks = append(r.Checks, result)
+	if result.Passed {
+		r.Passed++
+	} else {
+		r.Failed++
+	}
+}
+
+// printReport prints the report as a table
+func printReport(report Report) {
+	fmt.Printf("Conformance report: %s @ %s\n\n", report.Device, report.Host)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "BLOCK\tCHECK\tADDR\tRESULT\tDETAIL")
+	for _, check := range report.Checks {
+		status := "PASS"
+		if !check.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%s\t%s\n", check.Block, check.Check, check.Address, status, check.Detail)
+	}
+	writer.Flush()
+
+	fmt.Printf("\n%d passed, %d failed\n", report.Passed, report.Failed)
+}
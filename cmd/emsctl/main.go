@@ -0,0 +1,353 @@
+// Command emsctl is the administrative client for the EMS REST API. It wraps
+// the common operator actions (status, mode changes, power commands, alarm
+// acknowledgement, diagnostics collection) behind one tool with per-site
+// profiles, replacing the curl snippets operators keep in text files.
+//
+// Profiles live in ~/.config/emsctl/profiles.json as a map of profile name to
+// settings, e.g. {"polat": {"url": "https://10.1.2.3:8080"}}. The -url flag
+// overrides the profile for ad-hoc use.
+//
+// Output is a key/value table by default; -json prints the raw API response
+// for scripting.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// usage lists the available commands
+const usage = `Usage: emsctl [flags] <command> [arguments]
+
+Commands:
+  status                      show the aggregated system status
+  mode <AUTO|MANUAL|MAINTENANCE>  change the control mode
+  power <pcs-id> <kw>         command active power for one PCS
+  fcr                         show the FCR state and activation
+  alarms                      list the active alarms
+  ack <alarm-type> <code>     acknowledge an alarm
+  diag [file]                 collect a diagnostics bundle (default: stdout)
+
+Flags:
+`
+
+// profile holds the connection settings of one site
+type profile struct {
+	URL     string        `json:"url"`
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// diagEndpoints are the API endpoints collected into a diagnostics bundle
+var diagEndpoints = map[string]string{
+	"health":            "/health",
+	"status":            "/api/v1/status",
+	"alarms":            "/api/v1/alarms",
+	"site_limits":       "/api/v1/site/limits",
+	"site_balance":      "/api/v1/site/balance",
+	"control_explain":   "/api/v1/control/explain",
+	"fcr_state":         "/api/v1/fcr/state",
+	"modbus_exceptions": "/api/v1/diagnostics/modbus-exceptions",
+	"schema":            "/api/v1/diagnostics/schema",
+}
+
+// client talks to one EMS instance
+type client struct {
+	baseURL string
+	http    *http.Client
+}
+
+func main() {
+	profileName := flag.String("profile", "default", "profile name from ~/.config/emsctl/profiles.json")
+	urlOverride := flag.String("url", "", "EMS base URL, overrides the profile")
+	timeout := flag.Duration("timeout", 10*time.Second, "request timeout")
+	jsonOutput := flag.Bool("json", false, "print raw JSON responses")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	baseURL, err := resolveBaseURL(*profileName, *urlOverride, timeout)
+	if err != nil {
+		fatal(err)
+	}
+
+	c := &client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: *timeout},
+	}
+
+	command := flag.Arg(0)
+	args := flag.Args()[1:]
+
+	if err := run(c, command, args, *jsonOutput); err != nil {
+		fatal(err)
+	}
+}
+
+// run dispatches one command
+func run(c *client, command string, args []string, jsonOutput bool) error {
+	switch command {
+	case "status":
+		return c.show("/api/v1/status", jsonOutput)
+
+	case "mode":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: emsctl mode <AUTO|MANUAL|MAINTENANCE>")
+		}
+		return c.post("/api/v1/control/mode", map[string]interface{}{
+			"mode": strings.ToUpper(args[0]),
+		}, jsonOutput)
+
+	case "power":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: emsctl power <pcs-id> <kw>")
+		}
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid PCS id: %s", args[0])
+		}
+		power, err := strconv.ParseFloat(args[1], 32)
+		if err != nil {
+			return fmt.Errorf("invalid power value: %s", args[1])
+		}
+		return c.post("/api/v1/control/active-power", map[string]interface{}{
+			"id":    id,
+			"power": power,
+		}, jsonOutput)
+
+	case "fcr":
+		return c.show("/api/v1/fcr/state", jsonOutput)
+
+	case "alarms":
+		return c.show("/api/v1/alarms", jsonOutput)
+
+	case "ack":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: emsctl ack <alarm-type> <code>")
+		}
+		code, err := strconv.ParseUint(args[1], 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid alarm code: %s", args[1])
+		}
+		return c.post("/api/v1/alarms/acknowledge", map[string]interface{}{
+			"alarm_type": args[0],
+			"alarm_code": code,
+		}, jsonOutput)
+
+	case "diag":
+		file := ""
+		if len(args) > 0 {
+			file = args[0]
+		}
+		return c.collectDiagnostics(file)
+
+	default:
+		return fmt.Errorf("unknown command: %s", command)
+	}
+}
+
+// resolveBaseURL picks the base URL from the override flag or the profile
+// file; the default profile falls back to localhost so a locally installed
+// emsctl works without configuration
+func resolveBaseURL(name, override string, timeout *time.Duration) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err == nil {
+		data, readErr := os.ReadFile(filepath.Join(home, ".config", "emsctl", "profiles.json"))
+		if readErr == nil {
+			var profiles map[string]profile
+			if err := json.Unmarshal(data, &profiles); err != nil {
+				return "", fmt.Errorf("invalid profiles file: %w", err)
+			}
+			entry, ok := profiles[name]
+			if !ok {
+				return "", fmt.Errorf("unknown profile: %s", name)
+			}
+			if entry.URL == "" {
+				return "", fmt.Errorf("profile %s has no url", name)
+			}
+			if entry.Timeout > 0 {
+				*timeout = entry.Timeout
+			}
+			return entry.URL, nil
+		}
+	}
+
+	if name != "default" {
+		return "", fmt.Errorf("unknown profile: %s (no profiles file)", name)
+	}
+	return "http://localhost:8080", nil
+}
+
+// get fetches one endpoint and returns the response body
+func (c *client) get(path string) ([]byte, error) {
+	resp, err := c.http.Get(c.baseURL + path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// show fetches one endpoint and prints it as a table or raw JSON
+func (c *client) show(path string, jsonOutput bool) error {
+	body, err := c.get(path)
+	if err != nil {
+		return err
+	}
+	return render(body, jsonOutput)
+}
+
+// post sends one JSON command and prints the response
+func (c *client) post(path string, payload map[string]interface{}, jsonOutput bool) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Post(c.baseURL+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return render(body, jsonOutput)
+}
+
+// collectDiagnostics fetches the diagnostics endpoints into one JSON bundle;
+// endpoints that fail are recorded with their error instead of aborting the
+// whole bundle
+func (c *client) collectDiagnostics(file string) error {
+	bundle := map[string]interface{}{
+		"collected_at": time.Now().Format(time.RFC3339),
+		"base_url":     c.baseURL,
+	}
+
+	for name, path := range diagEndpoints {
+		body, err := c.get(path)
+		if err != nil {
+			bundle[name] = map[string]string{"error": err.Error()}
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			bundle[name] = map[string]string{"error": err.Error()}
+			continue
+		}
+		bundle[name] = value
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if file == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Diagnostics bundle written to %s\n", file)
+	return nil
+}
+
+// render prints a response as raw JSON or a flattened key/value table
+func render(body []byte, jsonOutput bool) error {
+	if jsonOutput {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, body, "", "  "); err != nil {
+			fmt.Println(string(body))
+			return nil
+		}
+		fmt.Println(buf.String())
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		fmt.Println(string(body))
+		return nil
+	}
+
+	rows := map[string]string{}
+	flatten("", value, rows)
+
+	keys := make([]string, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, key := range keys {
+		fmt.Fprintf(writer, "%s\t%s\n", key, rows[key])
+	}
+	return writer.Flush()
+}
+
+// flatten converts nested JSON into dotted key/value rows for table output
+func flatten(prefix string, value interface{}, rows map[string]string) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			name := key
+			if prefix != "" {
+				name = prefix + "." + key
+			}
+			flatten(name, child, rows)
+		}
+	case []interface{}:
+		for i, child := range typed {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), child, rows)
+		}
+	case float64:
+		rows[prefix] = strconv.FormatFloat(typed, 'f', -1, 64)
+	case nil:
+		rows[prefix] = ""
+	default:
+		rows[prefix] = fmt.Sprintf("%v", typed)
+	}
+}
+
+// fatal prints the error and exits
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "emsctl:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,244 @@
+// Command loadtest hammers the EMS REST API and internal Modbus server with
+// configurable concurrency and read/write mixes, and reports latency
+// percentiles and error rates. It is used to validate a site gateway's sizing
+// before deployment.
+//
+// API write traffic is always sent with dry_run=true so the tool can be
+// pointed at a live gateway without issuing real commands. Modbus traffic is
+// read-only.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"powerkonnekt/ems/pkg/modbus"
+)
+
+// readEndpoints is the REST read mix: cheap status endpoints plus the heavier
+// aggregated telemetry endpoint
+var readEndpoints = []string{
+	"/api/v1/status",
+	"/api/v1/telemetry",
+	"/api/v1/alarms",
+	"/api/v1/site/limits",
+	"/api/v1/kpi",
+}
+
+// stats collects per-target request latencies and error counts
+type stats struct {
+	mutex     sync.Mutex
+	latencies []time.Duration
+	errors    int
+}
+
+// record stores one request outcome
+func (s *stats) record(latency time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err != nil {
+		s.errors++
+		return
+	}
+	s.latencies = append(s.latencies, latency)
+}
+
+// percentile returns the given percentile from a sorted latency slice
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	index := int(float64(len(sorted)-1) * p)
+	return sorted[index]
+}
+
+// report prints the summary for one target
+func (s *stats) report(name string, elapsed time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	total := len(s.latencies) + s.errors
+	if total == 0 {
+		return
+	}
+
+	sorted := make([]time.Duration, len(s.latencies))
+	copy(sorted, s.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	fmt.Printf("\n%s\n", name)
+	fmt.Printf("  requests:   %d (%.1f req/s)\n", total, float64(total)/elapsed.Seconds())
+	fmt.Printf("  errors:     %d (%.2f%%)\n", s.errors, float64(s.errors)/float64(total)*100)
+	if len(sorted) > 0 {
+		fmt.Printf("  p50:        %v\n", percentile(sorted, 0.50))
+		fmt.Printf("  p90:        %v\n", percentile(sorted, 0.90))
+		fmt.Printf("  p99:        %v\n", percentile(sorted, 0.99))
+		fmt.Printf("  max:        %v\n", sorted[len(sorted)-1])
+	}
+}
+
+// apiWorker issues REST requests until the context is cancelled
+func apiWorker(ctx context.Context, baseURL string, writeRatio float64, results *stats) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for ctx.Err() == nil {
+		var err error
+		start := time.Now()
+
+		if rng.Float64() < writeRatio {
+			err = apiWrite(ctx, client, baseURL, rng)
+		} else {
+			err = apiRead(ctx, client, baseURL, readEndpoints[rng.Intn(len(readEndpoints))])
+		}
+
+		results.record(time.Since(start), err)
+	}
+}
+
+// apiRead issues one GET request against a read endpoint
+func apiRead(ctx context.Context, client *http.Client, baseURL string, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%s returned %d", endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+// apiWrite issues one dry-run power command so the write path is exercised
+// without commanding the site
+func apiWrite(ctx context.Context, client *http.Client, baseURL string, rng *rand.Rand) error {
+	power := rng.Float64()*200 - 100
+	body := fmt.Sprintf(`{"pcs_id":1,"power":%.1f,"dry_run":true}`, power)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		baseURL+"/api/v1/control/active-power", bytes.NewBufferString(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("dry-run write returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// modbusWorker issues holding register reads until the context is cancelled
+func modbusWorker(ctx context.Context, host string, port int, slaveID byte, startAddr, quantity uint16, results *stats) {
+	client := modbus.NewClient(host, port, slaveID, 10*time.Second)
+	if err := client.Connect(ctx); err != nil {
+		results.record(0, err)
+		return
+	}
+	defer client.Disconnect()
+
+	for ctx.Err() == nil {
+		start := time.Now()
+		_, err := client.ReadHoldingRegisters(ctx, startAddr, quantity)
+		results.record(time.Since(start), err)
+
+		if err != nil && !client.IsConnected() {
+			if err := client.Connect(ctx); err != nil {
+				time.Sleep(time.Second)
+			}
+		}
+	}
+}
+
+func main() {
+	apiURL := flag.String("api-url", "http://localhost:8080", "base URL of the EMS REST API (empty to skip)")
+	writeRatio := flag.Float64("write-ratio", 0.1, "fraction of API requests issued as dry-run writes")
+	modbusHost := flag.String("modbus-host", "", "host of the EMS Modbus server (empty to skip)")
+	modbusPort := flag.Int("modbus-port", 1502, "port of the EMS Modbus server")
+	modbusSlaveID := flag.Int("modbus-slave-id", 1, "slave ID for Modbus requests")
+	modbusAddr := flag.Int("modbus-addr", 0, "start address for Modbus holding register reads")
+	modbusQuantity := flag.Int("modbus-quantity", 10, "number of registers per Modbus read")
+	concurrency := flag.Int("concurrency", 10, "number of concurrent workers per target")
+	duration := flag.Duration("duration", 30*time.Second, "how long to run the load test")
+	flag.Parse()
+
+	if *apiURL == "" && *modbusHost == "" {
+		fmt.Fprintln(os.Stderr, "nothing to do: both -api-url and -modbus-host are empty")
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	apiStats := &stats{}
+	modbusStats := &stats{}
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	if *apiURL != "" {
+		for range *concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				apiWorker(ctx, *apiURL, *writeRatio, apiStats)
+			}()
+		}
+	}
+
+	if *modbusHost != "" {
+		for range *concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				modbusWorker(ctx, *modbusHost, *modbusPort, byte(*modbusSlaveID),
+					uint16(*modbusAddr), uint16(*modbusQuantity), modbusStats)
+			}()
+		}
+	}
+
+	fmt.Printf("running load test for %v with %d workers per target\n", *duration, *concurrency)
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	apiStats.report("REST API", elapsed)
+	modbusStats.report("Modbus server", elapsed)
+}
@@ -0,0 +1,321 @@
+// Command provision-dashboards generates Grafana dashboards matching the
+// current device configuration and pushes them through the Grafana HTTP
+// API, so every new site gets consistent monitoring without manual
+// dashboard cloning. The InfluxDB datasource is created when missing and
+// the site dashboard is rebuilt with one row per PCS, BMS and wind farm
+// unit plus per-rack SOC panels.
+//
+// The Grafana API token is taken from the -token flag or the
+// GRAFANA_API_TOKEN environment variable and needs editor rights.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// datasourceName is the Grafana datasource the generated panels query
+const datasourceName = "EMS InfluxDB"
+
+func main() {
+	grafanaURL := flag.String("grafana", "http://localhost:3000", "Grafana base URL")
+	token := flag.String("token", os.Getenv("GRAFANA_API_TOKEN"), "Grafana API token (or GRAFANA_API_TOKEN)")
+	folder := flag.String("folder", "EMS", "Grafana folder for the generated dashboards")
+	dryRun := flag.Bool("dry-run", false, "print the generated dashboard JSON instead of pushing it")
+	flag.Parse()
+
+	cfg, err := config.NewConfig(config.NewValidator())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	dashboard := buildSiteDashboard(cfg)
+
+	if *dryRun {
+		encoded, err := json.MarshalIndent(dashboard, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode dashboard: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(encoded))
+		return
+	}
+
+	if *token == "" {
+		fmt.Fprintln(os.Stderr, "missing Grafana API token: use -token or GRAFANA_API_TOKEN")
+		os.Exit(1)
+	}
+
+	client := &grafanaClient{
+		baseURL: strings.TrimRight(*grafanaURL, "/"),
+		token:   *token,
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+
+	if err := client.ensureDatasource(cfg.InfluxDB); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to provision datasource: %v\n", err)
+		os.Exit(1)
+	}
+
+	folderUID, err := client.ensureFolder(*folder)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to provision folder: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := client.pushDashboard(dashboard, folderUID); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to push dashboard: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("provisioned dashboard %q for %s (%d PCS, %d BMS, %d wind farm units)\n",
+		dashboard["title"], cfg.EMS.ParkName, len(cfg.PCS), len(cfg.BMS), len(cfg.WindFarm))
+}
+
+// grafanaClient is a minimal client for the Grafana provisioning endpoints
+type grafanaClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// request performs an authenticated JSON request and decodes the response
+// into result when it is non-nil
+func (c *grafanaClient) request(method, path string, body interface{}, result interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+c.token)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	payload, err := io.ReadAll(response.Body)
+	if err != nil {
+		return response.StatusCode, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if response.StatusCode >= 300 && response.StatusCode != http.StatusNotFound {
+		return response.StatusCode, fmt.Errorf("%s %s returned %d: %s", method, path, response.StatusCode, payload)
+	}
+
+	if result != nil && response.StatusCode < 300 {
+		if err := json.Unmarshal(payload, result); err != nil {
+			return response.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return response.StatusCode, nil
+}
+
+// ensureDatasource creates the InfluxDB datasource when it does not exist
+func (c *grafanaClient) ensureDatasource(cfg config.InfluxDBConfig) error {
+	status, err := c.request(http.MethodGet, "/api/datasources/name/"+datasourceName, nil, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusOK {
+		return nil
+	}
+
+	datasource := map[string]interface{}{
+		"name":   datasourceName,
+		"type":   "influxdb",
+		"access": "proxy",
+		"url":    cfg.URL,
+		"jsonData": map[string]interface{}{
+			"version":       "Flux",
+			"organization":  cfg.Organization,
+			"defaultBucket": cfg.Bucket,
+		},
+		"secureJsonData": map[string]interface{}{
+			"token": cfg.Token,
+		},
+	}
+	_, err = c.request(http.MethodPost, "/api/datasources", datasource, nil)
+	return err
+}
+
+// ensureFolder creates the dashboard folder when it does not exist and
+// returns its UID
+func (c *grafanaClient) ensureFolder(title string) (string, error) {
+	var folders []struct {
+		UID   string `json:"uid"`
+		Title string `json:"title"`
+	}
+	if _, err := c.request(http.MethodGet, "/api/folders", nil, &folders); err != nil {
+		return "", err
+	}
+	for _, folder := range folders {
+		if folder.Title == title {
+			return folder.UID, nil
+		}
+	}
+
+	var created struct {
+		UID string `json:"uid"`
+	}
+	if _, err := c.request(http.MethodPost, "/api/folders", map[string]interface{}{"title": title}, &created); err != nil {
+		return "", err
+	}
+	return created.UID, nil
+}
+
+// pushDashboard creates or overwrites the dashboard in the given folder
+func (c *grafanaClient) pushDashboard(dashboard map[string]interface{}, folderUID string) error {
+	payload := map[string]interface{}{
+		"dashboard": dashboard,
+		"folderUid": folderUID,
+		"overwrite": true,
+		"message":   "provisioned from EMS configuration",
+	}
+	_, err := c.request(http.MethodPost, "/api/dashboards/db", payload, nil)
+	return err
+}
+
+// buildSiteDashboard assembles the site dashboard from the device
+// configuration: one row per PCS, per BMS (with per-rack SOC panels) and
+// per wind farm unit, plus the grid analyzer
+func buildSiteDashboard(cfg *config.Config) map[string]interface{} {
+	var panels []map[string]interface{}
+	y := 0
+
+	for _, pcsCfg := range cfg.PCS {
+		panels = append(panels, rowPanel(fmt.Sprintf("PCS %d", pcsCfg.ID), y))
+		y++
+		panels = append(panels,
+			timeSeriesPanel("Active Power", fluxQuery(cfg.InfluxDB.Bucket, "pcs", pcsCfg.ID, "mv_grid_active_power"), 0, y),
+			timeSeriesPanel("Reactive Power", fluxQuery(cfg.InfluxDB.Bucket, "pcs", pcsCfg.ID, "mv_grid_reactive_power"), 12, y),
+		)
+		y += panelHeight
+	}
+
+	for _, bmsCfg := range cfg.BMS {
+		panels = append(panels, rowPanel(fmt.Sprintf("BMS %d", bmsCfg.ID), y))
+		y++
+		panels = append(panels,
+			timeSeriesPanel("SOC", fluxQuery(cfg.InfluxDB.Bucket, "bms", bmsCfg.ID, "soc"), 0, y),
+			timeSeriesPanel("Power", fluxQuery(cfg.InfluxDB.Bucket, "bms", bmsCfg.ID, "power"), 12, y),
+		)
+		y += panelHeight
+
+		for rack := 1; rack <= bmsCfg.RackCount; rack++ {
+			x := ((rack - 1) % 4) * 6
+			if rack > 1 && x == 0 {
+				y += panelHeight
+			}
+			panels = append(panels, rackPanel(cfg.InfluxDB.Bucket, bmsCfg.ID, rack, x, y))
+		}
+		y += panelHeight
+	}
+
+	for _, windFarmCfg := range cfg.WindFarm {
+		panels = append(panels, rowPanel(fmt.Sprintf("Wind Farm %d", windFarmCfg.ID), y))
+		y++
+		panels = append(panels,
+			timeSeriesPanel("Active Power", fluxQuery(cfg.InfluxDB.Bucket, "windfarm_measuring", windFarmCfg.ID, "active_power_ncp"), 0, y),
+			timeSeriesPanel("Wind Speed", fluxQuery(cfg.InfluxDB.Bucket, "windfarm_measuring", windFarmCfg.ID, "wind_speed"), 12, y),
+		)
+		y += panelHeight
+	}
+
+	panels = append(panels, rowPanel("Grid Analyzer", y))
+	y++
+	panels = append(panels,
+		timeSeriesPanel("Grid Active Power", analyzerQuery(cfg.InfluxDB.Bucket, "active_power_sum"), 0, y),
+		timeSeriesPanel("Grid Frequency", analyzerQuery(cfg.InfluxDB.Bucket, "frequency"), 12, y),
+	)
+
+	return map[string]interface{}{
+		"uid":           "ems-site-overview",
+		"title":         fmt.Sprintf("%s - Site Overview", cfg.EMS.ParkName),
+		"tags":          []string{"ems", "generated"},
+		"timezone":      "browser",
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"panels":        panels,
+	}
+}
+
+// panelHeight is the grid height of every generated panel
+const panelHeight = 8
+
+// rowPanel creates a collapsible row separator
+func rowPanel(title string, y int) map[string]interface{} {
+	return map[string]interface{}{
+		"type":    "row",
+		"title":   title,
+		"gridPos": map[string]int{"h": 1, "w": 24, "x": 0, "y": y},
+	}
+}
+
+// timeSeriesPanel creates a half-width time series panel for one Flux query
+func timeSeriesPanel(title, query string, x, y int) map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "timeseries",
+		"title":      title,
+		"gridPos":    map[string]int{"h": panelHeight, "w": 12, "x": x, "y": y},
+		"datasource": map[string]string{"type": "influxdb", "uid": datasourceName},
+		"targets": []map[string]interface{}{
+			{"refId": "A", "query": query},
+		},
+	}
+}
+
+// rackPanel creates a quarter-width SOC panel for one BMS rack
+func rackPanel(bucket string, bmsID, rack, x, y int) map[string]interface{} {
+	query := fmt.Sprintf(`from(bucket: %q)
+  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)
+  |> filter(fn: (r) => r._measurement == "bms_rack" and r.id == "%d" and r.number == "%d" and r._field == "soc")`,
+		bucket, bmsID, rack)
+
+	return map[string]interface{}{
+		"type":       "timeseries",
+		"title":      fmt.Sprintf("Rack %d SOC", rack),
+		"gridPos":    map[string]int{"h": panelHeight, "w": 6, "x": x, "y": y},
+		"datasource": map[string]string{"type": "influxdb", "uid": datasourceName},
+		"targets": []map[string]interface{}{
+			{"refId": "A", "query": query},
+		},
+	}
+}
+
+// fluxQuery builds the standard per-device field query
+func fluxQuery(bucket, measurement string, id int, field string) string {
+	return fmt.Sprintf(`from(bucket: %q)
+  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)
+  |> filter(fn: (r) => r._measurement == %q and r.id == "%d" and r._field == %q)`,
+		bucket, measurement, id, field)
+}
+
+// analyzerQuery builds the grid analyzer field query (single device, no id)
+func analyzerQuery(bucket, field string) string {
+	return fmt.Sprintf(`from(bucket: %q)
+  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)
+  |> filter(fn: (r) => r._measurement == "ion7400" and r._field == %q)`,
+		bucket, field)
+}
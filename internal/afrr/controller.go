@@ -0,0 +1,412 @@
+// Package afrr implements automatic frequency restoration reserve
+// delivery. Unlike FCR, aFRR is not derived from a local frequency
+// measurement: the TSO sends an explicit power setpoint over the SCADA
+// link, and the controller ramps the BESS to follow it within the
+// configured gradient limits. The deviation from the baseline captured at
+// activation is integrated per direction for settlement.
+package afrr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/pkg/loopwatch"
+)
+
+// settlementReportInterval is the cadence of the settlement reporting slow
+// path, decoupled from the dispatch loop
+const settlementReportInterval = 10 * time.Second
+
+// dispatchDeadbandKW suppresses re-dispatching outputs that have not
+// meaningfully changed
+const dispatchDeadbandKW = 0.01
+
+// State is a snapshot of the aFRR delivery
+type State struct {
+	Active                 bool      `json:"active"`
+	ActivatedAt            time.Time `json:"activated_at,omitempty"`
+	ActivatedBy            string    `json:"activated_by,omitempty"`
+	SetpointKW             float32   `json:"setpoint_kw"`
+	SetpointAgeSeconds     float64   `json:"setpoint_age_seconds"`
+	OutputKW               float32   `json:"output_kw"`
+	BaselineKW             float32   `json:"baseline_kw"`
+	CapacityUpKW           float32   `json:"capacity_up_kw"`
+	CapacityDownKW         float32   `json:"capacity_down_kw"`
+	ActivatedEnergyUpKWh   float64   `json:"activated_energy_up_kwh"`
+	ActivatedEnergyDownKWh float64   `json:"activated_energy_down_kwh"`
+}
+
+// Controller runs the aFRR setpoint-following loop
+type Controller struct {
+	config       config.AFRRConfig
+	pcsManager   *pcs.Manager
+	controlLogic *control.Logic
+	influxDB     *database.InfluxDB
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	log          *zap.Logger
+	loopWatch    *loopwatch.Loop
+
+	mutex          sync.RWMutex
+	active         bool
+	activatedAt    time.Time
+	activatedBy    string
+	setpointKW     float32
+	setpointAt     time.Time
+	outputKW       float32
+	baselineKW     float32
+	energyUpKWh    float64
+	energyDownKWh  float64
+	capacityUpKW   float32
+	capacityDownKW float32
+	lastTick       time.Time
+	dispatching    bool
+}
+
+// NewController creates the aFRR controller
+func NewController(cfg config.AFRRConfig, pcsManager *pcs.Manager, controlLogic *control.Logic, influxDB *database.InfluxDB, logger *zap.Logger) *Controller {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Controller{
+		config:         cfg,
+		pcsManager:     pcsManager,
+		controlLogic:   controlLogic,
+		influxDB:       influxDB,
+		capacityUpKW:   cfg.CapacityUpKW,
+		capacityDownKW: cfg.CapacityDownKW,
+		loopWatch:      loopwatch.NewLoop("afrr_dispatch", cfg.LoopInterval),
+		ctx:            ctx,
+		cancel:         cancel,
+		log: logger.With(
+			zap.String("service", "afrr"),
+		),
+	}
+}
+
+// Start starts the dispatch loop and the settlement reporting slow path
+func (c *Controller) Start() error {
+	if !c.config.Enabled {
+		c.log.Info("aFRR delivery disabled")
+		return nil
+	}
+
+	c.wg.Go(c.dispatchLoop)
+	c.wg.Go(c.reportLoop)
+
+	c.log.Info("aFRR delivery started",
+		zap.Duration("loop_interval", c.config.LoopInterval),
+		zap.Float32("max_ramp_rate_kw_per_min", c.config.MaxRampRateKWPerMin))
+	return nil
+}
+
+// Stop stops the dispatch loop
+func (c *Controller) Stop() {
+	c.cancel()
+	c.wg.Wait()
+	if c.config.Enabled {
+		c.log.Info("aFRR delivery stopped")
+	}
+}
+
+// Activate begins an aFRR delivery period: the baseline power is captured,
+// the settlement energies restart, and TSO setpoints are accepted
+func (c *Controller) Activate(operator string) error {
+	if !c.config.Enabled {
+		return fmt.Errorf("aFRR delivery is disabled")
+	}
+
+	baseline := c.baselinePowerKW()
+
+	c.mutex.Lock()
+	if c.active {
+		c.mutex.Unlock()
+		return fmt.Errorf("aFRR delivery is already active")
+	}
+	c.active = true
+	c.activatedAt = time.Now()
+	c.activatedBy = operator
+	c.baselineKW = baseline
+	c.setpointKW = 0
+	c.setpointAt = time.Time{}
+	c.energyUpKWh = 0
+	c.energyDownKWh = 0
+	c.mutex.Unlock()
+
+	c.log.Info("aFRR delivery activated",
+		zap.String("operator", operator),
+		zap.Float32("baseline_kw", baseline))
+	return nil
+}
+
+// Deactivate ends the delivery period; the output ramps back to zero along
+// the gradient limit and the claims are released once it settles
+func (c *Controller) Deactivate(operator string) error {
+	c.mutex.Lock()
+	if !c.active {
+		c.mutex.Unlock()
+		return fmt.Errorf("aFRR delivery is not active")
+	}
+	c.active = false
+	energyUp := c.energyUpKWh
+	energyDown := c.energyDownKWh
+	c.mutex.Unlock()
+
+	c.log.Info("aFRR delivery deactivated",
+		zap.String("operator", operator),
+		zap.Float64("activated_energy_up_kwh", energyUp),
+		zap.Float64("activated_energy_down_kwh", energyDown))
+	return nil
+}
+
+// SetSetpoint records an aFRR power setpoint from the TSO link, positive
+// requesting upward (discharge) activation
+func (c *Controller) SetSetpoint(powerKW float32, source string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.active {
+		return fmt.Errorf("aFRR delivery is not active")
+	}
+	if powerKW > c.capacityUpKW {
+		return fmt.Errorf("setpoint %.1f kW exceeds the contracted upward capacity %.1f kW", powerKW, c.capacityUpKW)
+	}
+	if -powerKW > c.capacityDownKW {
+		return fmt.Errorf("setpoint %.1f kW exceeds the contracted downward capacity %.1f kW", powerKW, c.capacityDownKW)
+	}
+
+	c.setpointKW = powerKW
+	c.setpointAt = time.Now()
+
+	c.log.Debug("aFRR setpoint received",
+		zap.Float32("setpoint_kw", powerKW),
+		zap.String("source", source))
+	return nil
+}
+
+// SetCapacity updates the contracted capacity per direction
+func (c *Controller) SetCapacity(upKW, downKW float32, operator string) error {
+	if upKW < 0 || downKW < 0 {
+		return fmt.Errorf("capacity must not be negative")
+	}
+
+	c.mutex.Lock()
+	c.capacityUpKW = upKW
+	c.capacityDownKW = downKW
+	c.mutex.Unlock()
+
+	c.log.Info("aFRR contracted capacity updated",
+		zap.Float32("capacity_up_kw", upKW),
+		zap.Float32("capacity_down_kw", downKW),
+		zap.String("operator", operator))
+	return nil
+}
+
+// GetState returns a snapshot of the aFRR delivery
+func (c *Controller) GetState() State {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	state := State{
+		Active:                 c.active,
+		ActivatedAt:            c.activatedAt,
+		ActivatedBy:            c.activatedBy,
+		SetpointKW:             c.setpointKW,
+		OutputKW:               c.outputKW,
+		BaselineKW:             c.baselineKW,
+		CapacityUpKW:           c.capacityUpKW,
+		CapacityDownKW:         c.capacityDownKW,
+		ActivatedEnergyUpKWh:   c.energyUpKWh,
+		ActivatedEnergyDownKWh: c.energyDownKWh,
+	}
+	if !c.setpointAt.IsZero() {
+		state.SetpointAgeSeconds = time.Since(c.setpointAt).Seconds()
+	}
+	return state
+}
+
+// baselinePowerKW captures the power the BESS is scheduled to run at
+// without aFRR: the sum of the current PCS active power commands
+func (c *Controller) baselinePowerKW() float32 {
+	var baseline float32
+	for _, service := range c.pcsManager.GetAllServices() {
+		baseline += service.GetCommandState().ActivePowerCommand
+	}
+	return baseline
+}
+
+// dispatchLoop ramps the output toward the TSO setpoint and dispatches it
+func (c *Controller) dispatchLoop() {
+	ticker := time.NewTicker(c.config.LoopInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			finish := c.loopWatch.Begin()
+			c.tick()
+			finish()
+		}
+	}
+}
+
+// tick advances the ramped output one step and integrates the settlement
+// energies. A stale setpoint ramps back to zero so a dead TSO link cannot
+// hold an activation.
+func (c *Controller) tick() {
+	now := time.Now()
+
+	c.mutex.Lock()
+	dt := now.Sub(c.lastTick)
+	if c.lastTick.IsZero() || dt > 2*c.config.LoopInterval {
+		dt = c.config.LoopInterval
+	}
+	c.lastTick = now
+
+	target := c.setpointKW
+	if !c.active {
+		target = 0
+	}
+	if c.config.SetpointTimeout > 0 && (c.setpointAt.IsZero() || now.Sub(c.setpointAt) > c.config.SetpointTimeout) {
+		target = 0
+	}
+
+	previous := c.outputKW
+	c.outputKW = rampToward(previous, target, c.config.MaxRampRateKWPerMin*float32(dt.Minutes()))
+
+	if c.outputKW > 0 {
+		c.energyUpKWh += float64(c.outputKW) * dt.Hours()
+	} else if c.outputKW < 0 {
+		c.energyDownKWh += float64(-c.outputKW) * dt.Hours()
+	}
+
+	output := c.outputKW
+	baseline := c.baselineKW
+	dispatching := c.dispatching
+	settled := !c.active && output == 0
+	c.dispatching = !settled
+	c.mutex.Unlock()
+
+	if settled {
+		if dispatching {
+			c.releaseAll()
+		}
+		return
+	}
+
+	if dispatching && abs32(output-previous) <= dispatchDeadbandKW {
+		return
+	}
+	c.dispatch(baseline + output)
+}
+
+// rampToward moves from the current value toward the target, limited to
+// maxDelta per step; a non-positive gradient limit steps immediately
+func rampToward(from, target, maxDelta float32) float32 {
+	if maxDelta <= 0 {
+		return target
+	}
+
+	delta := target - from
+	if delta > maxDelta {
+		return from + maxDelta
+	}
+	if delta < -maxDelta {
+		return from - maxDelta
+	}
+	return target
+}
+
+// dispatch splits the total power across the PCS units weighted by SOC and
+// headroom and applies it through the setpoint arbitration
+func (c *Controller) dispatch(total float32) {
+	services := c.pcsManager.GetAllServices()
+	if len(services) == 0 {
+		return
+	}
+
+	pcsIDs := make([]int, 0, len(services))
+	for pcsID := range services {
+		pcsIDs = append(pcsIDs, pcsID)
+	}
+	shares := c.controlLogic.DispatchShares(total, pcsIDs)
+
+	for pcsID, service := range services {
+		power, err := c.controlLogic.ArbitratePCSPowerCommand(pcsID, control.SourceAFRR, shares[pcsID])
+		if err != nil {
+			c.log.Warn("aFRR dispatch rejected by arbitration",
+				zap.Int("pcs_id", pcsID),
+				zap.Error(err))
+			continue
+		}
+		if err := service.SetActivePowerCommand(power); err != nil {
+			c.log.Error("Failed to apply aFRR power command",
+				zap.Int("pcs_id", pcsID),
+				zap.Error(err))
+		}
+	}
+}
+
+// releaseAll drops the aFRR claim on every PCS
+func (c *Controller) releaseAll() {
+	for pcsID := range c.pcsManager.GetAllServices() {
+		c.controlLogic.ReleasePCSSetpoint(pcsID, control.SourceAFRR)
+	}
+	c.log.Info("aFRR delivery settled, claims released")
+}
+
+// reportLoop periodically records the settlement state to the historian
+func (c *Controller) reportLoop() {
+	ticker := time.NewTicker(settlementReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.report()
+		}
+	}
+}
+
+// report writes one settlement sample
+func (c *Controller) report() {
+	c.mutex.RLock()
+	active := c.active
+	sample := database.AFRRSettlement{
+		Timestamp:              time.Now(),
+		SetpointKW:             c.setpointKW,
+		OutputKW:               c.outputKW,
+		BaselineKW:             c.baselineKW,
+		ActivatedEnergyUpKWh:   c.energyUpKWh,
+		ActivatedEnergyDownKWh: c.energyDownKWh,
+	}
+	c.mutex.RUnlock()
+
+	if !active {
+		return
+	}
+
+	if err := c.influxDB.WriteAFRRSettlement(sample); err != nil {
+		c.log.Error("Failed to write aFRR settlement sample", zap.Error(err))
+	}
+}
+
+// abs32 returns the absolute value of a float32
+func abs32(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
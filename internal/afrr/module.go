@@ -0,0 +1,37 @@
+package afrr
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pcs"
+)
+
+// Module provides aFRR support to the Fx application
+var Module = fx.Module("afrr",
+	fx.Provide(ProvideController),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideController creates the aFRR controller
+func ProvideController(cfg *config.Config, pcsManager *pcs.Manager, controlLogic *control.Logic, influxDB *database.InfluxDB, logger *zap.Logger) *Controller {
+	return NewController(cfg.AFRR, pcsManager, controlLogic, influxDB, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the aFRR controller
+func RegisterLifecycle(lc fx.Lifecycle, controller *Controller) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return controller.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			controller.Stop()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,205 @@
+package alarm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+)
+
+// incidentConnector opens and resolves incidents in an external incident
+// management service. The dedup key keeps repeated raises of the same alarm
+// on one device in a single incident.
+type incidentConnector interface {
+	Trigger(dedupKey string, alarm database.BMSAlarmData) (string, error)
+	Resolve(dedupKey string) error
+}
+
+// newIncidentConnector builds the configured connector, or nil when the
+// integration is disabled
+func newIncidentConnector(cfg config.IncidentConfig) incidentConnector {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout}
+	switch cfg.Provider {
+	case "pagerduty":
+		return &pagerDutyConnector{config: cfg, client: client}
+	case "opsgenie":
+		return &opsgenieConnector{config: cfg, client: client}
+	}
+	return nil
+}
+
+// openIncident opens an incident for a new HIGH alarm and records the
+// incident key and link on the alarm
+func (m *Manager) openIncident(alarmKey string, alarm database.BMSAlarmData) {
+	link, err := m.incidents.Trigger(alarmKey, alarm)
+	if err != nil {
+		m.log.Error("Failed to open incident",
+			zap.Error(err),
+			zap.String("dedup_key", alarmKey))
+		return
+	}
+
+	m.mutex.Lock()
+	if entry, exists := m.activeAlarms.Get(alarmKey); exists {
+		entry.incidentKey = alarmKey
+		entry.incidentURL = link
+	}
+	m.mutex.Unlock()
+
+	if err := m.postgreSQL.SetAlarmIncident(alarm.AlarmType, alarm.AlarmCode, alarmKey, link); err != nil {
+		m.log.Error("Failed to record incident on alarm",
+			zap.Error(err),
+			zap.String("dedup_key", alarmKey))
+	}
+
+	m.log.Info("Incident opened",
+		zap.String("dedup_key", alarmKey),
+		zap.String("incident_url", link))
+}
+
+// resolveIncident auto-resolves the incident of a cleared HIGH alarm
+func (m *Manager) resolveIncident(alarmKey string) {
+	if err := m.incidents.Resolve(alarmKey); err != nil {
+		m.log.Error("Failed to resolve incident",
+			zap.Error(err),
+			zap.String("dedup_key", alarmKey))
+		return
+	}
+
+	m.log.Info("Incident resolved", zap.String("dedup_key", alarmKey))
+}
+
+// pagerDutyConnector talks to the PagerDuty Events API v2; trigger and
+// resolve share the dedup key so the incident lifecycle follows the alarm
+type pagerDutyConnector struct {
+	config config.IncidentConfig
+	client *http.Client
+}
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+func (c *pagerDutyConnector) endpoint() string {
+	if c.config.Endpoint != "" {
+		return c.config.Endpoint
+	}
+	return pagerDutyEventsURL
+}
+
+func (c *pagerDutyConnector) Trigger(dedupKey string, alarm database.BMSAlarmData) (string, error) {
+	payload := map[string]any{
+		"routing_key":  c.config.APIKey,
+		"event_action": "trigger",
+		"dedup_key":    dedupKey,
+		"payload": map[string]any{
+			"summary":   fmt.Sprintf("%s alarm %d: %s", alarm.AlarmType, alarm.AlarmCode, alarm.Message),
+			"source":    alarm.AlarmType,
+			"severity":  "critical",
+			"timestamp": alarm.Timestamp,
+		},
+	}
+
+	// The Events API does not return an incident link; the dedup key is
+	// the stable reference recorded on the alarm
+	return "", c.send(payload)
+}
+
+func (c *pagerDutyConnector) Resolve(dedupKey string) error {
+	return c.send(map[string]any{
+		"routing_key":  c.config.APIKey,
+		"event_action": "resolve",
+		"dedup_key":    dedupKey,
+	})
+}
+
+func (c *pagerDutyConnector) send(payload map[string]any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal PagerDuty event: %w", err)
+	}
+
+	resp, err := c.client.Post(c.endpoint(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send PagerDuty event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("PagerDuty event rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// opsgenieConnector talks to the Opsgenie alert API; the alarm's dedup key
+// becomes the alert alias so raises deduplicate and the clear closes the
+// same alert
+type opsgenieConnector struct {
+	config config.IncidentConfig
+	client *http.Client
+}
+
+const opsgenieAlertsURL = "https://api.opsgenie.com/v2/alerts"
+
+func (c *opsgenieConnector) endpoint() string {
+	if c.config.Endpoint != "" {
+		return c.config.Endpoint
+	}
+	return opsgenieAlertsURL
+}
+
+func (c *opsgenieConnector) Trigger(dedupKey string, alarm database.BMSAlarmData) (string, error) {
+	payload := map[string]any{
+		"message":  fmt.Sprintf("%s alarm %d: %s", alarm.AlarmType, alarm.AlarmCode, alarm.Message),
+		"alias":    dedupKey,
+		"source":   alarm.AlarmType,
+		"priority": "P1",
+	}
+
+	if err := c.send(c.endpoint(), payload); err != nil {
+		return "", err
+	}
+
+	// Opsgenie returns a request id, not the alert link; record the alias
+	// search link as the stable reference
+	link := fmt.Sprintf("https://app.opsgenie.com/alert/list?query=%s", url.QueryEscape("alias: "+dedupKey))
+	return link, nil
+}
+
+func (c *opsgenieConnector) Resolve(dedupKey string) error {
+	closeURL := fmt.Sprintf("%s/%s/close?identifierType=alias", c.endpoint(), url.PathEscape(dedupKey))
+	return c.send(closeURL, map[string]any{})
+}
+
+func (c *opsgenieConnector) send(endpoint string, payload map[string]any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Opsgenie alert: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build Opsgenie request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+c.config.APIKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Opsgenie alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Opsgenie alert rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -10,8 +10,14 @@ import (
 
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/pkg/cache"
 )
 
+// activeAlarmCapacity bounds the active alarm store; a plant raising more
+// simultaneous alarms than this is in deep trouble anyway, and the bound
+// keeps a flapping device from exhausting memory
+const activeAlarmCapacity = 1024
+
 // Manager handles alarm processing and management
 type Manager struct {
 	config     config.AlarmConfig
@@ -23,7 +29,10 @@ type Manager struct {
 	log        *zap.Logger
 
 	mutex        sync.RWMutex
-	activeAlarms map[string]database.BMSAlarmData
+	activeAlarms *cache.Store[string, *activeAlarm]
+	digest       map[string]*digestEntry
+	incidents    incidentConnector
+	notifiers    []notifierChannel
 }
 
 // NewManager creates a new alarm manager
@@ -41,7 +50,10 @@ func NewManager(cfg config.AlarmConfig, postgreSQL *database.PostgreSQL, logger
 	return &Manager{
 		config:       cfg,
 		postgreSQL:   postgreSQL,
-		activeAlarms: make(map[string]database.BMSAlarmData),
+		activeAlarms: cache.NewStore[string, *activeAlarm]("alarm_active", activeAlarmCapacity),
+		digest:       make(map[string]*digestEntry),
+		incidents:    newIncidentConnector(cfg.Incidents),
+		notifiers:    newNotifierChannels(cfg.Notifiers),
 		alarmQueue:   make(chan database.BMSAlarmData, cfg.QueueBufferSize),
 		ctx:          ctx,
 		cancel:       cancel,
@@ -53,6 +65,12 @@ func NewManager(cfg config.AlarmConfig, postgreSQL *database.PostgreSQL, logger
 func (m *Manager) Start() error {
 	m.deactivateAllAlarms()
 	m.wg.Go(m.alarmProcessingWorker)
+	if m.config.DigestEnabled {
+		m.wg.Go(m.digestWorker)
+	}
+	if m.config.EscalationEnabled && len(m.config.EscalationContacts) > 0 {
+		m.wg.Go(m.escalationWorker)
+	}
 	m.log.Info("Alarm manager started")
 	return nil
 }
@@ -144,9 +162,12 @@ func (m *Manager) processAlarm(alarm database.BMSAlarmData) {
 	}
 
 	if alarm.Active {
-		if _, exists := m.activeAlarms[alarmKey]; !exists {
+		if _, exists := m.activeAlarms.Get(alarmKey); !exists {
 			// New alarm
-			m.activeAlarms[alarmKey] = alarm
+			m.activeAlarms.Put(alarmKey, &activeAlarm{
+				data:     alarm,
+				raisedAt: time.Now(),
+			})
 
 			// Save to PostgreSQL
 			if err := m.postgreSQL.SaveAlarm(alarm); err != nil {
@@ -156,14 +177,24 @@ func (m *Manager) processAlarm(alarm database.BMSAlarmData) {
 
 			if alarm.Severity == "HIGH" {
 				m.log.Error("NEW CRITICAL ALARM", logFields...)
+				if m.incidents != nil {
+					go m.openIncident(alarmKey, alarm)
+				}
+			} else if m.digestEligible(alarm) {
+				m.bufferDigest(alarmKey, alarm, true)
 			} else {
 				m.log.Warn("NEW ALARM", logFields...)
 			}
+
+			if len(m.notifiers) > 0 {
+				go m.dispatchNotifications(alarm, false)
+			}
 		}
 	} else {
-		if existingAlarm, exists := m.activeAlarms[alarmKey]; exists {
+		if entry, exists := m.activeAlarms.Get(alarmKey); exists {
 			// Alarm cleared
-			delete(m.activeAlarms, alarmKey)
+			existingAlarm := entry.data
+			m.activeAlarms.Delete(alarmKey)
 
 			// Update the existing alarm in PostgreSQL to set active = false
 			// First, get the active alarm record from PostgreSQL
@@ -186,9 +217,18 @@ func (m *Manager) processAlarm(alarm database.BMSAlarmData) {
 
 			if existingAlarm.Severity == "HIGH" {
 				m.log.Info("CRITICAL ALARM CLEARED", logFields...)
+				if m.incidents != nil && entry.incidentKey != "" {
+					go m.resolveIncident(entry.incidentKey)
+				}
+			} else if m.digestEligible(existingAlarm) {
+				m.bufferDigest(alarmKey, existingAlarm, false)
 			} else {
 				m.log.Info("ALARM CLEARED", logFields...)
 			}
+
+			if len(m.notifiers) > 0 {
+				go m.dispatchNotifications(existingAlarm, true)
+			}
 		}
 	}
 }
@@ -202,17 +242,101 @@ func (m *Manager) GetActiveAlarms() []database.BMSAlarmData {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	alarms := make([]database.BMSAlarmData, 0, len(m.activeAlarms))
-	for _, alarm := range m.activeAlarms {
-		alarms = append(alarms, alarm)
-	}
+	alarms := make([]database.BMSAlarmData, 0, m.activeAlarms.Len())
+	m.activeAlarms.ForEach(func(_ string, entry *activeAlarm) bool {
+		alarms = append(alarms, entry.data)
+		return true
+	})
 
 	return alarms
 }
 
+// AcknowledgeAlarm marks an active alarm as acknowledged so its escalation
+// chain stops
+func (m *Manager) AcknowledgeAlarm(alarmType string, alarmCode uint16) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	alarmKey := fmt.Sprintf("%s_%d", alarmType, alarmCode)
+	entry, exists := m.activeAlarms.Get(alarmKey)
+	if !exists {
+		return fmt.Errorf("no active alarm of type %s with code %d", alarmType, alarmCode)
+	}
+
+	entry.acknowledged = true
+	m.log.Info("Alarm acknowledged",
+		zap.String("alarm_type", alarmType),
+		zap.Uint16("alarm_code", alarmCode))
+	return nil
+}
+
+// AcknowledgeAlarmByID records an operator acknowledgement on an alarm
+// record; a still-active alarm additionally stops its escalation chain
+func (m *Manager) AcknowledgeAlarmByID(id uint, ackedBy, comment string) (database.AlarmRecord, error) {
+	record, err := m.postgreSQL.GetAlarmByID(id)
+	if err != nil {
+		return database.AlarmRecord{}, fmt.Errorf("alarm %d not found", id)
+	}
+
+	now := time.Now()
+	if err := m.postgreSQL.SetAlarmAcknowledgement(id, ackedBy, comment, &now); err != nil {
+		return database.AlarmRecord{}, err
+	}
+	record.AckedBy = ackedBy
+	record.AckedAt = &now
+	record.AckComment = comment
+
+	m.mutex.Lock()
+	if entry, exists := m.activeAlarms.Get(fmt.Sprintf("%s_%d", record.AlarmType, record.AlarmCode)); exists {
+		entry.acknowledged = true
+		entry.ackedBy = ackedBy
+		entry.ackedAt = now
+		entry.ackComment = comment
+	}
+	m.mutex.Unlock()
+
+	m.log.Info("Alarm acknowledged",
+		zap.Uint("id", id),
+		zap.String("alarm_type", record.AlarmType),
+		zap.Uint16("alarm_code", record.AlarmCode),
+		zap.String("acked_by", ackedBy))
+	return record, nil
+}
+
+// UnacknowledgeAlarmByID reverts an operator acknowledgement, re-arming the
+// escalation chain of a still-active alarm
+func (m *Manager) UnacknowledgeAlarmByID(id uint) (database.AlarmRecord, error) {
+	record, err := m.postgreSQL.GetAlarmByID(id)
+	if err != nil {
+		return database.AlarmRecord{}, fmt.Errorf("alarm %d not found", id)
+	}
+
+	if err := m.postgreSQL.SetAlarmAcknowledgement(id, "", "", nil); err != nil {
+		return database.AlarmRecord{}, err
+	}
+	record.AckedBy = ""
+	record.AckedAt = nil
+	record.AckComment = ""
+
+	m.mutex.Lock()
+	if entry, exists := m.activeAlarms.Get(fmt.Sprintf("%s_%d", record.AlarmType, record.AlarmCode)); exists {
+		entry.acknowledged = false
+		entry.ackedBy = ""
+		entry.ackedAt = time.Time{}
+		entry.ackComment = ""
+	}
+	m.mutex.Unlock()
+
+	m.log.Info("Alarm acknowledgement reverted",
+		zap.Uint("id", id),
+		zap.String("alarm_type", record.AlarmType),
+		zap.Uint16("alarm_code", record.AlarmCode))
+	return record, nil
+}
+
 // GetAlarmHistory returns alarm history from PostgreSQL
-func (m *Manager) GetAlarmHistory(limit int, offset int) ([]database.AlarmRecord, error) {
-	records, err := m.postgreSQL.GetAlarmHistory(limit, offset)
+func (m *Manager) GetAlarmHistory(ctx context.Context, limit int, offset int) ([]database.AlarmRecord, error) {
+	records, err := m.postgreSQL.GetAlarmHistory(ctx, limit, offset)
 	if err != nil {
 		m.log.Error("Failed to get alarm history",
 			zap.Error(err),
@@ -224,17 +348,53 @@ func (m *Manager) GetAlarmHistory(limit int, offset int) ([]database.AlarmRecord
 	return records, nil
 }
 
+// ActiveAlarmState is one active alarm together with its acknowledgement
+// state
+type ActiveAlarmState struct {
+	database.BMSAlarmData
+	Acknowledged bool       `json:"acknowledged"`
+	AckedBy      string     `json:"acked_by,omitempty"`
+	AckedAt      *time.Time `json:"acked_at,omitempty"`
+	Comment      string     `json:"comment,omitempty"`
+}
+
+// GetActiveAlarmStates returns all active alarms with their acknowledgement
+// state
+func (m *Manager) GetActiveAlarmStates() []ActiveAlarmState {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	states := make([]ActiveAlarmState, 0, m.activeAlarms.Len())
+	m.activeAlarms.ForEach(func(_ string, entry *activeAlarm) bool {
+		state := ActiveAlarmState{
+			BMSAlarmData: entry.data,
+			Acknowledged: entry.acknowledged,
+			AckedBy:      entry.ackedBy,
+			Comment:      entry.ackComment,
+		}
+		if !entry.ackedAt.IsZero() {
+			ackedAt := entry.ackedAt
+			state.AckedAt = &ackedAt
+		}
+		states = append(states, state)
+		return true
+	})
+
+	return states
+}
+
 // GetActiveAlarmsByType returns active alarms by type
 func (m *Manager) GetActiveAlarmsByType(alarmType string) []database.BMSAlarmData {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
 	var alarms []database.BMSAlarmData
-	for _, alarm := range m.activeAlarms {
-		if alarm.AlarmType == alarmType {
-			alarms = append(alarms, alarm)
+	m.activeAlarms.ForEach(func(_ string, entry *activeAlarm) bool {
+		if entry.data.AlarmType == alarmType {
+			alarms = append(alarms, entry.data)
 		}
-	}
+		return true
+	})
 
 	return alarms
 }
@@ -245,11 +405,12 @@ func (m *Manager) GetActiveAlarmsBySeverity(severity string) []database.BMSAlarm
 	defer m.mutex.RUnlock()
 
 	var alarms []database.BMSAlarmData
-	for _, alarm := range m.activeAlarms {
-		if alarm.Severity == severity {
-			alarms = append(alarms, alarm)
+	m.activeAlarms.ForEach(func(_ string, entry *activeAlarm) bool {
+		if entry.data.Severity == severity {
+			alarms = append(alarms, entry.data)
 		}
-	}
+		return true
+	})
 
 	return alarms
 }
@@ -267,8 +428,8 @@ func (m *Manager) HasCriticalAlarms() bool {
 }
 
 // GetAlarmsByTimeRange returns alarms in a specific time range
-func (m *Manager) GetAlarmsByTimeRange(start, end time.Time) ([]database.AlarmRecord, error) {
-	records, err := m.postgreSQL.GetAlarmsInTimeRange(start, end)
+func (m *Manager) GetAlarmsByTimeRange(ctx context.Context, start, end time.Time) ([]database.AlarmRecord, error) {
+	records, err := m.postgreSQL.GetAlarmsInTimeRange(ctx, start, end)
 	if err != nil {
 		m.log.Error("Failed to get alarms by time range",
 			zap.Error(err),
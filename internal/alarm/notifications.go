@@ -0,0 +1,157 @@
+package alarm
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// escalationCheckInterval is how often unacknowledged HIGH alarms are
+// re-evaluated against the escalation timeout
+const escalationCheckInterval = time.Minute
+
+// activeAlarm tracks an active alarm together with its notification state
+type activeAlarm struct {
+	data            database.BMSAlarmData
+	raisedAt        time.Time
+	acknowledged    bool
+	ackedBy         string
+	ackedAt         time.Time
+	ackComment      string
+	escalationLevel int
+	incidentKey     string
+	incidentURL     string
+}
+
+// digestEntry accumulates raise and clear counts of one LOW/MEDIUM alarm
+// between digest flushes
+type digestEntry struct {
+	alarm   database.BMSAlarmData
+	raised  int
+	cleared int
+}
+
+// digestEligible reports whether an alarm is summarized in the periodic
+// digest instead of being notified immediately; HIGH alarms are always
+// notified immediately
+func (m *Manager) digestEligible(alarm database.BMSAlarmData) bool {
+	return m.config.DigestEnabled && alarm.Severity != "HIGH"
+}
+
+// bufferDigest records a raise or clear for the next digest flush. The
+// caller must hold the mutex.
+func (m *Manager) bufferDigest(alarmKey string, alarm database.BMSAlarmData, raised bool) {
+	entry, exists := m.digest[alarmKey]
+	if !exists {
+		entry = &digestEntry{alarm: alarm}
+		m.digest[alarmKey] = entry
+	}
+
+	if raised {
+		entry.raised++
+	} else {
+		entry.cleared++
+	}
+}
+
+// digestWorker flushes the accumulated LOW/MEDIUM alarm activity as one
+// summary per digest interval
+func (m *Manager) digestWorker() {
+	ticker := time.NewTicker(m.config.DigestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			m.flushDigest()
+			return
+		case <-ticker.C:
+			m.flushDigest()
+		}
+	}
+}
+
+// flushDigest logs the buffered digest entries and resets the buffer;
+// intervals without LOW/MEDIUM activity produce no digest
+func (m *Manager) flushDigest() {
+	m.mutex.Lock()
+	entries := m.digest
+	m.digest = make(map[string]*digestEntry)
+	m.mutex.Unlock()
+
+	if len(entries) == 0 {
+		return
+	}
+
+	totalRaised := 0
+	totalCleared := 0
+	for _, entry := range entries {
+		totalRaised += entry.raised
+		totalCleared += entry.cleared
+	}
+
+	m.log.Warn("ALARM DIGEST",
+		zap.Duration("interval", m.config.DigestInterval),
+		zap.Int("distinct_alarms", len(entries)),
+		zap.Int("raised", totalRaised),
+		zap.Int("cleared", totalCleared))
+
+	for _, entry := range entries {
+		m.log.Info("Alarm digest entry",
+			zap.String("alarm_type", entry.alarm.AlarmType),
+			zap.Uint16("alarm_code", entry.alarm.AlarmCode),
+			zap.String("severity", entry.alarm.Severity),
+			zap.String("message", entry.alarm.Message),
+			zap.Int("raised", entry.raised),
+			zap.Int("cleared", entry.cleared))
+	}
+}
+
+// escalationWorker walks unacknowledged HIGH alarms along the contact chain:
+// each time the escalation timeout passes without an acknowledgment the next
+// configured contact is notified, until the chain is exhausted
+func (m *Manager) escalationWorker() {
+	ticker := time.NewTicker(escalationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.escalateUnacknowledged()
+		}
+	}
+}
+
+// escalateUnacknowledged notifies the next escalation contacts for every
+// unacknowledged HIGH alarm whose escalation timeout has elapsed
+func (m *Manager) escalateUnacknowledged() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.activeAlarms.ForEach(func(_ string, entry *activeAlarm) bool {
+		if entry.data.Severity != "HIGH" || entry.acknowledged {
+			return true
+		}
+
+		dueLevel := int(time.Since(entry.raisedAt) / m.config.EscalationTimeout)
+		if dueLevel > len(m.config.EscalationContacts) {
+			dueLevel = len(m.config.EscalationContacts)
+		}
+
+		for level := entry.escalationLevel; level < dueLevel; level++ {
+			m.log.Error("HIGH ALARM ESCALATED",
+				zap.String("contact", m.config.EscalationContacts[level]),
+				zap.Int("escalation_level", level+1),
+				zap.String("alarm_type", entry.data.AlarmType),
+				zap.Uint16("alarm_code", entry.data.AlarmCode),
+				zap.String("message", entry.data.Message),
+				zap.Duration("unacknowledged_for", time.Since(entry.raisedAt)))
+		}
+		entry.escalationLevel = dueLevel
+		return true
+	})
+}
@@ -0,0 +1,234 @@
+package alarm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+)
+
+// alarmNotifier delivers one alarm raise or clear over an external channel
+type alarmNotifier interface {
+	Notify(alarm database.BMSAlarmData, cleared bool) error
+}
+
+// notifierChannel pairs a built notifier with its routing configuration
+type notifierChannel struct {
+	config   config.NotifierConfig
+	notifier alarmNotifier
+}
+
+// routed reports whether the channel receives alarms of this severity; a
+// channel without severity rules receives everything
+func (c notifierChannel) routed(severity string) bool {
+	if len(c.config.Severities) == 0 {
+		return true
+	}
+	for _, s := range c.config.Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// newNotifierChannels builds the configured notification channels; channels
+// of an unknown type are skipped
+func newNotifierChannels(cfgs []config.NotifierConfig) []notifierChannel {
+	var channels []notifierChannel
+	for _, cfg := range cfgs {
+		if cfg.Timeout <= 0 {
+			cfg.Timeout = 10 * time.Second
+		}
+
+		var notifier alarmNotifier
+		switch cfg.Type {
+		case "email":
+			notifier = &emailNotifier{config: cfg}
+		case "webhook":
+			notifier = &webhookNotifier{config: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+		case "sms":
+			notifier = &smsNotifier{config: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+		}
+		if notifier != nil {
+			channels = append(channels, notifierChannel{config: cfg, notifier: notifier})
+		}
+	}
+	return channels
+}
+
+// dispatchNotifications sends an alarm raise or clear to every channel
+// routed for its severity; channel failures are logged and do not affect
+// the other channels
+func (m *Manager) dispatchNotifications(alarm database.BMSAlarmData, cleared bool) {
+	for _, channel := range m.notifiers {
+		if !channel.routed(alarm.Severity) {
+			continue
+		}
+
+		if err := channel.notifier.Notify(alarm, cleared); err != nil {
+			m.log.Error("Alarm notification failed",
+				zap.Error(err),
+				zap.String("channel", channel.config.Name),
+				zap.String("channel_type", channel.config.Type),
+				zap.String("alarm_type", alarm.AlarmType),
+				zap.Uint16("alarm_code", alarm.AlarmCode))
+			continue
+		}
+
+		m.log.Info("Alarm notification sent",
+			zap.String("channel", channel.config.Name),
+			zap.String("channel_type", channel.config.Type),
+			zap.String("alarm_type", alarm.AlarmType),
+			zap.Uint16("alarm_code", alarm.AlarmCode),
+			zap.Bool("cleared", cleared))
+	}
+}
+
+// notificationSummary is the one-line alarm description shared by all
+// channels
+func notificationSummary(alarm database.BMSAlarmData, cleared bool) string {
+	event := "ALARM"
+	if cleared {
+		event = "CLEARED"
+	}
+	return fmt.Sprintf("[%s] %s %s alarm %d: %s", alarm.Severity, event, alarm.AlarmType, alarm.AlarmCode, alarm.Message)
+}
+
+// emailNotifier mails alarm notifications through an SMTP relay
+type emailNotifier struct {
+	config config.NotifierConfig
+}
+
+func (n *emailNotifier) Notify(alarm database.BMSAlarmData, cleared bool) error {
+	cfg := n.config
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", notificationSummary(alarm, cleared))
+	message.WriteString("\r\n")
+	fmt.Fprintf(&message, "%s\r\n\r\n", notificationSummary(alarm, cleared))
+	fmt.Fprintf(&message, "Raised at: %s\r\n", alarm.Timestamp.Format(time.RFC3339))
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.SMTPHost)
+	}
+
+	address := fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort)
+	if err := smtp.SendMail(address, auth, cfg.From, cfg.To, message.Bytes()); err != nil {
+		return fmt.Errorf("failed to send mail via %s: %w", address, err)
+	}
+	return nil
+}
+
+// webhookNotifier POSTs alarm notifications as JSON to a receiver endpoint
+type webhookNotifier struct {
+	config config.NotifierConfig
+	client *http.Client
+}
+
+func (n *webhookNotifier) Notify(alarm database.BMSAlarmData, cleared bool) error {
+	event := "raised"
+	if cleared {
+		event = "cleared"
+	}
+	payload := map[string]any{
+		"event":      event,
+		"timestamp":  alarm.Timestamp,
+		"alarm_type": alarm.AlarmType,
+		"alarm_code": alarm.AlarmCode,
+		"severity":   alarm.Severity,
+		"message":    alarm.Message,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if n.config.AuthToken != "" {
+		request.Header.Set("Authorization", "Bearer "+n.config.AuthToken)
+	}
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook rejected the notification: %s", response.Status)
+	}
+	return nil
+}
+
+// smsNotifier texts alarm notifications through the Twilio messages API
+type smsNotifier struct {
+	config config.NotifierConfig
+	client *http.Client
+}
+
+const twilioMessagesURL = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+
+func (n *smsNotifier) endpoint() string {
+	if n.config.URL != "" {
+		return n.config.URL
+	}
+	return fmt.Sprintf(twilioMessagesURL, url.PathEscape(n.config.AccountSID))
+}
+
+func (n *smsNotifier) Notify(alarm database.BMSAlarmData, cleared bool) error {
+	summary := notificationSummary(alarm, cleared)
+
+	var errs []string
+	for _, to := range n.config.ToNumbers {
+		if err := n.sendMessage(to, summary); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", to, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to text %d of %d recipients: %s", len(errs), len(n.config.ToNumbers), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (n *smsNotifier) sendMessage(to, body string) error {
+	form := url.Values{}
+	form.Set("From", n.config.FromNumber)
+	form.Set("To", to)
+	form.Set("Body", body)
+
+	request, err := http.NewRequest(http.MethodPost, n.endpoint(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(n.config.AccountSID, n.config.AuthToken)
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("Twilio rejected the message: %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package ion7400
+
+import (
+	"powerkonnekt/ems/internal/registerdoc"
+)
+
+// RegisterDocumentation describes the ION7400 register map polled by the
+// grid analyzer service, derived from the protocol constants
+func RegisterDocumentation() registerdoc.Device {
+	return registerdoc.Device{
+		Device:      "ion7400",
+		Description: "Schneider PowerLogic ION7400 grid analyzer",
+		Blocks: []registerdoc.Block{
+			{
+				Name:      "base_data",
+				Table:     "holding",
+				StartAddr: BaseDataStartAddr,
+				Length:    BaseDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "power_factor",
+				Table:     "holding",
+				StartAddr: PowerFactorDataStartAddr,
+				Length:    PowerFactorDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "energy",
+				Table:     "holding",
+				StartAddr: EnergyDataStartAddr,
+				Length:    EnergyDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:        "thd",
+				Table:       "holding",
+				StartAddr:   THDDataStartAddr,
+				Length:      THDDataLength,
+				Direction:   registerdoc.DirectionRead,
+				Description: "Voltage and current THD per phase (%)",
+			},
+		},
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
 )
 
 // Module provides ION7400 management functionality to the Fx application
@@ -20,9 +21,10 @@ var Module = fx.Module("ion7400",
 func ProvideService(
 	cfg *config.Config,
 	influxDB *database.InfluxDB,
+	telemetryManager *telemetry.Manager,
 	logger *zap.Logger,
 ) *Service {
-	return NewService(cfg.ION7400, influxDB, logger)
+	return NewService(cfg.ION7400, influxDB, telemetryManager, logger)
 }
 
 // RegisterLifecycle registers lifecycle hooks for the ION7400 service
@@ -76,6 +76,23 @@ func parseEnergyData(data []byte) database.AnalyzerData {
 	}
 }
 
+// parseTHDData converts raw MODBUS data to AnalyzerData structure for THD registers
+func parseTHDData(data []byte) database.AnalyzerData {
+	if len(data) < THDDataLength*2 {
+		return database.AnalyzerData{Timestamp: time.Now()}
+	}
+
+	return database.AnalyzerData{
+		Timestamp:    time.Now(),
+		THDVoltageL1: float32FromBytes(data, 0),  // 21329 - THD Voltage A-N (%)
+		THDVoltageL2: float32FromBytes(data, 4),  // 21331 - THD Voltage B-N (%)
+		THDVoltageL3: float32FromBytes(data, 8),  // 21333 - THD Voltage C-N (%)
+		THDCurrentL1: float32FromBytes(data, 12), // 21335 - THD Current A (%)
+		THDCurrentL2: float32FromBytes(data, 16), // 21337 - THD Current B (%)
+		THDCurrentL3: float32FromBytes(data, 20), // 21339 - THD Current C (%)
+	}
+}
+
 // float32FromBytes converts bytes to float32
 func float32FromBytes(data []byte, offset int) float32 {
 	if len(data) < offset+4 {
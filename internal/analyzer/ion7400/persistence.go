@@ -6,9 +6,15 @@ import (
 	"go.uber.org/zap"
 )
 
+// persistInterval returns the configured persist interval scaled by the
+// active telemetry profile
+func (s *Service) persistInterval() time.Duration {
+	return s.config.PersistInterval * time.Duration(s.telemetry.Current().PersistScale)
+}
+
 // persistenceLoop handles data persistence
 func (s *Service) persistenceLoop() {
-	interval := s.config.PersistInterval
+	interval := s.persistInterval()
 
 	// Calculate first aligned time and create timer
 	nextTick := time.Now().Truncate(interval).Add(interval)
@@ -29,7 +35,9 @@ func (s *Service) persistenceLoop() {
 					zap.Duration("interval", interval))
 			}
 
-			// Calculate next aligned time and reset timer
+			// Calculate next aligned time and reset timer, picking up
+			// telemetry profile changes
+			interval = s.persistInterval()
 			nextTick = time.Now().Truncate(interval).Add(interval)
 			timer.Reset(time.Until(nextTick))
 		}
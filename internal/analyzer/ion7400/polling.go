@@ -5,10 +5,25 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"powerkonnekt/ems/pkg/modbus"
 )
 
 // pollLoop handles continuous data polling
 func (s *Service) pollLoop() {
+	s.client.OnConnectionChange(func(connected bool) {
+		if connected {
+			s.log.Info("ION7400 connection established")
+		} else {
+			s.log.Warn("ION7400 connection lost, reconnecting in the background")
+		}
+	})
+	s.client.StartAutoReconnect(s.ctx, modbus.ReconnectSettings{
+		InitialDelay: s.config.ReconnectDelay,
+		MaxDelay:     s.config.ReconnectMaxDelay,
+		MaxRetries:   s.config.ReconnectMaxRetries,
+	})
+
 	if err := s.client.Connect(s.ctx); err != nil {
 		s.log.Error("Initial ION7400 connection failed", zap.Error(err))
 	}
@@ -25,9 +40,7 @@ func (s *Service) pollLoop() {
 		case <-s.ctx.Done():
 			return
 		case <-timer.C:
-			if !s.client.IsConnected() {
-				s.handleConnectionError()
-			} else {
+			if s.client.IsConnected() {
 				startTime := time.Now()
 				if err := s.readAllData(); err != nil {
 					s.log.Error("Error reading data", zap.Error(err))
@@ -54,35 +67,6 @@ func (s *Service) pollLoop() {
 	}
 }
 
-// handleConnectionError attempts to reconnect to the ION7400
-func (s *Service) handleConnectionError() {
-	s.log.Warn("ION7400 connection lost, attempting reconnection")
-	s.client.Disconnect()
-
-	reconnectAttempts := 0
-	timer := time.NewTimer(s.config.ReconnectDelay)
-	defer timer.Stop()
-
-	for !s.client.IsConnected() {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-timer.C:
-			reconnectAttempts++
-			if err := s.client.Connect(s.ctx); err != nil {
-				s.log.Error("Failed to reconnect to ION7400",
-					zap.Error(err),
-					zap.Int("attempt", reconnectAttempts))
-				timer.Reset(s.config.ReconnectDelay)
-			} else {
-				s.log.Info("Successfully reconnected to ION7400",
-					zap.Int("total_attempts", reconnectAttempts),
-					zap.Duration("total_downtime", time.Duration(reconnectAttempts)*s.config.ReconnectDelay))
-			}
-		}
-	}
-}
-
 // readAllData reads all necessary data from the ION7400
 func (s *Service) readAllData() error {
 	if err := s.readBaseData(); err != nil {
@@ -11,4 +11,7 @@ const (
 
 	EnergyDataStartAddr = 3203
 	EnergyDataLength    = 40
+
+	THDDataStartAddr = 21329
+	THDDataLength    = 12
 )
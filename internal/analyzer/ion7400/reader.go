@@ -19,9 +19,15 @@ func (s *Service) readBaseData() error {
 		return fmt.Errorf("failed to read energy registers: %w", err)
 	}
 
+	data4, err := s.client.ReadHoldingRegisters(s.ctx, THDDataStartAddr, THDDataLength)
+	if err != nil {
+		return fmt.Errorf("failed to read THD registers: %w", err)
+	}
+
 	baseData := parseBaseData(data1)
 	powerFactorData := parsePowerFactorData(data2)
 	energyData := parseEnergyData(data3)
+	thdData := parseTHDData(data4)
 
 	s.mutex.Lock()
 	s.lastData = baseData
@@ -35,6 +41,12 @@ func (s *Service) readBaseData() error {
 	s.lastData.ReactiveEnergyImport = energyData.ReactiveEnergyImport
 	s.lastData.ApparentEnergyExport = energyData.ApparentEnergyExport
 	s.lastData.ApparentEnergyImport = energyData.ApparentEnergyImport
+	s.lastData.THDVoltageL1 = thdData.THDVoltageL1
+	s.lastData.THDVoltageL2 = thdData.THDVoltageL2
+	s.lastData.THDVoltageL3 = thdData.THDVoltageL3
+	s.lastData.THDCurrentL1 = thdData.THDCurrentL1
+	s.lastData.THDCurrentL2 = thdData.THDCurrentL2
+	s.lastData.THDCurrentL3 = thdData.THDCurrentL3
 	s.mutex.Unlock()
 
 	return nil
@@ -8,18 +8,20 @@ import (
 
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
 	"powerkonnekt/ems/pkg/modbus"
 )
 
 // Service represents the ION7400 service
 type Service struct {
-	config   config.AnalyzerConfig
-	influxDB *database.InfluxDB
-	client   *modbus.Client
-	ctx      context.Context
-	cancel   context.CancelFunc
-	wg       sync.WaitGroup
-	log      *zap.Logger
+	config    config.AnalyzerConfig
+	influxDB  *database.InfluxDB
+	telemetry *telemetry.Manager
+	client    *modbus.Client
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	log       *zap.Logger
 
 	dataUpdateChan chan struct{}
 
@@ -28,8 +30,13 @@ type Service struct {
 }
 
 // NewService creates a new ION7400 service
-func NewService(cfg config.AnalyzerConfig, influxDB *database.InfluxDB, logger *zap.Logger) *Service {
-	client := modbus.NewClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout)
+func NewService(cfg config.AnalyzerConfig, influxDB *database.InfluxDB, telemetryManager *telemetry.Manager, logger *zap.Logger) *Service {
+	client := modbus.NewSecureClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout, cfg.BindAddress, modbus.TLSSettings{
+		Enabled:  cfg.TLS.Enabled,
+		CertFile: cfg.TLS.CertFile,
+		KeyFile:  cfg.TLS.KeyFile,
+		CAFile:   cfg.TLS.CAFile,
+	})
 	ctx, cancel := context.WithCancel(context.Background())
 
 	serviceLogger := logger.With(
@@ -40,6 +47,7 @@ func NewService(cfg config.AnalyzerConfig, influxDB *database.InfluxDB, logger *
 	return &Service{
 		config:         cfg,
 		influxDB:       influxDB,
+		telemetry:      telemetryManager,
 		client:         client,
 		ctx:            ctx,
 		cancel:         cancel,
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -9,15 +11,40 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/afrr"
 	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/analyzer/ion7400"
+	"powerkonnekt/ems/internal/auth"
 	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/chaos"
 	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/configversion"
 	"powerkonnekt/ems/internal/control"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/declaration"
+	"powerkonnekt/ems/internal/estimator"
+	"powerkonnekt/ems/internal/fcr"
+	"powerkonnekt/ems/internal/features"
 	"powerkonnekt/ems/internal/health"
+	"powerkonnekt/ems/internal/metrics"
+	"powerkonnekt/ems/internal/modbus"
 	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/peer"
 	"powerkonnekt/ems/internal/plc"
+	"powerkonnekt/ems/internal/powerquality"
+	"powerkonnekt/ems/internal/recorder"
+	"powerkonnekt/ems/internal/registerdoc"
+	"powerkonnekt/ems/internal/rules"
+	"powerkonnekt/ems/internal/schedule"
+	"powerkonnekt/ems/internal/season"
+	"powerkonnekt/ems/internal/selftest"
+	"powerkonnekt/ems/internal/shadow"
+	"powerkonnekt/ems/internal/site"
+	"powerkonnekt/ems/internal/telemetry"
+	"powerkonnekt/ems/internal/warranty"
 	"powerkonnekt/ems/internal/windfarm"
+	"powerkonnekt/ems/pkg/cache"
+	"powerkonnekt/ems/pkg/loopwatch"
 )
 
 // Handlers contains all API handlers
@@ -30,6 +57,30 @@ type Handlers struct {
 	alarmManager    *alarm.Manager
 	controlLogic    *control.Logic
 	healthService   *health.HealthService
+	chaosManager    *chaos.Manager
+	configVersions  *configversion.Service
+	featureManager  *features.Manager
+	telemetry       *telemetry.Manager
+	season          *season.Manager
+	declaration     *declaration.Service
+	fcrController   *fcr.Controller
+	afrrController  *afrr.Controller
+	fcrDelivery     *fcr.DeliveryMonitor
+	fcrPlant        *fcr.PlantConditionTracker
+	fcrRecovery     *fcr.RecoveryPlanner
+	fcrPortfolio    *fcr.Portfolio
+	siteService     *site.Service
+	ruleEngine      *rules.Engine
+	shadowService   *shadow.Service
+	estimator       *estimator.Service
+	powerQuality    *powerquality.Service
+	authService     *auth.Service
+	warranty        *warranty.Exporter
+	metricsManager  *metrics.Manager
+	scheduler       *schedule.Service
+	selfTest        *selftest.Service
+	flightRecorder  *recorder.Service
+	peerService     *peer.Service
 	log             *zap.Logger
 }
 
@@ -43,6 +94,30 @@ func NewHandlers(
 	alarmManager *alarm.Manager,
 	controlLogic *control.Logic,
 	healthService *health.HealthService,
+	chaosManager *chaos.Manager,
+	configVersions *configversion.Service,
+	featureManager *features.Manager,
+	telemetryManager *telemetry.Manager,
+	seasonManager *season.Manager,
+	declarationService *declaration.Service,
+	fcrController *fcr.Controller,
+	afrrController *afrr.Controller,
+	fcrDelivery *fcr.DeliveryMonitor,
+	fcrPlant *fcr.PlantConditionTracker,
+	fcrRecovery *fcr.RecoveryPlanner,
+	fcrPortfolio *fcr.Portfolio,
+	siteService *site.Service,
+	ruleEngine *rules.Engine,
+	shadowService *shadow.Service,
+	estimatorService *estimator.Service,
+	powerQuality *powerquality.Service,
+	authService *auth.Service,
+	warrantyExporter *warranty.Exporter,
+	metricsManager *metrics.Manager,
+	scheduler *schedule.Service,
+	selfTest *selftest.Service,
+	flightRecorder *recorder.Service,
+	peerService *peer.Service,
 	logger *zap.Logger,
 ) *Handlers {
 	// Create handlers-specific logger
@@ -59,6 +134,30 @@ func NewHandlers(
 		alarmManager:    alarmManager,
 		controlLogic:    controlLogic,
 		healthService:   healthService,
+		chaosManager:    chaosManager,
+		configVersions:  configVersions,
+		featureManager:  featureManager,
+		telemetry:       telemetryManager,
+		season:          seasonManager,
+		declaration:     declarationService,
+		fcrController:   fcrController,
+		afrrController:  afrrController,
+		fcrDelivery:     fcrDelivery,
+		fcrPlant:        fcrPlant,
+		fcrRecovery:     fcrRecovery,
+		fcrPortfolio:    fcrPortfolio,
+		siteService:     siteService,
+		ruleEngine:      ruleEngine,
+		shadowService:   shadowService,
+		estimator:       estimatorService,
+		powerQuality:    powerQuality,
+		authService:     authService,
+		warranty:        warrantyExporter,
+		metricsManager:  metricsManager,
+		scheduler:       scheduler,
+		selfTest:        selfTest,
+		flightRecorder:  flightRecorder,
+		peerService:     peerService,
 		log:             handlersLogger,
 	}
 }
@@ -105,6 +204,7 @@ func (h *Handlers) GetStatus(c *gin.Context) {
 	status := gin.H{
 		"control_mode":         h.controlLogic.GetMode(),
 		"active_power_control": h.controlLogic.GetActivePowerControl(),
+		"soc_calibration":      h.controlLogic.GetSOCCalibrationStatus(),
 		"bess_connected":       service.IsConnected(),
 		"bms_soc":              bmsData.SOC,
 		"bms_soh":              bmsData.SOH,
@@ -119,6 +219,512 @@ func (h *Handlers) GetStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// GetSiteLimits returns the site connection-point limits and current headroom
+func (h *Handlers) GetSiteLimits(c *gin.Context) {
+	c.JSON(http.StatusOK, h.controlLogic.GetSiteLimitStatus())
+}
+
+// GetImportLimitSchedule returns the time-of-day import limit windows and
+// the limit currently in force
+func (h *Handlers) GetImportLimitSchedule(c *gin.Context) {
+	schedule, effective := h.controlLogic.GetImportLimitSchedule()
+	c.JSON(http.StatusOK, gin.H{
+		"schedule":           schedule,
+		"effective_limit_kw": effective,
+		"static_limit_kw":    h.config.EMS.MaxSiteImportPower,
+	})
+}
+
+// SetImportLimitSchedule replaces the time-of-day import limit windows
+func (h *Handlers) SetImportLimitSchedule(c *gin.Context) {
+	var request struct {
+		Schedule []config.ImportLimitWindowConfig `json:"schedule"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.controlLogic.SetImportLimitSchedule(request.Schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, effective := h.controlLogic.GetImportLimitSchedule()
+	c.JSON(http.StatusOK, gin.H{
+		"schedule":           schedule,
+		"effective_limit_kw": effective,
+	})
+}
+
+// GetRapidDownwardStatus returns the automatic rapid downward state
+func (h *Handlers) GetRapidDownwardStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.controlLogic.GetRapidDownwardStatus())
+}
+
+// GetCurtailment returns the curtailment state and curtailed-energy account
+func (h *Handlers) GetCurtailment(c *gin.Context) {
+	c.JSON(http.StatusOK, h.controlLogic.GetCurtailmentStatus())
+}
+
+// SetCurtailment records a TSO/DSO-instructed export limitation with its
+// validity window; the order is released automatically at expiry
+func (h *Handlers) SetCurtailment(c *gin.Context) {
+	var request struct {
+		LimitKW   *float32  `json:"limit_kw" binding:"required"`
+		StartTime time.Time `json:"start_time"`
+		EndTime   time.Time `json:"end_time" binding:"required"`
+		Reference string    `json:"reference"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	order, err := h.controlLogic.SetCurtailment(*request.LimitKW, request.StartTime, request.EndTime, request.Reference, operatorIdentity(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// ClearCurtailment releases a curtailment order before its expiry
+func (h *Handlers) ClearCurtailment(c *gin.Context) {
+	if err := h.controlLogic.ClearCurtailment(); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.controlLogic.GetCurtailmentStatus())
+}
+
+// GetPeerStatus returns the peer coordination link state and the export
+// allocation negotiated for the shared connection point
+func (h *Handlers) GetPeerStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.peerService.GetStatus())
+}
+
+// GetSiteBalance returns the computed site net power and energy balance
+func (h *Handlers) GetSiteBalance(c *gin.Context) {
+	c.JSON(http.StatusOK, h.siteService.GetBalance())
+}
+
+// GetRuleStates returns the state of the configured automation rules
+func (h *Handlers) GetRuleStates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": h.config.Rules.Enabled,
+		"rules":   h.ruleEngine.GetRuleStates(),
+	})
+}
+
+// GetShadowStatus returns the shadow evaluation divergence statistics
+func (h *Handlers) GetShadowStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.shadowService.GetStatus())
+}
+
+// GetStateEstimate returns the reconciled power-flow estimate
+func (h *Handlers) GetStateEstimate(c *gin.Context) {
+	c.JSON(http.StatusOK, h.estimator.GetEstimate())
+}
+
+// GetControlOwnership returns the current local/remote control ownership
+func (h *Handlers) GetControlOwnership(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"ownership": h.controlLogic.GetControlOwnership(),
+	})
+}
+
+// SetControlOwnership switches control ownership between local and remote
+func (h *Handlers) SetControlOwnership(c *gin.Context) {
+	var request struct {
+		Ownership string `json:"ownership" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.controlLogic.SetControlOwnership(request.Ownership); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.log.Info("Control ownership changed via API",
+		zap.String("ownership", request.Ownership),
+		zap.String("client_ip", c.ClientIP()))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Control ownership changed",
+		"ownership": request.Ownership,
+	})
+}
+
+// SelectOperation arms a select-before-operate token for a dangerous action
+func (h *Handlers) SelectOperation(c *gin.Context) {
+	var request struct {
+		Action string `json:"action" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, expiresAt, err := h.controlLogic.SelectOperation(request.Action)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"action":     request.Action,
+		"token":      token,
+		"expires_at": expiresAt,
+	})
+}
+
+// confirmSBO enforces select-before-operate on a dangerous action when
+// enabled. It returns false after writing the error response when the
+// command must not proceed.
+func (h *Handlers) confirmSBO(c *gin.Context, action string, token string) bool {
+	if !h.controlLogic.SBOEnabled() {
+		return true
+	}
+
+	if token == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"error":  "selection token required",
+			"action": action,
+		})
+		return false
+	}
+
+	if err := h.controlLogic.ConfirmOperation(token, action); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return false
+	}
+
+	return true
+}
+
+// operatorIdentity identifies the requesting operator: the authenticated
+// session user when authentication is enabled, otherwise the X-Operator
+// header, falling back to the client IP
+func operatorIdentity(c *gin.Context) string {
+	if value, ok := c.Get("session"); ok {
+		if session, ok := value.(*auth.Session); ok {
+			return session.Username
+		}
+	}
+	if operator := c.GetHeader("X-Operator"); operator != "" {
+		return operator
+	}
+	return c.ClientIP()
+}
+
+// requireTwoManApproval queues a critical action for second-operator approval
+// when the two-man rule is enabled. It returns true when the request was
+// queued and the handler must not execute the action itself.
+func (h *Handlers) requireTwoManApproval(c *gin.Context, action string, execute func() error) bool {
+	if !h.controlLogic.TwoManRuleEnabled() {
+		return false
+	}
+
+	operator := operatorIdentity(c)
+	approvalID, err := h.controlLogic.RequestCriticalAction(action, operator, execute)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return true
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":     "Approval by a second operator required",
+		"action":      action,
+		"approval_id": approvalID,
+		"approve_url": fmt.Sprintf("/api/v1/control/approvals/%s/approve", approvalID),
+	})
+	return true
+}
+
+// GetPendingApprovals returns critical actions awaiting a second operator
+func (h *Handlers) GetPendingApprovals(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"pending": h.controlLogic.GetPendingActions(),
+	})
+}
+
+// ApproveCriticalAction approves and executes a pending critical action
+func (h *Handlers) ApproveCriticalAction(c *gin.Context) {
+	approvalID := c.Param("action_id")
+
+	if err := h.controlLogic.ApproveCriticalAction(approvalID, operatorIdentity(c)); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Critical action approved and executed",
+		"approval_id": approvalID,
+	})
+}
+
+// isDryRun reports whether a command should be evaluated without execution,
+// either because the caller requested it or the EMS runs in simulation mode
+func (h *Handlers) isDryRun(requested bool) bool {
+	return requested || h.config.EMS.SimulationMode
+}
+
+// ExplainControl returns a structured explanation of the current dispatch:
+// active mode, applied constraints, originating setpoints and the final
+// per-PCS commands
+func (h *Handlers) ExplainControl(c *gin.Context) {
+	c.JSON(http.StatusOK, h.controlLogic.ExplainControl())
+}
+
+// GetReserveSOC returns the protected islanding reserve SOC
+func (h *Handlers) GetReserveSOC(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"reserve_soc": h.controlLogic.GetReserveSOC(),
+	})
+}
+
+// SetReserveSOC renegotiates the protected islanding reserve SOC
+func (h *Handlers) SetReserveSOC(c *gin.Context) {
+	var request struct {
+		ReserveSOC *float32 `json:"reserve_soc" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.controlLogic.SetReserveSOC(*request.ReserveSOC, operatorIdentity(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reserve_soc": h.controlLogic.GetReserveSOC(),
+	})
+}
+
+// GetWarrantyPackage assembles the end-of-warranty data package of one
+// BMS string from stored data and returns it as a zip archive
+func (h *Handlers) GetWarrantyPackage(c *gin.Context) {
+	bmsID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid BMS ID"})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "365"))
+	if err != nil || days <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter"})
+		return
+	}
+
+	end := time.Now()
+	start := end.AddDate(0, 0, -days)
+
+	var buffer bytes.Buffer
+	if err := h.warranty.WriteDataPackage(c.Request.Context(), bmsID, start, end, &buffer); err != nil {
+		h.log.Error("Failed to assemble warranty data package",
+			zap.Int("bms_id", bmsID),
+			zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("warranty_bms%d_%s.zip", bmsID, end.Format("2006-01-02"))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/zip", buffer.Bytes())
+}
+
+// GetResyncStatus reports the progress of the resynchronization sequence
+func (h *Handlers) GetResyncStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.controlLogic.GetResyncStatus())
+}
+
+// StartResync starts the supervised resynchronization sequence after a
+// grid return
+func (h *Handlers) StartResync(c *gin.Context) {
+	if err := h.controlLogic.StartResynchronization(operatorIdentity(c)); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.controlLogic.GetResyncStatus())
+}
+
+// GetSetpointSources returns the active setpoint command source per PCS
+func (h *Handlers) GetSetpointSources(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"sources": h.controlLogic.GetActiveSetpointSources(),
+	})
+}
+
+// GetPowerQuality returns the current power quality state and the last
+// completed EN 50160 observation window
+func (h *Handlers) GetPowerQuality(c *gin.Context) {
+	c.JSON(http.StatusOK, h.powerQuality.GetStatus())
+}
+
+// Login authenticates a local or LDAP user and returns a session token
+func (h *Handlers) Login(c *gin.Context) {
+	var request struct {
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.authService.Login(request.Username, request.Password, c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// Logout terminates the current session
+func (h *Handlers) Logout(c *gin.Context) {
+	if token := c.GetHeader("X-Auth-Token"); token != "" {
+		h.authService.Logout(token)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// GetCurrentUser returns the session of the requesting user
+func (h *Handlers) GetCurrentUser(c *gin.Context) {
+	value, ok := c.Get("session")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+	c.JSON(http.StatusOK, value)
+}
+
+// GetUsers lists all user accounts
+func (h *Handlers) GetUsers(c *gin.Context) {
+	users, err := h.authService.GetUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+// CreateUser creates a new user account
+func (h *Handlers) CreateUser(c *gin.Context) {
+	var request struct {
+		Username string   `json:"username" binding:"required"`
+		Password string   `json:"password"`
+		Source   string   `json:"source" binding:"required,oneof=local ldap oidc"`
+		Role     string   `json:"role" binding:"required,oneof=viewer operator admin"`
+		Sites    []string `json:"sites"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.authService.CreateUser(request.Username, request.Password, request.Source, request.Role, request.Sites)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateUser changes role, scoping, password or disabled state of an account
+func (h *Handlers) UpdateUser(c *gin.Context) {
+	var request struct {
+		Password string   `json:"password"`
+		Role     string   `json:"role" binding:"omitempty,oneof=viewer operator admin"`
+		Sites    []string `json:"sites"`
+		Disabled *bool    `json:"disabled"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.authService.UpdateUser(c.Param("username"), request.Password, request.Role, request.Sites, request.Disabled)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser removes a user account
+func (h *Handlers) DeleteUser(c *gin.Context) {
+	if err := h.authService.DeleteUser(c.Param("username")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted"})
+}
+
+// GetLoginAttempts returns the audited login attempts, newest first
+func (h *Handlers) GetLoginAttempts(c *gin.Context) {
+	attempts, err := h.authService.GetLoginAttempts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"attempts": attempts})
+}
+
+// GetPlant returns the plant standing data: configured nameplate ratings
+// and grid connection identifiers plus the fleet composition derived from
+// the device configuration
+func (h *Handlers) GetPlant(c *gin.Context) {
+	transformers := make([]gin.H, 0, len(h.config.PCS))
+	for _, pcsConfig := range h.config.PCS {
+		transformers = append(transformers, gin.H{
+			"pcs_id":     pcsConfig.ID,
+			"rating_kva": pcsConfig.TransformerRatingKVA,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"park_name":            h.config.EMS.ParkName,
+		"connection_point_id":  h.config.Plant.ConnectionPointID,
+		"grid_operator":        h.config.Plant.GridOperator,
+		"grid_code":            h.config.Plant.GridCode,
+		"rated_power_kw":       h.config.Plant.RatedPowerKW,
+		"rated_energy_kwh":     h.config.Plant.RatedEnergyKWh,
+		"nominal_voltage_kv":   h.config.Plant.NominalVoltageKV,
+		"nominal_frequency_hz": h.config.Plant.NominalFrequencyHz,
+		"commissioning_date":   h.config.Plant.CommissioningDate,
+		"firmware_versions":    h.config.Plant.FirmwareVersions,
+		"pcs_count":            len(h.config.PCS),
+		"bms_count":            len(h.config.BMS),
+		"fcu_count":            len(h.config.WindFarm),
+		"transformers":         transformers,
+		"max_site_export_kw":   h.config.EMS.MaxSiteExportPower,
+		"max_site_import_kw":   h.config.EMS.MaxSiteImportPower,
+		"max_charge_kw":        h.config.EMS.MaxChargePower,
+		"max_discharge_kw":     h.config.EMS.MaxDischargePower,
+	})
+}
+
+// GetKPI returns computed site KPIs
+func (h *Handlers) GetKPI(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"losses": h.controlLogic.GetLossBreakdown(),
+	})
+}
+
 // GetBMSData returns BMS data
 func (h *Handlers) GetBMSData(c *gin.Context) {
 	bmsID := c.Param("id")
@@ -166,6 +772,7 @@ func (h *Handlers) GetBMSData(c *gin.Context) {
 		"bms_data":      bmsDataResponse,
 		"bms_rack_data": bmsRackDataResponse,
 		"bms_connected": service.IsConnected(),
+		"trends":        service.GetTrendStats(),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -228,7 +835,134 @@ func (h *Handlers) GetBMSRackData(c *gin.Context) {
 	c.JSON(http.StatusOK, bmsRackData[rackNo-1])
 }
 
-// GetBMSCommandState returns BMS command state
+// GetBMSRackDiagnosis combines the data, status relays, cell extremes and
+// related alarms of one rack into a single diagnostic view, so a rack
+// alarm can be worked without correlating across endpoints
+func (h *Handlers) GetBMSRackDiagnosis(c *gin.Context) {
+	bmsIDInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid BMS ID"})
+		return
+	}
+
+	service, err := h.bmsManager.GetService(bmsIDInt)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	rackNo, err := strconv.Atoi(c.Param("rack_no"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rack number"})
+		return
+	}
+
+	rackData := service.GetLatestBMSRackData()
+	if rackNo < 1 || rackNo > len(rackData) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rack not found"})
+		return
+	}
+
+	response := gin.H{
+		"bms_id":  bmsIDInt,
+		"rack_no": rackNo,
+		"rack":    rackData[rackNo-1],
+	}
+
+	rackStatus := service.GetLatestBMSRackStatusData()
+	if rackNo <= len(rackStatus) {
+		response["status"] = rackStatus[rackNo-1]
+	}
+
+	if extremes := rackCellExtremes(service, uint8(rackNo)); extremes != nil {
+		response["cell_extremes"] = extremes
+	}
+
+	// Alarms of this BMS; rack alarm codes are partitioned per rack, so
+	// other racks' alarms can be filtered out
+	systemType := fmt.Sprintf("BMS_%d", bmsIDInt)
+	rackType := fmt.Sprintf("BMS_%d_RACK", bmsIDInt)
+	rackCodeStart := bms.BMSRackAlarmStartAddr + uint16(rackNo-1)*bms.BMSRackDataOffset
+	rackCodeEnd := rackCodeStart + bms.BMSRackDataOffset
+
+	matches := func(alarmType string, alarmCode uint16) bool {
+		if alarmType == systemType {
+			return true
+		}
+		return alarmType == rackType && alarmCode > rackCodeStart && alarmCode <= rackCodeEnd
+	}
+
+	activeAlarms := []database.BMSAlarmData{}
+	for _, alarm := range h.alarmManager.GetActiveAlarms() {
+		if matches(alarm.AlarmType, alarm.AlarmCode) {
+			activeAlarms = append(activeAlarms, alarm)
+		}
+	}
+	response["active_alarms"] = activeAlarms
+
+	// Recent alarm transitions of this BMS/rack from the stored history
+	recentEvents := []database.AlarmRecord{}
+	history, err := h.alarmManager.GetAlarmHistory(c.Request.Context(), 200, 0)
+	if err == nil {
+		for _, record := range history {
+			if matches(record.AlarmType, record.AlarmCode) {
+				recentEvents = append(recentEvents, record)
+				if len(recentEvents) >= 20 {
+					break
+				}
+			}
+		}
+	}
+	response["recent_events"] = recentEvents
+
+	c.JSON(http.StatusOK, response)
+}
+
+// rackCellExtremes locates the extreme cell voltages and temperatures of
+// one rack in the latest cell sweep; nil when no cell data has been read
+func rackCellExtremes(service *bms.Service, rackNo uint8) gin.H {
+	voltages := service.GetLatestCellVoltageData(rackNo)
+	temperatures := service.GetLatestCellTemperatureData(rackNo)
+	if len(voltages) == 0 && len(temperatures) == 0 {
+		return nil
+	}
+
+	extremes := gin.H{}
+
+	if len(voltages) > 0 {
+		maxV, minV := voltages[0], voltages[0]
+		for _, cell := range voltages[1:] {
+			if cell.Voltage > maxV.Voltage {
+				maxV = cell
+			}
+			if cell.Voltage < minV.Voltage {
+				minV = cell
+			}
+		}
+		extremes["max_voltage"] = maxV
+		extremes["min_voltage"] = minV
+		extremes["voltage_spread"] = maxV.Voltage - minV.Voltage
+	}
+
+	if len(temperatures) > 0 {
+		maxT, minT := temperatures[0], temperatures[0]
+		for _, sensor := range temperatures[1:] {
+			if sensor.Temperature > maxT.Temperature {
+				maxT = sensor
+			}
+			if sensor.Temperature < minT.Temperature {
+				minT = sensor
+			}
+		}
+		extremes["max_temperature"] = maxT
+		extremes["min_temperature"] = minT
+		extremes["temperature_spread"] = maxT.Temperature - minT.Temperature
+	}
+
+	return extremes
+}
+
+// GetBMSCommandState returns BMS command state
 func (h *Handlers) GetBMSCommandState(c *gin.Context) {
 	bmsID := c.Param("id")
 	bmsIDInt, err := strconv.Atoi(bmsID)
@@ -250,6 +984,68 @@ func (h *Handlers) GetBMSCommandState(c *gin.Context) {
 	})
 }
 
+// GetBMSThroughput returns the per-rack energy throughput utilization and
+// the resulting dispatch derating factor of a BMS string
+func (h *Handlers) GetBMSThroughput(c *gin.Context) {
+	bmsID := c.Param("id")
+	bmsIDInt, err := strconv.Atoi(bmsID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid BMS ID"})
+		return
+	}
+
+	service, err := h.bmsManager.GetService(bmsIDInt)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"racks":    service.GetRackThroughput(),
+		"derating": service.ThroughputDerating(),
+	})
+}
+
+// GetBMSResidency returns the per-rack SOC/temperature residency histograms
+// of a BMS string without resetting the counters
+func (h *Handlers) GetBMSResidency(c *gin.Context) {
+	bmsID := c.Param("id")
+	bmsIDInt, err := strconv.Atoi(bmsID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid BMS ID"})
+		return
+	}
+
+	service, err := h.bmsManager.GetService(bmsIDInt)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"racks": service.GetRackResidency()})
+}
+
+// ResetBMSResidency atomically reads and resets the per-rack residency
+// histograms of a BMS string; the response carries the histograms as they
+// were before the reset, so a collector cannot lose samples between a read
+// and a separate reset
+func (h *Handlers) ResetBMSResidency(c *gin.Context) {
+	bmsID := c.Param("id")
+	bmsIDInt, err := strconv.Atoi(bmsID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid BMS ID"})
+		return
+	}
+
+	service, err := h.bmsManager.GetService(bmsIDInt)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"racks": service.ResetRackResidency()})
+}
+
 // GetPCSData returns PCS data
 func (h *Handlers) GetPCSData(c *gin.Context) {
 	pcsID := c.Param("id")
@@ -268,10 +1064,30 @@ func (h *Handlers) GetPCSData(c *gin.Context) {
 	pcsData := service.GetLatestPCSStatusData()
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": pcsData,
+		"data":   pcsData,
+		"trends": service.GetTrendStats(),
 	})
 }
 
+// GetPCSDCBreakdown returns the DC-side power and daily energy of a PCS
+// split by busbar role (battery vs DC-coupled PV)
+func (h *Handlers) GetPCSDCBreakdown(c *gin.Context) {
+	pcsID := c.Param("id")
+	pcsIDInt, err := strconv.Atoi(pcsID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid PCS ID"})
+		return
+	}
+
+	service, err := h.pcsManager.GetService(pcsIDInt)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, service.GetDCBreakdown())
+}
+
 // GetPCSCommandState returns PCS command state
 func (h *Handlers) GetPCSCommandState(c *gin.Context) {
 	pcsID := c.Param("id")
@@ -294,12 +1110,39 @@ func (h *Handlers) GetPCSCommandState(c *gin.Context) {
 	})
 }
 
+// GetPCSQueuedCommand returns the status of a queued PCS command
+func (h *Handlers) GetPCSQueuedCommand(c *gin.Context) {
+	pcsID := c.Param("id")
+	pcsIDInt, err := strconv.Atoi(pcsID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid PCS ID"})
+		return
+	}
+
+	service, err := h.pcsManager.GetService(pcsIDInt)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	command, err := service.GetQueuedCommand(c.Param("cmd_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"command": command,
+	})
+}
+
 // GetAlarms returns alarm information
 func (h *Handlers) GetAlarms(c *gin.Context) {
 	// Get query parameters
 	alarmType := c.Query("type")
 	severity := c.Query("severity")
 	active := c.Query("active")
+	acknowledged := c.Query("acknowledged")
 
 	var alarms []any
 
@@ -318,8 +1161,12 @@ func (h *Handlers) GetAlarms(c *gin.Context) {
 			}
 		}
 
-		history, err := h.alarmManager.GetAlarmHistory(limit, offset)
+		history, err := h.alarmManager.GetAlarmHistory(c.Request.Context(), limit, offset)
 		if err != nil {
+			if c.Request.Context().Err() != nil {
+				c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+				return
+			}
 			h.log.Error("Failed to get alarm history",
 				zap.Error(err),
 				zap.Int("limit", limit),
@@ -329,13 +1176,16 @@ func (h *Handlers) GetAlarms(c *gin.Context) {
 		}
 
 		for _, alarm := range history {
+			if acknowledged != "" && strconv.FormatBool(alarm.AckedAt != nil) != acknowledged {
+				continue
+			}
 			alarms = append(alarms, alarm)
 		}
 	} else {
-		// Get active alarms
-		activeAlarms := h.alarmManager.GetActiveAlarms()
+		// Get active alarms with their acknowledgement state
+		activeAlarms := h.alarmManager.GetActiveAlarmStates()
 
-		// Filter by type and severity if specified
+		// Filter by type, severity and acknowledgement state if specified
 		for _, alarm := range activeAlarms {
 			if alarmType != "" && alarm.AlarmType != alarmType {
 				continue
@@ -343,6 +1193,9 @@ func (h *Handlers) GetAlarms(c *gin.Context) {
 			if severity != "" && alarm.Severity != severity {
 				continue
 			}
+			if acknowledged != "" && strconv.FormatBool(alarm.Acknowledged) != acknowledged {
+				continue
+			}
 			alarms = append(alarms, alarm)
 		}
 	}
@@ -356,134 +1209,474 @@ func (h *Handlers) GetAlarms(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// SetControlMode sets the control mode
-func (h *Handlers) SetControlMode(c *gin.Context) {
+// GetModbusExceptions returns the accumulated Modbus exception statistics
+// of every PCS and BMS connection for diagnostics
+func (h *Handlers) GetModbusExceptions(c *gin.Context) {
+	devices := gin.H{}
+	for id, service := range h.pcsManager.GetAllServices() {
+		devices[fmt.Sprintf("pcs_%d", id)] = service.GetModbusExceptions()
+	}
+	for id, service := range h.bmsManager.GetAllServices() {
+		devices[fmt.Sprintf("bms_%d", id)] = service.GetModbusExceptions()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"devices":   devices,
+		"timestamp": time.Now(),
+	})
+}
+
+// GetScheduleEntries returns the active power schedule entries
+func (h *Handlers) GetScheduleEntries(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"entries": h.scheduler.GetEntries()})
+}
+
+// AddScheduleEntry adds an entry to the active power schedule
+func (h *Handlers) AddScheduleEntry(c *gin.Context) {
 	var request struct {
-		Mode string `json:"mode" binding:"required"`
+		StartTime  time.Time `json:"start_time" binding:"required"`
+		SetpointKW float32   `json:"setpoint_kw"`
+		RampRateKW float32   `json:"ramp_rate_kw_per_minute"`
+		TargetSOC  float32   `json:"target_soc"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		h.log.Warn("Invalid control mode request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
 		return
 	}
 
-	h.log.Info("Control mode change requested",
-		zap.String("requested_mode", request.Mode))
+	entry, err := h.scheduler.AddEntry(request.StartTime, request.SetpointKW, request.RampRateKW, request.TargetSOC)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Validate mode
-	validModes := []string{"AUTO", "MANUAL", "MAINTENANCE"}
-	isValid := false
-	for _, mode := range validModes {
-		if request.Mode == mode {
-			isValid = true
-			break
-		}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// DeleteScheduleEntry removes an entry from the active power schedule
+func (h *Handlers) DeleteScheduleEntry(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entry ID"})
+		return
 	}
 
-	if !isValid {
-		h.log.Warn("Invalid control mode requested",
-			zap.String("mode", request.Mode))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mode. Valid modes: AUTO, MANUAL, MAINTENANCE"})
+	if err := h.scheduler.DeleteEntry(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	h.controlLogic.SetMode(request.Mode)
+	c.JSON(http.StatusOK, gin.H{"status": "deleted", "id": id})
+}
 
-	h.log.Info("Control mode changed successfully",
-		zap.String("mode", request.Mode))
+// GetEffectiveSchedule returns the merged effective schedule trajectory for
+// visualization; defaults to the next 24 hours in 15 minute steps
+func (h *Handlers) GetEffectiveSchedule(c *gin.Context) {
+	now := time.Now()
+	start := now
+	end := now.Add(24 * time.Hour)
+	step := 15 * time.Minute
+
+	if value := c.Query("start"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start time, use RFC3339"})
+			return
+		}
+		start = parsed
+	}
+	if value := c.Query("end"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end time, use RFC3339"})
+			return
+		}
+		end = parsed
+	}
+	if value := c.Query("step"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid step duration"})
+			return
+		}
+		step = parsed
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "End must not be before start"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Control mode set successfully",
-		"mode":    request.Mode,
+		"points": h.scheduler.GetEffectiveSchedule(start, end, step),
 	})
 }
 
-// SetPCSStartStop starts or stops the PCS
-func (h *Handlers) SetPCSStartStop(c *gin.Context) {
-	var req struct {
-		ID    int   `json:"id" binding:"required"`
-		Start *bool `json:"start" binding:"required"`
-	}
+// GetCacheStats returns the statistics of the bounded in-memory caches
+func (h *Handlers) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"caches":    cache.CollectStats(),
+		"timestamp": time.Now(),
+	})
+}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// GetLoopStats returns the execution statistics of every periodic loop:
+// last run, duration and overrun count, with stalled loops flagged
+func (h *Handlers) GetLoopStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"loops":     loopwatch.CollectStats(),
+		"timestamp": time.Now(),
+	})
+}
+
+// GetProfileCaptures returns the automatic runtime profile captures taken
+// since startup
+func (h *Handlers) GetProfileCaptures(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"captures":  h.metricsManager.GetProfileCaptures(),
+		"timestamp": time.Now(),
+	})
+}
+
+// GetFlightRecordings lists the stored flight recordings
+func (h *Handlers) GetFlightRecordings(c *gin.Context) {
+	recordings, err := h.flightRecorder.ListRecordings()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"recordings": recordings})
+}
 
-	service, err := h.pcsManager.GetService(req.ID)
+// GetFlightRecording downloads one flight recording with its samples
+func (h *Handlers) GetFlightRecording(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recording ID"})
 		return
 	}
 
-	if err := service.StartStopCommand(*req.Start); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	recording, samples, err := h.flightRecorder.GetRecording(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	action := "stopped"
-	if *req.Start {
-		action = "started"
-	}
+	c.JSON(http.StatusOK, gin.H{
+		"recording": recording,
+		"samples":   samples,
+	})
+}
+
+// GetCommandLatency returns the response-time distribution and the recent
+// per-command traces for the SLA reporting
+func (h *Handlers) GetCommandLatency(c *gin.Context) {
+	traces := h.pcsManager.GetCommandTraces()
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("PCS %s successfully", action),
-		"start":   *req.Start,
+		"stats":     pcs.ComputeCommandLatencyStats(traces),
+		"traces":    traces,
+		"timestamp": time.Now(),
 	})
 }
 
-// SetPowerCommand sets manual power command
-func (h *Handlers) SetPowerCommand(c *gin.Context) {
+// GetSelfTest returns the control-chain self-test configuration and the
+// retained run history
+func (h *Handlers) GetSelfTest(c *gin.Context) {
+	results := h.selfTest.GetResults()
+
+	response := gin.H{
+		"enabled":   h.config.SelfTest.Enabled,
+		"time":      h.config.SelfTest.Time,
+		"results":   results,
+		"timestamp": time.Now(),
+	}
+	if len(results) > 0 {
+		response["last_result"] = results[len(results)-1]
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RunSelfTest triggers a control-chain self-test run outside the nightly
+// schedule
+func (h *Handlers) RunSelfTest(c *gin.Context) {
+	if err := h.selfTest.Trigger(); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.log.Info("Control-chain self-test triggered over the API")
+	c.JSON(http.StatusAccepted, gin.H{"message": "Self-test started"})
+}
+
+// AcknowledgeAlarm acknowledges an active alarm so its escalation chain stops
+func (h *Handlers) AcknowledgeAlarm(c *gin.Context) {
 	var request struct {
-		ID    int      `json:"id" binding:"required"`
-		Power *float32 `json:"power" binding:"required"`
+		AlarmType string `json:"alarm_type" binding:"required"`
+		AlarmCode uint16 `json:"alarm_code" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		h.log.Warn("Invalid power command request", zap.Error(err))
+		h.log.Warn("Invalid alarm acknowledge request", zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	service, err := h.pcsManager.GetService(request.ID)
-	if err != nil {
+	if err := h.alarmManager.AcknowledgeAlarm(request.AlarmType, request.AlarmCode); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	if request.Power == nil {
-		h.log.Warn("Power command request missing power field")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "power field is required"})
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Alarm acknowledged",
+		"alarm_type": request.AlarmType,
+		"alarm_code": request.AlarmCode,
+	})
+}
+
+// AcknowledgeAlarmRecord records an operator acknowledgement on an alarm
+// record, tracking who has seen it
+func (h *Handlers) AcknowledgeAlarmRecord(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alarm ID"})
 		return
 	}
 
-	h.log.Info("Manual power command requested",
-		zap.Float32("power", *request.Power))
+	var request struct {
+		AckedBy string `json:"acked_by" binding:"required"`
+		Comment string `json:"comment"`
+	}
 
-	// Execute manual power command
-	if err := service.SetActivePowerCommand(*request.Power); err != nil {
-		h.log.Error("Manual power command failed",
-			zap.Error(err),
-			zap.Float32("power", *request.Power))
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.log.Warn("Invalid alarm acknowledge request", zap.Error(err))
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	h.log.Info("Manual power command executed successfully",
-		zap.Float32("power", *request.Power))
+	record, err := h.alarmManager.AcknowledgeAlarmByID(uint(id), request.AckedBy, request.Comment)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"alarm": record})
+}
+
+// UnacknowledgeAlarmRecord reverts an operator acknowledgement
+func (h *Handlers) UnacknowledgeAlarmRecord(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alarm ID"})
+		return
+	}
+
+	record, err := h.alarmManager.UnacknowledgeAlarmByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"alarm": record})
+}
+
+// SetControlMode sets the control mode
+func (h *Handlers) SetControlMode(c *gin.Context) {
+	var request struct {
+		Mode   string `json:"mode" binding:"required"`
+		DryRun bool   `json:"dry_run"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.log.Warn("Invalid control mode request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.log.Info("Control mode change requested",
+		zap.String("requested_mode", request.Mode))
+
+	// Validate mode
+	validModes := []string{"AUTO", "MANUAL", "MAINTENANCE"}
+	isValid := false
+	for _, mode := range validModes {
+		if request.Mode == mode {
+			isValid = true
+			break
+		}
+	}
+
+	if !isValid {
+		h.log.Warn("Invalid control mode requested",
+			zap.String("mode", request.Mode))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mode. Valid modes: AUTO, MANUAL, MAINTENANCE"})
+		return
+	}
+
+	// Dry-run: the mode passed validation, report without switching
+	if h.isDryRun(request.DryRun) {
+		c.JSON(http.StatusOK, gin.H{
+			"dry_run":      true,
+			"current_mode": h.controlLogic.GetMode(),
+			"would_set":    request.Mode,
+		})
+		return
+	}
+
+	h.controlLogic.SetMode(request.Mode)
+
+	h.log.Info("Control mode changed successfully",
+		zap.String("mode", request.Mode))
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Power command executed successfully",
-		"power":   *request.Power,
+		"message": "Control mode set successfully",
+		"mode":    request.Mode,
+	})
+}
+
+// SetPCSStartStop starts or stops the PCS
+func (h *Handlers) SetPCSStartStop(c *gin.Context) {
+	var req struct {
+		ID     int   `json:"id" binding:"required"`
+		Start  *bool `json:"start" binding:"required"`
+		DryRun bool  `json:"dry_run"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	service, err := h.pcsManager.GetService(req.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Dry-run: report what would have been sent without queueing
+	if h.isDryRun(req.DryRun) {
+		c.JSON(http.StatusOK, gin.H{
+			"dry_run":    true,
+			"would_send": *req.Start,
+		})
+		return
+	}
+
+	// Stopping a PCS is a shutdown operation under the two-man rule
+	if !*req.Start {
+		if h.requireTwoManApproval(c, fmt.Sprintf("PCS %d stop", req.ID), func() error {
+			_, err := service.EnqueueCommand(pcs.CommandKindStartStop, 0, false)
+			return err
+		}) {
+			return
+		}
+	}
+
+	// Queue the command; start/stop commands execute strictly in order
+	cmdID, err := service.EnqueueCommand(pcs.CommandKindStartStop, 0, *req.Start)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	action := "stop"
+	if *req.Start {
+		action = "start"
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    fmt.Sprintf("PCS %s command queued", action),
+		"start":      *req.Start,
+		"command_id": cmdID,
+		"status_url": fmt.Sprintf("/api/v1/pcs/commands/%d/%s", req.ID, cmdID),
+	})
+}
+
+// SetPowerCommand sets manual power command
+func (h *Handlers) SetPowerCommand(c *gin.Context) {
+	var request struct {
+		ID     int      `json:"id" binding:"required"`
+		Power  *float32 `json:"power" binding:"required"`
+		DryRun bool     `json:"dry_run"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.log.Warn("Invalid power command request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	service, err := h.pcsManager.GetService(request.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if request.Power == nil {
+		h.log.Warn("Power command request missing power field")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "power field is required"})
+		return
+	}
+
+	h.log.Info("Manual power command requested",
+		zap.Float32("power", *request.Power),
+		zap.Bool("dry_run", h.isDryRun(request.DryRun)))
+
+	// Dry-run: evaluate the full pipeline without touching the device
+	if h.isDryRun(request.DryRun) {
+		power, err := h.controlLogic.EvaluatePCSPowerCommand(request.ID, control.SourceManual, *request.Power)
+		if err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "dry_run": true})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"dry_run":         true,
+			"requested_power": *request.Power,
+			"would_send":      power,
+		})
+		return
+	}
+
+	// Arbitrate against other setpoint sources and enforce site limits
+	power, err := h.controlLogic.ArbitratePCSPowerCommand(request.ID, control.SourceManual, *request.Power)
+	if err != nil {
+		status := http.StatusConflict
+		if errors.Is(err, control.ErrNotControlOwner) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Queue the command; execution and retries happen asynchronously
+	cmdID, err := service.EnqueueCommand(pcs.CommandKindActivePower, power, false)
+	if err != nil {
+		h.log.Error("Failed to queue power command",
+			zap.Error(err),
+			zap.Float32("power", power))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.log.Info("Manual power command queued",
+		zap.String("command_id", cmdID),
+		zap.Float32("power", power))
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    "Power command queued",
+		"power":      power,
+		"command_id": cmdID,
+		"status_url": fmt.Sprintf("/api/v1/pcs/commands/%d/%s", request.ID, cmdID),
 	})
 }
 
 // SetReactivePowerCommand sets manual reactive power command
 func (h *Handlers) SetReactivePowerCommand(c *gin.Context) {
 	var request struct {
-		ID    int      `json:"id" binding:"required"`
-		Power *float32 `json:"power" binding:"required"`
+		ID     int      `json:"id" binding:"required"`
+		Power  *float32 `json:"power" binding:"required"`
+		DryRun bool     `json:"dry_run"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -505,23 +1698,38 @@ func (h *Handlers) SetReactivePowerCommand(c *gin.Context) {
 	}
 
 	h.log.Info("Manual reactive power command requested",
-		zap.Float32("power", *request.Power))
+		zap.Float32("power", *request.Power),
+		zap.Bool("dry_run", h.isDryRun(request.DryRun)))
+
+	// Dry-run: report what would have been sent without queueing
+	if h.isDryRun(request.DryRun) {
+		c.JSON(http.StatusOK, gin.H{
+			"dry_run":         true,
+			"requested_power": *request.Power,
+			"would_send":      *request.Power,
+		})
+		return
+	}
 
-	// Execute manual power command
-	if err := service.SetReactivePowerCommand(*request.Power); err != nil {
-		h.log.Error("Manual reactive power command failed",
+	// Queue the command; execution and retries happen asynchronously
+	cmdID, err := service.EnqueueCommand(pcs.CommandKindReactivePower, *request.Power, false)
+	if err != nil {
+		h.log.Error("Failed to queue reactive power command",
 			zap.Error(err),
 			zap.Float32("power", *request.Power))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
 		return
 	}
 
-	h.log.Info("Manual power command executed successfully",
+	h.log.Info("Manual reactive power command queued",
+		zap.String("command_id", cmdID),
 		zap.Float32("power", *request.Power))
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Power command executed successfully",
-		"power":   *request.Power,
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    "Reactive power command queued",
+		"power":      *request.Power,
+		"command_id": cmdID,
+		"status_url": fmt.Sprintf("/api/v1/pcs/commands/%d/%s", request.ID, cmdID),
 	})
 }
 
@@ -586,8 +1794,9 @@ func (h *Handlers) PCSReset(c *gin.Context) {
 // BMSBreakerControl controls the main breaker
 func (h *Handlers) BMSBreakerControl(c *gin.Context) {
 	var request struct {
-		ID     int    `json:"id" binding:"required"`
-		Action string `json:"action" binding:"required"`
+		ID       int    `json:"id" binding:"required"`
+		Action   string `json:"action" binding:"required"`
+		SBOToken string `json:"sbo_token"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -612,6 +1821,16 @@ func (h *Handlers) BMSBreakerControl(c *gin.Context) {
 		return
 	}
 
+	if !h.confirmSBO(c, fmt.Sprintf("BMS %d main breaker %s", request.ID, request.Action), request.SBOToken) {
+		return
+	}
+
+	if h.requireTwoManApproval(c, fmt.Sprintf("BMS %d main breaker %s", request.ID, request.Action), func() error {
+		return service.ControlMainBreaker(action)
+	}) {
+		return
+	}
+
 	if err := service.ControlMainBreaker(action); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -794,8 +2013,9 @@ func (h *Handlers) GetPLCData(c *gin.Context) {
 // ControlAuxiliaryCB controls the auxiliary circuit breaker
 func (h *Handlers) ControlAuxiliaryCB(c *gin.Context) {
 	var request struct {
-		ID    int   `json:"id" binding:"required"`
-		Close *bool `json:"close" binding:"required"`
+		ID       int    `json:"id" binding:"required"`
+		Close    *bool  `json:"close" binding:"required"`
+		SBOToken string `json:"sbo_token"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -809,16 +2029,26 @@ func (h *Handlers) ControlAuxiliaryCB(c *gin.Context) {
 		return
 	}
 
-	if err := service.ControlAuxiliaryCB(*request.Close); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
 	action := "opened"
 	if *request.Close {
 		action = "closed"
 	}
 
+	if !h.confirmSBO(c, fmt.Sprintf("Auxiliary CB %s (PLC %d)", action, request.ID), request.SBOToken) {
+		return
+	}
+
+	if h.requireTwoManApproval(c, fmt.Sprintf("Auxiliary CB %s (PLC %d)", action, request.ID), func() error {
+		return service.ControlAuxiliaryCB(*request.Close)
+	}) {
+		return
+	}
+
+	if err := service.ControlAuxiliaryCB(*request.Close); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	h.log.Info("Auxiliary CB control executed",
 		zap.String("action", action),
 		zap.String("client_ip", c.ClientIP()))
@@ -832,8 +2062,9 @@ func (h *Handlers) ControlAuxiliaryCB(c *gin.Context) {
 // ControlMVAuxTransformerCB controls the MV auxiliary transformer circuit breaker
 func (h *Handlers) ControlMVAuxTransformerCB(c *gin.Context) {
 	var request struct {
-		ID    int   `json:"id" binding:"required"`
-		Close *bool `json:"close" binding:"required"`
+		ID       int    `json:"id" binding:"required"`
+		Close    *bool  `json:"close" binding:"required"`
+		SBOToken string `json:"sbo_token"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -847,16 +2078,26 @@ func (h *Handlers) ControlMVAuxTransformerCB(c *gin.Context) {
 		return
 	}
 
-	if err := service.ControlMVAuxTransformerCB(*request.Close); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
 	action := "opened"
 	if *request.Close {
 		action = "closed"
 	}
 
+	if !h.confirmSBO(c, fmt.Sprintf("MV Aux Transformer CB %s (PLC %d)", action, request.ID), request.SBOToken) {
+		return
+	}
+
+	if h.requireTwoManApproval(c, fmt.Sprintf("MV Aux Transformer CB %s (PLC %d)", action, request.ID), func() error {
+		return service.ControlMVAuxTransformerCB(*request.Close)
+	}) {
+		return
+	}
+
+	if err := service.ControlMVAuxTransformerCB(*request.Close); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	h.log.Info("MV Aux Transformer CB control executed",
 		zap.String("action", action),
 		zap.String("client_ip", c.ClientIP()))
@@ -870,9 +2111,10 @@ func (h *Handlers) ControlMVAuxTransformerCB(c *gin.Context) {
 // ControlTransformerCB controls a transformer circuit breaker
 func (h *Handlers) ControlTransformerCB(c *gin.Context) {
 	var request struct {
-		ID            int   `json:"id" binding:"required"`
-		TransformerNo uint8 `json:"transformer_no" binding:"required,min=1,max=4"`
-		Close         *bool `json:"close" binding:"required"`
+		ID            int    `json:"id" binding:"required"`
+		TransformerNo uint8  `json:"transformer_no" binding:"required,min=1,max=4"`
+		Close         *bool  `json:"close" binding:"required"`
+		SBOToken      string `json:"sbo_token"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -886,16 +2128,26 @@ func (h *Handlers) ControlTransformerCB(c *gin.Context) {
 		return
 	}
 
-	if err := service.ControlTransformerCB(request.TransformerNo, *request.Close); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
 	action := "opened"
 	if *request.Close {
 		action = "closed"
 	}
 
+	if !h.confirmSBO(c, fmt.Sprintf("Transformer %d CB %s (PLC %d)", request.TransformerNo, action, request.ID), request.SBOToken) {
+		return
+	}
+
+	if h.requireTwoManApproval(c, fmt.Sprintf("Transformer %d CB %s (PLC %d)", request.TransformerNo, action, request.ID), func() error {
+		return service.ControlTransformerCB(request.TransformerNo, *request.Close)
+	}) {
+		return
+	}
+
+	if err := service.ControlTransformerCB(request.TransformerNo, *request.Close); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	h.log.Info("Transformer CB control executed",
 		zap.Uint8("transformer_no", request.TransformerNo),
 		zap.String("action", action),
@@ -911,8 +2163,9 @@ func (h *Handlers) ControlTransformerCB(c *gin.Context) {
 // ControlAutoproducerCB controls the autoproducer circuit breaker
 func (h *Handlers) ControlAutoproducerCB(c *gin.Context) {
 	var request struct {
-		ID    int   `json:"id" binding:"required"`
-		Close *bool `json:"close" binding:"required"`
+		ID       int    `json:"id" binding:"required"`
+		Close    *bool  `json:"close" binding:"required"`
+		SBOToken string `json:"sbo_token"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -926,16 +2179,26 @@ func (h *Handlers) ControlAutoproducerCB(c *gin.Context) {
 		return
 	}
 
-	if err := service.ControlAutoproducerCB(*request.Close); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
 	action := "opened"
 	if *request.Close {
 		action = "closed"
 	}
 
+	if !h.confirmSBO(c, fmt.Sprintf("Autoproducer CB %s (PLC %d)", action, request.ID), request.SBOToken) {
+		return
+	}
+
+	if h.requireTwoManApproval(c, fmt.Sprintf("Autoproducer CB %s (PLC %d)", action, request.ID), func() error {
+		return service.ControlAutoproducerCB(*request.Close)
+	}) {
+		return
+	}
+
+	if err := service.ControlAutoproducerCB(*request.Close); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	h.log.Info("Autoproducer CB control executed",
 		zap.String("action", action),
 		zap.String("client_ip", c.ClientIP()))
@@ -949,7 +2212,8 @@ func (h *Handlers) ControlAutoproducerCB(c *gin.Context) {
 // ResetAllCircuitBreakers opens all circuit breakers (emergency function)
 func (h *Handlers) ResetAllCircuitBreakers(c *gin.Context) {
 	var request struct {
-		ID int `json:"id" binding:"required"`
+		ID       int    `json:"id" binding:"required"`
+		SBOToken string `json:"sbo_token"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -963,6 +2227,16 @@ func (h *Handlers) ResetAllCircuitBreakers(c *gin.Context) {
 		return
 	}
 
+	if !h.confirmSBO(c, fmt.Sprintf("Open all circuit breakers (PLC %d)", request.ID), request.SBOToken) {
+		return
+	}
+
+	if h.requireTwoManApproval(c, fmt.Sprintf("Open all circuit breakers (PLC %d)", request.ID), func() error {
+		return service.ResetAllCircuitBreakers()
+	}) {
+		return
+	}
+
 	if err := service.ResetAllCircuitBreakers(); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -999,6 +2273,7 @@ func (h *Handlers) GetWindFarmData(c *gin.Context) {
 		"data":       data,
 		"connected":  service.IsConnected(),
 		"fcu_online": service.IsFCUOnline(),
+		"trends":     service.GetTrendStats(),
 	})
 }
 
@@ -1036,25 +2311,63 @@ func (h *Handlers) GetWindFarmCommandState(c *gin.Context) {
 	})
 }
 
-// StartWindFarm starts a wind farm
-func (h *Handlers) StartWindFarm(c *gin.Context) {
-	var request struct {
-		ID int `json:"id" binding:"required"`
+// GetWindFarmReconciliation returns the outcome of the last setpoint
+// reconciliation against the FCU mirrors after a reconnect or restart
+func (h *Handlers) GetWindFarmReconciliation(c *gin.Context) {
+	windFarmID := c.Param("id")
+	windFarmIDInt, err := strconv.Atoi(windFarmID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wind farm ID"})
+		return
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	service, err := h.windFarmManager.GetService(windFarmIDInt)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	service, err := h.windFarmManager.GetService(request.ID)
+	c.JSON(http.StatusOK, service.GetReconcileResult())
+}
+
+// GetWindFarmKPI returns the daily wind farm KPIs (availability, performance
+// ratio and communication quality)
+func (h *Handlers) GetWindFarmKPI(c *gin.Context) {
+	windFarmID := c.Param("id")
+	windFarmIDInt, err := strconv.Atoi(windFarmID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wind farm ID"})
 		return
 	}
 
-	if err := service.StartWindFarm(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	service, err := h.windFarmManager.GetService(windFarmIDInt)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, service.GetKPIReport())
+}
+
+// StartWindFarm starts a wind farm
+func (h *Handlers) StartWindFarm(c *gin.Context) {
+	var request struct {
+		ID int `json:"id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	service, err := h.windFarmManager.GetService(request.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := service.StartWindFarm(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -1400,3 +2713,526 @@ func (h *Handlers) ReceiveSchedule(c *gin.Context) {
 func strPtr(s string) *string {
 	return &s
 }
+
+// GetChaosFaults returns the active chaos fault injections
+func (h *Handlers) GetChaosFaults(c *gin.Context) {
+	if !h.chaosManager.Enabled() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "chaos fault injection is disabled"})
+		return
+	}
+
+	faults := make([]gin.H, 0)
+	for _, fault := range h.chaosManager.GetFaults() {
+		faults = append(faults, gin.H{
+			"name":        fault.Name,
+			"probability": fault.Probability,
+			"delay_ms":    fault.Delay.Milliseconds(),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"faults": faults})
+}
+
+// SetChaosFault activates or updates a chaos fault injection
+func (h *Handlers) SetChaosFault(c *gin.Context) {
+	if !h.chaosManager.Enabled() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "chaos fault injection is disabled"})
+		return
+	}
+
+	var req struct {
+		Name        string  `json:"name" binding:"required"`
+		Probability float64 `json:"probability" binding:"required"`
+		DelayMS     int64   `json:"delay_ms"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fault := chaos.Fault{
+		Name:        req.Name,
+		Probability: req.Probability,
+		Delay:       time.Duration(req.DelayMS) * time.Millisecond,
+	}
+
+	if err := h.chaosManager.SetFault(fault); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("fault %s activated", fault.Name),
+	})
+}
+
+// ClearChaosFault deactivates a chaos fault injection
+func (h *Handlers) ClearChaosFault(c *gin.Context) {
+	if !h.chaosManager.Enabled() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "chaos fault injection is disabled"})
+		return
+	}
+
+	if err := h.chaosManager.ClearFault(c.Param("name")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("fault %s cleared", c.Param("name")),
+	})
+}
+
+// GetConfigVersions returns the recorded configuration version history
+func (h *Handlers) GetConfigVersions(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	versions, err := h.configVersions.ListVersions(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// GetConfigVersion returns one configuration version including its content
+func (h *Handlers) GetConfigVersion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version id"})
+		return
+	}
+
+	version, err := h.configVersions.GetVersion(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version": version,
+		"content": version.Content,
+	})
+}
+
+// RollbackConfigVersion rolls the configuration file back to a previous
+// version; the new configuration takes effect on the next service restart
+func (h *Handlers) RollbackConfigVersion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version id"})
+		return
+	}
+
+	if err := h.configVersions.Rollback(uint(id), operatorIdentity(c)); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          fmt.Sprintf("configuration rolled back to version %d", id),
+		"restart_required": true,
+	})
+}
+
+// GetFeatureFlags returns the state of every known feature flag
+func (h *Handlers) GetFeatureFlags(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"flags": h.featureManager.GetFlags(),
+	})
+}
+
+// SetFeatureFlag applies a runtime override for a feature flag
+func (h *Handlers) SetFeatureFlag(c *gin.Context) {
+	var request struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := c.Param("name")
+	if err := h.featureManager.SetFlag(name, *request.Enabled); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.log.Info("Feature flag changed via API",
+		zap.String("flag", name),
+		zap.Bool("enabled", *request.Enabled),
+		zap.String("operator", operatorIdentity(c)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"flag":    name,
+		"enabled": *request.Enabled,
+	})
+}
+
+// ClearFeatureFlag removes a runtime override for a feature flag
+func (h *Handlers) ClearFeatureFlag(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.featureManager.ClearFlag(name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("override for %s cleared", name),
+	})
+}
+
+// GetMeasurementSchema returns the generated measurement schema document
+// for InfluxDB consumers
+func (h *Handlers) GetMeasurementSchema(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"measurements": database.SchemaDocument(),
+	})
+}
+
+// GetRegisterDocumentation returns the generated Modbus register/point
+// documentation for the polled devices and the internal Modbus server
+func (h *Handlers) GetRegisterDocumentation(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"devices": []registerdoc.Device{
+			bms.RegisterDocumentation(),
+			pcs.RegisterDocumentation(),
+			windfarm.RegisterDocumentation(),
+			ion7400.RegisterDocumentation(),
+		},
+		"server": modbus.RegisterDocumentation(),
+	})
+}
+
+// GetTelemetryProfile returns the active telemetry profile and the
+// available profiles
+func (h *Handlers) GetTelemetryProfile(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"active":   h.telemetry.Current(),
+		"source":   h.telemetry.Source(),
+		"profiles": h.telemetry.Profiles(),
+	})
+}
+
+// SetTelemetryProfile switches the telemetry profile at runtime
+func (h *Handlers) SetTelemetryProfile(c *gin.Context) {
+	var request struct {
+		Profile string `json:"profile" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.telemetry.SetProfile(request.Profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.log.Info("Telemetry profile changed via API",
+		zap.String("profile", request.Profile),
+		zap.String("operator", operatorIdentity(c)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"active": h.telemetry.Current(),
+		"source": h.telemetry.Source(),
+	})
+}
+
+// ClearTelemetryProfile removes the runtime profile override
+func (h *Handlers) ClearTelemetryProfile(c *gin.Context) {
+	h.telemetry.ClearProfile()
+
+	c.JSON(http.StatusOK, gin.H{
+		"active": h.telemetry.Current(),
+		"source": h.telemetry.Source(),
+	})
+}
+
+// GetDeclaration returns the latest availability declaration and the
+// registered maintenance windows
+func (h *Handlers) GetDeclaration(c *gin.Context) {
+	response := gin.H{
+		"maintenance_windows": h.declaration.MaintenanceWindows(),
+	}
+	if current, ok := h.declaration.Current(); ok {
+		response["declaration"] = current
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CompileDeclaration compiles a fresh declaration on demand, replacing any
+// unapproved one
+func (h *Handlers) CompileDeclaration(c *gin.Context) {
+	declaration := h.declaration.Compile()
+
+	h.log.Info("Availability declaration compiled via API",
+		zap.String("date", declaration.Date),
+		zap.String("operator", operatorIdentity(c)))
+
+	c.JSON(http.StatusOK, declaration)
+}
+
+// ApproveDeclaration approves the pending declaration and triggers its
+// delivery
+func (h *Handlers) ApproveDeclaration(c *gin.Context) {
+	declaration, err := h.declaration.Approve(operatorIdentity(c))
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, declaration)
+}
+
+// AddMaintenanceWindow registers a maintenance window for the declaration
+// compiler
+func (h *Handlers) AddMaintenanceWindow(c *gin.Context) {
+	var request struct {
+		Start   time.Time `json:"start" binding:"required"`
+		End     time.Time `json:"end" binding:"required"`
+		PowerKW float32   `json:"power_kw"`
+		Reason  string    `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	window, err := h.declaration.AddMaintenanceWindow(request.Start, request.End, request.PowerKW, request.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.log.Info("Maintenance window registered via API",
+		zap.Int("id", window.ID),
+		zap.String("operator", operatorIdentity(c)))
+
+	c.JSON(http.StatusCreated, window)
+}
+
+// DeleteMaintenanceWindow removes a registered maintenance window
+func (h *Handlers) DeleteMaintenanceWindow(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window ID"})
+		return
+	}
+
+	if err := h.declaration.RemoveMaintenanceWindow(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted", "id": id})
+}
+
+// GetSeasonProfile returns the active seasonal operating profile and the
+// configured profiles
+func (h *Handlers) GetSeasonProfile(c *gin.Context) {
+	response := gin.H{
+		"source":   h.season.Source(),
+		"profiles": h.season.Profiles(),
+	}
+	if profile, active := h.season.Current(); active {
+		response["active"] = profile
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// SetSeasonProfile switches the seasonal operating profile at runtime
+func (h *Handlers) SetSeasonProfile(c *gin.Context) {
+	var request struct {
+		Profile string `json:"profile" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.season.SetProfile(request.Profile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.log.Info("Season profile changed via API",
+		zap.String("profile", request.Profile),
+		zap.String("operator", operatorIdentity(c)))
+
+	profile, _ := h.season.Current()
+	c.JSON(http.StatusOK, gin.H{
+		"active": profile,
+		"source": h.season.Source(),
+	})
+}
+
+// ClearSeasonProfile removes the runtime profile override
+func (h *Handlers) ClearSeasonProfile(c *gin.Context) {
+	h.season.ClearProfile()
+
+	response := gin.H{"source": h.season.Source()}
+	if profile, active := h.season.Current(); active {
+		response["active"] = profile
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFCRState returns the current FCR frequency measurement and activation
+func (h *Handlers) GetFCRState(c *gin.Context) {
+	activation := h.fcrController.GetActivationState()
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":    h.config.FCR.Enabled,
+		"frequency":  h.fcrController.GetLastFrequency(),
+		"activation": activation,
+		"delivery":   h.fcrDelivery.GetState(),
+		"plant":      h.fcrPlant.GetState(),
+		"recovery":   h.fcrRecovery.GetState(),
+		"portfolio":  h.fcrPortfolio.GetState(activation.ActivatedPowerKW),
+	})
+}
+
+// GetAFRRState returns the current aFRR delivery state and settlement
+// energies
+func (h *Handlers) GetAFRRState(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": h.config.AFRR.Enabled,
+		"state":   h.afrrController.GetState(),
+	})
+}
+
+// ActivateAFRR begins an aFRR delivery period
+func (h *Handlers) ActivateAFRR(c *gin.Context) {
+	if err := h.afrrController.Activate(operatorIdentity(c)); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.afrrController.GetState())
+}
+
+// DeactivateAFRR ends the aFRR delivery period; the output ramps back to
+// zero along the gradient limit
+func (h *Handlers) DeactivateAFRR(c *gin.Context) {
+	if err := h.afrrController.Deactivate(operatorIdentity(c)); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.afrrController.GetState())
+}
+
+// SetAFRRSetpoint records an aFRR power setpoint, for TSO links that come
+// in over HTTPS instead of the SCADA register
+func (h *Handlers) SetAFRRSetpoint(c *gin.Context) {
+	var request struct {
+		SetpointKW *float32 `json:"setpoint_kw" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.afrrController.SetSetpoint(*request.SetpointKW, operatorIdentity(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.afrrController.GetState())
+}
+
+// SetAFRRCapacity updates the contracted aFRR capacity per direction
+func (h *Handlers) SetAFRRCapacity(c *gin.Context) {
+	var request struct {
+		CapacityUpKW   *float32 `json:"capacity_up_kw" binding:"required"`
+		CapacityDownKW *float32 `json:"capacity_down_kw" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.afrrController.SetCapacity(*request.CapacityUpKW, *request.CapacityDownKW, operatorIdentity(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.afrrController.GetState())
+}
+
+// GetFCRProducts returns the activation parameters of every FCR product
+func (h *Handlers) GetFCRProducts(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"active":   h.config.FCR.Product,
+		"products": h.fcrController.GetProducts(),
+	})
+}
+
+// UpdateFCRProduct replaces the activation parameters of an FCR product at
+// runtime; the change is validated and audit-logged with the operator
+func (h *Handlers) UpdateFCRProduct(c *gin.Context) {
+	var request struct {
+		NominalFrequency float32 `json:"nominal_frequency" binding:"required"`
+		Deadband         float32 `json:"deadband"`
+		ActivationBand   float32 `json:"activation_band" binding:"required"`
+		MaxPower         float32 `json:"max_power"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := c.Param("name")
+	params := config.FCRProductConfig{
+		NominalFrequency: request.NominalFrequency,
+		Deadband:         request.Deadband,
+		ActivationBand:   request.ActivationBand,
+		MaxPower:         request.MaxPower,
+	}
+
+	if err := h.fcrController.UpdateProduct(name, params, operatorIdentity(c)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product":    name,
+		"parameters": params,
+	})
+}
+
+// SimulateDispatch replays stored frequency history through the real FCR
+// activation and shaping code for offline sizing studies, returning the SOC
+// trajectory, violations and energy statistics of the candidate battery
+func (h *Handlers) SimulateDispatch(c *gin.Context) {
+	var request fcr.SimulationRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.fcrController.Simulate(c.Request.Context(), request)
+	if err != nil {
+		// An aborted replay reports how far it got before the deadline
+		if c.Request.Context().Err() != nil {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
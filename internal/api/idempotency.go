@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idempotencyCapacity bounds the replay store; a SCADA integration holding
+// more keys than this within one window gets its excess requests executed
+// without deduplication instead of growing the map unboundedly
+const idempotencyCapacity = 1024
+
+// idempotencyEntry is one recorded POST execution; until done it marks the
+// first attempt as still in flight
+type idempotencyEntry struct {
+	done        bool
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// idempotencyStore holds the recorded responses keyed by method, path and
+// idempotency key
+type idempotencyStore struct {
+	mutex   sync.Mutex
+	entries map[string]*idempotencyEntry
+}
+
+// IdempotencyMiddleware deduplicates POST requests retried by SCADA
+// middleware: a request carrying an Idempotency-Key header is executed once,
+// and every retry of the same key within the window receives the recorded
+// response again. A retry arriving while the first attempt is still running
+// is answered with 409 instead of a double execution.
+func IdempotencyMiddleware(window time.Duration) gin.HandlerFunc {
+	store := &idempotencyStore{entries: make(map[string]*idempotencyEntry)}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if window <= 0 || key == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+
+		storeKey := c.Request.URL.Path + " " + key
+		entry, first := store.begin(storeKey, window)
+		if !first {
+			if entry == nil {
+				// Store full; execute without deduplication
+				c.Next()
+				return
+			}
+			if !entry.done {
+				c.AbortWithStatusJSON(http.StatusConflict,
+					gin.H{"error": "a request with this idempotency key is still in progress"})
+				return
+			}
+
+			c.Header("Idempotency-Replayed", "true")
+			c.Data(entry.status, entry.contentType, entry.body)
+			c.Abort()
+			return
+		}
+
+		recorder := &replayRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		store.finish(storeKey, c.Writer.Status(), c.Writer.Header().Get("Content-Type"),
+			recorder.body.Bytes(), window)
+	}
+}
+
+// begin claims a key for execution. It returns the existing entry when the
+// key was seen before, and nil, false when the store is full.
+func (s *idempotencyStore) begin(key string, window time.Duration) (*idempotencyEntry, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	for existing, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, existing)
+		}
+	}
+
+	if entry, exists := s.entries[key]; exists {
+		return entry, false
+	}
+	if len(s.entries) >= idempotencyCapacity {
+		return nil, false
+	}
+
+	s.entries[key] = &idempotencyEntry{expiresAt: now.Add(window)}
+	return nil, true
+}
+
+// finish records the response for replay; server errors are dropped so a
+// retry executes the command again
+func (s *idempotencyStore) finish(key string, status int, contentType string, body []byte, window time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, exists := s.entries[key]
+	if !exists {
+		return
+	}
+	if status >= http.StatusInternalServerError {
+		delete(s.entries, key)
+		return
+	}
+
+	entry.done = true
+	entry.status = status
+	entry.contentType = contentType
+	entry.body = body
+	entry.expiresAt = time.Now().Add(window)
+}
+
+// replayRecorder tees the response body into a buffer while it is written
+// to the client
+type replayRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *replayRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *replayRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
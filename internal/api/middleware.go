@@ -1,8 +1,15 @@
 package api
 
 import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/auth"
 )
 
 // LoggerMiddleware provides request logging using the decoupled logger
@@ -87,10 +94,88 @@ func RateLimitMiddleware() gin.HandlerFunc {
 	}
 }
 
-// AuthMiddleware provides authentication (placeholder)
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware enforces authentication and role-based access when user
+// management is enabled. Session tokens come from the X-Auth-Token header;
+// OIDC bearer tokens from the Authorization header. Write requests require
+// the operator role, user management the admin role.
+func AuthMiddleware(authService *auth.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authService.Enabled() || isPublicPath(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		session, ok := resolveSession(c, authService)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+
+		role := auth.RoleViewer
+		if c.Request.Method != http.MethodGet {
+			role = auth.RoleOperator
+		}
+		if strings.HasPrefix(c.Request.URL.Path, "/api/v1/users") ||
+			strings.HasPrefix(c.Request.URL.Path, "/debug/pprof") ||
+			(c.Request.URL.Path == "/api/v1/control/reserve" && c.Request.Method != http.MethodGet) {
+			role = auth.RoleAdmin
+		}
+
+		if !authService.HasRole(session, role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient privileges"})
+			return
+		}
+
+		c.Set("session", session)
+		c.Next()
+	}
+}
+
+// isPublicPath lists the endpoints reachable without authentication
+func isPublicPath(path string) bool {
+	return path == "/health" || path == "/api/v1/auth/login"
+}
+
+// resolveSession extracts and validates the session of a request
+func resolveSession(c *gin.Context, authService *auth.Service) (*auth.Session, bool) {
+	if token := c.GetHeader("X-Auth-Token"); token != "" {
+		if session, ok := authService.Authenticate(token); ok {
+			return session, true
+		}
+	}
+
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		token := strings.TrimPrefix(header, "Bearer ")
+		if session, ok := authService.Authenticate(token); ok {
+			return session, true
+		}
+		if session, ok := authService.AuthenticateBearer(token, c.ClientIP()); ok {
+			return session, true
+		}
+	}
+
+	return nil, false
+}
+
+// RequestTimeoutMiddleware attaches a deadline to the request context, so
+// device commands and database queries running on it abort once the budget
+// is spent or the client disconnects. A request that produced no response
+// by its deadline is answered with 504.
+func RequestTimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Add authentication logic here
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
 		c.Next()
+
+		if ctx.Err() == context.DeadlineExceeded && !c.Writer.Written() {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "request deadline exceeded"})
+		}
 	}
 }
@@ -9,13 +9,33 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/afrr"
 	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/auth"
 	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/chaos"
 	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/configversion"
 	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/declaration"
+	"powerkonnekt/ems/internal/estimator"
+	"powerkonnekt/ems/internal/fcr"
+	"powerkonnekt/ems/internal/features"
 	"powerkonnekt/ems/internal/health"
+	"powerkonnekt/ems/internal/metrics"
 	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/peer"
 	"powerkonnekt/ems/internal/plc"
+	"powerkonnekt/ems/internal/powerquality"
+	"powerkonnekt/ems/internal/recorder"
+	"powerkonnekt/ems/internal/rules"
+	"powerkonnekt/ems/internal/schedule"
+	"powerkonnekt/ems/internal/season"
+	"powerkonnekt/ems/internal/selftest"
+	"powerkonnekt/ems/internal/shadow"
+	"powerkonnekt/ems/internal/site"
+	"powerkonnekt/ems/internal/telemetry"
+	"powerkonnekt/ems/internal/warranty"
 	"powerkonnekt/ems/internal/windfarm"
 )
 
@@ -39,6 +59,30 @@ func ProvideHandlers(
 	alarmManager *alarm.Manager,
 	controlLogic *control.Logic,
 	healthService *health.HealthService,
+	chaosManager *chaos.Manager,
+	configVersions *configversion.Service,
+	featureManager *features.Manager,
+	telemetryManager *telemetry.Manager,
+	seasonManager *season.Manager,
+	declarationService *declaration.Service,
+	fcrController *fcr.Controller,
+	afrrController *afrr.Controller,
+	fcrDelivery *fcr.DeliveryMonitor,
+	fcrPlant *fcr.PlantConditionTracker,
+	fcrRecovery *fcr.RecoveryPlanner,
+	fcrPortfolio *fcr.Portfolio,
+	siteService *site.Service,
+	ruleEngine *rules.Engine,
+	shadowService *shadow.Service,
+	estimatorService *estimator.Service,
+	powerQuality *powerquality.Service,
+	authService *auth.Service,
+	warrantyExporter *warranty.Exporter,
+	metricsManager *metrics.Manager,
+	scheduler *schedule.Service,
+	selfTest *selftest.Service,
+	flightRecorder *recorder.Service,
+	peerService *peer.Service,
 	logger *zap.Logger,
 ) *Handlers {
 	return NewHandlers(
@@ -50,18 +94,43 @@ func ProvideHandlers(
 		alarmManager,
 		controlLogic,
 		healthService,
+		chaosManager,
+		configVersions,
+		featureManager,
+		telemetryManager,
+		seasonManager,
+		declarationService,
+		fcrController,
+		afrrController,
+		fcrDelivery,
+		fcrPlant,
+		fcrRecovery,
+		fcrPortfolio,
+		siteService,
+		ruleEngine,
+		shadowService,
+		estimatorService,
+		powerQuality,
+		authService,
+		warrantyExporter,
+		metricsManager,
+		scheduler,
+		selfTest,
+		flightRecorder,
+		peerService,
 		logger,
 	)
 }
 
 // ProvideRouter creates and configures the Gin router
-func ProvideRouter(handlers *Handlers, logger *zap.Logger) *gin.Engine {
-	return NewRouter(handlers, logger)
+func ProvideRouter(cfg *config.Config, handlers *Handlers, authService *auth.Service, logger *zap.Logger) *gin.Engine {
+	return NewRouter(cfg, handlers, authService, logger)
 }
 
-// ProvideHTTPServer creates the HTTP server
+// ProvideHTTPServer creates the HTTP server, bound to the IT network
+// interface when one is configured
 func ProvideHTTPServer(cfg *config.Config, router *gin.Engine) *http.Server {
-	addr := fmt.Sprintf(":%d", cfg.EMS.HTTPPort)
+	addr := fmt.Sprintf("%s:%d", cfg.Network.ITBindAddress, cfg.EMS.HTTPPort)
 
 	return &http.Server{
 		Addr:    addr,
@@ -1,12 +1,17 @@
 package api
 
 import (
+	"net/http/pprof"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/auth"
+	"powerkonnekt/ems/internal/config"
 )
 
 // NewRouter sets up the Gin router with all routes and middleware
-func NewRouter(handlers *Handlers, logger *zap.Logger) *gin.Engine {
+func NewRouter(cfg *config.Config, handlers *Handlers, authService *auth.Service, logger *zap.Logger) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 
@@ -14,13 +19,33 @@ func NewRouter(handlers *Handlers, logger *zap.Logger) *gin.Engine {
 	router.Use(LoggerMiddleware(logger))
 	router.Use(CORSMiddleware())
 	router.Use(ErrorHandlerMiddleware(logger))
+	router.Use(AuthMiddleware(authService))
 	router.Use(gin.Recovery())
 
 	// Health check
 	router.GET("/health", handlers.HealthCheck)
 
-	// API routes
-	api := router.Group("/api/v1")
+	// Runtime profiling (admin role)
+	pprofGroup := router.Group("/debug/pprof")
+	{
+		pprofGroup.GET("/", gin.WrapF(pprof.Index))
+		pprofGroup.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		pprofGroup.GET("/profile", gin.WrapF(pprof.Profile))
+		pprofGroup.GET("/symbol", gin.WrapF(pprof.Symbol))
+		pprofGroup.GET("/trace", gin.WrapF(pprof.Trace))
+		pprofGroup.GET("/:name", gin.WrapF(pprof.Index))
+	}
+
+	// API routes, each carrying a per-request deadline; the slow group
+	// runs the history and simulation routes on a larger budget
+	slow := router.Group("/api/v1", RequestTimeoutMiddleware(cfg.EMS.HTTPSlowRequestTimeout))
+	{
+		slow.GET("/alarms", handlers.GetAlarms)
+		slow.POST("/simulate", handlers.SimulateDispatch)
+	}
+
+	api := router.Group("/api/v1", RequestTimeoutMiddleware(cfg.EMS.HTTPRequestTimeout),
+		IdempotencyMiddleware(cfg.EMS.IdempotencyWindow))
 	{
 		// System status
 		api.GET("/status", handlers.GetStatus)
@@ -28,15 +53,108 @@ func NewRouter(handlers *Handlers, logger *zap.Logger) *gin.Engine {
 		// Telemetry endpoint
 		api.GET("/telemetry", handlers.GetTelemetry)
 
+		// Telemetry profile for bandwidth-limited sites
+		api.GET("/telemetry/profile", handlers.GetTelemetryProfile)
+		api.POST("/telemetry/profile", handlers.SetTelemetryProfile)
+		api.DELETE("/telemetry/profile", handlers.ClearTelemetryProfile)
+
+		// Off-taker availability declaration
+		api.GET("/declaration", handlers.GetDeclaration)
+		api.POST("/declaration/compile", handlers.CompileDeclaration)
+		api.POST("/declaration/approve", handlers.ApproveDeclaration)
+		api.POST("/declaration/maintenance", handlers.AddMaintenanceWindow)
+		api.DELETE("/declaration/maintenance/:id", handlers.DeleteMaintenanceWindow)
+
+		// Seasonal operating profile
+		api.GET("/season/profile", handlers.GetSeasonProfile)
+		api.POST("/season/profile", handlers.SetSeasonProfile)
+		api.DELETE("/season/profile", handlers.ClearSeasonProfile)
+
+		// Site connection-point limits and headroom
+		api.GET("/site/limits", handlers.GetSiteLimits)
+		api.GET("/site/import-limit-schedule", handlers.GetImportLimitSchedule)
+		api.PUT("/site/import-limit-schedule", handlers.SetImportLimitSchedule)
+		api.GET("/site/rapid-downward", handlers.GetRapidDownwardStatus)
+		api.GET("/site/balance", handlers.GetSiteBalance)
+
+		// Peer coordination of the shared connection point
+		api.GET("/peer", handlers.GetPeerStatus)
+
+		// TSO/DSO-instructed export curtailment
+		api.GET("/curtailment", handlers.GetCurtailment)
+		api.POST("/curtailment", handlers.SetCurtailment)
+		api.DELETE("/curtailment", handlers.ClearCurtailment)
+		api.GET("/rules", handlers.GetRuleStates)
+		api.GET("/shadow", handlers.GetShadowStatus)
+		api.GET("/estimate", handlers.GetStateEstimate)
+		api.GET("/power-quality", handlers.GetPowerQuality)
+
+		// Authentication and session management
+		authGroup := api.Group("/auth")
+		{
+			authGroup.POST("/login", handlers.Login)
+			authGroup.POST("/logout", handlers.Logout)
+			authGroup.GET("/me", handlers.GetCurrentUser)
+		}
+
+		// User management (admin role)
+		usersGroup := api.Group("/users")
+		{
+			usersGroup.GET("", handlers.GetUsers)
+			usersGroup.POST("", handlers.CreateUser)
+			usersGroup.PUT("/:username", handlers.UpdateUser)
+			usersGroup.DELETE("/:username", handlers.DeleteUser)
+			usersGroup.GET("/login-attempts", handlers.GetLoginAttempts)
+		}
+
+		// Plant standing data (nameplate ratings, grid connection)
+		api.GET("/plant", handlers.GetPlant)
+
+		// Computed KPIs (per-stage losses)
+		api.GET("/kpi", handlers.GetKPI)
+
 		// Data endpoints
-		api.GET("/alarms", handlers.GetAlarms)
+		api.POST("/alarms/acknowledge", handlers.AcknowledgeAlarm)
+		api.POST("/alarms/:id/ack", handlers.AcknowledgeAlarmRecord)
+		api.POST("/alarms/:id/unack", handlers.UnacknowledgeAlarmRecord)
 
 		// Schedule endpoint
 		api.POST("/schedule", handlers.ReceiveSchedule)
 
+		// Explicit active power schedule with ramped transitions
+		api.GET("/schedule/entries", handlers.GetScheduleEntries)
+		api.POST("/schedule/entries", handlers.AddScheduleEntry)
+		api.DELETE("/schedule/entries/:id", handlers.DeleteScheduleEntry)
+		api.GET("/schedule/effective", handlers.GetEffectiveSchedule)
+
+		// Nightly control-chain self-test
+		api.GET("/selftest", handlers.GetSelfTest)
+		api.POST("/selftest/run", handlers.RunSelfTest)
+
 		// Control endpoints
 		api.POST("/control/mode", handlers.SetControlMode)
 		api.POST("/control/active-power", handlers.SetPowerCommand)
+		api.GET("/control/setpoint-sources", handlers.GetSetpointSources)
+		api.GET("/control/reserve", handlers.GetReserveSOC)
+		api.POST("/control/reserve", handlers.SetReserveSOC)
+		api.GET("/warranty/package/:id", handlers.GetWarrantyPackage)
+		api.GET("/control/resync", handlers.GetResyncStatus)
+		api.POST("/control/resync", handlers.StartResync)
+		api.GET("/control/explain", handlers.ExplainControl)
+		api.GET("/control/command-latency", handlers.GetCommandLatency)
+		api.GET("/diagnostics/modbus-exceptions", handlers.GetModbusExceptions)
+		api.GET("/diagnostics/profiles", handlers.GetProfileCaptures)
+		api.GET("/diagnostics/flight-recordings", handlers.GetFlightRecordings)
+		api.GET("/diagnostics/flight-recordings/:id", handlers.GetFlightRecording)
+		api.GET("/diagnostics/caches", handlers.GetCacheStats)
+		api.GET("/diagnostics/loops", handlers.GetLoopStats)
+		api.GET("/diagnostics/schema", handlers.GetMeasurementSchema)
+		api.GET("/diagnostics/registers", handlers.GetRegisterDocumentation)
+		api.GET("/control/ownership", handlers.GetControlOwnership)
+		api.POST("/control/ownership", handlers.SetControlOwnership)
+		api.POST("/control/select", handlers.SelectOperation)
+		api.GET("/control/approvals", handlers.GetPendingApprovals)
+		api.POST("/control/approvals/:action_id/approve", handlers.ApproveCriticalAction)
 		api.POST("/control/reactive-power", handlers.SetReactivePowerCommand)
 
 		// BMS endpoints
@@ -46,7 +164,11 @@ func NewRouter(handlers *Handlers, logger *zap.Logger) *gin.Engine {
 			bmsGroup.GET("/data/:id", handlers.GetBMSData)
 			bmsGroup.GET("/racks/:id", handlers.GetBMSRacks)
 			bmsGroup.GET("/racks/:id/:rack_no", handlers.GetBMSRackData)
+			bmsGroup.GET("/racks/:id/:rack_no/diagnosis", handlers.GetBMSRackDiagnosis)
 			bmsGroup.GET("/command-state/:id", handlers.GetBMSCommandState)
+			bmsGroup.GET("/throughput/:id", handlers.GetBMSThroughput)
+			bmsGroup.GET("/residency/:id", handlers.GetBMSResidency)
+			bmsGroup.POST("/residency/:id/reset", handlers.ResetBMSResidency)
 
 			// Control endpoints
 			bmsGroup.POST("/reset", handlers.BMSReset)
@@ -61,6 +183,8 @@ func NewRouter(handlers *Handlers, logger *zap.Logger) *gin.Engine {
 		{
 			pcsGroup.GET("/data/:id", handlers.GetPCSData)
 			pcsGroup.GET("/command-state/:id", handlers.GetPCSCommandState)
+			pcsGroup.GET("/dc/:id", handlers.GetPCSDCBreakdown)
+			pcsGroup.GET("/commands/:id/:cmd_id", handlers.GetPCSQueuedCommand)
 			pcsGroup.POST("/start", handlers.SetPCSStartStop)
 			pcsGroup.POST("/reset", handlers.PCSReset)
 		}
@@ -79,6 +203,48 @@ func NewRouter(handlers *Handlers, logger *zap.Logger) *gin.Engine {
 			plcGroup.POST("/reset-all", handlers.ResetAllCircuitBreakers)
 		}
 
+		// Feature flags
+		featuresGroup := api.Group("/features")
+		{
+			featuresGroup.GET("", handlers.GetFeatureFlags)
+			featuresGroup.POST("/:name", handlers.SetFeatureFlag)
+			featuresGroup.DELETE("/:name", handlers.ClearFeatureFlag)
+		}
+
+		// Configuration version history and rollback
+		configGroup := api.Group("/config")
+		{
+			configGroup.GET("/versions", handlers.GetConfigVersions)
+			configGroup.GET("/versions/:id", handlers.GetConfigVersion)
+			configGroup.POST("/versions/:id/rollback", handlers.RollbackConfigVersion)
+		}
+
+		// FCR state and per-product activation parameters
+		fcrGroup := api.Group("/fcr")
+		{
+			fcrGroup.GET("/state", handlers.GetFCRState)
+			fcrGroup.GET("/products", handlers.GetFCRProducts)
+			fcrGroup.PUT("/products/:name", handlers.UpdateFCRProduct)
+		}
+
+		// aFRR delivery and setpoint following
+		afrrGroup := api.Group("/afrr")
+		{
+			afrrGroup.GET("/state", handlers.GetAFRRState)
+			afrrGroup.POST("/activate", handlers.ActivateAFRR)
+			afrrGroup.POST("/deactivate", handlers.DeactivateAFRR)
+			afrrGroup.POST("/setpoint", handlers.SetAFRRSetpoint)
+			afrrGroup.PUT("/capacity", handlers.SetAFRRCapacity)
+		}
+
+		// Engineering endpoints (chaos fault injection; 403 unless enabled)
+		engineeringGroup := api.Group("/engineering")
+		{
+			engineeringGroup.GET("/chaos", handlers.GetChaosFaults)
+			engineeringGroup.POST("/chaos", handlers.SetChaosFault)
+			engineeringGroup.DELETE("/chaos/:name", handlers.ClearChaosFault)
+		}
+
 		// Wind Farm endpoints
 		windFarmGroup := api.Group("/windfarm")
 		{
@@ -86,6 +252,8 @@ func NewRouter(handlers *Handlers, logger *zap.Logger) *gin.Engine {
 			windFarmGroup.GET("/data/:id", handlers.GetWindFarmData)
 			windFarmGroup.GET("/summary", handlers.GetWindFarmSummary)
 			windFarmGroup.GET("/command-state/:id", handlers.GetWindFarmCommandState)
+			windFarmGroup.GET("/reconciliation/:id", handlers.GetWindFarmReconciliation)
+			windFarmGroup.GET("/kpi/:id", handlers.GetWindFarmKPI)
 
 			// Control endpoints
 			windFarmGroup.POST("/start", handlers.StartWindFarm)
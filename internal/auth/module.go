@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+)
+
+// Module provides the authentication service to the Fx application
+var Module = fx.Module("auth",
+	fx.Provide(ProvideService),
+)
+
+// ProvideService creates and provides an authentication service instance
+func ProvideService(
+	cfg *config.Config,
+	postgreSQL *database.PostgreSQL,
+	logger *zap.Logger,
+) *Service {
+	return NewService(cfg.Auth, cfg.EMS.ParkName, postgreSQL, logger)
+}
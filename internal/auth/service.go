@@ -0,0 +1,442 @@
+// Package auth implements user management and API authentication: local
+// accounts with a password policy, optional LDAP/OIDC federation for
+// corporate SSO, per-user roles with per-site scoping, and an audit trail
+// of login attempts.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-ldap/ldap/v3"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+)
+
+// User roles, in ascending order of privilege
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// Account sources
+const (
+	SourceLocal = "local"
+	SourceLDAP  = "ldap"
+	SourceOIDC  = "oidc"
+)
+
+// oidcSessionTTL bounds how long a validated OIDC bearer token is cached
+// before it is revalidated against the identity provider
+const oidcSessionTTL = 5 * time.Minute
+
+// loginAttemptLimit caps the login attempt listing
+const loginAttemptLimit = 200
+
+// rolePriority orders the roles for privilege checks
+var rolePriority = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// Session is one authenticated API session
+type Session struct {
+	Token    string    `json:"token"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	Sites    []string  `json:"sites,omitempty"` // empty = all sites
+	Source   string    `json:"source"`
+	Expires  time.Time `json:"expires"`
+}
+
+// Service manages user accounts and API sessions
+type Service struct {
+	config     config.AuthConfig
+	parkName   string
+	postgreSQL *database.PostgreSQL
+	httpClient *http.Client
+	log        *zap.Logger
+
+	mutex    sync.RWMutex
+	sessions map[string]*Session
+}
+
+// NewService creates the authentication service
+func NewService(cfg config.AuthConfig, parkName string, postgreSQL *database.PostgreSQL, logger *zap.Logger) *Service {
+	return &Service{
+		config:     cfg,
+		parkName:   parkName,
+		postgreSQL: postgreSQL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		sessions:   make(map[string]*Session),
+		log: logger.With(
+			zap.String("service", "auth"),
+		),
+	}
+}
+
+// Enabled reports whether API authentication is enforced
+func (s *Service) Enabled() bool {
+	return s.config.Enabled
+}
+
+// Login authenticates a local or LDAP user by password and creates a
+// session; every attempt is audited
+func (s *Service) Login(username, password, remoteAddr string) (*Session, error) {
+	user, err := s.postgreSQL.GetUserByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.Disabled {
+		s.audit(username, SourceLocal, remoteAddr, false, "unknown or disabled account")
+		return nil, fmt.Errorf("invalid credentials")
+	}
+
+	switch user.Source {
+	case SourceLocal:
+		if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+			s.audit(username, SourceLocal, remoteAddr, false, "wrong password")
+			return nil, fmt.Errorf("invalid credentials")
+		}
+	case SourceLDAP:
+		if err := s.ldapBind(username, password); err != nil {
+			s.audit(username, SourceLDAP, remoteAddr, false, err.Error())
+			return nil, fmt.Errorf("invalid credentials")
+		}
+	default:
+		s.audit(username, user.Source, remoteAddr, false, "password login not supported for source")
+		return nil, fmt.Errorf("account %s authenticates via %s", username, user.Source)
+	}
+
+	s.audit(username, user.Source, remoteAddr, true, "")
+	return s.createSession(user, s.config.SessionTTL), nil
+}
+
+// Logout terminates a session
+func (s *Service) Logout(token string) {
+	s.mutex.Lock()
+	delete(s.sessions, token)
+	s.mutex.Unlock()
+}
+
+// Authenticate resolves a session token; expired sessions are rejected
+func (s *Service) Authenticate(token string) (*Session, bool) {
+	s.mutex.RLock()
+	session, ok := s.sessions[token]
+	s.mutex.RUnlock()
+
+	if !ok || time.Now().After(session.Expires) {
+		return nil, false
+	}
+	return session, true
+}
+
+// AuthenticateBearer validates an OIDC bearer token against the identity
+// provider userinfo endpoint and maps it to a provisioned user account.
+// Validated tokens are cached briefly as regular sessions.
+func (s *Service) AuthenticateBearer(token, remoteAddr string) (*Session, bool) {
+	if !s.config.OIDC.Enabled {
+		return nil, false
+	}
+
+	if session, ok := s.Authenticate(token); ok {
+		return session, true
+	}
+
+	username, err := s.userinfoUsername(token)
+	if err != nil {
+		s.audit("", SourceOIDC, remoteAddr, false, err.Error())
+		return nil, false
+	}
+
+	user, err := s.postgreSQL.GetUserByUsername(username)
+	if err != nil || user == nil || user.Disabled || user.Source != SourceOIDC {
+		s.audit(username, SourceOIDC, remoteAddr, false, "no provisioned OIDC account")
+		return nil, false
+	}
+
+	s.audit(username, SourceOIDC, remoteAddr, true, "")
+	session := s.createSession(user, oidcSessionTTL)
+
+	// Key the cached session by the bearer token so subsequent requests
+	// with the same token resolve without another userinfo round trip
+	s.mutex.Lock()
+	delete(s.sessions, session.Token)
+	session.Token = token
+	s.sessions[token] = session
+	s.mutex.Unlock()
+
+	return session, true
+}
+
+// HasRole reports whether a session grants at least the given role and
+// covers this site
+func (s *Service) HasRole(session *Session, role string) bool {
+	if rolePriority[session.Role] < rolePriority[role] {
+		return false
+	}
+
+	if len(session.Sites) == 0 {
+		return true
+	}
+	for _, site := range session.Sites {
+		if site == s.parkName {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateUser validates and persists a new user account
+func (s *Service) CreateUser(username, password, source, role string, sites []string) (*database.UserRecord, error) {
+	if username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+	if _, ok := rolePriority[role]; !ok {
+		return nil, fmt.Errorf("unknown role: %s", role)
+	}
+	if source != SourceLocal && source != SourceLDAP && source != SourceOIDC {
+		return nil, fmt.Errorf("unknown source: %s", source)
+	}
+
+	record := &database.UserRecord{
+		Username: username,
+		Source:   source,
+		Role:     role,
+		Sites:    strings.Join(sites, ","),
+	}
+
+	if source == SourceLocal {
+		if err := s.ValidatePassword(password); err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		record.PasswordHash = string(hash)
+	}
+
+	if err := s.postgreSQL.CreateUser(record); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("User created",
+		zap.String("username", username),
+		zap.String("source", source),
+		zap.String("role", role))
+
+	return record, nil
+}
+
+// UpdateUser applies changes to an existing account; empty values keep the
+// current setting
+func (s *Service) UpdateUser(username, password, role string, sites []string, disabled *bool) (*database.UserRecord, error) {
+	user, err := s.postgreSQL.GetUserByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user %s not found", username)
+	}
+
+	if role != "" {
+		if _, ok := rolePriority[role]; !ok {
+			return nil, fmt.Errorf("unknown role: %s", role)
+		}
+		user.Role = role
+	}
+	if sites != nil {
+		user.Sites = strings.Join(sites, ",")
+	}
+	if disabled != nil {
+		user.Disabled = *disabled
+	}
+	if password != "" {
+		if user.Source != SourceLocal {
+			return nil, fmt.Errorf("account %s has no local password", username)
+		}
+		if err := s.ValidatePassword(password); err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		user.PasswordHash = string(hash)
+	}
+
+	if err := s.postgreSQL.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("User updated", zap.String("username", username))
+	return user, nil
+}
+
+// DeleteUser removes an account and its sessions
+func (s *Service) DeleteUser(username string) error {
+	if err := s.postgreSQL.DeleteUser(username); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	for token, session := range s.sessions {
+		if session.Username == username {
+			delete(s.sessions, token)
+		}
+	}
+	s.mutex.Unlock()
+
+	s.log.Info("User deleted", zap.String("username", username))
+	return nil
+}
+
+// GetUsers returns all user accounts
+func (s *Service) GetUsers() ([]database.UserRecord, error) {
+	return s.postgreSQL.GetUsers()
+}
+
+// GetLoginAttempts returns the most recent audited login attempts
+func (s *Service) GetLoginAttempts() ([]database.LoginAttemptRecord, error) {
+	return s.postgreSQL.GetLoginAttempts(loginAttemptLimit)
+}
+
+// ValidatePassword checks a password against the configured policy
+func (s *Service) ValidatePassword(password string) error {
+	if len(password) < s.config.PasswordMinLength {
+		return fmt.Errorf("password must be at least %d characters", s.config.PasswordMinLength)
+	}
+
+	if s.config.PasswordMixedCase {
+		var upper, lower bool
+		for _, r := range password {
+			upper = upper || unicode.IsUpper(r)
+			lower = lower || unicode.IsLower(r)
+		}
+		if !upper || !lower {
+			return fmt.Errorf("password must contain upper and lower case characters")
+		}
+	}
+
+	if s.config.PasswordDigit {
+		hasDigit := false
+		for _, r := range password {
+			hasDigit = hasDigit || unicode.IsDigit(r)
+		}
+		if !hasDigit {
+			return fmt.Errorf("password must contain a digit")
+		}
+	}
+
+	return nil
+}
+
+// createSession registers a new session for a user
+func (s *Service) createSession(user *database.UserRecord, ttl time.Duration) *Session {
+	session := &Session{
+		Token:    newToken(),
+		Username: user.Username,
+		Role:     user.Role,
+		Source:   user.Source,
+		Expires:  time.Now().Add(ttl),
+	}
+	if user.Sites != "" {
+		session.Sites = strings.Split(user.Sites, ",")
+	}
+
+	s.mutex.Lock()
+	for token, existing := range s.sessions {
+		if time.Now().After(existing.Expires) {
+			delete(s.sessions, token)
+		}
+	}
+	s.sessions[session.Token] = session
+	s.mutex.Unlock()
+
+	return session
+}
+
+// ldapBind authenticates a user by binding against the corporate directory
+func (s *Service) ldapBind(username, password string) error {
+	if !s.config.LDAP.Enabled {
+		return fmt.Errorf("LDAP federation is not enabled")
+	}
+
+	conn, err := ldap.DialURL(s.config.LDAP.URL)
+	if err != nil {
+		return fmt.Errorf("failed to reach LDAP server: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(fmt.Sprintf(s.config.LDAP.UserDNTemplate, username), password); err != nil {
+		return fmt.Errorf("LDAP bind rejected: %w", err)
+	}
+	return nil
+}
+
+// userinfoUsername resolves the username claim of a bearer token via the
+// identity provider userinfo endpoint
+func (s *Service) userinfoUsername(token string) (string, error) {
+	request, err := http.NewRequest(http.MethodGet, s.config.OIDC.UserinfoURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build userinfo request: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("userinfo request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("userinfo rejected token: status %d", response.StatusCode)
+	}
+
+	var claims map[string]interface{}
+	if err := json.NewDecoder(response.Body).Decode(&claims); err != nil {
+		return "", fmt.Errorf("failed to decode userinfo response: %w", err)
+	}
+
+	username, _ := claims[s.config.OIDC.UsernameClaim].(string)
+	if username == "" {
+		return "", fmt.Errorf("userinfo response misses claim %s", s.config.OIDC.UsernameClaim)
+	}
+	return username, nil
+}
+
+// audit persists one login attempt
+func (s *Service) audit(username, source, remoteAddr string, success bool, detail string) {
+	record := &database.LoginAttemptRecord{
+		Timestamp:  time.Now(),
+		Username:   username,
+		Source:     source,
+		RemoteAddr: remoteAddr,
+		Success:    success,
+		Detail:     detail,
+	}
+	if err := s.postgreSQL.SaveLoginAttempt(record); err != nil {
+		s.log.Error("Failed to audit login attempt", zap.Error(err))
+	}
+}
+
+// newToken generates a random session token
+func newToken() string {
+	buffer := make([]byte, 32)
+	rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
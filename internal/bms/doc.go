@@ -0,0 +1,93 @@
+package bms
+
+import (
+	"powerkonnekt/ems/internal/registerdoc"
+)
+
+// RegisterDocumentation describes the CATL EnerC register map polled by the
+// BMS service, derived from the protocol constants
+func RegisterDocumentation() registerdoc.Device {
+	return registerdoc.Device{
+		Device:      "bms",
+		Description: "CATL EnerC battery management system",
+		Blocks: []registerdoc.Block{
+			{
+				Name:      "bms_data",
+				Table:     "holding",
+				StartAddr: BMSDataStartAddr,
+				Length:    BMSDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "bms_status",
+				Table:     "holding",
+				StartAddr: BMSStatusDataStartAddr,
+				Length:    BMSStatusDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:       "rack_status",
+				Table:      "holding",
+				StartAddr:  BMSRackStatusDataStartAddr,
+				Length:     BMSRackStatusDataLength,
+				UnitOffset: BMSRackDataOffset,
+				Direction:  registerdoc.DirectionRead,
+			},
+			{
+				Name:       "rack_data",
+				Table:      "holding",
+				StartAddr:  BMSRackDataStartAddr,
+				Length:     BMSRackDataLength,
+				UnitOffset: BMSRackDataOffset,
+				Direction:  registerdoc.DirectionRead,
+			},
+			{
+				Name:      "system_alarms",
+				Table:     "holding",
+				StartAddr: BMSAlarmStartAddr,
+				Length:    BMSAlarmLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:       "rack_alarms",
+				Table:      "holding",
+				StartAddr:  BMSRackAlarmStartAddr,
+				Length:     BMSRackAlarmLength,
+				UnitOffset: BMSRackDataOffset,
+				Direction:  registerdoc.DirectionRead,
+			},
+			{
+				Name:        "cell_voltages",
+				Table:       "holding",
+				StartAddr:   CellVoltageBaseAddr,
+				UnitOffset:  BMSRackDataOffset,
+				Direction:   registerdoc.DirectionRead,
+				Description: "One register per cell, 52 cells per module",
+			},
+			{
+				Name:        "cell_temperatures",
+				Table:       "holding",
+				StartAddr:   CellTempBaseAddr,
+				UnitOffset:  BMSRackDataOffset,
+				Direction:   registerdoc.DirectionRead,
+				Description: "One register per sensor, 8 sensors per module",
+			},
+			{
+				Name:      "control",
+				Table:     "holding",
+				StartAddr: HeartbeatRegister,
+				Direction: registerdoc.DirectionWrite,
+				Points: []registerdoc.Point{
+					{Address: HeartbeatRegister, Name: "heartbeat", Direction: registerdoc.DirectionWrite, Description: "Incrementing EMS heartbeat counter"},
+					{Address: BreakerControlRegister, Name: "breaker_control", Direction: registerdoc.DirectionWrite, Description: "1 = close, 2 = open"},
+					{Address: InsulationControlRegister, Name: "insulation_control", Direction: registerdoc.DirectionWrite, Description: "1 = off, 2 = on"},
+					{Address: FaultClearRegister, Name: "fault_clear", Direction: registerdoc.DirectionWrite, Description: "1 = reset"},
+					{Address: RackDisableRegister1, Name: "rack_disable_1", Direction: registerdoc.DirectionWrite, Description: "Rack disable bitmask, racks 1-16"},
+					{Address: RackDisableRegister2, Name: "rack_disable_2", Direction: registerdoc.DirectionWrite, Description: "Rack disable bitmask, racks 17-32"},
+					{Address: RackDisableRegister3, Name: "rack_disable_3", Direction: registerdoc.DirectionWrite, Description: "Rack disable bitmask, racks 33-48"},
+					{Address: StepChargeControlRegister, Name: "step_charge_control", Direction: registerdoc.DirectionWrite, Description: "0 = disable, 1 = enable"},
+				},
+			},
+		},
+	}
+}
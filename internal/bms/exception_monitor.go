@@ -0,0 +1,78 @@
+package bms
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/pkg/modbus"
+)
+
+// ExceptionSpikeAlarmCode identifies the alarm raised when the Modbus
+// exception rate of a BMS spikes
+const ExceptionSpikeAlarmCode = 9005
+
+// Modbus exception spike supervision; a spike usually means a firmware
+// change or a misconfigured register map rather than a transport problem
+const (
+	exceptionCheckInterval  = time.Minute
+	exceptionSpikeThreshold = 30 // Exceptions per rolling rate window
+)
+
+// exceptionMonitorLoop watches the Modbus exception rate of both BMS
+// connections and raises a spike alarm with edge triggering
+func (s *Service) exceptionMonitorLoop() {
+	ticker := time.NewTicker(exceptionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkExceptionRate()
+		}
+	}
+}
+
+// checkExceptionRate compares the rolling exception rate against the spike
+// threshold and submits the alarm on state changes
+func (s *Service) checkExceptionRate() {
+	rate := s.systemClient.ExceptionRate() + s.cellClient.ExceptionRate()
+	spiking := rate >= exceptionSpikeThreshold
+
+	s.mutex.Lock()
+	changed := spiking != s.exceptionSpike
+	s.exceptionSpike = spiking
+	s.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: fmt.Sprintf("BMS_%d", s.config.ID),
+		AlarmCode: ExceptionSpikeAlarmCode,
+		Message:   fmt.Sprintf("Modbus exception rate spike: %d exceptions in the last 5m", rate),
+		Severity:  "MEDIUM",
+		Active:    spiking,
+	})
+
+	if spiking {
+		s.log.Warn("Modbus exception rate spike",
+			zap.Int("exceptions_in_window", rate),
+			zap.Int("threshold", exceptionSpikeThreshold))
+	} else {
+		s.log.Info("Modbus exception rate back to normal",
+			zap.Int("exceptions_in_window", rate))
+	}
+}
+
+// GetModbusExceptions returns the accumulated Modbus exception statistics of
+// both BMS connections for the diagnostics endpoint
+func (s *Service) GetModbusExceptions() []modbus.ExceptionStat {
+	return append(s.systemClient.GetExceptionStats(), s.cellClient.GetExceptionStats()...)
+}
@@ -0,0 +1,122 @@
+package bms
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// Heartbeat supervision defaults used when no thresholds are configured
+const (
+	DefaultHeartbeatStaleTimeout = 15 * time.Second
+	DefaultHeartbeatLossTimeout  = 60 * time.Second
+)
+
+// HeartbeatStallAlarmCode is a synthetic alarm code raised by the EMS itself
+// when the device heartbeat counter stops progressing. It is outside the
+// register-based alarm code range so it cannot collide with device alarms.
+const HeartbeatStallAlarmCode = 9001
+
+// heartbeatSupervisionLoop verifies that the BMS heartbeat counter keeps
+// progressing. The EMS already writes its own heartbeat to the device; this
+// loop covers the opposite direction and raises an alarm when the device
+// counter freezes. A prolonged freeze is reported as heartbeat loss which the
+// control logic uses as a safe-state trigger.
+func (s *Service) heartbeatSupervisionLoop() {
+	staleTimeout := s.config.HeartbeatStaleTimeout
+	if staleTimeout <= 0 {
+		staleTimeout = DefaultHeartbeatStaleTimeout
+	}
+	lossTimeout := s.config.HeartbeatLossTimeout
+	if lossTimeout <= 0 {
+		lossTimeout = DefaultHeartbeatLossTimeout
+	}
+
+	s.mutex.Lock()
+	s.lastHeartbeatProgress = time.Now()
+	s.mutex.Unlock()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.superviseHeartbeat(staleTimeout, lossTimeout)
+		}
+	}
+}
+
+// superviseHeartbeat samples the device heartbeat counter and updates the
+// stale/loss state
+func (s *Service) superviseHeartbeat(staleTimeout, lossTimeout time.Duration) {
+	now := time.Now()
+	connected := s.systemClient.IsConnected()
+
+	s.mutex.Lock()
+	counter := s.lastBMSStatusData.Heartbeat
+	if counter != s.lastHeartbeatValue {
+		s.lastHeartbeatValue = counter
+		s.lastHeartbeatProgress = now
+	}
+	sinceProgress := now.Sub(s.lastHeartbeatProgress)
+
+	wasStale := s.heartbeatStale
+	wasLost := s.heartbeatLost
+	s.heartbeatStale = connected && sinceProgress >= staleTimeout
+	s.heartbeatLost = connected && sinceProgress >= lossTimeout
+	stale := s.heartbeatStale
+	lost := s.heartbeatLost
+	s.mutex.Unlock()
+
+	if stale != wasStale {
+		s.submitHeartbeatAlarm(stale)
+
+		if stale {
+			s.log.Warn("BMS heartbeat counter frozen",
+				zap.Uint16("counter", counter),
+				zap.Duration("since_progress", sinceProgress))
+		} else {
+			s.log.Info("BMS heartbeat counter progressing again",
+				zap.Uint16("counter", counter))
+		}
+	}
+
+	if lost && !wasLost {
+		s.log.Error("BMS heartbeat lost - safe-state stop will be triggered",
+			zap.Duration("since_progress", sinceProgress),
+			zap.Duration("loss_timeout", lossTimeout))
+	}
+}
+
+// submitHeartbeatAlarm raises or clears the heartbeat stall alarm
+func (s *Service) submitHeartbeatAlarm(active bool) {
+	s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: fmt.Sprintf("BMS_%d", s.config.ID),
+		AlarmCode: HeartbeatStallAlarmCode,
+		Message:   "BMS heartbeat counter not progressing",
+		Severity:  "HIGH",
+		Active:    active,
+	})
+}
+
+// IsHeartbeatStale returns whether the device heartbeat counter is stale
+func (s *Service) IsHeartbeatStale() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.heartbeatStale
+}
+
+// IsHeartbeatLost returns whether the device heartbeat has been lost long
+// enough to require a safe-state reaction
+func (s *Service) IsHeartbeatLost() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.heartbeatLost
+}
@@ -9,6 +9,7 @@ import (
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
 )
 
 // Module provides BMS management functionality to the Fx application
@@ -21,10 +22,12 @@ var Module = fx.Module("bms",
 func ProvideManager(
 	cfg *config.Config,
 	influxDB *database.InfluxDB,
+	postgreSQL *database.PostgreSQL,
 	alarmManager *alarm.Manager,
+	telemetryManager *telemetry.Manager,
 	logger *zap.Logger,
 ) *Manager {
-	return NewManager(cfg.BMS, influxDB, alarmManager, logger)
+	return NewManager(cfg.BMS, influxDB, postgreSQL, alarmManager, telemetryManager, logger)
 }
 
 // RegisterLifecycle registers lifecycle hooks for the BMS manager
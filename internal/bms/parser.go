@@ -1,6 +1,7 @@
 package bms
 
 import (
+	"fmt"
 	"time"
 
 	"powerkonnekt/ems/internal/database"
@@ -8,12 +9,9 @@ import (
 )
 
 // parseBMSStatusData converts raw MODBUS data to BMSStatusData structure
-func parseBMSStatusData(data []byte, id int) database.BMSStatusData {
+func parseBMSStatusData(data []byte, id int) (database.BMSStatusData, error) {
 	if len(data) < BMSStatusDataLength*2 {
-		return database.BMSStatusData{
-			Timestamp: time.Now(),
-			ID:        id,
-		}
+		return database.BMSStatusData{}, fmt.Errorf("status data too short: got %d bytes, want %d", len(data), BMSStatusDataLength*2)
 	}
 
 	return database.BMSStatusData{
@@ -25,16 +23,13 @@ func parseBMSStatusData(data []byte, id int) database.BMSStatusData {
 		ConnectedRacks:   utils.FromBytes[uint16](data[8:10]),  // 772 - Connected racks
 		TotalRacks:       utils.FromBytes[uint16](data[10:12]), // 773 - Total racks
 		StepChargeStatus: utils.FromBytes[uint16](data[12:14]), // 774 - Step charge status
-	}
+	}, nil
 }
 
 // parseBMSData converts raw MODBUS data to BMSData structure
-func parseBMSData(data []byte, id int) database.BMSData {
+func parseBMSData(data []byte, id int) (database.BMSData, error) {
 	if len(data) < BMSDataLength*2 {
-		return database.BMSData{
-			Timestamp: time.Now(),
-			ID:        id,
-		}
+		return database.BMSData{}, fmt.Errorf("bms data too short: got %d bytes, want %d", len(data), BMSDataLength*2)
 	}
 
 	return database.BMSData{
@@ -62,17 +57,13 @@ func parseBMSData(data []byte, id int) database.BMSData {
 		InsulationDetectionStatus: utils.FromBytes[uint16](data[42:44]),                              // 53 - Insulation detection status
 		InsulationResistancePos:   utils.FromBytes[uint16](data[44:46]),                              // 54 - Insulation resistance positive (kΩ)
 		InsulationResistanceNeg:   utils.FromBytes[uint16](data[46:48]),                              // 55 - Insulation resistance negative (kΩ)
-	}
+	}, nil
 }
 
 // parseBMSRackStatusData converts raw MODBUS data to BMSRackStatusData structure
-func parseBMSRackStatusData(data []byte, id int, rackNo uint8) database.BMSRackStatusData {
+func parseBMSRackStatusData(data []byte, id int, rackNo uint8) (database.BMSRackStatusData, error) {
 	if len(data) < BMSRackStatusDataLength*2 {
-		return database.BMSRackStatusData{
-			Timestamp: time.Now(),
-			ID:        id,
-			Number:    rackNo,
-		}
+		return database.BMSRackStatusData{}, fmt.Errorf("rack status data too short: got %d bytes, want %d", len(data), BMSRackStatusDataLength*2)
 	}
 
 	return database.BMSRackStatusData{
@@ -85,17 +76,13 @@ func parseBMSRackStatusData(data []byte, id int, rackNo uint8) database.BMSRackS
 		HVStatus:             utils.FromBytes[uint16](data[6:8]),   // 1043 - High voltage status
 		SOCMaintenanceStatus: utils.FromBytes[uint16](data[8:10]),  // 1044 - SOC maintenance status
 		StepChargeStatus:     utils.FromBytes[uint16](data[10:12]), // 1045 - Step charge status
-	}
+	}, nil
 }
 
 // parseBMSRackData converts raw MODBUS data to BMSRackData structure
-func parseBMSRackData(data []byte, id int, rackNo uint8) database.BMSRackData {
+func parseBMSRackData(data []byte, id int, rackNo uint8) (database.BMSRackData, error) {
 	if len(data) < BMSRackDataLength*2 {
-		return database.BMSRackData{
-			Timestamp: time.Now(),
-			ID:        id,
-			Number:    rackNo,
-		}
+		return database.BMSRackData{}, fmt.Errorf("rack data too short: got %d bytes, want %d", len(data), BMSRackDataLength*2)
 	}
 
 	return database.BMSRackData{
@@ -132,13 +119,13 @@ func parseBMSRackData(data []byte, id int, rackNo uint8) database.BMSRackData {
 		TotalChargeEnergy:    utils.Scale(utils.FromBytesCDAB[uint32](data[104:108]), float32(0.1)), // 1108-1109 - Total charge energy (kWh)
 		TotalDischargeEnergy: utils.Scale(utils.FromBytesCDAB[uint32](data[108:112]), float32(0.1)), // 1110-1111 - Total discharge energy (kWh)
 		CycleCount:           utils.FromBytes[uint16](data[120:122]),                                // 1116 - Cycle count
-	}
+	}, nil
 }
 
 // parseCellVoltages converts raw MODBUS data to cell voltage array
-func parseCellVoltages(data []byte, id int, rackNo uint8, startCellNo uint16) []database.BMSCellVoltageData {
-	if len(data) < 2 {
-		return nil
+func parseCellVoltages(data []byte, id int, rackNo uint8, startCellNo uint16) ([]database.BMSCellVoltageData, error) {
+	if len(data) < 2 || len(data)%2 != 0 {
+		return nil, fmt.Errorf("cell voltage data has invalid length: %d bytes", len(data))
 	}
 
 	cellCount := len(data) / 2
@@ -160,11 +147,15 @@ func parseCellVoltages(data []byte, id int, rackNo uint8, startCellNo uint16) []
 		}
 	}
 
-	return cells
+	return cells, nil
 }
 
 // parseCellTemperatures converts raw MODBUS data to cell temperature array
-func parseCellTemperatures(data []byte, id int, rackNo uint8, startSensorNo uint16) []database.BMSCellTemperatureData {
+func parseCellTemperatures(data []byte, id int, rackNo uint8, startSensorNo uint16) ([]database.BMSCellTemperatureData, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("cell temperature data is empty")
+	}
+
 	sensors := make([]database.BMSCellTemperatureData, len(data))
 	timestamp := time.Now()
 
@@ -183,5 +174,5 @@ func parseCellTemperatures(data []byte, id int, rackNo uint8, startSensorNo uint
 		}
 	}
 
-	return sensors
+	return sensors, nil
 }
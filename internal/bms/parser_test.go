@@ -0,0 +1,97 @@
+package bms
+
+import (
+	"testing"
+)
+
+// FuzzParseBMSStatusData ensures the parser never panics on malformed frames
+// and rejects short input with an error
+func FuzzParseBMSStatusData(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(make([]byte, BMSStatusDataLength*2-1))
+	f.Add(make([]byte, BMSStatusDataLength*2))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := parseBMSStatusData(data, 1)
+		if (len(data) < BMSStatusDataLength*2) != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+	})
+}
+
+// FuzzParseBMSData ensures the parser never panics on malformed frames
+// and rejects short input with an error
+func FuzzParseBMSData(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(make([]byte, BMSDataLength*2-1))
+	f.Add(make([]byte, BMSDataLength*2))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := parseBMSData(data, 1)
+		if (len(data) < BMSDataLength*2) != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+	})
+}
+
+// FuzzParseBMSRackStatusData ensures the parser never panics on malformed
+// frames and rejects short input with an error
+func FuzzParseBMSRackStatusData(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(make([]byte, BMSRackStatusDataLength*2-1))
+	f.Add(make([]byte, BMSRackStatusDataLength*2))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := parseBMSRackStatusData(data, 1, 1)
+		if (len(data) < BMSRackStatusDataLength*2) != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+	})
+}
+
+// FuzzParseBMSRackData ensures the parser never panics on malformed frames
+// and rejects short input with an error
+func FuzzParseBMSRackData(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(make([]byte, BMSRackDataLength*2-1))
+	f.Add(make([]byte, BMSRackDataLength*2))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := parseBMSRackData(data, 1, 1)
+		if (len(data) < BMSRackDataLength*2) != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+	})
+}
+
+// FuzzParseCellVoltages ensures the parser never panics and rejects empty or
+// odd-length frames with an error
+func FuzzParseCellVoltages(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x0c})
+	f.Add([]byte{0x0c, 0xe4})
+	f.Add(make([]byte, 250))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		cells, err := parseCellVoltages(data, 1, 1, 1)
+		malformed := len(data) < 2 || len(data)%2 != 0
+		if malformed != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+		if err == nil && len(cells) != len(data)/2 {
+			t.Errorf("got %d cells for %d-byte frame", len(cells), len(data))
+		}
+	})
+}
+
+// FuzzParseCellTemperatures ensures the parser never panics and rejects
+// empty frames with an error
+func FuzzParseCellTemperatures(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x4b})
+	f.Add(make([]byte, 125))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sensors, err := parseCellTemperatures(data, 1, 1, 1)
+		if (len(data) == 0) != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+		if err == nil && len(sensors) != len(data) {
+			t.Errorf("got %d sensors for %d-byte frame", len(sensors), len(data))
+		}
+	})
+}
@@ -8,9 +8,15 @@ import (
 	"powerkonnekt/ems/internal/database"
 )
 
+// persistInterval returns the configured persist interval scaled by the
+// active telemetry profile
+func (s *Service) persistInterval() time.Duration {
+	return s.config.PersistInterval * time.Duration(s.telemetry.Current().PersistScale)
+}
+
 // persistenceLoop periodically writes data to InfluxDB
 func (s *Service) persistenceLoop() {
-	interval := s.config.PersistInterval
+	interval := s.persistInterval()
 
 	// Calculate first aligned time and create timer
 	nextTick := time.Now().Truncate(interval).Add(interval)
@@ -31,15 +37,21 @@ func (s *Service) persistenceLoop() {
 					zap.Duration("interval", interval))
 			}
 
-			// Calculate next aligned time and reset timer
+			// Calculate next aligned time and reset timer, picking up
+			// telemetry profile changes
+			interval = s.persistInterval()
 			nextTick = time.Now().Truncate(interval).Add(interval)
 			timer.Reset(time.Until(nextTick))
 		}
 	}
 }
 
-// persistData writes all data to InfluxDB
+// persistData writes all data to InfluxDB; per-rack status and the
+// high-volume cell measurements are skipped when the telemetry profile
+// excludes them
 func (s *Service) persistData() {
+	profile := s.telemetry.Current()
+
 	s.mutex.RLock()
 	bmsData := s.lastBMSData
 	bmsStatusData := s.lastBMSStatusData
@@ -69,7 +81,7 @@ func (s *Service) persistData() {
 	}
 
 	// Save BMS status data to InfluxDB
-	if !bmsStatusData.Timestamp.IsZero() {
+	if profile.IncludeDetail && !bmsStatusData.Timestamp.IsZero() {
 		if err := s.influxDB.WriteBMSStatusData(bmsStatusData); err != nil {
 			s.log.Error("Failed to save BMS status data to InfluxDB", zap.Error(err))
 		}
@@ -87,6 +99,18 @@ func (s *Service) persistData() {
 	}
 
 	// Save rack status data to InfluxDB
+	if profile.IncludeDetail {
+		s.persistRackStatusData(bmsRackStatusData)
+	}
+
+	// Save cell data to InfluxDB
+	if profile.IncludeCellData {
+		s.persistCellData(cellVoltages, cellTemperatures)
+	}
+}
+
+// persistRackStatusData writes the per-rack status measurements
+func (s *Service) persistRackStatusData(bmsRackStatusData []database.BMSRackStatusData) {
 	for _, rackStatus := range bmsRackStatusData {
 		if !rackStatus.Timestamp.IsZero() {
 			if err := s.influxDB.WriteBMSRackStatusData(rackStatus); err != nil {
@@ -96,8 +120,10 @@ func (s *Service) persistData() {
 			}
 		}
 	}
+}
 
-	// Save cell voltage data to InfluxDB
+// persistCellData writes the per-cell voltage and temperature measurements
+func (s *Service) persistCellData(cellVoltages [][]database.BMSCellVoltageData, cellTemperatures [][]database.BMSCellTemperatureData) {
 	for rackNo, cells := range cellVoltages {
 		if len(cells) > 0 {
 			if err := s.influxDB.WriteBMSCellVoltageData(cells); err != nil {
@@ -5,10 +5,25 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"powerkonnekt/ems/pkg/modbus"
 )
 
 // systemDataPollLoop periodically reads system data from the BMS
 func (s *Service) systemDataPollLoop() {
+	s.systemClient.OnConnectionChange(func(connected bool) {
+		if connected {
+			s.log.Info("BMS connection established (system client)")
+		} else {
+			s.log.Warn("BMS connection lost, reconnecting in the background (system client)")
+		}
+	})
+	s.systemClient.StartAutoReconnect(s.ctx, modbus.ReconnectSettings{
+		InitialDelay: s.config.ReconnectDelay,
+		MaxDelay:     s.config.ReconnectMaxDelay,
+		MaxRetries:   s.config.ReconnectMaxRetries,
+	})
+
 	if err := s.systemClient.Connect(s.ctx); err != nil {
 		s.log.Warn("Initial Modbus connection failed (system client)", zap.Error(err))
 	}
@@ -25,9 +40,8 @@ func (s *Service) systemDataPollLoop() {
 		case <-s.ctx.Done():
 			return
 		case <-timer.C:
-			if !s.systemClient.IsConnected() {
-				s.handleSystemClientConnectionError()
-			} else {
+			finish := s.pollWatch.Begin()
+			if s.systemClient.IsConnected() {
 				startTime := time.Now()
 				if err := s.readSystemData(); err != nil {
 					s.log.Error("Error reading system data", zap.Error(err))
@@ -46,6 +60,7 @@ func (s *Service) systemDataPollLoop() {
 						zap.Duration("interval", interval))
 				}
 			}
+			finish()
 
 			// Calculate next aligned time and reset timer
 			nextTick = time.Now().Truncate(interval).Add(interval)
@@ -56,6 +71,23 @@ func (s *Service) systemDataPollLoop() {
 
 // cellDataPollLoop periodically reads cell data from the BMS
 func (s *Service) cellDataPollLoop() {
+	// The simulated device shares one client between system and cell data,
+	// which the system loop already supervises
+	if s.cellClient != s.systemClient {
+		s.cellClient.OnConnectionChange(func(connected bool) {
+			if connected {
+				s.log.Info("BMS connection established (cell client)")
+			} else {
+				s.log.Warn("BMS connection lost, reconnecting in the background (cell client)")
+			}
+		})
+		s.cellClient.StartAutoReconnect(s.ctx, modbus.ReconnectSettings{
+			InitialDelay: s.config.ReconnectDelay,
+			MaxDelay:     s.config.ReconnectMaxDelay,
+			MaxRetries:   s.config.ReconnectMaxRetries,
+		})
+	}
+
 	if err := s.cellClient.Connect(s.ctx); err != nil {
 		s.log.Warn("Initial Modbus connection failed (cell client)", zap.Error(err))
 	}
@@ -72,9 +104,8 @@ func (s *Service) cellDataPollLoop() {
 		case <-s.ctx.Done():
 			return
 		case <-timer.C:
-			if !s.cellClient.IsConnected() {
-				s.handleCellClientConnectionError()
-			} else {
+			finish := s.cellWatch.Begin()
+			if s.cellClient.IsConnected() {
 				startTime := time.Now()
 				if err := s.readCellDataForAllRacks(); err != nil {
 					s.log.Error("Error reading cell data", zap.Error(err))
@@ -93,6 +124,7 @@ func (s *Service) cellDataPollLoop() {
 						zap.Duration("interval", interval))
 				}
 			}
+			finish()
 
 			// Calculate next aligned time and reset timer
 			nextTick = time.Now().Truncate(interval).Add(interval)
@@ -101,66 +133,6 @@ func (s *Service) cellDataPollLoop() {
 	}
 }
 
-// handleSystemClientConnectionError attempts to reconnect to the BMS
-func (s *Service) handleSystemClientConnectionError() {
-	s.log.Warn("BMS connection lost, attempting reconnection (system client)")
-	s.systemClient.Disconnect()
-
-	reconnectAttempts := 0
-	timer := time.NewTimer(s.config.ReconnectDelay)
-	defer timer.Stop()
-
-	for !s.systemClient.IsConnected() {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-timer.C:
-			reconnectAttempts++
-			if err := s.systemClient.Connect(s.ctx); err != nil {
-				s.log.Error("Failed to reconnect to BMS (system client)",
-					zap.Error(err),
-					zap.Int("attempt", reconnectAttempts))
-				timer.Reset(s.config.ReconnectDelay)
-			} else {
-				s.log.Info("Successfully reconnected to BMS (system client)",
-					zap.Int("total_attempts", reconnectAttempts),
-					zap.Duration("total_downtime", time.Duration(reconnectAttempts)*s.config.ReconnectDelay))
-				return
-			}
-		}
-	}
-}
-
-// handleCellClientConnectionError attempts to reconnect to the BMS
-func (s *Service) handleCellClientConnectionError() {
-	s.log.Warn("BMS connection lost, attempting reconnection (cell client)")
-	s.cellClient.Disconnect()
-
-	reconnectAttempts := 0
-	timer := time.NewTimer(s.config.ReconnectDelay)
-	defer timer.Stop()
-
-	for !s.cellClient.IsConnected() {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-timer.C:
-			reconnectAttempts++
-			if err := s.cellClient.Connect(s.ctx); err != nil {
-				s.log.Error("Failed to reconnect to BMS (cell client)",
-					zap.Error(err),
-					zap.Int("attempt", reconnectAttempts))
-				timer.Reset(s.config.ReconnectDelay)
-			} else {
-				s.log.Info("Successfully reconnected to BMS (cell client)",
-					zap.Int("total_attempts", reconnectAttempts),
-					zap.Duration("total_downtime", time.Duration(reconnectAttempts)*s.config.ReconnectDelay))
-				return
-			}
-		}
-	}
-}
-
 // readSystemData reads system data
 func (s *Service) readSystemData() error {
 	// Read BMS status data
@@ -16,7 +16,10 @@ func (s *Service) readBMSStatusData() error {
 		return fmt.Errorf("failed to read registers: %w", err)
 	}
 
-	bmsStatusData := parseBMSStatusData(data, s.config.ID)
+	bmsStatusData, err := parseBMSStatusData(data, s.config.ID)
+	if err != nil {
+		return fmt.Errorf("failed to parse status data: %w", err)
+	}
 
 	s.mutex.Lock()
 	s.lastBMSStatusData = bmsStatusData
@@ -32,12 +35,23 @@ func (s *Service) readBMSData() error {
 		return fmt.Errorf("failed to read registers: %w", err)
 	}
 
-	bmsData := parseBMSData(data, s.config.ID)
+	bmsData, err := parseBMSData(data, s.config.ID)
+	if err != nil {
+		return fmt.Errorf("failed to parse BMS data: %w", err)
+	}
 
 	s.mutex.Lock()
 	s.lastBMSData = bmsData
 	s.mutex.Unlock()
 
+	// Feed the rolling trend statistics of the key system fields
+	s.trends.Observe("soc", bmsData.SOC)
+	s.trends.Observe("power", float32(bmsData.Power))
+	s.trends.Observe("max_cell_voltage", bmsData.MaxCellVoltage)
+	s.trends.Observe("min_cell_voltage", bmsData.MinCellVoltage)
+	s.trends.Observe("max_cell_temperature", float32(bmsData.MaxCellTemperature))
+	s.trends.Observe("min_cell_temperature", float32(bmsData.MinCellTemperature))
+
 	return nil
 }
 
@@ -50,7 +64,10 @@ func (s *Service) readBMSRackStatusData(rackNo uint8) error {
 		return fmt.Errorf("failed to read registers: %w", err)
 	}
 
-	bmsRackStatusData := parseBMSRackStatusData(data, s.config.ID, rackNo)
+	bmsRackStatusData, err := parseBMSRackStatusData(data, s.config.ID, rackNo)
+	if err != nil {
+		return fmt.Errorf("failed to parse rack status data: %w", err)
+	}
 
 	s.mutex.Lock()
 	s.lastBMSRackStatusData[rackNo-1] = bmsRackStatusData
@@ -68,12 +85,21 @@ func (s *Service) readBMSRackData(rackNo uint8) error {
 		return fmt.Errorf("failed to read registers: %w", err)
 	}
 
-	bmsRackData := parseBMSRackData(data, s.config.ID, rackNo)
+	bmsRackData, err := parseBMSRackData(data, s.config.ID, rackNo)
+	if err != nil {
+		return fmt.Errorf("failed to parse rack data: %w", err)
+	}
 
 	s.mutex.Lock()
 	s.lastBMSRackData[rackNo-1] = bmsRackData
 	s.mutex.Unlock()
 
+	// Track the energy throughput against the warranty limits
+	s.recordRackThroughput(rackNo, bmsRackData.Power, bmsRackData.Timestamp)
+
+	// Accumulate the SOC/temperature residency histogram
+	s.recordRackResidency(rackNo, bmsRackData.SOC, bmsRackData.AvgCellTemperature, bmsRackData.Timestamp)
+
 	return nil
 }
 
@@ -164,7 +190,10 @@ func (s *Service) readCellVoltages(rackNo uint8) error {
 		}
 
 		// Parse raw bytes into structured cell data with rack and module info
-		cells := parseCellVoltages(data, s.config.ID, rackNo, startRegister+1)
+		cells, err := parseCellVoltages(data, s.config.ID, rackNo, startRegister+1)
+		if err != nil {
+			return fmt.Errorf("failed to parse cell voltage chunk %d: %w", chunk, err)
+		}
 
 		// Add this chunk's cells to our collection
 		allCells = append(allCells, cells...)
@@ -222,7 +251,10 @@ func (s *Service) readCellTemperatures(rackNo uint8) error {
 
 		// Parse raw bytes into structured sensor data with rack and module info
 		// Each register contains 2 sensors, so first sensor number is (startRegister * 2) + 1
-		sensors := parseCellTemperatures(data, s.config.ID, rackNo, (startRegister*2)+1)
+		sensors, err := parseCellTemperatures(data, s.config.ID, rackNo, (startRegister*2)+1)
+		if err != nil {
+			return fmt.Errorf("failed to parse cell temperature chunk %d: %w", chunk, err)
+		}
 
 		// Add this chunk's sensors to our collection
 		allSensors = append(allSensors, sensors...)
@@ -0,0 +1,232 @@
+package bms
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// Binning of the SOC/temperature residency histograms. SOC covers 0-100 % in
+// 5 % bins; temperature covers -20 °C to +80 °C in 5 °C bins, with samples
+// outside the range clamped into the edge bins.
+const (
+	residencySOCBinWidth  = 5.0 // % SOC per bin
+	residencySOCBins      = 20
+	residencyTempBinWidth = 5.0 // °C per bin
+	residencyTempMinC     = -20.0
+	residencyTempBins     = 20
+
+	// residencyPersistInterval is how often the accumulated histograms are
+	// written to PostgreSQL
+	residencyPersistInterval = 5 * time.Minute
+)
+
+// RackResidency is the accumulated 2-D SOC/temperature residency histogram
+// of one rack, feeding degradation models and warranty reports
+type RackResidency struct {
+	RackNo       uint8       `json:"rack_no"`
+	SOCBinWidth  float32     `json:"soc_bin_width_percent"`
+	TempBinWidth float32     `json:"temperature_bin_width_celsius"`
+	TempMinC     float32     `json:"temperature_min_celsius"`
+	Since        time.Time   `json:"since"`
+	Hours        [][]float64 `json:"hours"` // [SOC bin][temperature bin] residency hours
+}
+
+// newResidencyBins allocates an empty SOC/temperature histogram
+func newResidencyBins() [][]float64 {
+	bins := make([][]float64, residencySOCBins)
+	for i := range bins {
+		bins[i] = make([]float64, residencyTempBins)
+	}
+	return bins
+}
+
+// newResidencyHours allocates empty histograms for every rack
+func newResidencyHours(rackCount int) [][][]float64 {
+	hours := make([][][]float64, rackCount)
+	for i := range hours {
+		hours[i] = newResidencyBins()
+	}
+	return hours
+}
+
+// recordRackResidency accumulates the time since the previous sample into the
+// SOC/temperature bin the rack currently occupies
+func (s *Service) recordRackResidency(rackNo uint8, soc float32, avgCellTemp int16, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	index := int(rackNo) - 1
+	if index < 0 || index >= len(s.rackResidencyHours) {
+		return
+	}
+
+	if s.rackResidencySince[index].IsZero() {
+		s.rackResidencySince[index] = now
+	}
+
+	last := s.rackResidencySample[index]
+	s.rackResidencySample[index] = now
+	if last.IsZero() {
+		return
+	}
+
+	// Clip the step so data gaps do not integrate phantom residency
+	dt := now.Sub(last)
+	if dt > 2*s.config.PollInterval {
+		dt = 2 * s.config.PollInterval
+	}
+
+	socBin := clampBin(int(soc/residencySOCBinWidth), residencySOCBins)
+	tempBin := clampBin(int((float64(avgCellTemp)-residencyTempMinC)/residencyTempBinWidth), residencyTempBins)
+	s.rackResidencyHours[index][socBin][tempBin] += dt.Hours()
+}
+
+// clampBin clamps a bin index into [0, bins)
+func clampBin(bin, bins int) int {
+	if bin < 0 {
+		return 0
+	}
+	if bin >= bins {
+		return bins - 1
+	}
+	return bin
+}
+
+// GetRackResidency returns a snapshot of the residency histograms of every
+// rack; reading does not reset the counters
+func (s *Service) GetRackResidency() []RackResidency {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.snapshotResidencyLocked()
+}
+
+// ResetRackResidency atomically snapshots and zeroes the residency histograms
+// of every rack, so a collector reading and resetting in one call cannot lose
+// samples accumulated in between. The returned snapshot is the state before
+// the reset; the zeroed state is persisted immediately so the reset survives
+// a restart.
+func (s *Service) ResetRackResidency() []RackResidency {
+	s.mutex.Lock()
+	snapshot := s.snapshotResidencyLocked()
+	now := time.Now()
+	for i := range s.rackResidencyHours {
+		s.rackResidencyHours[i] = newResidencyBins()
+		s.rackResidencySince[i] = now
+	}
+	s.mutex.Unlock()
+
+	s.persistResidency()
+	return snapshot
+}
+
+// snapshotResidencyLocked copies the residency histograms; the caller must
+// hold the mutex
+func (s *Service) snapshotResidencyLocked() []RackResidency {
+	racks := make([]RackResidency, len(s.rackResidencyHours))
+	for i := range racks {
+		hours := newResidencyBins()
+		for socBin := range hours {
+			copy(hours[socBin], s.rackResidencyHours[i][socBin])
+		}
+		racks[i] = RackResidency{
+			RackNo:       uint8(i + 1),
+			SOCBinWidth:  residencySOCBinWidth,
+			TempBinWidth: residencyTempBinWidth,
+			TempMinC:     residencyTempMinC,
+			Since:        s.rackResidencySince[i],
+			Hours:        hours,
+		}
+	}
+	return racks
+}
+
+// restoreResidency loads the persisted residency histograms so the counters
+// are not lost across a restart
+func (s *Service) restoreResidency() {
+	if s.postgreSQL == nil {
+		return
+	}
+
+	records, err := s.postgreSQL.GetRackResidencyRecords(s.config.ID)
+	if err != nil {
+		s.log.Error("Failed to restore rack residency histograms", zap.Error(err))
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, record := range records {
+		index := int(record.RackNo) - 1
+		if index < 0 || index >= len(s.rackResidencyHours) {
+			continue
+		}
+
+		var hours [][]float64
+		if err := json.Unmarshal([]byte(record.Histogram), &hours); err != nil {
+			s.log.Error("Failed to decode persisted rack residency histogram",
+				zap.Error(err),
+				zap.Uint8("rack_no", record.RackNo))
+			continue
+		}
+		if len(hours) != residencySOCBins {
+			continue // Binning changed, start the rack over
+		}
+
+		for socBin := range s.rackResidencyHours[index] {
+			copy(s.rackResidencyHours[index][socBin], hours[socBin])
+		}
+		s.rackResidencySince[index] = record.Since
+	}
+}
+
+// persistResidency writes the residency histograms of every rack to
+// PostgreSQL
+func (s *Service) persistResidency() {
+	if s.postgreSQL == nil {
+		return
+	}
+
+	for _, rack := range s.GetRackResidency() {
+		histogram, err := json.Marshal(rack.Hours)
+		if err != nil {
+			s.log.Error("Failed to encode rack residency histogram",
+				zap.Error(err),
+				zap.Uint8("rack_no", rack.RackNo))
+			continue
+		}
+
+		if err := s.postgreSQL.SaveRackResidency(database.RackResidencyRecord{
+			ID:        uint(s.config.ID*100 + int(rack.RackNo)),
+			BMSID:     s.config.ID,
+			RackNo:    rack.RackNo,
+			Histogram: string(histogram),
+			Since:     rack.Since,
+		}); err != nil {
+			s.log.Error("Failed to persist rack residency histogram",
+				zap.Error(err),
+				zap.Uint8("rack_no", rack.RackNo))
+		}
+	}
+}
+
+// residencyPersistLoop periodically persists the residency histograms, with a
+// final write on shutdown
+func (s *Service) residencyPersistLoop() {
+	ticker := time.NewTicker(residencyPersistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.persistResidency()
+			return
+		case <-ticker.C:
+			s.persistResidency()
+		}
+	}
+}
@@ -2,23 +2,36 @@ package bms
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
+	"powerkonnekt/ems/pkg/loopwatch"
 	"powerkonnekt/ems/pkg/modbus"
+	"powerkonnekt/ems/pkg/rolling"
 )
 
+// trendSampleCapacity bounds the samples kept per trend field; it covers the
+// longest rolling window at the 1 s poll rate
+const trendSampleCapacity = 1024
+
 // Service represents the BMS service
 type Service struct {
 	config       config.BMSConfig
 	influxDB     *database.InfluxDB
+	postgreSQL   *database.PostgreSQL
 	alarmManager *alarm.Manager
+	telemetry    *telemetry.Manager
 	systemClient *modbus.Client
 	cellClient   *modbus.Client
+	pollWatch    *loopwatch.Loop
+	cellWatch    *loopwatch.Loop
 	ctx          context.Context
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
@@ -27,6 +40,8 @@ type Service struct {
 	systemDataUpdateChan chan struct{}
 	cellDataUpdateChan   chan struct{}
 
+	trends *rolling.Tracker // Rolling statistics of the key system fields
+
 	mutex                 sync.RWMutex
 	lastBMSData           database.BMSData
 	lastBMSStatusData     database.BMSStatusData
@@ -37,12 +52,43 @@ type Service struct {
 	commandState          database.BMSCommandState
 	previousAlarmStates   map[string]bool
 	heartbeatCount        uint16
+	lastHeartbeatValue    uint16
+	lastHeartbeatProgress time.Time
+	heartbeatStale        bool
+	heartbeatLost         bool
+	exceptionSpike        bool
+
+	// Per-rack energy throughput tracking against warranty limits
+	throughputDay        time.Time
+	throughputYear       int
+	rackDailyKWh         []float64
+	rackAnnualKWh        []float64
+	rackThroughputSample []time.Time
+
+	// Per-rack SOC/temperature residency histograms for degradation models
+	rackResidencyHours  [][][]float64
+	rackResidencySince  []time.Time
+	rackResidencySample []time.Time
 }
 
 // NewService creates a new BMS service
-func NewService(cfg config.BMSConfig, influxDB *database.InfluxDB, alarmManager *alarm.Manager, logger *zap.Logger) *Service {
-	systemClient := modbus.NewClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout)
-	cellClient := modbus.NewClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout)
+func NewService(cfg config.BMSConfig, influxDB *database.InfluxDB, postgreSQL *database.PostgreSQL, alarmManager *alarm.Manager, telemetryManager *telemetry.Manager, logger *zap.Logger) *Service {
+	var systemClient, cellClient *modbus.Client
+	if cfg.Simulate {
+		// Both clients share one register bank so system and cell data
+		// come from the same simulated device
+		systemClient = modbus.NewSimulatedClient(cfg.SlaveID, cfg.Timeout)
+		cellClient = systemClient
+	} else {
+		tlsSettings := modbus.TLSSettings{
+			Enabled:  cfg.TLS.Enabled,
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.CAFile,
+		}
+		systemClient = modbus.NewSecureClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout, cfg.BindAddress, tlsSettings)
+		cellClient = modbus.NewSecureClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout, cfg.BindAddress, tlsSettings)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -55,17 +101,30 @@ func NewService(cfg config.BMSConfig, influxDB *database.InfluxDB, alarmManager
 	)
 
 	return &Service{
-		config:                cfg,
-		influxDB:              influxDB,
-		alarmManager:          alarmManager,
-		systemClient:          systemClient,
-		cellClient:            cellClient,
-		ctx:                   ctx,
-		cancel:                cancel,
-		log:                   serviceLogger,
-		systemDataUpdateChan:  make(chan struct{}, 1),
-		cellDataUpdateChan:    make(chan struct{}, 1),
+		config:               cfg,
+		influxDB:             influxDB,
+		postgreSQL:           postgreSQL,
+		alarmManager:         alarmManager,
+		telemetry:            telemetryManager,
+		systemClient:         systemClient,
+		cellClient:           cellClient,
+		pollWatch:            loopwatch.NewLoop(fmt.Sprintf("bms_%d_poll", cfg.ID), cfg.PollInterval),
+		cellWatch:            loopwatch.NewLoop(fmt.Sprintf("bms_%d_cell_poll", cfg.ID), cfg.CellDataInterval),
+		ctx:                  ctx,
+		cancel:               cancel,
+		log:                  serviceLogger,
+		systemDataUpdateChan: make(chan struct{}, 1),
+		cellDataUpdateChan:   make(chan struct{}, 1),
+		trends: rolling.NewTracker(fmt.Sprintf("bms_%d_trend", cfg.ID), trendSampleCapacity,
+			"soc", "power", "max_cell_voltage", "min_cell_voltage",
+			"max_cell_temperature", "min_cell_temperature"),
 		lastBMSRackData:       make([]database.BMSRackData, cfg.RackCount),
+		rackDailyKWh:          make([]float64, cfg.RackCount),
+		rackAnnualKWh:         make([]float64, cfg.RackCount),
+		rackThroughputSample:  make([]time.Time, cfg.RackCount),
+		rackResidencyHours:    newResidencyHours(cfg.RackCount),
+		rackResidencySince:    make([]time.Time, cfg.RackCount),
+		rackResidencySample:   make([]time.Time, cfg.RackCount),
 		lastBMSRackStatusData: make([]database.BMSRackStatusData, cfg.RackCount),
 		lastCellVoltages:      make([][]database.BMSCellVoltageData, cfg.RackCount),
 		lastCellTemperatures:  make([][]database.BMSCellTemperatureData, cfg.RackCount),
@@ -75,12 +134,20 @@ func NewService(cfg config.BMSConfig, influxDB *database.InfluxDB, alarmManager
 
 // Start starts the BMS service
 func (s *Service) Start() error {
+	s.restoreResidency()
+
+	if s.config.Simulate {
+		s.wg.Go(s.simulateLoop)
+	}
 	s.wg.Go(s.systemDataPollLoop)
 	if s.config.EnableCellData {
 		s.wg.Go(s.cellDataPollLoop)
 	}
 	s.wg.Go(s.heartbeatLoop)
+	s.wg.Go(s.heartbeatSupervisionLoop)
+	s.wg.Go(s.exceptionMonitorLoop)
 	s.wg.Go(s.persistenceLoop)
+	s.wg.Go(s.residencyPersistLoop)
 
 	s.log.Info("BMS service started",
 		zap.Int("rack_count", s.config.RackCount),
@@ -123,6 +190,12 @@ func (s *Service) GetCellDataUpdateChannel() <-chan struct{} {
 	return s.cellDataUpdateChan
 }
 
+// GetTrendStats returns the rolling min/max/average statistics of the key
+// system fields per reporting window
+func (s *Service) GetTrendStats() map[string]map[string]rolling.Stats {
+	return s.trends.Stats()
+}
+
 // GetLatestBMSData returns the latest BMS data
 func (s *Service) GetLatestBMSData() database.BMSData {
 	s.mutex.RLock()
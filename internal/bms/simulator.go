@@ -0,0 +1,201 @@
+package bms
+
+import (
+	"math"
+	"time"
+)
+
+// Characteristics of the simulated BMS container
+const (
+	simUsableCapacityKWh = 3200 // Usable energy of the container
+	simRatedPowerKW      = 1600 // Charge/discharge power limit of the container
+	simMinSOC            = 5.0  // Bounds of the autonomous cycle (%)
+	simMaxSOC            = 95.0
+	simCyclePeriod       = 40 * time.Minute // Period of the charge/discharge cycle
+	simStepInterval      = time.Second
+)
+
+// bmsSimulator holds the state of the built-in battery model: the SOC is
+// integrated from a gentle sinusoidal charge/discharge cycle and everything
+// else (voltages, currents, temperatures) is derived from it
+type bmsSimulator struct {
+	soc       float64
+	elapsed   time.Duration
+	heartbeat uint16
+}
+
+// simulateLoop drives the built-in BMS simulator against the in-memory
+// register bank of the simulated MODBUS client, so the regular poll loops,
+// parsers and heartbeat supervision run unchanged without hardware
+func (s *Service) simulateLoop() {
+	s.log.Info("BMS simulator active, serving the in-memory device model")
+
+	sim := &bmsSimulator{soc: 50}
+	sim.step(s, 0)
+
+	ticker := time.NewTicker(simStepInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			sim.step(s, now.Sub(last))
+			last = now
+		}
+	}
+}
+
+// step advances the battery model by dt and publishes the register image
+func (sim *bmsSimulator) step(s *Service, dt time.Duration) {
+	sim.elapsed += dt
+	sim.heartbeat++
+
+	// Positive power means discharge; the cycle stalls at the SOC bounds
+	phase := 2 * math.Pi * sim.elapsed.Seconds() / simCyclePeriod.Seconds()
+	power := simRatedPowerKW * 0.5 * math.Sin(phase)
+
+	sim.soc -= power * dt.Hours() / simUsableCapacityKWh * 100
+	if sim.soc <= simMinSOC {
+		sim.soc = simMinSOC
+		power = math.Min(power, 0)
+	}
+	if sim.soc >= simMaxSOC {
+		sim.soc = simMaxSOC
+		power = math.Max(power, 0)
+	}
+
+	state := uint16(StateNormal)
+	if sim.soc >= simMaxSOC {
+		state = StateFullCharge
+	} else if sim.soc <= simMinSOC {
+		state = StateFullDischarge
+	}
+
+	voltage := 1200 + 2.6*sim.soc // DC voltage rises with the SOC
+	current := power * 1000 / voltage
+	cellsInSeries := float64(s.config.ModulesPerRack * CellsPerModule)
+	avgCellVoltage := voltage / cellsInSeries
+	temperature := 25.0 + 3*math.Abs(power)/simRatedPowerKW
+
+	client := s.systemClient
+
+	// System status block
+	client.SimSetRegisters(BMSStatusDataStartAddr,
+		sim.heartbeat,
+		HVStatusPowerOnReady,
+		state,
+		0,
+		uint16(s.config.RackCount), // Connected racks
+		uint16(s.config.RackCount), // Total racks
+		StepChargeStatusDefault)
+
+	// System data block
+	client.SimSetRegisters(BMSDataStartAddr,
+		simScale10(voltage),
+		simOffset20000(current),
+		simScale10(sim.soc),
+		simScale10(98.0), // SOH
+		simScale1000(avgCellVoltage+0.02),
+		simScale1000(avgCellVoltage-0.02),
+		simScale1000(avgCellVoltage),
+		simOffset50(temperature+2),
+		simOffset50(temperature-2),
+		simOffset50(temperature),
+		simOffset20000(simRatedPowerKW*1000/voltage), // Max charge current
+		simOffset20000(simRatedPowerKW*1000/voltage), // Max discharge current
+		simOffset20000(simRatedPowerKW),              // Max charge power
+		simOffset20000(simRatedPowerKW),              // Max discharge power
+		simOffset20000(power),
+		0,
+		0,
+		uint16((100-sim.soc)/100*simUsableCapacityKWh), // Charge capacity
+		uint16(sim.soc/100*simUsableCapacityKWh),       // Discharge capacity
+		simScale10(1200+2.6*simMaxSOC),                 // Max charge voltage
+		simScale10(1200+2.6*simMinSOC),                 // Min discharge voltage
+		InsulationStatusEnable,
+		2000, // Insulation resistance positive (kΩ)
+		2000) // Insulation resistance negative (kΩ)
+
+	rackPower := power / float64(s.config.RackCount)
+	rackCurrent := current / float64(s.config.RackCount)
+
+	for rackNo := uint8(1); int(rackNo) <= s.config.RackCount; rackNo++ {
+		// Rack status block: relays closed, high voltage on
+		client.SimSetRegisters(GetRackStatusDataStartAddr(rackNo),
+			1, 1, 1,
+			HVStatusPowerOnReady,
+			0,
+			StepChargeStatusDefault)
+
+		// Rack data block; currents and powers carry a 0.1 scale here
+		client.SimSetRegisters(GetRackDataStartAddr(rackNo),
+			simScale10(voltage),
+			simScale10(voltage),
+			simOffset20000(rackCurrent*10),
+			simScale10(sim.soc),
+			simScale10(98.0),
+			simScale1000(avgCellVoltage+0.02),
+			simScale1000(avgCellVoltage-0.02),
+			simScale1000(avgCellVoltage),
+			simOffset50(temperature+2),
+			simOffset50(temperature-2),
+			simOffset50(temperature),
+			simOffset20000(simRatedPowerKW*1000/voltage*10/float64(s.config.RackCount)),
+			simOffset20000(simRatedPowerKW*1000/voltage*10/float64(s.config.RackCount)),
+			simOffset20000(simRatedPowerKW*10/float64(s.config.RackCount)),
+			simOffset20000(simRatedPowerKW*10/float64(s.config.RackCount)),
+			simOffset20000(rackPower*10))
+
+		if s.config.EnableCellData {
+			sim.writeCellData(s, rackNo, avgCellVoltage, temperature)
+		}
+	}
+}
+
+// writeCellData publishes per-cell voltages and temperatures with a small
+// deterministic spread so the min/max tracking has something to chew on
+func (sim *bmsSimulator) writeCellData(s *Service, rackNo uint8, avgCellVoltage, temperature float64) {
+	cellCount := s.GetTotalCellsPerRack()
+	voltages := make([]uint16, cellCount)
+	for i := range cellCount {
+		spread := 0.01 * math.Sin(float64(i)*0.7)
+		voltages[i] = simScale1000(avgCellVoltage + spread)
+	}
+	s.cellClient.SimSetRegisters(GetCellVoltageStartAddr(rackNo), voltages...)
+
+	// Temperatures are packed two sensors per register, one per byte
+	sensorCount := s.GetTotalTempSensorsPerRack()
+	temps := make([]uint16, (sensorCount+1)/2)
+	for i := range sensorCount {
+		value := uint16(temperature+50+math.Round(math.Sin(float64(i)*0.5))) & 0xFF
+		if i%2 == 0 {
+			temps[i/2] |= value << 8
+		} else {
+			temps[i/2] |= value
+		}
+	}
+	s.cellClient.SimSetRegisters(GetCellTempStartAddr(rackNo), temps...)
+}
+
+// simScale10 encodes a value with a 0.1 register scale
+func simScale10(value float64) uint16 {
+	return uint16(math.Round(value * 10))
+}
+
+// simScale1000 encodes a value with a 0.001 register scale
+func simScale1000(value float64) uint16 {
+	return uint16(math.Round(value * 1000))
+}
+
+// simOffset20000 encodes a signed value with the protocol's 20000 offset
+func simOffset20000(value float64) uint16 {
+	return uint16(math.Round(value) + 20000)
+}
+
+// simOffset50 encodes a temperature with the protocol's 50 offset
+func simOffset50(value float64) uint16 {
+	return uint16(math.Round(value) + 50)
+}
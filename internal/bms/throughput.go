@@ -0,0 +1,127 @@
+package bms
+
+import (
+	"time"
+)
+
+// Throughput derating: below the start utilization the rack contributes its
+// full share to the dispatch; from there the share shrinks linearly to zero
+// at 100 % of the warranty limit
+const throughputDeratingStart = 0.8
+
+// RackThroughput is the energy throughput utilization of one rack against
+// its configured warranty limits
+type RackThroughput struct {
+	RackNo            uint8   `json:"rack_no"`
+	DailyKWh          float32 `json:"daily_kwh"`
+	AnnualKWh         float32 `json:"annual_kwh"`
+	DailyLimitKWh     float32 `json:"daily_limit_kwh"`
+	AnnualLimitKWh    float32 `json:"annual_limit_kwh"`
+	DailyUtilization  float32 `json:"daily_utilization"`  // fraction of the daily limit
+	AnnualUtilization float32 `json:"annual_utilization"` // fraction of the annual limit
+}
+
+// recordRackThroughput integrates the absolute rack power into the daily and
+// annual throughput counters; the counters roll over on calendar boundaries
+func (s *Service) recordRackThroughput(rackNo uint8, powerKW float32, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if !s.throughputDay.Equal(day) {
+		if !s.throughputDay.IsZero() {
+			for i := range s.rackDailyKWh {
+				s.rackDailyKWh[i] = 0
+			}
+		}
+		s.throughputDay = day
+	}
+	if s.throughputYear != now.Year() {
+		if s.throughputYear != 0 {
+			for i := range s.rackAnnualKWh {
+				s.rackAnnualKWh[i] = 0
+			}
+		}
+		s.throughputYear = now.Year()
+	}
+
+	index := int(rackNo) - 1
+	if index < 0 || index >= len(s.rackDailyKWh) {
+		return
+	}
+
+	last := s.rackThroughputSample[index]
+	s.rackThroughputSample[index] = now
+	if last.IsZero() {
+		return
+	}
+
+	// Clip the step so data gaps do not integrate phantom throughput
+	dt := now.Sub(last)
+	if dt > 2*s.config.PollInterval {
+		dt = 2 * s.config.PollInterval
+	}
+
+	energy := float64(abs32(powerKW)) * dt.Hours()
+	s.rackDailyKWh[index] += energy
+	s.rackAnnualKWh[index] += energy
+}
+
+// GetRackThroughput returns the throughput utilization of every rack
+func (s *Service) GetRackThroughput() []RackThroughput {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	racks := make([]RackThroughput, len(s.rackDailyKWh))
+	for i := range racks {
+		racks[i] = RackThroughput{
+			RackNo:         uint8(i + 1),
+			DailyKWh:       float32(s.rackDailyKWh[i]),
+			AnnualKWh:      float32(s.rackAnnualKWh[i]),
+			DailyLimitKWh:  s.config.RackDailyThroughputKWh,
+			AnnualLimitKWh: s.config.RackAnnualThroughputKWh,
+		}
+		if racks[i].DailyLimitKWh > 0 {
+			racks[i].DailyUtilization = racks[i].DailyKWh / racks[i].DailyLimitKWh
+		}
+		if racks[i].AnnualLimitKWh > 0 {
+			racks[i].AnnualUtilization = racks[i].AnnualKWh / racks[i].AnnualLimitKWh
+		}
+	}
+	return racks
+}
+
+// ThroughputDerating returns the dispatch share factor of this string based
+// on the most utilized rack: 1 below the derating start, shrinking linearly
+// to 0 at the warranty limit. Strings without configured limits return 1.
+func (s *Service) ThroughputDerating() float32 {
+	if s.config.RackDailyThroughputKWh <= 0 && s.config.RackAnnualThroughputKWh <= 0 {
+		return 1
+	}
+
+	var worst float32
+	for _, rack := range s.GetRackThroughput() {
+		if rack.DailyUtilization > worst {
+			worst = rack.DailyUtilization
+		}
+		if rack.AnnualUtilization > worst {
+			worst = rack.AnnualUtilization
+		}
+	}
+
+	if worst <= throughputDeratingStart {
+		return 1
+	}
+	if worst >= 1 {
+		return 0
+	}
+	return (1 - worst) / (1 - throughputDeratingStart)
+}
+
+// abs32 returns the absolute value of a float32
+func abs32(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,188 @@
+// Package chaos provides injectable fault hooks for engineering
+// verification: dropping Modbus connections, delaying responses, corrupting
+// frames and failing database writes on demand, so reconnect, buffering and
+// safe-state logic can be exercised before relying on it. Fault injection is
+// gated behind ems.chaos_enabled and must never be enabled on a production
+// site.
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/pkg/modbus"
+)
+
+// Fault names accepted by the engineering endpoint
+const (
+	FaultModbusDrop    = "modbus_drop"
+	FaultModbusDelay   = "modbus_delay"
+	FaultModbusCorrupt = "modbus_corrupt"
+	FaultDBWriteFail   = "db_write_fail"
+)
+
+// knownFaults lists the faults the manager can inject
+var knownFaults = map[string]bool{
+	FaultModbusDrop:    true,
+	FaultModbusDelay:   true,
+	FaultModbusCorrupt: true,
+	FaultDBWriteFail:   true,
+}
+
+// Fault describes one active fault injection
+type Fault struct {
+	Name        string
+	Probability float64
+	Delay       time.Duration
+}
+
+// Manager holds the active fault set and implements the hooks installed into
+// the Modbus client and the database layer
+type Manager struct {
+	enabled bool
+	log     *zap.Logger
+
+	mutex  sync.RWMutex
+	faults map[string]Fault
+}
+
+// NewManager creates the chaos manager and installs the fault hooks when
+// chaos injection is enabled
+func NewManager(cfg *config.Config, logger *zap.Logger) *Manager {
+	manager := &Manager{
+		enabled: cfg.EMS.ChaosEnabled,
+		faults:  make(map[string]Fault),
+		log: logger.With(
+			zap.String("component", "chaos"),
+		),
+	}
+
+	if manager.enabled {
+		manager.log.Warn("Chaos fault injection is enabled; do not run on a production site")
+
+		modbus.SetFaultHooks(&modbus.FaultHooks{
+			BeforeRequest:     manager.beforeModbusRequest,
+			TransformResponse: manager.transformModbusResponse,
+		})
+		database.SetWriteFaultHook(manager.injectDBWrite)
+	}
+
+	return manager
+}
+
+// Enabled reports whether chaos injection is enabled
+func (m *Manager) Enabled() bool {
+	return m.enabled
+}
+
+// SetFault activates or updates a fault injection
+func (m *Manager) SetFault(fault Fault) error {
+	if !knownFaults[fault.Name] {
+		return fmt.Errorf("unknown fault: %s", fault.Name)
+	}
+	if fault.Probability <= 0 || fault.Probability > 1 {
+		return fmt.Errorf("probability must be in (0, 1], got %f", fault.Probability)
+	}
+	if fault.Delay < 0 {
+		return fmt.Errorf("delay must not be negative, got %v", fault.Delay)
+	}
+
+	m.mutex.Lock()
+	m.faults[fault.Name] = fault
+	m.mutex.Unlock()
+
+	m.log.Warn("Fault injection activated",
+		zap.String("fault", fault.Name),
+		zap.Float64("probability", fault.Probability),
+		zap.Duration("delay", fault.Delay))
+
+	return nil
+}
+
+// ClearFault deactivates a fault injection
+func (m *Manager) ClearFault(name string) error {
+	if !knownFaults[name] {
+		return fmt.Errorf("unknown fault: %s", name)
+	}
+
+	m.mutex.Lock()
+	delete(m.faults, name)
+	m.mutex.Unlock()
+
+	m.log.Info("Fault injection cleared", zap.String("fault", name))
+
+	return nil
+}
+
+// GetFaults returns the currently active faults
+func (m *Manager) GetFaults() []Fault {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	faults := make([]Fault, 0, len(m.faults))
+	for _, fault := range m.faults {
+		faults = append(faults, fault)
+	}
+	return faults
+}
+
+// shouldFire rolls the dice for one fault
+func (m *Manager) shouldFire(name string) (Fault, bool) {
+	m.mutex.RLock()
+	fault, active := m.faults[name]
+	m.mutex.RUnlock()
+
+	if !active {
+		return Fault{}, false
+	}
+	return fault, rand.Float64() < fault.Probability
+}
+
+// beforeModbusRequest delays or fails an outgoing Modbus request
+func (m *Manager) beforeModbusRequest(op string) error {
+	if fault, fire := m.shouldFire(FaultModbusDelay); fire {
+		time.Sleep(fault.Delay)
+	}
+
+	if _, fire := m.shouldFire(FaultModbusDrop); fire {
+		m.log.Debug("Injecting Modbus connection drop", zap.String("op", op))
+		return modbus.ErrConnectionDropped
+	}
+
+	return nil
+}
+
+// transformModbusResponse corrupts a response frame by truncating it or
+// flipping a byte, exercising the parser length guards and sanity checks
+func (m *Manager) transformModbusResponse(op string, data []byte) []byte {
+	_, fire := m.shouldFire(FaultModbusCorrupt)
+	if !fire || len(data) == 0 {
+		return data
+	}
+
+	m.log.Debug("Injecting Modbus frame corruption", zap.String("op", op))
+
+	corrupted := make([]byte, len(data))
+	copy(corrupted, data)
+
+	if rand.Intn(2) == 0 {
+		return corrupted[:rand.Intn(len(corrupted))]
+	}
+	corrupted[rand.Intn(len(corrupted))] ^= 0xFF
+	return corrupted
+}
+
+// injectDBWrite fails a database write
+func (m *Manager) injectDBWrite() error {
+	if _, fire := m.shouldFire(FaultDBWriteFail); fire {
+		return errors.New("database write failed by fault injection")
+	}
+	return nil
+}
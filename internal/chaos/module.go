@@ -0,0 +1,10 @@
+package chaos
+
+import (
+	"go.uber.org/fx"
+)
+
+// Module provides chaos fault injection to the Fx application
+var Module = fx.Module("chaos",
+	fx.Provide(NewManager),
+)
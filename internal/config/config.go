@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -11,23 +12,280 @@ import (
 
 // Config represents the complete application configuration
 type Config struct {
-	PCS          []PCSConfig        `mapstructure:"pcs" validate:"required,min=1,dive"`
-	BMS          []BMSConfig        `mapstructure:"bms" validate:"required,min=1,dive"`
-	PLC          []PLCConfig        `mapstructure:"plc" validate:"required,min=1,dive"`
-	WindFarm     []WindFarmConfig   `mapstructure:"windfarm" validate:"required,min=1,dive"`
-	ION7400      AnalyzerConfig     `mapstructure:"ion7400" validate:"required"`
-	EMS          EMSConfig          `mapstructure:"ems" validate:"required"`
-	Alarm        AlarmConfig        `mapstructure:"alarm" validate:"required"`
-	InfluxDB     InfluxDBConfig     `mapstructure:"influxdb" validate:"required"`
-	PostgreSQL   PostgreSQLConfig   `mapstructure:"postgresql" validate:"required"`
-	ModbusServer ModbusServerConfig `mapstructure:"modbus_server" validate:"required"`
-	Logging      LoggingConfig      `mapstructure:"logging" validate:"required"`
+	PCS          []PCSConfig          `mapstructure:"pcs" validate:"required,min=1,dive"`
+	BMS          []BMSConfig          `mapstructure:"bms" validate:"required,min=1,dive"`
+	PLC          []PLCConfig          `mapstructure:"plc" validate:"required,min=1,dive"`
+	WindFarm     []WindFarmConfig     `mapstructure:"windfarm" validate:"required,min=1,dive"`
+	Topology     []TopologyPairConfig `mapstructure:"topology" validate:"omitempty,dive"`
+	ION7400      AnalyzerConfig       `mapstructure:"ion7400" validate:"required"`
+	EMS          EMSConfig            `mapstructure:"ems" validate:"required"`
+	FCR          FCRConfig            `mapstructure:"fcr"`
+	AFRR         AFRRConfig           `mapstructure:"afrr"`
+	SelfTest     SelfTestConfig       `mapstructure:"selftest"`
+	Alarm        AlarmConfig          `mapstructure:"alarm" validate:"required"`
+	InfluxDB     InfluxDBConfig       `mapstructure:"influxdb" validate:"required"`
+	PostgreSQL   PostgreSQLConfig     `mapstructure:"postgresql" validate:"required"`
+	ModbusServer ModbusServerConfig   `mapstructure:"modbus_server" validate:"required"`
+	Logging      LoggingConfig        `mapstructure:"logging" validate:"required"`
+	Network      NetworkConfig        `mapstructure:"network"`
+	CMDB         CMDBConfig           `mapstructure:"cmdb"`
+	Weather      WeatherConfig        `mapstructure:"weather"`
+	Rules        RulesConfig          `mapstructure:"rules"`
+	Shadow       ShadowConfig         `mapstructure:"shadow"`
+	Peer         PeerConfig           `mapstructure:"peer"`
+	MQTT         MQTTConfig           `mapstructure:"mqtt"`
+	Historian    HistorianConfig      `mapstructure:"historian"`
+	Season       SeasonConfig         `mapstructure:"season"`
+	Declaration  DeclarationConfig    `mapstructure:"declaration"`
+	Plant        PlantConfig          `mapstructure:"plant"`
+	Auth         AuthConfig           `mapstructure:"auth"`
+
+	// filePath is the configuration file the values were loaded from
+	filePath string
+}
+
+// FilePath returns the configuration file the values were loaded from
+func (c *Config) FilePath() string {
+	return c.filePath
+}
+
+// NetworkConfig separates device and operator traffic onto dedicated
+// network interfaces on dual-homed sites: device connections bind to the OT
+// NIC and the HTTP API binds to the IT NIC. Empty addresses keep the
+// operating system routing.
+type NetworkConfig struct {
+	OTBindAddress string `mapstructure:"ot_bind_address" validate:"omitempty,ip"`
+	ITBindAddress string `mapstructure:"it_bind_address" validate:"omitempty,ip"`
+}
+
+// CMDBConfig configures self-registration with the central asset-management
+// system. When enabled the device inventory is POSTed to the URL on startup
+// and re-submitted on changes or after the refresh interval.
+type CMDBConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	URL             string        `mapstructure:"url" validate:"required_if=Enabled true,omitempty,url"`
+	APIKey          string        `mapstructure:"api_key"`
+	Timeout         time.Duration `mapstructure:"timeout" validate:"min=0"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval" validate:"min=0"`
+}
+
+// WeatherConfig configures the secondary weather source used when the FCU
+// weather registers are frozen or implausible. The URL is polled
+// periodically and must return the current observation as JSON.
+type WeatherConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	URL             string        `mapstructure:"url" validate:"required_if=Enabled true,omitempty,url"`
+	APIKey          string        `mapstructure:"api_key"`
+	Timeout         time.Duration `mapstructure:"timeout" validate:"min=0"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval" validate:"min=0"`
+}
+
+// RulesConfig configures the embedded automation rule engine. Rules are CEL
+// expressions over the live telemetry; their actions are limited to raising
+// alarms and guarded power commands, and the whole engine is rate limited.
+type RulesConfig struct {
+	Enabled              bool          `mapstructure:"enabled"`
+	EvalInterval         time.Duration `mapstructure:"eval_interval" validate:"min=0"`
+	MaxCommandsPerMinute int           `mapstructure:"max_commands_per_minute" validate:"min=0"`
+	Rules                []RuleConfig  `mapstructure:"rules" validate:"dive"`
+}
+
+// RuleConfig defines one automation rule: a boolean CEL expression and the
+// action taken on its rising edge. Alarm rules also clear the alarm on the
+// falling edge; power rules re-arm after the cooldown.
+type RuleConfig struct {
+	Name       string `mapstructure:"name" validate:"required"`
+	Expression string `mapstructure:"expression" validate:"required"`
+	Action     string `mapstructure:"action" validate:"required,oneof=alarm set_power"`
+
+	// Alarm action parameters
+	Message  string `mapstructure:"message"`
+	Severity string `mapstructure:"severity" validate:"omitempty,oneof=HIGH MEDIUM LOW"`
+
+	// Power action parameters
+	PCSID   int     `mapstructure:"pcs_id" validate:"min=0"`
+	PowerKW float32 `mapstructure:"power_kw"`
+
+	Cooldown time.Duration `mapstructure:"cooldown" validate:"min=0"`
+}
+
+// ShadowConfig configures shadow-mode evaluation of an alternative dispatch
+// strategy. The strategy runs on live data without commanding anything; its
+// would-be commands are recorded and compared against the active controller.
+type ShadowConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Strategy     string        `mapstructure:"strategy"`
+	EvalInterval time.Duration `mapstructure:"eval_interval" validate:"min=0"`
+}
+
+// PeerConfig configures the coordination link to the EMS of the plant that
+// shares our grid connection point. Both instances exchange real-time NCP
+// state over gRPC and negotiate their export allocations so the combined
+// output stays below the shared connection limit.
+type PeerConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	SiteName            string        `mapstructure:"site_name"`
+	ListenAddress       string        `mapstructure:"listen_address"`
+	PeerAddress         string        `mapstructure:"peer_address"`
+	SharedExportLimitKW float32       `mapstructure:"shared_export_limit_kw" validate:"min=0"`
+	FallbackExportKW    float32       `mapstructure:"fallback_export_kw" validate:"min=0"`
+	ExchangeInterval    time.Duration `mapstructure:"exchange_interval" validate:"min=0"`
+	Timeout             time.Duration `mapstructure:"timeout" validate:"min=0"`
+}
+
+// MQTTConfig configures the external telemetry publisher. Device
+// snapshots, active alarms and frequency-reserve state are pushed as JSON
+// to the configured topic tree on every publish interval.
+type MQTTConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	BrokerURL       string        `mapstructure:"broker_url" validate:"required_if=Enabled true"`
+	ClientID        string        `mapstructure:"client_id"`
+	Username        string        `mapstructure:"username"`
+	Password        string        `mapstructure:"password"`
+	BaseTopic       string        `mapstructure:"base_topic"`
+	QoS             int           `mapstructure:"qos" validate:"min=0,max=2"`
+	TLS             TLSConfig     `mapstructure:"tls"`
+	PublishInterval time.Duration `mapstructure:"publish_interval" validate:"min=0"`
+	ConnectTimeout  time.Duration `mapstructure:"connect_timeout" validate:"min=0"`
+	ReconnectDelay  time.Duration `mapstructure:"reconnect_delay" validate:"min=0"`
+}
+
+// HistorianConfig configures mirroring of the telemetry stream into
+// third-party plant historians alongside InfluxDB
+type HistorianConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	FlushInterval time.Duration `mapstructure:"flush_interval" validate:"min=0"`
+	BufferSize    int           `mapstructure:"buffer_size" validate:"min=0"`
+	PI            PIConfig      `mapstructure:"pi"`
+	OPCUA         OPCUAConfig   `mapstructure:"opcua"`
+}
+
+// PIConfig configures the OSIsoft PI Web API sink. Batches are POSTed as
+// OMF data messages to the configured ingress endpoint; the container id
+// of a value is the measurement followed by its tag values.
+type PIConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	URL      string        `mapstructure:"url" validate:"required_if=Enabled true,omitempty,url"`
+	Username string        `mapstructure:"username"`
+	Password string        `mapstructure:"password"`
+	Timeout  time.Duration `mapstructure:"timeout" validate:"min=0"`
+}
+
+// OPCUAConfig configures the generic OPC UA historian sink. Values are
+// written to string node ids "<node_prefix>.<container>.<field>" in the
+// configured namespace.
+type OPCUAConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Endpoint   string        `mapstructure:"endpoint" validate:"required_if=Enabled true"`
+	Namespace  int           `mapstructure:"namespace" validate:"min=0,max=65535"`
+	NodePrefix string        `mapstructure:"node_prefix"`
+	Timeout    time.Duration `mapstructure:"timeout" validate:"min=0"`
+}
+
+// DeclarationConfig configures the daily availability declaration to the
+// off-taker: the declaration is compiled each morning at the compile hour
+// in market time, held for operator review, and delivered over the enabled
+// channels after approval.
+type DeclarationConfig struct {
+	Enabled     bool                   `mapstructure:"enabled"`
+	CompileHour int                    `mapstructure:"compile_hour" validate:"min=0,max=23"`
+	Email       DeclarationEmailConfig `mapstructure:"email"`
+	SFTP        DeclarationSFTPConfig  `mapstructure:"sftp"`
+	Endpoint    DeclarationAPIEndpoint `mapstructure:"api"`
+}
+
+// DeclarationEmailConfig delivers the approved declaration as a CSV mail
+// attachment through an SMTP relay
+type DeclarationEmailConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	SMTPHost string   `mapstructure:"smtp_host" validate:"required_if=Enabled true"`
+	SMTPPort int      `mapstructure:"smtp_port" validate:"min=0,max=65535"`
+	From     string   `mapstructure:"from" validate:"required_if=Enabled true"`
+	To       []string `mapstructure:"to" validate:"required_if=Enabled true,omitempty,dive,email"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+}
+
+// DeclarationSFTPConfig uploads the approved declaration as a CSV file into
+// the off-taker's SFTP drop directory
+type DeclarationSFTPConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Host      string `mapstructure:"host" validate:"required_if=Enabled true"`
+	Port      int    `mapstructure:"port" validate:"min=0,max=65535"`
+	Username  string `mapstructure:"username" validate:"required_if=Enabled true"`
+	Password  string `mapstructure:"password"`
+	RemoteDir string `mapstructure:"remote_dir"`
+}
+
+// DeclarationAPIEndpoint POSTs the approved declaration as JSON to the
+// off-taker's API
+type DeclarationAPIEndpoint struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	URL       string        `mapstructure:"url" validate:"required_if=Enabled true,omitempty,url"`
+	AuthToken string        `mapstructure:"auth_token"`
+	Timeout   time.Duration `mapstructure:"timeout" validate:"min=0"`
+}
+
+// SeasonConfig configures named seasonal operating profiles. A profile
+// bundles the SOC window, reserve level, FCR capacity cap and thermal power
+// limits of one season so re-tuning a site does not mean editing the base
+// configuration. The active profile comes from the calendar, the configured
+// default, or a runtime override through the API.
+type SeasonConfig struct {
+	Enabled       bool                   `mapstructure:"enabled"`
+	ActiveProfile string                 `mapstructure:"active_profile"`
+	Profiles      []SeasonProfileConfig  `mapstructure:"profiles" validate:"omitempty,dive"`
+	Calendar      []SeasonCalendarConfig `mapstructure:"calendar" validate:"omitempty,dive"`
+}
+
+// SeasonProfileConfig is the operating point of one named season. A zero
+// value keeps the corresponding base configuration; the charge and
+// discharge limits bound the thermal loading of the battery.
+type SeasonProfileConfig struct {
+	Name                string  `mapstructure:"name" json:"name" validate:"required"`
+	MaxSOC              float32 `mapstructure:"max_soc" json:"max_soc" validate:"min=0,max=100"`
+	MinSOC              float32 `mapstructure:"min_soc" json:"min_soc" validate:"min=0,max=100"`
+	IslandingReserveSOC float32 `mapstructure:"islanding_reserve_soc" json:"islanding_reserve_soc" validate:"min=0,max=100"`
+	FCRCapacityCapKW    float32 `mapstructure:"fcr_capacity_cap_kw" json:"fcr_capacity_cap_kw" validate:"min=0"`
+	MaxChargePower      float32 `mapstructure:"max_charge_power" json:"max_charge_power" validate:"min=0"`
+	MaxDischargePower   float32 `mapstructure:"max_discharge_power" json:"max_discharge_power" validate:"min=0"`
+}
+
+// SeasonCalendarConfig activates one profile over a yearly recurring date
+// range in market time, dates as "MM-DD"; a range may wrap over the turn of
+// the year (e.g. 11-01 to 03-31)
+type SeasonCalendarConfig struct {
+	Profile string `mapstructure:"profile" validate:"required"`
+	Start   string `mapstructure:"start" validate:"required,datetime=01-02"`
+	End     string `mapstructure:"end" validate:"required,datetime=01-02"`
+}
+
+// TLSConfig enables Modbus/TCP Security (TLS-tunneled Modbus) on one
+// connection. The certificate and key identify this EMS; the CA file
+// validates the peer. All paths are on the local filesystem.
+type TLSConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CertFile string `mapstructure:"cert_file" validate:"required_if=Enabled true"`
+	KeyFile  string `mapstructure:"key_file" validate:"required_if=Enabled true"`
+	CAFile   string `mapstructure:"ca_file" validate:"required_if=Enabled true"`
+}
+
+// PollGroupConfig tunes one named poll group of a device. Optional groups
+// are disabled automatically after the failure threshold is reached; an
+// interval of 0 polls the group every cycle.
+type PollGroupConfig struct {
+	Name             string        `mapstructure:"name" validate:"required"`
+	Optional         bool          `mapstructure:"optional"`
+	Interval         time.Duration `mapstructure:"interval" validate:"min=0"`
+	FailureThreshold int           `mapstructure:"failure_threshold" validate:"min=0"`
 }
 
 // PCSConfig contains PCS-specific configuration
 type PCSConfig struct {
 	ID                int           `mapstructure:"id" validate:"required,min=1"`
 	Host              string        `mapstructure:"host" validate:"required,hostname_rfc1123|ip"`
+	BindAddress       string        `mapstructure:"bind_address" validate:"omitempty,ip"`
+	TLS               TLSConfig     `mapstructure:"tls"`
 	Port              int           `mapstructure:"port" validate:"required,min=1,max=65535"`
 	SlaveID           byte          `mapstructure:"slave_id" validate:"required,min=1,max=255"`
 	Timeout           time.Duration `mapstructure:"timeout" validate:"required"`
@@ -35,12 +293,41 @@ type PCSConfig struct {
 	PollInterval      time.Duration `mapstructure:"poll_interval" validate:"required,aligned_interval"`
 	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval" validate:"required,aligned_interval"`
 	PersistInterval   time.Duration `mapstructure:"persist_interval" validate:"required,aligned_interval"`
+
+	// Run against the built-in device simulator instead of the real
+	// MODBUS endpoint (software-in-the-loop)
+	Simulate bool `mapstructure:"simulate"`
+
+	// Exponential reconnect backoff ceiling (0 = ten times the reconnect
+	// delay) and retry budget per outage (0 = retry forever)
+	ReconnectMaxDelay   time.Duration `mapstructure:"reconnect_max_delay" validate:"min=0"`
+	ReconnectMaxRetries int           `mapstructure:"reconnect_max_retries" validate:"min=0"`
+
+	// Bidirectional heartbeat supervision thresholds (0 = built-in defaults)
+	HeartbeatStaleTimeout time.Duration `mapstructure:"heartbeat_stale_timeout" validate:"min=0"`
+	HeartbeatLossTimeout  time.Duration `mapstructure:"heartbeat_loss_timeout" validate:"min=0"`
+
+	// MV transformer nameplate rating in kVA (0 = loading monitoring disabled)
+	TransformerRatingKVA float32 `mapstructure:"transformer_rating_kva" validate:"min=0"`
+
+	// Command queue retry behaviour (0 = built-in defaults)
+	CommandRetries    int           `mapstructure:"command_retries" validate:"min=0"`
+	CommandRetryDelay time.Duration `mapstructure:"command_retry_delay" validate:"min=0"`
+
+	// Per-group polling overrides (see PollGroupConfig)
+	PollGroups []PollGroupConfig `mapstructure:"poll_groups" validate:"dive"`
+
+	// DC busbar roles for sites with DC-coupled PV ("battery" or "pv",
+	// in busbar order); unlisted busbars are battery busbars
+	BusbarRoles []string `mapstructure:"busbar_roles" validate:"omitempty,max=4,dive,oneof=battery pv"`
 }
 
 // BMSConfig contains BMS-specific configuration
 type BMSConfig struct {
 	ID                int           `mapstructure:"id" validate:"required,min=1"`
 	Host              string        `mapstructure:"host" validate:"required,hostname_rfc1123|ip"`
+	BindAddress       string        `mapstructure:"bind_address" validate:"omitempty,ip"`
+	TLS               TLSConfig     `mapstructure:"tls"`
 	Port              int           `mapstructure:"port" validate:"required,min=1,max=65535"`
 	SlaveID           byte          `mapstructure:"slave_id" validate:"required,min=1,max=255"`
 	Timeout           time.Duration `mapstructure:"timeout" validate:"required"`
@@ -52,24 +339,192 @@ type BMSConfig struct {
 	RackCount         int           `mapstructure:"rack_count" validate:"required,min=1,max=20"`
 	ModulesPerRack    int           `mapstructure:"modules_per_rack" validate:"required,min=1,max=8"`
 	EnableCellData    bool          `mapstructure:"enable_cell_data"`
+
+	// Run against the built-in device simulator instead of the real
+	// MODBUS endpoint (software-in-the-loop)
+	Simulate bool `mapstructure:"simulate"`
+
+	// Exponential reconnect backoff ceiling (0 = ten times the reconnect
+	// delay) and retry budget per outage (0 = retry forever)
+	ReconnectMaxDelay   time.Duration `mapstructure:"reconnect_max_delay" validate:"min=0"`
+	ReconnectMaxRetries int           `mapstructure:"reconnect_max_retries" validate:"min=0"`
+
+	// Bidirectional heartbeat supervision thresholds (0 = built-in defaults)
+	HeartbeatStaleTimeout time.Duration `mapstructure:"heartbeat_stale_timeout" validate:"min=0"`
+	HeartbeatLossTimeout  time.Duration `mapstructure:"heartbeat_loss_timeout" validate:"min=0"`
+
+	// Per-string SOC window and power limits (0 = global EMS fallback);
+	// racks with different usable windows override the EMS-wide values
+	MaxSOC            float32 `mapstructure:"max_soc" validate:"min=0,max=100"`
+	MinSOC            float32 `mapstructure:"min_soc" validate:"min=0,max=100"`
+	MaxChargePower    float32 `mapstructure:"max_charge_power" validate:"min=0"`
+	MaxDischargePower float32 `mapstructure:"max_discharge_power" validate:"min=0"`
+
+	// Per-rack warranty throughput limits in kWh (0 = no limit); racks
+	// nearing a limit reduce the dispatch share of the whole string
+	RackDailyThroughputKWh  float32 `mapstructure:"rack_daily_throughput_kwh" validate:"min=0"`
+	RackAnnualThroughputKWh float32 `mapstructure:"rack_annual_throughput_kwh" validate:"min=0"`
+}
+
+// SafetyActionConfig maps one safety condition class to the set of actions
+// executed when the condition is active; rules with a higher priority are
+// executed first when several conditions hit the same PCS
+type SafetyActionConfig struct {
+	Condition string   `mapstructure:"condition" validate:"required,oneof=bms_fault heartbeat_lost soc_high_charging soc_low_discharging"`
+	Priority  int      `mapstructure:"priority" validate:"min=0"`
+	Actions   []string `mapstructure:"actions" validate:"required,min=1,dive,oneof=zero_power stop_pcs open_breaker alarm"`
+}
+
+// BMSLimits are the effective SOC window and power limits of one BMS string
+// after resolving per-device overrides against the global EMS limits
+type BMSLimits struct {
+	MaxSOC            float32 `json:"max_soc"`
+	MinSOC            float32 `json:"min_soc"`
+	MaxChargePower    float32 `json:"max_charge_power"`
+	MaxDischargePower float32 `json:"max_discharge_power"`
+}
+
+// TopologyPairConfig maps one PCS to the BMS strings connected to it; sites
+// without a topology section fall back to the default layout of two strings
+// per PCS
+type TopologyPairConfig struct {
+	PCS int   `mapstructure:"pcs" validate:"required,min=1"`
+	BMS []int `mapstructure:"bms" validate:"required,min=1,dive,min=1"`
+}
+
+// PairedBMSIDs returns the BMS strings connected to one PCS. Without a
+// configured topology the fixed default layout applies
+// (PCS1 -> BMS1, BMS2; PCS2 -> BMS3, BMS4; ...).
+func (c *Config) PairedBMSIDs(pcsID int) []int {
+	for _, pair := range c.Topology {
+		if pair.PCS == pcsID {
+			return pair.BMS
+		}
+	}
+	return []int{(pcsID-1)*2 + 1, (pcsID-1)*2 + 2}
+}
+
+// TopologyMap resolves the full BMS-PCS topology keyed by PCS ID
+func (c *Config) TopologyMap() map[int][]int {
+	topology := make(map[int][]int, len(c.PCS))
+	for _, pcsCfg := range c.PCS {
+		topology[pcsCfg.ID] = c.PairedBMSIDs(pcsCfg.ID)
+	}
+	return topology
+}
+
+// verifyTopology checks that a configured topology only references
+// configured devices and does not connect one BMS string to several PCS
+func (c *Config) verifyTopology() error {
+	if len(c.Topology) == 0 {
+		return nil
+	}
+
+	pcsIDs := make(map[int]bool, len(c.PCS))
+	for _, pcsCfg := range c.PCS {
+		pcsIDs[pcsCfg.ID] = true
+	}
+	bmsIDs := make(map[int]bool, len(c.BMS))
+	for _, bmsCfg := range c.BMS {
+		bmsIDs[bmsCfg.ID] = true
+	}
+
+	assigned := make(map[int]int)
+	for _, pair := range c.Topology {
+		if !pcsIDs[pair.PCS] {
+			return fmt.Errorf("topology references unknown PCS %d", pair.PCS)
+		}
+		for _, bmsID := range pair.BMS {
+			if !bmsIDs[bmsID] {
+				return fmt.Errorf("topology references unknown BMS %d on PCS %d", bmsID, pair.PCS)
+			}
+			if other, ok := assigned[bmsID]; ok {
+				return fmt.Errorf("topology connects BMS %d to both PCS %d and PCS %d", bmsID, other, pair.PCS)
+			}
+			assigned[bmsID] = pair.PCS
+		}
+	}
+	return nil
+}
+
+// LimitsForBMS resolves the limits for a BMS string, falling back to the
+// global EMS limits for any value not set per device
+func (c *Config) LimitsForBMS(id int) BMSLimits {
+	limits := BMSLimits{
+		MaxSOC:            c.EMS.MaxSOC,
+		MinSOC:            c.EMS.MinSOC,
+		MaxChargePower:    c.EMS.MaxChargePower,
+		MaxDischargePower: c.EMS.MaxDischargePower,
+	}
+
+	for _, bmsCfg := range c.BMS {
+		if bmsCfg.ID != id {
+			continue
+		}
+		if bmsCfg.MaxSOC > 0 {
+			limits.MaxSOC = bmsCfg.MaxSOC
+		}
+		if bmsCfg.MinSOC > 0 {
+			limits.MinSOC = bmsCfg.MinSOC
+		}
+		if bmsCfg.MaxChargePower > 0 {
+			limits.MaxChargePower = bmsCfg.MaxChargePower
+		}
+		if bmsCfg.MaxDischargePower > 0 {
+			limits.MaxDischargePower = bmsCfg.MaxDischargePower
+		}
+		break
+	}
+
+	return limits
 }
 
 // PLCConfig contains PLC-specific configuration
 type PLCConfig struct {
 	ID              int           `mapstructure:"id" validate:"required,min=1"`
 	Host            string        `mapstructure:"host" validate:"required,hostname_rfc1123|ip"`
+	BindAddress     string        `mapstructure:"bind_address" validate:"omitempty,ip"`
+	TLS             TLSConfig     `mapstructure:"tls"`
 	Port            int           `mapstructure:"port" validate:"required,min=1,max=65535"`
 	SlaveID         byte          `mapstructure:"slave_id" validate:"required,min=1,max=255"`
 	Timeout         time.Duration `mapstructure:"timeout" validate:"required"`
 	ReconnectDelay  time.Duration `mapstructure:"reconnect_delay" validate:"required"`
 	PollInterval    time.Duration `mapstructure:"poll_interval" validate:"required,aligned_interval"`
 	PersistInterval time.Duration `mapstructure:"persist_interval" validate:"required,aligned_interval"`
+
+	// Run against the built-in device simulator instead of the real
+	// MODBUS endpoint (software-in-the-loop)
+	Simulate bool `mapstructure:"simulate"`
+
+	// Exponential reconnect backoff ceiling (0 = ten times the reconnect
+	// delay) and retry budget per outage (0 = retry forever)
+	ReconnectMaxDelay   time.Duration `mapstructure:"reconnect_max_delay" validate:"min=0"`
+	ReconnectMaxRetries int           `mapstructure:"reconnect_max_retries" validate:"min=0"`
+
+	// Holding register exposing the transformer tap-changer position (0 = not exposed by this PLC)
+	TapPositionAddr uint16 `mapstructure:"tap_position_addr" validate:"min=0"`
+
+	// Automatic reclosure of selected breakers after a trip (see AutoRecloseConfig)
+	AutoReclose AutoRecloseConfig `mapstructure:"auto_reclose"`
+}
+
+// AutoRecloseConfig configures automatic reclosure of selected circuit
+// breakers after a nuisance trip. Reclosure is only armed by an observed
+// closed-to-open transition, never for breakers found open at startup, and
+// is interlocked against the matching protection relay.
+type AutoRecloseConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Breakers    []string      `mapstructure:"breakers" validate:"omitempty,dive,oneof=auxiliary mv_aux_transformer transformer1 transformer2 transformer3 transformer4"`
+	MaxAttempts int           `mapstructure:"max_attempts" validate:"min=0,max=10"`
+	RetryDelay  time.Duration `mapstructure:"retry_delay" validate:"min=0"`
 }
 
 // WindFarmConfig contains Wind Farm (ENERCON FCU) specific configuration
 type WindFarmConfig struct {
 	ID                int           `mapstructure:"id" validate:"required,min=1"`
 	Host              string        `mapstructure:"host" validate:"required,hostname_rfc1123|ip"`
+	BindAddress       string        `mapstructure:"bind_address" validate:"omitempty,ip"`
+	TLS               TLSConfig     `mapstructure:"tls"`
 	Port              int           `mapstructure:"port" validate:"required,min=1,max=65535"`
 	SlaveID           byte          `mapstructure:"slave_id" validate:"required,min=1,max=255"`
 	Timeout           time.Duration `mapstructure:"timeout" validate:"required"`
@@ -77,17 +532,91 @@ type WindFarmConfig struct {
 	PollInterval      time.Duration `mapstructure:"poll_interval" validate:"required,aligned_interval"`
 	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval" validate:"required,aligned_interval"`
 	PersistInterval   time.Duration `mapstructure:"persist_interval" validate:"required,aligned_interval"`
+
+	// Run against the built-in device simulator instead of the real
+	// MODBUS endpoint (software-in-the-loop)
+	Simulate bool `mapstructure:"simulate"`
+
+	// Exponential reconnect backoff ceiling (0 = ten times the reconnect
+	// delay) and retry budget per outage (0 = retry forever)
+	ReconnectMaxDelay   time.Duration `mapstructure:"reconnect_max_delay" validate:"min=0"`
+	ReconnectMaxRetries int           `mapstructure:"reconnect_max_retries" validate:"min=0"`
+
+	// Per-group polling overrides (see PollGroupConfig)
+	PollGroups []PollGroupConfig `mapstructure:"poll_groups" validate:"dive"`
+
+	// Power curve of the wind farm (wind speed -> expected power); at least
+	// two points enable expected-production and deviation calculation
+	PowerCurve []PowerCurvePoint `mapstructure:"power_curve" validate:"dive"`
+}
+
+// PowerCurvePoint is one support point of the wind farm power curve
+type PowerCurvePoint struct {
+	WindSpeed float32 `mapstructure:"wind_speed" validate:"min=0"` // m/s
+	Power     float32 `mapstructure:"power" validate:"min=0"`      // MW
 }
 
 // AnalyzerConfig contains Energy Analyzer-specific configuration
 type AnalyzerConfig struct {
 	Host            string        `mapstructure:"host" validate:"required,hostname_rfc1123|ip"`
+	BindAddress     string        `mapstructure:"bind_address" validate:"omitempty,ip"`
+	TLS             TLSConfig     `mapstructure:"tls"`
 	Port            int           `mapstructure:"port" validate:"required,min=1,max=65535"`
 	SlaveID         byte          `mapstructure:"slave_id" validate:"required,min=1,max=255"`
 	Timeout         time.Duration `mapstructure:"timeout" validate:"required"`
 	ReconnectDelay  time.Duration `mapstructure:"reconnect_delay" validate:"required"`
 	PollInterval    time.Duration `mapstructure:"poll_interval" validate:"required,aligned_interval"`
 	PersistInterval time.Duration `mapstructure:"persist_interval" validate:"required,aligned_interval"`
+
+	// Exponential reconnect backoff ceiling (0 = ten times the reconnect
+	// delay) and retry budget per outage (0 = retry forever)
+	ReconnectMaxDelay   time.Duration `mapstructure:"reconnect_max_delay" validate:"min=0"`
+	ReconnectMaxRetries int           `mapstructure:"reconnect_max_retries" validate:"min=0"`
+}
+
+// AuthConfig contains the API authentication and user management settings
+type AuthConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	SessionTTL time.Duration `mapstructure:"session_ttl" validate:"min=0"`
+
+	// Local account password policy
+	PasswordMinLength int  `mapstructure:"password_min_length" validate:"min=0"`
+	PasswordMixedCase bool `mapstructure:"password_mixed_case"`
+	PasswordDigit     bool `mapstructure:"password_digit"`
+
+	LDAP LDAPConfig `mapstructure:"ldap"`
+	OIDC OIDCConfig `mapstructure:"oidc"`
+}
+
+// LDAPConfig contains the optional LDAP federation settings; users are
+// authenticated by binding with a DN derived from the username
+type LDAPConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	URL            string `mapstructure:"url" validate:"required_if=Enabled true"`
+	UserDNTemplate string `mapstructure:"user_dn_template" validate:"required_if=Enabled true"`
+}
+
+// OIDCConfig contains the optional OIDC federation settings; bearer tokens
+// are validated against the identity provider userinfo endpoint
+type OIDCConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	UserinfoURL   string `mapstructure:"userinfo_url" validate:"required_if=Enabled true,omitempty,url"`
+	UsernameClaim string `mapstructure:"username_claim"`
+}
+
+// PlantConfig contains the plant standing data: nameplate ratings and grid
+// connection identifiers that SCADA, TSO forms and reports need. The values
+// are declarative; nothing in the EMS control path depends on them.
+type PlantConfig struct {
+	ConnectionPointID  string            `mapstructure:"connection_point_id"`
+	GridOperator       string            `mapstructure:"grid_operator"`
+	GridCode           string            `mapstructure:"grid_code"`
+	RatedPowerKW       float32           `mapstructure:"rated_power_kw" validate:"min=0"`
+	RatedEnergyKWh     float32           `mapstructure:"rated_energy_kwh" validate:"min=0"`
+	NominalVoltageKV   float32           `mapstructure:"nominal_voltage_kv" validate:"min=0"`
+	NominalFrequencyHz float32           `mapstructure:"nominal_frequency_hz" validate:"min=0"`
+	CommissioningDate  string            `mapstructure:"commissioning_date"`
+	FirmwareVersions   map[string]string `mapstructure:"firmware_versions"`
 }
 
 // EMSConfig contains EMS-specific configuration
@@ -98,11 +627,356 @@ type EMSConfig struct {
 	MinSOC            float32 `mapstructure:"min_soc" validate:"required,min=0,max=100"`
 	MaxChargePower    float32 `mapstructure:"max_charge_power" validate:"required,min=0"`
 	MaxDischargePower float32 `mapstructure:"max_discharge_power" validate:"required,min=0"`
+
+	// Per-request deadlines for the HTTP API (0 = none); the slow budget
+	// covers the history and simulation routes
+	HTTPRequestTimeout     time.Duration `mapstructure:"http_request_timeout" validate:"min=0"`
+	HTTPSlowRequestTimeout time.Duration `mapstructure:"http_slow_request_timeout" validate:"min=0"`
+
+	// How long a POST response is replayed for retries carrying the same
+	// Idempotency-Key header (0 = deduplication disabled)
+	IdempotencyWindow time.Duration `mapstructure:"idempotency_window" validate:"min=0"`
+
+	// Site connection-point limits in kW (0 = not enforced)
+	MaxSiteExportPower float32 `mapstructure:"max_site_export_power" validate:"min=0"`
+	MaxSiteImportPower float32 `mapstructure:"max_site_import_power" validate:"min=0"`
+
+	// Time-of-day import limit windows in market time; inside a window the
+	// window limit replaces the static import limit, the most restrictive
+	// overlapping window wins. The table is replaceable at runtime via the
+	// API.
+	ImportLimitSchedule []ImportLimitWindowConfig `mapstructure:"import_limit_schedule" validate:"omitempty,dive"`
+
+	// Manual setpoints expire after this lease unless refreshed, reverting
+	// to zero power and AUTO mode (0 = never)
+	ManualCommandLease time.Duration `mapstructure:"manual_command_lease" validate:"min=0"`
+
+	// Protected islanding backup reserve in percent SOC (0 = none); the
+	// initial value, renegotiable at runtime via the API
+	IslandingReserveSOC float32 `mapstructure:"islanding_reserve_soc" validate:"min=0,max=100"`
+
+	// Declarative safety action matrix mapping condition classes to action
+	// sets; conditions without a configured rule fall back to zeroing the
+	// PCS power
+	SafetyMatrix []SafetyActionConfig `mapstructure:"safety_matrix" validate:"omitempty,dive"`
+
+	// Resynchronization after grid return: how long the grid must measure
+	// healthy before breakers are closed, and the ramp rate in kW per
+	// minute along which power is released afterwards
+	ResyncGridHealthyTime time.Duration `mapstructure:"resync_grid_healthy_time" validate:"min=0"`
+	ResyncRampRate        float32       `mapstructure:"resync_ramp_rate" validate:"min=0"`
+
+	// Automatic runtime profile capture: goroutine/heap/CPU profiles are
+	// written to the capture directory when the runtime metrics cross
+	// these thresholds; zero disables a threshold
+	ProfileCaptureDir         string  `mapstructure:"profile_capture_dir"`
+	ProfileGoroutineThreshold int     `mapstructure:"profile_goroutine_threshold" validate:"min=0"`
+	ProfileHeapThresholdMB    float64 `mapstructure:"profile_heap_threshold_mb" validate:"min=0"`
+
+	// Market timezone for wall-clock scheduling (IANA name, e.g.
+	// Europe/Berlin); devices and storage stay on UTC
+	MarketTimezone string `mapstructure:"market_timezone" validate:"required,timezone"`
+
+	// Telemetry profile for bandwidth-limited sites (full, reduced or
+	// minimal); reduced profiles slow persistence and drop detail data
+	TelemetryProfile string `mapstructure:"telemetry_profile" validate:"required,oneof=full reduced minimal"`
+
+	// SOC calibration charge scheduling, window hours in market time
+	SOCCalibrationEnabled     bool          `mapstructure:"soc_calibration_enabled"`
+	SOCCalibrationInterval    time.Duration `mapstructure:"soc_calibration_interval"`
+	SOCCalibrationWindowStart int           `mapstructure:"soc_calibration_window_start" validate:"min=0,max=23"`
+	SOCCalibrationWindowEnd   int           `mapstructure:"soc_calibration_window_end" validate:"min=0,max=23"`
+	SOCCalibrationPower       float32       `mapstructure:"soc_calibration_power" validate:"min=0"`
+
+	// EMS-wide simulation switch: all control commands behave as dry-run
+	SimulationMode bool `mapstructure:"simulation_mode"`
+
+	// Chaos fault injection for engineering verification; never enable on a
+	// production site
+	ChaosEnabled bool `mapstructure:"chaos_enabled"`
+
+	// Two-man rule for breaker control and plant shutdown
+	TwoManRuleEnabled     bool          `mapstructure:"two_man_rule_enabled"`
+	TwoManApprovalTimeout time.Duration `mapstructure:"two_man_approval_timeout" validate:"min=0"`
+
+	// Select-before-operate on dangerous endpoints
+	SBOEnabled bool          `mapstructure:"sbo_enabled"`
+	SBOTimeout time.Duration `mapstructure:"sbo_timeout" validate:"min=0"`
+
+	// Per-stage loss model used to convert between DC-side and NCP power
+	LossModel LossModelConfig `mapstructure:"loss_model"`
+
+	// Feature flags gating risky new subsystems (all default to off)
+	FeatureFlags map[string]bool `mapstructure:"feature_flags"`
+}
+
+// ImportLimitWindowConfig is one daily window of the contractual import
+// limit at the connection point; a window whose end precedes its start
+// wraps over midnight
+type ImportLimitWindowConfig struct {
+	Start   string  `mapstructure:"start" json:"start" validate:"required,datetime=15:04"`
+	End     string  `mapstructure:"end" json:"end" validate:"required,datetime=15:04"`
+	LimitKW float32 `mapstructure:"limit_kw" json:"limit_kw" validate:"required,gt=0"`
+}
+
+// MarketLocation resolves the configured market timezone. The timezone is
+// validated at configuration load, so a resolution failure only happens when
+// the host is missing its timezone database; UTC is the fallback then.
+func (c EMSConfig) MarketLocation() *time.Location {
+	location, err := time.LoadLocation(c.MarketTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// FCRConfig contains frequency containment reserve configuration
+type FCRConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// High-resolution control loop cadence; FCR-D dynamics need 100 ms or
+	// faster
+	LoopInterval time.Duration `mapstructure:"loop_interval" validate:"min=0"`
+
+	// Dedicated frequency transducer; when disabled or unavailable the PCS
+	// grid frequency is used instead
+	FrequencyMeter FrequencyMeterConfig `mapstructure:"frequency_meter"`
+
+	// Output shaping of the activated power, tuned per FCR product
+	Shaping FCRShapingConfig `mapstructure:"shaping"`
+
+	// Product whose activation parameters drive the control loop
+	Product string `mapstructure:"product"`
+
+	// Activation parameters keyed by product name; different synchronous
+	// areas prescribe different bands and limits
+	Products map[string]FCRProductConfig `mapstructure:"products" validate:"dive"`
+
+	// Self-monitoring of delivered versus expected activation
+	Delivery FCRDeliveryConfig `mapstructure:"delivery"`
+
+	// Proactive endurance recovery for limited energy reservoirs
+	Recovery FCRRecoveryConfig `mapstructure:"recovery"`
+
+	// Independent PCS/BMS groups sharing the FCR provision; when empty the
+	// whole plant acts as a single group
+	Groups []FCRGroupConfig `mapstructure:"groups" validate:"dive"`
+
+	// SOC operating window the recovery planner keeps the reservoir in
+	SOCWindow FCRSOCWindowConfig `mapstructure:"soc_window"`
+}
+
+// FCRSOCWindowConfig bounds the SOC operating window for FCR provision: the
+// recovery planner schedules recovery energy when the true SOC leaves the
+// window and holds it until the SOC is back inside by the recover margin
+type FCRSOCWindowConfig struct {
+	Min           float32 `mapstructure:"min" validate:"min=0,max=100"`
+	Max           float32 `mapstructure:"max" validate:"min=0,max=100"`
+	RecoverMargin float32 `mapstructure:"recover_margin" validate:"min=0"`
+
+	// ClampForTest substitutes the window midpoint for the measured SOC
+	// whenever it leaves the window, which disables the SOC-triggered
+	// recovery entirely. Only meant for simulation runs; never enable it
+	// in production.
+	ClampForTest bool `mapstructure:"clamp_for_test"`
+}
+
+// AFRRConfig configures automatic frequency restoration reserve delivery:
+// the TSO sends explicit power setpoints over the SCADA link and the BESS
+// ramps to follow them within the gradient limit
+type AFRRConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// Dispatch loop cadence; aFRR full activation time is minutes, so one
+	// second is ample
+	LoopInterval time.Duration `mapstructure:"loop_interval" validate:"min=0"`
+
+	// Gradient limit for following the setpoint; 0 follows immediately
+	MaxRampRateKWPerMin float32 `mapstructure:"max_ramp_rate_kw_per_min" validate:"min=0"`
+
+	// Contracted capacity per direction
+	CapacityUpKW   float32 `mapstructure:"capacity_up_kw" validate:"min=0"`
+	CapacityDownKW float32 `mapstructure:"capacity_down_kw" validate:"min=0"`
+
+	// A setpoint older than this ramps back to zero, so a dead TSO link
+	// cannot hold an activation
+	SetpointTimeout time.Duration `mapstructure:"setpoint_timeout" validate:"min=0"`
+}
+
+// SelfTestConfig configures the nightly control-chain self-test: a small,
+// bounded power pulse is driven through the regular command chain on one PCS
+// per night and the measured grid response must follow within tolerance,
+// catching a broken control path before the next reserve activation needs it
+type SelfTestConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// Wall-clock start time in the market timezone
+	Time string `mapstructure:"time" validate:"omitempty,datetime=15:04"`
+
+	// Magnitude of the test pulse; the direction alternates between runs
+	// so the net energy stays near zero
+	PulsePowerKW float32 `mapstructure:"pulse_power_kw" validate:"min=0"`
+
+	// How long the pulse is held while the response is sampled
+	PulseDuration time.Duration `mapstructure:"pulse_duration" validate:"min=0"`
+
+	// Ramp and measurement delay before sampling starts
+	SettleTime time.Duration `mapstructure:"settle_time" validate:"min=0"`
+
+	// Accepted deviation between the commanded and the measured power
+	// change
+	ToleranceKW float32 `mapstructure:"tolerance_kw" validate:"min=0"`
+}
+
+// FCRGroupConfig describes one independent PCS/BMS group of an FCR
+// portfolio and its share of the provided capacity
+type FCRGroupConfig struct {
+	Name   string  `mapstructure:"name" validate:"required"`
+	PCSIDs []int   `mapstructure:"pcs_ids" validate:"required,min=1,dive,min=1"`
+	BMSIDs []int   `mapstructure:"bms_ids" validate:"required,min=1,dive,min=1"`
+	Share  float32 `mapstructure:"share" validate:"required,gt=0,max=1"`
+}
+
+// FCRRecoveryConfig configures the endurance recovery planner: when the
+// endurance of the reservoir drops toward the LER limit, recovery energy is
+// scheduled as a baseline through the scheduler setpoint source instead of
+// relying on normal-state energy management alone.
+type FCRRecoveryConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	CheckInterval   time.Duration `mapstructure:"check_interval" validate:"min=0"`
+	EnduranceLimit  time.Duration `mapstructure:"endurance_limit" validate:"min=0"`
+	TargetEndurance time.Duration `mapstructure:"target_endurance" validate:"min=0"`
+	BaselinePower   float32       `mapstructure:"baseline_power" validate:"min=0"` // kW
+}
+
+// FCRDeliveryConfig configures the FCR non-delivery self-monitoring: the
+// delivered PCS power is compared against the expected activation every
+// check interval, and a deviation beyond the tolerance sustained for the
+// grace period raises a non-delivery alarm. Capacity derating additionally
+// reduces the reported maintained capacity while the deviation persists.
+type FCRDeliveryConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	CheckInterval    time.Duration `mapstructure:"check_interval" validate:"min=0"`
+	ToleranceKW      float32       `mapstructure:"tolerance_kw" validate:"min=0"`
+	GracePeriod      time.Duration `mapstructure:"grace_period" validate:"min=0"`
+	CapacityDerating bool          `mapstructure:"capacity_derating"`
+}
+
+// FCRProductConfig holds the activation parameters of one FCR product:
+// frequency deviations within the deadband produce no activation, beyond it
+// the activation grows linearly until the full activation band, saturating
+// at the maximum power. A zero maximum power disables activation.
+type FCRProductConfig struct {
+	NominalFrequency float32 `mapstructure:"nominal_frequency" validate:"min=0"` // Hz
+	Deadband         float32 `mapstructure:"deadband" validate:"min=0"`          // Hz
+	ActivationBand   float32 `mapstructure:"activation_band" validate:"min=0"`   // Hz at full activation
+	MaxPower         float32 `mapstructure:"max_power" validate:"min=0"`         // kW
+}
+
+// FCRShapingConfig shapes the activated FCR power before it is commanded:
+// an optional first-order filter and an optional ramp-rate limit, to mimic
+// the required dynamic behavior without exciting PCS oscillations. Zero
+// values disable the respective stage.
+type FCRShapingConfig struct {
+	FilterTimeConstant time.Duration `mapstructure:"filter_time_constant" validate:"min=0"`
+	MaxRampRate        float32       `mapstructure:"max_ramp_rate" validate:"min=0"` // kW/s
+}
+
+// FrequencyMeterConfig describes a dedicated high-accuracy frequency
+// transducer read over Modbus TCP. The frequency in Hz is computed as
+// register value x scale + offset.
+type FrequencyMeterConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Host           string        `mapstructure:"host"`
+	BindAddress    string        `mapstructure:"bind_address" validate:"omitempty,ip"`
+	TLS            TLSConfig     `mapstructure:"tls"`
+	Port           int           `mapstructure:"port" validate:"min=0,max=65535"`
+	SlaveID        byte          `mapstructure:"slave_id"`
+	Register       uint16        `mapstructure:"register"`
+	Scale          float32       `mapstructure:"scale" validate:"min=0"`
+	Offset         float32       `mapstructure:"offset"`
+	SampleInterval time.Duration `mapstructure:"sample_interval" validate:"min=0"`
+	StaleAfter     time.Duration `mapstructure:"stale_after" validate:"min=0"`
+	Timeout        time.Duration `mapstructure:"timeout" validate:"min=0"`
+	ReconnectDelay time.Duration `mapstructure:"reconnect_delay" validate:"min=0"`
+}
+
+// EfficiencyPoint is one point of a piecewise-linear efficiency curve,
+// ordered by ascending load percentage
+type EfficiencyPoint struct {
+	LoadPercent float32 `mapstructure:"load_percent" validate:"min=0,max=100"`
+	Efficiency  float32 `mapstructure:"efficiency" validate:"gt=0,max=1"`
+}
+
+// LossModelConfig contains the efficiency curves for each conversion stage
+// between the battery DC side and the network connection point. An empty
+// curve falls back to a built-in flat efficiency.
+type LossModelConfig struct {
+	PCSCurve         []EfficiencyPoint `mapstructure:"pcs_curve" validate:"dive"`
+	TransformerCurve []EfficiencyPoint `mapstructure:"transformer_curve" validate:"dive"`
+	AuxiliaryPower   float32           `mapstructure:"auxiliary_power" validate:"min=0"`
 }
 
 // AlarmConfig contains alarm processing configuration
 type AlarmConfig struct {
 	QueueBufferSize int `mapstructure:"queue_buffer_size" validate:"min=1,max=10000"`
+
+	// Digest mode summarizes LOW/MEDIUM alarms per interval instead of
+	// notifying each one immediately; HIGH alarms always notify immediately
+	DigestEnabled  bool          `mapstructure:"digest_enabled"`
+	DigestInterval time.Duration `mapstructure:"digest_interval" validate:"min=0"`
+
+	// Escalation notifies the next contact in the chain each time a HIGH
+	// alarm stays unacknowledged for another escalation timeout
+	EscalationEnabled  bool          `mapstructure:"escalation_enabled"`
+	EscalationTimeout  time.Duration `mapstructure:"escalation_timeout" validate:"min=0"`
+	EscalationContacts []string      `mapstructure:"escalation_contacts"`
+
+	// Incident management connector for HIGH severity alarms
+	Incidents IncidentConfig `mapstructure:"incidents"`
+
+	// Notification channels with per-severity routing
+	Notifiers []NotifierConfig `mapstructure:"notifiers" validate:"omitempty,dive"`
+}
+
+// IncidentConfig configures the incident management connector: HIGH alarms
+// open an incident keyed by alarm type and code, and the clear auto-resolves
+// it. The API key is the PagerDuty routing key or the Opsgenie GenieKey.
+type IncidentConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Provider string        `mapstructure:"provider" validate:"omitempty,oneof=pagerduty opsgenie"`
+	APIKey   string        `mapstructure:"api_key"`
+	Endpoint string        `mapstructure:"endpoint" validate:"omitempty,url"`
+	Timeout  time.Duration `mapstructure:"timeout" validate:"min=0"`
+}
+
+// NotifierConfig configures one alarm notification channel. The severities
+// list routes alarms onto the channel; an empty list receives every severity.
+// Only the fields of the configured type are used.
+type NotifierConfig struct {
+	Name       string   `mapstructure:"name" validate:"required"`
+	Type       string   `mapstructure:"type" validate:"required,oneof=email webhook sms"`
+	Severities []string `mapstructure:"severities" validate:"omitempty,dive,oneof=LOW MEDIUM HIGH"`
+
+	// Email: alarms are mailed through an SMTP relay
+	SMTPHost string   `mapstructure:"smtp_host"`
+	SMTPPort int      `mapstructure:"smtp_port" validate:"min=0,max=65535"`
+	From     string   `mapstructure:"from"`
+	To       []string `mapstructure:"to" validate:"omitempty,dive,email"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+
+	// Webhook: alarms are POSTed as JSON; the auth token is sent as a
+	// bearer token when set
+	URL       string        `mapstructure:"url" validate:"omitempty,url"`
+	AuthToken string        `mapstructure:"auth_token"`
+	Timeout   time.Duration `mapstructure:"timeout" validate:"min=0"`
+
+	// SMS: alarms are texted through the Twilio messages API; the auth
+	// token field doubles as the Twilio API secret
+	AccountSID string   `mapstructure:"account_sid"`
+	FromNumber string   `mapstructure:"from_number"`
+	ToNumbers  []string `mapstructure:"to_numbers"`
 }
 
 // InfluxDBConfig contains InfluxDB-specific configuration
@@ -113,6 +987,11 @@ type InfluxDBConfig struct {
 	Bucket        string        `mapstructure:"bucket" validate:"required"`
 	BatchSize     uint          `mapstructure:"batch_size" validate:"required,min=1"`
 	FlushInterval time.Duration `mapstructure:"flush_interval" validate:"required"`
+
+	// In-process 1-minute aggregates written as <measurement>_1m; raw
+	// persistence can be disabled per measurement on constrained sites
+	AggregationEnabled bool     `mapstructure:"aggregation_enabled"`
+	RawDisabled        []string `mapstructure:"raw_disabled"`
 }
 
 // PostgreSQLConfig contains PostgreSQL-specific configuration
@@ -133,6 +1012,7 @@ type ModbusServerConfig struct {
 	Port       int           `mapstructure:"port" validate:"required,min=1,max=65535"`
 	Timeout    time.Duration `mapstructure:"timeout" validate:"required"`
 	MaxClients uint          `mapstructure:"max_clients" validate:"required,min=1,max=100"`
+	TLS        TLSConfig     `mapstructure:"tls"`
 }
 
 // LoggingConfig contains logging configuration
@@ -166,6 +1046,11 @@ func NewConfig(validate *validator.Validate) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	// Expand device templates into full per-unit entries
+	if err := expandDeviceTemplates(v); err != nil {
+		return nil, fmt.Errorf("failed to expand device templates: %w", err)
+	}
+
 	// Unmarshal configuration
 	var config Config
 	if err := v.Unmarshal(&config); err != nil {
@@ -177,9 +1062,103 @@ func NewConfig(validate *validator.Validate) (*Config, error) {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
+	// Propagate and verify the dual-network interface bindings
+	config.applyNetworkBindings()
+	if err := config.verifyNetworkBindings(); err != nil {
+		return nil, fmt.Errorf("network binding check failed: %w", err)
+	}
+
+	// Verify the BMS-PCS topology against the configured devices
+	if err := config.verifyTopology(); err != nil {
+		return nil, fmt.Errorf("topology check failed: %w", err)
+	}
+
+	config.filePath = v.ConfigFileUsed()
+
 	return &config, nil
 }
 
+// applyNetworkBindings propagates the OT bind address to every device
+// connection without an explicit bind address of its own
+func (c *Config) applyNetworkBindings() {
+	if c.Network.OTBindAddress == "" {
+		return
+	}
+
+	for i := range c.PCS {
+		if c.PCS[i].BindAddress == "" {
+			c.PCS[i].BindAddress = c.Network.OTBindAddress
+		}
+	}
+	for i := range c.BMS {
+		if c.BMS[i].BindAddress == "" {
+			c.BMS[i].BindAddress = c.Network.OTBindAddress
+		}
+	}
+	for i := range c.PLC {
+		if c.PLC[i].BindAddress == "" {
+			c.PLC[i].BindAddress = c.Network.OTBindAddress
+		}
+	}
+	for i := range c.WindFarm {
+		if c.WindFarm[i].BindAddress == "" {
+			c.WindFarm[i].BindAddress = c.Network.OTBindAddress
+		}
+	}
+	if c.ION7400.BindAddress == "" {
+		c.ION7400.BindAddress = c.Network.OTBindAddress
+	}
+	if c.FCR.FrequencyMeter.BindAddress == "" {
+		c.FCR.FrequencyMeter.BindAddress = c.Network.OTBindAddress
+	}
+}
+
+// verifyNetworkBindings checks at startup that every configured bind address
+// is assigned to a local interface, so a misrouted NIC configuration fails
+// fast instead of producing unreachable devices
+func (c *Config) verifyNetworkBindings() error {
+	addresses := []string{c.Network.OTBindAddress, c.Network.ITBindAddress}
+	for _, pcsCfg := range c.PCS {
+		addresses = append(addresses, pcsCfg.BindAddress)
+	}
+	for _, bmsCfg := range c.BMS {
+		addresses = append(addresses, bmsCfg.BindAddress)
+	}
+	for _, plcCfg := range c.PLC {
+		addresses = append(addresses, plcCfg.BindAddress)
+	}
+	for _, windFarmCfg := range c.WindFarm {
+		addresses = append(addresses, windFarmCfg.BindAddress)
+	}
+	addresses = append(addresses, c.ION7400.BindAddress, c.FCR.FrequencyMeter.BindAddress)
+
+	interfaceAddrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return fmt.Errorf("failed to list interface addresses: %w", err)
+	}
+
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+		if !isLocalAddress(address, interfaceAddrs) {
+			return fmt.Errorf("bind address %s is not assigned to any local interface", address)
+		}
+	}
+	return nil
+}
+
+// isLocalAddress reports whether the IP is assigned to a local interface
+func isLocalAddress(address string, interfaceAddrs []net.Addr) bool {
+	ip := net.ParseIP(address)
+	for _, interfaceAddr := range interfaceAddrs {
+		if ipNet, ok := interfaceAddr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // setDefaults sets default configuration values
 func setDefaults(v *viper.Viper) {
 	// ION7400 analyzer defaults
@@ -194,13 +1173,86 @@ func setDefaults(v *viper.Viper) {
 	// EMS defaults
 	v.SetDefault("ems.park_name", "polat-ege-res")
 	v.SetDefault("ems.http_port", 8080)
+	v.SetDefault("ems.http_request_timeout", 15*time.Second)
+	v.SetDefault("ems.http_slow_request_timeout", 2*time.Minute)
+	v.SetDefault("ems.idempotency_window", 5*time.Minute)
 	v.SetDefault("ems.max_soc", 90.0)
 	v.SetDefault("ems.min_soc", 10.0)
 	v.SetDefault("ems.max_charge_power", 100.0)
 	v.SetDefault("ems.max_discharge_power", 100.0)
+	v.SetDefault("ems.max_site_export_power", 0.0)
+	v.SetDefault("ems.max_site_import_power", 0.0)
+	v.SetDefault("ems.market_timezone", "Europe/Berlin")
+	v.SetDefault("ems.telemetry_profile", "full")
+	v.SetDefault("ems.soc_calibration_enabled", false)
+	v.SetDefault("ems.soc_calibration_interval", 720*time.Hour)
+	v.SetDefault("ems.soc_calibration_window_start", 1)
+	v.SetDefault("ems.soc_calibration_window_end", 5)
+	v.SetDefault("ems.soc_calibration_power", 100.0)
+	v.SetDefault("ems.resync_grid_healthy_time", 60*time.Second)
+	v.SetDefault("ems.resync_ramp_rate", 500.0)
+	v.SetDefault("ems.profile_capture_dir", "profiles")
+	v.SetDefault("ems.profile_goroutine_threshold", 2000)
+	v.SetDefault("ems.profile_heap_threshold_mb", 1024.0)
+	v.SetDefault("ems.loss_model.auxiliary_power", 0.0)
+	v.SetDefault("fcr.enabled", false)
+	v.SetDefault("fcr.loop_interval", 100*time.Millisecond)
+	v.SetDefault("fcr.shaping.filter_time_constant", time.Duration(0))
+	v.SetDefault("fcr.shaping.max_ramp_rate", 0.0)
+	v.SetDefault("fcr.product", "fcr-n")
+	v.SetDefault("fcr.products.fcr-n.nominal_frequency", 50.0)
+	v.SetDefault("fcr.products.fcr-n.deadband", 0.0)
+	v.SetDefault("fcr.products.fcr-n.activation_band", 0.1)
+	v.SetDefault("fcr.products.fcr-n.max_power", 0.0)
+	v.SetDefault("fcr.delivery.enabled", false)
+	v.SetDefault("fcr.delivery.check_interval", time.Second)
+	v.SetDefault("fcr.delivery.tolerance_kw", 50.0)
+	v.SetDefault("fcr.delivery.grace_period", 30*time.Second)
+	v.SetDefault("fcr.delivery.capacity_derating", false)
+	v.SetDefault("fcr.recovery.enabled", false)
+	v.SetDefault("fcr.recovery.check_interval", time.Minute)
+	v.SetDefault("fcr.recovery.endurance_limit", time.Hour)
+	v.SetDefault("fcr.recovery.target_endurance", 2*time.Hour)
+	v.SetDefault("fcr.recovery.baseline_power", 0.0)
+	v.SetDefault("fcr.soc_window.min", 40.0)
+	v.SetDefault("fcr.soc_window.max", 60.0)
+	v.SetDefault("fcr.soc_window.recover_margin", 5.0)
+	v.SetDefault("fcr.soc_window.clamp_for_test", false)
+	v.SetDefault("fcr.frequency_meter.enabled", false)
+	v.SetDefault("fcr.frequency_meter.port", 502)
+	v.SetDefault("fcr.frequency_meter.slave_id", 1)
+	v.SetDefault("fcr.frequency_meter.scale", 0.001)
+	v.SetDefault("fcr.frequency_meter.offset", 0.0)
+	v.SetDefault("fcr.frequency_meter.sample_interval", 50*time.Millisecond)
+	v.SetDefault("fcr.frequency_meter.stale_after", 500*time.Millisecond)
+	v.SetDefault("fcr.frequency_meter.timeout", time.Second)
+	v.SetDefault("fcr.frequency_meter.reconnect_delay", time.Second)
+	v.SetDefault("afrr.enabled", false)
+	v.SetDefault("afrr.loop_interval", time.Second)
+	v.SetDefault("afrr.max_ramp_rate_kw_per_min", 0.0)
+	v.SetDefault("afrr.setpoint_timeout", 30*time.Second)
+	v.SetDefault("selftest.enabled", false)
+	v.SetDefault("selftest.time", "03:30")
+	v.SetDefault("selftest.pulse_power_kw", 50.0)
+	v.SetDefault("selftest.pulse_duration", 30*time.Second)
+	v.SetDefault("selftest.settle_time", 5*time.Second)
+	v.SetDefault("selftest.tolerance_kw", 25.0)
+	v.SetDefault("ems.simulation_mode", false)
+	v.SetDefault("ems.chaos_enabled", false)
+	v.SetDefault("ems.two_man_rule_enabled", false)
+	v.SetDefault("ems.two_man_approval_timeout", 5*time.Minute)
+	v.SetDefault("ems.sbo_enabled", false)
+	v.SetDefault("ems.sbo_timeout", 30*time.Second)
 
 	// Alarm defaults
 	v.SetDefault("alarm.queue_buffer_size", 100)
+	v.SetDefault("alarm.digest_enabled", false)
+	v.SetDefault("alarm.digest_interval", time.Hour)
+	v.SetDefault("alarm.escalation_enabled", false)
+	v.SetDefault("alarm.escalation_timeout", 15*time.Minute)
+	v.SetDefault("alarm.incidents.enabled", false)
+	v.SetDefault("alarm.incidents.provider", "pagerduty")
+	v.SetDefault("alarm.incidents.timeout", 10*time.Second)
 
 	// InfluxDB defaults
 	v.SetDefault("influxdb.url", "http://localhost:8086")
@@ -209,6 +1261,54 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("influxdb.bucket", "ems_data")
 	v.SetDefault("influxdb.batch_size", 100)
 	v.SetDefault("influxdb.flush_interval", 5*time.Second)
+	v.SetDefault("influxdb.aggregation_enabled", false)
+	v.SetDefault("cmdb.enabled", false)
+	v.SetDefault("cmdb.timeout", 10*time.Second)
+	v.SetDefault("cmdb.refresh_interval", 24*time.Hour)
+	v.SetDefault("weather.enabled", false)
+	v.SetDefault("weather.timeout", 10*time.Second)
+	v.SetDefault("weather.refresh_interval", 10*time.Minute)
+	v.SetDefault("rules.enabled", false)
+	v.SetDefault("rules.eval_interval", time.Second)
+	v.SetDefault("rules.max_commands_per_minute", 6)
+	v.SetDefault("shadow.enabled", false)
+	v.SetDefault("shadow.strategy", "soc_proportional")
+	v.SetDefault("shadow.eval_interval", time.Second)
+	v.SetDefault("peer.enabled", false)
+	v.SetDefault("peer.listen_address", ":9433")
+	v.SetDefault("peer.exchange_interval", time.Second)
+	v.SetDefault("peer.timeout", 2*time.Second)
+
+	// MQTT telemetry publisher defaults
+	v.SetDefault("mqtt.enabled", false)
+	v.SetDefault("mqtt.client_id", "powerkonnekt-ems")
+	v.SetDefault("mqtt.base_topic", "powerkonnekt/ems")
+	v.SetDefault("mqtt.qos", 1)
+	v.SetDefault("mqtt.publish_interval", 10*time.Second)
+	v.SetDefault("mqtt.connect_timeout", 10*time.Second)
+	v.SetDefault("mqtt.reconnect_delay", 5*time.Second)
+
+	// Historian forwarding defaults
+	v.SetDefault("historian.enabled", false)
+	v.SetDefault("historian.flush_interval", 5*time.Second)
+	v.SetDefault("historian.buffer_size", 10000)
+	v.SetDefault("historian.pi.timeout", 10*time.Second)
+	v.SetDefault("historian.opcua.timeout", 10*time.Second)
+	v.SetDefault("historian.opcua.node_prefix", "EMS")
+
+	// Availability declaration defaults
+	v.SetDefault("declaration.enabled", false)
+	v.SetDefault("declaration.compile_hour", 6)
+	v.SetDefault("declaration.email.smtp_port", 25)
+	v.SetDefault("declaration.sftp.port", 22)
+	v.SetDefault("declaration.api.timeout", 30*time.Second)
+
+	v.SetDefault("auth.enabled", false)
+	v.SetDefault("auth.session_ttl", 12*time.Hour)
+	v.SetDefault("auth.password_min_length", 12)
+	v.SetDefault("auth.password_mixed_case", true)
+	v.SetDefault("auth.password_digit", true)
+	v.SetDefault("auth.oidc.username_claim", "preferred_username")
 
 	// PostgreSQL defaults
 	v.SetDefault("postgresql.host", "localhost")
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// deviceTemplateFamilies lists the device sections that support template
+// expansion; each family reads its template from the "<family>_template" key.
+var deviceTemplateFamilies = []string{"pcs", "bms", "plc", "windfarm"}
+
+// DeviceTemplateConfig describes a family of near-identical devices once
+// instead of repeating the full entry per unit. The base entry is expanded
+// count times with sequential IDs starting at start_id, and each unit may
+// override individual fields (typically host or slave ID) through the units
+// list. Expansion happens at load time before validation, so expanded
+// entries are validated exactly like hand-written ones and may be mixed
+// with an explicit device list as long as the IDs do not collide.
+type DeviceTemplateConfig struct {
+	Count   int              `mapstructure:"count"`
+	StartID int              `mapstructure:"start_id"`
+	Base    map[string]any   `mapstructure:"base"`
+	Units   []map[string]any `mapstructure:"units"`
+}
+
+// expandDeviceTemplates expands the per-family device templates into the
+// corresponding device lists before the configuration is unmarshalled
+func expandDeviceTemplates(v *viper.Viper) error {
+	for _, family := range deviceTemplateFamilies {
+		if err := expandDeviceTemplate(v, family); err != nil {
+			return fmt.Errorf("%s_template: %w", family, err)
+		}
+	}
+	return nil
+}
+
+// expandDeviceTemplate expands one family template and merges the result
+// with any explicitly listed entries of the same family
+func expandDeviceTemplate(v *viper.Viper, family string) error {
+	key := family + "_template"
+	if !v.IsSet(key) {
+		return nil
+	}
+
+	var template DeviceTemplateConfig
+	if err := v.UnmarshalKey(key, &template); err != nil {
+		return fmt.Errorf("failed to unmarshal template: %w", err)
+	}
+
+	if len(template.Base) == 0 {
+		return fmt.Errorf("base entry is required")
+	}
+	if _, ok := deviceEntryID(template.Base); ok {
+		return fmt.Errorf("id belongs in start_id or a unit override, not in base")
+	}
+
+	count := template.Count
+	if count == 0 {
+		count = len(template.Units)
+	}
+	if count < 1 {
+		return fmt.Errorf("count or units must define at least one unit")
+	}
+	if len(template.Units) > count {
+		return fmt.Errorf("%d unit overrides for %d units", len(template.Units), count)
+	}
+
+	startID := template.StartID
+	if startID == 0 {
+		startID = 1
+	}
+
+	entries, _ := v.Get(family).([]any)
+	for i := 0; i < count; i++ {
+		entry := make(map[string]any, len(template.Base)+1)
+		for field, value := range template.Base {
+			entry[field] = value
+		}
+		entry["id"] = startID + i
+		if i < len(template.Units) {
+			for field, value := range template.Units[i] {
+				entry[field] = value
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	// Reject ID collisions between expanded and explicit entries here;
+	// once merged into one list the validator cannot tell them apart
+	seen := make(map[int]bool, len(entries))
+	for _, entry := range entries {
+		id, ok := deviceEntryID(entry)
+		if !ok {
+			continue
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate %s id %d after expansion", family, id)
+		}
+		seen[id] = true
+	}
+
+	v.Set(family, entries)
+
+	return nil
+}
+
+// deviceEntryID reads the numeric id of one raw device entry, tolerating
+// the map and integer representations the YAML decoder produces
+func deviceEntryID(entry any) (int, bool) {
+	var raw any
+	switch fields := entry.(type) {
+	case map[string]any:
+		raw = fields["id"]
+	case map[any]any:
+		raw = fields["id"]
+	default:
+		return 0, false
+	}
+
+	switch id := raw.(type) {
+	case int:
+		return id, true
+	case int64:
+		return int(id), true
+	case float64:
+		return int(id), true
+	default:
+		return 0, false
+	}
+}
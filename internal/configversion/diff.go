@@ -0,0 +1,53 @@
+package configversion
+
+import (
+	"strings"
+)
+
+// diffLines produces a line-based diff between two configuration contents,
+// prefixing removed lines with "-" and added lines with "+". Unchanged lines
+// are omitted to keep stored diffs compact.
+func diffLines(previous, current string) string {
+	previousLines := strings.Split(previous, "\n")
+	currentLines := strings.Split(current, "\n")
+
+	// Longest common subsequence over lines; config files are small enough
+	// for the quadratic table
+	lcs := make([][]int, len(previousLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(currentLines)+1)
+	}
+	for i := len(previousLines) - 1; i >= 0; i-- {
+		for j := len(currentLines) - 1; j >= 0; j-- {
+			if previousLines[i] == currentLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var builder strings.Builder
+	i, j := 0, 0
+	for i < len(previousLines) && j < len(currentLines) {
+		switch {
+		case previousLines[i] == currentLines[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			builder.WriteString("-" + previousLines[i] + "\n")
+			i++
+		default:
+			builder.WriteString("+" + currentLines[j] + "\n")
+			j++
+		}
+	}
+	for ; i < len(previousLines); i++ {
+		builder.WriteString("-" + previousLines[i] + "\n")
+	}
+	for ; j < len(currentLines); j++ {
+		builder.WriteString("+" + currentLines[j] + "\n")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,34 @@
+package configversion
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+)
+
+// Module provides configuration version tracking to the Fx application
+var Module = fx.Module("configversion",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates the configuration version service
+func ProvideService(cfg *config.Config, postgreSQL *database.PostgreSQL, logger *zap.Logger) *Service {
+	return NewService(cfg, postgreSQL, logger)
+}
+
+// RegisterLifecycle captures the active configuration version on startup
+func RegisterLifecycle(lc fx.Lifecycle, service *Service, logger *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := service.RecordCurrent("startup"); err != nil {
+				logger.Error("Failed to record configuration version", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,145 @@
+// Package configversion persists every applied configuration version (hash,
+// timestamp, author, diff) in PostgreSQL and supports rolling the config
+// file back to a previous version, so there is a change history to consult
+// after an incident.
+package configversion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+)
+
+// Service records and rolls back configuration versions
+type Service struct {
+	config     *config.Config
+	postgreSQL *database.PostgreSQL
+	log        *zap.Logger
+
+	mutex sync.Mutex
+}
+
+// NewService creates a new configuration version service
+func NewService(cfg *config.Config, postgreSQL *database.PostgreSQL, logger *zap.Logger) *Service {
+	return &Service{
+		config:     cfg,
+		postgreSQL: postgreSQL,
+		log: logger.With(
+			zap.String("component", "configversion"),
+		),
+	}
+}
+
+// RecordCurrent captures the on-disk configuration as a new version if it
+// differs from the latest recorded one
+func (s *Service) RecordCurrent(author string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.recordCurrentLocked(author)
+}
+
+func (s *Service) recordCurrentLocked(author string) error {
+	path := s.config.FilePath()
+	if path == "" {
+		s.log.Warn("No configuration file in use, skipping version capture")
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	hash := sha256.Sum256(content)
+	hashHex := hex.EncodeToString(hash[:])
+
+	latest, err := s.postgreSQL.GetLatestConfigVersion()
+	if err != nil {
+		return err
+	}
+	if latest != nil && latest.Hash == hashHex {
+		return nil
+	}
+
+	diff := "(initial version)"
+	if latest != nil {
+		diff = diffLines(latest.Content, string(content))
+	}
+
+	record := &database.ConfigVersionRecord{
+		Hash:      hashHex,
+		AppliedAt: time.Now(),
+		Author:    author,
+		Content:   string(content),
+		Diff:      diff,
+	}
+
+	if err := s.postgreSQL.SaveConfigVersion(record); err != nil {
+		return err
+	}
+
+	s.log.Info("Recorded new configuration version",
+		zap.Uint("version_id", record.ID),
+		zap.String("hash", hashHex),
+		zap.String("author", author))
+
+	return nil
+}
+
+// ListVersions returns recorded configuration versions, newest first
+func (s *Service) ListVersions(limit int, offset int) ([]database.ConfigVersionRecord, error) {
+	return s.postgreSQL.GetConfigVersions(limit, offset)
+}
+
+// GetVersion returns one recorded configuration version with its content
+func (s *Service) GetVersion(id uint) (*database.ConfigVersionRecord, error) {
+	return s.postgreSQL.GetConfigVersion(id)
+}
+
+// Rollback writes a previously recorded version back to the config file and
+// records the rollback as a new version. The running service keeps its
+// current configuration until restart, since config is read at startup.
+func (s *Service) Rollback(id uint, author string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	path := s.config.FilePath()
+	if path == "" {
+		return fmt.Errorf("no configuration file in use, cannot roll back")
+	}
+
+	version, err := s.postgreSQL.GetConfigVersion(id)
+	if err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	currentHash := sha256.Sum256(current)
+	if hex.EncodeToString(currentHash[:]) == version.Hash {
+		return fmt.Errorf("version %d is already the active configuration", id)
+	}
+
+	if err := os.WriteFile(path, []byte(version.Content), 0o644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	s.log.Warn("Rolled configuration file back to a previous version",
+		zap.Uint("version_id", id),
+		zap.String("hash", version.Hash),
+		zap.String("author", author))
+
+	return s.recordCurrentLocked(fmt.Sprintf("%s (rollback to version %d)", author, id))
+}
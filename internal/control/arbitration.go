@@ -0,0 +1,186 @@
+package control
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SetpointSource identifies the origin of a PCS power setpoint
+type SetpointSource string
+
+const (
+	SourceSafety    SetpointSource = "SAFETY"
+	SourceFCR       SetpointSource = "FCR"
+	SourceAFRR      SetpointSource = "AFRR"
+	SourceSCADA     SetpointSource = "SCADA"
+	SourceScheduler SetpointSource = "SCHEDULER"
+	SourceRules     SetpointSource = "RULES"
+	SourceManual    SetpointSource = "MANUAL"
+	SourceSelfTest  SetpointSource = "SELFTEST"
+)
+
+// sourcePriority defines the override hierarchy; higher wins. FCR and aFRR
+// share the frequency-reserve tier: only one product is contracted for a
+// delivery period at a time.
+var sourcePriority = map[SetpointSource]int{
+	SourceSafety:    6,
+	SourceFCR:       5,
+	SourceAFRR:      5,
+	SourceSCADA:     4,
+	SourceScheduler: 3,
+	SourceRules:     2,
+	SourceManual:    1,
+
+	// The nightly self-test pulse shares the lowest tier: it must never
+	// displace an actual dispatch, only run when the PCS is idle
+	SourceSelfTest: 1,
+}
+
+// setpointHoldTimeout is how long a source retains ownership of a PCS after
+// its last command before lower-priority sources are accepted again
+const setpointHoldTimeout = 30 * time.Second
+
+// setpointOwner tracks which source currently commands a PCS
+type setpointOwner struct {
+	source   SetpointSource
+	lastSeen time.Time
+	power    float32
+}
+
+// ActiveSetpointSource describes the active command source of one PCS
+type ActiveSetpointSource struct {
+	PCSID      int            `json:"pcs_id"`
+	Source     SetpointSource `json:"source"`
+	PowerKW    float32        `json:"power_kw"`
+	AgeSeconds float64        `json:"age_seconds"`
+}
+
+// ArbitratePCSPowerCommand arbitrates a power command from the given source
+// against the current owner of the PCS. Commands from a lower-priority source
+// are rejected while a higher-priority source holds the PCS; ownership expires
+// after setpointHoldTimeout without commands. Accepted commands are clamped
+// by the site connection-point limits and the clamped power is returned.
+func (l *Logic) ArbitratePCSPowerCommand(pcsID int, source SetpointSource, power float32) (float32, error) {
+	priority, ok := sourcePriority[source]
+	if !ok {
+		return 0, fmt.Errorf("unknown setpoint source: %s", source)
+	}
+
+	// Local/remote ownership check comes before any priority arbitration
+	if err := l.checkControlOwnership(source); err != nil {
+		l.log.Warn("Power command rejected by control ownership",
+			zap.Int("pcs_id", pcsID),
+			zap.String("source", string(source)),
+			zap.Error(err))
+		return 0, err
+	}
+
+	// While the site is islanded only safety and manual commands are
+	// accepted; market-driven dispatch has no grid to trade against
+	if source != SourceSafety && source != SourceManual && l.GetGridState() == GridStateIslanded {
+		l.log.Warn("Power command rejected while islanded",
+			zap.Int("pcs_id", pcsID),
+			zap.String("source", string(source)),
+			zap.Float32("requested_power", power))
+		return 0, fmt.Errorf("site is islanded: %s dispatch is suspended", source)
+	}
+
+	l.mutex.Lock()
+	owner, held := l.setpointOwners[pcsID]
+	if held && owner.source != source &&
+		sourcePriority[owner.source] > priority &&
+		time.Since(owner.lastSeen) < setpointHoldTimeout {
+		ownerSource := owner.source
+		l.mutex.Unlock()
+
+		l.log.Warn("Power command rejected by setpoint arbitration",
+			zap.Int("pcs_id", pcsID),
+			zap.String("source", string(source)),
+			zap.String("active_source", string(ownerSource)),
+			zap.Float32("requested_power", power))
+		return 0, fmt.Errorf("PCS %d is controlled by higher-priority source %s", pcsID, ownerSource)
+	}
+
+	if !held {
+		owner = &setpointOwner{}
+		l.setpointOwners[pcsID] = owner
+	}
+	if owner.source != source && owner.source != "" {
+		l.log.Info("Setpoint source changed",
+			zap.Int("pcs_id", pcsID),
+			zap.String("old_source", string(owner.source)),
+			zap.String("new_source", string(source)))
+	}
+	owner.source = source
+	owner.lastSeen = time.Now()
+	l.mutex.Unlock()
+
+	clamped := l.ClampPCSPowerCommand(pcsID, power)
+	clamped = l.applyThroughputDerating(pcsID, clamped)
+	clamped = l.applyResyncRamp(pcsID, clamped)
+
+	l.mutex.Lock()
+	owner.power = clamped
+	l.mutex.Unlock()
+
+	return clamped, nil
+}
+
+// EvaluatePCSPowerCommand runs the same arbitration and limit pipeline as
+// ArbitratePCSPowerCommand but without claiming ownership, recording clamp
+// events or issuing anything to the device. It returns the power that would
+// have been sent, for dry-run evaluation.
+func (l *Logic) EvaluatePCSPowerCommand(pcsID int, source SetpointSource, power float32) (float32, error) {
+	priority, ok := sourcePriority[source]
+	if !ok {
+		return 0, fmt.Errorf("unknown setpoint source: %s", source)
+	}
+
+	if err := l.checkControlOwnership(source); err != nil {
+		return 0, err
+	}
+
+	l.mutex.RLock()
+	owner, held := l.setpointOwners[pcsID]
+	l.mutex.RUnlock()
+
+	if held && owner.source != source &&
+		sourcePriority[owner.source] > priority &&
+		time.Since(owner.lastSeen) < setpointHoldTimeout {
+		return 0, fmt.Errorf("PCS %d is controlled by higher-priority source %s", pcsID, owner.source)
+	}
+
+	clamped, _ := l.computeSiteClamp(pcsID, power)
+	return clamped * l.throughputDerating(pcsID), nil
+}
+
+// ReleasePCSSetpoint releases ownership of a PCS if it is held by the given
+// source, allowing lower-priority sources to command it immediately
+func (l *Logic) ReleasePCSSetpoint(pcsID int, source SetpointSource) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if owner, held := l.setpointOwners[pcsID]; held && owner.source == source {
+		delete(l.setpointOwners, pcsID)
+	}
+}
+
+// GetActiveSetpointSources returns the active command source per PCS
+func (l *Logic) GetActiveSetpointSources() []ActiveSetpointSource {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	sources := make([]ActiveSetpointSource, 0, len(l.setpointOwners))
+	for pcsID, owner := range l.setpointOwners {
+		sources = append(sources, ActiveSetpointSource{
+			PCSID:      pcsID,
+			Source:     owner.source,
+			PowerKW:    owner.power,
+			AgeSeconds: time.Since(owner.lastSeen).Seconds(),
+		})
+	}
+
+	return sources
+}
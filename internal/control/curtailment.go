@@ -0,0 +1,244 @@
+package control
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// CurtailmentAlarmCode identifies the alarm raised while a TSO/DSO
+// curtailment order is active
+const CurtailmentAlarmCode = 9800
+
+// curtailmentSampleGap is the longest interval integrated into the
+// curtailed-energy account in one control cycle, so a stalled control loop
+// cannot attribute its whole outage to the curtailment
+const curtailmentSampleGap = 10 * time.Second
+
+// Curtailment is a TSO/DSO-instructed limitation of the site export power,
+// valid for a bounded time window. While the window is active the order
+// tightens the connection-point export limit, which the existing
+// enforcement chain then carries to every asset: command clamping keeps
+// BESS setpoints below the limit (with wind and PV counted as site power),
+// and a sustained physical breach asserts the rapid downward signal to
+// bring the turbines themselves down.
+type Curtailment struct {
+	LimitKW   float32   `json:"limit_kw"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Reference string    `json:"reference,omitempty"` // Instruction reference of the system operator
+	SetBy     string    `json:"set_by,omitempty"`
+	SetAt     time.Time `json:"set_at"`
+}
+
+// CurtailmentStatus describes the curtailment state and the curtailed-energy
+// account of the current (or most recent) order
+type CurtailmentStatus struct {
+	Active             bool         `json:"active"`
+	Curtailment        *Curtailment `json:"curtailment,omitempty"`
+	CurtailedEnergyKWh float64      `json:"curtailed_energy_kwh"`
+}
+
+// SetCurtailment accepts a curtailment order for the given validity window.
+// An order that is already active is replaced, matching how system
+// operators update a running instruction. The curtailed-energy account
+// restarts with the new order.
+func (l *Logic) SetCurtailment(limitKW float32, start, end time.Time, reference, setBy string) (Curtailment, error) {
+	now := time.Now()
+
+	if limitKW < 0 {
+		return Curtailment{}, fmt.Errorf("curtailment limit must not be negative")
+	}
+	if start.IsZero() {
+		start = now
+	}
+	if !end.After(start) {
+		return Curtailment{}, fmt.Errorf("curtailment end time must be after the start time")
+	}
+	if end.Before(now) {
+		return Curtailment{}, fmt.Errorf("curtailment window has already expired")
+	}
+	if limit := l.config.MaxSiteExportPower; limit > 0 && limitKW >= limit {
+		return Curtailment{}, fmt.Errorf("curtailment limit %.1f kW does not reduce the configured export limit %.1f kW", limitKW, limit)
+	}
+
+	order := Curtailment{
+		LimitKW:   limitKW,
+		StartTime: start,
+		EndTime:   end,
+		Reference: reference,
+		SetBy:     setBy,
+		SetAt:     now,
+	}
+
+	sampleAt := now
+	if start.After(now) {
+		sampleAt = start
+	}
+
+	l.mutex.Lock()
+	replaced := l.curtailment
+	l.curtailment = &order
+	l.curtailedEnergyKWh = 0
+	l.curtailmentSampleAt = sampleAt
+	l.mutex.Unlock()
+
+	if replaced != nil {
+		l.log.Info("Previous curtailment order replaced",
+			zap.String("reference", replaced.Reference),
+			zap.Float32("limit_kw", replaced.LimitKW))
+	}
+
+	l.log.Warn("Curtailment order accepted",
+		zap.Float32("limit_kw", order.LimitKW),
+		zap.Time("start_time", order.StartTime),
+		zap.Time("end_time", order.EndTime),
+		zap.String("reference", order.Reference),
+		zap.String("set_by", order.SetBy))
+
+	l.submitCurtailmentAlarm(order, true)
+	return order, nil
+}
+
+// ClearCurtailment releases a curtailment order before its expiry
+func (l *Logic) ClearCurtailment() error {
+	l.mutex.Lock()
+	order := l.curtailment
+	if order == nil {
+		l.mutex.Unlock()
+		return fmt.Errorf("no curtailment order is active")
+	}
+	l.curtailment = nil
+	total := l.curtailedEnergyKWh
+	l.mutex.Unlock()
+
+	l.log.Info("Curtailment order cleared",
+		zap.String("reference", order.Reference),
+		zap.Float32("limit_kw", order.LimitKW),
+		zap.Float64("curtailed_energy_kwh", total))
+
+	l.submitCurtailmentAlarm(*order, false)
+	return nil
+}
+
+// GetCurtailmentStatus returns the curtailment state and energy account
+func (l *Logic) GetCurtailmentStatus() CurtailmentStatus {
+	now := time.Now()
+
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	status := CurtailmentStatus{
+		Curtailment:        l.curtailment,
+		CurtailedEnergyKWh: l.curtailedEnergyKWh,
+	}
+	if order := l.curtailment; order != nil {
+		status.Active = !now.Before(order.StartTime) && !now.After(order.EndTime)
+	}
+	return status
+}
+
+// effectiveExportLimit returns the connection-point export limit with an
+// active curtailment order and the peer export allocation applied; this is
+// the limit every enforcement path (command clamping, rapid downward
+// trigger, status reporting) works against
+func (l *Logic) effectiveExportLimit() float32 {
+	limit := l.config.MaxSiteExportPower
+
+	l.mutex.RLock()
+	order := l.curtailment
+	peerSet := l.peerExportLimitSet
+	peerLimit := l.peerExportLimitKW
+	l.mutex.RUnlock()
+
+	if peerSet && (limit <= 0 || peerLimit < limit) {
+		limit = peerLimit
+	}
+
+	if order == nil {
+		return limit
+	}
+
+	now := time.Now()
+	if now.Before(order.StartTime) || now.After(order.EndTime) {
+		return limit
+	}
+	if limit <= 0 || order.LimitKW < limit {
+		return order.LimitKW
+	}
+	return limit
+}
+
+// superviseCurtailment releases expired orders and integrates the curtailed
+// energy while an order is active. Runs in every control cycle regardless
+// of mode because a curtailment order is binding.
+func (l *Logic) superviseCurtailment() {
+	now := time.Now()
+
+	l.mutex.Lock()
+	order := l.curtailment
+	if order == nil {
+		l.mutex.Unlock()
+		return
+	}
+
+	if now.After(order.EndTime) {
+		l.curtailment = nil
+		total := l.curtailedEnergyKWh
+		l.mutex.Unlock()
+
+		l.log.Info("Curtailment order expired",
+			zap.String("reference", order.Reference),
+			zap.Float32("limit_kw", order.LimitKW),
+			zap.Float64("curtailed_energy_kwh", total))
+
+		l.submitCurtailmentAlarm(*order, false)
+		return
+	}
+
+	if now.Before(order.StartTime) {
+		l.mutex.Unlock()
+		return
+	}
+
+	dt := now.Sub(l.curtailmentSampleAt)
+	l.curtailmentSampleAt = now
+	l.mutex.Unlock()
+
+	if dt <= 0 || dt > curtailmentSampleGap {
+		return
+	}
+
+	// The compensation claim is based on the production the turbines report
+	// as possible but do not deliver while the order is active. This follows
+	// the usual settlement basis for instructed curtailment of wind; denied
+	// BESS discharge is not compensated.
+	shortfallKW := l.windFarmManager.GetTotalPossiblePower()*1000.0 - l.windPowerKW()
+	if shortfallKW <= 0 {
+		return
+	}
+
+	l.mutex.Lock()
+	l.curtailedEnergyKWh += float64(shortfallKW) * dt.Hours()
+	l.mutex.Unlock()
+}
+
+// submitCurtailmentAlarm records the order in the alarm system so operators
+// see the active limitation and the alarm history keeps the window
+func (l *Logic) submitCurtailmentAlarm(order Curtailment, active bool) {
+	if l.alarmManager == nil {
+		return
+	}
+
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: CurtailmentAlarmCode,
+		Message:   fmt.Sprintf("Site export curtailed to %.0f kW by system operator instruction %s", order.LimitKW, order.Reference),
+		Severity:  "MEDIUM",
+		Active:    active,
+	})
+}
@@ -0,0 +1,142 @@
+package control
+
+import (
+	"go.uber.org/zap"
+)
+
+// pcsDispatchCapacity summarizes the dispatchable state of one PCS and its
+// paired BMS strings for the site power split
+type pcsDispatchCapacity struct {
+	headroomKW float32 // Available power in the commanded direction
+	socMargin  float32 // Mean SOC distance to the limiting end of the window
+}
+
+// DispatchShares splits a total site active power command across the given
+// PCS units proportional to available headroom and SOC, so strings with
+// higher SOC carry more of a discharge and less of a charge and the fleet
+// converges toward equal SOC. Each share is clamped to the headroom of its
+// unit; power that no unit can take remains unplaced and the shares sum to
+// less than the request.
+func (l *Logic) DispatchShares(total float32, pcsIDs []int) map[int]float32 {
+	shares := make(map[int]float32, len(pcsIDs))
+	for _, pcsID := range pcsIDs {
+		shares[pcsID] = 0
+	}
+	if total == 0 || len(pcsIDs) == 0 {
+		return shares
+	}
+
+	capacities := make(map[int]pcsDispatchCapacity, len(pcsIDs))
+	var totalHeadroom float32
+	for _, pcsID := range pcsIDs {
+		capacity := l.dispatchCapacity(pcsID, total > 0)
+		capacities[pcsID] = capacity
+		totalHeadroom += capacity.headroomKW
+	}
+	if totalHeadroom <= 0 {
+		return shares
+	}
+
+	// Weight each unit by headroom scaled with its SOC margin, so a string
+	// far from the limiting end of its SOC window takes a larger share
+	var totalWeight float32
+	weights := make(map[int]float32, len(pcsIDs))
+	for _, pcsID := range pcsIDs {
+		weight := capacities[pcsID].headroomKW * capacities[pcsID].socMargin
+		weights[pcsID] = weight
+		totalWeight += weight
+	}
+	if totalWeight <= 0 {
+		// Every string sits at its SOC limit; fall back to plain headroom
+		for _, pcsID := range pcsIDs {
+			weights[pcsID] = capacities[pcsID].headroomKW
+		}
+		totalWeight = totalHeadroom
+	}
+
+	magnitude := abs(total)
+	sign := float32(1)
+	if total < 0 {
+		sign = -1
+	}
+
+	// Place the proportional shares, clamp to headroom and redistribute the
+	// remainder over the units that still have room
+	remaining := magnitude
+	for iteration := 0; iteration < len(pcsIDs) && remaining > dispatchRemainderKW; iteration++ {
+		var openWeight float32
+		for _, pcsID := range pcsIDs {
+			if abs(shares[pcsID]) < capacities[pcsID].headroomKW {
+				openWeight += weights[pcsID]
+			}
+		}
+		if openWeight <= 0 {
+			break
+		}
+
+		placed := float32(0)
+		for _, pcsID := range pcsIDs {
+			room := capacities[pcsID].headroomKW - abs(shares[pcsID])
+			if room <= 0 {
+				continue
+			}
+			share := remaining * weights[pcsID] / openWeight
+			if share > room {
+				share = room
+			}
+			shares[pcsID] += sign * share
+			placed += share
+		}
+		remaining -= placed
+	}
+
+	if remaining > dispatchRemainderKW {
+		l.log.Warn("Site power command exceeds fleet headroom",
+			zap.Float32("requested_power", total),
+			zap.Float32("unplaced_power", sign*remaining))
+	}
+
+	return shares
+}
+
+// dispatchRemainderKW is the unplaced power below which the share
+// redistribution stops
+const dispatchRemainderKW = 0.1
+
+// dispatchCapacity evaluates the headroom and SOC position of one PCS over
+// its paired BMS strings; disconnected strings contribute nothing
+func (l *Logic) dispatchCapacity(pcsID int, discharge bool) pcsDispatchCapacity {
+	var capacity pcsDispatchCapacity
+	var marginSum float32
+	connected := 0
+
+	for _, bmsID := range l.pairedBMSIDs(pcsID) {
+		service, err := l.bmsManager.GetService(bmsID)
+		if err != nil || !service.IsConnected() {
+			continue
+		}
+
+		bmsData := service.GetLatestBMSData()
+		limits := l.limitsForBMS(bmsID)
+		soc := float32(bmsData.SOC)
+
+		if discharge {
+			capacity.headroomKW += l.calculateDischargePower(bmsData)
+			marginSum += max(soc-l.effectiveMinSOC(limits), 0)
+		} else {
+			capacity.headroomKW += l.calculateChargePower(bmsData)
+			marginSum += max(limits.MaxSOC-soc, 0)
+		}
+		connected++
+	}
+
+	if connected > 0 {
+		capacity.socMargin = marginSum / float32(connected)
+	}
+
+	// The rack warranty throughput derating caps the unit like any other
+	// headroom reduction
+	capacity.headroomKW *= l.throughputDerating(pcsID)
+
+	return capacity
+}
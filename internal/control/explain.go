@@ -0,0 +1,160 @@
+package control
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConstraintExplanation describes one constraint evaluated for a PCS and
+// whether it currently limits the dispatch
+type ConstraintExplanation struct {
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+	Detail  string `json:"detail"`
+}
+
+// PCSExplanation explains the current command of one PCS: where the
+// setpoint came from, what was finally commanded and which constraints
+// acted on the way
+type PCSExplanation struct {
+	PCSID              int                     `json:"pcs_id"`
+	Connected          bool                    `json:"connected"`
+	Source             SetpointSource          `json:"source,omitempty"`
+	SetpointKW         float32                 `json:"setpoint_kw"`
+	SetpointAgeSeconds float64                 `json:"setpoint_age_seconds"`
+	FinalCommandKW     float32                 `json:"final_command_kw"`
+	ChargeLimitKW      float32                 `json:"charge_limit_kw"`
+	DischargeLimitKW   float32                 `json:"discharge_limit_kw"`
+	Constraints        []ConstraintExplanation `json:"constraints"`
+}
+
+// ControlExplanation is a structured explanation of the current dispatch,
+// assembled so operators can see why the plant is or is not following
+// their expectation
+type ControlExplanation struct {
+	Timestamp  time.Time        `json:"timestamp"`
+	Mode       string           `json:"mode"`
+	Ownership  string           `json:"ownership"`
+	GridState  string           `json:"grid_state"`
+	SiteLimits SiteLimitStatus  `json:"site_limits"`
+	PCS        []PCSExplanation `json:"pcs"`
+}
+
+// ExplainControl assembles the explanation of the current control
+// decisions: the active mode and ownership, the site limit state and, per
+// PCS, the originating setpoint source, the final command and every
+// constraint evaluated against it
+func (l *Logic) ExplainControl() ControlExplanation {
+	l.mutex.RLock()
+	mode := l.mode
+	ownership := l.controlOwnership
+	gridState := l.gridState
+	owners := make(map[int]setpointOwner, len(l.setpointOwners))
+	for pcsID, owner := range l.setpointOwners {
+		owners[pcsID] = *owner
+	}
+	l.mutex.RUnlock()
+
+	explanation := ControlExplanation{
+		Timestamp:  time.Now(),
+		Mode:       mode,
+		Ownership:  ownership,
+		GridState:  gridState,
+		SiteLimits: l.GetSiteLimitStatus(),
+	}
+
+	for pcsID, service := range l.pcsManager.GetAllServices() {
+		pcsExplanation := PCSExplanation{
+			PCSID:          pcsID,
+			Connected:      service.IsConnected(),
+			FinalCommandKW: service.GetCommandState().ActivePowerCommand,
+		}
+
+		if owner, held := owners[pcsID]; held {
+			pcsExplanation.Source = owner.source
+			pcsExplanation.SetpointKW = owner.power
+			pcsExplanation.SetpointAgeSeconds = time.Since(owner.lastSeen).Seconds()
+		}
+
+		pcsExplanation.Constraints = l.explainPCSConstraints(pcsID, &pcsExplanation)
+		explanation.PCS = append(explanation.PCS, pcsExplanation)
+	}
+
+	return explanation
+}
+
+// explainPCSConstraints evaluates every constraint acting on one PCS and
+// fills the charge and discharge limits in the explanation
+func (l *Logic) explainPCSConstraints(pcsID int, pcsExplanation *PCSExplanation) []ConstraintExplanation {
+	constraints := make([]ConstraintExplanation, 0, 4)
+
+	// SOC-based ramping of the paired BMS units against their own limits
+	chargeLimit := l.config.MaxChargePower
+	dischargeLimit := l.config.MaxDischargePower
+	rampApplied := false
+	rampDetail := ""
+	for _, bmsID := range l.pairedBMSIDs(pcsID) {
+		bmsService, err := l.bmsManager.GetService(bmsID)
+		if err != nil || !bmsService.IsConnected() {
+			continue
+		}
+		bmsData := bmsService.GetLatestBMSData()
+		limits := l.limitsForBMS(bmsID)
+
+		if limit := l.calculateChargePower(bmsData); limit < chargeLimit {
+			chargeLimit = limit
+		}
+		if limit := l.calculateDischargePower(bmsData); limit < dischargeLimit {
+			dischargeLimit = limit
+		}
+		if chargeLimit < limits.MaxChargePower || dischargeLimit < limits.MaxDischargePower {
+			rampApplied = true
+		}
+		rampDetail = fmt.Sprintf("BMS %d SOC %.1f%% (window %.0f-%.0f%%)",
+			bmsID, bmsData.SOC, limits.MinSOC, limits.MaxSOC)
+	}
+	pcsExplanation.ChargeLimitKW = chargeLimit
+	pcsExplanation.DischargeLimitKW = dischargeLimit
+
+	constraints = append(constraints, ConstraintExplanation{
+		Name:    "soc_ramp",
+		Applied: rampApplied,
+		Detail:  rampDetail,
+	})
+
+	// Site connection-point limit on the current command
+	clamped, otherPower := l.computeSiteClamp(pcsID, pcsExplanation.FinalCommandKW)
+	constraints = append(constraints, ConstraintExplanation{
+		Name:    "site_limit",
+		Applied: clamped != pcsExplanation.FinalCommandKW,
+		Detail: fmt.Sprintf("other site power %.1f kW, export limit %.0f kW, import limit %.0f kW",
+			otherPower, l.config.MaxSiteExportPower, l.config.MaxSiteImportPower),
+	})
+
+	// Heartbeat interlock forcing a safe-state stop
+	heartbeatLost := false
+	if service, err := l.pcsManager.GetService(pcsID); err == nil {
+		heartbeatLost = service.IsHeartbeatLost()
+	}
+	constraints = append(constraints, ConstraintExplanation{
+		Name:    "heartbeat_interlock",
+		Applied: heartbeatLost,
+		Detail:  "safe-state stop while the PCS heartbeat is lost",
+	})
+
+	// Safety source holding the PCS at its setpoint
+	safetyHold := pcsExplanation.Source == SourceSafety
+	constraints = append(constraints, ConstraintExplanation{
+		Name:    "safety_hold",
+		Applied: safetyHold,
+		Detail:  "safety source holds the setpoint; lower-priority commands are rejected",
+	})
+
+	return constraints
+}
+
+// pairedBMSIDs returns the BMS units connected to a PCS following the
+// configured plant topology
+func (l *Logic) pairedBMSIDs(pcsID int) []int {
+	return l.topology[pcsID]
+}
@@ -0,0 +1,80 @@
+package control
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// effectiveImportLimit resolves the import limit at the connection point
+// for the current market time: inside a schedule window the window limit
+// replaces the static import limit, and the most restrictive overlapping
+// window wins
+func (l *Logic) effectiveImportLimit() float32 {
+	l.mutex.RLock()
+	schedule := l.importSchedule
+	l.mutex.RUnlock()
+
+	limit := l.config.MaxSiteImportPower
+
+	hourMinute := time.Now().In(l.location).Format("15:04")
+	for _, window := range schedule {
+		if !inDailyWindow(hourMinute, window.Start, window.End) {
+			continue
+		}
+		if limit == 0 || window.LimitKW < limit {
+			limit = window.LimitKW
+		}
+	}
+
+	return limit
+}
+
+// inDailyWindow reports whether a wall-clock time falls into a daily
+// recurring window; a window whose end precedes its start wraps over
+// midnight. Both bounds are inclusive. "HH:MM" strings compare correctly
+// lexicographically.
+func inDailyWindow(hourMinute, start, end string) bool {
+	if start <= end {
+		return hourMinute >= start && hourMinute <= end
+	}
+	return hourMinute >= start || hourMinute <= end
+}
+
+// GetImportLimitSchedule returns the active import limit windows and the
+// limit currently in force
+func (l *Logic) GetImportLimitSchedule() ([]config.ImportLimitWindowConfig, float32) {
+	l.mutex.RLock()
+	schedule := append([]config.ImportLimitWindowConfig(nil), l.importSchedule...)
+	l.mutex.RUnlock()
+
+	return schedule, l.effectiveImportLimit()
+}
+
+// SetImportLimitSchedule replaces the import limit windows at runtime, e.g.
+// after a renegotiated grid connection contract
+func (l *Logic) SetImportLimitSchedule(schedule []config.ImportLimitWindowConfig) error {
+	for _, window := range schedule {
+		if _, err := time.Parse("15:04", window.Start); err != nil {
+			return fmt.Errorf("invalid window start %q: %w", window.Start, err)
+		}
+		if _, err := time.Parse("15:04", window.End); err != nil {
+			return fmt.Errorf("invalid window end %q: %w", window.End, err)
+		}
+		if window.LimitKW <= 0 {
+			return fmt.Errorf("import limit must be positive, got %.1f", window.LimitKW)
+		}
+	}
+
+	l.mutex.Lock()
+	l.importSchedule = append([]config.ImportLimitWindowConfig(nil), schedule...)
+	l.mutex.Unlock()
+
+	l.log.Info("Import limit schedule replaced",
+		zap.Int("windows", len(schedule)))
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package control
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// Grid connection states
+const (
+	GridStateConnected = "GRID_CONNECTED"
+	GridStateIslanded  = "ISLANDED"
+)
+
+// IslandAlarmCode identifies an unintentional islanding detection
+const IslandAlarmCode = 9500
+
+const (
+	// Frequency window outside of which the site is considered separated
+	// from the 50 Hz grid
+	islandFrequencyMin = 47.5
+	islandFrequencyMax = 52.0
+
+	// islandDetectDwell is how long an islanding signature must persist
+	// before the transition is taken, to ride through measurement glitches
+	islandDetectDwell = 2 * time.Second
+
+	// islandReconnectDwell is how long every signature must be clear before
+	// the site is considered grid-connected again
+	islandReconnectDwell = 10 * time.Second
+)
+
+// GetGridState returns the current grid connection state
+func (l *Logic) GetGridState() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.gridState
+}
+
+// superviseIslanding detects unintentional islanding from the upstream
+// circuit breaker state, the PCS anti-islanding protection and the grid
+// frequency signature, and switches the control strategy accordingly:
+// while islanded every market-driven dispatch source (FCR, SCADA,
+// scheduler, rules) is suspended and only safety and manual commands are
+// accepted. The Power Electronics register map exposes no isochronous or
+// grid-forming mode, so the PCS anti-islanding protection remains the
+// authority over whether the island stays energized.
+func (l *Logic) superviseIslanding() {
+	reason := l.islandingSignature()
+	now := time.Now()
+
+	l.mutex.Lock()
+	state := l.gridState
+
+	switch {
+	case state == GridStateConnected && reason != "":
+		if l.islandSignalSince.IsZero() {
+			l.islandSignalSince = now
+		}
+		if now.Sub(l.islandSignalSince) >= islandDetectDwell {
+			l.gridState = GridStateIslanded
+			l.islandReason = reason
+			l.islandNormalSince = time.Time{}
+			l.mutex.Unlock()
+			l.enterIslanded(reason)
+			return
+		}
+	case state == GridStateConnected:
+		l.islandSignalSince = time.Time{}
+	case state == GridStateIslanded && reason == "":
+		if l.islandNormalSince.IsZero() {
+			l.islandNormalSince = now
+		}
+		if now.Sub(l.islandNormalSince) >= islandReconnectDwell {
+			l.gridState = GridStateConnected
+			l.islandReason = ""
+			l.islandSignalSince = time.Time{}
+			l.mutex.Unlock()
+			l.leaveIslanded()
+			return
+		}
+	case state == GridStateIslanded:
+		l.islandNormalSince = time.Time{}
+		l.islandReason = reason
+	}
+	l.mutex.Unlock()
+}
+
+// islandingSignature evaluates the islanding detection signals and returns
+// the reason of the first active signature, or "" when none is active
+func (l *Logic) islandingSignature() string {
+	if l.upstreamCBOpen() {
+		return "upstream autoproducer circuit breaker open"
+	}
+
+	for pcsID, service := range l.pcsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+
+		if service.AntiIslandActive() {
+			return fmt.Sprintf("PCS %d anti-islanding protection active", pcsID)
+		}
+
+		frequency := service.GetLatestPCSGridData().GridFrequency
+		if frequency > 0 && (frequency < islandFrequencyMin || frequency > islandFrequencyMax) {
+			return fmt.Sprintf("PCS %d grid frequency %.2f Hz outside %.1f-%.1f Hz",
+				pcsID, frequency, float32(islandFrequencyMin), float32(islandFrequencyMax))
+		}
+	}
+
+	return ""
+}
+
+// upstreamCBOpen reports whether a connected PLC shows the autoproducer
+// circuit breaker at the grid connection point open
+func (l *Logic) upstreamCBOpen() bool {
+	if l.plcManager == nil {
+		return false
+	}
+
+	for _, service := range l.plcManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+
+		if !service.GetLatestPLCData().MVCircuitBreakers.AutoproducerCB {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enterIslanded records the transition to islanded operation and raises
+// the islanding alarm. Market-driven setpoint sources are rejected by the
+// arbitration for as long as the state persists.
+func (l *Logic) enterIslanded(reason string) {
+	l.log.Warn("Unintentional islanding detected, suspending market dispatch",
+		zap.String("reason", reason))
+
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: IslandAlarmCode,
+		Message:   "Unintentional islanding detected: " + reason,
+		Severity:  "HIGH",
+		Active:    true,
+	})
+}
+
+// leaveIslanded records the transition back to grid-connected operation
+// and clears the islanding alarm
+func (l *Logic) leaveIslanded() {
+	l.log.Warn("Grid connection restored, resuming normal dispatch")
+
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: IslandAlarmCode,
+		Message:   "Grid connection restored after islanding",
+		Severity:  "HIGH",
+		Active:    false,
+	})
+}
@@ -0,0 +1,77 @@
+package control
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// LeaseAlarmCode identifies an expired manual command lease
+const LeaseAlarmCode = 9400
+
+// superviseManualLease expires manual setpoints that have not been refreshed
+// within the configured lease: the PCS is commanded to zero power, the
+// manual ownership is released and an alarm is raised. A lease of zero
+// disables the supervision.
+func (l *Logic) superviseManualLease() {
+	lease := l.config.ManualCommandLease
+	if lease <= 0 {
+		return
+	}
+
+	type expired struct {
+		pcsID int
+		power float32
+	}
+	var expirations []expired
+
+	l.mutex.Lock()
+	for pcsID, owner := range l.setpointOwners {
+		if owner.source != SourceManual || time.Since(owner.lastSeen) < lease {
+			continue
+		}
+		expirations = append(expirations, expired{pcsID: pcsID, power: owner.power})
+		delete(l.setpointOwners, pcsID)
+	}
+	l.mutex.Unlock()
+
+	for _, expiration := range expirations {
+		l.expireManualSetpoint(expiration.pcsID, expiration.power, lease)
+	}
+
+	// With every manual setpoint expired there is no reason to stay in
+	// manual mode; revert to automatic control
+	if len(expirations) > 0 && l.GetMode() == ModeManual {
+		l.log.Warn("Reverting to AUTO mode after manual command lease expiry")
+		l.SetMode(ModeAutomatic)
+	}
+}
+
+// expireManualSetpoint zeroes one expired manual setpoint and raises the
+// lease alarm
+func (l *Logic) expireManualSetpoint(pcsID int, power float32, lease time.Duration) {
+	l.log.Warn("Manual setpoint lease expired, commanding zero power",
+		zap.Int("pcs_id", pcsID),
+		zap.Float32("stale_power", power),
+		zap.Duration("lease", lease))
+
+	service, err := l.pcsManager.GetService(pcsID)
+	if err == nil {
+		if err := service.SetActivePowerCommand(0); err != nil {
+			l.log.Error("Failed to zero expired manual setpoint",
+				zap.Int("pcs_id", pcsID),
+				zap.Error(err))
+		}
+	}
+
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: LeaseAlarmCode,
+		Message:   "Manual setpoint lease expired, power command reverted to zero",
+		Severity:  "MEDIUM",
+		Active:    true,
+	})
+}
@@ -3,13 +3,18 @@ package control
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/bms"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
 	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/plc"
+	"powerkonnekt/ems/internal/season"
+	"powerkonnekt/ems/internal/windfarm"
 )
 
 type ActivePowerControl struct {
@@ -19,14 +24,71 @@ type ActivePowerControl struct {
 
 // Logic handles control logic and automation
 type Logic struct {
-	config     config.EMSConfig
-	bmsManager *bms.Manager
-	pcsManager *pcs.Manager
-	log        *zap.Logger
+	config          config.EMSConfig
+	bmsLimits       map[int]config.BMSLimits // Resolved per-string limits
+	topology        map[int][]int            // Connected BMS strings per PCS
+	bmsManager      *bms.Manager
+	pcsManager      *pcs.Manager
+	plcManager      *plc.Manager
+	windFarmManager *windfarm.Manager
+	alarmManager    *alarm.Manager
+	postgreSQL      *database.PostgreSQL
+	season          *season.Manager
+	location        *time.Location // Market timezone for wall-clock schedules
+	log             *zap.Logger
 
 	mutex              sync.RWMutex
 	mode               string             // "AUTO", "MANUAL", "MAINTENANCE"
+	controlOwnership   string             // "LOCAL" or "REMOTE"
 	activePowerControl ActivePowerControl // Active power control state
+
+	calibrationState         string    // SOC calibration state machine
+	calibrationLastCompleted time.Time // Last completed SOC calibration charge
+	calibrationStartedAt     time.Time // Start time of the running calibration charge
+
+	siteClampEvents   uint64 // Number of commands clamped by site limits
+	importClampEvents uint64 // Commands clamped by the import limit alone
+
+	importSchedule []config.ImportLimitWindowConfig // Time-of-day import limit windows
+
+	curtailment         *Curtailment // Active TSO/DSO curtailment order
+	curtailedEnergyKWh  float64      // Energy not produced under the current order
+	curtailmentSampleAt time.Time    // Last curtailed-energy integration sample
+
+	peerExportLimitKW  float32 // Export allocation negotiated with the peer EMS
+	peerExportLimitSet bool
+
+	reserveSOC float32 // Protected islanding reserve SOC in percent
+
+	gridState         string    // "GRID_CONNECTED" or "ISLANDED"
+	islandReason      string    // Active islanding detection signature
+	islandSignalSince time.Time // Start of the current islanding signature
+	islandNormalSince time.Time // Start of the current clear period while islanded
+
+	resyncState         string    // Resynchronization sequence state machine
+	resyncStartedAt     time.Time // Start of the running sequence
+	resyncHealthySince  time.Time // Start of the current healthy-grid period
+	resyncBreakerStep   int       // Index into the breaker closing order
+	resyncBreakerCmdAt  time.Time // When the current breaker close was commanded
+	resyncRampStartedAt time.Time // Start of the power release ramp
+	resyncFailure       string    // Failure reason of an aborted sequence
+
+	tsoEmergency            bool            // TSO emergency command asserted via SCADA
+	rapidDownwardActive     bool            // Rapid downward signal asserted automatically
+	rapidDownwardReason     string          // Trigger reason of the active assertion
+	rapidDownwardAssertedAt time.Time       // When the signal was asserted
+	rapidDownwardWindKW     float32         // Wind power at assertion, for BESS compensation
+	rapidDownwardComp       map[int]float32 // Active BESS compensation per PCS in kW
+
+	setpointOwners map[int]*setpointOwner // Active setpoint source per PCS
+
+	safetyMatrix  map[string]config.SafetyActionConfig // Condition class -> action set
+	safetyEngaged map[int]bool                         // PCS with active safety conditions
+
+	pendingActions       map[string]*PendingAction // Critical actions awaiting approval
+	pendingActionCounter uint64
+
+	sboSelections map[string]sboSelection // Armed select-before-operate tokens
 }
 
 const (
@@ -37,31 +99,70 @@ const (
 )
 
 // NewLogic creates a new control logic instance
-func NewLogic(config config.EMSConfig, bmsManager *bms.Manager, pcsManager *pcs.Manager, logger *zap.Logger) *Logic {
+func NewLogic(config config.EMSConfig, bmsLimits map[int]config.BMSLimits, topology map[int][]int, bmsManager *bms.Manager, pcsManager *pcs.Manager, plcManager *plc.Manager, windFarmManager *windfarm.Manager, alarmManager *alarm.Manager, postgreSQL *database.PostgreSQL, seasonManager *season.Manager, logger *zap.Logger) *Logic {
 	// Create component-specific logger
 	controlLogger := logger.With(
 		zap.String("component", "control_logic"),
 	)
 
 	return &Logic{
-		config:     config,
-		bmsManager: bmsManager,
-		pcsManager: pcsManager,
-		mode:       ModeManual,
-		log:        controlLogger,
+		config:            config,
+		bmsLimits:         bmsLimits,
+		topology:          topology,
+		bmsManager:        bmsManager,
+		pcsManager:        pcsManager,
+		plcManager:        plcManager,
+		windFarmManager:   windFarmManager,
+		alarmManager:      alarmManager,
+		postgreSQL:        postgreSQL,
+		season:            seasonManager,
+		location:          config.MarketLocation(),
+		mode:              ModeManual,
+		controlOwnership:  OwnershipLocal,
+		calibrationState:  CalibrationIdle,
+		reserveSOC:        config.IslandingReserveSOC,
+		importSchedule:    config.ImportLimitSchedule,
+		gridState:         GridStateConnected,
+		resyncState:       ResyncIdle,
+		rapidDownwardComp: make(map[int]float32),
+		setpointOwners:    make(map[int]*setpointOwner),
+		safetyMatrix:      newSafetyMatrix(config.SafetyMatrix),
+		safetyEngaged:     make(map[int]bool),
+		pendingActions:    make(map[string]*PendingAction),
+		sboSelections:     make(map[string]sboSelection),
+		log:               controlLogger,
 	}
 }
 
 // SetMode sets the control mode
 func (l *Logic) SetMode(mode string) {
 	l.mutex.Lock()
-	defer l.mutex.Unlock()
 	oldMode := l.mode
 	l.mode = mode
+	l.mutex.Unlock()
 
 	l.log.Info("Control mode changed",
 		zap.String("old_mode", oldMode),
 		zap.String("new_mode", mode))
+
+	l.persistControlState()
+	l.submitModeRestoreAlarm(false)
+}
+
+// limitsForBMS returns the effective SOC window and power limits of one BMS
+// string; strings without a configured entry fall back to the global EMS
+// limits. The active seasonal profile overlays both.
+func (l *Logic) limitsForBMS(bmsID int) config.BMSLimits {
+	limits, ok := l.bmsLimits[bmsID]
+	if !ok {
+		limits = config.BMSLimits{
+			MaxSOC:            l.config.MaxSOC,
+			MinSOC:            l.config.MinSOC,
+			MaxChargePower:    l.config.MaxChargePower,
+			MaxDischargePower: l.config.MaxDischargePower,
+		}
+	}
+	return l.season.ApplyLimits(limits)
 }
 
 // GetMode returns the current control mode
@@ -73,9 +174,11 @@ func (l *Logic) GetMode() string {
 
 func (l *Logic) SetActivePowerControl(power float32) {
 	l.mutex.Lock()
-	defer l.mutex.Unlock()
 	l.activePowerControl.Enabled = power != 0
 	l.activePowerControl.Power = power
+	l.mutex.Unlock()
+
+	l.persistControlState()
 }
 
 func (l *Logic) GetActivePowerControl() ActivePowerControl {
@@ -93,28 +196,33 @@ func (l *Logic) ExecuteControl() {
 	// Check all BMS-PCS pairs
 	l.checkBMSPCSPairs()
 
+	// Supervise the automatic rapid downward triggers
+	l.superviseRapidDownward()
+
+	// Expire curtailment orders and account the curtailed energy
+	l.superviseCurtailment()
+
+	// Expire stale manual setpoints
+	l.superviseManualLease()
+
+	// Detect unintentional islanding and handle the transitions
+	l.superviseIslanding()
+
+	// Advance a running resynchronization sequence
+	l.superviseResynchronization()
+
 	if mode != "AUTO" {
 		return // Skip automatic control in manual or maintenance mode
 	}
+
+	// Schedule and supervise periodic SOC-calibration charges
+	l.superviseSOCCalibration()
 }
 
-// checkBMSPCSPairs checks SOC limits for each BMS-PCS pair and stops PCS if needed
+// checkBMSPCSPairs evaluates the safety conditions of each BMS-PCS pair
+// and hands them to the safety action matrix
 func (l *Logic) checkBMSPCSPairs() {
-	// Each PCS is connected to 2 BMS units
-	// PCS1 -> BMS1, BMS2
-	// PCS2 -> BMS3, BMS4
-	// PCS3 -> BMS5, BMS6
-	// PCS4 -> BMS7, BMS8
-
-	pcsCount := 4
-
-	for pcsID := 1; pcsID <= pcsCount; pcsID++ {
-		bms1ID := (pcsID-1)*2 + 1
-		bms2ID := (pcsID-1)*2 + 2
-
-		shouldStopPCS := false
-		reason := ""
-
+	for pcsID, bmsIDs := range l.topology {
 		// Get PCS data to check power direction
 		pcsService, err := l.pcsManager.GetService(pcsID)
 		if err != nil {
@@ -126,101 +234,84 @@ func (l *Logic) checkBMSPCSPairs() {
 		pcsCommandState := pcsService.GetCommandState()
 		pcsPower := pcsCommandState.ActivePowerCommand
 
-		// Check BMS1 for this PCS
-		bms1Service, err := l.bmsManager.GetService(bms1ID)
-		if err == nil {
-			bmsData := bms1Service.GetLatestBMSData()
-			bmsStatusData := bms1Service.GetLatestBMSStatusData()
-
-			// Check for fault state
-			if bms.IsFaultState(bmsStatusData.SystemStatus) {
-				shouldStopPCS = true
-				reason = fmt.Sprintf("BMS%d in fault state", bms1ID)
-			}
-
-			// Check for high SOC during charging (negative power)
-			if pcsPower < 0 && (bms.IsFullChargeState(bmsStatusData.SystemStatus) || float32(bmsData.SOC) >= l.config.MaxSOC) {
-				shouldStopPCS = true
-				reason = fmt.Sprintf("BMS%d SOC at MaxSOC during charging", bms1ID)
-			}
-
-			// Check for low SOC during discharging (positive power)
-			if pcsPower > 0 && (bms.IsFullDischargeState(bmsStatusData.SystemStatus) || float32(bmsData.SOC) <= l.config.MinSOC) {
-				shouldStopPCS = true
-				reason = fmt.Sprintf("BMS%d SOC at MinSOC during discharging", bms1ID)
-			}
-		}
+		var conditions []safetyCondition
 
-		// Check BMS2 for this PCS (if it exists)
-		bms2Service, err := l.bmsManager.GetService(bms2ID)
-		if err == nil {
-			bmsData := bms2Service.GetLatestBMSData()
-			bmsStatusData := bms2Service.GetLatestBMSStatusData()
-
-			// Check for fault state
-			if bms.IsFaultState(bmsStatusData.SystemStatus) {
-				shouldStopPCS = true
-				if reason != "" {
-					reason += fmt.Sprintf(", BMS%d in fault state", bms2ID)
-				} else {
-					reason = fmt.Sprintf("BMS%d in fault state", bms2ID)
-				}
-			}
-
-			// Check for high SOC during charging (negative power)
-			if pcsPower < 0 && (bms.IsFullChargeState(bmsStatusData.SystemStatus) || float32(bmsData.SOC) >= l.config.MaxSOC) {
-				shouldStopPCS = true
-				if reason != "" {
-					reason += fmt.Sprintf(", BMS%d SOC at MaxSOC during charging", bms2ID)
-				} else {
-					reason = fmt.Sprintf("BMS%d SOC at MaxSOC during charging", bms2ID)
-				}
-			}
-
-			// Check for low SOC during discharging (positive power)
-			if pcsPower > 0 && (bms.IsFullDischargeState(bmsStatusData.SystemStatus) || float32(bmsData.SOC) <= l.config.MinSOC) {
-				shouldStopPCS = true
-				if reason != "" {
-					reason += fmt.Sprintf(", BMS%d SOC at MinSOC during discharging", bms2ID)
-				} else {
-					reason = fmt.Sprintf("BMS%d SOC at MinSOC during discharging", bms2ID)
-				}
-			}
+		// Safe-state trigger on prolonged PCS heartbeat loss
+		if pcsService.IsHeartbeatLost() {
+			conditions = append(conditions, safetyCondition{
+				class:  ConditionHeartbeatLost,
+				reason: fmt.Sprintf("PCS%d heartbeat lost", pcsID),
+			})
 		}
 
-		// Stop PCS if needed
-		if shouldStopPCS {
-			l.log.Warn("Stopping PCS due to BMS condition",
-				zap.Int("pcs_id", pcsID),
-				zap.String("reason", reason))
-
-			// Set active power to zero
-			if err := pcsService.SetActivePowerCommand(0); err != nil {
-				l.log.Error("Failed to set active power to zero",
-					zap.Error(err),
-					zap.Int("pcs_id", pcsID))
-			}
-
-			// Optionally stop the PCS completely
-			// if err := pcsService.StartStopCommand(false); err != nil {
-			// 	l.log.Error("Failed to stop PCS",
-			// 		logger.Err(err),
-			// 		logger.Int("pcs_id", pcsID))
-			// }
+		for _, bmsID := range bmsIDs {
+			conditions = append(conditions, l.collectBMSConditions(bmsID, pcsPower)...)
 		}
+
+		l.executeSafetyMatrix(pcsID, conditions)
+	}
+}
+
+// collectBMSConditions evaluates the safety conditions of one BMS string
+// against the current PCS power direction
+func (l *Logic) collectBMSConditions(bmsID int, pcsPower float32) []safetyCondition {
+	service, err := l.bmsManager.GetService(bmsID)
+	if err != nil {
+		return nil
+	}
+
+	bmsData := service.GetLatestBMSData()
+	bmsStatusData := service.GetLatestBMSStatusData()
+	limits := l.limitsForBMS(bmsID)
+
+	var conditions []safetyCondition
+
+	// Check for fault state
+	if bms.IsFaultState(bmsStatusData.SystemStatus) {
+		conditions = append(conditions, safetyCondition{
+			class:  ConditionBMSFault,
+			reason: fmt.Sprintf("BMS%d in fault state", bmsID),
+		})
+	}
+
+	// Safe-state trigger on prolonged BMS heartbeat loss
+	if service.IsHeartbeatLost() {
+		conditions = append(conditions, safetyCondition{
+			class:  ConditionHeartbeatLost,
+			reason: fmt.Sprintf("BMS%d heartbeat lost", bmsID),
+		})
+	}
+
+	// Check for high SOC during charging (negative power)
+	if pcsPower < 0 && (bms.IsFullChargeState(bmsStatusData.SystemStatus) || float32(bmsData.SOC) >= limits.MaxSOC) {
+		conditions = append(conditions, safetyCondition{
+			class:  ConditionSOCHighCharging,
+			reason: fmt.Sprintf("BMS%d SOC at MaxSOC during charging", bmsID),
+		})
+	}
+
+	// Check for low SOC during discharging (positive power)
+	if pcsPower > 0 && (bms.IsFullDischargeState(bmsStatusData.SystemStatus) || float32(bmsData.SOC) <= l.effectiveMinSOC(limits)) {
+		conditions = append(conditions, safetyCondition{
+			class:  ConditionSOCLowDischarging,
+			reason: fmt.Sprintf("BMS%d SOC at MinSOC during discharging", bmsID),
+		})
 	}
+
+	return conditions
 }
 
 func (l *Logic) calculateChargePower(bmsData database.BMSData) float32 {
-	maxPower := min(float32(bmsData.MaxChargePower), l.config.MaxChargePower)
+	limits := l.limitsForBMS(bmsData.ID)
+	maxPower := min(float32(bmsData.MaxChargePower), limits.MaxChargePower)
 
 	// Apply SOC-based ramping
 	soc := float32(bmsData.SOC)
-	rampStartSOC := l.config.MaxSOC - 5.0 // Start ramping 5% below MaxSOC
+	rampStartSOC := limits.MaxSOC - 5.0 // Start ramping 5% below MaxSOC
 
 	if soc > rampStartSOC {
 		// Reduce charge power as SOC approaches MaxSOC
-		rampFactor := (l.config.MaxSOC - soc) / 5.0
+		rampFactor := (limits.MaxSOC - soc) / 5.0
 		if rampFactor < 0 {
 			rampFactor = 0
 		}
@@ -237,15 +328,18 @@ func (l *Logic) GetBESSUpdateChannel() <-chan struct{} {
 }
 
 func (l *Logic) calculateDischargePower(bmsData database.BMSData) float32 {
-	maxPower := min(float32(bmsData.MaxDischargePower), l.config.MaxDischargePower)
+	limits := l.limitsForBMS(bmsData.ID)
+	maxPower := min(float32(bmsData.MaxDischargePower), limits.MaxDischargePower)
 
-	// Apply SOC-based ramping
+	// Apply SOC-based ramping down to the discharge floor (configured
+	// minimum SOC or the protected islanding reserve, whichever is higher)
 	soc := float32(bmsData.SOC)
-	rampStartSOC := l.config.MinSOC + 5.0 // Start ramping 5% above MinSOC
+	minSOC := l.effectiveMinSOC(limits)
+	rampStartSOC := minSOC + 5.0 // Start ramping 5% above the floor
 
 	if soc < rampStartSOC {
-		// Reduce discharge power as SOC approaches MinSOC
-		rampFactor := (soc - l.config.MinSOC) / 5.0
+		// Reduce discharge power as SOC approaches the floor
+		rampFactor := (soc - minSOC) / 5.0
 		if rampFactor < 0 {
 			rampFactor = 0
 		}
@@ -280,6 +374,12 @@ func (l *Logic) ManualPowerCommand(power float32) error {
 
 	originalPower := power
 
+	// Arbitrate against other setpoint sources and enforce site limits
+	power, err := l.ArbitratePCSPowerCommand(1, SourceManual, power)
+	if err != nil {
+		return err
+	}
+
 	// Check power limits
 	if power < 0 { // Charging (negative power)
 		maxCharge := l.calculateChargePower(bmsData)
@@ -306,7 +406,7 @@ func (l *Logic) ManualPowerCommand(power float32) error {
 		zap.Float32("final_power", power),
 		zap.Float32("current_soc", float32(bmsData.SOC)))
 
-	err := pcs1Service.SetActivePowerCommand(power)
+	err = pcs1Service.SetActivePowerCommand(power)
 	if err != nil {
 		l.log.Error("Manual power command failed",
 			zap.Error(err),
@@ -0,0 +1,145 @@
+package control
+
+import (
+	"powerkonnekt/ems/internal/config"
+)
+
+// Built-in flat efficiencies used when no curve is configured
+const (
+	defaultPCSEfficiency         = 0.97
+	defaultTransformerEfficiency = 0.99
+)
+
+// LossBreakdown holds the computed losses per conversion stage in kW
+type LossBreakdown struct {
+	DCPowerKW         float32 `json:"dc_power_kw"` // battery and PV combined
+	PVDCPowerKW       float32 `json:"pv_dc_power_kw"`
+	NCPPowerKW        float32 `json:"ncp_power_kw"`
+	PCSLossKW         float32 `json:"pcs_loss_kw"`
+	TransformerLossKW float32 `json:"transformer_loss_kw"`
+	AuxiliaryLossKW   float32 `json:"auxiliary_loss_kw"`
+	TotalLossKW       float32 `json:"total_loss_kw"`
+}
+
+// interpolateEfficiency returns the efficiency at the given load percentage
+// by linear interpolation over the configured curve. Loads outside the curve
+// are clamped to the nearest point; an empty curve yields the fallback.
+func interpolateEfficiency(curve []config.EfficiencyPoint, loadPercent float32, fallback float32) float32 {
+	if len(curve) == 0 {
+		return fallback
+	}
+
+	if loadPercent <= curve[0].LoadPercent {
+		return curve[0].Efficiency
+	}
+	if loadPercent >= curve[len(curve)-1].LoadPercent {
+		return curve[len(curve)-1].Efficiency
+	}
+
+	for i := 1; i < len(curve); i++ {
+		if loadPercent <= curve[i].LoadPercent {
+			span := curve[i].LoadPercent - curve[i-1].LoadPercent
+			if span <= 0 {
+				return curve[i].Efficiency
+			}
+			fraction := (loadPercent - curve[i-1].LoadPercent) / span
+			return curve[i-1].Efficiency + fraction*(curve[i].Efficiency-curve[i-1].Efficiency)
+		}
+	}
+
+	return curve[len(curve)-1].Efficiency
+}
+
+// stageEfficiencies returns the PCS and transformer efficiencies at the given
+// power, using the site rated power as the load percentage base
+func (l *Logic) stageEfficiencies(powerKW float32) (float32, float32) {
+	rated := l.config.MaxDischargePower
+	if powerKW < 0 {
+		rated = l.config.MaxChargePower
+	}
+
+	loadPercent := float32(0)
+	if rated > 0 {
+		loadPercent = abs(powerKW) / rated * 100.0
+	}
+
+	pcsEff := interpolateEfficiency(l.config.LossModel.PCSCurve, loadPercent, defaultPCSEfficiency)
+	trafoEff := interpolateEfficiency(l.config.LossModel.TransformerCurve, loadPercent, defaultTransformerEfficiency)
+	return pcsEff, trafoEff
+}
+
+// DCToNCPPower converts battery DC-side power to the resulting power at the
+// network connection point. Positive power means discharge/export; losses
+// reduce the exported power when discharging and increase the drawn power
+// when charging.
+func (l *Logic) DCToNCPPower(dcPowerKW float32) float32 {
+	pcsEff, trafoEff := l.stageEfficiencies(dcPowerKW)
+
+	ncp := dcPowerKW
+	if dcPowerKW >= 0 {
+		ncp = dcPowerKW * pcsEff * trafoEff
+	} else {
+		ncp = dcPowerKW / (pcsEff * trafoEff)
+	}
+
+	return ncp - l.config.LossModel.AuxiliaryPower
+}
+
+// NCPToDCPower converts a desired power at the network connection point to
+// the battery DC-side power required to deliver it
+func (l *Logic) NCPToDCPower(ncpPowerKW float32) float32 {
+	// Auxiliaries draw from the connection point regardless of direction
+	converted := ncpPowerKW + l.config.LossModel.AuxiliaryPower
+
+	pcsEff, trafoEff := l.stageEfficiencies(converted)
+	if converted >= 0 {
+		return converted / (pcsEff * trafoEff)
+	}
+	return converted * (pcsEff * trafoEff)
+}
+
+// GetLossBreakdown computes the current per-stage losses from the measured
+// DC-side power; DC-coupled PV enters the conversion chain alongside the
+// battery strings
+func (l *Logic) GetLossBreakdown() LossBreakdown {
+	dcPower := float32(0)
+	for _, service := range l.bmsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		dcPower += float32(service.GetLatestBMSData().Power)
+	}
+	pvPower := l.pvPowerKW()
+	dcPower += pvPower
+
+	pcsEff, trafoEff := l.stageEfficiencies(dcPower)
+
+	var afterPCS, afterTrafo float32
+	if dcPower >= 0 {
+		afterPCS = dcPower * pcsEff
+		afterTrafo = afterPCS * trafoEff
+	} else {
+		afterPCS = dcPower / pcsEff
+		afterTrafo = afterPCS / trafoEff
+	}
+
+	breakdown := LossBreakdown{
+		DCPowerKW:         dcPower,
+		PVDCPowerKW:       pvPower,
+		NCPPowerKW:        afterTrafo - l.config.LossModel.AuxiliaryPower,
+		PCSLossKW:         abs(dcPower - afterPCS),
+		TransformerLossKW: abs(afterPCS - afterTrafo),
+		AuxiliaryLossKW:   l.config.LossModel.AuxiliaryPower,
+	}
+	breakdown.TotalLossKW = breakdown.PCSLossKW + breakdown.TransformerLossKW + breakdown.AuxiliaryLossKW
+
+	return breakdown
+}
+
+// abs returns the absolute value of a float32
+func abs(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
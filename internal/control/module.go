@@ -1,17 +1,25 @@
 package control
 
 import (
+	"context"
+
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/bms"
 	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
 	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/plc"
+	"powerkonnekt/ems/internal/season"
+	"powerkonnekt/ems/internal/windfarm"
 )
 
 // Module provides control logic functionality to the Fx application
 var Module = fx.Module("control",
 	fx.Provide(ProvideLogic),
+	fx.Invoke(RegisterLifecycle),
 )
 
 // ProvideLogic creates and provides a control logic instance
@@ -19,7 +27,35 @@ func ProvideLogic(
 	cfg *config.Config,
 	bmsManager *bms.Manager,
 	pcsManager *pcs.Manager,
+	plcManager *plc.Manager,
+	windFarmManager *windfarm.Manager,
+	alarmManager *alarm.Manager,
+	postgreSQL *database.PostgreSQL,
+	seasonManager *season.Manager,
 	logger *zap.Logger,
 ) *Logic {
-	return NewLogic(cfg.EMS, bmsManager, pcsManager, logger)
+	bmsLimits := make(map[int]config.BMSLimits, len(cfg.BMS))
+	for _, bmsCfg := range cfg.BMS {
+		bmsLimits[bmsCfg.ID] = cfg.LimitsForBMS(bmsCfg.ID)
+	}
+
+	return NewLogic(cfg.EMS, bmsLimits, cfg.TopologyMap(), bmsManager, pcsManager, plcManager, windFarmManager, alarmManager, postgreSQL, seasonManager, logger)
+}
+
+// RegisterLifecycle restores the persisted control state after startup; the
+// restore waits for device health in the background so it does not delay
+// application start
+func RegisterLifecycle(lc fx.Lifecycle, logic *Logic) {
+	restoreCtx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go logic.RestorePersistedState(restoreCtx)
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			return nil
+		},
+	})
 }
@@ -0,0 +1,63 @@
+package control
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Control ownership sides, matching substation local/remote practice
+const (
+	OwnershipLocal  = "LOCAL"
+	OwnershipRemote = "REMOTE"
+)
+
+// ErrNotControlOwner is returned when a command is issued from the control
+// side that does not currently own the plant
+var ErrNotControlOwner = errors.New("command issued from non-owning control side")
+
+// SetControlOwnership switches command ownership between the local HMI and
+// remote SCADA/fleet side
+func (l *Logic) SetControlOwnership(ownership string) error {
+	if ownership != OwnershipLocal && ownership != OwnershipRemote {
+		return fmt.Errorf("invalid control ownership: %s", ownership)
+	}
+
+	l.mutex.Lock()
+	oldOwnership := l.controlOwnership
+	l.controlOwnership = ownership
+	l.mutex.Unlock()
+
+	l.log.Info("Control ownership changed",
+		zap.String("old_ownership", oldOwnership),
+		zap.String("new_ownership", ownership))
+
+	return nil
+}
+
+// GetControlOwnership returns the current control ownership side
+func (l *Logic) GetControlOwnership() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.controlOwnership
+}
+
+// checkControlOwnership rejects commands from the non-owning control side.
+// Internal sources (safety, FCR, scheduler) are always accepted.
+func (l *Logic) checkControlOwnership(source SetpointSource) error {
+	ownership := l.GetControlOwnership()
+
+	switch source {
+	case SourceManual:
+		if ownership != OwnershipLocal {
+			return fmt.Errorf("%w: local command while ownership is %s", ErrNotControlOwner, ownership)
+		}
+	case SourceSCADA:
+		if ownership != OwnershipRemote {
+			return fmt.Errorf("%w: remote command while ownership is %s", ErrNotControlOwner, ownership)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package control
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// ModeRestoreAlarmCode identifies the alarm raised when the persisted
+// control state could not be restored after a restart
+const ModeRestoreAlarmCode = 9004
+
+// Device health polling while waiting to restore the persisted state
+const (
+	restoreHealthTimeout      = 30 * time.Second
+	restoreHealthPollInterval = 2 * time.Second
+)
+
+// persistControlState saves the current mode and active power control so a
+// restart does not silently fall back to MANUAL. Persistence failures are
+// logged but never block the control path.
+func (l *Logic) persistControlState() {
+	if l.postgreSQL == nil {
+		return
+	}
+
+	l.mutex.RLock()
+	record := database.ControlStateRecord{
+		Mode:               l.mode,
+		ActivePowerEnabled: l.activePowerControl.Enabled,
+		ActivePowerKW:      l.activePowerControl.Power,
+		ReserveSOC:         l.reserveSOC,
+	}
+	l.mutex.RUnlock()
+
+	if err := l.postgreSQL.SaveControlState(record); err != nil {
+		l.log.Error("Failed to persist control state", zap.Error(err))
+	}
+}
+
+// RestorePersistedState restores the persisted control mode and active
+// power control after a restart. The restore only happens once the devices
+// report healthy within the timeout; otherwise the EMS stays in MANUAL and
+// an alarm is raised so the disabled auto operation does not go unnoticed.
+func (l *Logic) RestorePersistedState(ctx context.Context) {
+	if l.postgreSQL == nil {
+		return
+	}
+
+	record, err := l.postgreSQL.GetControlState()
+	if err != nil {
+		l.log.Error("Failed to load persisted control state", zap.Error(err))
+		return
+	}
+	if record == nil {
+		l.log.Info("No persisted control state to restore")
+		return
+	}
+	// The negotiated reserve is restored regardless of the control mode
+	if record.ReserveSOC > 0 {
+		l.mutex.Lock()
+		l.reserveSOC = record.ReserveSOC
+		l.mutex.Unlock()
+	}
+
+	if record.Mode == ModeManual && !record.ActivePowerEnabled {
+		return
+	}
+
+	if !l.waitForDeviceHealth(ctx) {
+		l.log.Error("Persisted control state not restored - devices unhealthy",
+			zap.String("persisted_mode", record.Mode),
+			zap.Duration("health_timeout", restoreHealthTimeout))
+		l.submitModeRestoreAlarm(true)
+		return
+	}
+
+	l.SetMode(record.Mode)
+	if record.ActivePowerEnabled {
+		l.SetActivePowerControl(record.ActivePowerKW)
+	}
+
+	l.log.Warn("Control state restored from before restart",
+		zap.String("mode", record.Mode),
+		zap.Bool("active_power_enabled", record.ActivePowerEnabled),
+		zap.Float32("active_power_kw", record.ActivePowerKW),
+		zap.Time("persisted_at", record.UpdatedAt))
+}
+
+// waitForDeviceHealth polls until every PCS and BMS is connected and no
+// critical alarm is active, or the timeout elapses
+func (l *Logic) waitForDeviceHealth(ctx context.Context) bool {
+	deadline := time.Now().Add(restoreHealthTimeout)
+
+	for {
+		if l.devicesHealthy() {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(restoreHealthPollInterval):
+		}
+	}
+}
+
+// devicesHealthy reports whether every PCS and BMS is connected and no
+// critical alarm is active
+func (l *Logic) devicesHealthy() bool {
+	for _, service := range l.pcsManager.GetAllServices() {
+		if !service.IsConnected() {
+			return false
+		}
+	}
+	for _, service := range l.bmsManager.GetAllServices() {
+		if !service.IsConnected() {
+			return false
+		}
+	}
+	if l.alarmManager != nil && l.alarmManager.HasCriticalAlarms() {
+		return false
+	}
+	return true
+}
+
+// submitModeRestoreAlarm raises or clears the failed-restore alarm
+func (l *Logic) submitModeRestoreAlarm(active bool) {
+	if l.alarmManager == nil {
+		return
+	}
+
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: ModeRestoreAlarmCode,
+		Message:   "Persisted control mode not restored - EMS stays in MANUAL",
+		Severity:  "HIGH",
+		Active:    active,
+	})
+}
@@ -0,0 +1,317 @@
+package control
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// RapidDownwardAlarmCode identifies the alarm raised while the rapid
+// downward signal is asserted automatically
+const RapidDownwardAlarmCode = 9007
+
+// Rapid downward trigger reasons
+const (
+	ReasonProtectionRelayFault = "PROTECTION_RELAY_FAULT"
+	ReasonExportLimitBreach    = "EXPORT_LIMIT_BREACH"
+	ReasonTSOEmergency         = "TSO_EMERGENCY"
+)
+
+// exportBreachTolerance is how far the measured site power may exceed the
+// export limit before the rapid downward signal is asserted. Command
+// clamping already keeps setpoints below the limit, so a sustained breach
+// beyond the tolerance means wind generation itself has to come down.
+const exportBreachTolerance = 1.05
+
+// rapidDownwardHoldTime is the minimum time the signal stays asserted, so a
+// flickering trigger cannot toggle the wind farm rapidly
+const rapidDownwardHoldTime = 30 * time.Second
+
+// compensationDeadbandKW is the change in compensation power below which no
+// new PCS command is issued, keeping the command rate low while the wind
+// farm ramps down
+const compensationDeadbandKW = 1.0
+
+// RapidDownwardStatus describes the automatic rapid downward state
+type RapidDownwardStatus struct {
+	Active         bool      `json:"active"`
+	Reason         string    `json:"reason,omitempty"`
+	AssertedAt     time.Time `json:"asserted_at"`
+	CompensationKW float32   `json:"compensation_kw"`
+}
+
+// SetTSOEmergency records a TSO emergency command received via SCADA; the
+// next control cycle asserts or releases the rapid downward signal
+func (l *Logic) SetTSOEmergency(active bool) {
+	l.mutex.Lock()
+	changed := l.tsoEmergency != active
+	l.tsoEmergency = active
+	l.mutex.Unlock()
+
+	if changed {
+		l.log.Warn("TSO emergency command state changed",
+			zap.Bool("active", active))
+	}
+}
+
+// GetTSOEmergency returns whether a TSO emergency command is asserted
+func (l *Logic) GetTSOEmergency() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.tsoEmergency
+}
+
+// GetRapidDownwardStatus returns the automatic rapid downward state
+func (l *Logic) GetRapidDownwardStatus() RapidDownwardStatus {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	var compensation float32
+	for _, power := range l.rapidDownwardComp {
+		compensation += power
+	}
+
+	return RapidDownwardStatus{
+		Active:         l.rapidDownwardActive,
+		Reason:         l.rapidDownwardReason,
+		AssertedAt:     l.rapidDownwardAssertedAt,
+		CompensationKW: compensation,
+	}
+}
+
+// superviseRapidDownward asserts the wind farm rapid downward signal when a
+// trigger condition is present and releases it once all triggers are clear
+// and the signal has been asserted for at least rapidDownwardHoldTime. Runs
+// in every control cycle regardless of mode because it is a protective
+// function.
+func (l *Logic) superviseRapidDownward() {
+	reason := l.rapidDownwardTrigger()
+
+	l.mutex.RLock()
+	active := l.rapidDownwardActive
+	assertedAt := l.rapidDownwardAssertedAt
+	l.mutex.RUnlock()
+
+	switch {
+	case reason != "" && !active:
+		l.assertRapidDownward(reason)
+	case reason == "" && active && time.Since(assertedAt) >= rapidDownwardHoldTime:
+		l.releaseRapidDownward()
+	case active:
+		l.updateRapidDownwardCompensation()
+	}
+}
+
+// rapidDownwardTrigger evaluates the automatic trigger conditions and
+// returns the reason of the first active trigger, or "" when none is active
+func (l *Logic) rapidDownwardTrigger() string {
+	if l.GetTSOEmergency() {
+		return ReasonTSOEmergency
+	}
+
+	if l.upstreamRelayFault() {
+		return ReasonProtectionRelayFault
+	}
+
+	exportLimit := l.effectiveExportLimit()
+	if exportLimit > 0 && l.siteNetPowerKW() > exportLimit*exportBreachTolerance {
+		return ReasonExportLimitBreach
+	}
+
+	return ""
+}
+
+// upstreamRelayFault reports whether any protection relay on a connected PLC
+// reports a fault upstream of the connection point
+func (l *Logic) upstreamRelayFault() bool {
+	if l.plcManager == nil {
+		return false
+	}
+
+	for _, service := range l.plcManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+
+		relays := service.GetLatestPLCData().ProtectionRelays
+		if relays.AuxTransformerFault || relays.Transformer1Fault ||
+			relays.Transformer2Fault || relays.Transformer3Fault ||
+			relays.Transformer4Fault {
+			return true
+		}
+	}
+
+	return false
+}
+
+// assertRapidDownward asserts the rapid downward signal on every wind farm
+// FCU, records the event and starts BESS compensation for the lost wind
+// power where the trigger allows it
+func (l *Logic) assertRapidDownward(reason string) {
+	windKW := l.windPowerKW()
+
+	l.mutex.Lock()
+	l.rapidDownwardActive = true
+	l.rapidDownwardReason = reason
+	l.rapidDownwardAssertedAt = time.Now()
+	l.rapidDownwardWindKW = windKW
+	l.mutex.Unlock()
+
+	for id, service := range l.windFarmManager.GetAllServices() {
+		if err := service.SetRapidDownwardSignal(true); err != nil {
+			l.log.Error("Failed to assert rapid downward signal",
+				zap.Int("windfarm_id", id),
+				zap.Error(err))
+		}
+	}
+
+	l.log.Warn("Rapid downward signal asserted automatically",
+		zap.String("reason", reason),
+		zap.Float32("wind_power_kw", windKW))
+
+	l.submitRapidDownwardAlarm(reason, true)
+	l.updateRapidDownwardCompensation()
+}
+
+// releaseRapidDownward deasserts the rapid downward signal, records the
+// event and withdraws any BESS compensation
+func (l *Logic) releaseRapidDownward() {
+	l.mutex.Lock()
+	reason := l.rapidDownwardReason
+	l.rapidDownwardActive = false
+	l.rapidDownwardReason = ""
+	l.mutex.Unlock()
+
+	for id, service := range l.windFarmManager.GetAllServices() {
+		if err := service.SetRapidDownwardSignal(false); err != nil {
+			l.log.Error("Failed to release rapid downward signal",
+				zap.Int("windfarm_id", id),
+				zap.Error(err))
+		}
+	}
+
+	l.clearRapidDownwardCompensation()
+
+	l.log.Info("Rapid downward signal released",
+		zap.String("reason", reason))
+
+	l.submitRapidDownwardAlarm(reason, false)
+}
+
+// updateRapidDownwardCompensation discharges the BESS to cover the wind
+// power lost since the assertion. Compensation only runs for upstream
+// protection faults: for an export limit breach or a TSO emergency the site
+// power itself has to come down, so compensating would defeat the trigger.
+func (l *Logic) updateRapidDownwardCompensation() {
+	l.mutex.RLock()
+	reason := l.rapidDownwardReason
+	windAtAssert := l.rapidDownwardWindKW
+	l.mutex.RUnlock()
+
+	if reason != ReasonProtectionRelayFault {
+		return
+	}
+
+	lostKW := windAtAssert - l.windPowerKW()
+	if lostKW < 0 {
+		lostKW = 0
+	}
+
+	services := l.pcsManager.GetAllServices()
+	if len(services) == 0 {
+		return
+	}
+	share := lostKW / float32(len(services))
+
+	for id, service := range services {
+		if !service.IsConnected() {
+			continue
+		}
+
+		power := share
+		if maxDischarge := l.limitsForBMS(id).MaxDischargePower; maxDischarge > 0 && power > maxDischarge {
+			power = maxDischarge
+		}
+
+		l.mutex.RLock()
+		previous := l.rapidDownwardComp[id]
+		l.mutex.RUnlock()
+
+		if power-previous < compensationDeadbandKW && previous-power < compensationDeadbandKW {
+			continue
+		}
+
+		power, err := l.ArbitratePCSPowerCommand(id, SourceSafety, power)
+		if err != nil {
+			l.log.Warn("BESS compensation command rejected",
+				zap.Int("pcs_id", id),
+				zap.Error(err))
+			continue
+		}
+
+		if err := service.SetActivePowerCommand(power); err != nil {
+			l.log.Error("Failed to command BESS compensation",
+				zap.Int("pcs_id", id),
+				zap.Float32("power", power),
+				zap.Error(err))
+			continue
+		}
+
+		l.mutex.Lock()
+		l.rapidDownwardComp[id] = power
+		l.mutex.Unlock()
+	}
+}
+
+// clearRapidDownwardCompensation withdraws the compensation commands issued
+// while the rapid downward signal was asserted
+func (l *Logic) clearRapidDownwardCompensation() {
+	l.mutex.Lock()
+	compensated := make([]int, 0, len(l.rapidDownwardComp))
+	for id := range l.rapidDownwardComp {
+		compensated = append(compensated, id)
+	}
+	l.rapidDownwardComp = make(map[int]float32)
+	l.mutex.Unlock()
+
+	for _, id := range compensated {
+		service, err := l.pcsManager.GetService(id)
+		if err != nil {
+			continue
+		}
+
+		power, err := l.ArbitratePCSPowerCommand(id, SourceSafety, 0)
+		if err != nil {
+			l.log.Warn("Failed to withdraw BESS compensation",
+				zap.Int("pcs_id", id),
+				zap.Error(err))
+			continue
+		}
+
+		if err := service.SetActivePowerCommand(power); err != nil {
+			l.log.Error("Failed to withdraw BESS compensation",
+				zap.Int("pcs_id", id),
+				zap.Error(err))
+		}
+	}
+}
+
+// submitRapidDownwardAlarm records the assert/release event in the alarm
+// system so it reaches the operators and the alarm history
+func (l *Logic) submitRapidDownwardAlarm(reason string, active bool) {
+	if l.alarmManager == nil {
+		return
+	}
+
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: RapidDownwardAlarmCode,
+		Message:   fmt.Sprintf("Rapid downward signal asserted automatically: %s", reason),
+		Severity:  "HIGH",
+		Active:    active,
+	})
+}
@@ -0,0 +1,55 @@
+package control
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// GetReserveSOC returns the protected islanding reserve SOC in percent;
+// discharge stops at this level regardless of the configured SOC window.
+// The active seasonal profile acts as a floor under the negotiated reserve.
+func (l *Logic) GetReserveSOC() float32 {
+	l.mutex.RLock()
+	reserve := l.reserveSOC
+	l.mutex.RUnlock()
+
+	if seasonal := l.season.ReserveSOC(); seasonal > reserve {
+		reserve = seasonal
+	}
+	return reserve
+}
+
+// SetReserveSOC changes the protected islanding reserve. The reserve must
+// leave a usable window below the maximum SOC; the change is persisted so a
+// restart keeps the negotiated level.
+func (l *Logic) SetReserveSOC(reserve float32, operator string) error {
+	if reserve < 0 || reserve >= l.config.MaxSOC {
+		return fmt.Errorf("reserve SOC must be between 0 and the maximum SOC %.1f", l.config.MaxSOC)
+	}
+
+	l.mutex.Lock()
+	previous := l.reserveSOC
+	l.reserveSOC = reserve
+	l.mutex.Unlock()
+
+	l.log.Warn("Islanding reserve SOC changed",
+		zap.Float32("previous_reserve_soc", previous),
+		zap.Float32("reserve_soc", reserve),
+		zap.String("operator", operator))
+
+	l.persistControlState()
+	return nil
+}
+
+// effectiveMinSOC returns the discharge floor of one BMS string: the higher
+// of its configured minimum SOC and the protected islanding reserve
+func (l *Logic) effectiveMinSOC(limits config.BMSLimits) float32 {
+	minSOC := limits.MinSOC
+	if reserve := l.GetReserveSOC(); reserve > minSOC {
+		minSOC = reserve
+	}
+	return minSOC
+}
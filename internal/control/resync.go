@@ -0,0 +1,386 @@
+package control
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/plc"
+)
+
+// Resynchronization sequence states
+const (
+	ResyncIdle            = "IDLE"
+	ResyncVerifyingGrid   = "VERIFYING_GRID"
+	ResyncClosingBreakers = "CLOSING_BREAKERS"
+	ResyncRamping         = "RAMPING"
+	ResyncComplete        = "COMPLETE"
+	ResyncFailed          = "FAILED"
+)
+
+// ResyncAlarmCode identifies a failed resynchronization sequence
+const ResyncAlarmCode = 9501
+
+// resyncBreakerTimeout is how long a commanded breaker may take to read
+// back closed before the sequence is aborted
+const resyncBreakerTimeout = 10 * time.Second
+
+// resyncBreakerOrder is the closing order after grid return: the grid
+// connection point first, then the auxiliary supply, then the unit
+// transformers
+var resyncBreakerOrder = []struct {
+	name    string
+	closed  func(database.MVCircuitBreakerStatus) bool
+	command func(*plc.Service) error
+}{
+	{
+		name:    "autoproducer CB",
+		closed:  func(s database.MVCircuitBreakerStatus) bool { return s.AutoproducerCB },
+		command: func(s *plc.Service) error { return s.ControlAutoproducerCB(true) },
+	},
+	{
+		name:    "MV aux transformer CB",
+		closed:  func(s database.MVCircuitBreakerStatus) bool { return s.AuxTransformerCB },
+		command: func(s *plc.Service) error { return s.ControlMVAuxTransformerCB(true) },
+	},
+	{
+		name:    "transformer 1 CB",
+		closed:  func(s database.MVCircuitBreakerStatus) bool { return s.Transformer1CB },
+		command: func(s *plc.Service) error { return s.ControlTransformerCB(1, true) },
+	},
+	{
+		name:    "transformer 2 CB",
+		closed:  func(s database.MVCircuitBreakerStatus) bool { return s.Transformer2CB },
+		command: func(s *plc.Service) error { return s.ControlTransformerCB(2, true) },
+	},
+	{
+		name:    "transformer 3 CB",
+		closed:  func(s database.MVCircuitBreakerStatus) bool { return s.Transformer3CB },
+		command: func(s *plc.Service) error { return s.ControlTransformerCB(3, true) },
+	},
+	{
+		name:    "transformer 4 CB",
+		closed:  func(s database.MVCircuitBreakerStatus) bool { return s.Transformer4CB },
+		command: func(s *plc.Service) error { return s.ControlTransformerCB(4, true) },
+	},
+}
+
+// ResyncStatus reports the progress of the resynchronization sequence
+type ResyncStatus struct {
+	State              string    `json:"state"`
+	StartedAt          time.Time `json:"started_at,omitempty"`
+	GridHealthySeconds float64   `json:"grid_healthy_seconds"`
+	BreakerStep        int       `json:"breaker_step"`
+	BreakerStepName    string    `json:"breaker_step_name,omitempty"`
+	RampAllowanceKW    float32   `json:"ramp_allowance_kw"`
+	Failure            string    `json:"failure,omitempty"`
+}
+
+// StartResynchronization starts the supervised resynchronization sequence
+// after a grid return: the grid must measure healthy for the configured
+// time, then the breakers are closed in order and power is released along
+// the configured ramp
+func (l *Logic) StartResynchronization(operator string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	switch l.resyncState {
+	case ResyncVerifyingGrid, ResyncClosingBreakers, ResyncRamping:
+		return fmt.Errorf("resynchronization sequence already running in state %s", l.resyncState)
+	}
+
+	l.resyncState = ResyncVerifyingGrid
+	l.resyncStartedAt = time.Now()
+	l.resyncHealthySince = time.Time{}
+	l.resyncBreakerStep = 0
+	l.resyncBreakerCmdAt = time.Time{}
+	l.resyncRampStartedAt = time.Time{}
+	l.resyncFailure = ""
+
+	l.log.Warn("Resynchronization sequence started",
+		zap.String("operator", operator),
+		zap.Duration("grid_healthy_time", l.config.ResyncGridHealthyTime),
+		zap.Float32("ramp_rate_kw_per_min", l.config.ResyncRampRate))
+
+	return nil
+}
+
+// GetResyncStatus returns the progress of the resynchronization sequence
+func (l *Logic) GetResyncStatus() ResyncStatus {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	status := ResyncStatus{
+		State:           l.resyncState,
+		StartedAt:       l.resyncStartedAt,
+		BreakerStep:     l.resyncBreakerStep,
+		RampAllowanceKW: l.resyncAllowanceKWLocked(),
+		Failure:         l.resyncFailure,
+	}
+	if status.State == "" {
+		status.State = ResyncIdle
+	}
+	if !l.resyncHealthySince.IsZero() {
+		status.GridHealthySeconds = time.Since(l.resyncHealthySince).Seconds()
+	}
+	if l.resyncState == ResyncClosingBreakers && l.resyncBreakerStep < len(resyncBreakerOrder) {
+		status.BreakerStepName = resyncBreakerOrder[l.resyncBreakerStep].name
+	}
+	return status
+}
+
+// superviseResynchronization advances the running resynchronization
+// sequence by one step
+func (l *Logic) superviseResynchronization() {
+	l.mutex.RLock()
+	state := l.resyncState
+	l.mutex.RUnlock()
+
+	switch state {
+	case ResyncVerifyingGrid:
+		l.resyncVerifyGrid()
+	case ResyncClosingBreakers:
+		l.resyncCloseBreakers()
+	case ResyncRamping:
+		l.resyncRamp()
+	}
+}
+
+// resyncVerifyGrid holds the sequence until the grid has measured healthy
+// for the configured time
+func (l *Logic) resyncVerifyGrid() {
+	healthy := l.resyncGridHealthy()
+	now := time.Now()
+
+	l.mutex.Lock()
+	if !healthy {
+		l.resyncHealthySince = time.Time{}
+		l.mutex.Unlock()
+		return
+	}
+	if l.resyncHealthySince.IsZero() {
+		l.resyncHealthySince = now
+	}
+	if now.Sub(l.resyncHealthySince) < l.config.ResyncGridHealthyTime {
+		l.mutex.Unlock()
+		return
+	}
+	l.resyncState = ResyncClosingBreakers
+	l.resyncBreakerStep = 0
+	l.resyncBreakerCmdAt = time.Time{}
+	l.mutex.Unlock()
+
+	l.log.Info("Grid verified healthy, closing breakers",
+		zap.Duration("verified_for", l.config.ResyncGridHealthyTime))
+}
+
+// resyncGridHealthy reports whether the grid measures healthy for
+// reconnection: no anti-islanding protection active and every reported
+// grid frequency inside the islanding window
+func (l *Logic) resyncGridHealthy() bool {
+	for _, service := range l.pcsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		if service.AntiIslandActive() {
+			return false
+		}
+		frequency := service.GetLatestPCSGridData().GridFrequency
+		if frequency > 0 && (frequency < islandFrequencyMin || frequency > islandFrequencyMax) {
+			return false
+		}
+	}
+	return true
+}
+
+// resyncCloseBreakers closes the breakers in order, verifying the read-back
+// of each before commanding the next
+func (l *Logic) resyncCloseBreakers() {
+	l.mutex.RLock()
+	step := l.resyncBreakerStep
+	commandedAt := l.resyncBreakerCmdAt
+	l.mutex.RUnlock()
+
+	if step >= len(resyncBreakerOrder) {
+		l.resyncStartRamp()
+		return
+	}
+	breaker := resyncBreakerOrder[step]
+
+	if l.resyncBreakerClosed(breaker.closed) {
+		l.log.Info("Breaker closed", zap.String("breaker", breaker.name))
+		l.mutex.Lock()
+		l.resyncBreakerStep = step + 1
+		l.resyncBreakerCmdAt = time.Time{}
+		l.mutex.Unlock()
+		return
+	}
+
+	if commandedAt.IsZero() {
+		l.resyncCommandBreaker(breaker.name, breaker.command)
+		return
+	}
+
+	if time.Since(commandedAt) > resyncBreakerTimeout {
+		l.resyncFail(fmt.Sprintf("%s did not close within %s", breaker.name, resyncBreakerTimeout))
+	}
+}
+
+// resyncBreakerClosed reports whether a connected PLC reads the breaker
+// closed
+func (l *Logic) resyncBreakerClosed(closed func(database.MVCircuitBreakerStatus) bool) bool {
+	if l.plcManager == nil {
+		return false
+	}
+	for _, service := range l.plcManager.GetAllServices() {
+		if service.IsConnected() && closed(service.GetLatestPLCData().MVCircuitBreakers) {
+			return true
+		}
+	}
+	return false
+}
+
+// resyncCommandBreaker issues the close command on every connected PLC
+func (l *Logic) resyncCommandBreaker(name string, command func(*plc.Service) error) {
+	if l.plcManager == nil {
+		l.resyncFail("no PLC available to command breakers")
+		return
+	}
+
+	commanded := false
+	for id, service := range l.plcManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		if err := command(service); err != nil {
+			l.log.Error("Failed to command breaker close",
+				zap.String("breaker", name),
+				zap.Int("plc_id", id),
+				zap.Error(err))
+			continue
+		}
+		commanded = true
+	}
+	if !commanded {
+		l.resyncFail(fmt.Sprintf("no connected PLC accepted the %s close command", name))
+		return
+	}
+
+	l.log.Info("Breaker close commanded", zap.String("breaker", name))
+	l.mutex.Lock()
+	l.resyncBreakerCmdAt = time.Now()
+	l.mutex.Unlock()
+}
+
+// resyncStartRamp starts the power release ramp, or completes the sequence
+// directly when no ramp rate is configured
+func (l *Logic) resyncStartRamp() {
+	l.mutex.Lock()
+	if l.config.ResyncRampRate <= 0 {
+		l.resyncState = ResyncComplete
+		l.mutex.Unlock()
+		l.resyncComplete()
+		return
+	}
+	l.resyncState = ResyncRamping
+	l.resyncRampStartedAt = time.Now()
+	l.mutex.Unlock()
+
+	l.log.Info("All breakers closed, releasing power",
+		zap.Float32("ramp_rate_kw_per_min", l.config.ResyncRampRate))
+}
+
+// resyncRamp supervises the power release ramp and completes the sequence
+// once the allowance no longer constrains any PCS
+func (l *Logic) resyncRamp() {
+	if l.GetGridState() == GridStateIslanded {
+		l.resyncFail("islanding detected during power release")
+		return
+	}
+
+	l.mutex.Lock()
+	allowance := l.resyncAllowanceKWLocked()
+	limit := 2 * max(l.config.MaxChargePower, l.config.MaxDischargePower)
+	done := allowance >= limit
+	if done {
+		l.resyncState = ResyncComplete
+	}
+	l.mutex.Unlock()
+
+	if done {
+		l.resyncComplete()
+	}
+}
+
+// resyncAllowanceKWLocked returns the current per-PCS power allowance of
+// the release ramp; the caller must hold the mutex
+func (l *Logic) resyncAllowanceKWLocked() float32 {
+	if l.resyncState != ResyncRamping || l.resyncRampStartedAt.IsZero() {
+		return 0
+	}
+	elapsed := float32(time.Since(l.resyncRampStartedAt).Minutes())
+	return l.config.ResyncRampRate * elapsed
+}
+
+// applyResyncRamp clamps a power command to the release ramp allowance
+// while the resynchronization sequence is ramping
+func (l *Logic) applyResyncRamp(pcsID int, power float32) float32 {
+	l.mutex.RLock()
+	ramping := l.resyncState == ResyncRamping
+	allowance := l.resyncAllowanceKWLocked()
+	l.mutex.RUnlock()
+
+	if !ramping {
+		return power
+	}
+
+	clamped := power
+	if clamped > allowance {
+		clamped = allowance
+	} else if clamped < -allowance {
+		clamped = -allowance
+	}
+	if clamped != power {
+		l.log.Info("Power command limited by resynchronization ramp",
+			zap.Int("pcs_id", pcsID),
+			zap.Float32("requested_power", power),
+			zap.Float32("allowed_power", clamped))
+	}
+	return clamped
+}
+
+// resyncComplete records the completed sequence and clears a failure alarm
+// from an earlier attempt
+func (l *Logic) resyncComplete() {
+	l.log.Info("Resynchronization sequence complete")
+
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: ResyncAlarmCode,
+		Message:   "Resynchronization sequence complete",
+		Severity:  "HIGH",
+		Active:    false,
+	})
+}
+
+// resyncFail aborts the sequence and raises the failure alarm
+func (l *Logic) resyncFail(reason string) {
+	l.mutex.Lock()
+	l.resyncState = ResyncFailed
+	l.resyncFailure = reason
+	l.mutex.Unlock()
+
+	l.log.Error("Resynchronization sequence failed", zap.String("reason", reason))
+
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: ResyncAlarmCode,
+		Message:   "Resynchronization sequence failed: " + reason,
+		Severity:  "HIGH",
+		Active:    true,
+	})
+}
@@ -0,0 +1,229 @@
+package control
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+)
+
+// Safety condition classes of the action matrix
+const (
+	ConditionBMSFault          = "bms_fault"
+	ConditionHeartbeatLost     = "heartbeat_lost"
+	ConditionSOCHighCharging   = "soc_high_charging"
+	ConditionSOCLowDischarging = "soc_low_discharging"
+)
+
+// Safety actions of the action matrix
+const (
+	ActionZeroPower   = "zero_power"
+	ActionStopPCS     = "stop_pcs"
+	ActionOpenBreaker = "open_breaker"
+	ActionAlarm       = "alarm"
+)
+
+// SafetyAlarmCode identifies an executed safety action set
+const SafetyAlarmCode = 9700
+
+// safetyCondition is one active safety condition of a PCS
+type safetyCondition struct {
+	class  string
+	reason string
+}
+
+// defaultSafetyMatrix mirrors the historic hardcoded reaction: every
+// condition zeroes the PCS power
+func defaultSafetyMatrix() map[string]config.SafetyActionConfig {
+	rules := make(map[string]config.SafetyActionConfig)
+	for _, condition := range []string{
+		ConditionBMSFault,
+		ConditionHeartbeatLost,
+		ConditionSOCHighCharging,
+		ConditionSOCLowDischarging,
+	} {
+		rules[condition] = config.SafetyActionConfig{
+			Condition: condition,
+			Actions:   []string{ActionZeroPower},
+		}
+	}
+	return rules
+}
+
+// newSafetyMatrix merges the configured rules over the default matrix
+func newSafetyMatrix(overrides []config.SafetyActionConfig) map[string]config.SafetyActionConfig {
+	rules := defaultSafetyMatrix()
+	for _, rule := range overrides {
+		rules[rule.Condition] = rule
+	}
+	return rules
+}
+
+// executeSafetyMatrix resolves the active conditions of one PCS against
+// the action matrix and executes the union of their action sets, highest
+// priority rule first. Continuous actions (zeroing power) repeat every
+// control cycle; one-shot actions (stop, breaker, alarm) fire on the first
+// cycle a condition becomes active and the alarm clears when the last
+// condition disappears.
+func (l *Logic) executeSafetyMatrix(pcsID int, conditions []safetyCondition) {
+	l.mutex.Lock()
+	wasEngaged := l.safetyEngaged[pcsID]
+	l.safetyEngaged[pcsID] = len(conditions) > 0
+	l.mutex.Unlock()
+
+	if len(conditions) == 0 {
+		if wasEngaged {
+			l.clearSafetyAlarm(pcsID)
+		}
+		return
+	}
+
+	// Order the conditions by rule priority so the reason of the
+	// highest-priority rule leads the log and alarm message
+	sort.SliceStable(conditions, func(i, j int) bool {
+		return l.safetyMatrix[conditions[i].class].Priority > l.safetyMatrix[conditions[j].class].Priority
+	})
+
+	var reasons []string
+	actions := make(map[string]bool)
+	for _, condition := range conditions {
+		reasons = append(reasons, condition.reason)
+		for _, action := range l.safetyMatrix[condition.class].Actions {
+			actions[action] = true
+		}
+	}
+	reason := strings.Join(reasons, ", ")
+
+	if !wasEngaged {
+		l.log.Warn("Executing safety action set",
+			zap.Int("pcs_id", pcsID),
+			zap.String("reason", reason),
+			zap.Strings("actions", sortedActions(actions)))
+	}
+
+	if actions[ActionZeroPower] {
+		l.safetyZeroPower(pcsID)
+	}
+	if !wasEngaged {
+		if actions[ActionStopPCS] {
+			l.safetyStopPCS(pcsID)
+		}
+		if actions[ActionOpenBreaker] {
+			l.safetyOpenBreaker(pcsID)
+		}
+		if actions[ActionAlarm] {
+			l.submitSafetyAlarm(pcsID, reason)
+		}
+	}
+}
+
+// sortedActions returns the action names in a stable order for logging
+func sortedActions(actions map[string]bool) []string {
+	names := make([]string, 0, len(actions))
+	for action := range actions {
+		names = append(names, action)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// safetyZeroPower claims the PCS for the safety source and zeroes its
+// active power
+func (l *Logic) safetyZeroPower(pcsID int) {
+	// Claim the PCS for the safety source so lower-priority sources
+	// cannot immediately re-command it
+	if _, err := l.ArbitratePCSPowerCommand(pcsID, SourceSafety, 0); err != nil {
+		l.log.Error("Failed to claim PCS for safety stop",
+			zap.Error(err),
+			zap.Int("pcs_id", pcsID))
+	}
+
+	service, err := l.pcsManager.GetService(pcsID)
+	if err != nil {
+		return
+	}
+	if err := service.SetActivePowerCommand(0); err != nil {
+		l.log.Error("Failed to set active power to zero",
+			zap.Error(err),
+			zap.Int("pcs_id", pcsID))
+	}
+}
+
+// safetyStopPCS sends the stop command to the PCS
+func (l *Logic) safetyStopPCS(pcsID int) {
+	service, err := l.pcsManager.GetService(pcsID)
+	if err != nil {
+		return
+	}
+	if err := service.StartStopCommand(false); err != nil {
+		l.log.Error("Failed to stop PCS as safety action",
+			zap.Error(err),
+			zap.Int("pcs_id", pcsID))
+	}
+}
+
+// safetyOpenBreaker opens the unit transformer breaker of the PCS via a
+// connected PLC
+func (l *Logic) safetyOpenBreaker(pcsID int) {
+	if l.plcManager == nil {
+		return
+	}
+
+	for id, service := range l.plcManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		if err := service.ControlTransformerCB(uint8(pcsID), false); err != nil {
+			l.log.Error("Failed to open transformer breaker as safety action",
+				zap.Error(err),
+				zap.Int("pcs_id", pcsID),
+				zap.Int("plc_id", id))
+		}
+	}
+}
+
+// submitSafetyAlarm raises the safety action alarm for one PCS
+func (l *Logic) submitSafetyAlarm(pcsID int, reason string) {
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: uint16(SafetyAlarmCode + pcsID),
+		Message:   "Safety actions executed: " + reason,
+		Severity:  "HIGH",
+		Active:    true,
+	})
+}
+
+// clearSafetyAlarm clears the safety action alarm of one PCS once every
+// condition is gone
+func (l *Logic) clearSafetyAlarm(pcsID int) {
+	if !l.safetyMatrixAlarms() {
+		return
+	}
+
+	l.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "CONTROL",
+		AlarmCode: uint16(SafetyAlarmCode + pcsID),
+		Message:   "Safety conditions cleared",
+		Severity:  "HIGH",
+		Active:    false,
+	})
+}
+
+// safetyMatrixAlarms reports whether any rule of the matrix includes the
+// alarm action
+func (l *Logic) safetyMatrixAlarms() bool {
+	for _, rule := range l.safetyMatrix {
+		for _, action := range rule.Actions {
+			if action == ActionAlarm {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package control
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Default selection validity when none is configured
+const defaultSBOTimeout = 30 * time.Second
+
+// sboSelection is an armed select-before-operate token for one action
+type sboSelection struct {
+	action    string
+	expiresAt time.Time
+}
+
+// SBOEnabled reports whether select-before-operate semantics are enforced
+// on dangerous endpoints
+func (l *Logic) SBOEnabled() bool {
+	return l.config.SBOEnabled
+}
+
+// sboTimeout returns the configured selection validity
+func (l *Logic) sboTimeout() time.Duration {
+	if l.config.SBOTimeout > 0 {
+		return l.config.SBOTimeout
+	}
+	return defaultSBOTimeout
+}
+
+// SelectOperation arms a one-time selection token for the given action.
+// The matching execute request must present the token before it expires.
+func (l *Logic) SelectOperation(action string) (string, time.Time, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate selection token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	expiresAt := time.Now().Add(l.sboTimeout())
+
+	l.mutex.Lock()
+	l.pruneSBOSelectionsLocked(time.Now())
+	l.sboSelections[token] = sboSelection{
+		action:    action,
+		expiresAt: expiresAt,
+	}
+	l.mutex.Unlock()
+
+	l.log.Info("Operation selected",
+		zap.String("action", action),
+		zap.Time("expires_at", expiresAt))
+
+	return token, expiresAt, nil
+}
+
+// ConfirmOperation consumes a selection token for the given action. The
+// token is single-use: it is invalidated whether or not it matches.
+func (l *Logic) ConfirmOperation(token string, action string) error {
+	now := time.Now()
+
+	l.mutex.Lock()
+	selection, exists := l.sboSelections[token]
+	delete(l.sboSelections, token)
+	l.pruneSBOSelectionsLocked(now)
+	l.mutex.Unlock()
+
+	if !exists || now.After(selection.expiresAt) {
+		l.log.Warn("Operation rejected - unknown or expired selection token",
+			zap.String("action", action))
+		return fmt.Errorf("unknown or expired selection token")
+	}
+
+	if selection.action != action {
+		l.log.Warn("Operation rejected - selection token does not match action",
+			zap.String("selected_action", selection.action),
+			zap.String("requested_action", action))
+		return fmt.Errorf("selection token was issued for a different action")
+	}
+
+	return nil
+}
+
+// pruneSBOSelectionsLocked drops expired selections; the caller must hold
+// the mutex
+func (l *Logic) pruneSBOSelectionsLocked(now time.Time) {
+	for token, selection := range l.sboSelections {
+		if now.After(selection.expiresAt) {
+			delete(l.sboSelections, token)
+		}
+	}
+}
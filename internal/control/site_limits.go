@@ -0,0 +1,149 @@
+package control
+
+import (
+	"go.uber.org/zap"
+)
+
+// SiteLimitStatus describes the connection-point limit enforcement state
+type SiteLimitStatus struct {
+	ExportLimitKW     float32 `json:"export_limit_kw"`
+	ImportLimitKW     float32 `json:"import_limit_kw"`
+	SiteNetPowerKW    float32 `json:"site_net_power_kw"`
+	ExportHeadroomKW  float32 `json:"export_headroom_kw"`
+	ImportHeadroomKW  float32 `json:"import_headroom_kw"`
+	ClampEvents       uint64  `json:"clamp_events"`
+	ImportClampEvents uint64  `json:"import_clamp_events"`
+}
+
+// ClampPCSPowerCommand clamps a requested active power command for a single
+// PCS so that the total site power at the connection point (wind generation
+// plus all BESS units) never exceeds the effective export or import limit,
+// including an active curtailment order.
+// Every command path (API, Modbus server, control logic) is expected to route
+// its power commands through this method. Positive power means export.
+func (l *Logic) ClampPCSPowerCommand(pcsID int, requested float32) float32 {
+	clamped, otherPower := l.computeSiteClamp(pcsID, requested)
+
+	if clamped != requested {
+		l.mutex.Lock()
+		l.siteClampEvents++
+		// The import clamp is the only one raising the command toward export
+		if clamped > requested {
+			l.importClampEvents++
+		}
+		l.mutex.Unlock()
+
+		l.log.Warn("Power command clamped by site connection-point limit",
+			zap.Int("pcs_id", pcsID),
+			zap.Float32("requested_power", requested),
+			zap.Float32("clamped_power", clamped),
+			zap.Float32("other_site_power", otherPower),
+			zap.Float32("export_limit", l.effectiveExportLimit()),
+			zap.Float32("import_limit", l.effectiveImportLimit()))
+	}
+
+	return clamped
+}
+
+// computeSiteClamp calculates the clamped power for a PCS without recording
+// a clamp event, so it can also be used for dry-run evaluation
+func (l *Logic) computeSiteClamp(pcsID int, requested float32) (float32, float32) {
+	exportLimit := l.effectiveExportLimit()
+	importLimit := l.effectiveImportLimit()
+
+	if exportLimit <= 0 && importLimit <= 0 {
+		return requested, 0
+	}
+
+	// Site power contribution of everything except the PCS being commanded;
+	// DC-coupled PV rides on top of the battery commands
+	otherPower := l.windPowerKW() + l.pvPowerKW()
+	for id, service := range l.pcsManager.GetAllServices() {
+		if id == pcsID {
+			continue
+		}
+		otherPower += service.GetCommandState().ActivePowerCommand
+	}
+
+	clamped := requested
+	if exportLimit > 0 && otherPower+clamped > exportLimit {
+		clamped = exportLimit - otherPower
+	}
+	if importLimit > 0 && otherPower+clamped < -importLimit {
+		clamped = -importLimit - otherPower
+	}
+
+	return clamped, otherPower
+}
+
+// SetPeerExportLimit applies the export allocation negotiated with the EMS
+// of the plant sharing our grid connection point; it bounds the effective
+// export limit like a curtailment order does
+func (l *Logic) SetPeerExportLimit(limitKW float32) {
+	l.mutex.Lock()
+	l.peerExportLimitKW = limitKW
+	l.peerExportLimitSet = true
+	l.mutex.Unlock()
+}
+
+// ClearPeerExportLimit removes the peer export allocation
+func (l *Logic) ClearPeerExportLimit() {
+	l.mutex.Lock()
+	l.peerExportLimitSet = false
+	l.mutex.Unlock()
+}
+
+// windPowerKW returns the total wind farm active power in kW
+func (l *Logic) windPowerKW() float32 {
+	// Wind farm measuring data is reported in MW
+	return l.windFarmManager.GetTotalActivePower() * 1000.0
+}
+
+// pvPowerKW returns the total DC-coupled PV power in kW
+func (l *Logic) pvPowerKW() float32 {
+	var pv float32
+	for _, service := range l.pcsManager.GetAllServices() {
+		pv += service.GetPVPowerKW()
+	}
+	return pv
+}
+
+// siteNetPowerKW returns the measured site net power in kW (positive = export)
+func (l *Logic) siteNetPowerKW() float32 {
+	power := l.windPowerKW()
+	for _, service := range l.bmsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		power += float32(service.GetLatestBMSData().Power)
+	}
+	return power
+}
+
+// GetSiteLimitStatus returns the configured site limits, the current site net
+// power and the remaining headroom in both directions
+func (l *Logic) GetSiteLimitStatus() SiteLimitStatus {
+	l.mutex.RLock()
+	clampEvents := l.siteClampEvents
+	importClampEvents := l.importClampEvents
+	l.mutex.RUnlock()
+
+	netPower := l.siteNetPowerKW()
+
+	status := SiteLimitStatus{
+		ExportLimitKW:     l.effectiveExportLimit(),
+		ImportLimitKW:     l.effectiveImportLimit(),
+		SiteNetPowerKW:    netPower,
+		ClampEvents:       clampEvents,
+		ImportClampEvents: importClampEvents,
+	}
+
+	if status.ExportLimitKW > 0 {
+		status.ExportHeadroomKW = status.ExportLimitKW - netPower
+	}
+	if status.ImportLimitKW > 0 {
+		status.ImportHeadroomKW = status.ImportLimitKW + netPower
+	}
+
+	return status
+}
@@ -0,0 +1,185 @@
+package control
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+)
+
+// SOC calibration states
+const (
+	CalibrationIdle     = "IDLE"
+	CalibrationCharging = "CHARGING"
+)
+
+// SOCCalibrationStatus describes the current state of the SOC calibration cycle
+type SOCCalibrationStatus struct {
+	Enabled            bool      `json:"enabled"`
+	State              string    `json:"state"`
+	LastCompleted      time.Time `json:"last_completed"`
+	NextDue            time.Time `json:"next_due"`
+	StartedAt          time.Time `json:"started_at"`
+	RacksInMaintenance int       `json:"racks_in_maintenance"`
+	CalibrationPowerKW float32   `json:"calibration_power_kw"`
+	WindowStartHour    int       `json:"window_start_hour"`
+	WindowEndHour      int       `json:"window_end_hour"`
+}
+
+// superviseSOCCalibration schedules and supervises periodic SOC-calibration charges.
+// A calibration charge drives all BMS units to full charge so the BMS can
+// rebalance racks and recalibrate its SOC estimate. Charges are only started
+// inside the configured low-demand window and are aborted when the window ends.
+func (l *Logic) superviseSOCCalibration() {
+	if !l.config.SOCCalibrationEnabled {
+		return
+	}
+
+	now := time.Now()
+
+	l.mutex.RLock()
+	state := l.calibrationState
+	lastCompleted := l.calibrationLastCompleted
+	l.mutex.RUnlock()
+
+	switch state {
+	case CalibrationIdle:
+		if now.Sub(lastCompleted) < l.config.SOCCalibrationInterval {
+			return
+		}
+		if !l.inCalibrationWindow(now) {
+			return
+		}
+
+		// Claim all PCS for the scheduler source; a higher-priority
+		// source holding any PCS defers the calibration charge
+		for pcsID := range l.pcsManager.GetAllServices() {
+			if _, err := l.ArbitratePCSPowerCommand(pcsID, SourceScheduler, -l.config.SOCCalibrationPower); err != nil {
+				l.log.Warn("SOC calibration charge deferred", zap.Error(err))
+				return
+			}
+		}
+
+		if err := l.pcsManager.SetActivePowerCommandAll(-l.config.SOCCalibrationPower); err != nil {
+			l.log.Error("Failed to start SOC calibration charge", zap.Error(err))
+			return
+		}
+
+		l.mutex.Lock()
+		l.calibrationState = CalibrationCharging
+		l.calibrationStartedAt = now
+		l.mutex.Unlock()
+
+		l.log.Info("SOC calibration charge started",
+			zap.Float32("charge_power", l.config.SOCCalibrationPower),
+			zap.Time("last_completed", lastCompleted))
+
+	case CalibrationCharging:
+		if !l.inCalibrationWindow(now) {
+			// Window closed before completion - abort and retry next window
+			l.stopCalibrationCharge()
+
+			l.mutex.Lock()
+			l.calibrationState = CalibrationIdle
+			l.mutex.Unlock()
+
+			l.log.Warn("SOC calibration charge aborted - outside calibration window")
+			return
+		}
+
+		if l.allBMSFullyCharged() {
+			l.stopCalibrationCharge()
+
+			l.mutex.Lock()
+			l.calibrationState = CalibrationIdle
+			l.calibrationLastCompleted = now
+			l.mutex.Unlock()
+
+			l.log.Info("SOC calibration charge completed",
+				zap.Duration("duration", now.Sub(l.calibrationStartedAt)))
+		}
+	}
+}
+
+// stopCalibrationCharge sets all PCS active power back to zero and releases
+// the scheduler's claim on them
+func (l *Logic) stopCalibrationCharge() {
+	if err := l.pcsManager.SetActivePowerCommandAll(0); err != nil {
+		l.log.Error("Failed to stop SOC calibration charge", zap.Error(err))
+	}
+
+	for pcsID := range l.pcsManager.GetAllServices() {
+		l.ReleasePCSSetpoint(pcsID, SourceScheduler)
+	}
+}
+
+// inCalibrationWindow checks whether the given time falls inside the configured
+// calibration window; windows crossing midnight are supported. Window hours
+// are interpreted in the market timezone, so the window tracks local wall
+// clock across DST transitions (23- and 25-hour days) while the EMS itself
+// runs on UTC.
+func (l *Logic) inCalibrationWindow(t time.Time) bool {
+	start := l.config.SOCCalibrationWindowStart
+	end := l.config.SOCCalibrationWindowEnd
+	hour := t.In(l.location).Hour()
+
+	if start <= end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// allBMSFullyCharged checks whether every connected BMS reports full charge
+func (l *Logic) allBMSFullyCharged() bool {
+	connected := 0
+	for _, service := range l.bmsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		connected++
+
+		bmsData := service.GetLatestBMSData()
+		bmsStatusData := service.GetLatestBMSStatusData()
+
+		if !bms.IsFullChargeState(bmsStatusData.SystemStatus) && bmsData.SOC < 99.5 {
+			return false
+		}
+	}
+
+	return connected > 0
+}
+
+// GetSOCCalibrationStatus returns the current SOC calibration status including
+// rack-level maintenance indicators reported by the BMS
+func (l *Logic) GetSOCCalibrationStatus() SOCCalibrationStatus {
+	l.mutex.RLock()
+	state := l.calibrationState
+	lastCompleted := l.calibrationLastCompleted
+	startedAt := l.calibrationStartedAt
+	l.mutex.RUnlock()
+
+	racksInMaintenance := 0
+	for _, service := range l.bmsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		for _, rackStatus := range service.GetLatestBMSRackStatusData() {
+			if rackStatus.SOCMaintenanceStatus != 0 {
+				racksInMaintenance++
+			}
+		}
+	}
+
+	return SOCCalibrationStatus{
+		Enabled:            l.config.SOCCalibrationEnabled,
+		State:              state,
+		LastCompleted:      lastCompleted,
+		NextDue:            lastCompleted.Add(l.config.SOCCalibrationInterval),
+		StartedAt:          startedAt,
+		RacksInMaintenance: racksInMaintenance,
+		CalibrationPowerKW: l.config.SOCCalibrationPower,
+		WindowStartHour:    l.config.SOCCalibrationWindowStart,
+		WindowEndHour:      l.config.SOCCalibrationWindowEnd,
+	}
+}
@@ -0,0 +1,42 @@
+package control
+
+import (
+	"go.uber.org/zap"
+)
+
+// throughputDerating returns the dispatch share factor of a PCS based on the
+// rack warranty throughput utilization of its two BMS strings; the tighter
+// string governs
+func (l *Logic) throughputDerating(pcsID int) float32 {
+	var factor float32 = 1
+
+	for _, bmsID := range l.pairedBMSIDs(pcsID) {
+		bmsService, err := l.bmsManager.GetService(bmsID)
+		if err != nil {
+			continue
+		}
+		if derating := bmsService.ThroughputDerating(); derating < factor {
+			factor = derating
+		}
+	}
+
+	return factor
+}
+
+// applyThroughputDerating scales a power command by the throughput derating
+// factor of the PCS and logs when a reduction is in effect
+func (l *Logic) applyThroughputDerating(pcsID int, power float32) float32 {
+	factor := l.throughputDerating(pcsID)
+	if factor >= 1 {
+		return power
+	}
+
+	derated := power * factor
+	l.log.Warn("Power command derated by rack throughput limit",
+		zap.Int("pcs_id", pcsID),
+		zap.Float32("requested_power", power),
+		zap.Float32("derated_power", derated),
+		zap.Float32("derating_factor", factor))
+
+	return derated
+}
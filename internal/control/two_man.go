@@ -0,0 +1,136 @@
+package control
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Default approval timeout when none is configured
+const defaultTwoManApprovalTimeout = 5 * time.Minute
+
+// PendingAction is a critical operation awaiting second-operator approval
+type PendingAction struct {
+	ID          string    `json:"id"`
+	Action      string    `json:"action"`
+	RequestedBy string    `json:"requested_by"`
+	RequestedAt time.Time `json:"requested_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+
+	execute func() error
+}
+
+// TwoManRuleEnabled reports whether critical operations require a second
+// operator's approval
+func (l *Logic) TwoManRuleEnabled() bool {
+	return l.config.TwoManRuleEnabled
+}
+
+// approvalTimeout returns the configured approval timeout
+func (l *Logic) approvalTimeout() time.Duration {
+	if l.config.TwoManApprovalTimeout > 0 {
+		return l.config.TwoManApprovalTimeout
+	}
+	return defaultTwoManApprovalTimeout
+}
+
+// RequestCriticalAction registers a critical operation for second-operator
+// approval and returns its approval ID. The action executes only when
+// approved by a different operator before the timeout expires.
+func (l *Logic) RequestCriticalAction(action string, requestedBy string, execute func() error) (string, error) {
+	now := time.Now()
+
+	l.mutex.Lock()
+	l.pruneExpiredActionsLocked(now)
+	l.pendingActionCounter++
+	pending := &PendingAction{
+		ID:          fmt.Sprintf("action-%d", l.pendingActionCounter),
+		Action:      action,
+		RequestedBy: requestedBy,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(l.approvalTimeout()),
+		execute:     execute,
+	}
+	l.pendingActions[pending.ID] = pending
+	l.mutex.Unlock()
+
+	l.log.Info("Critical action awaiting second-operator approval",
+		zap.String("approval_id", pending.ID),
+		zap.String("action", action),
+		zap.String("requested_by", requestedBy),
+		zap.Time("expires_at", pending.ExpiresAt))
+
+	return pending.ID, nil
+}
+
+// ApproveCriticalAction executes a pending critical action after verifying
+// the approver differs from the requester and the request has not expired
+func (l *Logic) ApproveCriticalAction(id string, approvedBy string) error {
+	now := time.Now()
+
+	l.mutex.Lock()
+	l.pruneExpiredActionsLocked(now)
+
+	pending, exists := l.pendingActions[id]
+	if !exists {
+		l.mutex.Unlock()
+		return fmt.Errorf("pending action %s not found or expired", id)
+	}
+
+	if pending.RequestedBy == approvedBy {
+		l.mutex.Unlock()
+		l.log.Warn("Two-man rule violation: approver matches requester",
+			zap.String("approval_id", id),
+			zap.String("operator", approvedBy))
+		return fmt.Errorf("approval must come from a different operator than the requester")
+	}
+
+	delete(l.pendingActions, id)
+	l.mutex.Unlock()
+
+	l.log.Info("Critical action approved",
+		zap.String("approval_id", id),
+		zap.String("action", pending.Action),
+		zap.String("requested_by", pending.RequestedBy),
+		zap.String("approved_by", approvedBy))
+
+	if err := pending.execute(); err != nil {
+		l.log.Error("Approved critical action failed",
+			zap.String("approval_id", id),
+			zap.String("action", pending.Action),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetPendingActions returns all critical actions awaiting approval
+func (l *Logic) GetPendingActions() []PendingAction {
+	now := time.Now()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.pruneExpiredActionsLocked(now)
+
+	actions := make([]PendingAction, 0, len(l.pendingActions))
+	for _, pending := range l.pendingActions {
+		actions = append(actions, *pending)
+	}
+	return actions
+}
+
+// pruneExpiredActionsLocked drops expired approval requests; the caller must
+// hold the mutex
+func (l *Logic) pruneExpiredActionsLocked(now time.Time) {
+	for id, pending := range l.pendingActions {
+		if now.After(pending.ExpiresAt) {
+			delete(l.pendingActions, id)
+			l.log.Warn("Critical action approval expired",
+				zap.String("approval_id", id),
+				zap.String("action", pending.Action),
+				zap.String("requested_by", pending.RequestedBy))
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+)
+
+// aggregateInterval is the window over which numeric fields are aggregated
+// before being written as separate <measurement>_1m measurements
+const aggregateInterval = time.Minute
+
+// aggregateField accumulates min/max/avg/last for one numeric field over
+// the current aggregation window
+type aggregateField struct {
+	min   float64
+	max   float64
+	sum   float64
+	last  float64
+	count int
+}
+
+// aggregateSeries accumulates the fields of one measurement/tag combination
+type aggregateSeries struct {
+	measurement string
+	tags        map[string]string
+	fields      map[string]*aggregateField
+}
+
+// aggregator computes in-process per-minute aggregates of every numeric
+// field passing through the write path, so constrained sites can persist
+// compact aggregate measurements instead of (or in addition to) raw points
+type aggregator struct {
+	mutex  sync.Mutex
+	series map[string]*aggregateSeries
+}
+
+// newAggregator creates an empty aggregator
+func newAggregator() *aggregator {
+	return &aggregator{
+		series: make(map[string]*aggregateSeries),
+	}
+}
+
+// add folds the numeric fields of a point into the current window
+func (a *aggregator) add(point *write.Point) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	key := point.Name()
+	tags := make(map[string]string, len(point.TagList()))
+	for _, tag := range point.TagList() {
+		tags[tag.Key] = tag.Value
+		key += "," + tag.Key + "=" + tag.Value
+	}
+
+	series, exists := a.series[key]
+	if !exists {
+		series = &aggregateSeries{
+			measurement: point.Name(),
+			tags:        tags,
+			fields:      make(map[string]*aggregateField),
+		}
+		a.series[key] = series
+	}
+
+	for _, field := range point.FieldList() {
+		value, numeric := numericValue(field.Value)
+		if !numeric {
+			continue
+		}
+
+		agg, tracked := series.fields[field.Key]
+		if !tracked {
+			agg = &aggregateField{min: value, max: value}
+			series.fields[field.Key] = agg
+		}
+
+		if value < agg.min {
+			agg.min = value
+		}
+		if value > agg.max {
+			agg.max = value
+		}
+		agg.sum += value
+		agg.last = value
+		agg.count++
+	}
+}
+
+// flush drains the current window into aggregate points stamped with the
+// window end time
+func (a *aggregator) flush(windowEnd time.Time) []*write.Point {
+	a.mutex.Lock()
+	series := a.series
+	a.series = make(map[string]*aggregateSeries)
+	a.mutex.Unlock()
+
+	points := make([]*write.Point, 0, len(series))
+	for _, s := range series {
+		if len(s.fields) == 0 {
+			continue
+		}
+
+		point := influxdb2.NewPointWithMeasurement(s.measurement + "_1m")
+		for key, value := range s.tags {
+			point.AddTag(key, value)
+		}
+
+		// Deterministic field order keeps the line protocol stable
+		names := make([]string, 0, len(s.fields))
+		for name := range s.fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			agg := s.fields[name]
+			point.AddField(name+"_min", agg.min)
+			point.AddField(name+"_max", agg.max)
+			point.AddField(name+"_avg", agg.sum/float64(agg.count))
+			point.AddField(name+"_last", agg.last)
+		}
+		point.AddField("samples", int64(sampleCount(s.fields)))
+		point.SetTime(windowEnd)
+
+		points = append(points, point)
+	}
+	return points
+}
+
+// sampleCount returns the highest sample count across the fields of a series
+func sampleCount(fields map[string]*aggregateField) int {
+	count := 0
+	for _, agg := range fields {
+		if agg.count > count {
+			count = agg.count
+		}
+	}
+	return count
+}
+
+// numericValue converts a line-protocol field value to float64; the client
+// library normalizes numeric field types to int64, uint64 or float64
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// aggregateFlushLoop writes the completed aggregation window every minute,
+// aligned to wall-clock minute boundaries
+func (db *InfluxDB) aggregateFlushLoop() {
+	nextTick := time.Now().Truncate(aggregateInterval).Add(aggregateInterval)
+	timer := time.NewTimer(time.Until(nextTick))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-db.aggregateStop:
+			db.flushAggregates(time.Now())
+			return
+		case <-timer.C:
+			db.flushAggregates(nextTick)
+			nextTick = time.Now().Truncate(aggregateInterval).Add(aggregateInterval)
+			timer.Reset(time.Until(nextTick))
+		}
+	}
+}
+
+// flushAggregates submits the aggregate points directly to the write API,
+// bypassing writePoint so aggregates never feed back into the aggregator
+func (db *InfluxDB) flushAggregates(windowEnd time.Time) {
+	for _, point := range db.aggregates.flush(windowEnd) {
+		db.writeAPI.WritePoint(point)
+	}
+}
+
+// rawDisabledSet builds the lookup of measurements whose raw points are
+// dropped in favour of their aggregates
+func rawDisabledSet(measurements []string) (map[string]bool, error) {
+	disabled := make(map[string]bool, len(measurements))
+	for _, measurement := range measurements {
+		if measurement == "" {
+			return nil, fmt.Errorf("empty measurement name in raw_disabled")
+		}
+		disabled[measurement] = true
+	}
+	return disabled, nil
+}
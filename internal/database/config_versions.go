@@ -0,0 +1,70 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ConfigVersionRecord represents one applied configuration version
+type ConfigVersionRecord struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Hash      string    `gorm:"index;size:64" json:"hash"`
+	AppliedAt time.Time `gorm:"index" json:"applied_at"`
+	Author    string    `gorm:"size:100" json:"author"`
+	Content   string    `gorm:"type:text" json:"-"`
+	Diff      string    `gorm:"type:text" json:"diff"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for ConfigVersionRecord
+func (ConfigVersionRecord) TableName() string {
+	return "config_versions"
+}
+
+// SaveConfigVersion persists a new configuration version
+func (p *PostgreSQL) SaveConfigVersion(record *ConfigVersionRecord) error {
+	if err := p.db.Create(record).Error; err != nil {
+		p.log.Error("Failed to save config version")
+		return fmt.Errorf("failed to save config version: %w", err)
+	}
+	return nil
+}
+
+// GetLatestConfigVersion returns the most recently applied configuration
+// version, or nil when no version has been recorded yet
+func (p *PostgreSQL) GetLatestConfigVersion() (*ConfigVersionRecord, error) {
+	var record ConfigVersionRecord
+	err := p.db.Order("applied_at DESC").First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest config version: %w", err)
+	}
+	return &record, nil
+}
+
+// GetConfigVersions returns configuration versions, newest first
+func (p *PostgreSQL) GetConfigVersions(limit int, offset int) ([]ConfigVersionRecord, error) {
+	var records []ConfigVersionRecord
+	err := p.db.
+		Order("applied_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query config versions: %w", err)
+	}
+	return records, nil
+}
+
+// GetConfigVersion returns one configuration version including its content
+func (p *PostgreSQL) GetConfigVersion(id uint) (*ConfigVersionRecord, error) {
+	var record ConfigVersionRecord
+	if err := p.db.First(&record, id).Error; err != nil {
+		return nil, fmt.Errorf("failed to query config version %d: %w", id, err)
+	}
+	return &record, nil
+}
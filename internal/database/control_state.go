@@ -0,0 +1,44 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ControlStateRecord persists the operator-selected control state across
+// restarts; a single row holds the latest state
+type ControlStateRecord struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	Mode               string    `gorm:"size:20" json:"mode"`
+	ActivePowerEnabled bool      `json:"active_power_enabled"`
+	ActivePowerKW      float32   `json:"active_power_kw"`
+	ReserveSOC         float32   `json:"reserve_soc"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// SaveControlState upserts the persisted control state
+func (p *PostgreSQL) SaveControlState(record ControlStateRecord) error {
+	record.ID = 1
+	record.UpdatedAt = time.Now()
+
+	if err := p.db.Save(&record).Error; err != nil {
+		return fmt.Errorf("failed to save control state: %w", err)
+	}
+	return nil
+}
+
+// GetControlState returns the persisted control state, or nil when none
+// has been saved yet
+func (p *PostgreSQL) GetControlState() (*ControlStateRecord, error) {
+	var record ControlStateRecord
+	err := p.db.First(&record, 1).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get control state: %w", err)
+	}
+	return &record, nil
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// deferredFlushInterval is how often queued writes are replayed once
+	// the store is reachable again
+	deferredFlushInterval = 5 * time.Second
+
+	// maxDeferredWrites bounds the dead-letter queue; a long outage drops
+	// the oldest entries rather than exhausting memory
+	maxDeferredWrites = 1000
+)
+
+// deferredWrite is one record write that failed or arrived while earlier
+// writes were still queued, held for later replay
+type deferredWrite struct {
+	describe string
+	apply    func(db *gorm.DB) error
+	queuedAt time.Time
+}
+
+// deferWrite appends one write to the dead-letter queue, dropping the
+// oldest entry when the queue is full
+func (p *PostgreSQL) deferWrite(describe string, apply func(db *gorm.DB) error) {
+	p.deferredMutex.Lock()
+	defer p.deferredMutex.Unlock()
+
+	if len(p.deferred) >= maxDeferredWrites {
+		dropped := p.deferred[0]
+		p.deferred = p.deferred[1:]
+		p.log.Warn("Deferred write queue full, dropping oldest entry",
+			zap.String("dropped", dropped.describe),
+			zap.Time("queued_at", dropped.queuedAt))
+	}
+
+	p.deferred = append(p.deferred, deferredWrite{
+		describe: describe,
+		apply:    apply,
+		queuedAt: time.Now(),
+	})
+}
+
+// deferredPending reports whether writes are queued for replay. New writes
+// behind a non-empty queue are queued as well so delivery order holds.
+func (p *PostgreSQL) deferredPending() bool {
+	p.deferredMutex.Lock()
+	defer p.deferredMutex.Unlock()
+	return len(p.deferred) > 0
+}
+
+// PendingDeferredWrites returns the number of writes awaiting replay
+func (p *PostgreSQL) PendingDeferredWrites() int {
+	p.deferredMutex.Lock()
+	defer p.deferredMutex.Unlock()
+	return len(p.deferred)
+}
+
+// deferredFlushLoop periodically replays the dead-letter queue
+func (p *PostgreSQL) deferredFlushLoop() {
+	ticker := time.NewTicker(deferredFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.flushStop:
+			return
+		case <-ticker.C:
+			p.flushDeferred()
+		}
+	}
+}
+
+// flushDeferred replays queued writes in arrival order, stopping at the
+// first failure so the remainder keeps its ordering for the next attempt
+func (p *PostgreSQL) flushDeferred() {
+	if !p.Available() {
+		return
+	}
+
+	p.deferredMutex.Lock()
+	defer p.deferredMutex.Unlock()
+
+	replayed := 0
+	for _, write := range p.deferred {
+		if err := write.apply(p.db); err != nil {
+			p.log.Warn("Deferred write replay failed, keeping queue",
+				zap.String("write", write.describe),
+				zap.Int("replayed", replayed),
+				zap.Int("remaining", len(p.deferred)-replayed),
+				zap.Error(err))
+			p.deferred = p.deferred[replayed:]
+			return
+		}
+		replayed++
+	}
+
+	if replayed > 0 {
+		p.log.Info("Deferred writes replayed", zap.Int("count", replayed))
+	}
+	p.deferred = nil
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"sync"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+)
+
+var (
+	writeFaultMutex sync.RWMutex
+	writeFaultHook  func() error
+)
+
+// SetWriteFaultHook installs a process-wide hook that can fail InfluxDB
+// writes on demand; nil removes it. Used by the chaos fault injection
+// tooling to verify buffering and degraded-mode behavior.
+func SetWriteFaultHook(hook func() error) {
+	writeFaultMutex.Lock()
+	defer writeFaultMutex.Unlock()
+	writeFaultHook = hook
+}
+
+// writePoint submits a point to the async write API, or rejects it when a
+// write fault has been injected
+func (db *InfluxDB) writePoint(point *write.Point) error {
+	writeFaultMutex.RLock()
+	hook := writeFaultHook
+	writeFaultMutex.RUnlock()
+
+	if hook != nil {
+		if err := hook(); err != nil {
+			return err
+		}
+	}
+
+	tagSchemaVersion(point)
+	db.forwardPoint(point)
+
+	if db.aggregates != nil {
+		db.aggregates.add(point)
+		if db.rawDisabled[point.Name()] {
+			return nil
+		}
+	}
+
+	db.writeAPI.WritePoint(point)
+	return nil
+}
+
+// forwardPoint mirrors a point into the registered historian forwarder,
+// when one is configured
+func (db *InfluxDB) forwardPoint(point *write.Point) {
+	db.forwardMutex.RLock()
+	forwarder := db.forwarder
+	db.forwardMutex.RUnlock()
+
+	if forwarder == nil {
+		return
+	}
+
+	tags := make(map[string]string, len(point.TagList()))
+	for _, tag := range point.TagList() {
+		tags[tag.Key] = tag.Value
+	}
+	fields := make(map[string]any, len(point.FieldList()))
+	for _, field := range point.FieldList() {
+		fields[field.Key] = field.Value
+	}
+
+	forwarder.Forward(point.Name(), tags, fields, point.Time())
+}
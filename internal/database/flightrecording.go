@@ -0,0 +1,64 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// FlightRecordingRecord persists one frozen flight recorder window around a
+// serious event; the samples are stored as a JSON array
+type FlightRecordingRecord struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TriggerReason string    `json:"trigger_reason"`
+	Severity      string    `json:"severity"`
+	TriggeredAt   time.Time `json:"triggered_at"`
+	Samples       string    `json:"samples,omitempty"` // JSON array of recorder samples
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SaveFlightRecording stores a frozen flight recording
+func (p *PostgreSQL) SaveFlightRecording(record *FlightRecordingRecord) error {
+	if err := p.db.Create(record).Error; err != nil {
+		return fmt.Errorf("failed to save flight recording: %w", err)
+	}
+	return nil
+}
+
+// GetFlightRecordings lists the stored flight recordings, newest first,
+// without the sample payload
+func (p *PostgreSQL) GetFlightRecordings(limit int) ([]FlightRecordingRecord, error) {
+	var records []FlightRecordingRecord
+	err := p.db.Select("id", "trigger_reason", "severity", "triggered_at", "created_at").
+		Order("id DESC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight recordings: %w", err)
+	}
+	return records, nil
+}
+
+// GetFlightRecording returns one stored flight recording with its samples
+func (p *PostgreSQL) GetFlightRecording(id uint) (FlightRecordingRecord, error) {
+	var record FlightRecordingRecord
+	if err := p.db.First(&record, id).Error; err != nil {
+		return FlightRecordingRecord{}, fmt.Errorf("failed to get flight recording %d: %w", id, err)
+	}
+	return record, nil
+}
+
+// PruneFlightRecordings deletes all but the newest keep recordings
+func (p *PostgreSQL) PruneFlightRecordings(keep int) error {
+	var ids []uint
+	if err := p.db.Model(&FlightRecordingRecord{}).Order("id DESC").Limit(keep).Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("failed to prune flight recordings: %w", err)
+	}
+	if len(ids) < keep {
+		return nil
+	}
+
+	if err := p.db.Where("id < ?", ids[len(ids)-1]).Delete(&FlightRecordingRecord{}).Error; err != nil {
+		return fmt.Errorf("failed to prune flight recordings: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
@@ -12,6 +13,10 @@ import (
 	"powerkonnekt/ems/internal/config"
 )
 
+// connectRetryInterval is how often an unreachable database is re-tried in
+// the background after a degraded startup
+const connectRetryInterval = 30 * time.Second
+
 // InfluxDB represents the InfluxDB connection
 type InfluxDB struct {
 	client   influxdb2.Client
@@ -19,6 +24,24 @@ type InfluxDB struct {
 	queryAPI api.QueryAPI
 	config   config.InfluxDBConfig
 	log      *zap.Logger
+
+	stateMutex sync.RWMutex
+	connected  bool
+	retryStop  chan struct{}
+
+	forwardMutex sync.RWMutex
+	forwarder    PointForwarder
+
+	aggregates    *aggregator
+	aggregateStop chan struct{}
+	aggregateDone chan struct{}
+	rawDisabled   map[string]bool
+}
+
+// PointForwarder receives a copy of every telemetry point written to
+// InfluxDB, for mirroring into external plant historians
+type PointForwarder interface {
+	Forward(measurement string, tags map[string]string, fields map[string]any, timestamp time.Time)
 }
 
 // NewInfluxDB initializes the InfluxDB connection
@@ -40,21 +63,6 @@ func NewInfluxDB(cfg config.InfluxDBConfig, logger *zap.Logger) (*InfluxDB, erro
 
 	client := influxdb2.NewClientWithOptions(cfg.URL, cfg.Token, options)
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	health, err := client.Health(ctx)
-	if err != nil {
-		dbLogger.Error("Failed to connect to InfluxDB", zap.Error(err))
-		return nil, fmt.Errorf("failed to connect to InfluxDB: %w", err)
-	}
-
-	if health.Status != "pass" {
-		dbLogger.Error("InfluxDB health check failed", zap.String("status", string(health.Status)))
-		return nil, fmt.Errorf("InfluxDB health check failed: %s", health.Status)
-	}
-
 	writeAPI := client.WriteAPI(cfg.Organization, cfg.Bucket)
 	queryAPI := client.QueryAPI(cfg.Organization)
 
@@ -66,16 +74,118 @@ func NewInfluxDB(cfg config.InfluxDBConfig, logger *zap.Logger) (*InfluxDB, erro
 		log:      dbLogger,
 	}
 
-	dbLogger.Info("InfluxDB connection established successfully",
-		zap.Uint("batch_size", cfg.BatchSize),
-		zap.Duration("flush_interval", cfg.FlushInterval))
+	// An unreachable historian must not block plant operation: the client
+	// batches writes regardless, so start degraded and keep probing in the
+	// background until the connection comes up
+	if err := db.checkHealth(); err != nil {
+		dbLogger.Warn("InfluxDB unreachable at startup, continuing without historian",
+			zap.Error(err))
+		db.retryStop = make(chan struct{})
+		go db.connectRetryLoop()
+	} else {
+		db.setConnected(true)
+	}
+
+	if len(cfg.RawDisabled) > 0 && !cfg.AggregationEnabled {
+		return nil, fmt.Errorf("raw_disabled requires aggregation_enabled, otherwise data would be dropped")
+	}
+
+	if cfg.AggregationEnabled {
+		rawDisabled, err := rawDisabledSet(cfg.RawDisabled)
+		if err != nil {
+			return nil, fmt.Errorf("invalid raw_disabled configuration: %w", err)
+		}
+		db.aggregates = newAggregator()
+		db.aggregateStop = make(chan struct{})
+		db.aggregateDone = make(chan struct{})
+		db.rawDisabled = rawDisabled
+		go func() {
+			defer close(db.aggregateDone)
+			db.aggregateFlushLoop()
+		}()
+
+		dbLogger.Info("In-process aggregation enabled",
+			zap.Duration("window", aggregateInterval),
+			zap.Strings("raw_disabled", cfg.RawDisabled))
+	}
+
+	if db.Available() {
+		dbLogger.Info("InfluxDB connection established successfully",
+			zap.Uint("batch_size", cfg.BatchSize),
+			zap.Duration("flush_interval", cfg.FlushInterval))
+	}
 	return db, nil
 }
 
+// checkHealth probes the InfluxDB health endpoint
+func (db *InfluxDB) checkHealth() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	health, err := db.client.Health(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to InfluxDB: %w", err)
+	}
+	if health.Status != "pass" {
+		return fmt.Errorf("InfluxDB health check failed: %s", health.Status)
+	}
+	return nil
+}
+
+// connectRetryLoop probes an unreachable InfluxDB until it comes up
+func (db *InfluxDB) connectRetryLoop() {
+	ticker := time.NewTicker(connectRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.retryStop:
+			return
+		case <-ticker.C:
+			if err := db.checkHealth(); err != nil {
+				db.log.Debug("InfluxDB still unreachable", zap.Error(err))
+				continue
+			}
+			db.setConnected(true)
+			db.log.Info("InfluxDB connection established after retry")
+			return
+		}
+	}
+}
+
+// setConnected records the connection state
+func (db *InfluxDB) setConnected(connected bool) {
+	db.stateMutex.Lock()
+	db.connected = connected
+	db.stateMutex.Unlock()
+}
+
+// Available reports whether the historian has been reachable
+func (db *InfluxDB) Available() bool {
+	db.stateMutex.RLock()
+	defer db.stateMutex.RUnlock()
+	return db.connected
+}
+
+// SetPointForwarder registers the forwarder that mirrors every written
+// point into the external historians
+func (db *InfluxDB) SetPointForwarder(forwarder PointForwarder) {
+	db.forwardMutex.Lock()
+	db.forwarder = forwarder
+	db.forwardMutex.Unlock()
+}
+
 // Close closes the InfluxDB connection
 func (db *InfluxDB) Close() error {
 	db.log.Info("Closing InfluxDB connection")
 
+	if db.retryStop != nil {
+		close(db.retryStop)
+	}
+	if db.aggregateStop != nil {
+		close(db.aggregateStop)
+		<-db.aggregateDone
+	}
 	if db.writeAPI != nil {
 		db.writeAPI.Flush()
 	}
@@ -89,20 +199,12 @@ func (db *InfluxDB) Close() error {
 
 // HealthCheck checks if InfluxDB is accessible
 func (db *InfluxDB) HealthCheck() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	health, err := db.client.Health(ctx)
-	if err != nil {
+	if err := db.checkHealth(); err != nil {
 		db.log.Error("InfluxDB health check failed", zap.Error(err))
 		return err
 	}
 
-	if health.Status != "pass" {
-		db.log.Error("InfluxDB health check status failed", zap.String("status", string(health.Status)))
-		return fmt.Errorf("InfluxDB health check failed: %s", health.Status)
-	}
-
+	db.setConnected(true)
 	return nil
 }
 
@@ -117,9 +219,7 @@ func (db *InfluxDB) WriteBMSStatusData(data BMSStatusData) error {
 		AddField("step_charge_status", data.StepChargeStatus).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WriteBMSData writes BMS data to InfluxDB
@@ -150,9 +250,7 @@ func (db *InfluxDB) WriteBMSData(data BMSData) error {
 		AddField("insulation_resistance_neg", data.InsulationResistanceNeg).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WriteBMSRackStatusData writes BMS rack status data to InfluxDB
@@ -168,9 +266,7 @@ func (db *InfluxDB) WriteBMSRackStatusData(data BMSRackStatusData) error {
 		AddField("step_charge_status", data.StepChargeStatus).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WriteBMSRackData writes BMS rack data to InfluxDB
@@ -210,9 +306,7 @@ func (db *InfluxDB) WriteBMSRackData(data BMSRackData) error {
 		AddField("cycle_count", data.CycleCount).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WriteBMSCellVoltageData writes BMS cell voltage data to InfluxDB
@@ -229,7 +323,9 @@ func (db *InfluxDB) WriteBMSCellVoltageData(cells []BMSCellVoltageData) error {
 			AddTag("cell_number", fmt.Sprintf("%d", cell.CellNo)).
 			AddField("voltage", cell.Voltage).
 			SetTime(cell.Timestamp)
-		db.writeAPI.WritePoint(point)
+		if err := db.writePoint(point); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -249,7 +345,9 @@ func (db *InfluxDB) WriteBMSCellTemperatureData(cells []BMSCellTemperatureData)
 			AddTag("sensor_number", fmt.Sprintf("%d", cell.SensorNo)).
 			AddField("temperature", cell.Temperature).
 			SetTime(cell.Timestamp)
-		db.writeAPI.WritePoint(point)
+		if err := db.writePoint(point); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -262,9 +360,7 @@ func (db *InfluxDB) WritePCSStatusData(data PCSStatusData) error {
 		AddField("status", data.Status).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WritePCSEquipmentData writes PCS equipment data to InfluxDB
@@ -281,9 +377,7 @@ func (db *InfluxDB) WritePCSEquipmentData(data PCSEquipmentData) error {
 		AddField("dc4_switch_status", data.DC4SwitchStatus).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WritePCSEnvironmentData writes PCS environment data to InfluxDB
@@ -293,9 +387,7 @@ func (db *InfluxDB) WritePCSEnvironmentData(data PCSEnvironmentData) error {
 		AddField("air_inlet_temperature", data.AirInletTemperature).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WritePCSDCSourceData writes PCS DC source data to InfluxDB
@@ -316,9 +408,7 @@ func (db *InfluxDB) WritePCSDCSourceData(data PCSDCSourceData) error {
 		AddField("dc4_voltage_external", data.DC4VoltageExternal).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WritePCSGridData writes PCS grid data to InfluxDB
@@ -348,9 +438,7 @@ func (db *InfluxDB) WritePCSGridData(data PCSGridData) error {
 		AddField("grid_frequency", data.GridFrequency).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WritePCSCounterData writes PCS counter data to InfluxDB
@@ -371,9 +459,7 @@ func (db *InfluxDB) WritePCSCounterData(data PCSCounterData) error {
 		AddField("reactive_energy_total", data.ReactiveEnergyTotal).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WritePLCData writes PLC data to InfluxDB
@@ -405,9 +491,25 @@ func (db *InfluxDB) WritePLCData(data PLCData) error {
 		AddField("relay_transformer4_fault", boolToInt(data.ProtectionRelays.Transformer4Fault)).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
+	// Tap position is only written where the PLC exposes it
+	if data.TapPositionValid {
+		point.AddField("tap_position", int(data.TapPosition))
+	}
 
-	return nil
+	return db.writePoint(point)
+}
+
+// WriteBreakerRecloseEvent writes one auto-reclose journal entry to InfluxDB
+func (db *InfluxDB) WriteBreakerRecloseEvent(data BreakerRecloseEvent) error {
+	point := influxdb2.NewPointWithMeasurement("plc_reclose_events").
+		AddTag("plc_id", fmt.Sprintf("%d", data.PLCID)).
+		AddTag("breaker", data.Breaker).
+		AddField("attempt", data.Attempt).
+		AddField("result", data.Result).
+		AddField("detail", data.Detail).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
 }
 
 // boolToInt converts boolean to integer (1 for true, 0 for false)
@@ -439,11 +541,12 @@ func (db *InfluxDB) WriteWindFarmMeasuringData(data WindFarmMeasuringData) error
 		AddField("absolute_min_reactive_power", data.AbsoluteMinReactivePower).
 		AddField("relative_max_reactive_power", data.RelativeMaxReactivePower).
 		AddField("absolute_max_reactive_power", data.AbsoluteMaxReactivePower).
+		AddField("icing_suspected", data.IcingSuspected).
+		AddField("expected_power", data.ExpectedPower).
+		AddField("power_deviation", data.PowerDeviation).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WriteWindFarmStatusData writes wind farm status data to InfluxDB
@@ -459,9 +562,7 @@ func (db *InfluxDB) WriteWindFarmStatusData(data WindFarmStatusData) error {
 		AddField("rapid_downward_signal_active", data.RapidDownwardSignalActive).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WriteWindFarmSetpointData writes wind farm setpoint data to InfluxDB
@@ -485,15 +586,19 @@ func (db *InfluxDB) WriteWindFarmSetpointData(data WindFarmSetpointData) error {
 		AddField("qdu_setpoint_current", data.QdUSetpointCurrent).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WriteWindFarmWeatherData writes wind farm weather data to InfluxDB
 func (db *InfluxDB) WriteWindFarmWeatherData(data WindFarmWeatherData) error {
+	source := data.Source
+	if source == "" {
+		source = "fcu"
+	}
+
 	point := influxdb2.NewPointWithMeasurement("windfarm_weather").
 		AddTag("id", fmt.Sprintf("%d", data.ID)).
+		AddTag("source", source).
 		AddField("wind_speed_meteo", data.WindSpeedMeteo).
 		AddField("wind_direction_meteo", data.WindDirectionMeteo).
 		AddField("outside_temperature", data.OutsideTemperature).
@@ -505,9 +610,90 @@ func (db *InfluxDB) WriteWindFarmWeatherData(data WindFarmWeatherData) error {
 		AddField("weather_measurements_count", data.WeatherMeasurementsCount).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
+	return db.writePoint(point)
+}
 
-	return nil
+// WriteWindFarmAlarmData writes one FCU alarm list entry to InfluxDB
+func (db *InfluxDB) WriteWindFarmAlarmData(data WindFarmAlarmData) error {
+	point := influxdb2.NewPointWithMeasurement("windfarm_alarm").
+		AddTag("id", fmt.Sprintf("%d", data.ID)).
+		AddField("code", data.Code).
+		AddField("status", data.Status).
+		AddField("active", data.Active).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
+}
+
+// WriteWindFarmKPIData writes the daily wind farm KPIs to InfluxDB
+func (db *InfluxDB) WriteWindFarmKPIData(data WindFarmKPIData) error {
+	point := influxdb2.NewPointWithMeasurement("windfarm_kpi").
+		AddTag("id", fmt.Sprintf("%d", data.ID)).
+		AddField("availability", data.Availability).
+		AddField("performance_ratio", data.PerformanceRatio).
+		AddField("communication_quality", data.CommunicationQuality).
+		AddField("samples", data.Samples).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
+}
+
+// WriteSiteBalanceData writes the computed site energy balance to InfluxDB
+func (db *InfluxDB) WriteSiteBalanceData(data SiteBalanceData) error {
+	point := influxdb2.NewPointWithMeasurement("site_balance").
+		AddField("bess_power_kw", data.BESSPowerKW).
+		AddField("wind_power_kw", data.WindPowerKW).
+		AddField("aux_power_kw", data.AuxPowerKW).
+		AddField("computed_net_kw", data.ComputedNetKW).
+		AddField("measured_net_kw", data.MeasuredNetKW).
+		AddField("measured", data.Measured).
+		AddField("net_power_kw", data.NetPowerKW).
+		AddField("import_energy_kwh", data.ImportEnergyKWh).
+		AddField("export_energy_kwh", data.ExportEnergyKWh).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
+}
+
+// WriteShadowCommandData writes one shadow evaluation sample to InfluxDB
+func (db *InfluxDB) WriteShadowCommandData(data ShadowCommandData) error {
+	point := influxdb2.NewPointWithMeasurement("shadow_command").
+		AddTag("pcs_id", fmt.Sprintf("%d", data.PCSID)).
+		AddTag("strategy", data.Strategy).
+		AddField("actual_kw", data.ActualKW).
+		AddField("shadow_kw", data.ShadowKW).
+		AddField("divergence_kw", data.DivergenceKW).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
+}
+
+// WriteStateEstimateData writes the site-level state estimate to InfluxDB
+func (db *InfluxDB) WriteStateEstimateData(data StateEstimateData) error {
+	point := influxdb2.NewPointWithMeasurement("state_estimate").
+		AddField("meter_ncp_kw", data.MeterNCPKW).
+		AddField("computed_ncp_kw", data.ComputedNCPKW).
+		AddField("estimated_ncp_kw", data.EstimatedNCPKW).
+		AddField("residual_kw", data.ResidualKW).
+		AddField("bad_data_count", data.BadDataCount).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
+}
+
+// WritePowerQualityData writes power quality window statistics to InfluxDB
+func (db *InfluxDB) WritePowerQualityData(data PowerQualityData) error {
+	point := influxdb2.NewPointWithMeasurement("power_quality").
+		AddField("thd_voltage_mean", data.THDVoltageMean).
+		AddField("thd_voltage_max", data.THDVoltageMax).
+		AddField("unbalance_mean", data.UnbalanceMean).
+		AddField("unbalance_max", data.UnbalanceMax).
+		AddField("samples", data.Samples).
+		AddField("thd_compliant", data.THDCompliant).
+		AddField("unbalance_compliant", data.UnbalanceCompliant).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
 }
 
 // WriteION7400Data writes ION7400 energy analyzer data to InfluxDB
@@ -542,6 +728,12 @@ func (db *InfluxDB) WriteION7400Data(data AnalyzerData) error {
 		AddField("power_factor_l3", data.PowerFactorL3).
 		AddField("power_factor_avg", data.PowerFactorAvg).
 		AddField("frequency", data.Frequency).
+		AddField("thd_voltage_l1", data.THDVoltageL1).
+		AddField("thd_voltage_l2", data.THDVoltageL2).
+		AddField("thd_voltage_l3", data.THDVoltageL3).
+		AddField("thd_current_l1", data.THDCurrentL1).
+		AddField("thd_current_l2", data.THDCurrentL2).
+		AddField("thd_current_l3", data.THDCurrentL3).
 		AddField("active_energy_export", data.ActiveEnergyExport).
 		AddField("active_energy_import", data.ActiveEnergyImport).
 		AddField("reactive_energy_export", data.ReactiveEnergyExport).
@@ -550,9 +742,59 @@ func (db *InfluxDB) WriteION7400Data(data AnalyzerData) error {
 		AddField("apparent_energy_import", data.ApparentEnergyImport).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
+	return db.writePoint(point)
+}
+
+// WriteFCRLoopMetrics writes FCR control loop timing metrics to InfluxDB
+func (db *InfluxDB) WriteFCRLoopMetrics(data FCRLoopMetrics) error {
+	point := influxdb2.NewPointWithMeasurement("fcr_loop").
+		AddField("ticks", data.Ticks).
+		AddField("missed_deadlines", data.MissedDeadlines).
+		AddField("jitter_avg_ms", data.JitterAvgMs).
+		AddField("jitter_max_ms", data.JitterMaxMs).
+		SetTime(data.Timestamp)
 
-	return nil
+	return db.writePoint(point)
+}
+
+// WriteAFRRSettlement writes an aFRR settlement sample to InfluxDB
+func (db *InfluxDB) WriteAFRRSettlement(data AFRRSettlement) error {
+	point := influxdb2.NewPointWithMeasurement("afrr_settlement").
+		AddField("setpoint_kw", data.SetpointKW).
+		AddField("output_kw", data.OutputKW).
+		AddField("baseline_kw", data.BaselineKW).
+		AddField("activated_energy_up_kwh", data.ActivatedEnergyUpKWh).
+		AddField("activated_energy_down_kwh", data.ActivatedEnergyDownKWh).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
+}
+
+// WriteFCRDeliveryStatus writes an FCR delivery check to InfluxDB; the
+// non-delivery flag marks the interval for settlement
+func (db *InfluxDB) WriteFCRDeliveryStatus(data FCRDeliveryStatus) error {
+	point := influxdb2.NewPointWithMeasurement("fcr_delivery").
+		AddField("expected_power_kw", data.ExpectedPowerKW).
+		AddField("delivered_power_kw", data.DeliveredPowerKW).
+		AddField("deviation_kw", data.DeviationKW).
+		AddField("non_delivery", data.NonDelivery).
+		AddField("maintained_capacity_kw", data.MaintainedCapacityKW).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
+}
+
+// WriteCommandLatency writes a completed command latency trace to InfluxDB
+func (db *InfluxDB) WriteCommandLatency(data CommandLatency) error {
+	point := influxdb2.NewPointWithMeasurement("command_latency").
+		AddTag("id", fmt.Sprintf("%d", data.PCSID)).
+		AddField("target_power_kw", data.TargetPowerKW).
+		AddField("write_latency_ms", data.WriteLatencyMs).
+		AddField("effect_latency_ms", data.EffectLatencyMs).
+		AddField("effect_seen", data.EffectSeen).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
 }
 
 // WriteSystemMetrics writes system metrics to InfluxDB
@@ -565,9 +807,7 @@ func (db *InfluxDB) WriteSystemMetrics(data SystemMetrics) error {
 		AddField("network_tx", data.NetworkTx).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
-
-	return nil
+	return db.writePoint(point)
 }
 
 // WriteRuntimeMetrics writes runtime metrics to InfluxDB
@@ -593,9 +833,22 @@ func (db *InfluxDB) WriteRuntimeMetrics(data RuntimeMetrics) error {
 		AddField("lookups_total", data.LookupsTotal).
 		SetTime(data.Timestamp)
 
-	db.writeAPI.WritePoint(point)
+	return db.writePoint(point)
+}
 
-	return nil
+// WriteCacheMetrics writes the statistics of one in-memory cache to InfluxDB
+func (db *InfluxDB) WriteCacheMetrics(data CacheMetrics) error {
+	point := influxdb2.NewPointWithMeasurement("cache_metrics").
+		AddTag("name", data.Name).
+		AddField("size", data.Size).
+		AddField("capacity", data.Capacity).
+		AddField("hits", data.Hits).
+		AddField("misses", data.Misses).
+		AddField("evictions", data.Evictions).
+		AddField("hit_rate", data.HitRate).
+		SetTime(data.Timestamp)
+
+	return db.writePoint(point)
 }
 
 // Flush forces writing of any buffered data
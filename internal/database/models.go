@@ -237,6 +237,8 @@ type PLCData struct {
 	CircuitBreakers   CircuitBreakerStatus   `json:"circuit_breakers"`
 	MVCircuitBreakers MVCircuitBreakerStatus `json:"mv_circuit_breakers"`
 	ProtectionRelays  ProtectionRelayStatus  `json:"protection_relays"`
+	TapPosition       int16                  `json:"tap_position"`
+	TapPositionValid  bool                   `json:"tap_position_valid"`
 }
 
 // CircuitBreakerStatus represents the status of all circuit breakers
@@ -271,6 +273,16 @@ type ProtectionRelayStatus struct {
 	Transformer4Fault   bool `json:"transformer4_fault"`
 }
 
+// BreakerRecloseEvent represents one entry of the auto-reclose event journal
+type BreakerRecloseEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	PLCID     int       `json:"plc_id"`
+	Breaker   string    `json:"breaker"`
+	Attempt   int       `json:"attempt"`
+	Result    string    `json:"result"`
+	Detail    string    `json:"detail"`
+}
+
 // =============================================================================
 // Wind Farm (ENERCON FCU) Data Models
 // =============================================================================
@@ -304,6 +316,9 @@ type WindFarmMeasuringData struct {
 	AbsoluteMinReactivePower  float32   `json:"absolute_min_reactive_power"` // MVar, scale 0.01/0.1
 	RelativeMaxReactivePower  float32   `json:"relative_max_reactive_power"` // %, scale 0.01
 	AbsoluteMaxReactivePower  float32   `json:"absolute_max_reactive_power"` // MVar, scale 0.01/0.1
+	IcingSuspected            bool      `json:"icing_suspected"`             // probable icing detected
+	ExpectedPower             float32   `json:"expected_power"`              // MW, from the configured power curve
+	PowerDeviation            float32   `json:"power_deviation"`             // MW, expected minus actual
 }
 
 // WindFarmStatusData represents FCU status data
@@ -346,6 +361,7 @@ type WindFarmSetpointData struct {
 type WindFarmWeatherData struct {
 	Timestamp                time.Time `json:"timestamp"`
 	ID                       int       `json:"id"`
+	Source                   string    `json:"source"`                  // "fcu" or "secondary"
 	WindSpeedMeteo           float32   `json:"wind_speed_meteo"`        // m/s, scale 0.1
 	WindDirectionMeteo       float32   `json:"wind_direction_meteo"`    // degrees, scale 0.1
 	OutsideTemperature       float32   `json:"outside_temperature"`     // °C, scale 0.1
@@ -357,6 +373,62 @@ type WindFarmWeatherData struct {
 	WeatherMeasurementsCount uint16    `json:"weather_measurements_count"`
 }
 
+// WindFarmAlarmData represents one entry of the FCU alarm/event list
+type WindFarmAlarmData struct {
+	Timestamp time.Time `json:"timestamp"`
+	ID        int       `json:"id"`
+	Code      uint16    `json:"code"`
+	Status    uint16    `json:"status"` // raw status word as read from the FCU
+	Active    bool      `json:"active"`
+}
+
+// WindFarmKPIData represents the daily wind farm KPI set
+type WindFarmKPIData struct {
+	Timestamp            time.Time `json:"timestamp"` // start of the KPI day
+	ID                   int       `json:"id"`
+	Availability         float32   `json:"availability"`          // %, daily average of WEC availability
+	PerformanceRatio     float32   `json:"performance_ratio"`     // produced vs possible energy
+	CommunicationQuality float32   `json:"communication_quality"` // %, daily average of WEC communication
+	Samples              uint64    `json:"samples"`
+}
+
+// SiteBalanceData represents the computed site virtual device: the aggregated
+// net power at the network connection point and the cumulative energy balance.
+// Positive power means export.
+type SiteBalanceData struct {
+	Timestamp       time.Time `json:"timestamp"`
+	BESSPowerKW     float32   `json:"bess_power_kw"`
+	WindPowerKW     float32   `json:"wind_power_kw"`
+	AuxPowerKW      float32   `json:"aux_power_kw"`
+	ComputedNetKW   float32   `json:"computed_net_kw"` // BESS + wind - aux
+	MeasuredNetKW   float32   `json:"measured_net_kw"` // grid analyzer, when available
+	Measured        bool      `json:"measured"`
+	NetPowerKW      float32   `json:"net_power_kw"` // measured when available, else computed
+	ImportEnergyKWh float64   `json:"import_energy_kwh"`
+	ExportEnergyKWh float64   `json:"export_energy_kwh"`
+}
+
+// ShadowCommandData represents one shadow evaluation sample: the command of
+// the active controller next to the would-be command of the shadow strategy
+type ShadowCommandData struct {
+	Timestamp    time.Time `json:"timestamp"`
+	PCSID        int       `json:"pcs_id"`
+	Strategy     string    `json:"strategy"`
+	ActualKW     float32   `json:"actual_kw"`
+	ShadowKW     float32   `json:"shadow_kw"`
+	DivergenceKW float32   `json:"divergence_kw"`
+}
+
+// StateEstimateData represents the site-level state estimation result
+type StateEstimateData struct {
+	Timestamp      time.Time `json:"timestamp"`
+	MeterNCPKW     float32   `json:"meter_ncp_kw"`
+	ComputedNCPKW  float32   `json:"computed_ncp_kw"`
+	EstimatedNCPKW float32   `json:"estimated_ncp_kw"`
+	ResidualKW     float32   `json:"residual_kw"`
+	BadDataCount   int       `json:"bad_data_count"`
+}
+
 // WindFarmCommandState represents the current command state for the wind farm
 type WindFarmCommandState struct {
 	LastUpdated              time.Time `json:"last_updated"`
@@ -403,6 +475,12 @@ type AnalyzerData struct {
 	PowerFactorL3        float32   `json:"power_factor_l3"`
 	PowerFactorAvg       float32   `json:"power_factor_avg"`
 	Frequency            float32   `json:"frequency"`
+	THDVoltageL1         float32   `json:"thd_voltage_l1"`
+	THDVoltageL2         float32   `json:"thd_voltage_l2"`
+	THDVoltageL3         float32   `json:"thd_voltage_l3"`
+	THDCurrentL1         float32   `json:"thd_current_l1"`
+	THDCurrentL2         float32   `json:"thd_current_l2"`
+	THDCurrentL3         float32   `json:"thd_current_l3"`
 	ActiveEnergyExport   int64     `json:"active_energy_export"`
 	ActiveEnergyImport   int64     `json:"active_energy_import"`
 	ReactiveEnergyExport int64     `json:"reactive_energy_export"`
@@ -411,6 +489,62 @@ type AnalyzerData struct {
 	ApparentEnergyImport int64     `json:"apparent_energy_import"`
 }
 
+// PowerQualityData represents the EN 50160 compliance statistics of one
+// completed 10-minute observation window
+type PowerQualityData struct {
+	Timestamp          time.Time `json:"timestamp"` // window end
+	THDVoltageMean     float32   `json:"thd_voltage_mean"`
+	THDVoltageMax      float32   `json:"thd_voltage_max"`
+	UnbalanceMean      float32   `json:"unbalance_mean"`
+	UnbalanceMax       float32   `json:"unbalance_max"`
+	Samples            int       `json:"samples"`
+	THDCompliant       bool      `json:"thd_compliant"`
+	UnbalanceCompliant bool      `json:"unbalance_compliant"`
+}
+
+// FCRLoopMetrics represents timing statistics of the FCR fast control loop
+type FCRLoopMetrics struct {
+	Timestamp       time.Time `json:"timestamp"`
+	Ticks           uint64    `json:"ticks"`
+	MissedDeadlines uint64    `json:"missed_deadlines"`
+	JitterAvgMs     float64   `json:"jitter_avg_ms"`
+	JitterMaxMs     float64   `json:"jitter_max_ms"`
+}
+
+// AFRRSettlement represents one aFRR settlement sample: the TSO setpoint,
+// the ramped output around the captured baseline, and the activated
+// energies accumulated since activation
+type AFRRSettlement struct {
+	Timestamp              time.Time `json:"timestamp"`
+	SetpointKW             float32   `json:"setpoint_kw"`
+	OutputKW               float32   `json:"output_kw"`
+	BaselineKW             float32   `json:"baseline_kw"`
+	ActivatedEnergyUpKWh   float64   `json:"activated_energy_up_kwh"`
+	ActivatedEnergyDownKWh float64   `json:"activated_energy_down_kwh"`
+}
+
+// FCRDeliveryStatus represents one FCR delivery check: the expected
+// activation against the power the PCS units actually delivered
+type FCRDeliveryStatus struct {
+	Timestamp            time.Time `json:"timestamp"`
+	ExpectedPowerKW      float32   `json:"expected_power_kw"`
+	DeliveredPowerKW     float32   `json:"delivered_power_kw"`
+	DeviationKW          float32   `json:"deviation_kw"`
+	NonDelivery          bool      `json:"non_delivery"`
+	MaintainedCapacityKW float32   `json:"maintained_capacity_kw"`
+}
+
+// CommandLatency records the end-to-end latency of one active power command
+// for the response-time SLA reporting
+type CommandLatency struct {
+	Timestamp       time.Time `json:"timestamp"`
+	PCSID           int       `json:"pcs_id"`
+	TargetPowerKW   float32   `json:"target_power_kw"`
+	WriteLatencyMs  float64   `json:"write_latency_ms"`
+	EffectLatencyMs float64   `json:"effect_latency_ms"`
+	EffectSeen      bool      `json:"effect_seen"`
+}
+
 // SystemMetrics represents system performance metrics
 type SystemMetrics struct {
 	Timestamp   time.Time `json:"timestamp"`
@@ -452,6 +586,18 @@ type RuntimeMetrics struct {
 	LookupsTotal uint64  `json:"lookups_total"`
 }
 
+// CacheMetrics represents the statistics of one bounded in-memory cache
+type CacheMetrics struct {
+	Timestamp time.Time `json:"timestamp"`
+	Name      string    `json:"name"`
+	Size      int       `json:"size"`
+	Capacity  int       `json:"capacity"`
+	Hits      uint64    `json:"hits"`
+	Misses    uint64    `json:"misses"`
+	Evictions uint64    `json:"evictions"`
+	HitRate   float64   `json:"hit_rate"`
+}
+
 // TelemetryResponse represents the complete telemetry response
 type TelemetryResponse struct {
 	ParkName         string         `json:"park-name"`
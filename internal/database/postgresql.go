@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -16,6 +18,16 @@ import (
 type PostgreSQL struct {
 	db  *gorm.DB
 	log *zap.Logger
+
+	stateMutex sync.RWMutex
+	ready      bool
+	retryStop  chan struct{}
+
+	// Dead-letter queue for alarm and audit writes that failed while the
+	// store was down (see deferred.go)
+	deferredMutex sync.Mutex
+	deferred      []deferredWrite
+	flushStop     chan struct{}
 }
 
 // AlarmRecord represents the alarm table structure
@@ -27,6 +39,17 @@ type AlarmRecord struct {
 	AlarmCode uint16    `json:"alarm_code"`
 	Message   string    `gorm:"size:500" json:"message"`
 	Active    bool      `gorm:"index" json:"active"`
+
+	// Incident opened for this alarm in the external incident management
+	// service, when the connector is enabled
+	IncidentKey string `gorm:"size:100" json:"incident_key,omitempty"`
+	IncidentURL string `gorm:"size:300" json:"incident_url,omitempty"`
+
+	// Operator acknowledgement, tracking who has seen the alarm
+	AckedBy    string     `gorm:"size:100" json:"acked_by,omitempty"`
+	AckedAt    *time.Time `json:"acked_at,omitempty"`
+	AckComment string     `gorm:"size:500" json:"comment,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -53,10 +76,14 @@ func NewPostgreSQL(cfg config.PostgreSQLConfig, logger *zap.Logger) (*PostgreSQL
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: gormLogger.Default.LogMode(gormLogger.Error),
+
+		// The connection is probed explicitly below so an unreachable
+		// server starts degraded instead of failing the boot
+		DisableAutomaticPing: true,
 	})
 	if err != nil {
-		dbLogger.Error("Failed to connect to PostgreSQL", zap.Error(err))
-		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+		dbLogger.Error("Failed to open PostgreSQL connection", zap.Error(err))
+		return nil, fmt.Errorf("failed to open PostgreSQL connection: %w", err)
 	}
 
 	// Configure connection pool
@@ -70,22 +97,29 @@ func NewPostgreSQL(cfg config.PostgreSQLConfig, logger *zap.Logger) (*PostgreSQL
 	sqlDB.SetMaxOpenConns(cfg.MaxOpen)
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
-	// Test connection
-	if err := sqlDB.Ping(); err != nil {
-		dbLogger.Error("Failed to ping PostgreSQL", zap.Error(err))
-		return nil, fmt.Errorf("failed to ping PostgreSQL: %w", err)
+	postgreSQL := &PostgreSQL{
+		db:        db,
+		log:       dbLogger,
+		flushStop: make(chan struct{}),
 	}
+	go postgreSQL.deferredFlushLoop()
 
-	postgreSQL := &PostgreSQL{
-		db:  db,
-		log: dbLogger,
+	// An unreachable persistence store must not block plant operation:
+	// start degraded and migrate in the background once it comes up
+	if err := sqlDB.Ping(); err != nil {
+		dbLogger.Warn("PostgreSQL unreachable at startup, continuing without persistence",
+			zap.Error(err))
+		postgreSQL.retryStop = make(chan struct{})
+		go postgreSQL.connectRetryLoop()
+		return postgreSQL, nil
 	}
 
-	// Auto-migrate the schema
+	// Auto-migrate the schema; a reachable but broken database stays fatal
 	if err := postgreSQL.migrate(); err != nil {
 		dbLogger.Error("Failed to migrate database", zap.Error(err))
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
+	postgreSQL.setReady(true)
 
 	dbLogger.Info("PostgreSQL connection established successfully",
 		zap.Int("max_idle", cfg.MaxIdle),
@@ -93,11 +127,57 @@ func NewPostgreSQL(cfg config.PostgreSQLConfig, logger *zap.Logger) (*PostgreSQL
 	return postgreSQL, nil
 }
 
+// connectRetryLoop probes an unreachable PostgreSQL until it comes up, then
+// runs the schema migration
+func (p *PostgreSQL) connectRetryLoop() {
+	ticker := time.NewTicker(connectRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.retryStop:
+			return
+		case <-ticker.C:
+			sqlDB, err := p.db.DB()
+			if err != nil {
+				return
+			}
+			if err := sqlDB.Ping(); err != nil {
+				p.log.Debug("PostgreSQL still unreachable", zap.Error(err))
+				continue
+			}
+			if err := p.migrate(); err != nil {
+				p.log.Error("Database migration failed after retry", zap.Error(err))
+				continue
+			}
+			p.setReady(true)
+			p.log.Info("PostgreSQL connection established after retry")
+			return
+		}
+	}
+}
+
+// setReady records whether the schema has been migrated and the store is
+// usable
+func (p *PostgreSQL) setReady(ready bool) {
+	p.stateMutex.Lock()
+	p.ready = ready
+	p.stateMutex.Unlock()
+}
+
+// Available reports whether the persistence store has been reachable and
+// migrated
+func (p *PostgreSQL) Available() bool {
+	p.stateMutex.RLock()
+	defer p.stateMutex.RUnlock()
+	return p.ready
+}
+
 // migrate creates or updates the database schema
 func (p *PostgreSQL) migrate() error {
 	p.log.Info("Running database migration")
 
-	err := p.db.AutoMigrate(&AlarmRecord{})
+	err := p.db.AutoMigrate(&AlarmRecord{}, &ConfigVersionRecord{}, &ControlStateRecord{}, &UserRecord{}, &LoginAttemptRecord{}, &WindFarmCommandStateRecord{}, &RackResidencyRecord{}, &FlightRecordingRecord{}, &ScheduleEntryRecord{})
 	if err != nil {
 		p.log.Error("Database migration failed", zap.Error(err))
 		return err
@@ -111,6 +191,11 @@ func (p *PostgreSQL) migrate() error {
 func (p *PostgreSQL) Close() error {
 	p.log.Info("Closing PostgreSQL connection")
 
+	if p.retryStop != nil {
+		close(p.retryStop)
+	}
+	close(p.flushStop)
+
 	sqlDB, err := p.db.DB()
 	if err != nil {
 		return err
@@ -126,7 +211,9 @@ func (p *PostgreSQL) Close() error {
 	return err
 }
 
-// SaveAlarm saves an alarm to PostgreSQL
+// SaveAlarm saves an alarm to PostgreSQL. While the store is down or
+// earlier writes are still queued the alarm goes to the dead-letter queue
+// for ordered replay instead of being lost.
 func (p *PostgreSQL) SaveAlarm(alarm BMSAlarmData) error {
 	record := AlarmRecord{
 		Timestamp: alarm.Timestamp,
@@ -136,13 +223,77 @@ func (p *PostgreSQL) SaveAlarm(alarm BMSAlarmData) error {
 		Message:   alarm.Message,
 		Active:    alarm.Active,
 	}
+	describe := fmt.Sprintf("alarm %s/%d", alarm.AlarmType, alarm.AlarmCode)
+	create := func(db *gorm.DB) error {
+		fresh := record
+		return db.Create(&fresh).Error
+	}
 
-	err := p.db.Create(&record).Error
-	if err != nil {
-		p.log.Error("Failed to save alarm",
+	if !p.Available() || p.deferredPending() {
+		p.deferWrite(describe, create)
+		return nil
+	}
+
+	if err := create(p.db); err != nil {
+		p.log.Warn("Failed to save alarm, deferring for replay",
 			zap.Error(err),
 			zap.String("alarm_type", alarm.AlarmType),
 			zap.Uint16("alarm_code", alarm.AlarmCode))
+		p.deferWrite(describe, create)
+	}
+
+	return nil
+}
+
+// SetAlarmIncident records the incident key and link on the active alarm
+// record of the given type and code
+func (p *PostgreSQL) SetAlarmIncident(alarmType string, alarmCode uint16, incidentKey, incidentURL string) error {
+	err := p.db.Model(&AlarmRecord{}).
+		Where("alarm_type = ? AND alarm_code = ? AND active = ?", alarmType, alarmCode, true).
+		Updates(map[string]any{
+			"incident_key": incidentKey,
+			"incident_url": incidentURL,
+		}).Error
+	if err != nil {
+		p.log.Error("Failed to record alarm incident",
+			zap.Error(err),
+			zap.String("alarm_type", alarmType),
+			zap.Uint16("alarm_code", alarmCode))
+		return err
+	}
+
+	return nil
+}
+
+// GetAlarmByID retrieves one alarm record
+func (p *PostgreSQL) GetAlarmByID(id uint) (AlarmRecord, error) {
+	var alarm AlarmRecord
+	err := p.db.First(&alarm, id).Error
+	if err != nil {
+		p.log.Error("Failed to get alarm by ID",
+			zap.Error(err),
+			zap.Uint("id", id))
+		return AlarmRecord{}, err
+	}
+
+	return alarm, nil
+}
+
+// SetAlarmAcknowledgement records or clears the operator acknowledgement on
+// an alarm record; a nil ackedAt clears it
+func (p *PostgreSQL) SetAlarmAcknowledgement(id uint, ackedBy, comment string, ackedAt *time.Time) error {
+	err := p.db.Model(&AlarmRecord{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"acked_by":    ackedBy,
+			"acked_at":    ackedAt,
+			"ack_comment": comment,
+		}).Error
+	if err != nil {
+		p.log.Error("Failed to record alarm acknowledgement",
+			zap.Error(err),
+			zap.Uint("id", id),
+			zap.String("acked_by", ackedBy))
 		return err
 	}
 
@@ -163,10 +314,11 @@ func (p *PostgreSQL) GetActiveAlarms() ([]AlarmRecord, error) {
 	return alarms, nil
 }
 
-// GetAlarmHistory retrieves alarm history with pagination
-func (p *PostgreSQL) GetAlarmHistory(limit int, offset int) ([]AlarmRecord, error) {
+// GetAlarmHistory retrieves alarm history with pagination; the query is
+// cancelled with the caller's context
+func (p *PostgreSQL) GetAlarmHistory(ctx context.Context, limit int, offset int) ([]AlarmRecord, error) {
 	var alarms []AlarmRecord
-	err := p.db.Order("timestamp desc").
+	err := p.db.WithContext(ctx).Order("timestamp desc").
 		Limit(limit).
 		Offset(offset).
 		Find(&alarms).Error
@@ -219,10 +371,11 @@ func (p *PostgreSQL) GetAlarmsBySeverity(severity string, active bool) ([]AlarmR
 	return alarms, nil
 }
 
-// GetAlarmsInTimeRange retrieves alarms within a time range
-func (p *PostgreSQL) GetAlarmsInTimeRange(start, end time.Time) ([]AlarmRecord, error) {
+// GetAlarmsInTimeRange retrieves alarms within a time range; the query is
+// cancelled with the caller's context
+func (p *PostgreSQL) GetAlarmsInTimeRange(ctx context.Context, start, end time.Time) ([]AlarmRecord, error) {
 	var alarms []AlarmRecord
-	err := p.db.Where("timestamp BETWEEN ? AND ?", start, end).
+	err := p.db.WithContext(ctx).Where("timestamp BETWEEN ? AND ?", start, end).
 		Order("timestamp desc").
 		Find(&alarms).Error
 	if err != nil {
@@ -333,10 +486,18 @@ func (p *PostgreSQL) HealthCheck() error {
 		return err
 	}
 
-	err = sqlDB.Ping()
-	if err != nil {
+	if err := sqlDB.Ping(); err != nil {
 		p.log.Error("PostgreSQL health check failed", zap.Error(err))
+		return err
 	}
 
-	return err
+	if !p.Available() {
+		return fmt.Errorf("PostgreSQL schema migration pending")
+	}
+
+	if pending := p.PendingDeferredWrites(); pending > 0 {
+		return fmt.Errorf("%d deferred writes awaiting replay", pending)
+	}
+
+	return nil
 }
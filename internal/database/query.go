@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// FieldSample is one sample of a stored time series field
+type FieldSample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// QueryFieldSeries reads the stored history of a single measurement field
+// between start and end, ordered by time. Series of different tag values are
+// merged; callers reading multi-device measurements should filter further.
+func (db *InfluxDB) QueryFieldSeries(ctx context.Context, measurement, field string, start, end time.Time) ([]FieldSample, error) {
+	query := fmt.Sprintf(`from(bucket: %q)
+  |> range(start: %s, stop: %s)
+  |> filter(fn: (r) => r._measurement == %q and r._field == %q)
+  |> keep(columns: ["_time", "_value"])
+  |> sort(columns: ["_time"])`,
+		db.config.Bucket,
+		start.UTC().Format(time.RFC3339),
+		end.UTC().Format(time.RFC3339),
+		measurement,
+		field)
+
+	result, err := db.queryAPI.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s.%s: %w", measurement, field, err)
+	}
+	defer result.Close()
+
+	var samples []FieldSample
+	for result.Next() {
+		value, ok := numericValue(result.Record().Value())
+		if !ok {
+			continue
+		}
+		samples = append(samples, FieldSample{
+			Timestamp: result.Record().Time(),
+			Value:     value,
+		})
+	}
+	if result.Err() != nil {
+		return nil, fmt.Errorf("failed to read query result for %s.%s: %w", measurement, field, result.Err())
+	}
+
+	return samples, nil
+}
+
+// QueryDeviceFieldSeries reads the stored history of a single measurement
+// field of one device between start and end, ordered by time
+func (db *InfluxDB) QueryDeviceFieldSeries(ctx context.Context, measurement, field string, deviceID int, start, end time.Time) ([]FieldSample, error) {
+	query := fmt.Sprintf(`from(bucket: %q)
+  |> range(start: %s, stop: %s)
+  |> filter(fn: (r) => r._measurement == %q and r._field == %q and r.id == %q)
+  |> keep(columns: ["_time", "_value"])
+  |> sort(columns: ["_time"])`,
+		db.config.Bucket,
+		start.UTC().Format(time.RFC3339),
+		end.UTC().Format(time.RFC3339),
+		measurement,
+		field,
+		fmt.Sprintf("%d", deviceID))
+
+	result, err := db.queryAPI.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s.%s of device %d: %w", measurement, field, deviceID, err)
+	}
+	defer result.Close()
+
+	var samples []FieldSample
+	for result.Next() {
+		value, ok := numericValue(result.Record().Value())
+		if !ok {
+			continue
+		}
+		samples = append(samples, FieldSample{
+			Timestamp: result.Record().Time(),
+			Value:     value,
+		})
+	}
+	if result.Err() != nil {
+		return nil, fmt.Errorf("failed to read query result for %s.%s of device %d: %w", measurement, field, deviceID, result.Err())
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// RackResidencyRecord persists the accumulated SOC/temperature residency
+// histogram of one BMS rack across restarts; one row per rack
+type RackResidencyRecord struct {
+	ID        uint      `gorm:"primaryKey" json:"id"` // BMS ID * 100 + rack number
+	BMSID     int       `json:"bms_id"`
+	RackNo    uint8     `json:"rack_no"`
+	Histogram string    `json:"histogram"` // JSON [SOC bin][temperature bin] hours
+	Since     time.Time `json:"since"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SaveRackResidency upserts the persisted residency histogram of one rack
+func (p *PostgreSQL) SaveRackResidency(record RackResidencyRecord) error {
+	record.UpdatedAt = time.Now()
+
+	if err := p.db.Save(&record).Error; err != nil {
+		return fmt.Errorf("failed to save rack residency: %w", err)
+	}
+	return nil
+}
+
+// GetRackResidencyRecords returns the persisted residency histograms of all
+// racks of one BMS
+func (p *PostgreSQL) GetRackResidencyRecords(bmsID int) ([]RackResidencyRecord, error) {
+	var records []RackResidencyRecord
+	if err := p.db.Where("bms_id = ?", bmsID).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get rack residency records: %w", err)
+	}
+	return records, nil
+}
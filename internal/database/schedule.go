@@ -0,0 +1,47 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScheduleEntryRecord persists one entry of the active power schedule so a
+// restart resumes the day-ahead plan
+type ScheduleEntryRecord struct {
+	ID         int       `gorm:"primaryKey" json:"id"`
+	StartTime  time.Time `json:"start_time"`
+	SetpointKW float32   `json:"setpoint_kw"`
+	RampRateKW float32   `json:"ramp_rate_kw_per_minute"`
+	TargetSOC  float32   `json:"target_soc"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for ScheduleEntryRecord
+func (ScheduleEntryRecord) TableName() string {
+	return "schedule_entries"
+}
+
+// SaveScheduleEntry upserts one schedule entry
+func (p *PostgreSQL) SaveScheduleEntry(record ScheduleEntryRecord) error {
+	if err := p.db.Save(&record).Error; err != nil {
+		return fmt.Errorf("failed to save schedule entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteScheduleEntry removes one persisted schedule entry
+func (p *PostgreSQL) DeleteScheduleEntry(id int) error {
+	if err := p.db.Delete(&ScheduleEntryRecord{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete schedule entry: %w", err)
+	}
+	return nil
+}
+
+// GetScheduleEntries returns the persisted schedule ordered by start time
+func (p *PostgreSQL) GetScheduleEntries() ([]ScheduleEntryRecord, error) {
+	var records []ScheduleEntryRecord
+	if err := p.db.Order("start_time asc").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get schedule entries: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+)
+
+// defaultSchemaVersion is the version written for measurements whose schema
+// has not changed since the initial release
+const defaultSchemaVersion = 1
+
+// measurementVersions records measurements whose schema moved past version
+// 1. Bump the version here whenever a field is added, renamed or changes
+// meaning, so dashboards can pin or filter on the schema_version tag.
+var measurementVersions = map[string]int{}
+
+// fieldRenames maps, per measurement, a legacy field name to its current
+// name. The write path re-emits the value under the legacy name so
+// dashboards built against the old schema keep working for one deprecation
+// cycle, e.g. "pcs": {"active_power": "mv_grid_active_power"}.
+var fieldRenames = map[string]map[string]string{}
+
+// measurementModels maps each measurement to the Go structs its points are
+// built from; the schema document is generated from their json tags
+var measurementModels = map[string][]interface{}{
+	"bms":                {BMSStatusData{}, BMSData{}},
+	"bms_rack":           {BMSRackStatusData{}, BMSRackData{}},
+	"bms_cell":           {BMSCellVoltageData{}, BMSCellTemperatureData{}},
+	"pcs":                {PCSStatusData{}, PCSEquipmentData{}, PCSEnvironmentData{}, PCSDCSourceData{}, PCSGridData{}, PCSCounterData{}},
+	"plc":                {PLCData{}},
+	"windfarm_measuring": {WindFarmMeasuringData{}},
+	"windfarm_status":    {WindFarmStatusData{}},
+	"windfarm_setpoint":  {WindFarmSetpointData{}},
+	"windfarm_weather":   {WindFarmWeatherData{}},
+	"windfarm_alarm":     {WindFarmAlarmData{}},
+	"windfarm_kpi":       {WindFarmKPIData{}},
+	"site_balance":       {SiteBalanceData{}},
+	"shadow_command":     {ShadowCommandData{}},
+	"state_estimate":     {StateEstimateData{}},
+	"power_quality":      {PowerQualityData{}},
+	"ion7400":            {AnalyzerData{}},
+	"fcr_loop":           {FCRLoopMetrics{}},
+	"fcr_delivery":       {FCRDeliveryStatus{}},
+	"command_latency":    {CommandLatency{}},
+	"system_metrics":     {SystemMetrics{}},
+	"runtime_metrics":    {RuntimeMetrics{}},
+}
+
+// MeasurementSchema documents one measurement for InfluxDB consumers
+type MeasurementSchema struct {
+	Measurement  string            `json:"measurement"`
+	Version      int               `json:"version"`
+	Fields       []string          `json:"fields"`
+	LegacyFields map[string]string `json:"legacy_fields,omitempty"`
+}
+
+// schemaVersion returns the current schema version of a measurement
+func schemaVersion(measurement string) int {
+	if version, changed := measurementVersions[measurement]; changed {
+		return version
+	}
+	return defaultSchemaVersion
+}
+
+// tagSchemaVersion stamps a point with the schema version of its
+// measurement and re-emits renamed fields under their legacy names
+func tagSchemaVersion(point *write.Point) {
+	point.AddTag("schema_version", strconv.Itoa(schemaVersion(point.Name())))
+
+	renames := fieldRenames[point.Name()]
+	if len(renames) == 0 {
+		return
+	}
+
+	current := make(map[string]interface{}, len(point.FieldList()))
+	for _, field := range point.FieldList() {
+		current[field.Key] = field.Value
+	}
+	for legacy, name := range renames {
+		if value, present := current[name]; present {
+			point.AddField(legacy, value)
+		}
+	}
+}
+
+// SchemaDocument generates the measurement schema documentation from the Go
+// model structs, giving InfluxDB consumers a machine-readable upgrade path
+// across EMS releases
+func SchemaDocument() []MeasurementSchema {
+	schemas := make([]MeasurementSchema, 0, len(measurementModels))
+	for measurement, models := range measurementModels {
+		fieldSet := make(map[string]struct{})
+		for _, model := range models {
+			collectFields(reflect.TypeOf(model), fieldSet)
+		}
+
+		fields := make([]string, 0, len(fieldSet))
+		for name := range fieldSet {
+			fields = append(fields, name)
+		}
+		sort.Strings(fields)
+
+		schemas = append(schemas, MeasurementSchema{
+			Measurement:  measurement,
+			Version:      schemaVersion(measurement),
+			Fields:       fields,
+			LegacyFields: fieldRenames[measurement],
+		})
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Measurement < schemas[j].Measurement
+	})
+	return schemas
+}
+
+// collectFields adds the json tag of every persisted struct field; the
+// timestamp and the id tag are not line-protocol fields
+func collectFields(t reflect.Type, fields map[string]struct{}) {
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == "" || tag == "-" || tag == "timestamp" || tag == "id" {
+			continue
+		}
+		fields[tag] = struct{}{}
+	}
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// UserRecord represents one EMS user account. Local accounts carry a
+// password hash; federated accounts (LDAP/OIDC) are authenticated against
+// the corporate directory and only carry role and scoping here.
+type UserRecord struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Username     string    `gorm:"uniqueIndex;size:100" json:"username"`
+	PasswordHash string    `gorm:"size:100" json:"-"`
+	Source       string    `gorm:"size:20" json:"source"` // local, ldap or oidc
+	Role         string    `gorm:"size:20" json:"role"`   // viewer, operator or admin
+	Sites        string    `gorm:"size:300" json:"sites"` // comma-separated park names, empty = all
+	Disabled     bool      `json:"disabled"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for UserRecord
+func (UserRecord) TableName() string {
+	return "users"
+}
+
+// LoginAttemptRecord represents one audited login attempt
+type LoginAttemptRecord struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Timestamp  time.Time `gorm:"index" json:"timestamp"`
+	Username   string    `gorm:"index;size:100" json:"username"`
+	Source     string    `gorm:"size:20" json:"source"`
+	RemoteAddr string    `gorm:"size:60" json:"remote_addr"`
+	Success    bool      `gorm:"index" json:"success"`
+	Detail     string    `gorm:"size:300" json:"detail,omitempty"`
+}
+
+// TableName specifies the table name for LoginAttemptRecord
+func (LoginAttemptRecord) TableName() string {
+	return "login_attempts"
+}
+
+// CreateUser persists a new user account
+func (p *PostgreSQL) CreateUser(record *UserRecord) error {
+	if err := p.db.Create(record).Error; err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// GetUserByUsername returns a user account, or nil when it does not exist
+func (p *PostgreSQL) GetUserByUsername(username string) (*UserRecord, error) {
+	var record UserRecord
+	err := p.db.Where("username = ?", username).First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return &record, nil
+}
+
+// GetUsers returns all user accounts
+func (p *PostgreSQL) GetUsers() ([]UserRecord, error) {
+	var records []UserRecord
+	if err := p.db.Order("username").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	return records, nil
+}
+
+// UpdateUser persists changes to a user account
+func (p *PostgreSQL) UpdateUser(record *UserRecord) error {
+	if err := p.db.Save(record).Error; err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
+// DeleteUser removes a user account
+func (p *PostgreSQL) DeleteUser(username string) error {
+	result := p.db.Where("username = ?", username).Delete(&UserRecord{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user %s not found", username)
+	}
+	return nil
+}
+
+// SaveLoginAttempt persists one audited login attempt. Like alarms, audit
+// entries are deferred for ordered replay while the store is down.
+func (p *PostgreSQL) SaveLoginAttempt(record *LoginAttemptRecord) error {
+	attempt := *record
+	describe := fmt.Sprintf("login attempt %s@%s", attempt.Username, attempt.RemoteAddr)
+	create := func(db *gorm.DB) error {
+		fresh := attempt
+		return db.Create(&fresh).Error
+	}
+
+	if !p.Available() || p.deferredPending() {
+		p.deferWrite(describe, create)
+		return nil
+	}
+
+	if err := create(p.db); err != nil {
+		p.log.Warn("Failed to save login attempt, deferring for replay", zap.Error(err))
+		p.deferWrite(describe, create)
+	}
+
+	return nil
+}
+
+// GetLoginAttempts returns the most recent login attempts, newest first
+func (p *PostgreSQL) GetLoginAttempts(limit int) ([]LoginAttemptRecord, error) {
+	var records []LoginAttemptRecord
+	err := p.db.Order("timestamp DESC").Limit(limit).Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query login attempts: %w", err)
+	}
+	return records, nil
+}
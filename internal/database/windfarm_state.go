@@ -0,0 +1,45 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WindFarmCommandStateRecord persists the last commanded wind farm
+// setpoints across restarts and FCU reconnects; one row per wind farm
+type WindFarmCommandStateRecord struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	PSetpoint           float32   `json:"p_setpoint"`
+	QSetpoint           float32   `json:"q_setpoint"`
+	PowerFactorSetpoint float32   `json:"power_factor_setpoint"`
+	WindFarmStartStop   uint16    `json:"wind_farm_start_stop"`
+	RapidDownwardSignal uint16    `json:"rapid_downward_signal"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// SaveWindFarmCommandState upserts the persisted command state of one wind
+// farm
+func (p *PostgreSQL) SaveWindFarmCommandState(record WindFarmCommandStateRecord) error {
+	record.UpdatedAt = time.Now()
+
+	if err := p.db.Save(&record).Error; err != nil {
+		return fmt.Errorf("failed to save wind farm command state: %w", err)
+	}
+	return nil
+}
+
+// GetWindFarmCommandState returns the persisted command state of one wind
+// farm, or nil when none has been saved yet
+func (p *PostgreSQL) GetWindFarmCommandState(id int) (*WindFarmCommandStateRecord, error) {
+	var record WindFarmCommandStateRecord
+	err := p.db.First(&record, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wind farm command state: %w", err)
+	}
+	return &record, nil
+}
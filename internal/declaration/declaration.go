@@ -0,0 +1,384 @@
+// Package declaration compiles the daily plant availability declaration
+// for the off-taker: hourly available power and energy derived from device
+// health, SOC and the registered maintenance windows. A compiled
+// declaration is held for operator review and delivered over the
+// configured channels (email, SFTP, API) once approved.
+package declaration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/pcs"
+)
+
+// Declaration states
+const (
+	StatusPendingReview  = "PENDING_REVIEW"
+	StatusApproved       = "APPROVED"
+	StatusDelivered      = "DELIVERED"
+	StatusDeliveryFailed = "DELIVERY_FAILED"
+)
+
+// compileCheckInterval is the cadence at which the compile hour is checked
+const compileCheckInterval = time.Minute
+
+// maxMaintenanceWindows bounds the registered windows
+const maxMaintenanceWindows = 100
+
+// HourlyAvailability is the declared availability of one hour
+type HourlyAvailability struct {
+	Start              time.Time `json:"start"`
+	AvailablePowerKW   float32   `json:"available_power_kw"`
+	AvailableEnergyKWh float32   `json:"available_energy_kwh"`
+	Derated            bool      `json:"derated"`
+	Note               string    `json:"note,omitempty"`
+}
+
+// Declaration is one daily availability declaration
+type Declaration struct {
+	Date          string               `json:"date"` // Declaration day, "2006-01-02" in market time
+	GeneratedAt   time.Time            `json:"generated_at"`
+	Hours         []HourlyAvailability `json:"hours"`
+	Status        string               `json:"status"`
+	ApprovedBy    string               `json:"approved_by,omitempty"`
+	ApprovedAt    time.Time            `json:"approved_at,omitempty"`
+	DeliveredAt   time.Time            `json:"delivered_at,omitempty"`
+	DeliveryError string               `json:"delivery_error,omitempty"`
+}
+
+// MaintenanceWindow derates the declared power over one time range; zero
+// power takes the plant out of the declaration entirely
+type MaintenanceWindow struct {
+	ID        int       `json:"id"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	PowerKW   float32   `json:"power_kw"` // Declared power during the window
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Service compiles, holds and delivers the daily availability declaration
+type Service struct {
+	config       *config.Config
+	bmsManager   *bms.Manager
+	pcsManager   *pcs.Manager
+	controlLogic *control.Logic
+	location     *time.Location
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	log          *zap.Logger
+
+	mutex        sync.RWMutex
+	current      *Declaration
+	lastCompiled string // Declaration day of the last automatic compile
+	windows      []MaintenanceWindow
+	nextWindowID int
+}
+
+// NewService creates a new availability declaration service
+func NewService(cfg *config.Config, bmsManager *bms.Manager, pcsManager *pcs.Manager, controlLogic *control.Logic, logger *zap.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	serviceLogger := logger.With(
+		zap.String("component", "declaration"),
+	)
+
+	return &Service{
+		config:       cfg,
+		bmsManager:   bmsManager,
+		pcsManager:   pcsManager,
+		controlLogic: controlLogic,
+		location:     cfg.EMS.MarketLocation(),
+		ctx:          ctx,
+		cancel:       cancel,
+		log:          serviceLogger,
+		nextWindowID: 1,
+	}
+}
+
+// Start begins the compile scheduler
+func (s *Service) Start() error {
+	if !s.config.Declaration.Enabled {
+		s.log.Info("Availability declaration disabled")
+		return nil
+	}
+
+	s.wg.Go(s.compileLoop)
+	s.log.Info("Availability declaration service started",
+		zap.Int("compile_hour", s.config.Declaration.CompileHour))
+	return nil
+}
+
+// Stop stops the compile scheduler
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	s.log.Info("Availability declaration service stopped")
+}
+
+// compileLoop compiles the declaration when the compile hour is reached
+func (s *Service) compileLoop() {
+	ticker := time.NewTicker(compileCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().In(s.location)
+			if now.Hour() < s.config.Declaration.CompileHour {
+				continue
+			}
+
+			day := declarationDay(now)
+			s.mutex.RLock()
+			compiled := s.lastCompiled == day
+			s.mutex.RUnlock()
+			if compiled {
+				continue
+			}
+
+			declaration := s.Compile()
+			s.mutex.Lock()
+			s.lastCompiled = day
+			s.mutex.Unlock()
+
+			s.log.Info("Availability declaration compiled, awaiting review",
+				zap.String("date", declaration.Date),
+				zap.Int("hours", len(declaration.Hours)))
+		}
+	}
+}
+
+// Compile builds a fresh declaration for the next declaration day from the
+// current plant condition and the registered maintenance windows, replacing
+// any unapproved one
+func (s *Service) Compile() Declaration {
+	now := time.Now().In(s.location)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location).AddDate(0, 0, 1)
+
+	power, energy := s.plantAvailability()
+
+	declaration := Declaration{
+		Date:        dayStart.Format("2006-01-02"),
+		GeneratedAt: time.Now(),
+		Status:      StatusPendingReview,
+	}
+
+	for hour := 0; hour < 24; hour++ {
+		start := dayStart.Add(time.Duration(hour) * time.Hour)
+		entry := HourlyAvailability{
+			Start:            start,
+			AvailablePowerKW: power,
+			// The hourly energy is bounded by the declared power; the
+			// usable reservoir additionally caps it near the SOC limits
+			AvailableEnergyKWh: min(power, energy),
+		}
+
+		if window, limited := s.windowCovering(start, start.Add(time.Hour)); limited {
+			if window.PowerKW < entry.AvailablePowerKW {
+				entry.AvailablePowerKW = window.PowerKW
+				entry.AvailableEnergyKWh = min(window.PowerKW, entry.AvailableEnergyKWh)
+			}
+			entry.Derated = true
+			entry.Note = fmt.Sprintf("maintenance: %s", window.Reason)
+		}
+
+		declaration.Hours = append(declaration.Hours, entry)
+	}
+
+	s.mutex.Lock()
+	s.current = &declaration
+	s.mutex.Unlock()
+
+	return declaration
+}
+
+// plantAvailability derives the available power (kW) and usable energy
+// (kWh) from the nameplate ratings, device health and the current SOC
+func (s *Service) plantAvailability() (float32, float32) {
+	ratedPower := s.config.Plant.RatedPowerKW
+	if ratedPower <= 0 {
+		ratedPower = s.config.EMS.MaxDischargePower
+	}
+
+	pcsTotal, pcsConnected := 0, 0
+	for _, service := range s.pcsManager.GetAllServices() {
+		pcsTotal++
+		if service.IsConnected() {
+			pcsConnected++
+		}
+	}
+
+	racksTotal, racksConnected := 0, 0
+	socSum, socCount := float32(0), 0
+	for _, service := range s.bmsManager.GetAllServices() {
+		status := service.GetLatestBMSStatusData()
+		racksTotal += int(status.TotalRacks)
+		if service.IsConnected() {
+			racksConnected += int(status.ConnectedRacks)
+			socSum += float32(service.GetLatestBMSData().SOC)
+			socCount++
+		}
+	}
+
+	ratio := availabilityRatio(pcsConnected, pcsTotal) * availabilityRatio(racksConnected, racksTotal)
+	power := ratedPower * ratio
+
+	// Usable energy between the current SOC and the protected discharge
+	// floor, scaled by the connected fraction of the reservoir
+	energy := float32(0)
+	if socCount > 0 && s.config.Plant.RatedEnergyKWh > 0 {
+		soc := socSum / float32(socCount)
+		floor := s.controlLogic.GetReserveSOC()
+		if usable := (soc - floor) / 100; usable > 0 {
+			energy = s.config.Plant.RatedEnergyKWh * usable * availabilityRatio(racksConnected, racksTotal)
+		}
+	}
+
+	return power, energy
+}
+
+// Approve marks the pending declaration approved and delivers it in the
+// background
+func (s *Service) Approve(operator string) (Declaration, error) {
+	s.mutex.Lock()
+	if s.current == nil {
+		s.mutex.Unlock()
+		return Declaration{}, fmt.Errorf("no declaration compiled")
+	}
+	if s.current.Status != StatusPendingReview {
+		status := s.current.Status
+		s.mutex.Unlock()
+		return Declaration{}, fmt.Errorf("declaration is %s, not pending review", status)
+	}
+
+	s.current.Status = StatusApproved
+	s.current.ApprovedBy = operator
+	s.current.ApprovedAt = time.Now()
+	declaration := *s.current
+	s.mutex.Unlock()
+
+	s.log.Info("Availability declaration approved",
+		zap.String("date", declaration.Date),
+		zap.String("operator", operator))
+
+	s.wg.Go(func() { s.deliver(declaration) })
+
+	return declaration, nil
+}
+
+// Current returns the latest declaration
+func (s *Service) Current() (Declaration, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.current == nil {
+		return Declaration{}, false
+	}
+	return *s.current, true
+}
+
+// AddMaintenanceWindow registers a maintenance window for the compiler
+func (s *Service) AddMaintenanceWindow(start, end time.Time, powerKW float32, reason string) (MaintenanceWindow, error) {
+	if !end.After(start) {
+		return MaintenanceWindow{}, fmt.Errorf("window end must be after its start")
+	}
+	if powerKW < 0 {
+		return MaintenanceWindow{}, fmt.Errorf("window power must not be negative")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.windows) >= maxMaintenanceWindows {
+		return MaintenanceWindow{}, fmt.Errorf("maintenance window limit of %d reached", maxMaintenanceWindows)
+	}
+
+	window := MaintenanceWindow{
+		ID:        s.nextWindowID,
+		Start:     start,
+		End:       end,
+		PowerKW:   powerKW,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	s.nextWindowID++
+	s.windows = append(s.windows, window)
+	sort.Slice(s.windows, func(i, j int) bool { return s.windows[i].Start.Before(s.windows[j].Start) })
+
+	s.log.Info("Maintenance window registered",
+		zap.Int("id", window.ID),
+		zap.Time("start", start),
+		zap.Time("end", end),
+		zap.Float32("power_kw", powerKW),
+		zap.String("reason", reason))
+
+	return window, nil
+}
+
+// RemoveMaintenanceWindow deletes a registered maintenance window
+func (s *Service) RemoveMaintenanceWindow(id int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, window := range s.windows {
+		if window.ID == id {
+			s.windows = append(s.windows[:i], s.windows[i+1:]...)
+			s.log.Info("Maintenance window removed", zap.Int("id", id))
+			return nil
+		}
+	}
+	return fmt.Errorf("maintenance window %d not found", id)
+}
+
+// MaintenanceWindows returns the registered maintenance windows
+func (s *Service) MaintenanceWindows() []MaintenanceWindow {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]MaintenanceWindow{}, s.windows...)
+}
+
+// windowCovering returns the most restrictive maintenance window
+// overlapping the given hour
+func (s *Service) windowCovering(start, end time.Time) (MaintenanceWindow, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var match MaintenanceWindow
+	found := false
+	for _, window := range s.windows {
+		if window.Start.Before(end) && window.End.After(start) {
+			if !found || window.PowerKW < match.PowerKW {
+				match = window
+				found = true
+			}
+		}
+	}
+	return match, found
+}
+
+// declarationDay is the day a declaration compiled now would cover
+func declarationDay(now time.Time) string {
+	return now.AddDate(0, 0, 1).Format("2006-01-02")
+}
+
+// availabilityRatio is the connected fraction, or full when there is
+// nothing to count
+func availabilityRatio(connected, total int) float32 {
+	if total == 0 {
+		return 1
+	}
+	return float32(connected) / float32(total)
+}
@@ -0,0 +1,215 @@
+package declaration
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+// deliver sends an approved declaration over every enabled channel and
+// records the outcome on the current declaration
+func (s *Service) deliver(declaration Declaration) {
+	cfg := s.config.Declaration
+
+	var errs []string
+	channels := 0
+
+	if cfg.Email.Enabled {
+		channels++
+		if err := s.deliverEmail(declaration); err != nil {
+			errs = append(errs, fmt.Sprintf("email: %v", err))
+			s.log.Error("Declaration email delivery failed", zap.Error(err))
+		}
+	}
+	if cfg.SFTP.Enabled {
+		channels++
+		if err := s.deliverSFTP(declaration); err != nil {
+			errs = append(errs, fmt.Sprintf("sftp: %v", err))
+			s.log.Error("Declaration SFTP delivery failed", zap.Error(err))
+		}
+	}
+	if cfg.Endpoint.Enabled {
+		channels++
+		if err := s.deliverAPI(declaration); err != nil {
+			errs = append(errs, fmt.Sprintf("api: %v", err))
+			s.log.Error("Declaration API delivery failed", zap.Error(err))
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.current == nil || s.current.Date != declaration.Date {
+		return
+	}
+	if len(errs) > 0 {
+		s.current.Status = StatusDeliveryFailed
+		s.current.DeliveryError = strings.Join(errs, "; ")
+		return
+	}
+
+	s.current.Status = StatusDelivered
+	s.current.DeliveredAt = time.Now()
+	s.log.Info("Availability declaration delivered",
+		zap.String("date", declaration.Date),
+		zap.Int("channels", channels))
+}
+
+// declarationCSV renders the declaration in the agreed CSV layout
+func (s *Service) declarationCSV(declaration Declaration) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	if err := writer.Write([]string{"hour_start", "available_power_kw", "available_energy_kwh", "derated", "note"}); err != nil {
+		return nil, err
+	}
+	for _, hour := range declaration.Hours {
+		record := []string{
+			hour.Start.Format(time.RFC3339),
+			fmt.Sprintf("%.1f", hour.AvailablePowerKW),
+			fmt.Sprintf("%.1f", hour.AvailableEnergyKWh),
+			fmt.Sprintf("%t", hour.Derated),
+			hour.Note,
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// declarationFileName is the delivery file name of one declaration
+func (s *Service) declarationFileName(declaration Declaration) string {
+	park := strings.ReplaceAll(s.config.EMS.ParkName, " ", "_")
+	return fmt.Sprintf("availability_%s_%s.csv", park, declaration.Date)
+}
+
+// deliverEmail mails the declaration as a CSV attachment
+func (s *Service) deliverEmail(declaration Declaration) error {
+	cfg := s.config.Declaration.Email
+
+	body, err := s.declarationCSV(declaration)
+	if err != nil {
+		return fmt.Errorf("failed to render declaration: %w", err)
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&message, "Subject: Availability declaration %s - %s\r\n", s.config.EMS.ParkName, declaration.Date)
+	message.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: text/csv; name=%q\r\n", s.declarationFileName(declaration))
+	fmt.Fprintf(&message, "Content-Disposition: attachment; filename=%q\r\n", s.declarationFileName(declaration))
+	message.WriteString("\r\n")
+	message.Write(body)
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.SMTPHost)
+	}
+
+	address := fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort)
+	if err := smtp.SendMail(address, auth, cfg.From, cfg.To, message.Bytes()); err != nil {
+		return fmt.Errorf("failed to send mail via %s: %w", address, err)
+	}
+	return nil
+}
+
+// deliverSFTP uploads the declaration into the off-taker's drop directory
+func (s *Service) deliverSFTP(declaration Declaration) error {
+	cfg := s.config.Declaration.SFTP
+
+	body, err := s.declarationCSV(declaration)
+	if err != nil {
+		return fmt.Errorf("failed to render declaration: %w", err)
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User:            cfg.Username,
+		Auth:            []ssh.AuthMethod{ssh.Password(cfg.Password)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // the drop host is pinned by the OT firewall
+		Timeout:         30 * time.Second,
+	}
+
+	address := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	conn, err := ssh.Dial("tcp", address, sshConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		return fmt.Errorf("failed to open SFTP session: %w", err)
+	}
+	defer client.Close()
+
+	remotePath := path.Join(cfg.RemoteDir, s.declarationFileName(declaration))
+	file, err := client.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", remotePath, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(body); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", remotePath, err)
+	}
+	return nil
+}
+
+// deliverAPI POSTs the declaration as JSON to the off-taker's endpoint
+func (s *Service) deliverAPI(declaration Declaration) error {
+	cfg := s.config.Declaration.Endpoint
+
+	payload := struct {
+		ParkName          string               `json:"park_name"`
+		ConnectionPointID string               `json:"connection_point_id"`
+		Date              string               `json:"date"`
+		GeneratedAt       time.Time            `json:"generated_at"`
+		Hours             []HourlyAvailability `json:"hours"`
+	}{
+		ParkName:          s.config.EMS.ParkName,
+		ConnectionPointID: s.config.Plant.ConnectionPointID,
+		Date:              declaration.Date,
+		GeneratedAt:       declaration.GeneratedAt,
+		Hours:             declaration.Hours,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal declaration: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if cfg.AuthToken != "" {
+		request.Header.Set("Authorization", "Bearer "+cfg.AuthToken)
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("endpoint rejected the declaration: %s", response.Status)
+	}
+	return nil
+}
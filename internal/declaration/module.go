@@ -0,0 +1,37 @@
+package declaration
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/pcs"
+)
+
+// Module provides the availability declaration service to the Fx application
+var Module = fx.Module("declaration",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides a declaration service instance
+func ProvideService(cfg *config.Config, bmsManager *bms.Manager, pcsManager *pcs.Manager, controlLogic *control.Logic, logger *zap.Logger) *Service {
+	return NewService(cfg, bmsManager, pcsManager, controlLogic, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the declaration service
+func RegisterLifecycle(lc fx.Lifecycle, service *Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/pkg/loopwatch"
 )
 
 // EMS represents the main EMS application
@@ -19,6 +20,7 @@ type EMS struct {
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
 	log          *zap.Logger
+	loopWatch    *loopwatch.Loop
 }
 
 // New creates a new EMS instance
@@ -35,6 +37,7 @@ func New(cfg config.EMSConfig, controlLogic *control.Logic, logger *zap.Logger)
 		ctx:          ctx,
 		cancel:       cancel,
 		log:          emsLogger,
+		loopWatch:    loopwatch.NewLoop("control_fallback", 100*time.Millisecond),
 	}
 }
 
@@ -69,7 +72,9 @@ func (e *EMS) reactiveControlLoop() {
 			// controlLogic.ExecuteControl()
 		case <-fallbackTicker.C:
 			// Safety fallback - ensure control runs at least once per 100 milliseconds
+			finish := e.loopWatch.Begin()
 			e.controlLogic.ExecuteControl()
+			finish()
 		}
 	}
 }
@@ -0,0 +1,331 @@
+// Package estimator reconciles the redundant power measurements of the plant
+// (BMS DC power, PCS LV and MV power, meter NCP power) into one consistent
+// power-flow estimate with bad-data detection. Consumers that need a power
+// value should read the estimate instead of picking one raw measurement.
+package estimator
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/analyzer/ion7400"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// estimateInterval is the cadence of the estimation
+const estimateInterval = time.Second
+
+// persistEvery persists one out of this many estimates
+const persistEvery = 10
+
+// Flat conversion efficiencies used to map the measurements of one unit to
+// the MV level. The estimator only needs them to compare measurements on a
+// common basis; small errors show up as residuals, not as bad data.
+const (
+	pcsEfficiency         = 0.97
+	transformerEfficiency = 0.99
+)
+
+// Bad-data thresholds: a measurement is rejected when it deviates from the
+// median of its redundancy group by more than the larger of the absolute
+// floor and the relative fraction of the largest measurement
+const (
+	badDataMinKW    = 25.0
+	badDataFraction = 0.10
+)
+
+// Measurement weights for the weighted fusion, by proximity to the MV level
+const (
+	weightMV    = 4.0
+	weightLV    = 2.0
+	weightDC    = 1.0
+	weightMeter = 3.0
+	weightSum   = 1.0
+)
+
+// UnitEstimate is the reconciled power flow of one BMS/PCS unit, all values
+// mapped to the MV level in kW
+type UnitEstimate struct {
+	PCSID         int      `json:"pcs_id"`
+	BMSDCKW       float32  `json:"bms_dc_kw"`
+	LVPowerKW     float32  `json:"lv_power_kw"`
+	MVPowerKW     float32  `json:"mv_power_kw"`
+	EstimatedKW   float32  `json:"estimated_kw"`
+	MaxResidualKW float32  `json:"max_residual_kw"`
+	BadData       []string `json:"bad_data,omitempty"`
+}
+
+// Estimate is the reconciled site power flow
+type Estimate struct {
+	Timestamp      time.Time      `json:"timestamp"`
+	Units          []UnitEstimate `json:"units"`
+	WindPowerKW    float32        `json:"wind_power_kw"`
+	MeterNCPKW     float32        `json:"meter_ncp_kw"`
+	MeterAvailable bool           `json:"meter_available"`
+	ComputedNCPKW  float32        `json:"computed_ncp_kw"` // unit estimates + wind - aux
+	EstimatedNCPKW float32        `json:"estimated_ncp_kw"`
+	ResidualKW     float32        `json:"residual_kw"` // meter vs computed
+	BadData        []string       `json:"bad_data,omitempty"`
+}
+
+// Service runs the periodic state estimation
+type Service struct {
+	config          config.EMSConfig
+	topology        map[int][]int // Connected BMS strings per PCS
+	bmsManager      *bms.Manager
+	pcsManager      *pcs.Manager
+	windFarmManager *windfarm.Manager
+	analyzer        *ion7400.Service
+	influxDB        *database.InfluxDB
+	ctx             context.Context
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	log             *zap.Logger
+
+	mutex        sync.RWMutex
+	lastEstimate Estimate
+}
+
+// NewService creates the state estimation service
+func NewService(cfg config.EMSConfig, topology map[int][]int, bmsManager *bms.Manager, pcsManager *pcs.Manager, windFarmManager *windfarm.Manager, analyzer *ion7400.Service, influxDB *database.InfluxDB, logger *zap.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Service{
+		config:          cfg,
+		topology:        topology,
+		bmsManager:      bmsManager,
+		pcsManager:      pcsManager,
+		windFarmManager: windFarmManager,
+		analyzer:        analyzer,
+		influxDB:        influxDB,
+		ctx:             ctx,
+		cancel:          cancel,
+		log: logger.With(
+			zap.String("service", "estimator"),
+		),
+	}
+}
+
+// Start starts the estimation loop
+func (s *Service) Start() error {
+	s.wg.Go(s.estimateLoop)
+
+	s.log.Info("State estimation started",
+		zap.Duration("interval", estimateInterval))
+
+	return nil
+}
+
+// Stop stops the estimation loop
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	s.log.Info("State estimation stopped")
+}
+
+// estimateLoop periodically recomputes the estimate
+func (s *Service) estimateLoop() {
+	ticker := time.NewTicker(estimateInterval)
+	defer ticker.Stop()
+
+	samples := 0
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			estimate := s.estimate(now)
+
+			s.mutex.Lock()
+			s.lastEstimate = estimate
+			s.mutex.Unlock()
+
+			samples++
+			if samples >= persistEvery {
+				samples = 0
+				s.persist(estimate)
+			}
+		}
+	}
+}
+
+// estimate reconciles the current measurements into one estimate
+func (s *Service) estimate(now time.Time) Estimate {
+	estimate := Estimate{
+		Timestamp:   now,
+		WindPowerKW: s.windFarmManager.GetTotalActivePower() * 1000.0,
+	}
+
+	var unitSum float32
+	for id, service := range s.pcsManager.GetAllServices() {
+		unit := s.estimateUnit(id, service)
+		unitSum += unit.EstimatedKW
+		estimate.Units = append(estimate.Units, unit)
+	}
+	sort.Slice(estimate.Units, func(i, j int) bool {
+		return estimate.Units[i].PCSID < estimate.Units[j].PCSID
+	})
+
+	estimate.ComputedNCPKW = unitSum + estimate.WindPowerKW - s.config.LossModel.AuxiliaryPower
+
+	if s.analyzer != nil && s.analyzer.IsConnected() {
+		estimate.MeterAvailable = true
+		estimate.MeterNCPKW = s.analyzer.GetLatestData().ActivePowerSum / 1000.0 // W to kW
+	}
+
+	if !estimate.MeterAvailable {
+		estimate.EstimatedNCPKW = estimate.ComputedNCPKW
+		return estimate
+	}
+
+	estimate.ResidualKW = estimate.MeterNCPKW - estimate.ComputedNCPKW
+	if exceedsThreshold(estimate.ResidualKW, estimate.MeterNCPKW, estimate.ComputedNCPKW) {
+		// With only two site-level sources the bad one cannot be identified;
+		// flag the inconsistency and keep the meter, the reference for
+		// settlement
+		estimate.BadData = append(estimate.BadData, "ncp_mismatch")
+		estimate.EstimatedNCPKW = estimate.MeterNCPKW
+		return estimate
+	}
+
+	estimate.EstimatedNCPKW = weightedPair(estimate.MeterNCPKW, weightMeter, estimate.ComputedNCPKW, weightSum)
+	return estimate
+}
+
+// estimateUnit reconciles the three redundant measurements of one unit. The
+// BMS DC power and the LV power are mapped to the MV level with the flat
+// conversion efficiencies; the measurement deviating from the median by more
+// than the bad-data threshold is excluded from the fusion.
+func (s *Service) estimateUnit(pcsID int, service *pcs.Service) UnitEstimate {
+	unit := UnitEstimate{PCSID: pcsID}
+
+	grid := service.GetLatestPCSGridData()
+	unit.MVPowerKW = float32(grid.MVGridActivePower)
+	unit.LVPowerKW = float32(grid.LVGridActivePower)
+
+	// Sum the DC power over the BMS strings feeding this PCS
+	for _, bmsID := range s.topology[pcsID] {
+		bmsService, err := s.bmsManager.GetService(bmsID)
+		if err != nil || !bmsService.IsConnected() {
+			continue
+		}
+		unit.BMSDCKW += float32(bmsService.GetLatestBMSData().Power)
+	}
+
+	candidates := []struct {
+		name   string
+		value  float32
+		weight float32
+	}{
+		{"mv", unit.MVPowerKW, weightMV},
+		{"lv", toMVLevel(unit.LVPowerKW, transformerEfficiency), weightLV},
+		{"dc", toMVLevel(unit.BMSDCKW, pcsEfficiency*transformerEfficiency), weightDC},
+	}
+
+	median := medianOf3(candidates[0].value, candidates[1].value, candidates[2].value)
+
+	var weightTotal, weighted float32
+	for _, candidate := range candidates {
+		residual := candidate.value - median
+		if abs(residual) > unit.MaxResidualKW {
+			unit.MaxResidualKW = abs(residual)
+		}
+		if exceedsThreshold(residual, candidate.value, median) {
+			unit.BadData = append(unit.BadData, candidate.name)
+			continue
+		}
+		weighted += candidate.value * candidate.weight
+		weightTotal += candidate.weight
+	}
+
+	if weightTotal > 0 {
+		unit.EstimatedKW = weighted / weightTotal
+	} else {
+		unit.EstimatedKW = median
+	}
+
+	return unit
+}
+
+// persist writes the site-level estimate to InfluxDB
+func (s *Service) persist(estimate Estimate) {
+	badData := len(estimate.BadData)
+	for _, unit := range estimate.Units {
+		badData += len(unit.BadData)
+	}
+
+	data := database.StateEstimateData{
+		Timestamp:      estimate.Timestamp,
+		MeterNCPKW:     estimate.MeterNCPKW,
+		ComputedNCPKW:  estimate.ComputedNCPKW,
+		EstimatedNCPKW: estimate.EstimatedNCPKW,
+		ResidualKW:     estimate.ResidualKW,
+		BadDataCount:   badData,
+	}
+
+	if err := s.influxDB.WriteStateEstimateData(data); err != nil {
+		s.log.Error("Failed to write state estimate", zap.Error(err))
+	}
+}
+
+// GetEstimate returns the latest estimate
+func (s *Service) GetEstimate() Estimate {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.lastEstimate
+}
+
+// weightedPair fuses two values by their weights
+func weightedPair(a, weightA, b, weightB float32) float32 {
+	return (a*weightA + b*weightB) / (weightA + weightB)
+}
+
+// toMVLevel maps a lower-level power measurement to the MV level: losses
+// reduce the power towards the grid when discharging and increase it when
+// charging
+func toMVLevel(power, efficiency float32) float32 {
+	if power >= 0 {
+		return power * efficiency
+	}
+	return power / efficiency
+}
+
+// exceedsThreshold reports whether a residual exceeds the bad-data
+// threshold relative to the magnitude of the compared values
+func exceedsThreshold(residual, a, b float32) bool {
+	scale := abs(a)
+	if abs(b) > scale {
+		scale = abs(b)
+	}
+
+	threshold := float32(badDataMinKW)
+	if relative := scale * badDataFraction; relative > threshold {
+		threshold = relative
+	}
+
+	return abs(residual) > threshold
+}
+
+// medianOf3 returns the median of three values
+func medianOf3(a, b, c float32) float32 {
+	values := []float32{a, b, c}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values[1]
+}
+
+// abs returns the absolute value of a float32
+func abs(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
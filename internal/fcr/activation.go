@@ -0,0 +1,59 @@
+package fcr
+
+import (
+	"fmt"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// computeActivation maps a frequency measurement to activated power (kW)
+// using the product's activation parameters. Deviations within the deadband
+// yield zero, beyond it the activation grows linearly until the activation
+// band, saturating at the maximum power. Underfrequency activates positive
+// (discharge) power, overfrequency negative (charge) power.
+func computeActivation(frequency float32, params config.FCRProductConfig) float32 {
+	deviation := frequency - params.NominalFrequency
+
+	magnitude := deviation
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	if magnitude <= params.Deadband {
+		return 0
+	}
+
+	band := params.ActivationBand - params.Deadband
+	if band <= 0 {
+		return 0
+	}
+
+	fraction := (magnitude - params.Deadband) / band
+	if fraction > 1 {
+		fraction = 1
+	}
+
+	power := fraction * params.MaxPower
+	if deviation > 0 {
+		power = -power
+	}
+	return power
+}
+
+// validateProductParams checks activation parameters for consistency before
+// they are applied at runtime
+func validateProductParams(params config.FCRProductConfig) error {
+	if params.NominalFrequency <= 0 {
+		return fmt.Errorf("nominal frequency must be positive, got %.3f Hz", params.NominalFrequency)
+	}
+	if params.Deadband < 0 {
+		return fmt.Errorf("deadband must not be negative, got %.3f Hz", params.Deadband)
+	}
+	if params.ActivationBand <= params.Deadband {
+		return fmt.Errorf("activation band (%.3f Hz) must exceed the deadband (%.3f Hz)",
+			params.ActivationBand, params.Deadband)
+	}
+	if params.MaxPower < 0 {
+		return fmt.Errorf("maximum power must not be negative, got %.1f kW", params.MaxPower)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package fcr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/pkg/loopwatch"
+)
+
+// metricsReportInterval is the cadence of the slow path that exports loop
+// timing metrics and logs summaries, decoupled from the fast loop
+const metricsReportInterval = 10 * time.Second
+
+// Controller runs the sub-second FCR control loop. The fast path only
+// samples the frequency source and updates in-memory state; logging and
+// persistence happen on a separate slow path so FCR-D dynamic requirements
+// can be met.
+type Controller struct {
+	config   config.FCRConfig
+	selector *Selector
+	influxDB *database.InfluxDB
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	log      *zap.Logger
+
+	loopWatch *loopwatch.Loop
+
+	// shaper is owned by the fast loop and only advanced from tick
+	shaper *Shaper
+
+	mutex          sync.RWMutex
+	lastFrequency  FrequencyMeasurement
+	targetPower    float32
+	activatedPower float32
+	activeProduct  string
+	products       map[string]config.FCRProductConfig
+
+	// Capacity cap pushed by the plant condition tracker; negative means
+	// no cap has been set yet
+	availableCapacity float32
+
+	// Loop timing statistics, reset every report interval
+	statsMutex      sync.Mutex
+	ticks           uint64
+	missedDeadlines uint64
+	jitterSum       time.Duration
+	jitterMax       time.Duration
+}
+
+// NewController creates the FCR controller
+func NewController(cfg config.FCRConfig, selector *Selector, influxDB *database.InfluxDB, logger *zap.Logger) *Controller {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	products := make(map[string]config.FCRProductConfig, len(cfg.Products))
+	for name, params := range cfg.Products {
+		products[name] = params
+	}
+
+	return &Controller{
+		config:            cfg,
+		selector:          selector,
+		influxDB:          influxDB,
+		shaper:            NewShaper(cfg.Shaping),
+		activeProduct:     cfg.Product,
+		products:          products,
+		availableCapacity: -1,
+		loopWatch:         loopwatch.NewLoop("fcr_control", cfg.LoopInterval),
+		ctx:               ctx,
+		cancel:            cancel,
+		log: logger.With(
+			zap.String("service", "fcr"),
+		),
+	}
+}
+
+// Start starts the fast control loop and the metrics slow path
+func (c *Controller) Start() error {
+	c.wg.Go(c.controlLoop)
+	c.wg.Go(c.reportLoop)
+
+	c.log.Info("FCR control loop started",
+		zap.Duration("loop_interval", c.config.LoopInterval))
+
+	return nil
+}
+
+// Stop stops the control loop
+func (c *Controller) Stop() {
+	c.cancel()
+	c.wg.Wait()
+	c.log.Info("FCR control loop stopped")
+}
+
+// GetLastFrequency returns the frequency measurement from the last tick
+func (c *Controller) GetLastFrequency() FrequencyMeasurement {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.lastFrequency
+}
+
+// SetAvailableCapacity caps the activation at the capacity the plant can
+// currently maintain (kW); the cap applies symmetrically in both directions
+func (c *Controller) SetAvailableCapacity(capacity float32) {
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.availableCapacity = capacity
+}
+
+// ActivationState is a snapshot of the FCR activation, exposing the raw
+// target next to the shaped output together with the shaping parameters
+type ActivationState struct {
+	Product             string        `json:"product"`
+	TargetPowerKW       float32       `json:"target_power_kw"`
+	ActivatedPowerKW    float32       `json:"activated_power_kw"`
+	AvailableCapacityKW float32       `json:"available_capacity_kw"`
+	FilterTimeConstant  time.Duration `json:"filter_time_constant"`
+	MaxRampRateKWPerS   float32       `json:"max_ramp_rate_kw_per_s"`
+}
+
+// GetActivationState returns the current activation state
+func (c *Controller) GetActivationState() ActivationState {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	capacity := c.availableCapacity
+	if capacity < 0 {
+		capacity = c.products[c.activeProduct].MaxPower
+	}
+
+	return ActivationState{
+		Product:             c.activeProduct,
+		TargetPowerKW:       c.targetPower,
+		ActivatedPowerKW:    c.activatedPower,
+		AvailableCapacityKW: capacity,
+		FilterTimeConstant:  c.config.Shaping.FilterTimeConstant,
+		MaxRampRateKWPerS:   c.config.Shaping.MaxRampRate,
+	}
+}
+
+// GetProducts returns a copy of the per-product activation parameters
+func (c *Controller) GetProducts() map[string]config.FCRProductConfig {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	products := make(map[string]config.FCRProductConfig, len(c.products))
+	for name, params := range c.products {
+		products[name] = params
+	}
+	return products
+}
+
+// UpdateProduct replaces the activation parameters of a product at runtime
+// after validation, leaving an audit trail of who changed what
+func (c *Controller) UpdateProduct(name string, params config.FCRProductConfig, operator string) error {
+	if err := validateProductParams(params); err != nil {
+		return fmt.Errorf("invalid parameters for FCR product %s: %w", name, err)
+	}
+
+	c.mutex.Lock()
+	previous, exists := c.products[name]
+	c.products[name] = params
+	c.mutex.Unlock()
+
+	fields := []zap.Field{
+		zap.String("product", name),
+		zap.String("operator", operator),
+		zap.Float32("nominal_frequency", params.NominalFrequency),
+		zap.Float32("deadband", params.Deadband),
+		zap.Float32("activation_band", params.ActivationBand),
+		zap.Float32("max_power", params.MaxPower),
+	}
+	if exists {
+		fields = append(fields,
+			zap.Float32("previous_deadband", previous.Deadband),
+			zap.Float32("previous_activation_band", previous.ActivationBand),
+			zap.Float32("previous_max_power", previous.MaxPower))
+	}
+	c.log.Info("FCR product parameters updated", fields...)
+
+	return nil
+}
+
+// controlLoop is the fast path: it must not log or touch the database
+func (c *Controller) controlLoop() {
+	ticker := time.NewTicker(c.config.LoopInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case now := <-ticker.C:
+			c.recordTiming(now.Sub(last))
+			last = now
+
+			finish := c.loopWatch.Begin()
+			c.tick()
+			finish()
+		}
+	}
+}
+
+// tick executes one fast-path iteration: it maps the frequency to the raw
+// activation of the active product and ramps the shaped output towards it.
+// Without a valid frequency the target is zero so the output ramps down.
+func (c *Controller) tick() {
+	measurement := c.selector.GetFrequency()
+
+	c.mutex.RLock()
+	params, haveParams := c.products[c.activeProduct]
+	capacity := c.availableCapacity
+	c.mutex.RUnlock()
+
+	var target float32
+	if measurement.Valid && haveParams {
+		target = computeActivation(measurement.Frequency, params)
+	}
+
+	// Cap at the capacity the plant can currently maintain
+	if capacity >= 0 {
+		if target > capacity {
+			target = capacity
+		} else if target < -capacity {
+			target = -capacity
+		}
+	}
+
+	activated := c.shaper.Apply(target, time.Now())
+
+	c.mutex.Lock()
+	c.lastFrequency = measurement
+	c.targetPower = target
+	c.activatedPower = activated
+	c.mutex.Unlock()
+}
+
+// recordTiming accumulates loop jitter and missed-deadline statistics. A
+// tick is a missed deadline when it arrives more than half an interval late.
+func (c *Controller) recordTiming(delta time.Duration) {
+	jitter := delta - c.config.LoopInterval
+	if jitter < 0 {
+		jitter = -jitter
+	}
+
+	c.statsMutex.Lock()
+	defer c.statsMutex.Unlock()
+
+	c.ticks++
+	c.jitterSum += jitter
+	if jitter > c.jitterMax {
+		c.jitterMax = jitter
+	}
+	if jitter > c.config.LoopInterval/2 {
+		c.missedDeadlines++
+	}
+}
+
+// reportLoop is the slow path: it exports loop metrics and logs summaries
+func (c *Controller) reportLoop() {
+	ticker := time.NewTicker(metricsReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.report()
+		}
+	}
+}
+
+// report snapshots and resets the timing counters, then persists them
+func (c *Controller) report() {
+	c.statsMutex.Lock()
+	ticks := c.ticks
+	missedDeadlines := c.missedDeadlines
+	jitterSum := c.jitterSum
+	jitterMax := c.jitterMax
+	c.ticks = 0
+	c.missedDeadlines = 0
+	c.jitterSum = 0
+	c.jitterMax = 0
+	c.statsMutex.Unlock()
+
+	if ticks == 0 {
+		return
+	}
+
+	metrics := database.FCRLoopMetrics{
+		Timestamp:       time.Now(),
+		Ticks:           ticks,
+		MissedDeadlines: missedDeadlines,
+		JitterAvgMs:     float64(jitterSum.Microseconds()) / 1000 / float64(ticks),
+		JitterMaxMs:     float64(jitterMax.Microseconds()) / 1000,
+	}
+
+	if missedDeadlines > 0 {
+		c.log.Warn("FCR loop missed deadlines",
+			zap.Uint64("missed_deadlines", missedDeadlines),
+			zap.Uint64("ticks", ticks),
+			zap.Float64("jitter_max_ms", metrics.JitterMaxMs))
+	}
+
+	if c.influxDB != nil {
+		if err := c.influxDB.WriteFCRLoopMetrics(metrics); err != nil {
+			c.log.Error("Failed to save FCR loop metrics to InfluxDB", zap.Error(err))
+		}
+	}
+}
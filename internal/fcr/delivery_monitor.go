@@ -0,0 +1,217 @@
+package fcr
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pcs"
+)
+
+// NonDeliveryAlarmCode identifies the FCR non-delivery alarm
+const NonDeliveryAlarmCode = 9003
+
+// DeliveryMonitor self-monitors the FCR provision: every check interval it
+// compares the expected activation against the power the PCS units actually
+// delivered. A deviation beyond the tolerance sustained for the grace period
+// raises a non-delivery alarm and marks the interval in the settlement data;
+// with capacity derating enabled the reported maintained capacity shrinks
+// with the delivery shortfall while the deviation persists.
+type DeliveryMonitor struct {
+	config       config.FCRDeliveryConfig
+	controller   *Controller
+	pcsManager   *pcs.Manager
+	alarmManager *alarm.Manager
+	influxDB     *database.InfluxDB
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	log          *zap.Logger
+
+	mutex          sync.RWMutex
+	state          database.FCRDeliveryStatus
+	deviationSince time.Time
+	alarmActive    bool
+}
+
+// NewDeliveryMonitor creates the FCR delivery monitor
+func NewDeliveryMonitor(cfg config.FCRDeliveryConfig, controller *Controller, pcsManager *pcs.Manager, alarmManager *alarm.Manager, influxDB *database.InfluxDB, logger *zap.Logger) *DeliveryMonitor {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &DeliveryMonitor{
+		config:       cfg,
+		controller:   controller,
+		pcsManager:   pcsManager,
+		alarmManager: alarmManager,
+		influxDB:     influxDB,
+		ctx:          ctx,
+		cancel:       cancel,
+		log: logger.With(
+			zap.String("service", "fcr_delivery"),
+		),
+	}
+}
+
+// Start starts the delivery check loop
+func (m *DeliveryMonitor) Start() error {
+	m.wg.Go(m.checkLoop)
+
+	m.log.Info("FCR delivery monitor started",
+		zap.Duration("check_interval", m.config.CheckInterval),
+		zap.Float32("tolerance_kw", m.config.ToleranceKW),
+		zap.Duration("grace_period", m.config.GracePeriod),
+		zap.Bool("capacity_derating", m.config.CapacityDerating))
+
+	return nil
+}
+
+// Stop stops the delivery check loop
+func (m *DeliveryMonitor) Stop() {
+	m.cancel()
+	m.wg.Wait()
+	m.log.Info("FCR delivery monitor stopped")
+}
+
+// GetState returns the result of the last delivery check
+func (m *DeliveryMonitor) GetState() database.FCRDeliveryStatus {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.state
+}
+
+// checkLoop runs the delivery check every check interval
+func (m *DeliveryMonitor) checkLoop() {
+	ticker := time.NewTicker(m.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check executes one delivery comparison
+func (m *DeliveryMonitor) check() {
+	now := time.Now()
+	activation := m.controller.GetActivationState()
+	expected := activation.ActivatedPowerKW
+	delivered := m.deliveredPower()
+
+	deviation := delivered - expected
+	if deviation < 0 {
+		deviation = -deviation
+	}
+
+	m.mutex.Lock()
+	if deviation > m.config.ToleranceKW {
+		if m.deviationSince.IsZero() {
+			m.deviationSince = now
+		}
+	} else {
+		m.deviationSince = time.Time{}
+	}
+
+	nonDelivery := !m.deviationSince.IsZero() && now.Sub(m.deviationSince) >= m.config.GracePeriod
+
+	capacity := m.maintainedCapacity(activation, expected, delivered, nonDelivery)
+
+	m.state = database.FCRDeliveryStatus{
+		Timestamp:            now,
+		ExpectedPowerKW:      expected,
+		DeliveredPowerKW:     delivered,
+		DeviationKW:          deviation,
+		NonDelivery:          nonDelivery,
+		MaintainedCapacityKW: capacity,
+	}
+	state := m.state
+
+	raise := nonDelivery && !m.alarmActive
+	clear := !nonDelivery && m.alarmActive
+	m.alarmActive = nonDelivery
+	m.mutex.Unlock()
+
+	if raise {
+		m.log.Error("FCR non-delivery detected",
+			zap.Float32("expected_kw", expected),
+			zap.Float32("delivered_kw", delivered),
+			zap.Float32("deviation_kw", deviation),
+			zap.Duration("grace_period", m.config.GracePeriod))
+		m.submitNonDeliveryAlarm(true)
+	}
+	if clear {
+		m.log.Info("FCR delivery within tolerance again",
+			zap.Float32("deviation_kw", deviation))
+		m.submitNonDeliveryAlarm(false)
+	}
+
+	if m.influxDB != nil {
+		if err := m.influxDB.WriteFCRDeliveryStatus(state); err != nil {
+			m.log.Error("Failed to save FCR delivery status to InfluxDB", zap.Error(err))
+		}
+	}
+}
+
+// deliveredPower sums the measured MV-side active power of the connected
+// PCS units in kW
+func (m *DeliveryMonitor) deliveredPower() float32 {
+	var total float32
+	for _, service := range m.pcsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		total += float32(service.GetLatestPCSGridData().MVGridActivePower)
+	}
+	return total
+}
+
+// maintainedCapacity derives the reported maintained capacity from the
+// active product's maximum power, scaled down by the delivery shortfall
+// while a non-delivery persists and capacity derating is enabled
+func (m *DeliveryMonitor) maintainedCapacity(activation ActivationState, expected, delivered float32, nonDelivery bool) float32 {
+	capacity := float32(0)
+	if params, ok := m.controller.GetProducts()[activation.Product]; ok {
+		capacity = params.MaxPower
+	}
+
+	if !m.config.CapacityDerating || !nonDelivery || capacity == 0 {
+		return capacity
+	}
+
+	magnitude := expected
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	if magnitude == 0 {
+		return capacity
+	}
+
+	ratio := delivered / expected
+	if ratio < 0 {
+		ratio = 0
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	return capacity * ratio
+}
+
+// submitNonDeliveryAlarm raises or clears the non-delivery alarm
+func (m *DeliveryMonitor) submitNonDeliveryAlarm(active bool) {
+	m.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: "FCR",
+		AlarmCode: NonDeliveryAlarmCode,
+		Message:   "FCR activation not delivered within tolerance",
+		Severity:  "HIGH",
+		Active:    active,
+	})
+}
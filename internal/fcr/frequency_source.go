@@ -0,0 +1,27 @@
+// Package fcr implements frequency containment reserve support. It provides
+// a pluggable frequency source abstraction: a dedicated high-accuracy
+// frequency transducer when one is configured, with automatic failover to
+// the PCS grid frequency, whose accuracy is marginal for FCR.
+package fcr
+
+import (
+	"time"
+)
+
+// FrequencyMeasurement is one grid frequency sample
+type FrequencyMeasurement struct {
+	Frequency float32   `json:"frequency"`
+	Timestamp time.Time `json:"timestamp"`
+	Valid     bool      `json:"valid"`
+	Source    string    `json:"source"`
+}
+
+// FrequencySource delivers grid frequency samples
+type FrequencySource interface {
+	// Name identifies the source in logs and telemetry
+	Name() string
+
+	// GetFrequency returns the latest sample; Valid is false when the
+	// source has no fresh measurement
+	GetFrequency() FrequencyMeasurement
+}
@@ -0,0 +1,158 @@
+package fcr
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/pkg/modbus"
+	"powerkonnekt/ems/pkg/utils"
+)
+
+// MeterSource samples a dedicated frequency transducer over Modbus TCP at
+// sub-100 ms intervals
+type MeterSource struct {
+	config config.FrequencyMeterConfig
+	client *modbus.Client
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	log    *zap.Logger
+
+	mutex         sync.RWMutex
+	lastFrequency float32
+	lastSample    time.Time
+	valid         bool
+}
+
+// NewMeterSource creates a frequency meter source
+func NewMeterSource(cfg config.FrequencyMeterConfig, logger *zap.Logger) *MeterSource {
+	client := modbus.NewSecureClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout, cfg.BindAddress, modbus.TLSSettings{
+		Enabled:  cfg.TLS.Enabled,
+		CertFile: cfg.TLS.CertFile,
+		KeyFile:  cfg.TLS.KeyFile,
+		CAFile:   cfg.TLS.CAFile,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &MeterSource{
+		config: cfg,
+		client: client,
+		ctx:    ctx,
+		cancel: cancel,
+		log: logger.With(
+			zap.String("service", "frequency_meter"),
+			zap.String("host", cfg.Host),
+			zap.Int("port", cfg.Port),
+		),
+	}
+}
+
+// Start starts the sampling loop
+func (s *MeterSource) Start() error {
+	s.wg.Go(s.sampleLoop)
+	s.log.Info("Frequency meter source started",
+		zap.Duration("sample_interval", s.config.SampleInterval))
+	return nil
+}
+
+// Stop stops the sampling loop
+func (s *MeterSource) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	s.client.Disconnect()
+	s.log.Info("Frequency meter source stopped")
+}
+
+// Name identifies the source in logs and telemetry
+func (s *MeterSource) Name() string {
+	return "frequency_meter"
+}
+
+// GetFrequency returns the latest sample; it is invalid once older than the
+// configured staleness threshold
+func (s *MeterSource) GetFrequency() FrequencyMeasurement {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	valid := s.valid && time.Since(s.lastSample) <= s.config.StaleAfter
+
+	return FrequencyMeasurement{
+		Frequency: s.lastFrequency,
+		Timestamp: s.lastSample,
+		Valid:     valid,
+		Source:    s.Name(),
+	}
+}
+
+// sampleLoop reads the frequency register at the configured interval
+func (s *MeterSource) sampleLoop() {
+	if err := s.client.Connect(s.ctx); err != nil {
+		s.log.Warn("Initial frequency meter connection failed", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(s.config.SampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.sample(); err != nil {
+				s.markInvalid()
+				s.log.Debug("Frequency meter sample failed", zap.Error(err))
+				s.reconnect()
+			}
+		}
+	}
+}
+
+// sample reads one frequency value from the transducer
+func (s *MeterSource) sample() error {
+	data, err := s.client.ReadHoldingRegisters(s.ctx, s.config.Register, 1)
+	if err != nil {
+		return err
+	}
+	if len(data) < 2 {
+		return errShortFrequencyFrame
+	}
+
+	frequency := utils.Scale(utils.FromBytes[uint16](data[0:2]), s.config.Scale) + s.config.Offset
+
+	s.mutex.Lock()
+	s.lastFrequency = frequency
+	s.lastSample = time.Now()
+	s.valid = true
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// markInvalid invalidates the last sample after a failed read
+func (s *MeterSource) markInvalid() {
+	s.mutex.Lock()
+	s.valid = false
+	s.mutex.Unlock()
+}
+
+// reconnect re-establishes the connection after a lost one, rate-limited by
+// the configured reconnect delay
+func (s *MeterSource) reconnect() {
+	if s.client.IsConnected() {
+		return
+	}
+
+	select {
+	case <-s.ctx.Done():
+		return
+	case <-time.After(s.config.ReconnectDelay):
+	}
+
+	if err := s.client.Connect(s.ctx); err != nil {
+		s.log.Debug("Frequency meter reconnect failed", zap.Error(err))
+	}
+}
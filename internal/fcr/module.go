@@ -0,0 +1,121 @@
+package fcr
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/season"
+)
+
+// Module provides FCR support to the Fx application
+var Module = fx.Module("fcr",
+	fx.Provide(
+		ProvideSelector,
+		ProvideController,
+		ProvideDeliveryMonitor,
+		ProvidePlantConditionTracker,
+		ProvidePortfolio,
+		ProvideRecoveryPlanner,
+	),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideController creates the FCR controller
+func ProvideController(cfg *config.Config, selector *Selector, influxDB *database.InfluxDB, logger *zap.Logger) *Controller {
+	return NewController(cfg.FCR, selector, influxDB, logger)
+}
+
+// ProvideDeliveryMonitor creates the FCR delivery monitor
+func ProvideDeliveryMonitor(cfg *config.Config, controller *Controller, pcsManager *pcs.Manager, alarmManager *alarm.Manager, influxDB *database.InfluxDB, logger *zap.Logger) *DeliveryMonitor {
+	return NewDeliveryMonitor(cfg.FCR.Delivery, controller, pcsManager, alarmManager, influxDB, logger)
+}
+
+// ProvidePlantConditionTracker creates the plant condition tracker
+func ProvidePlantConditionTracker(controller *Controller, pcsManager *pcs.Manager, bmsManager *bms.Manager, controlLogic *control.Logic, seasonManager *season.Manager, logger *zap.Logger) *PlantConditionTracker {
+	return NewPlantConditionTracker(controller, pcsManager, bmsManager, controlLogic, seasonManager, logger)
+}
+
+// ProvidePortfolio creates the FCR portfolio from the configured groups
+func ProvidePortfolio(cfg *config.Config, pcsManager *pcs.Manager, bmsManager *bms.Manager, logger *zap.Logger) (*Portfolio, error) {
+	return NewPortfolio(cfg.FCR.Groups, pcsManager, bmsManager, logger)
+}
+
+// ProvideRecoveryPlanner creates the endurance recovery planner
+func ProvideRecoveryPlanner(cfg *config.Config, controller *Controller, portfolio *Portfolio, pcsManager *pcs.Manager, bmsManager *bms.Manager, controlLogic *control.Logic, logger *zap.Logger) *RecoveryPlanner {
+	bmsLimits := make(map[int]config.BMSLimits, len(cfg.BMS))
+	for _, bmsCfg := range cfg.BMS {
+		bmsLimits[bmsCfg.ID] = cfg.LimitsForBMS(bmsCfg.ID)
+	}
+
+	return NewRecoveryPlanner(cfg.FCR.Recovery, cfg.FCR.SOCWindow, bmsLimits, controller, portfolio, pcsManager, bmsManager, controlLogic, logger)
+}
+
+// ProvideSelector creates the frequency source selector, with the dedicated
+// meter source when one is configured
+func ProvideSelector(cfg *config.Config, pcsManager *pcs.Manager, logger *zap.Logger) *Selector {
+	var meter *MeterSource
+	if cfg.FCR.FrequencyMeter.Enabled {
+		meter = NewMeterSource(cfg.FCR.FrequencyMeter, logger)
+	}
+
+	return NewSelector(meter, NewPCSSource(pcsManager), logger)
+}
+
+// RegisterLifecycle starts and stops the meter sampling loop and, when FCR
+// is enabled, the control loop, the delivery monitor, the plant condition
+// tracker and the recovery planner
+func RegisterLifecycle(lc fx.Lifecycle, cfg *config.Config, selector *Selector, controller *Controller, deliveryMonitor *DeliveryMonitor, plantCondition *PlantConditionTracker, recoveryPlanner *RecoveryPlanner) {
+	monitorEnabled := cfg.FCR.Enabled && cfg.FCR.Delivery.Enabled
+	recoveryEnabled := cfg.FCR.Enabled && cfg.FCR.Recovery.Enabled
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if selector.meter != nil {
+				if err := selector.meter.Start(); err != nil {
+					return err
+				}
+			}
+			if cfg.FCR.Enabled {
+				if err := controller.Start(); err != nil {
+					return err
+				}
+				if err := plantCondition.Start(); err != nil {
+					return err
+				}
+			}
+			if monitorEnabled {
+				if err := deliveryMonitor.Start(); err != nil {
+					return err
+				}
+			}
+			if recoveryEnabled {
+				return recoveryPlanner.Start()
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if recoveryEnabled {
+				recoveryPlanner.Stop()
+			}
+			if monitorEnabled {
+				deliveryMonitor.Stop()
+			}
+			if cfg.FCR.Enabled {
+				plantCondition.Stop()
+				controller.Stop()
+			}
+			if selector.meter != nil {
+				selector.meter.Stop()
+			}
+			return nil
+		},
+	})
+}
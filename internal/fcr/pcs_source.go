@@ -0,0 +1,65 @@
+package fcr
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"powerkonnekt/ems/internal/pcs"
+)
+
+// errShortFrequencyFrame reports a truncated frequency register response
+var errShortFrequencyFrame = errors.New("frequency register response too short")
+
+// pcsFrequencyStaleAfter invalidates PCS grid frequency once the underlying
+// poll data is older than this
+const pcsFrequencyStaleAfter = 5 * time.Second
+
+// PCSSource derives grid frequency from the PCS grid data of the first
+// connected PCS. It is the fallback when no dedicated meter is available.
+type PCSSource struct {
+	pcsManager *pcs.Manager
+}
+
+// NewPCSSource creates a PCS grid frequency source
+func NewPCSSource(pcsManager *pcs.Manager) *PCSSource {
+	return &PCSSource{pcsManager: pcsManager}
+}
+
+// Name identifies the source in logs and telemetry
+func (s *PCSSource) Name() string {
+	return "pcs_grid"
+}
+
+// GetFrequency returns the grid frequency from the lowest-numbered connected
+// PCS with a plausible measurement
+func (s *PCSSource) GetFrequency() FrequencyMeasurement {
+	services := s.pcsManager.GetAllServices()
+
+	ids := make([]int, 0, len(services))
+	for id := range services {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		service := services[id]
+		if !service.IsConnected() {
+			continue
+		}
+
+		gridData := service.GetLatestPCSGridData()
+		if gridData.GridFrequency <= 0 || time.Since(gridData.Timestamp) > pcsFrequencyStaleAfter {
+			continue
+		}
+
+		return FrequencyMeasurement{
+			Frequency: gridData.GridFrequency,
+			Timestamp: gridData.Timestamp,
+			Valid:     true,
+			Source:    s.Name(),
+		}
+	}
+
+	return FrequencyMeasurement{Source: s.Name()}
+}
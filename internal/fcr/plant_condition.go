@@ -0,0 +1,211 @@
+package fcr
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/season"
+)
+
+// plantConditionInterval is the cadence at which device availability is
+// re-evaluated; capacity reductions take effect within one interval
+const plantConditionInterval = 5 * time.Second
+
+// reserveRampSOC is the SOC band above the islanding reserve over which the
+// FCR capacity ramps down to zero
+const reserveRampSOC = 5.0
+
+// PlantCondition is a snapshot of the device availability backing the FCR
+// provision and the capacity it supports
+type PlantCondition struct {
+	Timestamp           time.Time `json:"timestamp"`
+	PCSConnected        int       `json:"pcs_connected"`
+	PCSTotal            int       `json:"pcs_total"`
+	RacksConnected      int       `json:"racks_connected"`
+	RacksTotal          int       `json:"racks_total"`
+	AvailabilityRatio   float32   `json:"availability_ratio"`
+	MinSOC              float32   `json:"min_soc"`
+	ReserveSOC          float32   `json:"reserve_soc"`
+	GridState           string    `json:"grid_state"`
+	AvailableCapacityKW float32   `json:"available_capacity_kw"`
+}
+
+// PlantConditionTracker recomputes the available FCR capacity from live
+// device availability: a tripped PCS or a disconnected BMS rack shrinks the
+// maintained capacity automatically. Updates are pushed to the controller,
+// which caps its activation accordingly, and surface in the TSO telemetry.
+type PlantConditionTracker struct {
+	controller   *Controller
+	pcsManager   *pcs.Manager
+	bmsManager   *bms.Manager
+	controlLogic *control.Logic
+	season       *season.Manager
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	log          *zap.Logger
+
+	mutex sync.RWMutex
+	state PlantCondition
+}
+
+// NewPlantConditionTracker creates the plant condition tracker
+func NewPlantConditionTracker(controller *Controller, pcsManager *pcs.Manager, bmsManager *bms.Manager, controlLogic *control.Logic, seasonManager *season.Manager, logger *zap.Logger) *PlantConditionTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &PlantConditionTracker{
+		controller:   controller,
+		pcsManager:   pcsManager,
+		bmsManager:   bmsManager,
+		controlLogic: controlLogic,
+		season:       seasonManager,
+		ctx:          ctx,
+		cancel:       cancel,
+		log: logger.With(
+			zap.String("service", "fcr_plant_condition"),
+		),
+	}
+}
+
+// Start starts the availability evaluation loop
+func (t *PlantConditionTracker) Start() error {
+	t.wg.Go(t.trackLoop)
+	t.log.Info("FCR plant condition tracker started",
+		zap.Duration("interval", plantConditionInterval))
+	return nil
+}
+
+// Stop stops the availability evaluation loop
+func (t *PlantConditionTracker) Stop() {
+	t.cancel()
+	t.wg.Wait()
+	t.log.Info("FCR plant condition tracker stopped")
+}
+
+// GetState returns the plant condition from the last evaluation
+func (t *PlantConditionTracker) GetState() PlantCondition {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.state
+}
+
+// trackLoop re-evaluates the plant condition every interval
+func (t *PlantConditionTracker) trackLoop() {
+	ticker := time.NewTicker(plantConditionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+			t.evaluate()
+		}
+	}
+}
+
+// evaluate recomputes the available capacity and pushes it to the controller
+// when it changed
+func (t *PlantConditionTracker) evaluate() {
+	condition := PlantCondition{Timestamp: time.Now()}
+
+	for _, service := range t.pcsManager.GetAllServices() {
+		condition.PCSTotal++
+		if service.IsConnected() {
+			condition.PCSConnected++
+		}
+	}
+
+	condition.MinSOC = 100
+	for _, service := range t.bmsManager.GetAllServices() {
+		status := service.GetLatestBMSStatusData()
+		condition.RacksTotal += int(status.TotalRacks)
+		if service.IsConnected() {
+			condition.RacksConnected += int(status.ConnectedRacks)
+			if soc := float32(service.GetLatestBMSData().SOC); soc < condition.MinSOC {
+				condition.MinSOC = soc
+			}
+		}
+	}
+
+	condition.AvailabilityRatio = availabilityRatio(condition.PCSConnected, condition.PCSTotal) *
+		availabilityRatio(condition.RacksConnected, condition.RacksTotal)
+
+	base := float32(0)
+	activation := t.controller.GetActivationState()
+	if params, ok := t.controller.GetProducts()[activation.Product]; ok {
+		base = params.MaxPower
+	}
+	condition.AvailableCapacityKW = base * condition.AvailabilityRatio
+
+	// The protected islanding reserve shrinks the maintained capacity as
+	// the worst string approaches the reserve level
+	condition.ReserveSOC = t.controlLogic.GetReserveSOC()
+	condition.AvailableCapacityKW *= reserveCapacityFactor(condition.MinSOC, condition.ReserveSOC)
+
+	// An islanded site has no grid frequency to serve; frequency reserves
+	// are withdrawn entirely until the connection is restored
+	condition.GridState = t.controlLogic.GetGridState()
+	if condition.GridState == control.GridStateIslanded {
+		condition.AvailableCapacityKW = 0
+	}
+
+	// A seasonal profile may cap the marketed capacity below what the
+	// plant could physically deliver
+	if cap := t.season.FCRCapacityCapKW(); cap > 0 && condition.AvailableCapacityKW > cap {
+		condition.AvailableCapacityKW = cap
+	}
+
+	t.mutex.Lock()
+	previous := t.state
+	t.state = condition
+	t.mutex.Unlock()
+
+	t.controller.SetAvailableCapacity(condition.AvailableCapacityKW)
+
+	if condition.AvailableCapacityKW < previous.AvailableCapacityKW {
+		t.log.Warn("FCR capacity reduced on degraded plant condition",
+			zap.Float32("available_capacity_kw", condition.AvailableCapacityKW),
+			zap.Float32("previous_capacity_kw", previous.AvailableCapacityKW),
+			zap.Int("pcs_connected", condition.PCSConnected),
+			zap.Int("pcs_total", condition.PCSTotal),
+			zap.Int("racks_connected", condition.RacksConnected),
+			zap.Int("racks_total", condition.RacksTotal))
+	} else if condition.AvailableCapacityKW > previous.AvailableCapacityKW {
+		t.log.Info("FCR capacity restored",
+			zap.Float32("available_capacity_kw", condition.AvailableCapacityKW),
+			zap.Float32("previous_capacity_kw", previous.AvailableCapacityKW))
+	}
+}
+
+// reserveCapacityFactor ramps the capacity from full at reserveRampSOC above
+// the reserve down to zero at the reserve level
+func reserveCapacityFactor(minSOC, reserve float32) float32 {
+	if reserve <= 0 {
+		return 1
+	}
+
+	margin := minSOC - reserve
+	if margin <= 0 {
+		return 0
+	}
+	if margin >= reserveRampSOC {
+		return 1
+	}
+	return margin / reserveRampSOC
+}
+
+// availabilityRatio returns connected/total, treating an empty device set
+// as fully available
+func availabilityRatio(connected, total int) float32 {
+	if total == 0 {
+		return 1
+	}
+	return float32(connected) / float32(total)
+}
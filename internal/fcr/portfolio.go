@@ -0,0 +1,191 @@
+package fcr
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/pcs"
+)
+
+// GroupState is the aggregated state of one portfolio group
+type GroupState struct {
+	Name             string  `json:"name"`
+	Share            float32 `json:"share"`
+	AllocationKW     float32 `json:"allocation_kw"`
+	PCSConnected     int     `json:"pcs_connected"`
+	PCSTotal         int     `json:"pcs_total"`
+	RacksConnected   int     `json:"racks_connected"`
+	RacksTotal       int     `json:"racks_total"`
+	RecoveryProvider bool    `json:"recovery_provider"`
+}
+
+// PortfolioState is the aggregated state of the FCR portfolio
+type PortfolioState struct {
+	Timestamp     time.Time    `json:"timestamp"`
+	Groups        []GroupState `json:"groups"`
+	RecoveryGroup string       `json:"recovery_group"`
+}
+
+// Portfolio coordinates FCR provision across independent PCS/BMS groups:
+// the activation is split among the groups by their configured capacity
+// shares, and the group providing recovery energy rotates so the burden
+// spreads over the portfolio. Without configured groups the whole plant
+// acts as a single group.
+type Portfolio struct {
+	groups     []config.FCRGroupConfig
+	pcsManager *pcs.Manager
+	bmsManager *bms.Manager
+	log        *zap.Logger
+
+	mutex       sync.RWMutex
+	recoveryIdx int
+}
+
+// NewPortfolio creates the FCR portfolio from the configured groups. An
+// empty group list yields a single implicit group spanning every device.
+func NewPortfolio(groups []config.FCRGroupConfig, pcsManager *pcs.Manager, bmsManager *bms.Manager, logger *zap.Logger) (*Portfolio, error) {
+	if err := validateGroups(groups); err != nil {
+		return nil, err
+	}
+
+	if len(groups) == 0 {
+		implicit := config.FCRGroupConfig{Name: "plant", Share: 1}
+		for id := range pcsManager.GetAllServices() {
+			implicit.PCSIDs = append(implicit.PCSIDs, id)
+		}
+		for id := range bmsManager.GetAllServices() {
+			implicit.BMSIDs = append(implicit.BMSIDs, id)
+		}
+		groups = []config.FCRGroupConfig{implicit}
+	}
+
+	return &Portfolio{
+		groups:     groups,
+		pcsManager: pcsManager,
+		bmsManager: bmsManager,
+		log: logger.With(
+			zap.String("service", "fcr_portfolio"),
+		),
+	}, nil
+}
+
+// validateGroups checks that the group shares cover the full provision and
+// that no device belongs to two groups
+func validateGroups(groups []config.FCRGroupConfig) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var shareSum float32
+	seenPCS := make(map[int]string)
+	seenBMS := make(map[int]string)
+
+	for _, group := range groups {
+		shareSum += group.Share
+
+		for _, id := range group.PCSIDs {
+			if other, dup := seenPCS[id]; dup {
+				return fmt.Errorf("PCS %d assigned to both group %s and %s", id, other, group.Name)
+			}
+			seenPCS[id] = group.Name
+		}
+		for _, id := range group.BMSIDs {
+			if other, dup := seenBMS[id]; dup {
+				return fmt.Errorf("BMS %d assigned to both group %s and %s", id, other, group.Name)
+			}
+			seenBMS[id] = group.Name
+		}
+	}
+
+	if shareSum < 0.999 || shareSum > 1.001 {
+		return fmt.Errorf("FCR group shares must sum to 1, got %.3f", shareSum)
+	}
+	return nil
+}
+
+// Allocations splits the given activation power among the groups by their
+// capacity shares
+func (p *Portfolio) Allocations(total float32) map[string]float32 {
+	allocations := make(map[string]float32, len(p.groups))
+	for _, group := range p.groups {
+		allocations[group.Name] = total * group.Share
+	}
+	return allocations
+}
+
+// RecoveryGroup returns the group currently designated to provide recovery
+// energy
+func (p *Portfolio) RecoveryGroup() config.FCRGroupConfig {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.groups[p.recoveryIdx]
+}
+
+// RotateRecovery advances the recovery designation to the next group so
+// consecutive recoveries spread over the portfolio
+func (p *Portfolio) RotateRecovery() {
+	p.mutex.Lock()
+	p.recoveryIdx = (p.recoveryIdx + 1) % len(p.groups)
+	next := p.groups[p.recoveryIdx].Name
+	p.mutex.Unlock()
+
+	if len(p.groups) > 1 {
+		p.log.Info("FCR recovery provider rotated",
+			zap.String("group", next))
+	}
+}
+
+// GetState aggregates the per-group availability and the split of the
+// given activation power
+func (p *Portfolio) GetState(activatedPower float32) PortfolioState {
+	p.mutex.RLock()
+	recoveryIdx := p.recoveryIdx
+	p.mutex.RUnlock()
+
+	state := PortfolioState{
+		Timestamp:     time.Now(),
+		Groups:        make([]GroupState, 0, len(p.groups)),
+		RecoveryGroup: p.groups[recoveryIdx].Name,
+	}
+
+	for i, group := range p.groups {
+		groupState := GroupState{
+			Name:             group.Name,
+			Share:            group.Share,
+			AllocationKW:     activatedPower * group.Share,
+			RecoveryProvider: i == recoveryIdx,
+		}
+
+		for _, id := range group.PCSIDs {
+			service, err := p.pcsManager.GetService(id)
+			if err != nil {
+				continue
+			}
+			groupState.PCSTotal++
+			if service.IsConnected() {
+				groupState.PCSConnected++
+			}
+		}
+
+		for _, id := range group.BMSIDs {
+			service, err := p.bmsManager.GetService(id)
+			if err != nil {
+				continue
+			}
+			status := service.GetLatestBMSStatusData()
+			groupState.RacksTotal += int(status.TotalRacks)
+			if service.IsConnected() {
+				groupState.RacksConnected += int(status.ConnectedRacks)
+			}
+		}
+
+		state.Groups = append(state.Groups, groupState)
+	}
+
+	return state
+}
@@ -0,0 +1,354 @@
+package fcr
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/pcs"
+)
+
+// RecoveryState is a snapshot of the endurance recovery planning
+type RecoveryState struct {
+	Timestamp          time.Time `json:"timestamp"`
+	Endurance          float64   `json:"endurance_hours"`
+	SOC                float32   `json:"soc"`
+	ChargeEnergyKWh    float32   `json:"charge_energy_kwh"`
+	DischargeEnergyKWh float32   `json:"discharge_energy_kwh"`
+	Active             bool      `json:"active"`
+	RecoveryGroup      string    `json:"recovery_group"`
+	BaselinePowerKW    float32   `json:"baseline_power_kw"`
+	StartedAt          time.Time `json:"started_at,omitempty"`
+}
+
+// RecoveryPlanner proactively manages the endurance of a limited energy
+// reservoir (LER) providing FCR. It estimates how long the reservoir can
+// sustain full activation in the constraining direction; when that
+// endurance drops toward the configured LER limit, recovery energy is
+// scheduled as a baseline through the scheduler setpoint source, so normal
+// state energy management is not the only way back. The baseline is held
+// until the endurance reaches the target again and yields to any
+// higher-priority setpoint source via the arbitration.
+type RecoveryPlanner struct {
+	config       config.FCRRecoveryConfig
+	socWindow    config.FCRSOCWindowConfig
+	bmsLimits    map[int]config.BMSLimits // Resolved per-string limits
+	controller   *Controller
+	portfolio    *Portfolio
+	pcsManager   *pcs.Manager
+	bmsManager   *bms.Manager
+	controlLogic *control.Logic
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	log          *zap.Logger
+
+	mutex sync.RWMutex
+	state RecoveryState
+}
+
+// NewRecoveryPlanner creates the endurance recovery planner
+func NewRecoveryPlanner(cfg config.FCRRecoveryConfig, socWindow config.FCRSOCWindowConfig, bmsLimits map[int]config.BMSLimits, controller *Controller, portfolio *Portfolio, pcsManager *pcs.Manager, bmsManager *bms.Manager, controlLogic *control.Logic, logger *zap.Logger) *RecoveryPlanner {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &RecoveryPlanner{
+		config:       cfg,
+		socWindow:    socWindow,
+		bmsLimits:    bmsLimits,
+		controller:   controller,
+		portfolio:    portfolio,
+		pcsManager:   pcsManager,
+		bmsManager:   bmsManager,
+		controlLogic: controlLogic,
+		ctx:          ctx,
+		cancel:       cancel,
+		log: logger.With(
+			zap.String("service", "fcr_recovery"),
+		),
+	}
+}
+
+// Start starts the recovery planning loop
+func (p *RecoveryPlanner) Start() error {
+	p.wg.Go(p.planLoop)
+
+	p.log.Info("FCR recovery planner started",
+		zap.Duration("endurance_limit", p.config.EnduranceLimit),
+		zap.Duration("target_endurance", p.config.TargetEndurance),
+		zap.Float32("baseline_power", p.config.BaselinePower))
+
+	return nil
+}
+
+// Stop stops the recovery planning loop and releases an active baseline
+func (p *RecoveryPlanner) Stop() {
+	p.cancel()
+	p.wg.Wait()
+
+	p.mutex.RLock()
+	active := p.state.Active
+	p.mutex.RUnlock()
+	if active {
+		p.stopRecovery()
+	}
+
+	p.log.Info("FCR recovery planner stopped")
+}
+
+// GetState returns the recovery planning state from the last evaluation
+func (p *RecoveryPlanner) GetState() RecoveryState {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.state
+}
+
+// planLoop re-evaluates the endurance every check interval
+func (p *RecoveryPlanner) planLoop() {
+	ticker := time.NewTicker(p.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.plan()
+		}
+	}
+}
+
+// plan estimates the endurance and the SOC and starts or stops the
+// recovery baseline
+func (p *RecoveryPlanner) plan() {
+	now := time.Now()
+
+	chargeEnergy, dischargeEnergy := p.reservoirEnergy()
+	endurance := p.endurance(chargeEnergy, dischargeEnergy)
+	soc := p.aggregateSOC()
+
+	p.mutex.RLock()
+	wasActive := p.state.Active
+	startedAt := p.state.StartedAt
+	p.mutex.RUnlock()
+
+	enduranceLow := endurance < p.config.EnduranceLimit.Hours()
+	socLow := soc < p.socWindow.Min
+	socHigh := soc > p.socWindow.Max
+
+	active := wasActive
+	baseline := float32(0)
+
+	switch {
+	case !wasActive && (enduranceLow || socLow || socHigh):
+		baseline = p.baselinePower(chargeEnergy, dischargeEnergy, socLow, socHigh)
+		if p.startRecovery(baseline) {
+			active = true
+			startedAt = now
+			p.log.Warn("FCR recovery baseline scheduled",
+				zap.Float64("endurance_hours", endurance),
+				zap.Float32("soc", soc),
+				zap.Bool("endurance_low", enduranceLow),
+				zap.Bool("soc_outside_window", socLow || socHigh),
+				zap.Float32("baseline_power", baseline))
+		}
+
+	case wasActive && p.recovered(endurance, soc):
+		p.stopRecovery()
+		p.portfolio.RotateRecovery()
+		active = false
+		p.log.Info("FCR reservoir recovered, baseline released",
+			zap.Float64("endurance_hours", endurance),
+			zap.Float32("soc", soc),
+			zap.Duration("recovery_duration", now.Sub(startedAt)))
+
+	case wasActive:
+		// Re-assert the baseline so the direction follows the reservoir
+		baseline = p.baselinePower(chargeEnergy, dischargeEnergy, socLow, socHigh)
+		if !p.startRecovery(baseline) {
+			active = false
+		}
+	}
+
+	p.mutex.Lock()
+	p.state = RecoveryState{
+		Timestamp:          now,
+		Endurance:          endurance,
+		SOC:                soc,
+		ChargeEnergyKWh:    chargeEnergy,
+		DischargeEnergyKWh: dischargeEnergy,
+		Active:             active,
+		RecoveryGroup:      p.portfolio.RecoveryGroup().Name,
+		BaselinePowerKW:    baseline,
+		StartedAt:          startedAt,
+	}
+	if !active {
+		p.state.StartedAt = time.Time{}
+		p.state.BaselinePowerKW = 0
+	}
+	p.mutex.Unlock()
+}
+
+// recovered reports whether both the endurance and the SOC are back where
+// the recovery baseline can be released; the recover margin keeps the SOC
+// from oscillating around the window edges
+func (p *RecoveryPlanner) recovered(endurance float64, soc float32) bool {
+	if endurance < p.config.TargetEndurance.Hours() {
+		return false
+	}
+	return soc >= p.socWindow.Min+p.socWindow.RecoverMargin &&
+		soc <= p.socWindow.Max-p.socWindow.RecoverMargin
+}
+
+// aggregateSOC averages the true SOC reported by the connected BMS units.
+// The clamp-for-test flag substitutes the window midpoint whenever the SOC
+// leaves the window, mimicking a fully balanced reservoir for simulation
+// runs; it must stay off in production since it disables the SOC-triggered
+// recovery.
+func (p *RecoveryPlanner) aggregateSOC() float32 {
+	var sum float32
+	count := 0
+	for _, service := range p.bmsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		sum += service.GetLatestBMSData().SOC
+		count++
+	}
+
+	if count == 0 {
+		return (p.socWindow.Min + p.socWindow.Max) / 2
+	}
+	soc := sum / float32(count)
+
+	if p.socWindow.ClampForTest && (soc < p.socWindow.Min || soc > p.socWindow.Max) {
+		return (p.socWindow.Min + p.socWindow.Max) / 2
+	}
+	return soc
+}
+
+// reservoirEnergy sums the remaining charge and discharge energy of the
+// connected BMS units in kWh, derated to each string's usable SOC window
+func (p *RecoveryPlanner) reservoirEnergy() (charge, discharge float32) {
+	for id, service := range p.bmsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		data := service.GetLatestBMSData()
+		chargeEnergy := float32(data.ChargeCapacity)
+		dischargeEnergy := float32(data.DischargeCapacity)
+
+		// The BMS reports energy up to the physical bounds; scale it down
+		// to the fraction reachable inside this string's SOC window
+		if limits, ok := p.bmsLimits[id]; ok {
+			soc := data.SOC
+			if limits.MaxSOC > 0 && soc < 100 {
+				chargeEnergy *= usableFraction((limits.MaxSOC - soc) / (100 - soc))
+			}
+			if soc > 0 {
+				dischargeEnergy *= usableFraction((soc - limits.MinSOC) / soc)
+			}
+		}
+
+		charge += chargeEnergy
+		discharge += dischargeEnergy
+	}
+	return charge, discharge
+}
+
+// usableFraction clamps a SOC-window energy fraction to the 0..1 range
+func usableFraction(fraction float32) float32 {
+	if fraction < 0 {
+		return 0
+	}
+	if fraction > 1 {
+		return 1
+	}
+	return fraction
+}
+
+// endurance estimates for how many hours the reservoir can sustain full
+// activation in the constraining direction
+func (p *RecoveryPlanner) endurance(chargeEnergy, dischargeEnergy float32) float64 {
+	capacity := p.controller.GetActivationState().AvailableCapacityKW
+	if capacity <= 0 {
+		return p.config.TargetEndurance.Hours()
+	}
+
+	constraining := chargeEnergy
+	if dischargeEnergy < constraining {
+		constraining = dischargeEnergy
+	}
+	return float64(constraining / capacity)
+}
+
+// baselinePower picks the recovery direction: a SOC below the window or a
+// depleted discharge side charges (negative power), the opposite discharges
+func (p *RecoveryPlanner) baselinePower(chargeEnergy, dischargeEnergy float32, socLow, socHigh bool) float32 {
+	switch {
+	case socLow:
+		return -p.config.BaselinePower
+	case socHigh:
+		return p.config.BaselinePower
+	case dischargeEnergy < chargeEnergy:
+		return -p.config.BaselinePower
+	}
+	return p.config.BaselinePower
+}
+
+// startRecovery claims the recovery group's PCS units for the scheduler
+// source and applies the recovery baseline; a higher-priority source holding
+// any of them defers the recovery until the next evaluation. The portfolio
+// rotation is only advanced once a recovery completes, so the group stays
+// stable for its duration.
+func (p *RecoveryPlanner) startRecovery(power float32) bool {
+	group := p.portfolio.RecoveryGroup()
+
+	for _, pcsID := range group.PCSIDs {
+		if _, err := p.controlLogic.ArbitratePCSPowerCommand(pcsID, control.SourceScheduler, power); err != nil {
+			p.log.Warn("FCR recovery baseline deferred",
+				zap.String("group", group.Name),
+				zap.Error(err))
+			return false
+		}
+	}
+
+	for _, pcsID := range group.PCSIDs {
+		service, err := p.pcsManager.GetService(pcsID)
+		if err != nil {
+			continue
+		}
+		if err := service.SetActivePowerCommand(power); err != nil {
+			p.log.Error("Failed to apply FCR recovery baseline",
+				zap.String("group", group.Name),
+				zap.Int("pcs_id", pcsID),
+				zap.Error(err))
+			return false
+		}
+	}
+	return true
+}
+
+// stopRecovery sets the recovery group's baseline back to zero and releases
+// the scheduler's claim on its PCS units
+func (p *RecoveryPlanner) stopRecovery() {
+	group := p.portfolio.RecoveryGroup()
+
+	for _, pcsID := range group.PCSIDs {
+		service, err := p.pcsManager.GetService(pcsID)
+		if err != nil {
+			continue
+		}
+		if err := service.SetActivePowerCommand(0); err != nil {
+			p.log.Error("Failed to clear FCR recovery baseline",
+				zap.String("group", group.Name),
+				zap.Int("pcs_id", pcsID),
+				zap.Error(err))
+		}
+		p.controlLogic.ReleasePCSSetpoint(pcsID, control.SourceScheduler)
+	}
+}
@@ -0,0 +1,67 @@
+package fcr
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Selector picks the best available frequency source: the dedicated meter
+// when configured and fresh, otherwise the PCS grid frequency
+type Selector struct {
+	meter    *MeterSource
+	fallback FrequencySource
+	log      *zap.Logger
+
+	mutex        sync.Mutex
+	activeSource string
+}
+
+// NewSelector creates a frequency source selector; meter may be nil when no
+// dedicated transducer is configured
+func NewSelector(meter *MeterSource, fallback FrequencySource, logger *zap.Logger) *Selector {
+	return &Selector{
+		meter:    meter,
+		fallback: fallback,
+		log: logger.With(
+			zap.String("component", "frequency_selector"),
+		),
+	}
+}
+
+// GetFrequency returns the current grid frequency from the preferred source,
+// failing over to the fallback when the meter has no fresh sample
+func (s *Selector) GetFrequency() FrequencyMeasurement {
+	if s.meter != nil {
+		measurement := s.meter.GetFrequency()
+		if measurement.Valid {
+			s.noteSource(measurement.Source)
+			return measurement
+		}
+	}
+
+	measurement := s.fallback.GetFrequency()
+	if measurement.Valid {
+		s.noteSource(measurement.Source)
+	}
+	return measurement
+}
+
+// noteSource logs transitions between frequency sources
+func (s *Selector) noteSource(source string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.activeSource == source {
+		return
+	}
+
+	if s.activeSource != "" {
+		s.log.Warn("Frequency source changed",
+			zap.String("previous_source", s.activeSource),
+			zap.String("new_source", source))
+	} else {
+		s.log.Info("Frequency source selected", zap.String("source", source))
+	}
+	s.activeSource = source
+}
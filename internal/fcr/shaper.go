@@ -0,0 +1,80 @@
+package fcr
+
+import (
+	"time"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// Shaper shapes the activated FCR power before it is commanded to the PCS
+// units: an optional first-order filter followed by an optional ramp-rate
+// limit. The filter mimics the required dynamic response of the product and
+// the ramp limit avoids step changes that excite PCS oscillations. A stage
+// whose parameter is zero passes the value through unchanged.
+//
+// Shaper is not safe for concurrent use; it is owned by the fast control
+// loop and only advanced from there.
+type Shaper struct {
+	config config.FCRShapingConfig
+
+	output     float32
+	lastUpdate time.Time
+}
+
+// NewShaper creates a shaper with the given parameters, starting at zero
+// output
+func NewShaper(cfg config.FCRShapingConfig) *Shaper {
+	return &Shaper{
+		config: cfg,
+	}
+}
+
+// Apply advances the shaper towards the target power (kW) and returns the
+// shaped output. The time step is derived from the previous call; the first
+// call establishes the time base and returns the current output unchanged.
+func (s *Shaper) Apply(target float32, now time.Time) float32 {
+	if s.lastUpdate.IsZero() {
+		s.lastUpdate = now
+		return s.output
+	}
+
+	dt := float32(now.Sub(s.lastUpdate).Seconds())
+	s.lastUpdate = now
+	if dt <= 0 {
+		return s.output
+	}
+
+	value := target
+
+	// First-order filter: output moves a fraction dt/(tau+dt) towards the
+	// target each step
+	if tau := float32(s.config.FilterTimeConstant.Seconds()); tau > 0 {
+		alpha := dt / (tau + dt)
+		value = s.output + alpha*(value-s.output)
+	}
+
+	// Ramp-rate limit in kW/s, applied symmetrically in both directions
+	if rate := s.config.MaxRampRate; rate > 0 {
+		maxStep := rate * dt
+		if value > s.output+maxStep {
+			value = s.output + maxStep
+		} else if value < s.output-maxStep {
+			value = s.output - maxStep
+		}
+	}
+
+	s.output = value
+	return value
+}
+
+// Reset snaps the shaper output to the given value, bypassing both stages.
+// Used when activation is disabled so the next activation ramps from zero.
+func (s *Shaper) Reset(value float32) {
+	s.output = value
+	s.lastUpdate = time.Time{}
+}
+
+// Output returns the current shaped output without advancing the shaper
+func (s *Shaper) Output() float32 {
+	return s.output
+}
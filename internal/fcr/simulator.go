@@ -0,0 +1,238 @@
+package fcr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+)
+
+// simulationMaxStep caps the integration step between two historical samples
+// so gaps in the stored data do not integrate phantom energy
+const simulationMaxStep = time.Minute
+
+// simulationTrajectoryPoints limits the SOC trajectory returned to clients;
+// longer simulations are downsampled to at most this many points
+const simulationTrajectoryPoints = 1000
+
+// defaultRoundTripEfficiency is assumed when the request does not specify one
+const defaultRoundTripEfficiency = 0.9
+
+// SimulationRequest describes an offline sizing study: a candidate battery
+// and dispatch replayed against the stored frequency history of a period
+type SimulationRequest struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Product selects the activation parameters; empty uses the active product
+	Product string `json:"product"`
+
+	// FCRCapacityKW overrides the product max power when positive, so
+	// different prequalified capacities can be studied with one parameter set
+	FCRCapacityKW float32 `json:"fcr_capacity_kw"`
+
+	// BaselineKW is a constant candidate dispatch on top of the FCR
+	// activation; positive discharges the battery
+	BaselineKW float32 `json:"baseline_kw"`
+
+	CapacityKWh         float32 `json:"capacity_kwh"`
+	InitialSOC          float32 `json:"initial_soc"` // %, default 50
+	MinSOC              float32 `json:"min_soc"`     // %
+	MaxSOC              float32 `json:"max_soc"`     // %, default 100
+	RoundTripEfficiency float32 `json:"round_trip_efficiency"`
+}
+
+// SOCPoint is one point of the simulated SOC trajectory
+type SOCPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	SOC       float32   `json:"soc"`
+}
+
+// SimulationResult summarizes one simulation run: the SOC trajectory, the
+// SOC limit violations and the energy throughput of the candidate battery
+type SimulationResult struct {
+	Product       string  `json:"product"`
+	Samples       int     `json:"samples"`
+	FinalSOC      float32 `json:"final_soc"`
+	MinSOCReached float32 `json:"min_soc_reached"`
+	MaxSOCReached float32 `json:"max_soc_reached"`
+
+	// Violations count the excursions to the SOC limits; undelivered time
+	// accumulates while the limit prevents delivering the requested power
+	MinSOCViolations   int     `json:"min_soc_violations"`
+	MaxSOCViolations   int     `json:"max_soc_violations"`
+	UndeliveredSeconds float64 `json:"undelivered_seconds"`
+
+	ChargedKWh       float64 `json:"charged_kwh"`
+	DischargedKWh    float64 `json:"discharged_kwh"`
+	EquivalentCycles float64 `json:"equivalent_cycles"`
+
+	SOCTrajectory []SOCPoint `json:"soc_trajectory"`
+}
+
+// Simulate replays the stored frequency history of the requested period
+// through the real activation and shaping code and integrates the resulting
+// power into the SOC of a candidate battery. The live controller state is
+// not touched; the simulation runs on its own shaper instance.
+func (c *Controller) Simulate(ctx context.Context, request SimulationRequest) (SimulationResult, error) {
+	if request.Start.IsZero() || request.End.IsZero() || !request.End.After(request.Start) {
+		return SimulationResult{}, fmt.Errorf("simulation period must have an end after its start")
+	}
+	if request.CapacityKWh <= 0 {
+		return SimulationResult{}, fmt.Errorf("simulation requires a positive battery capacity")
+	}
+	if request.MaxSOC == 0 {
+		request.MaxSOC = 100
+	}
+	if request.MinSOC < 0 || request.MaxSOC > 100 || request.MinSOC >= request.MaxSOC {
+		return SimulationResult{}, fmt.Errorf("invalid SOC limits: min %.1f, max %.1f", request.MinSOC, request.MaxSOC)
+	}
+	if request.InitialSOC == 0 {
+		request.InitialSOC = 50
+	}
+	if request.RoundTripEfficiency == 0 {
+		request.RoundTripEfficiency = defaultRoundTripEfficiency
+	}
+	if request.RoundTripEfficiency <= 0 || request.RoundTripEfficiency > 1 {
+		return SimulationResult{}, fmt.Errorf("round-trip efficiency must be in (0, 1]")
+	}
+
+	c.mutex.RLock()
+	product := request.Product
+	if product == "" {
+		product = c.activeProduct
+	}
+	params, haveParams := c.products[product]
+	c.mutex.RUnlock()
+
+	if !haveParams {
+		return SimulationResult{}, fmt.Errorf("unknown FCR product: %s", product)
+	}
+	if request.FCRCapacityKW > 0 {
+		params.MaxPower = request.FCRCapacityKW
+	}
+
+	frequencies, err := c.influxDB.QueryFieldSeries(ctx, "ion7400", "frequency", request.Start, request.End)
+	if err != nil {
+		return SimulationResult{}, fmt.Errorf("failed to load frequency history: %w", err)
+	}
+	if len(frequencies) < 2 {
+		return SimulationResult{}, fmt.Errorf("not enough frequency history in the requested period")
+	}
+
+	return runSimulation(ctx, request, product, params, c.config.Shaping, frequencies)
+}
+
+// runSimulation steps through the frequency samples and integrates the SOC
+// of the candidate battery; it aborts when the caller's context ends
+func runSimulation(ctx context.Context, request SimulationRequest, product string, params config.FCRProductConfig, shaping config.FCRShapingConfig, frequencies []database.FieldSample) (SimulationResult, error) {
+	result := SimulationResult{
+		Product:       product,
+		Samples:       len(frequencies),
+		MinSOCReached: request.InitialSOC,
+		MaxSOCReached: request.InitialSOC,
+	}
+
+	shaper := NewShaper(shaping)
+
+	// One-way efficiency, applied on both charge and discharge so the two
+	// legs multiply to the round-trip value
+	efficiency := math.Sqrt(float64(request.RoundTripEfficiency))
+
+	soc := float64(request.InitialSOC)
+	capacity := float64(request.CapacityKWh)
+	minSOC := float64(request.MinSOC)
+	maxSOC := float64(request.MaxSOC)
+	atMin := false
+	atMax := false
+
+	stride := len(frequencies) / simulationTrajectoryPoints
+	if stride < 1 {
+		stride = 1
+	}
+
+	for i, sample := range frequencies {
+		// Abort on cancellation or deadline, reporting the progress made
+		if i%4096 == 0 && ctx.Err() != nil {
+			return SimulationResult{}, fmt.Errorf("simulation aborted after %d of %d samples: %w",
+				i, len(frequencies), ctx.Err())
+		}
+
+		target := request.BaselineKW + computeActivation(float32(sample.Value), params)
+		shaped := shaper.Apply(target, sample.Timestamp)
+
+		if i > 0 {
+			dt := sample.Timestamp.Sub(frequencies[i-1].Timestamp)
+			if dt > simulationMaxStep {
+				dt = simulationMaxStep
+			}
+			hours := dt.Hours()
+
+			delivered := float64(shaped)
+			if soc <= minSOC && delivered > 0 {
+				delivered = 0
+				result.UndeliveredSeconds += dt.Seconds()
+			} else if soc >= maxSOC && delivered < 0 {
+				delivered = 0
+				result.UndeliveredSeconds += dt.Seconds()
+			}
+
+			if delivered > 0 {
+				energy := delivered * hours
+				result.DischargedKWh += energy
+				soc -= energy / efficiency / capacity * 100
+			} else if delivered < 0 {
+				energy := -delivered * hours
+				result.ChargedKWh += energy
+				soc += energy * efficiency / capacity * 100
+			}
+
+			if soc < minSOC {
+				soc = minSOC
+			}
+			if soc > maxSOC {
+				soc = maxSOC
+			}
+		}
+
+		// Count each excursion to a limit once
+		if soc <= minSOC {
+			if !atMin {
+				result.MinSOCViolations++
+				atMin = true
+			}
+		} else {
+			atMin = false
+		}
+		if soc >= maxSOC {
+			if !atMax {
+				result.MaxSOCViolations++
+				atMax = true
+			}
+		} else {
+			atMax = false
+		}
+
+		if float32(soc) < result.MinSOCReached {
+			result.MinSOCReached = float32(soc)
+		}
+		if float32(soc) > result.MaxSOCReached {
+			result.MaxSOCReached = float32(soc)
+		}
+
+		if i%stride == 0 || i == len(frequencies)-1 {
+			result.SOCTrajectory = append(result.SOCTrajectory, SOCPoint{
+				Timestamp: sample.Timestamp,
+				SOC:       float32(soc),
+			})
+		}
+	}
+
+	result.FinalSOC = float32(soc)
+	result.EquivalentCycles = (result.ChargedKWh + result.DischargedKWh) / 2 / capacity
+
+	return result, nil
+}
@@ -0,0 +1,145 @@
+// Package features provides feature flags gating risky new subsystems per
+// site. Flags default to off, can be enabled in configuration and can be
+// flipped at runtime through the API without a redeploy; runtime overrides
+// do not survive a restart.
+package features
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// Known feature flags
+const (
+	FlagClosedLoopControl   = "closed_loop_control"
+	FlagAutoDispatch        = "auto_dispatch"
+	FlagPredictiveAnalytics = "predictive_analytics"
+)
+
+// knownFlags lists every flag the manager accepts; new subsystems register
+// their flag here
+var knownFlags = []string{
+	FlagClosedLoopControl,
+	FlagAutoDispatch,
+	FlagPredictiveAnalytics,
+}
+
+// Flag describes the state of one feature flag
+type Flag struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"` // default, config or runtime
+}
+
+// Manager resolves feature flags from configuration with runtime overrides
+type Manager struct {
+	log *zap.Logger
+
+	mutex     sync.RWMutex
+	fromFile  map[string]bool
+	overrides map[string]bool
+}
+
+// NewManager creates a feature flag manager from the configured flag set
+func NewManager(cfg *config.Config, logger *zap.Logger) *Manager {
+	manager := &Manager{
+		fromFile:  make(map[string]bool),
+		overrides: make(map[string]bool),
+		log: logger.With(
+			zap.String("component", "features"),
+		),
+	}
+
+	for name, enabled := range cfg.EMS.FeatureFlags {
+		if !isKnownFlag(name) {
+			manager.log.Warn("Ignoring unknown feature flag in configuration",
+				zap.String("flag", name))
+			continue
+		}
+		manager.fromFile[name] = enabled
+		if enabled {
+			manager.log.Info("Feature enabled by configuration", zap.String("flag", name))
+		}
+	}
+
+	return manager
+}
+
+// isKnownFlag reports whether the flag name is registered
+func isKnownFlag(name string) bool {
+	for _, known := range knownFlags {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled reports whether a feature is enabled, preferring runtime overrides
+// over configuration. Unknown flags are always disabled.
+func (m *Manager) Enabled(name string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if enabled, overridden := m.overrides[name]; overridden {
+		return enabled
+	}
+	return m.fromFile[name]
+}
+
+// SetFlag applies a runtime override for a feature flag
+func (m *Manager) SetFlag(name string, enabled bool) error {
+	if !isKnownFlag(name) {
+		return fmt.Errorf("unknown feature flag: %s", name)
+	}
+
+	m.mutex.Lock()
+	m.overrides[name] = enabled
+	m.mutex.Unlock()
+
+	m.log.Warn("Feature flag changed at runtime",
+		zap.String("flag", name),
+		zap.Bool("enabled", enabled))
+
+	return nil
+}
+
+// ClearFlag removes a runtime override, falling back to the configured value
+func (m *Manager) ClearFlag(name string) error {
+	if !isKnownFlag(name) {
+		return fmt.Errorf("unknown feature flag: %s", name)
+	}
+
+	m.mutex.Lock()
+	delete(m.overrides, name)
+	m.mutex.Unlock()
+
+	m.log.Info("Feature flag override cleared", zap.String("flag", name))
+
+	return nil
+}
+
+// GetFlags returns the state of every known feature flag
+func (m *Manager) GetFlags() []Flag {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	flags := make([]Flag, 0, len(knownFlags))
+	for _, name := range knownFlags {
+		flag := Flag{Name: name, Source: "default"}
+		if enabled, configured := m.fromFile[name]; configured {
+			flag.Enabled = enabled
+			flag.Source = "config"
+		}
+		if enabled, overridden := m.overrides[name]; overridden {
+			flag.Enabled = enabled
+			flag.Source = "runtime"
+		}
+		flags = append(flags, flag)
+	}
+	return flags
+}
@@ -0,0 +1,10 @@
+package features
+
+import (
+	"go.uber.org/fx"
+)
+
+// Module provides feature flags to the Fx application
+var Module = fx.Module("features",
+	fx.Provide(NewManager),
+)
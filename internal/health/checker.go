@@ -3,8 +3,11 @@ package health
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
+
+	"powerkonnekt/ems/pkg/loopwatch"
 )
 
 type Status string
@@ -155,3 +158,21 @@ func (s *ServiceChecker) Check(ctx context.Context) error {
 	}
 	return nil
 }
+
+// Loop Health Checker, backed by the loopwatch registry
+type LoopChecker struct{}
+
+func NewLoopChecker() *LoopChecker {
+	return &LoopChecker{}
+}
+
+func (l *LoopChecker) Name() string {
+	return "loops"
+}
+
+func (l *LoopChecker) Check(ctx context.Context) error {
+	if stalled := loopwatch.Stalled(); len(stalled) > 0 {
+		return fmt.Errorf("stalled loops: %s", strings.Join(stalled, ", "))
+	}
+	return nil
+}
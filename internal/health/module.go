@@ -6,6 +6,7 @@ import (
 	"go.uber.org/fx"
 
 	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/database"
 	"powerkonnekt/ems/internal/pcs"
 	"powerkonnekt/ems/internal/plc"
 	"powerkonnekt/ems/internal/windfarm"
@@ -22,9 +23,20 @@ func ProvideHealthService(
 	pcsManager *pcs.Manager,
 	plcManager *plc.Manager,
 	windFarmManager *windfarm.Manager,
+	influxDB *database.InfluxDB,
+	postgreSQL *database.PostgreSQL,
 ) *HealthService {
 	healthService := NewService()
 
+	// Register the databases; they may have started degraded and their
+	// absence shows up here rather than blocking the boot
+	healthService.RegisterChecker(NewDatabaseChecker("influxdb", influxDB))
+	healthService.RegisterChecker(NewDatabaseChecker("postgresql", postgreSQL))
+
+	// Register the periodic-loop watchdog so a stalled control or poll
+	// loop is detected from its missing heartbeat
+	healthService.RegisterChecker(NewLoopChecker())
+
 	// Register health checkers for all BMS instances
 	bmsServices := bmsManager.GetAllServices()
 	for bmsID, bmsService := range bmsServices {
@@ -0,0 +1,177 @@
+// Package historian forwards telemetry to third-party plant historians.
+// Every point written to InfluxDB is mirrored into a bounded buffer and
+// flushed in batches to the configured sinks (OSIsoft PI Web API, OPC UA),
+// for customers that mandate plant data lands in their corporate historian
+// directly.
+package historian
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// Sample is one historian data point, decoupled from the InfluxDB line
+// protocol types
+type Sample struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]any
+	Timestamp   time.Time
+}
+
+// Sink delivers sample batches to one historian backend
+type Sink interface {
+	Name() string
+	Write(samples []Sample) error
+	Close() error
+}
+
+// Service buffers forwarded telemetry and flushes it to every configured
+// sink. The hot write path only enqueues; a full buffer drops the sample
+// so a slow historian can never stall plant telemetry.
+type Service struct {
+	config config.HistorianConfig
+	sinks  []Sink
+	buffer chan Sample
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	log    *zap.Logger
+
+	mutex   sync.Mutex
+	dropped uint64
+}
+
+// NewService creates a new historian forwarding service
+func NewService(cfg config.HistorianConfig, logger *zap.Logger) (*Service, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	serviceLogger := logger.With(
+		zap.String("service", "historian"),
+	)
+
+	service := &Service{
+		config: cfg,
+		buffer: make(chan Sample, cfg.BufferSize),
+		ctx:    ctx,
+		cancel: cancel,
+		log:    serviceLogger,
+	}
+
+	if !cfg.Enabled {
+		return service, nil
+	}
+
+	if cfg.PI.Enabled {
+		service.sinks = append(service.sinks, newPISink(cfg.PI, serviceLogger))
+	}
+	if cfg.OPCUA.Enabled {
+		service.sinks = append(service.sinks, newOPCUASink(cfg.OPCUA, serviceLogger))
+	}
+
+	return service, nil
+}
+
+// Start begins the flush loop
+func (s *Service) Start() error {
+	if !s.config.Enabled || len(s.sinks) == 0 {
+		s.log.Info("Historian forwarding disabled")
+		return nil
+	}
+
+	s.wg.Go(s.flushLoop)
+
+	names := make([]string, len(s.sinks))
+	for i, sink := range s.sinks {
+		names[i] = sink.Name()
+	}
+	s.log.Info("Historian forwarding started",
+		zap.Strings("sinks", names),
+		zap.Duration("flush_interval", s.config.FlushInterval),
+		zap.Int("buffer_size", s.config.BufferSize))
+	return nil
+}
+
+// Stop flushes the remaining buffer and closes the sinks
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	for _, sink := range s.sinks {
+		if err := sink.Close(); err != nil {
+			s.log.Warn("Failed to close historian sink",
+				zap.String("sink", sink.Name()),
+				zap.Error(err))
+		}
+	}
+	s.log.Info("Historian forwarding stopped")
+}
+
+// Forward mirrors one telemetry point into the buffer; it never blocks the
+// caller. Implements the database.PointForwarder interface.
+func (s *Service) Forward(measurement string, tags map[string]string, fields map[string]any, timestamp time.Time) {
+	if !s.config.Enabled || len(s.sinks) == 0 {
+		return
+	}
+
+	select {
+	case s.buffer <- Sample{Measurement: measurement, Tags: tags, Fields: fields, Timestamp: timestamp}:
+	default:
+		s.mutex.Lock()
+		s.dropped++
+		dropped := s.dropped
+		s.mutex.Unlock()
+
+		// Log the first drop of every thousand so a persistently slow
+		// historian is visible without flooding the log
+		if dropped%1000 == 1 {
+			s.log.Warn("Historian buffer full, dropping telemetry",
+				zap.Uint64("dropped_total", dropped))
+		}
+	}
+}
+
+// flushLoop drains the buffer on every flush interval and hands the batch
+// to each sink
+func (s *Service) flushLoop() {
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.flush()
+			return
+		case <-ticker.C:
+			s.flush()
+		}
+	}
+}
+
+// flush drains the buffered samples and writes them to every sink
+func (s *Service) flush() {
+	var batch []Sample
+	for {
+		select {
+		case sample := <-s.buffer:
+			batch = append(batch, sample)
+		default:
+			if len(batch) == 0 {
+				return
+			}
+			for _, sink := range s.sinks {
+				if err := sink.Write(batch); err != nil {
+					s.log.Warn("Historian write failed",
+						zap.String("sink", sink.Name()),
+						zap.Int("samples", len(batch)),
+						zap.Error(err))
+				}
+			}
+			return
+		}
+	}
+}
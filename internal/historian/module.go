@@ -0,0 +1,38 @@
+package historian
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+)
+
+// Module provides the historian forwarding service to the Fx application
+var Module = fx.Module("historian",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides a historian service instance
+func ProvideService(cfg *config.Config, logger *zap.Logger) (*Service, error) {
+	return NewService(cfg.Historian, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the historian service and
+// hooks it into the InfluxDB write path
+func RegisterLifecycle(lc fx.Lifecycle, service *Service, influxDB *database.InfluxDB) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			influxDB.SetPointForwarder(service)
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			influxDB.SetPointForwarder(nil)
+			service.Stop()
+			return nil
+		},
+	})
+}
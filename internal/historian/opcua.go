@@ -0,0 +1,143 @@
+package historian
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// opcuaWriteChunk caps the number of nodes in one write request so a large
+// flush does not exceed the server's operation limits
+const opcuaWriteChunk = 500
+
+// opcuaSink delivers sample batches to an OPC UA historian by writing each
+// field to a string node id derived from the configured prefix, the
+// measurement and the sample's tag values.
+type opcuaSink struct {
+	config config.OPCUAConfig
+	client *opcua.Client
+	log    *zap.Logger
+}
+
+// newOPCUASink creates the OPC UA historian sink; the connection is
+// established lazily on the first write
+func newOPCUASink(cfg config.OPCUAConfig, logger *zap.Logger) *opcuaSink {
+	return &opcuaSink{
+		config: cfg,
+		log:    logger.With(zap.String("sink", "opcua")),
+	}
+}
+
+// Name returns the sink name
+func (o *opcuaSink) Name() string {
+	return "opcua"
+}
+
+// Write converts the batch into node writes and submits them in chunks
+func (o *opcuaSink) Write(samples []Sample) error {
+	ctx, cancel := context.WithTimeout(context.Background(), o.config.Timeout)
+	defer cancel()
+
+	if err := o.connect(ctx); err != nil {
+		return err
+	}
+
+	var writes []*ua.WriteValue
+	for _, sample := range samples {
+		container := containerID(sample)
+		for field, value := range sample.Fields {
+			variant, err := ua.NewVariant(value)
+			if err != nil {
+				// Skip values the OPC UA type system cannot represent
+				continue
+			}
+
+			nodeID := fmt.Sprintf("%s.%s.%s", o.config.NodePrefix, container, field)
+			writes = append(writes, &ua.WriteValue{
+				NodeID:      ua.NewStringNodeID(uint16(o.config.Namespace), nodeID),
+				AttributeID: ua.AttributeIDValue,
+				Value: &ua.DataValue{
+					EncodingMask:    ua.DataValueValue | ua.DataValueSourceTimestamp,
+					Value:           variant,
+					SourceTimestamp: sample.Timestamp,
+				},
+			})
+		}
+	}
+
+	for start := 0; start < len(writes); start += opcuaWriteChunk {
+		end := start + opcuaWriteChunk
+		if end > len(writes) {
+			end = len(writes)
+		}
+
+		response, err := o.client.Write(ctx, &ua.WriteRequest{NodesToWrite: writes[start:end]})
+		if err != nil {
+			o.disconnect()
+			return fmt.Errorf("OPC UA write failed: %w", err)
+		}
+
+		for i, result := range response.Results {
+			if result != ua.StatusOK {
+				o.log.Debug("OPC UA server rejected node write",
+					zap.String("node_id", writes[start+i].NodeID.String()),
+					zap.String("status", result.Error()))
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close disconnects from the OPC UA server
+func (o *opcuaSink) Close() error {
+	if o.client == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.config.Timeout)
+	defer cancel()
+
+	err := o.client.Close(ctx)
+	o.client = nil
+	return err
+}
+
+// connect establishes the client session when none is active
+func (o *opcuaSink) connect(ctx context.Context) error {
+	if o.client != nil {
+		return nil
+	}
+
+	client, err := opcua.NewClient(o.config.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to create OPC UA client: %w", err)
+	}
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to OPC UA server: %w", err)
+	}
+
+	o.client = client
+	o.log.Info("Connected to OPC UA historian",
+		zap.String("endpoint", o.config.Endpoint))
+	return nil
+}
+
+// disconnect drops the client so the next write reconnects
+func (o *opcuaSink) disconnect() {
+	if o.client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.config.Timeout)
+	defer cancel()
+
+	_ = o.client.Close(ctx)
+	o.client = nil
+}
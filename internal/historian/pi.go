@@ -0,0 +1,128 @@
+package historian
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// piSink delivers sample batches to an OSIsoft PI Web API OMF ingress
+// endpoint. Each batch is one OMF data message; values of the same
+// measurement and tag set share a container.
+type piSink struct {
+	config config.PIConfig
+	client *http.Client
+	log    *zap.Logger
+}
+
+// piContainer is one OMF data message entry
+type piContainer struct {
+	ContainerID string           `json:"containerid"`
+	Values      []map[string]any `json:"values"`
+}
+
+// newPISink creates the PI Web API sink
+func newPISink(cfg config.PIConfig, logger *zap.Logger) *piSink {
+	return &piSink{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		log:    logger.With(zap.String("sink", "pi")),
+	}
+}
+
+// Name returns the sink name
+func (p *piSink) Name() string {
+	return "pi"
+}
+
+// Write POSTs the batch as one OMF data message
+func (p *piSink) Write(samples []Sample) error {
+	grouped := make(map[string]*piContainer)
+	var order []string
+
+	for _, sample := range samples {
+		id := containerID(sample)
+		container, exists := grouped[id]
+		if !exists {
+			container = &piContainer{ContainerID: id}
+			grouped[id] = container
+			order = append(order, id)
+		}
+
+		values := make(map[string]any, len(sample.Fields)+1)
+		values["time"] = sample.Timestamp.UTC().Format(time.RFC3339Nano)
+		for field, value := range sample.Fields {
+			values[field] = value
+		}
+		container.Values = append(container.Values, values)
+	}
+
+	message := make([]*piContainer, len(order))
+	for i, id := range order {
+		message[i] = grouped[id]
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal OMF message: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, p.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build OMF request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("messagetype", "data")
+	request.Header.Set("omfversion", "1.2")
+	request.Header.Set("action", "create")
+	if p.config.Username != "" {
+		request.SetBasicAuth(p.config.Username, p.config.Password)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("OMF request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("OMF ingress rejected the batch: %s", response.Status)
+	}
+
+	return nil
+}
+
+// Close releases the HTTP client
+func (p *piSink) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+// containerID derives the OMF container id of a sample: the measurement
+// followed by its tag values in key order
+func containerID(sample Sample) string {
+	if len(sample.Tags) == 0 {
+		return sample.Measurement
+	}
+
+	keys := make([]string, 0, len(sample.Tags))
+	for key := range sample.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys)+1)
+	parts = append(parts, sample.Measurement)
+	for _, key := range keys {
+		parts = append(parts, sample.Tags[key])
+	}
+	return strings.Join(parts, ".")
+}
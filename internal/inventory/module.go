@@ -0,0 +1,45 @@
+package inventory
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/plc"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// Module provides CMDB inventory registration to the Fx application
+var Module = fx.Module("inventory",
+	fx.Provide(ProvideRegistrar),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideRegistrar creates and provides the CMDB registrar
+func ProvideRegistrar(
+	cfg *config.Config,
+	bmsManager *bms.Manager,
+	pcsManager *pcs.Manager,
+	plcManager *plc.Manager,
+	windFarmManager *windfarm.Manager,
+	logger *zap.Logger,
+) *Registrar {
+	return NewRegistrar(cfg.CMDB, cfg, bmsManager, pcsManager, plcManager, windFarmManager, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the CMDB registrar
+func RegisterLifecycle(lc fx.Lifecycle, registrar *Registrar) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return registrar.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			registrar.Stop()
+			return nil
+		},
+	})
+}
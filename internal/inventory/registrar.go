@@ -0,0 +1,275 @@
+// Package inventory registers the EMS and its device inventory with the
+// central asset-management system (CMDB) over a configurable REST webhook.
+// The full inventory is submitted on startup and re-submitted whenever it
+// changes (for example a device going offline) or the refresh interval
+// elapses, replacing manual commissioning paperwork.
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/plc"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// checkInterval is how often the inventory snapshot is compared against the
+// last registered state
+const checkInterval = time.Minute
+
+// Device describes one inventory entry submitted to the CMDB
+type Device struct {
+	Type      string `json:"type"`
+	ID        int    `json:"id"`
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	SlaveID   byte   `json:"slave_id"`
+	Connected bool   `json:"connected"`
+}
+
+// Registration is the payload submitted to the CMDB endpoint
+type Registration struct {
+	ParkName  string    `json:"park_name"`
+	Timestamp time.Time `json:"timestamp"`
+	Devices   []Device  `json:"devices"`
+}
+
+// Registrar keeps the asset-management system in sync with the device
+// inventory of this site
+type Registrar struct {
+	config          config.CMDBConfig
+	appConfig       *config.Config
+	bmsManager      *bms.Manager
+	pcsManager      *pcs.Manager
+	plcManager      *plc.Manager
+	windFarmManager *windfarm.Manager
+	client          *http.Client
+	ctx             context.Context
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	log             *zap.Logger
+
+	mutex          sync.Mutex
+	lastHash       string
+	lastRegistered time.Time
+}
+
+// NewRegistrar creates a new CMDB registrar
+func NewRegistrar(
+	cfg config.CMDBConfig,
+	appConfig *config.Config,
+	bmsManager *bms.Manager,
+	pcsManager *pcs.Manager,
+	plcManager *plc.Manager,
+	windFarmManager *windfarm.Manager,
+	logger *zap.Logger,
+) *Registrar {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Registrar{
+		config:          cfg,
+		appConfig:       appConfig,
+		bmsManager:      bmsManager,
+		pcsManager:      pcsManager,
+		plcManager:      plcManager,
+		windFarmManager: windFarmManager,
+		client:          &http.Client{Timeout: cfg.Timeout},
+		ctx:             ctx,
+		cancel:          cancel,
+		log: logger.With(
+			zap.String("component", "inventory"),
+			zap.String("url", cfg.URL),
+		),
+	}
+}
+
+// Start registers the inventory and launches the update loop
+func (r *Registrar) Start() error {
+	if !r.config.Enabled {
+		return nil
+	}
+
+	r.wg.Go(r.registrationLoop)
+	r.log.Info("CMDB registration started",
+		zap.Duration("refresh_interval", r.config.RefreshInterval))
+	return nil
+}
+
+// Stop stops the registrar
+func (r *Registrar) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// registrationLoop submits the inventory on startup and re-submits it when
+// it changes or the refresh interval elapses
+func (r *Registrar) registrationLoop() {
+	r.registerIfNeeded(true)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.registerIfNeeded(false)
+		}
+	}
+}
+
+// registerIfNeeded submits the inventory when forced, changed or stale
+func (r *Registrar) registerIfNeeded(force bool) {
+	registration := r.buildRegistration()
+	hash := registrationHash(registration)
+
+	r.mutex.Lock()
+	due := force || hash != r.lastHash ||
+		time.Since(r.lastRegistered) >= r.config.RefreshInterval
+	r.mutex.Unlock()
+
+	if !due {
+		return
+	}
+
+	if err := r.submit(registration); err != nil {
+		r.log.Error("Failed to register inventory with CMDB", zap.Error(err))
+		return
+	}
+
+	r.mutex.Lock()
+	r.lastHash = hash
+	r.lastRegistered = time.Now()
+	r.mutex.Unlock()
+
+	r.log.Info("Inventory registered with CMDB",
+		zap.Int("devices", len(registration.Devices)))
+}
+
+// buildRegistration assembles the current inventory snapshot from the
+// device configuration and live connection states
+func (r *Registrar) buildRegistration() Registration {
+	var devices []Device
+
+	pcsServices := r.pcsManager.GetAllServices()
+	for _, deviceCfg := range r.appConfig.PCS {
+		connected := false
+		if service, exists := pcsServices[deviceCfg.ID]; exists {
+			connected = service.IsConnected()
+		}
+		devices = append(devices, Device{
+			Type: "pcs", ID: deviceCfg.ID, Host: deviceCfg.Host,
+			Port: deviceCfg.Port, SlaveID: deviceCfg.SlaveID, Connected: connected,
+		})
+	}
+
+	bmsServices := r.bmsManager.GetAllServices()
+	for _, deviceCfg := range r.appConfig.BMS {
+		connected := false
+		if service, exists := bmsServices[deviceCfg.ID]; exists {
+			connected = service.IsConnected()
+		}
+		devices = append(devices, Device{
+			Type: "bms", ID: deviceCfg.ID, Host: deviceCfg.Host,
+			Port: deviceCfg.Port, SlaveID: deviceCfg.SlaveID, Connected: connected,
+		})
+	}
+
+	plcServices := r.plcManager.GetAllServices()
+	for _, deviceCfg := range r.appConfig.PLC {
+		connected := false
+		if service, exists := plcServices[deviceCfg.ID]; exists {
+			connected = service.IsConnected()
+		}
+		devices = append(devices, Device{
+			Type: "plc", ID: deviceCfg.ID, Host: deviceCfg.Host,
+			Port: deviceCfg.Port, SlaveID: deviceCfg.SlaveID, Connected: connected,
+		})
+	}
+
+	windFarmServices := r.windFarmManager.GetAllServices()
+	for _, deviceCfg := range r.appConfig.WindFarm {
+		connected := false
+		if service, exists := windFarmServices[deviceCfg.ID]; exists {
+			connected = service.IsConnected()
+		}
+		devices = append(devices, Device{
+			Type: "windfarm", ID: deviceCfg.ID, Host: deviceCfg.Host,
+			Port: deviceCfg.Port, SlaveID: deviceCfg.SlaveID, Connected: connected,
+		})
+	}
+
+	devices = append(devices, Device{
+		Type: "ion7400", ID: 1, Host: r.appConfig.ION7400.Host,
+		Port: r.appConfig.ION7400.Port, SlaveID: r.appConfig.ION7400.SlaveID,
+	})
+
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Type != devices[j].Type {
+			return devices[i].Type < devices[j].Type
+		}
+		return devices[i].ID < devices[j].ID
+	})
+
+	return Registration{
+		ParkName:  r.appConfig.EMS.ParkName,
+		Timestamp: time.Now(),
+		Devices:   devices,
+	}
+}
+
+// registrationHash fingerprints the registration without its timestamp so
+// unchanged inventories are not re-submitted
+func registrationHash(registration Registration) string {
+	registration.Timestamp = time.Time{}
+	encoded, err := json.Marshal(registration)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// submit posts the registration to the configured CMDB endpoint
+func (r *Registrar) submit(registration Registration) error {
+	encoded, err := json.Marshal(registration)
+	if err != nil {
+		return fmt.Errorf("failed to encode registration: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(r.ctx, http.MethodPost, r.config.URL, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if r.config.APIKey != "" {
+		request.Header.Set("Authorization", "Bearer "+r.config.APIKey)
+	}
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("CMDB endpoint returned status %d", response.StatusCode)
+	}
+	return nil
+}
@@ -11,11 +11,14 @@ import (
 	"github.com/shirou/gopsutil/v3/net"
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/pkg/cache"
 )
 
 // Manager handles metrics collection and storage
 type Manager struct {
+	config   config.EMSConfig
 	influxDB *database.InfluxDB
 	ctx      context.Context
 	cancel   context.CancelFunc
@@ -26,10 +29,15 @@ type Manager struct {
 	startTime time.Time
 	lastNetRx uint64
 	lastNetTx uint64
+
+	// Automatic profile capture state
+	lastCapture   time.Time
+	captureActive bool
+	captures      []ProfileCapture
 }
 
 // NewManager creates a new metrics manager
-func NewManager(influxDB *database.InfluxDB, logger *zap.Logger) *Manager {
+func NewManager(cfg config.EMSConfig, influxDB *database.InfluxDB, logger *zap.Logger) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Create component-specific logger
@@ -38,6 +46,7 @@ func NewManager(influxDB *database.InfluxDB, logger *zap.Logger) *Manager {
 	)
 
 	return &Manager{
+		config:    cfg,
 		influxDB:  influxDB,
 		ctx:       ctx,
 		cancel:    cancel,
@@ -76,6 +85,8 @@ func (m *Manager) collectLoop() {
 		case <-ticker.C:
 			m.collectSystemMetrics()
 			m.collectRuntimeMetrics()
+			m.collectCacheMetrics()
+			m.checkProfileTriggers()
 		}
 	}
 }
@@ -194,3 +205,27 @@ func (m *Manager) collectRuntimeMetrics() {
 		m.log.Error("Failed to save runtime metrics to InfluxDB", zap.Error(err))
 	}
 }
+
+// collectCacheMetrics stores the statistics of every registered in-memory
+// cache, so the edge hardware can be sized against real occupancy
+func (m *Manager) collectCacheMetrics() {
+	now := time.Now()
+	for _, stats := range cache.CollectStats() {
+		cacheMetrics := database.CacheMetrics{
+			Timestamp: now,
+			Name:      stats.Name,
+			Size:      stats.Size,
+			Capacity:  stats.Capacity,
+			Hits:      stats.Hits,
+			Misses:    stats.Misses,
+			Evictions: stats.Evictions,
+			HitRate:   stats.HitRate,
+		}
+
+		if err := m.influxDB.WriteCacheMetrics(cacheMetrics); err != nil {
+			m.log.Error("Failed to save cache metrics to InfluxDB",
+				zap.Error(err),
+				zap.String("cache", stats.Name))
+		}
+	}
+}
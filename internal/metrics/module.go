@@ -6,6 +6,7 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
 )
 
@@ -16,8 +17,8 @@ var Module = fx.Module("metrics",
 )
 
 // ProvideManager creates and provides a metrics manager instance
-func ProvideManager(lc fx.Lifecycle, influxDB *database.InfluxDB, logger *zap.Logger) *Manager {
-	return NewManager(influxDB, logger)
+func ProvideManager(lc fx.Lifecycle, cfg *config.Config, influxDB *database.InfluxDB, logger *zap.Logger) *Manager {
+	return NewManager(cfg.EMS, influxDB, logger)
 }
 
 // RegisterLifecycle registers lifecycle hooks for the metrics manager
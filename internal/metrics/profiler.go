@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Automatic profile capture: when the runtime metrics cross the configured
+// thresholds, goroutine, heap and CPU profiles are written to the capture
+// directory so a remote performance issue can be diagnosed after the fact
+const (
+	// profileCooldown is the minimum time between automatic captures, so a
+	// sustained anomaly does not fill the disk
+	profileCooldown = 15 * time.Minute
+
+	// cpuProfileDuration is how long the CPU profile samples
+	cpuProfileDuration = 10 * time.Second
+
+	// maxProfileCaptures is how many captures are retained on disk; the
+	// oldest are pruned when a new one is written
+	maxProfileCaptures = 20
+)
+
+// ProfileCapture describes one automatic profile capture on disk
+type ProfileCapture struct {
+	Timestamp time.Time `json:"timestamp"`
+	Reason    string    `json:"reason"`
+	Directory string    `json:"directory"`
+	Files     []string  `json:"files"`
+}
+
+// checkProfileTriggers compares the current runtime metrics against the
+// configured thresholds and starts a capture when one is crossed
+func (m *Manager) checkProfileTriggers() {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	goroutines := runtime.NumGoroutine()
+	heapAllocMB := float64(memStats.HeapAlloc) / 1024 / 1024
+
+	var reason string
+	switch {
+	case m.config.ProfileGoroutineThreshold > 0 && goroutines > m.config.ProfileGoroutineThreshold:
+		reason = fmt.Sprintf("goroutines_%d", goroutines)
+	case m.config.ProfileHeapThresholdMB > 0 && heapAllocMB > m.config.ProfileHeapThresholdMB:
+		reason = fmt.Sprintf("heap_%.0fmb", heapAllocMB)
+	default:
+		return
+	}
+
+	m.mutex.Lock()
+	if m.captureActive || time.Since(m.lastCapture) < profileCooldown {
+		m.mutex.Unlock()
+		return
+	}
+	m.captureActive = true
+	m.lastCapture = time.Now()
+	m.mutex.Unlock()
+
+	m.log.Warn("Runtime metrics crossed profile threshold, capturing profiles",
+		zap.String("reason", reason),
+		zap.Int("goroutines", goroutines),
+		zap.Float64("heap_alloc_mb", heapAllocMB))
+
+	// The CPU profile samples for several seconds; capture outside the
+	// collect loop so metric collection is not delayed
+	m.wg.Go(func() {
+		m.captureProfiles(reason)
+
+		m.mutex.Lock()
+		m.captureActive = false
+		m.mutex.Unlock()
+	})
+}
+
+// captureProfiles writes goroutine, heap and CPU profiles into a timestamped
+// capture directory and prunes old captures
+func (m *Manager) captureProfiles(reason string) {
+	capture := ProfileCapture{
+		Timestamp: time.Now(),
+		Reason:    reason,
+	}
+	capture.Directory = filepath.Join(m.config.ProfileCaptureDir,
+		fmt.Sprintf("%s_%s", capture.Timestamp.UTC().Format("20060102T150405Z"), reason))
+
+	if err := os.MkdirAll(capture.Directory, 0o755); err != nil {
+		m.log.Error("Failed to create profile capture directory", zap.Error(err))
+		return
+	}
+
+	if err := m.writeLookupProfile(capture.Directory, "goroutine"); err != nil {
+		m.log.Error("Failed to capture goroutine profile", zap.Error(err))
+	} else {
+		capture.Files = append(capture.Files, "goroutine.pprof")
+	}
+
+	if err := m.writeHeapProfile(capture.Directory); err != nil {
+		m.log.Error("Failed to capture heap profile", zap.Error(err))
+	} else {
+		capture.Files = append(capture.Files, "heap.pprof")
+	}
+
+	if err := m.writeCPUProfile(capture.Directory); err != nil {
+		m.log.Error("Failed to capture CPU profile", zap.Error(err))
+	} else {
+		capture.Files = append(capture.Files, "cpu.pprof")
+	}
+
+	m.mutex.Lock()
+	m.captures = append(m.captures, capture)
+	if len(m.captures) > maxProfileCaptures {
+		m.captures = m.captures[len(m.captures)-maxProfileCaptures:]
+	}
+	m.mutex.Unlock()
+
+	m.pruneCaptures()
+
+	m.log.Info("Profile capture completed",
+		zap.String("directory", capture.Directory),
+		zap.Strings("files", capture.Files))
+}
+
+// writeLookupProfile writes a named runtime profile
+func (m *Manager) writeLookupProfile(dir, name string) error {
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		return fmt.Errorf("unknown profile %s", name)
+	}
+
+	file, err := os.Create(filepath.Join(dir, name+".pprof"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return profile.WriteTo(file, 0)
+}
+
+// writeHeapProfile writes the heap profile
+func (m *Manager) writeHeapProfile(dir string) error {
+	file, err := os.Create(filepath.Join(dir, "heap.pprof"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return pprof.WriteHeapProfile(file)
+}
+
+// writeCPUProfile samples and writes the CPU profile
+func (m *Manager) writeCPUProfile(dir string) error {
+	file, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := pprof.StartCPUProfile(file); err != nil {
+		return err
+	}
+
+	select {
+	case <-m.ctx.Done():
+	case <-time.After(cpuProfileDuration):
+	}
+	pprof.StopCPUProfile()
+
+	return nil
+}
+
+// pruneCaptures removes the oldest capture directories beyond the retention
+// limit; the directory names sort chronologically
+func (m *Manager) pruneCaptures() {
+	entries, err := os.ReadDir(m.config.ProfileCaptureDir)
+	if err != nil {
+		m.log.Error("Failed to read profile capture directory", zap.Error(err))
+		return
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, entry.Name())
+		}
+	}
+	if len(dirs) <= maxProfileCaptures {
+		return
+	}
+
+	sort.Strings(dirs)
+	for _, dir := range dirs[:len(dirs)-maxProfileCaptures] {
+		if err := os.RemoveAll(filepath.Join(m.config.ProfileCaptureDir, dir)); err != nil {
+			m.log.Error("Failed to prune profile capture", zap.Error(err), zap.String("capture", dir))
+		}
+	}
+}
+
+// GetProfileCaptures returns the automatic profile captures of this run
+func (m *Manager) GetProfileCaptures() []ProfileCapture {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return append([]ProfileCapture(nil), m.captures...)
+}
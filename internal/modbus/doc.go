@@ -0,0 +1,99 @@
+package modbus
+
+import (
+	"powerkonnekt/ems/internal/registerdoc"
+)
+
+// RegisterDocumentation describes the register map served by the internal
+// Modbus server to SCADA/TSO, derived from the protocol constants
+func RegisterDocumentation() registerdoc.Device {
+	return registerdoc.Device{
+		Device:      "ems_server",
+		Description: "Internal EMS Modbus server (SCADA/TSO interface)",
+		Blocks: []registerdoc.Block{
+			{
+				Name:        "bms_telemetry",
+				Table:       "input",
+				StartAddr:   BMSBaseAddr,
+				Length:      BMSDataLength,
+				UnitOffset:  BMSDataOffset,
+				Direction:   registerdoc.DirectionRead,
+				Description: "Mirrored BMS telemetry, one block per BMS string",
+			},
+			{
+				Name:        "pcs_telemetry",
+				Table:       "input",
+				StartAddr:   PCSBaseAddr,
+				Length:      PCSDataLength,
+				UnitOffset:  PCSDataOffset,
+				Direction:   registerdoc.DirectionRead,
+				Description: "Mirrored PCS telemetry, one block per PCS",
+			},
+			{
+				Name:      "fcr_telemetry",
+				Table:     "input",
+				StartAddr: FCRBaseAddr,
+				Length:    FCRDataLength,
+				Direction: registerdoc.DirectionRead,
+				Points: []registerdoc.Point{
+					{Address: FCRBaseAddr + RegFCRFrequency, Name: "frequency", Unit: "mHz", Direction: registerdoc.DirectionRead},
+					{Address: FCRBaseAddr + RegFCRTargetPower, Name: "target_power", Unit: "kW", Direction: registerdoc.DirectionRead, Description: "Signed"},
+					{Address: FCRBaseAddr + RegFCRActivatedPower, Name: "activated_power", Unit: "kW", Direction: registerdoc.DirectionRead, Description: "Signed"},
+					{Address: FCRBaseAddr + RegFCRAvailableCapacity, Name: "available_capacity", Unit: "kW", Direction: registerdoc.DirectionRead},
+					{Address: FCRBaseAddr + RegFCRAvailability, Name: "availability", Unit: "per mille", Direction: registerdoc.DirectionRead},
+				},
+			},
+			{
+				Name:      "site_balance",
+				Table:     "input",
+				StartAddr: SiteBaseAddr,
+				Length:    SiteDataLength,
+				Direction: registerdoc.DirectionRead,
+				Points: []registerdoc.Point{
+					{Address: SiteBaseAddr + RegSiteNetPower, Name: "net_power", Unit: "kW", Direction: registerdoc.DirectionRead, Description: "Signed, positive = export"},
+					{Address: SiteBaseAddr + RegSiteBESSPower, Name: "bess_power", Unit: "kW", Direction: registerdoc.DirectionRead, Description: "Signed"},
+					{Address: SiteBaseAddr + RegSiteWindPower, Name: "wind_power", Unit: "kW", Direction: registerdoc.DirectionRead, Description: "Signed"},
+					{Address: SiteBaseAddr + RegSiteAuxPower, Name: "aux_power", Unit: "kW", Direction: registerdoc.DirectionRead},
+					{Address: SiteBaseAddr + RegSiteExportEnergyHi, Name: "export_energy_hi", Unit: "kWh", Direction: registerdoc.DirectionRead, Description: "High word"},
+					{Address: SiteBaseAddr + RegSiteExportEnergyLo, Name: "export_energy_lo", Unit: "kWh", Direction: registerdoc.DirectionRead, Description: "Low word"},
+					{Address: SiteBaseAddr + RegSiteImportEnergyHi, Name: "import_energy_hi", Unit: "kWh", Direction: registerdoc.DirectionRead, Description: "High word"},
+					{Address: SiteBaseAddr + RegSiteImportEnergyLo, Name: "import_energy_lo", Unit: "kWh", Direction: registerdoc.DirectionRead, Description: "Low word"},
+				},
+			},
+			{
+				Name:        "billing_meter",
+				Table:       "input",
+				StartAddr:   BillingBaseAddr,
+				Length:      BillingHeaderLength,
+				Direction:   registerdoc.DirectionRead,
+				Description: "Billing meter emulation for off-taker settlement: version and asset-count header, then one 12-register block per asset with daily, frozen-previous-day and total import/export energies as 32-bit kWh",
+				Points: []registerdoc.Point{
+					{Address: BillingBaseAddr + RegBillingVersion, Name: "map_version", Direction: registerdoc.DirectionRead},
+					{Address: BillingBaseAddr + RegBillingAssetCount, Name: "asset_count", Direction: registerdoc.DirectionRead},
+				},
+			},
+			{
+				Name:        "unit_commands",
+				Table:       "holding",
+				StartAddr:   CmdBaseAddr,
+				UnitOffset:  CmdOffset,
+				Direction:   registerdoc.DirectionWrite,
+				Description: "One command block per PCS",
+				Points: []registerdoc.Point{
+					{Address: CmdBaseAddr + RegStartStopCommand, Name: "start_stop", Direction: registerdoc.DirectionWrite, Description: "0 = stop, 1 = start"},
+					{Address: CmdBaseAddr + RegActivePowerCommand, Name: "active_power_command", Unit: "kW", Direction: registerdoc.DirectionWrite, Description: "Signed, positive = discharge"},
+					{Address: CmdBaseAddr + RegReactivePowerCommand, Name: "reactive_power_command", Unit: "kvar", Direction: registerdoc.DirectionWrite},
+				},
+			},
+			{
+				Name:      "site_commands",
+				Table:     "holding",
+				StartAddr: SiteCmdBaseAddr,
+				Direction: registerdoc.DirectionWrite,
+				Points: []registerdoc.Point{
+					{Address: SiteCmdBaseAddr + RegTSOEmergencyCommand, Name: "tso_emergency", Direction: registerdoc.DirectionWrite, Description: "TSO emergency site command"},
+				},
+			},
+		},
+	}
+}
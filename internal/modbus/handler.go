@@ -6,19 +6,26 @@ import (
 	"github.com/simonvetter/modbus"
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/afrr"
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/bms"
 	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/fcr"
 	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/site"
 )
 
 // RequestHandler implements the modbus.RequestHandler interface
 type RequestHandler struct {
-	bmsManager   *bms.Manager
-	pcsManager   *pcs.Manager
-	alarmManager *alarm.Manager
-	controlLogic *control.Logic
-	log          *zap.Logger
+	bmsManager     *bms.Manager
+	pcsManager     *pcs.Manager
+	alarmManager   *alarm.Manager
+	controlLogic   *control.Logic
+	fcrController  *fcr.Controller
+	fcrPlant       *fcr.PlantConditionTracker
+	afrrController *afrr.Controller
+	siteService    *site.Service
+	log            *zap.Logger
 
 	mutex     sync.RWMutex
 	registers *RegisterMap
@@ -30,6 +37,10 @@ func NewRequestHandler(
 	pcsManager *pcs.Manager,
 	alarmManager *alarm.Manager,
 	controlLogic *control.Logic,
+	fcrController *fcr.Controller,
+	fcrPlant *fcr.PlantConditionTracker,
+	afrrController *afrr.Controller,
+	siteService *site.Service,
 	logger *zap.Logger,
 ) *RequestHandler {
 	// Create handler-specific logger
@@ -38,12 +49,16 @@ func NewRequestHandler(
 	)
 
 	return &RequestHandler{
-		bmsManager:   bmsManager,
-		pcsManager:   pcsManager,
-		alarmManager: alarmManager,
-		controlLogic: controlLogic,
-		registers:    NewRegisterMap(),
-		log:          handlerLogger,
+		bmsManager:     bmsManager,
+		pcsManager:     pcsManager,
+		alarmManager:   alarmManager,
+		controlLogic:   controlLogic,
+		fcrController:  fcrController,
+		fcrPlant:       fcrPlant,
+		afrrController: afrrController,
+		siteService:    siteService,
+		registers:      NewRegisterMap(),
+		log:            handlerLogger,
 	}
 }
 
@@ -79,6 +94,12 @@ func (h *RequestHandler) HandleInputRegisters(req *modbus.InputRegistersRequest)
 	switch {
 	case req.Addr >= BMSBaseAddr && req.Addr < PCSBaseAddr:
 		return h.handleBMSInputRegisters(req.Addr, req.Quantity)
+	case req.Addr >= BillingBaseAddr:
+		return h.handleBillingInputRegisters(req.Addr, req.Quantity)
+	case req.Addr >= SiteBaseAddr:
+		return h.handleSiteInputRegisters(req.Addr, req.Quantity)
+	case req.Addr >= FCRBaseAddr:
+		return h.handleFCRInputRegisters(req.Addr, req.Quantity)
 	case req.Addr >= PCSBaseAddr:
 		return h.handlePCSInputRegisters(req.Addr, req.Quantity)
 	default:
@@ -150,6 +171,120 @@ func (h *RequestHandler) handlePCSInputRegisters(addr uint16, quantity uint16) (
 	return nil, modbus.ErrIllegalDataAddress
 }
 
+// handleFCRInputRegisters handles FCR telemetry register reads for the TSO
+func (h *RequestHandler) handleFCRInputRegisters(addr uint16, quantity uint16) ([]uint16, error) {
+	offset := addr - FCRBaseAddr
+	endOffset := offset + quantity
+
+	if offset >= FCRDataLength || endOffset > FCRDataLength {
+		h.log.Warn("Illegal FCR telemetry address requested",
+			zap.Uint16("address", addr))
+		return nil, modbus.ErrIllegalDataAddress
+	}
+
+	frequency := h.fcrController.GetLastFrequency()
+	activation := h.fcrController.GetActivationState()
+	plant := h.fcrPlant.GetState()
+
+	result := make([]uint16, quantity)
+
+	setReg := func(idx uint16, val uint16) {
+		if idx >= offset && idx < endOffset {
+			result[idx-offset] = val
+		}
+	}
+
+	setReg(RegFCRFrequency, uint16(frequency.Frequency*1000))
+	setReg(RegFCRTargetPower, uint16(int16(activation.TargetPowerKW)))
+	setReg(RegFCRActivatedPower, uint16(int16(activation.ActivatedPowerKW)))
+	setReg(RegFCRAvailableCapacity, uint16(plant.AvailableCapacityKW))
+	setReg(RegFCRAvailability, uint16(plant.AvailabilityRatio*1000))
+
+	return result, nil
+}
+
+// handleSiteInputRegisters handles reads of the site balance telemetry
+func (h *RequestHandler) handleSiteInputRegisters(addr uint16, quantity uint16) ([]uint16, error) {
+	offset := addr - SiteBaseAddr
+	endOffset := offset + quantity
+
+	if offset >= SiteDataLength || endOffset > SiteDataLength {
+		h.log.Warn("Illegal site balance address requested",
+			zap.Uint16("address", addr))
+		return nil, modbus.ErrIllegalDataAddress
+	}
+
+	balance := h.siteService.GetBalance()
+	exportKWh := uint32(balance.ExportEnergyKWh)
+	importKWh := uint32(balance.ImportEnergyKWh)
+
+	result := make([]uint16, quantity)
+
+	setReg := func(idx uint16, val uint16) {
+		if idx >= offset && idx < endOffset {
+			result[idx-offset] = val
+		}
+	}
+
+	setReg(RegSiteNetPower, uint16(int16(balance.NetPowerKW)))
+	setReg(RegSiteBESSPower, uint16(int16(balance.BESSPowerKW)))
+	setReg(RegSiteWindPower, uint16(int16(balance.WindPowerKW)))
+	setReg(RegSiteAuxPower, uint16(int16(balance.AuxPowerKW)))
+	setReg(RegSiteExportEnergyHi, uint16(exportKWh>>16))
+	setReg(RegSiteExportEnergyLo, uint16(exportKWh))
+	setReg(RegSiteImportEnergyHi, uint16(importKWh>>16))
+	setReg(RegSiteImportEnergyLo, uint16(importKWh))
+
+	return result, nil
+}
+
+// handleBillingInputRegisters handles reads of the billing meter emulation
+// block: settlement-relevant import/export energies per asset, with the
+// previous day frozen at rollover following meter conventions
+func (h *RequestHandler) handleBillingInputRegisters(addr uint16, quantity uint16) ([]uint16, error) {
+	assets := len(h.pcsManager.GetAllServices())
+	length := uint16(BillingHeaderLength + assets*BillingAssetLength)
+
+	offset := addr - BillingBaseAddr
+	endOffset := offset + quantity
+
+	if offset >= length || endOffset > length {
+		h.log.Warn("Illegal billing meter address requested",
+			zap.Uint16("address", addr))
+		return nil, modbus.ErrIllegalDataAddress
+	}
+
+	result := make([]uint16, quantity)
+
+	setReg := func(idx uint16, val uint16) {
+		if idx >= offset && idx < endOffset {
+			result[idx-offset] = val
+		}
+	}
+
+	setReg(RegBillingVersion, BillingMapVersion)
+	setReg(RegBillingAssetCount, uint16(assets))
+
+	for assetNo := 1; assetNo <= assets; assetNo++ {
+		service, err := h.pcsManager.GetService(assetNo)
+		if err != nil {
+			continue
+		}
+
+		counter := service.GetLatestPCSCounterData()
+		base := uint16(BillingHeaderLength + (assetNo-1)*BillingAssetLength)
+
+		set32BitReg(setReg, base+RegBillingExportDayHi, counter.ActiveEnergyToday)
+		set32BitReg(setReg, base+RegBillingImportDayHi, counter.ConsumedEnergyToday)
+		set32BitReg(setReg, base+RegBillingExportFrozenHi, counter.ActiveEnergyYesterday)
+		set32BitReg(setReg, base+RegBillingImportFrozenHi, service.GetConsumedEnergyYesterday())
+		set32BitReg(setReg, base+RegBillingExportTotalHi, counter.ActiveEnergyTotal)
+		set32BitReg(setReg, base+RegBillingImportTotalHi, counter.ConsumedEnergyTotal)
+	}
+
+	return result, nil
+}
+
 // handleHoldingRegistersRead handles holding register read requests
 func (h *RequestHandler) handleHoldingRegistersRead(req *modbus.HoldingRegistersRequest) ([]uint16, error) {
 	addr := req.Addr
@@ -167,6 +302,11 @@ func (h *RequestHandler) handleHoldingRegistersRead(req *modbus.HoldingRegisters
 		return nil, modbus.ErrIllegalDataAddress
 	}
 
+	// Site-wide command registers
+	if addr >= SiteCmdBaseAddr {
+		return h.handleSiteCommandsRead(addr, quantity)
+	}
+
 	pcsNo := uint8((addr-CmdBaseAddr)/CmdOffset) + 1
 	cmdOffset := (addr - CmdBaseAddr) % CmdOffset
 
@@ -230,6 +370,11 @@ func (h *RequestHandler) handleHoldingRegistersWrite(req *modbus.HoldingRegister
 		return nil, modbus.ErrIllegalDataAddress
 	}
 
+	// Site-wide command registers
+	if addr >= SiteCmdBaseAddr {
+		return h.handleSiteCommandsWrite(addr, values)
+	}
+
 	pcsNo := uint8((addr-CmdBaseAddr)/CmdOffset) + 1
 	cmdOffset := (addr - CmdBaseAddr) % CmdOffset
 
@@ -281,6 +426,15 @@ func (h *RequestHandler) handleHoldingRegistersWrite(req *modbus.HoldingRegister
 			zap.Uint8("pcs_no", pcsNo),
 			zap.Float32("power", power))
 
+		// Arbitrate against other setpoint sources and enforce site limits
+		power, err = h.controlLogic.ArbitratePCSPowerCommand(int(pcsNo), control.SourceSCADA, power)
+		if err != nil {
+			h.log.Warn("Modbus active power command rejected by arbitration",
+				zap.Uint8("pcs_no", pcsNo),
+				zap.Error(err))
+			return nil, modbus.ErrServerDeviceFailure
+		}
+
 		if err := service.SetActivePowerCommand(power); err != nil {
 			h.log.Error("Failed to execute Modbus active power command",
 				zap.Uint8("pcs_no", pcsNo),
@@ -329,6 +483,62 @@ func (h *RequestHandler) handleHoldingRegistersWrite(req *modbus.HoldingRegister
 	}
 }
 
+// handleSiteCommandsRead handles reads of the site-wide command registers
+func (h *RequestHandler) handleSiteCommandsRead(addr uint16, quantity uint16) ([]uint16, error) {
+	result := make([]uint16, quantity)
+
+	for i := range quantity {
+		switch addr - SiteCmdBaseAddr + i {
+		case RegTSOEmergencyCommand:
+			if h.controlLogic.GetTSOEmergency() {
+				result[i] = 1
+			}
+		case RegAFRRSetpointCommand:
+			result[i] = uint16(int16(h.afrrController.GetState().SetpointKW))
+		default:
+			h.log.Warn("Read attempt from unsupported site command register",
+				zap.Uint16("address", addr+i))
+			return nil, modbus.ErrIllegalDataAddress
+		}
+	}
+
+	return result, nil
+}
+
+// handleSiteCommandsWrite handles writes to the site-wide command registers
+func (h *RequestHandler) handleSiteCommandsWrite(addr uint16, values []uint16) ([]uint16, error) {
+	if len(values) < 1 {
+		return nil, modbus.ErrIllegalDataValue
+	}
+
+	switch addr - SiteCmdBaseAddr {
+	case RegTSOEmergencyCommand:
+		active := values[0] != 0
+
+		h.log.Warn("TSO emergency command received via Modbus",
+			zap.Bool("active", active))
+
+		h.controlLogic.SetTSOEmergency(active)
+		return values, nil
+
+	case RegAFRRSetpointCommand:
+		setpoint := float32(int16(values[0]))
+
+		if err := h.afrrController.SetSetpoint(setpoint, "SCADA"); err != nil {
+			h.log.Warn("aFRR setpoint rejected",
+				zap.Float32("setpoint_kw", setpoint),
+				zap.Error(err))
+			return nil, modbus.ErrIllegalDataValue
+		}
+		return values, nil
+
+	default:
+		h.log.Warn("Write attempt to unsupported site command register",
+			zap.Uint16("address", addr))
+		return nil, modbus.ErrIllegalDataAddress
+	}
+}
+
 // getBMSNumberFromServerAddress calculates BMS number from server Modbus address
 func (h *RequestHandler) getBMSNumberFromServerAddress(addr uint16) uint8 {
 	if addr < BMSBaseAddr {
@@ -6,11 +6,14 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/afrr"
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/bms"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/fcr"
 	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/site"
 )
 
 // Module provides Modbus server functionality to the Fx application
@@ -26,9 +29,13 @@ func ProvideServer(
 	pcsManager *pcs.Manager,
 	alarmManager *alarm.Manager,
 	controlLogic *control.Logic,
+	fcrController *fcr.Controller,
+	fcrPlant *fcr.PlantConditionTracker,
+	afrrController *afrr.Controller,
+	siteService *site.Service,
 	logger *zap.Logger,
 ) (*Server, error) {
-	return NewServer(cfg.ModbusServer, bmsManager, pcsManager, alarmManager, controlLogic, logger)
+	return NewServer(cfg.ModbusServer, bmsManager, pcsManager, alarmManager, controlLogic, fcrController, fcrPlant, afrrController, siteService, logger)
 }
 
 // RegisterLifecycle registers the Modbus server lifecycle hooks with Fx
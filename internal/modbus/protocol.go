@@ -14,10 +14,67 @@ const (
 	PCSDataStartOffset = 0
 	PCSDataLength      = 68
 
+	// FCR Telemetry Data
+	FCRBaseAddr   = 9000
+	FCRDataLength = 5
+
+	// FCR telemetry register offsets
+	RegFCRFrequency         = 0 // mHz
+	RegFCRTargetPower       = 1 // kW, signed
+	RegFCRActivatedPower    = 2 // kW, signed
+	RegFCRAvailableCapacity = 3 // kW
+	RegFCRAvailability      = 4 // per mille
+
 	// Control Command Registers
 	CmdBaseAddr             = 1000
 	CmdOffset               = 100
 	RegStartStopCommand     = 0
 	RegActivePowerCommand   = 1
 	RegReactivePowerCommand = 2
+
+	// Site Command Registers (TSO/SCADA site-wide commands)
+	SiteCmdBaseAddr        = 9100
+	RegTSOEmergencyCommand = 0
+	RegAFRRSetpointCommand = 1 // kW, signed, positive = upward activation
+
+	// Site Balance Telemetry (computed site virtual device)
+	SiteBaseAddr   = 9200
+	SiteDataLength = 8
+
+	// Site balance register offsets
+	RegSiteNetPower       = 0 // kW, signed, positive = export
+	RegSiteBESSPower      = 1 // kW, signed
+	RegSiteWindPower      = 2 // kW, signed
+	RegSiteAuxPower       = 3 // kW
+	RegSiteExportEnergyHi = 4 // kWh, high word
+	RegSiteExportEnergyLo = 5 // kWh, low word
+	RegSiteImportEnergyHi = 6 // kWh, high word
+	RegSiteImportEnergyLo = 7 // kWh, low word
+
+	// Billing Meter Emulation (read-only settlement energies for the
+	// off-taker, versioned so readers can detect layout changes)
+	BillingBaseAddr     = 9300
+	BillingMapVersion   = 1
+	BillingHeaderLength = 2
+	BillingAssetLength  = 12
+
+	// Billing header register offsets
+	RegBillingVersion    = 0 // Register map version
+	RegBillingAssetCount = 1 // Number of per-asset blocks that follow
+
+	// Billing per-asset register offsets, relative to the asset block.
+	// Day counters roll over with the PCS internal day counter; the frozen
+	// registers latch the pre-rollover value of the previous day.
+	RegBillingExportDayHi    = 0  // kWh, high word
+	RegBillingExportDayLo    = 1  // kWh, low word
+	RegBillingImportDayHi    = 2  // kWh, high word
+	RegBillingImportDayLo    = 3  // kWh, low word
+	RegBillingExportFrozenHi = 4  // kWh, high word
+	RegBillingExportFrozenLo = 5  // kWh, low word
+	RegBillingImportFrozenHi = 6  // kWh, high word
+	RegBillingImportFrozenLo = 7  // kWh, low word
+	RegBillingExportTotalHi  = 8  // kWh, high word
+	RegBillingExportTotalLo  = 9  // kWh, low word
+	RegBillingImportTotalHi  = 10 // kWh, high word
+	RegBillingImportTotalLo  = 11 // kWh, low word
 )
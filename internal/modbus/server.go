@@ -2,17 +2,23 @@ package modbus
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
 	"sync"
 
 	"github.com/simonvetter/modbus"
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/afrr"
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/bms"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/fcr"
 	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/site"
 )
 
 // Server represents the Modbus TCP server
@@ -36,6 +42,10 @@ func NewServer(
 	pcsManager *pcs.Manager,
 	alarmManager *alarm.Manager,
 	controlLogic *control.Logic,
+	fcrController *fcr.Controller,
+	fcrPlant *fcr.PlantConditionTracker,
+	afrrController *afrr.Controller,
+	siteService *site.Service,
 	logger *zap.Logger,
 ) (*Server, error) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -49,7 +59,7 @@ func NewServer(
 	)
 
 	// Create request handler
-	handler := NewRequestHandler(bmsManager, pcsManager, alarmManager, controlLogic, logger)
+	handler := NewRequestHandler(bmsManager, pcsManager, alarmManager, controlLogic, fcrController, fcrPlant, afrrController, siteService, logger)
 
 	// Create server configuration
 	serverConfig := &modbus.ServerConfiguration{
@@ -58,6 +68,30 @@ func NewServer(
 		MaxClients: cfg.MaxClients,
 	}
 
+	// Modbus/TCP Security: serve over mutually authenticated TLS
+	if cfg.TLS.Enabled {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to load server TLS key pair: %w", err)
+		}
+
+		caPEM, err := os.ReadFile(cfg.TLS.CAFile)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to read client CA certificate: %w", err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caPEM) {
+			cancel()
+			return nil, fmt.Errorf("no certificates found in CA file %s", cfg.TLS.CAFile)
+		}
+
+		serverConfig.URL = fmt.Sprintf("tcp+tls://%s:%d", cfg.Host, cfg.Port)
+		serverConfig.TLSServerCert = &certificate
+		serverConfig.TLSClientCAs = caPool
+	}
+
 	serverLogger.Info("Creating Modbus TCP server",
 		zap.String("url", serverConfig.URL),
 		zap.Duration("timeout", cfg.Timeout))
@@ -0,0 +1,47 @@
+package mqtt
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/fcr"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// Module provides the MQTT telemetry publisher to the Fx application
+var Module = fx.Module("mqtt",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides an MQTT publisher service instance
+func ProvideService(
+	cfg *config.Config,
+	bmsManager *bms.Manager,
+	pcsManager *pcs.Manager,
+	windFarmManager *windfarm.Manager,
+	alarmManager *alarm.Manager,
+	fcrController *fcr.Controller,
+	logger *zap.Logger,
+) *Service {
+	return NewService(cfg.MQTT, bmsManager, pcsManager, windFarmManager, alarmManager, fcrController, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the MQTT publisher
+func RegisterLifecycle(lc fx.Lifecycle, service *Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,209 @@
+// Package mqtt pushes telemetry to an external MQTT broker: periodic
+// BMS/PCS/wind farm snapshots, the active alarm list and the
+// frequency-reserve state, each as JSON on its own topic below the
+// configured base topic.
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	pahomqtt "github.com/eclipse/paho.mqtt.golang"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/fcr"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// publishTimeout bounds how long one publish may block the loop when the
+// broker accepts the connection but stops reading
+const publishTimeout = 5 * time.Second
+
+// Service publishes telemetry snapshots to the configured MQTT broker
+type Service struct {
+	config          config.MQTTConfig
+	bmsManager      *bms.Manager
+	pcsManager      *pcs.Manager
+	windFarmManager *windfarm.Manager
+	alarmManager    *alarm.Manager
+	fcrController   *fcr.Controller
+	client          pahomqtt.Client
+	ctx             context.Context
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	log             *zap.Logger
+}
+
+// NewService creates a new MQTT publisher service
+func NewService(cfg config.MQTTConfig, bmsManager *bms.Manager, pcsManager *pcs.Manager, windFarmManager *windfarm.Manager, alarmManager *alarm.Manager, fcrController *fcr.Controller, logger *zap.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Service{
+		config:          cfg,
+		bmsManager:      bmsManager,
+		pcsManager:      pcsManager,
+		windFarmManager: windFarmManager,
+		alarmManager:    alarmManager,
+		fcrController:   fcrController,
+		ctx:             ctx,
+		cancel:          cancel,
+		log: logger.With(
+			zap.String("service", "mqtt"),
+		),
+	}
+}
+
+// Start connects to the broker and begins publishing. The connection is
+// retried in the background, so an unreachable broker does not block the
+// boot or stop the plant.
+func (s *Service) Start() error {
+	if !s.config.Enabled {
+		s.log.Info("MQTT telemetry publisher disabled")
+		return nil
+	}
+
+	options := pahomqtt.NewClientOptions().
+		AddBroker(s.config.BrokerURL).
+		SetClientID(s.config.ClientID).
+		SetConnectTimeout(s.config.ConnectTimeout).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(s.config.ReconnectDelay).
+		SetMaxReconnectInterval(s.config.ReconnectDelay)
+	if s.config.Username != "" {
+		options.SetUsername(s.config.Username)
+		options.SetPassword(s.config.Password)
+	}
+	if s.config.TLS.Enabled {
+		tlsConfig, err := buildTLSConfig(s.config.TLS)
+		if err != nil {
+			return fmt.Errorf("failed to build MQTT TLS configuration: %w", err)
+		}
+		options.SetTLSConfig(tlsConfig)
+	}
+	options.SetOnConnectHandler(func(pahomqtt.Client) {
+		s.log.Info("Connected to MQTT broker", zap.String("broker", s.config.BrokerURL))
+	})
+	options.SetConnectionLostHandler(func(_ pahomqtt.Client, err error) {
+		s.log.Warn("MQTT broker connection lost", zap.Error(err))
+	})
+
+	s.client = pahomqtt.NewClient(options)
+	s.client.Connect()
+
+	s.wg.Go(s.publishLoop)
+
+	s.log.Info("MQTT telemetry publisher started",
+		zap.String("broker", s.config.BrokerURL),
+		zap.String("base_topic", s.config.BaseTopic),
+		zap.Int("qos", s.config.QoS),
+		zap.Duration("publish_interval", s.config.PublishInterval))
+	return nil
+}
+
+// Stop disconnects from the broker
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	if s.client != nil {
+		s.client.Disconnect(uint(publishTimeout.Milliseconds()))
+	}
+	s.log.Info("MQTT telemetry publisher stopped")
+}
+
+// publishLoop pushes a full snapshot set on every publish interval
+func (s *Service) publishLoop() {
+	ticker := time.NewTicker(s.config.PublishInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.publishSnapshots()
+		}
+	}
+}
+
+// publishSnapshots publishes the device snapshots, the active alarm list
+// and the frequency-reserve state. A disconnected broker skips the cycle;
+// paho reconnects in the background.
+func (s *Service) publishSnapshots() {
+	if !s.client.IsConnectionOpen() {
+		return
+	}
+
+	for id, data := range s.bmsManager.GetAggregatedData() {
+		s.publish(fmt.Sprintf("%s/bms/%d", s.config.BaseTopic, id), data)
+	}
+	for id, service := range s.pcsManager.GetAllServices() {
+		s.publish(fmt.Sprintf("%s/pcs/%d", s.config.BaseTopic, id), service.GetLatestPCSData())
+	}
+	for id, data := range s.windFarmManager.GetAggregatedData() {
+		s.publish(fmt.Sprintf("%s/windfarm/%d", s.config.BaseTopic, id), data)
+	}
+
+	s.publish(s.config.BaseTopic+"/alarms", s.alarmManager.GetActiveAlarms())
+	s.publish(s.config.BaseTopic+"/fcr", map[string]any{
+		"frequency":  s.fcrController.GetLastFrequency(),
+		"activation": s.fcrController.GetActivationState(),
+	})
+}
+
+// publish marshals one payload and publishes it with the configured QoS
+func (s *Service) publish(topic string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		s.log.Error("Failed to marshal MQTT payload",
+			zap.String("topic", topic),
+			zap.Error(err))
+		return
+	}
+
+	token := s.client.Publish(topic, byte(s.config.QoS), false, data)
+	if !token.WaitTimeout(publishTimeout) {
+		s.log.Warn("MQTT publish timed out", zap.String("topic", topic))
+		return
+	}
+	if err := token.Error(); err != nil {
+		s.log.Warn("MQTT publish failed",
+			zap.String("topic", topic),
+			zap.Error(err))
+	}
+}
+
+// buildTLSConfig loads the certificate material and assembles the TLS
+// configuration for the broker connection
+func buildTLSConfig(settings config.TLSConfig) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(settings.CertFile, settings.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	caPEM, err := os.ReadFile(settings.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", settings.CAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      caPool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
@@ -8,6 +8,18 @@ import (
 	"powerkonnekt/ems/internal/database"
 )
 
+// antiIslandAlarmCode is the Power Electronics alarm bit for an active
+// anti-islanding trip ("Active Anti-island")
+const antiIslandAlarmCode = 11
+
+// AntiIslandActive reports whether the anti-islanding protection alarm is
+// currently asserted by the PCS
+func (s *Service) AntiIslandActive() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.previousAlarmStates[fmt.Sprintf("PCS_%d_%d", s.config.ID, antiIslandAlarmCode)]
+}
+
 // processFaults processes fault bits from the given data
 func (s *Service) processFaults(data []byte) {
 	timestamp := time.Now()
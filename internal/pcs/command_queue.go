@@ -0,0 +1,193 @@
+package pcs
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Command queue statuses
+const (
+	CommandStatusQueued     = "QUEUED"
+	CommandStatusRunning    = "RUNNING"
+	CommandStatusSucceeded  = "SUCCEEDED"
+	CommandStatusFailed     = "FAILED"
+	CommandStatusSuperseded = "SUPERSEDED"
+)
+
+// Command kinds
+const (
+	CommandKindStartStop     = "START_STOP"
+	CommandKindActivePower   = "ACTIVE_POWER"
+	CommandKindReactivePower = "REACTIVE_POWER"
+)
+
+// Built-in command queue defaults (used when no config value is set)
+const (
+	defaultCommandRetries    = 3
+	defaultCommandRetryDelay = 500 * time.Millisecond
+	commandQueueSize         = 32
+	commandStatusRetention   = 256
+)
+
+// QueuedCommand represents a command processed through the per-device queue
+type QueuedCommand struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	Value       float32   `json:"value"`
+	Start       bool      `json:"start"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	Attempts    int       `json:"attempts"`
+	EnqueuedAt  time.Time `json:"enqueued_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// EnqueueCommand puts a command on the per-device queue and returns its ID.
+// Queued setpoint commands of the same kind are superseded by the new one;
+// start/stop commands are never deduplicated and execute strictly in order.
+func (s *Service) EnqueueCommand(kind string, value float32, start bool) (string, error) {
+	cmd := &QueuedCommand{
+		Kind:       kind,
+		Value:      value,
+		Start:      start,
+		Status:     CommandStatusQueued,
+		EnqueuedAt: time.Now(),
+	}
+
+	s.mutex.Lock()
+	s.commandCounter++
+	cmd.ID = fmt.Sprintf("%d-%d", s.config.ID, s.commandCounter)
+
+	// Deduplicate superseded setpoints of the same kind
+	if kind != CommandKindStartStop {
+		if prev := s.pendingSetpoints[kind]; prev != nil && prev.Status == CommandStatusQueued {
+			prev.Status = CommandStatusSuperseded
+			prev.CompletedAt = time.Now()
+		}
+		s.pendingSetpoints[kind] = cmd
+	}
+
+	s.commandStatuses[cmd.ID] = cmd
+	s.commandOrder = append(s.commandOrder, cmd.ID)
+	for len(s.commandOrder) > commandStatusRetention {
+		delete(s.commandStatuses, s.commandOrder[0])
+		s.commandOrder = s.commandOrder[1:]
+	}
+	s.mutex.Unlock()
+
+	select {
+	case s.commandQueue <- cmd:
+		return cmd.ID, nil
+	default:
+		s.mutex.Lock()
+		cmd.Status = CommandStatusFailed
+		cmd.Error = "command queue full"
+		cmd.CompletedAt = time.Now()
+		s.mutex.Unlock()
+		return "", fmt.Errorf("command queue full for PCS %d", s.config.ID)
+	}
+}
+
+// GetQueuedCommand returns the status of a queued command by ID
+func (s *Service) GetQueuedCommand(id string) (QueuedCommand, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	cmd, exists := s.commandStatuses[id]
+	if !exists {
+		return QueuedCommand{}, fmt.Errorf("command %s not found", id)
+	}
+	return *cmd, nil
+}
+
+// commandQueueLoop executes queued commands in order with retries on
+// transient Modbus errors
+func (s *Service) commandQueueLoop() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case cmd := <-s.commandQueue:
+			s.mutex.Lock()
+			if cmd.Status != CommandStatusQueued {
+				// Superseded while waiting in the queue
+				s.mutex.Unlock()
+				continue
+			}
+			cmd.Status = CommandStatusRunning
+			s.mutex.Unlock()
+
+			s.executeQueuedCommand(cmd)
+		}
+	}
+}
+
+// executeQueuedCommand runs one command with the configured retry behaviour
+func (s *Service) executeQueuedCommand(cmd *QueuedCommand) {
+	retries := s.config.CommandRetries
+	if retries <= 0 {
+		retries = defaultCommandRetries
+	}
+	retryDelay := s.config.CommandRetryDelay
+	if retryDelay <= 0 {
+		retryDelay = defaultCommandRetryDelay
+	}
+
+	var err error
+	for attempt := 1; attempt <= retries; attempt++ {
+		s.mutex.Lock()
+		cmd.Attempts = attempt
+		s.mutex.Unlock()
+
+		if err = s.executeCommand(cmd); err == nil {
+			break
+		}
+
+		s.log.Warn("Queued command attempt failed",
+			zap.String("command_id", cmd.ID),
+			zap.String("kind", cmd.Kind),
+			zap.Int("attempt", attempt),
+			zap.Error(err))
+
+		if attempt < retries {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+		}
+	}
+
+	s.mutex.Lock()
+	cmd.CompletedAt = time.Now()
+	if err != nil {
+		cmd.Status = CommandStatusFailed
+		cmd.Error = err.Error()
+	} else {
+		cmd.Status = CommandStatusSucceeded
+	}
+	s.mutex.Unlock()
+
+	if err != nil {
+		s.log.Error("Queued command failed after retries",
+			zap.String("command_id", cmd.ID),
+			zap.String("kind", cmd.Kind),
+			zap.Error(err))
+	}
+}
+
+// executeCommand dispatches a queued command to the underlying device command
+func (s *Service) executeCommand(cmd *QueuedCommand) error {
+	switch cmd.Kind {
+	case CommandKindStartStop:
+		return s.StartStopCommand(cmd.Start)
+	case CommandKindActivePower:
+		return s.SetActivePowerCommand(cmd.Value)
+	case CommandKindReactivePower:
+		return s.SetReactivePowerCommand(cmd.Value)
+	default:
+		return fmt.Errorf("unknown command kind: %s", cmd.Kind)
+	}
+}
@@ -96,6 +96,8 @@ func (s *Service) StartStopCommand(start bool) error {
 
 // SetActivePowerCommand sets the active power (kW)
 func (s *Service) SetActivePowerCommand(power float32) error {
+	receivedAt := time.Now()
+
 	if !s.client.IsConnected() {
 		return fmt.Errorf("PCS not connected")
 	}
@@ -112,9 +114,12 @@ func (s *Service) SetActivePowerCommand(power float32) error {
 		return fmt.Errorf("failed to write active power command: %w", err)
 	}
 
+	writtenAt := time.Now()
+
 	s.mutex.Lock()
 	s.commandState.ActivePowerCommand = power
-	s.commandState.LastUpdated = time.Now()
+	s.commandState.LastUpdated = writtenAt
+	s.beginCommandTrace(power, receivedAt, writtenAt)
 	s.mutex.Unlock()
 
 	s.log.Info("PCS active power command set", zap.Float32("power", power))
@@ -0,0 +1,93 @@
+package pcs
+
+import (
+	"powerkonnekt/ems/internal/registerdoc"
+)
+
+// RegisterDocumentation describes the Power Electronics register map polled
+// by the PCS service, derived from the protocol constants
+func RegisterDocumentation() registerdoc.Device {
+	return registerdoc.Device{
+		Device:      "pcs",
+		Description: "Power Electronics power conversion system",
+		Blocks: []registerdoc.Block{
+			{
+				Name:      "status",
+				Table:     "holding",
+				StartAddr: StatusDataStartAddr,
+				Length:    StatusDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "equipment",
+				Table:     "holding",
+				StartAddr: EquipmentDataStartAddr,
+				Length:    EquipmentDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "environment",
+				Table:     "holding",
+				StartAddr: EnvironmentDataStartAddr,
+				Length:    EnvironmentDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:        "dc_source",
+				Table:       "holding",
+				StartAddr:   DCSourceDataStartAddr,
+				Length:      DCSourceDataLength,
+				Direction:   registerdoc.DirectionRead,
+				Description: "Power (kW) and current (A) of the four DC busbars",
+			},
+			{
+				Name:      "grid",
+				Table:     "holding",
+				StartAddr: GridDataStartAddr,
+				Length:    GridDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "counters",
+				Table:     "holding",
+				StartAddr: CounterDataStartAddr,
+				Length:    CounterDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "faults",
+				Table:     "holding",
+				StartAddr: FaultDataStartAddr,
+				Length:    FaultDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "warnings",
+				Table:     "holding",
+				StartAddr: WarningDataStartAddr,
+				Length:    WarningDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "pcs_status",
+				Table:     "holding",
+				StartAddr: PCSStatusDataStartAddr,
+				Length:    PCSStatusDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "control",
+				Table:     "holding",
+				StartAddr: CmdStartStopRegister,
+				Direction: registerdoc.DirectionWrite,
+				Points: []registerdoc.Point{
+					{Address: CmdStartStopRegister, Name: "start_stop", Direction: registerdoc.DirectionWrite, Description: "0 = stop, 1 = start"},
+					{Address: SystemResetRegister, Name: "system_reset", Direction: registerdoc.DirectionWrite, Description: "1 = reset"},
+					{Address: CmdActivePowerRegister, Name: "active_power_command", Unit: "kW", Direction: registerdoc.DirectionWrite, Description: "Signed, positive = discharge"},
+					{Address: CmdReactivePowerRegister, Name: "reactive_power_command", Unit: "kvar", Direction: registerdoc.DirectionWrite},
+					{Address: HeartbeatRegister, Name: "heartbeat", Direction: registerdoc.DirectionWrite, Description: "Incrementing EMS heartbeat counter"},
+				},
+			},
+		},
+	}
+}
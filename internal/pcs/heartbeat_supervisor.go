@@ -0,0 +1,119 @@
+package pcs
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// Heartbeat supervision defaults used when no thresholds are configured
+const (
+	DefaultHeartbeatStaleTimeout = 15 * time.Second
+	DefaultHeartbeatLossTimeout  = 60 * time.Second
+)
+
+// HeartbeatStallAlarmCode is a synthetic alarm code raised by the EMS itself
+// when the device stops delivering fresh status data. It is outside the
+// register-based alarm code range so it cannot collide with device alarms.
+const HeartbeatStallAlarmCode = 9001
+
+// heartbeatSupervisionLoop verifies that the PCS keeps answering status polls.
+// The PCS does not expose a readable heartbeat counter, so progression of the
+// status data timestamp is supervised instead. A prolonged freeze is reported
+// as heartbeat loss which the control logic uses as a safe-state trigger.
+func (s *Service) heartbeatSupervisionLoop() {
+	staleTimeout := s.config.HeartbeatStaleTimeout
+	if staleTimeout <= 0 {
+		staleTimeout = DefaultHeartbeatStaleTimeout
+	}
+	lossTimeout := s.config.HeartbeatLossTimeout
+	if lossTimeout <= 0 {
+		lossTimeout = DefaultHeartbeatLossTimeout
+	}
+
+	s.mutex.Lock()
+	s.lastHeartbeatProgress = time.Now()
+	s.mutex.Unlock()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.superviseHeartbeat(staleTimeout, lossTimeout)
+		}
+	}
+}
+
+// superviseHeartbeat samples the status data timestamp and updates the
+// stale/loss state
+func (s *Service) superviseHeartbeat(staleTimeout, lossTimeout time.Duration) {
+	now := time.Now()
+	connected := s.client.IsConnected()
+
+	s.mutex.Lock()
+	statusTimestamp := s.lastStatusData.Timestamp
+	if !statusTimestamp.Equal(s.lastStatusTimestamp) {
+		s.lastStatusTimestamp = statusTimestamp
+		s.lastHeartbeatProgress = now
+	}
+	sinceProgress := now.Sub(s.lastHeartbeatProgress)
+
+	wasStale := s.heartbeatStale
+	wasLost := s.heartbeatLost
+	s.heartbeatStale = connected && sinceProgress >= staleTimeout
+	s.heartbeatLost = connected && sinceProgress >= lossTimeout
+	stale := s.heartbeatStale
+	lost := s.heartbeatLost
+	s.mutex.Unlock()
+
+	if stale != wasStale {
+		s.submitHeartbeatAlarm(stale)
+
+		if stale {
+			s.log.Warn("PCS status data not progressing",
+				zap.Duration("since_progress", sinceProgress))
+		} else {
+			s.log.Info("PCS status data progressing again")
+		}
+	}
+
+	if lost && !wasLost {
+		s.log.Error("PCS heartbeat lost - safe-state stop will be triggered",
+			zap.Duration("since_progress", sinceProgress),
+			zap.Duration("loss_timeout", lossTimeout))
+	}
+}
+
+// submitHeartbeatAlarm raises or clears the heartbeat stall alarm
+func (s *Service) submitHeartbeatAlarm(active bool) {
+	s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: fmt.Sprintf("PCS_%d", s.config.ID),
+		AlarmCode: HeartbeatStallAlarmCode,
+		Message:   "PCS status data not progressing",
+		Severity:  "HIGH",
+		Active:    active,
+	})
+}
+
+// IsHeartbeatStale returns whether the device status data is stale
+func (s *Service) IsHeartbeatStale() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.heartbeatStale
+}
+
+// IsHeartbeatLost returns whether the device heartbeat has been lost long
+// enough to require a safe-state reaction
+func (s *Service) IsHeartbeatLost() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.heartbeatLost
+}
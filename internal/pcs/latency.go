@@ -0,0 +1,189 @@
+package pcs
+
+import (
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// Command latency tracing for the response-time SLA: every active power
+// command is traced from its receipt through the confirmed Modbus write to
+// the first telemetry sample showing the commanded power at the grid
+// connection.
+const (
+	// commandTraceLimit bounds the completed traces kept per PCS
+	commandTraceLimit = 100
+
+	// effectToleranceKW is how close the measured grid power must come to
+	// the commanded power before the effect counts as visible
+	effectToleranceKW = 10.0
+
+	// effectTimeout finalizes a trace without an observed effect when the
+	// telemetry never reaches the commanded power
+	effectTimeout = 30 * time.Second
+)
+
+// CommandTrace records the end-to-end latency of one active power command
+type CommandTrace struct {
+	PCSID           int       `json:"pcs_id"`
+	TargetPowerKW   float32   `json:"target_power_kw"`
+	ReceivedAt      time.Time `json:"received_at"`
+	WriteLatencyMs  float64   `json:"write_latency_ms"`
+	EffectLatencyMs float64   `json:"effect_latency_ms"`
+	EffectSeen      bool      `json:"effect_seen"`
+}
+
+// CommandLatencyStats summarizes the latency distribution over the retained
+// command traces
+type CommandLatencyStats struct {
+	Count           int     `json:"count"`
+	EffectSeenCount int     `json:"effect_seen_count"`
+	WriteP50Ms      float64 `json:"write_p50_ms"`
+	WriteP95Ms      float64 `json:"write_p95_ms"`
+	WriteMaxMs      float64 `json:"write_max_ms"`
+	EffectP50Ms     float64 `json:"effect_p50_ms"`
+	EffectP95Ms     float64 `json:"effect_p95_ms"`
+	EffectMaxMs     float64 `json:"effect_max_ms"`
+}
+
+// beginCommandTrace starts tracing a freshly written command; a command
+// superseding a pending one finalizes the old trace without an observed
+// effect. The caller must hold the mutex.
+func (s *Service) beginCommandTrace(power float32, receivedAt, writtenAt time.Time) {
+	if s.pendingTrace != nil {
+		s.finalizePendingTrace(false, time.Time{})
+	}
+
+	s.pendingTrace = &CommandTrace{
+		PCSID:          s.config.ID,
+		TargetPowerKW:  power,
+		ReceivedAt:     receivedAt,
+		WriteLatencyMs: float64(writtenAt.Sub(receivedAt)) / float64(time.Millisecond),
+	}
+}
+
+// finalizePendingTrace completes the pending trace and appends it to the
+// retained traces. The caller must hold the mutex.
+func (s *Service) finalizePendingTrace(effectSeen bool, effectAt time.Time) {
+	trace := *s.pendingTrace
+	s.pendingTrace = nil
+
+	if effectSeen {
+		trace.EffectSeen = true
+		trace.EffectLatencyMs = float64(effectAt.Sub(trace.ReceivedAt)) / float64(time.Millisecond)
+	}
+
+	s.commandTraces.Append(trace)
+
+	go s.persistCommandTrace(trace)
+}
+
+// persistCommandTrace writes a completed trace to InfluxDB for the SLA
+// reporting
+func (s *Service) persistCommandTrace(trace CommandTrace) {
+	data := database.CommandLatency{
+		Timestamp:       trace.ReceivedAt,
+		PCSID:           trace.PCSID,
+		TargetPowerKW:   trace.TargetPowerKW,
+		WriteLatencyMs:  trace.WriteLatencyMs,
+		EffectLatencyMs: trace.EffectLatencyMs,
+		EffectSeen:      trace.EffectSeen,
+	}
+
+	if err := s.influxDB.WriteCommandLatency(data); err != nil {
+		s.log.Error("Failed to persist command latency", zap.Error(err))
+	}
+}
+
+// checkCommandEffect matches a fresh grid power sample against the pending
+// command trace
+func (s *Service) checkCommandEffect(measuredPowerKW float32) {
+	now := time.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.pendingTrace == nil {
+		return
+	}
+
+	if now.Sub(s.pendingTrace.ReceivedAt) > effectTimeout {
+		s.finalizePendingTrace(false, time.Time{})
+		return
+	}
+
+	deviation := measuredPowerKW - s.pendingTrace.TargetPowerKW
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	if deviation <= effectToleranceKW {
+		s.finalizePendingTrace(true, now)
+	}
+}
+
+// GetCommandTraces returns the completed command traces, oldest first
+func (s *Service) GetCommandTraces() []CommandTrace {
+	return s.commandTraces.Snapshot()
+}
+
+// GetCommandTraces collects the completed command traces of every PCS
+func (m *Manager) GetCommandTraces() []CommandTrace {
+	var traces []CommandTrace
+	for _, service := range m.GetAllServices() {
+		traces = append(traces, service.GetCommandTraces()...)
+	}
+
+	sort.Slice(traces, func(i, j int) bool {
+		return traces[i].ReceivedAt.Before(traces[j].ReceivedAt)
+	})
+	return traces
+}
+
+// ComputeCommandLatencyStats summarizes the latency distribution of the
+// given traces; effect percentiles only cover traces with a visible effect
+func ComputeCommandLatencyStats(traces []CommandTrace) CommandLatencyStats {
+	stats := CommandLatencyStats{Count: len(traces)}
+
+	writeLatencies := make([]float64, 0, len(traces))
+	effectLatencies := make([]float64, 0, len(traces))
+	for _, trace := range traces {
+		writeLatencies = append(writeLatencies, trace.WriteLatencyMs)
+		if trace.EffectSeen {
+			effectLatencies = append(effectLatencies, trace.EffectLatencyMs)
+		}
+	}
+	stats.EffectSeenCount = len(effectLatencies)
+
+	stats.WriteP50Ms = percentile(writeLatencies, 0.50)
+	stats.WriteP95Ms = percentile(writeLatencies, 0.95)
+	stats.WriteMaxMs = percentile(writeLatencies, 1.0)
+	stats.EffectP50Ms = percentile(effectLatencies, 0.50)
+	stats.EffectP95Ms = percentile(effectLatencies, 0.95)
+	stats.EffectMaxMs = percentile(effectLatencies, 1.0)
+
+	return stats
+}
+
+// percentile returns the given percentile of the values, or 0 for an empty
+// set
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	idx := int(p*float64(len(sorted))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
@@ -10,6 +10,7 @@ import (
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
 )
 
 // Manager manages multiple PCS services
@@ -21,7 +22,7 @@ type Manager struct {
 }
 
 // NewManager creates a new PCS manager
-func NewManager(configs []config.PCSConfig, influxDB *database.InfluxDB, alarmManager *alarm.Manager, logger *zap.Logger) *Manager {
+func NewManager(configs []config.PCSConfig, influxDB *database.InfluxDB, alarmManager *alarm.Manager, telemetryManager *telemetry.Manager, logger *zap.Logger) *Manager {
 	managerLogger := logger.With(zap.String("component", "pcs_manager"))
 
 	manager := &Manager{
@@ -30,7 +31,7 @@ func NewManager(configs []config.PCSConfig, influxDB *database.InfluxDB, alarmMa
 	}
 
 	for _, cfg := range configs {
-		service := NewService(cfg, influxDB, alarmManager, logger)
+		service := NewService(cfg, influxDB, alarmManager, telemetryManager, logger)
 		manager.services[cfg.ID] = service
 	}
 
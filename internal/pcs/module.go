@@ -9,6 +9,7 @@ import (
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
 )
 
 // Module provides PCS management functionality to the Fx application
@@ -22,9 +23,10 @@ func ProvideManager(
 	cfg *config.Config,
 	influxDB *database.InfluxDB,
 	alarmManager *alarm.Manager,
+	telemetryManager *telemetry.Manager,
 	logger *zap.Logger,
 ) *Manager {
-	return NewManager(cfg.PCS, influxDB, alarmManager, logger)
+	return NewManager(cfg.PCS, influxDB, alarmManager, telemetryManager, logger)
 }
 
 // RegisterLifecycle registers lifecycle hooks for the PCS manager
@@ -1,6 +1,7 @@
 package pcs
 
 import (
+	"fmt"
 	"time"
 
 	"powerkonnekt/ems/internal/database"
@@ -8,28 +9,22 @@ import (
 )
 
 // parseStatusData parses status data registers
-func parseStatusData(data []byte, id int, timestamp time.Time) database.PCSStatusData {
+func parseStatusData(data []byte, id int, timestamp time.Time) (database.PCSStatusData, error) {
 	if len(data) < StatusDataLength*2 {
-		return database.PCSStatusData{
-			Timestamp: timestamp,
-			ID:        id,
-		}
+		return database.PCSStatusData{}, fmt.Errorf("status data too short: got %d bytes, want %d", len(data), StatusDataLength*2)
 	}
 
 	return database.PCSStatusData{
 		Timestamp: timestamp,
 		ID:        id,
 		Status:    utils.FromBytes[uint16](data[0:2]), // 1003 - Status
-	}
+	}, nil
 }
 
 // parseEquipmentData parses equipment data registers
-func parseEquipmentData(data []byte, id int, timestamp time.Time) database.PCSEquipmentData {
+func parseEquipmentData(data []byte, id int, timestamp time.Time) (database.PCSEquipmentData, error) {
 	if len(data) < EquipmentDataLength*2 {
-		return database.PCSEquipmentData{
-			Timestamp: timestamp,
-			ID:        id,
-		}
+		return database.PCSEquipmentData{}, fmt.Errorf("equipment data too short: got %d bytes, want %d", len(data), EquipmentDataLength*2)
 	}
 
 	return database.PCSEquipmentData{
@@ -43,32 +38,26 @@ func parseEquipmentData(data []byte, id int, timestamp time.Time) database.PCSEq
 		DC2SwitchStatus:        utils.FromBytes[uint16](data[14:16]), // 638 - DC switch status of module 2
 		DC3SwitchStatus:        utils.FromBytes[uint16](data[16:18]), // 639 - DC switch status of module 3
 		DC4SwitchStatus:        utils.FromBytes[uint16](data[18:20]), // 640 - DC switch status of module 4
-	}
+	}, nil
 }
 
 // parseEnvironmentData parses environment data registers
-func parseEnvironmentData(data []byte, id int, timestamp time.Time) database.PCSEnvironmentData {
+func parseEnvironmentData(data []byte, id int, timestamp time.Time) (database.PCSEnvironmentData, error) {
 	if len(data) < EnvironmentDataLength*2 {
-		return database.PCSEnvironmentData{
-			Timestamp: timestamp,
-			ID:        id,
-		}
+		return database.PCSEnvironmentData{}, fmt.Errorf("environment data too short: got %d bytes, want %d", len(data), EnvironmentDataLength*2)
 	}
 
 	return database.PCSEnvironmentData{
 		Timestamp:           timestamp,
 		ID:                  id,
 		AirInletTemperature: utils.FromBytes[int16](data[0:2]), // 1104 - Air inlet temperature (°C)
-	}
+	}, nil
 }
 
 // parseDCSourceData parses DC source data registers
-func parseDCSourceData(data []byte, id int, timestamp time.Time) database.PCSDCSourceData {
+func parseDCSourceData(data []byte, id int, timestamp time.Time) (database.PCSDCSourceData, error) {
 	if len(data) < DCSourceDataLength*2 {
-		return database.PCSDCSourceData{
-			Timestamp: timestamp,
-			ID:        id,
-		}
+		return database.PCSDCSourceData{}, fmt.Errorf("DC source data too short: got %d bytes, want %d", len(data), DCSourceDataLength*2)
 	}
 
 	return database.PCSDCSourceData{
@@ -82,16 +71,13 @@ func parseDCSourceData(data []byte, id int, timestamp time.Time) database.PCSDCS
 		DC2Current: utils.FromBytes[uint16](data[10:12]), // 1377 - DC current of busbar 2 (A)
 		DC3Current: utils.FromBytes[uint16](data[12:14]), // 1378 - DC current of busbar 3 (A)
 		DC4Current: utils.FromBytes[uint16](data[14:16]), // 1379 - DC current of busbar 4 (A)
-	}
+	}, nil
 }
 
 // parseGridData parses grid data registers
-func parseGridData(data []byte, id int, timestamp time.Time) database.PCSGridData {
+func parseGridData(data []byte, id int, timestamp time.Time) (database.PCSGridData, error) {
 	if len(data) < GridDataLength*2 {
-		return database.PCSGridData{
-			Timestamp: timestamp,
-			ID:        id,
-		}
+		return database.PCSGridData{}, fmt.Errorf("grid data too short: got %d bytes, want %d", len(data), GridDataLength*2)
 	}
 
 	return database.PCSGridData{
@@ -118,16 +104,13 @@ func parseGridData(data []byte, id int, timestamp time.Time) database.PCSGridDat
 		LVGridApparentPower: utils.FromBytes[uint16](data[56:58]),                               // 4328 - Apparent power (kVA)
 		LVGridCosPhi:        utils.Scale(utils.FromBytes[uint16](data[58:60]), float32(0.001)),  // 4329 - Grid cos phi (0.001)
 		GridFrequency:       utils.Scale(utils.FromBytes[uint32](data[62:66]), float32(0.0001)), // 4331-4332 - Grid frequency (0.0001Hz)
-	}
+	}, nil
 }
 
 // parseCounterData parses counter data registers
-func parseCounterData(data []byte, id int, timestamp time.Time) database.PCSCounterData {
+func parseCounterData(data []byte, id int, timestamp time.Time) (database.PCSCounterData, error) {
 	if len(data) < CounterDataLength*2 {
-		return database.PCSCounterData{
-			Timestamp: timestamp,
-			ID:        id,
-		}
+		return database.PCSCounterData{}, fmt.Errorf("counter data too short: got %d bytes, want %d", len(data), CounterDataLength*2)
 	}
 
 	return database.PCSCounterData{
@@ -145,5 +128,5 @@ func parseCounterData(data []byte, id int, timestamp time.Time) database.PCSCoun
 		ReactiveEnergyThisMonth: utils.FromBytes[int32](data[38:42]),  // 558-559 - This month's reactive energy (kVArh)
 		ReactiveEnergyLastMonth: utils.FromBytes[int32](data[42:46]),  // 560-561 - Last month's reactive energy (kVArh)
 		ReactiveEnergyTotal:     utils.FromBytes[int32](data[46:50]),  // 562-563 - Total reactive energy (kVArh)
-	}
+	}, nil
 }
@@ -0,0 +1,49 @@
+package pcs
+
+import (
+	"testing"
+	"time"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// fuzzParser runs a shared fuzz harness for the PCS register parsers: the
+// parser must never panic, must reject frames shorter than the register block
+// with an error, and must accept full-length frames
+func fuzzParser[T any](f *testing.F, registerCount int, parse func(data []byte, id int, timestamp time.Time) (T, error)) {
+	f.Helper()
+
+	f.Add([]byte{})
+	f.Add(make([]byte, registerCount*2-1))
+	f.Add(make([]byte, registerCount*2))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := parse(data, 1, time.Now())
+		if (len(data) < registerCount*2) != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+	})
+}
+
+func FuzzParseStatusData(f *testing.F) {
+	fuzzParser[database.PCSStatusData](f, StatusDataLength, parseStatusData)
+}
+
+func FuzzParseEquipmentData(f *testing.F) {
+	fuzzParser[database.PCSEquipmentData](f, EquipmentDataLength, parseEquipmentData)
+}
+
+func FuzzParseEnvironmentData(f *testing.F) {
+	fuzzParser[database.PCSEnvironmentData](f, EnvironmentDataLength, parseEnvironmentData)
+}
+
+func FuzzParseDCSourceData(f *testing.F) {
+	fuzzParser[database.PCSDCSourceData](f, DCSourceDataLength, parseDCSourceData)
+}
+
+func FuzzParseGridData(f *testing.F) {
+	fuzzParser[database.PCSGridData](f, GridDataLength, parseGridData)
+}
+
+func FuzzParseCounterData(f *testing.F) {
+	fuzzParser[database.PCSCounterData](f, CounterDataLength, parseCounterData)
+}
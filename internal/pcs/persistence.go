@@ -6,9 +6,15 @@ import (
 	"go.uber.org/zap"
 )
 
+// persistInterval returns the configured persist interval scaled by the
+// active telemetry profile
+func (s *Service) persistInterval() time.Duration {
+	return s.config.PersistInterval * time.Duration(s.telemetry.Current().PersistScale)
+}
+
 // persistenceLoop periodically writes data to InfluxDB
 func (s *Service) persistenceLoop() {
-	interval := s.config.PersistInterval
+	interval := s.persistInterval()
 
 	// Calculate first aligned time and create timer
 	nextTick := time.Now().Truncate(interval).Add(interval)
@@ -29,15 +35,20 @@ func (s *Service) persistenceLoop() {
 					zap.Duration("interval", interval))
 			}
 
-			// Calculate next aligned time and reset timer
+			// Calculate next aligned time and reset timer, picking up
+			// telemetry profile changes
+			interval = s.persistInterval()
 			nextTick = time.Now().Truncate(interval).Add(interval)
 			timer.Reset(time.Until(nextTick))
 		}
 	}
 }
 
-// persistData writes all data to InfluxDB
+// persistData writes all data to InfluxDB; secondary measurements are
+// skipped when the telemetry profile excludes detail data
 func (s *Service) persistData() {
+	profile := s.telemetry.Current()
+
 	s.mutex.RLock()
 	statusData := s.lastStatusData
 	equipmentData := s.lastEquipmentData
@@ -53,19 +64,19 @@ func (s *Service) persistData() {
 		}
 	}
 
-	if !equipmentData.Timestamp.IsZero() {
+	if profile.IncludeDetail && !equipmentData.Timestamp.IsZero() {
 		if err := s.influxDB.WritePCSEquipmentData(equipmentData); err != nil {
 			s.log.Error("Failed to write equipment data", zap.Error(err))
 		}
 	}
 
-	if !environmentData.Timestamp.IsZero() {
+	if profile.IncludeDetail && !environmentData.Timestamp.IsZero() {
 		if err := s.influxDB.WritePCSEnvironmentData(environmentData); err != nil {
 			s.log.Error("Failed to write environment data", zap.Error(err))
 		}
 	}
 
-	if !dcSourceData.Timestamp.IsZero() {
+	if profile.IncludeDetail && !dcSourceData.Timestamp.IsZero() {
 		if err := s.influxDB.WritePCSDCSourceData(dcSourceData); err != nil {
 			s.log.Error("Failed to write DC source data", zap.Error(err))
 		}
@@ -77,7 +88,7 @@ func (s *Service) persistData() {
 		}
 	}
 
-	if !counterData.Timestamp.IsZero() {
+	if profile.IncludeDetail && !counterData.Timestamp.IsZero() {
 		if err := s.influxDB.WritePCSCounterData(counterData); err != nil {
 			s.log.Error("Failed to write counter data", zap.Error(err))
 		}
@@ -0,0 +1,55 @@
+package pcs
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pollgroup"
+)
+
+// PollGroupDisabledAlarmCode identifies the alarm raised when an optional
+// poll group is disabled after persistent read failures
+const PollGroupDisabledAlarmCode = 9006
+
+// defaultPollGroups lists the register blocks of the PCS poll cycle. The
+// environment and counter blocks are non-critical and disabled
+// automatically when they fail persistently; the remaining blocks keep
+// being retried.
+func defaultPollGroups() []pollgroup.Settings {
+	return []pollgroup.Settings{
+		{Name: "status"},
+		{Name: "equipment"},
+		{Name: "environment", Optional: true},
+		{Name: "dc_source"},
+		{Name: "grid"},
+		{Name: "counter", Optional: true},
+		{Name: "faults"},
+		{Name: "warnings"},
+	}
+}
+
+// newPollGroups builds the poll groups with the configured overrides
+func newPollGroups(cfg config.PCSConfig) map[string]*pollgroup.Group {
+	return pollgroup.Build(defaultPollGroups(), cfg.PollGroups)
+}
+
+// disablePollGroup alarms once and logs when an optional group is disabled
+// after persistent failures; the group stays disabled until restart
+func (s *Service) disablePollGroup(group *pollgroup.Group) {
+	s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: fmt.Sprintf("PCS_%d", s.config.ID),
+		AlarmCode: PollGroupDisabledAlarmCode,
+		Message:   fmt.Sprintf("Poll group %s disabled after %d consecutive read failures", group.Name(), group.Failures()),
+		Severity:  "LOW",
+		Active:    true,
+	})
+
+	s.log.Error("Poll group disabled after persistent read failures",
+		zap.String("group", group.Name()),
+		zap.Int("failures", group.Failures()))
+}
@@ -5,10 +5,25 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"powerkonnekt/ems/pkg/modbus"
 )
 
 // pollLoop periodically reads data from the PCS
 func (s *Service) pollLoop() {
+	s.client.OnConnectionChange(func(connected bool) {
+		if connected {
+			s.log.Info("PCS connection established")
+		} else {
+			s.log.Warn("PCS connection lost, reconnecting in the background")
+		}
+	})
+	s.client.StartAutoReconnect(s.ctx, modbus.ReconnectSettings{
+		InitialDelay: s.config.ReconnectDelay,
+		MaxDelay:     s.config.ReconnectMaxDelay,
+		MaxRetries:   s.config.ReconnectMaxRetries,
+	})
+
 	if err := s.client.Connect(s.ctx); err != nil {
 		s.log.Warn("Initial Modbus connection failed", zap.Error(err))
 	}
@@ -25,9 +40,8 @@ func (s *Service) pollLoop() {
 		case <-s.ctx.Done():
 			return
 		case <-timer.C:
-			if !s.client.IsConnected() {
-				s.handleConnectionError()
-			} else {
+			finish := s.pollWatch.Begin()
+			if s.client.IsConnected() {
 				startTime := time.Now()
 				if err := s.readAllData(); err != nil {
 					s.log.Error("Error reading data", zap.Error(err))
@@ -46,6 +60,7 @@ func (s *Service) pollLoop() {
 						zap.Duration("interval", interval))
 				}
 			}
+			finish()
 
 			// Calculate next aligned time and reset timer
 			nextTick = time.Now().Truncate(interval).Add(interval)
@@ -54,36 +69,6 @@ func (s *Service) pollLoop() {
 	}
 }
 
-// handleConnectionError attempts to reconnect to the PCS
-func (s *Service) handleConnectionError() {
-	s.log.Warn("PCS connection lost, initiating reconnection procedure")
-	s.client.Disconnect()
-
-	reconnectAttempts := 0
-	timer := time.NewTimer(s.config.ReconnectDelay)
-	defer timer.Stop()
-
-	for !s.client.IsConnected() {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-timer.C:
-			reconnectAttempts++
-			if err := s.client.Connect(s.ctx); err != nil {
-				s.log.Error("Failed to reconnect to PCS",
-					zap.Error(err),
-					zap.Int("attempt", reconnectAttempts))
-				timer.Reset(s.config.ReconnectDelay)
-			} else {
-				s.log.Info("Successfully reconnected to PCS",
-					zap.Int("total_attempts", reconnectAttempts),
-					zap.Duration("total_downtime", time.Duration(reconnectAttempts)*s.config.ReconnectDelay))
-				return
-			}
-		}
-	}
-}
-
 // readAllData reads all data
 func (s *Service) readAllData() error {
 	// Read PCS data
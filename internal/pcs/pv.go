@@ -0,0 +1,120 @@
+package pcs
+
+import (
+	"time"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// DC busbar roles; sites with DC-coupled PV connect PV strings to some of
+// the four PCS DC busbars instead of battery strings
+const (
+	BusbarRoleBattery = "battery"
+	BusbarRolePV      = "pv"
+)
+
+// DCBreakdown is the DC-side power and daily energy of one PCS split by
+// busbar role
+type DCBreakdown struct {
+	BusbarRoles         []string `json:"busbar_roles"`
+	BatteryPowerKW      float32  `json:"battery_power_kw"`
+	PVPowerKW           float32  `json:"pv_power_kw"`
+	PVProductionKWh     float32  `json:"pv_production_kwh"`     // today
+	BatteryChargeKWh    float32  `json:"battery_charge_kwh"`    // today
+	BatteryDischargeKWh float32  `json:"battery_discharge_kwh"` // today
+}
+
+// busbarRole returns the configured role of a DC busbar (0-based index);
+// busbars without configuration are battery busbars
+func (s *Service) busbarRole(index int) string {
+	if index < len(s.config.BusbarRoles) {
+		return s.config.BusbarRoles[index]
+	}
+	return BusbarRoleBattery
+}
+
+// splitDCPower splits the four busbar powers of a DC source measurement by
+// busbar role
+func (s *Service) splitDCPower(data database.PCSDCSourceData) (battery, pv float32) {
+	powers := []int16{data.DC1Power, data.DC2Power, data.DC3Power, data.DC4Power}
+	for i, power := range powers {
+		if s.busbarRole(i) == BusbarRolePV {
+			pv += float32(power)
+		} else {
+			battery += float32(power)
+		}
+	}
+	return battery, pv
+}
+
+// recordDCEnergy integrates the busbar powers into the daily PV production
+// and battery throughput counters; the counters roll over at midnight
+func (s *Service) recordDCEnergy(data database.PCSDCSourceData, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if !s.dcEnergyDay.Equal(day) {
+		if !s.dcEnergyDay.IsZero() {
+			s.pvProductionKWh = 0
+			s.batteryChargeKWh = 0
+			s.batteryDischargeKWh = 0
+		}
+		s.dcEnergyDay = day
+	}
+
+	last := s.dcEnergySample
+	s.dcEnergySample = now
+	if last.IsZero() {
+		return
+	}
+
+	// Clip the step so data gaps do not integrate phantom energy
+	dt := now.Sub(last)
+	if dt > 2*s.config.PollInterval {
+		dt = 2 * s.config.PollInterval
+	}
+	hours := dt.Hours()
+
+	battery, pv := s.splitDCPower(data)
+	if pv > 0 {
+		s.pvProductionKWh += float64(pv) * hours
+	}
+	if battery >= 0 {
+		s.batteryDischargeKWh += float64(battery) * hours
+	} else {
+		s.batteryChargeKWh += float64(-battery) * hours
+	}
+}
+
+// GetDCBreakdown returns the current DC-side power and daily energy split by
+// busbar role
+func (s *Service) GetDCBreakdown() DCBreakdown {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	roles := make([]string, 4)
+	for i := range roles {
+		roles[i] = s.busbarRole(i)
+	}
+
+	battery, pv := s.splitDCPower(s.lastDCSourceData)
+
+	return DCBreakdown{
+		BusbarRoles:         roles,
+		BatteryPowerKW:      battery,
+		PVPowerKW:           pv,
+		PVProductionKWh:     float32(s.pvProductionKWh),
+		BatteryChargeKWh:    float32(s.batteryChargeKWh),
+		BatteryDischargeKWh: float32(s.batteryDischargeKWh),
+	}
+}
+
+// GetPVPowerKW returns the current DC-coupled PV power of this PCS in kW
+func (s *Service) GetPVPowerKW() float32 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, pv := s.splitDCPower(s.lastDCSourceData)
+	return pv
+}
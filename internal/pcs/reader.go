@@ -63,7 +63,10 @@ func (s *Service) readPCSData() error {
 			if err != nil {
 				return fmt.Errorf("failed to read registers: %w", err)
 			}
-			statusData = parseStatusData(data, s.config.ID, timestamp)
+			statusData, err = parseStatusData(data, s.config.ID, timestamp)
+			if err != nil {
+				return fmt.Errorf("failed to parse status data: %w", err)
+			}
 			return nil
 		}},
 		{"equipment", func() error {
@@ -71,7 +74,10 @@ func (s *Service) readPCSData() error {
 			if err != nil {
 				return fmt.Errorf("failed to read registers: %w", err)
 			}
-			equipmentData = parseEquipmentData(data, s.config.ID, timestamp)
+			equipmentData, err = parseEquipmentData(data, s.config.ID, timestamp)
+			if err != nil {
+				return fmt.Errorf("failed to parse equipment data: %w", err)
+			}
 			return nil
 		}},
 		{"environment", func() error {
@@ -79,7 +85,10 @@ func (s *Service) readPCSData() error {
 			if err != nil {
 				return fmt.Errorf("failed to read registers: %w", err)
 			}
-			environmentData = parseEnvironmentData(data, s.config.ID, timestamp)
+			environmentData, err = parseEnvironmentData(data, s.config.ID, timestamp)
+			if err != nil {
+				return fmt.Errorf("failed to parse environment data: %w", err)
+			}
 			return nil
 		}},
 		{"dc_source", func() error {
@@ -87,7 +96,10 @@ func (s *Service) readPCSData() error {
 			if err != nil {
 				return fmt.Errorf("failed to read registers: %w", err)
 			}
-			dcSourceData = parseDCSourceData(data, s.config.ID, timestamp)
+			dcSourceData, err = parseDCSourceData(data, s.config.ID, timestamp)
+			if err != nil {
+				return fmt.Errorf("failed to parse DC source data: %w", err)
+			}
 			return nil
 		}},
 		{"grid", func() error {
@@ -95,7 +107,10 @@ func (s *Service) readPCSData() error {
 			if err != nil {
 				return fmt.Errorf("failed to read registers: %w", err)
 			}
-			gridData = parseGridData(data, s.config.ID, timestamp)
+			gridData, err = parseGridData(data, s.config.ID, timestamp)
+			if err != nil {
+				return fmt.Errorf("failed to parse grid data: %w", err)
+			}
 			return nil
 		}},
 		{"counter", func() error {
@@ -103,38 +118,92 @@ func (s *Service) readPCSData() error {
 			if err != nil {
 				return fmt.Errorf("failed to read registers: %w", err)
 			}
-			counterData = parseCounterData(data, s.config.ID, timestamp)
+			counterData, err = parseCounterData(data, s.config.ID, timestamp)
+			if err != nil {
+				return fmt.Errorf("failed to parse counter data: %w", err)
+			}
 			return nil
 		}},
 		{"faults", s.readFaults},
 		{"warnings", s.readWarnings},
 	}
 
-	wg.Add(len(readFuncs))
-
+	// Skip groups that are disabled or not yet due this cycle
+	due := readFuncs[:0]
 	for _, rf := range readFuncs {
+		if group := s.pollGroups[rf.name]; group == nil || group.Due(timestamp) {
+			due = append(due, rf)
+		}
+	}
+
+	succeeded := make(map[string]bool, len(due))
+	wg.Add(len(due))
+
+	for _, rf := range due {
 		go func(name string, fn func() error) {
 			defer wg.Done()
+			group := s.pollGroups[name]
 			if err := fn(); err != nil {
 				s.log.Error("Failed to read "+name+" data", zap.Error(err))
+				if group != nil && group.RecordFailure(timestamp) {
+					s.disablePollGroup(group)
+				}
 				mu.Lock()
 				lastErr = err
 				mu.Unlock()
+				return
 			}
+			if group != nil {
+				group.RecordSuccess(timestamp)
+			}
+			mu.Lock()
+			succeeded[name] = true
+			mu.Unlock()
 		}(rf.name, rf.fn)
 	}
 
 	wg.Wait()
 
-	// Update all data atomically after all reads complete
+	// Update the data of successfully read groups atomically, keeping the
+	// last good data of skipped or failed groups
 	s.mutex.Lock()
-	s.lastStatusData = statusData
-	s.lastEquipmentData = equipmentData
-	s.lastEnvironmentData = environmentData
-	s.lastDCSourceData = dcSourceData
-	s.lastGridData = gridData
-	s.lastCounterData = counterData
+	if succeeded["status"] {
+		s.lastStatusData = statusData
+	}
+	if succeeded["equipment"] {
+		s.lastEquipmentData = equipmentData
+	}
+	if succeeded["environment"] {
+		s.lastEnvironmentData = environmentData
+	}
+	if succeeded["dc_source"] {
+		s.lastDCSourceData = dcSourceData
+	}
+	if succeeded["grid"] {
+		s.lastGridData = gridData
+	}
+	if succeeded["counter"] {
+		// Freeze the consumed-energy counter at its pre-rollover value when
+		// the PCS day counter rolls over
+		if counterData.ConsumedEnergyToday < s.lastCounterData.ConsumedEnergyToday {
+			s.consumedYesterdayKWh = s.lastCounterData.ConsumedEnergyToday
+		}
+		s.lastCounterData = counterData
+	}
 	s.mutex.Unlock()
 
+	if succeeded["dc_source"] {
+		s.recordDCEnergy(dcSourceData, timestamp)
+	}
+
+	// Match the fresh grid measurement against a pending command trace and
+	// feed the rolling trend statistics
+	if succeeded["grid"] {
+		s.checkCommandEffect(float32(gridData.MVGridActivePower))
+		s.trends.Observe("active_power", float32(gridData.MVGridActivePower))
+		s.trends.Observe("reactive_power", float32(gridData.MVGridReactivePower))
+		s.trends.Observe("grid_frequency", gridData.GridFrequency)
+	}
+
 	return lastErr
 }
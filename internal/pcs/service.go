@@ -2,22 +2,36 @@ package pcs
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pollgroup"
+	"powerkonnekt/ems/internal/telemetry"
+	"powerkonnekt/ems/pkg/cache"
+	"powerkonnekt/ems/pkg/loopwatch"
 	"powerkonnekt/ems/pkg/modbus"
+	"powerkonnekt/ems/pkg/rolling"
 )
 
+// trendSampleCapacity bounds the samples kept per trend field; it covers the
+// longest rolling window at the 1 s poll rate
+const trendSampleCapacity = 1024
+
 // Service represents the PCS service
 type Service struct {
 	config       config.PCSConfig
 	influxDB     *database.InfluxDB
 	alarmManager *alarm.Manager
+	telemetry    *telemetry.Manager
 	client       *modbus.Client
+	pollGroups   map[string]*pollgroup.Group
+	pollWatch    *loopwatch.Loop
 	ctx          context.Context
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
@@ -25,21 +39,56 @@ type Service struct {
 
 	dataUpdateChan chan struct{}
 
-	mutex               sync.RWMutex
-	lastStatusData      database.PCSStatusData
-	lastEquipmentData   database.PCSEquipmentData
-	lastEnvironmentData database.PCSEnvironmentData
-	lastDCSourceData    database.PCSDCSourceData
-	lastGridData        database.PCSGridData
-	lastCounterData     database.PCSCounterData
-	commandState        database.PCSCommandState
-	previousAlarmStates map[string]bool
-	heartbeatCount      uint16
+	trends *rolling.Tracker // Rolling statistics of the key grid fields
+
+	mutex                 sync.RWMutex
+	lastStatusData        database.PCSStatusData
+	lastEquipmentData     database.PCSEquipmentData
+	lastEnvironmentData   database.PCSEnvironmentData
+	lastDCSourceData      database.PCSDCSourceData
+	lastGridData          database.PCSGridData
+	lastCounterData       database.PCSCounterData
+	consumedYesterdayKWh  uint32 // Consumed energy frozen at the last day rollover
+	commandState          database.PCSCommandState
+	previousAlarmStates   map[string]bool
+	heartbeatCount        uint16
+	lastStatusTimestamp   time.Time
+	lastHeartbeatProgress time.Time
+	heartbeatStale        bool
+	heartbeatLost         bool
+	transformerOverloaded bool
+	exceptionSpike        bool
+
+	// Daily DC-side energy accounting split by busbar role
+	dcEnergyDay         time.Time
+	dcEnergySample      time.Time
+	pvProductionKWh     float64
+	batteryChargeKWh    float64
+	batteryDischargeKWh float64
+
+	commandQueue     chan *QueuedCommand
+	commandStatuses  map[string]*QueuedCommand
+	commandOrder     []string
+	pendingSetpoints map[string]*QueuedCommand
+	commandCounter   uint64
+
+	pendingTrace  *CommandTrace             // Command awaiting its telemetry effect
+	commandTraces *cache.Ring[CommandTrace] // Completed latency traces, oldest first
 }
 
 // NewService creates a new PCS service
-func NewService(cfg config.PCSConfig, influxDB *database.InfluxDB, alarmManager *alarm.Manager, logger *zap.Logger) *Service {
-	client := modbus.NewClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout)
+func NewService(cfg config.PCSConfig, influxDB *database.InfluxDB, alarmManager *alarm.Manager, telemetryManager *telemetry.Manager, logger *zap.Logger) *Service {
+	var client *modbus.Client
+	if cfg.Simulate {
+		client = modbus.NewSimulatedClient(cfg.SlaveID, cfg.Timeout)
+	} else {
+		client = modbus.NewSecureClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout, cfg.BindAddress, modbus.TLSSettings{
+			Enabled:  cfg.TLS.Enabled,
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.CAFile,
+		})
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Create service-specific logger
@@ -54,19 +103,35 @@ func NewService(cfg config.PCSConfig, influxDB *database.InfluxDB, alarmManager
 		config:              cfg,
 		influxDB:            influxDB,
 		alarmManager:        alarmManager,
+		telemetry:           telemetryManager,
 		client:              client,
+		pollGroups:          newPollGroups(cfg),
+		pollWatch:           loopwatch.NewLoop(fmt.Sprintf("pcs_%d_poll", cfg.ID), cfg.PollInterval),
 		ctx:                 ctx,
 		cancel:              cancel,
 		log:                 serviceLogger,
 		dataUpdateChan:      make(chan struct{}, 1),
 		previousAlarmStates: make(map[string]bool),
+		commandQueue:        make(chan *QueuedCommand, commandQueueSize),
+		commandStatuses:     make(map[string]*QueuedCommand),
+		pendingSetpoints:    make(map[string]*QueuedCommand),
+		commandTraces:       cache.NewRing[CommandTrace](fmt.Sprintf("pcs_%d_command_traces", cfg.ID), commandTraceLimit),
+		trends: rolling.NewTracker(fmt.Sprintf("pcs_%d_trend", cfg.ID), trendSampleCapacity,
+			"active_power", "reactive_power", "grid_frequency"),
 	}
 }
 
 // Start starts the PCS service
 func (s *Service) Start() error {
+	if s.config.Simulate {
+		s.wg.Go(s.simulateLoop)
+	}
 	s.wg.Go(s.pollLoop)
 	s.wg.Go(s.heartbeatLoop)
+	s.wg.Go(s.heartbeatSupervisionLoop)
+	s.wg.Go(s.transformerMonitorLoop)
+	s.wg.Go(s.exceptionMonitorLoop)
+	s.wg.Go(s.commandQueueLoop)
 	s.wg.Go(s.persistenceLoop)
 
 	s.log.Info("PCS service started")
@@ -92,6 +157,22 @@ func (s *Service) GetDataUpdateChannel() <-chan struct{} {
 	return s.dataUpdateChan
 }
 
+// GetConsumedEnergyYesterday returns the consumed energy of the previous
+// day in kWh, frozen at the last day rollover. Unlike the active energy
+// the PCS exposes no yesterday register for consumed energy, so the value
+// is latched when the day counter rolls over.
+func (s *Service) GetConsumedEnergyYesterday() uint32 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.consumedYesterdayKWh
+}
+
+// GetTrendStats returns the rolling min/max/average statistics of the key
+// grid fields per reporting window
+func (s *Service) GetTrendStats() map[string]map[string]rolling.Stats {
+	return s.trends.Stats()
+}
+
 // GetLatestPCSData returns the latest PCS data
 func (s *Service) GetLatestPCSStatusData() database.PCSStatusData {
 	s.mutex.RLock()
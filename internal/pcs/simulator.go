@@ -0,0 +1,166 @@
+package pcs
+
+import (
+	"math"
+	"time"
+)
+
+// Characteristics of the simulated PCS
+const (
+	simMVVoltageV      = 3300.0 // MV side line voltage
+	simLVVoltageV      = 690.0  // LV side line voltage
+	simDCVoltageV      = 1300.0 // DC busbar voltage
+	simRampRateKWPerS  = 500.0  // Slew rate toward the power setpoint
+	simStepInterval    = time.Second
+	simBusbarCount     = 4
+	simFrequencyPeriod = 30 * time.Second // Period of the grid frequency wiggle
+)
+
+// pcsSimulator holds the state of the built-in converter model: the active
+// power ramps toward the commanded setpoint while the PCS is on, and grid
+// measurements and energy counters are derived from it
+type pcsSimulator struct {
+	powerKW     float64
+	elapsed     time.Duration
+	exportedKWh float64
+	consumedKWh float64
+}
+
+// simulateLoop drives the built-in PCS simulator against the in-memory
+// register bank of the simulated MODBUS client, so the regular poll loop,
+// command queue and heartbeat supervision run unchanged without hardware
+func (s *Service) simulateLoop() {
+	s.log.Info("PCS simulator active, serving the in-memory device model")
+
+	sim := &pcsSimulator{}
+	sim.step(s, 0)
+
+	ticker := time.NewTicker(simStepInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			sim.step(s, now.Sub(last))
+			last = now
+		}
+	}
+}
+
+// step advances the converter model by dt and publishes the register image
+func (sim *pcsSimulator) step(s *Service, dt time.Duration) {
+	sim.elapsed += dt
+	client := s.client
+
+	// The EMS writes power commands as value*100, see SetActivePowerCommand
+	running := client.SimRegister(CmdStartStopRegister) == 1
+	var targetKW, reactiveKVAr float64
+	status := uint16(StatusReady)
+	if running {
+		status = StatusON
+		targetKW = float64(int16(client.SimRegister(CmdActivePowerRegister))) / 100
+		reactiveKVAr = float64(int16(client.SimRegister(CmdReactivePowerRegister))) / 100
+	}
+
+	// Ramp the active power toward the setpoint at the slew rate
+	maxStep := simRampRateKWPerS * dt.Seconds()
+	sim.powerKW += math.Max(-maxStep, math.Min(maxStep, targetKW-sim.powerKW))
+
+	if sim.powerKW > 0 {
+		sim.exportedKWh += sim.powerKW * dt.Hours()
+	} else {
+		sim.consumedKWh += -sim.powerKW * dt.Hours()
+	}
+
+	apparentKVA := math.Hypot(sim.powerKW, reactiveKVAr)
+	cosPhi := 1.0
+	if apparentKVA > 0 {
+		cosPhi = math.Abs(sim.powerKW) / apparentKVA
+	}
+
+	client.SimSetRegister(StatusDataStartAddr, status)
+
+	// Equipment: LV/MV switches and DC switches closed, earthing switch open
+	client.SimSetRegisters(EquipmentDataStartAddr,
+		1, 0, 1, 0, 1, 0, 1, 1, 1, 1)
+
+	// Environment: air inlet temperature follows the loading a little
+	client.SimSetRegister(EnvironmentDataStartAddr,
+		uint16(25+int16(2*math.Abs(sim.powerKW)/1000)))
+
+	sim.writeGridData(s, apparentKVA, reactiveKVAr, cosPhi)
+	sim.writeDCSourceData(s)
+	sim.writeCounterData(s)
+}
+
+// writeGridData publishes the MV and LV grid measurement block
+func (sim *pcsSimulator) writeGridData(s *Service, apparentKVA, reactiveKVAr, cosPhi float64) {
+	mvCurrent := apparentKVA * 1000 / (math.Sqrt(3) * simMVVoltageV)
+	lvCurrent := apparentKVA * 1000 / (math.Sqrt(3) * simLVVoltageV)
+
+	frequency := 50 + 0.01*math.Sin(2*math.Pi*sim.elapsed.Seconds()/simFrequencyPeriod.Seconds())
+	frequencyRaw := uint32(math.Round(frequency * 10000))
+
+	s.client.SimSetRegisters(GridDataStartAddr,
+		uint16(simMVVoltageV*10), uint16(simMVVoltageV*10), uint16(simMVVoltageV*10),
+		uint16(mvCurrent*10), uint16(mvCurrent*10), uint16(mvCurrent*10),
+		uint16(int16(math.Round(sim.powerKW))),
+		uint16(int16(math.Round(reactiveKVAr))),
+		uint16(math.Round(apparentKVA)),
+		uint16(math.Round(cosPhi*1000)))
+
+	s.client.SimSetRegisters(GridDataStartAddr+20,
+		uint16(simLVVoltageV*10), uint16(simLVVoltageV*10), uint16(simLVVoltageV*10),
+		uint16(lvCurrent*10), uint16(lvCurrent*10), uint16(lvCurrent*10),
+		uint16(int16(math.Round(sim.powerKW))),
+		uint16(int16(math.Round(reactiveKVAr))),
+		uint16(math.Round(apparentKVA)),
+		uint16(math.Round(cosPhi*1000)))
+
+	s.client.SimSetRegisters(GridDataStartAddr+31,
+		uint16(frequencyRaw>>16), uint16(frequencyRaw))
+}
+
+// writeDCSourceData publishes the DC busbar block; battery busbars share the
+// converter power, PV busbars stay at zero production
+func (sim *pcsSimulator) writeDCSourceData(s *Service) {
+	batteryBusbars := 0
+	for busbar := range simBusbarCount {
+		if s.busbarRole(busbar) == BusbarRoleBattery {
+			batteryBusbars++
+		}
+	}
+	if batteryBusbars == 0 {
+		batteryBusbars = simBusbarCount
+	}
+
+	values := make([]uint16, DCSourceDataLength)
+	for busbar := range simBusbarCount {
+		if s.busbarRole(busbar) != BusbarRoleBattery {
+			continue
+		}
+		powerKW := sim.powerKW / float64(batteryBusbars)
+		values[busbar] = uint16(int16(math.Round(powerKW)))
+		values[busbar+simBusbarCount] = uint16(math.Round(math.Abs(powerKW) * 1000 / simDCVoltageV))
+	}
+	s.client.SimSetRegisters(DCSourceDataStartAddr, values...)
+}
+
+// writeCounterData publishes the energy counter block; today's counters
+// double as the totals since the simulator starts from zero
+func (sim *pcsSimulator) writeCounterData(s *Service) {
+	exported := uint32(sim.exportedKWh)
+	consumed := uint32(sim.consumedKWh)
+
+	s.client.SimSetRegisters(CounterDataStartAddr,
+		uint16(exported>>16), uint16(exported), // Today
+		0, 0, // Yesterday
+		uint16(exported>>16), uint16(exported), // This month
+		0, 0, // Last month
+		uint16(exported>>16), uint16(exported), // Total
+		uint16(consumed>>16), uint16(consumed), // Consumed today
+		uint16(consumed>>16), uint16(consumed)) // Consumed total
+}
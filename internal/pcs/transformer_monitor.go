@@ -0,0 +1,95 @@
+package pcs
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// TransformerOverloadAlarmCode is a synthetic alarm code raised by the EMS
+// when MV transformer loading exceeds the configured nameplate rating
+const TransformerOverloadAlarmCode = 9002
+
+// Overload hysteresis: raise at 100% of rating, clear below 95%
+const (
+	transformerOverloadRaisePercent = 100.0
+	transformerOverloadClearPercent = 95.0
+)
+
+// transformerMonitorLoop monitors MV transformer loading against the
+// configured nameplate rating and raises an overload alarm with hysteresis.
+// Monitoring is disabled when no rating is configured.
+func (s *Service) transformerMonitorLoop() {
+	if s.config.TransformerRatingKVA <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if s.client.IsConnected() {
+				s.checkTransformerLoading()
+			}
+		}
+	}
+}
+
+// checkTransformerLoading evaluates the current MV apparent power against the
+// transformer nameplate rating
+func (s *Service) checkTransformerLoading() {
+	loading := s.GetTransformerLoading()
+
+	s.mutex.Lock()
+	wasOverloaded := s.transformerOverloaded
+	if !wasOverloaded && loading >= transformerOverloadRaisePercent {
+		s.transformerOverloaded = true
+	} else if wasOverloaded && loading < transformerOverloadClearPercent {
+		s.transformerOverloaded = false
+	}
+	overloaded := s.transformerOverloaded
+	s.mutex.Unlock()
+
+	if overloaded == wasOverloaded {
+		return
+	}
+
+	s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: fmt.Sprintf("PCS_%d", s.config.ID),
+		AlarmCode: TransformerOverloadAlarmCode,
+		Message:   "MV transformer loading above nameplate rating",
+		Severity:  "HIGH",
+		Active:    overloaded,
+	})
+
+	if overloaded {
+		s.log.Warn("MV transformer overloaded",
+			zap.Float32("loading_percent", loading),
+			zap.Float32("rating_kva", s.config.TransformerRatingKVA))
+	} else {
+		s.log.Info("MV transformer loading back within rating",
+			zap.Float32("loading_percent", loading))
+	}
+}
+
+// GetTransformerLoading returns the current MV transformer loading as a
+// percentage of the configured nameplate rating (0 when no rating is set)
+func (s *Service) GetTransformerLoading() float32 {
+	if s.config.TransformerRatingKVA <= 0 {
+		return 0
+	}
+
+	s.mutex.RLock()
+	apparentPower := float32(s.lastGridData.MVGridApparentPower)
+	s.mutex.RUnlock()
+
+	return apparentPower / s.config.TransformerRatingKVA * 100.0
+}
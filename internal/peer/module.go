@@ -0,0 +1,42 @@
+package peer
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/site"
+)
+
+// Module provides peer coordination to the Fx application
+var Module = fx.Module("peer",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides a peer coordination service instance
+func ProvideService(
+	cfg *config.Config,
+	controlLogic *control.Logic,
+	siteService *site.Service,
+	logger *zap.Logger,
+) *Service {
+	return NewService(cfg.Peer, cfg.EMS, controlLogic, siteService, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the peer coordination
+// service
+func RegisterLifecycle(lc fx.Lifecycle, service *Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,169 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: peerpb/peer.proto
+
+package peerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SiteState is one site's view of the shared connection point
+type SiteState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Site name, for logging and status reporting
+	Site string `protobuf:"bytes,1,opt,name=site,proto3" json:"site,omitempty"`
+	// Measured net power of this site at the NCP in kW, positive = export
+	NetPowerKw float64 `protobuf:"fixed64,2,opt,name=net_power_kw,json=netPowerKw,proto3" json:"net_power_kw,omitempty"`
+	// Export power this site wants to run at in kW
+	RequestedExportKw float64 `protobuf:"fixed64,3,opt,name=requested_export_kw,json=requestedExportKw,proto3" json:"requested_export_kw,omitempty"`
+	// Export allocation this site is currently enforcing on itself in kW
+	AllocatedExportKw float64 `protobuf:"fixed64,4,opt,name=allocated_export_kw,json=allocatedExportKw,proto3" json:"allocated_export_kw,omitempty"`
+	// Sample time in Unix milliseconds, for staleness detection
+	TimestampUnixMs int64 `protobuf:"varint,5,opt,name=timestamp_unix_ms,json=timestampUnixMs,proto3" json:"timestamp_unix_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SiteState) Reset() {
+	*x = SiteState{}
+	mi := &file_peerpb_peer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SiteState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SiteState) ProtoMessage() {}
+
+func (x *SiteState) ProtoReflect() protoreflect.Message {
+	mi := &file_peerpb_peer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SiteState.ProtoReflect.Descriptor instead.
+func (*SiteState) Descriptor() ([]byte, []int) {
+	return file_peerpb_peer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SiteState) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+func (x *SiteState) GetNetPowerKw() float64 {
+	if x != nil {
+		return x.NetPowerKw
+	}
+	return 0
+}
+
+func (x *SiteState) GetRequestedExportKw() float64 {
+	if x != nil {
+		return x.RequestedExportKw
+	}
+	return 0
+}
+
+func (x *SiteState) GetAllocatedExportKw() float64 {
+	if x != nil {
+		return x.AllocatedExportKw
+	}
+	return 0
+}
+
+func (x *SiteState) GetTimestampUnixMs() int64 {
+	if x != nil {
+		return x.TimestampUnixMs
+	}
+	return 0
+}
+
+var File_peerpb_peer_proto protoreflect.FileDescriptor
+
+const file_peerpb_peer_proto_rawDesc = "" +
+	"\n" +
+	"\x11peerpb/peer.proto\x12\x06peerpb\"\xcd\x01\n" +
+	"\tSiteState\x12\x12\n" +
+	"\x04site\x18\x01 \x01(\tR\x04site\x12 \n" +
+	"\fnet_power_kw\x18\x02 \x01(\x01R\n" +
+	"netPowerKw\x12.\n" +
+	"\x13requested_export_kw\x18\x03 \x01(\x01R\x11requestedExportKw\x12.\n" +
+	"\x13allocated_export_kw\x18\x04 \x01(\x01R\x11allocatedExportKw\x12*\n" +
+	"\x11timestamp_unix_ms\x18\x05 \x01(\x03R\x0ftimestampUnixMs2D\n" +
+	"\x10PeerCoordination\x120\n" +
+	"\bExchange\x12\x11.peerpb.SiteState\x1a\x11.peerpb.SiteStateB'Z%powerkonnekt/ems/internal/peer/peerpbb\x06proto3"
+
+var (
+	file_peerpb_peer_proto_rawDescOnce sync.Once
+	file_peerpb_peer_proto_rawDescData []byte
+)
+
+func file_peerpb_peer_proto_rawDescGZIP() []byte {
+	file_peerpb_peer_proto_rawDescOnce.Do(func() {
+		file_peerpb_peer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_peerpb_peer_proto_rawDesc), len(file_peerpb_peer_proto_rawDesc)))
+	})
+	return file_peerpb_peer_proto_rawDescData
+}
+
+var file_peerpb_peer_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_peerpb_peer_proto_goTypes = []any{
+	(*SiteState)(nil), // 0: peerpb.SiteState
+}
+var file_peerpb_peer_proto_depIdxs = []int32{
+	0, // 0: peerpb.PeerCoordination.Exchange:input_type -> peerpb.SiteState
+	0, // 1: peerpb.PeerCoordination.Exchange:output_type -> peerpb.SiteState
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_peerpb_peer_proto_init() }
+func file_peerpb_peer_proto_init() {
+	if File_peerpb_peer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_peerpb_peer_proto_rawDesc), len(file_peerpb_peer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_peerpb_peer_proto_goTypes,
+		DependencyIndexes: file_peerpb_peer_proto_depIdxs,
+		MessageInfos:      file_peerpb_peer_proto_msgTypes,
+	}.Build()
+	File_peerpb_peer_proto = out.File
+	file_peerpb_peer_proto_goTypes = nil
+	file_peerpb_peer_proto_depIdxs = nil
+}
@@ -0,0 +1,131 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: peerpb/peer.proto
+
+package peerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PeerCoordination_Exchange_FullMethodName = "/peerpb.PeerCoordination/Exchange"
+)
+
+// PeerCoordinationClient is the client API for PeerCoordination service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PeerCoordination exchanges real-time connection-point state between two
+// EMS instances that share one grid connection. Each exchange carries the
+// sender's state and returns the receiver's, so both sides negotiate the
+// export allocation from the same picture.
+type PeerCoordinationClient interface {
+	Exchange(ctx context.Context, in *SiteState, opts ...grpc.CallOption) (*SiteState, error)
+}
+
+type peerCoordinationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPeerCoordinationClient(cc grpc.ClientConnInterface) PeerCoordinationClient {
+	return &peerCoordinationClient{cc}
+}
+
+func (c *peerCoordinationClient) Exchange(ctx context.Context, in *SiteState, opts ...grpc.CallOption) (*SiteState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SiteState)
+	err := c.cc.Invoke(ctx, PeerCoordination_Exchange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PeerCoordinationServer is the server API for PeerCoordination service.
+// All implementations must embed UnimplementedPeerCoordinationServer
+// for forward compatibility.
+//
+// PeerCoordination exchanges real-time connection-point state between two
+// EMS instances that share one grid connection. Each exchange carries the
+// sender's state and returns the receiver's, so both sides negotiate the
+// export allocation from the same picture.
+type PeerCoordinationServer interface {
+	Exchange(context.Context, *SiteState) (*SiteState, error)
+	mustEmbedUnimplementedPeerCoordinationServer()
+}
+
+// UnimplementedPeerCoordinationServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPeerCoordinationServer struct{}
+
+func (UnimplementedPeerCoordinationServer) Exchange(context.Context, *SiteState) (*SiteState, error) {
+	return nil, status.Error(codes.Unimplemented, "method Exchange not implemented")
+}
+func (UnimplementedPeerCoordinationServer) mustEmbedUnimplementedPeerCoordinationServer() {}
+func (UnimplementedPeerCoordinationServer) testEmbeddedByValue()                          {}
+
+// UnsafePeerCoordinationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PeerCoordinationServer will
+// result in compilation errors.
+type UnsafePeerCoordinationServer interface {
+	mustEmbedUnimplementedPeerCoordinationServer()
+}
+
+func RegisterPeerCoordinationServer(s grpc.ServiceRegistrar, srv PeerCoordinationServer) {
+	// If the following call panics, it indicates UnimplementedPeerCoordinationServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PeerCoordination_ServiceDesc, srv)
+}
+
+func _PeerCoordination_Exchange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SiteState)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerCoordinationServer).Exchange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerCoordination_Exchange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerCoordinationServer).Exchange(ctx, req.(*SiteState))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PeerCoordination_ServiceDesc is the grpc.ServiceDesc for PeerCoordination service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PeerCoordination_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "peerpb.PeerCoordination",
+	HandlerType: (*PeerCoordinationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Exchange",
+			Handler:    _PeerCoordination_Exchange_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "peerpb/peer.proto",
+}
@@ -0,0 +1,305 @@
+// Package peer coordinates two EMS instances that share one grid
+// connection point. Both sides exchange their real-time NCP state over
+// gRPC and derive their export allocation from the same picture, so the
+// combined output never violates the shared connection limit. The
+// allocation is applied to the control logic as an export limit, which the
+// existing enforcement chain (command clamping, rapid downward) carries to
+// every asset. When the link is down the site falls back to its configured
+// standalone share.
+package peer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/peer/peerpb"
+	"powerkonnekt/ems/internal/site"
+)
+
+const (
+	// staleAfter is how long the last peer state stays usable; beyond it
+	// the link counts as down and the fallback allocation applies
+	staleAfter = 5 * time.Second
+
+	// allocationMarginKW is headroom kept unallocated so both sites
+	// ramping toward fresh allocations at the same time cannot overshoot
+	// the shared limit between two exchanges
+	allocationMarginKW = 50.0
+)
+
+// Status describes the peer link and the negotiated allocation
+type Status struct {
+	Enabled           bool      `json:"enabled"`
+	Connected         bool      `json:"connected"`
+	Site              string    `json:"site"`
+	PeerSite          string    `json:"peer_site,omitempty"`
+	PeerNetPowerKW    float64   `json:"peer_net_power_kw"`
+	SharedLimitKW     float32   `json:"shared_limit_kw"`
+	AllocatedExportKW float32   `json:"allocated_export_kw"`
+	LastExchange      time.Time `json:"last_exchange,omitempty"`
+}
+
+// Service runs the peer coordination link: a gRPC server answering the
+// peer's exchanges and a client loop driving our own
+type Service struct {
+	peerpb.UnimplementedPeerCoordinationServer
+
+	config       config.PeerConfig
+	emsConfig    config.EMSConfig
+	siteName     string
+	controlLogic *control.Logic
+	siteService  *site.Service
+	server       *grpc.Server
+	conn         *grpc.ClientConn
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	log          *zap.Logger
+
+	mutex      sync.RWMutex
+	peerState  *peerpb.SiteState
+	peerSeenAt time.Time
+	allocation float32
+	linkUp     bool
+}
+
+// NewService creates a new peer coordination service
+func NewService(cfg config.PeerConfig, emsConfig config.EMSConfig, controlLogic *control.Logic, siteService *site.Service, logger *zap.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	serviceLogger := logger.With(
+		zap.String("component", "peer"),
+	)
+
+	siteName := cfg.SiteName
+	if siteName == "" {
+		siteName = emsConfig.ParkName
+	}
+
+	return &Service{
+		config:       cfg,
+		emsConfig:    emsConfig,
+		siteName:     siteName,
+		controlLogic: controlLogic,
+		siteService:  siteService,
+		ctx:          ctx,
+		cancel:       cancel,
+		log:          serviceLogger,
+	}
+}
+
+// Start starts the peer link server and the exchange loop
+func (s *Service) Start() error {
+	if !s.config.Enabled {
+		s.log.Info("Peer coordination disabled")
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", s.config.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.ListenAddress, err)
+	}
+
+	s.server = grpc.NewServer()
+	peerpb.RegisterPeerCoordinationServer(s.server, s)
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			s.log.Error("Peer link server error", zap.Error(err))
+		}
+	}()
+
+	conn, err := grpc.NewClient(s.config.PeerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to create peer client: %w", err)
+	}
+	s.conn = conn
+
+	// Until the first exchange succeeds, run on the standalone share
+	s.controlLogic.SetPeerExportLimit(s.config.FallbackExportKW)
+
+	s.wg.Go(s.exchangeLoop)
+
+	s.log.Info("Peer coordination started",
+		zap.String("site", s.siteName),
+		zap.String("listen_address", s.config.ListenAddress),
+		zap.String("peer_address", s.config.PeerAddress),
+		zap.Float32("shared_export_limit_kw", s.config.SharedExportLimitKW))
+	return nil
+}
+
+// Stop stops the peer link
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+
+	if s.server != nil {
+		s.server.GracefulStop()
+	}
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	if s.config.Enabled {
+		s.controlLogic.ClearPeerExportLimit()
+		s.log.Info("Peer coordination stopped")
+	}
+}
+
+// Exchange answers the peer's state exchange with our own state
+func (s *Service) Exchange(ctx context.Context, remote *peerpb.SiteState) (*peerpb.SiteState, error) {
+	s.recordPeerState(remote)
+	return s.localState(), nil
+}
+
+// exchangeLoop periodically exchanges state with the peer and renegotiates
+// the export allocation
+func (s *Service) exchangeLoop() {
+	ticker := time.NewTicker(s.config.ExchangeInterval)
+	defer ticker.Stop()
+
+	client := peerpb.NewPeerCoordinationClient(s.conn)
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(s.ctx, s.config.Timeout)
+			remote, err := client.Exchange(ctx, s.localState())
+			cancel()
+
+			if err == nil {
+				s.recordPeerState(remote)
+			}
+			s.updateAllocation()
+		}
+	}
+}
+
+// localState captures our current view of the shared connection point
+func (s *Service) localState() *peerpb.SiteState {
+	s.mutex.RLock()
+	allocation := s.allocation
+	s.mutex.RUnlock()
+
+	return &peerpb.SiteState{
+		Site:              s.siteName,
+		NetPowerKw:        float64(s.siteService.GetBalance().NetPowerKW),
+		RequestedExportKw: float64(s.requestedExportKW()),
+		AllocatedExportKw: float64(allocation),
+		TimestampUnixMs:   time.Now().UnixMilli(),
+	}
+}
+
+// requestedExportKW is the export power this site wants to be able to run
+// at: its own connection limit when one is configured, otherwise the full
+// shared limit
+func (s *Service) requestedExportKW() float32 {
+	if limit := s.emsConfig.MaxSiteExportPower; limit > 0 && limit < s.config.SharedExportLimitKW {
+		return limit
+	}
+	return s.config.SharedExportLimitKW
+}
+
+// recordPeerState stores the peer state received over either direction of
+// the link
+func (s *Service) recordPeerState(state *peerpb.SiteState) {
+	if state == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	s.peerState = state
+	s.peerSeenAt = time.Now()
+	s.mutex.Unlock()
+}
+
+// updateAllocation renegotiates our export allocation from the freshest
+// peer state and applies it to the control logic. Both sides evaluate the
+// same rule on the same exchanged state, so the allocations are consistent
+// without a designated leader.
+func (s *Service) updateAllocation() {
+	shared := s.config.SharedExportLimitKW
+
+	s.mutex.Lock()
+	peerState := s.peerState
+	fresh := peerState != nil && time.Since(s.peerSeenAt) < staleAfter
+	wasUp := s.linkUp
+	s.linkUp = fresh
+
+	var allocation float32
+	if !fresh {
+		allocation = s.config.FallbackExportKW
+	} else {
+		ourRequest := s.requestedExportKW()
+		peerRequest := float32(peerState.RequestedExportKw)
+
+		// Proportional base share of the shared limit
+		share := shared
+		if total := ourRequest + peerRequest; total > 0 {
+			share = shared * ourRequest / total
+		}
+
+		// Take slack the peer is measurably not using, keeping a margin so
+		// simultaneous ramps on both sides cannot overshoot between two
+		// exchanges
+		if slack := shared - float32(peerState.NetPowerKw) - allocationMarginKW; slack > share {
+			share = slack
+		}
+
+		allocation = share
+		if allocation > ourRequest {
+			allocation = ourRequest
+		}
+		if allocation < 0 {
+			allocation = 0
+		}
+	}
+	changed := allocation != s.allocation
+	s.allocation = allocation
+	s.mutex.Unlock()
+
+	if fresh != wasUp {
+		if fresh {
+			s.log.Info("Peer link established",
+				zap.String("peer_site", peerState.Site))
+		} else {
+			s.log.Warn("Peer link lost, applying fallback export allocation",
+				zap.Float32("fallback_export_kw", s.config.FallbackExportKW))
+		}
+	}
+
+	if changed {
+		s.log.Debug("Peer export allocation updated",
+			zap.Float32("allocated_export_kw", allocation))
+	}
+	s.controlLogic.SetPeerExportLimit(allocation)
+}
+
+// GetStatus returns the peer link status and the negotiated allocation
+func (s *Service) GetStatus() Status {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	status := Status{
+		Enabled:           s.config.Enabled,
+		Connected:         s.linkUp,
+		Site:              s.siteName,
+		SharedLimitKW:     s.config.SharedExportLimitKW,
+		AllocatedExportKW: s.allocation,
+		LastExchange:      s.peerSeenAt,
+	}
+	if s.peerState != nil {
+		status.PeerSite = s.peerState.Site
+		status.PeerNetPowerKW = s.peerState.NetPowerKw
+	}
+	return status
+}
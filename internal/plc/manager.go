@@ -10,6 +10,7 @@ import (
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
 )
 
 // Manager manages multiple PLC services
@@ -21,7 +22,7 @@ type Manager struct {
 }
 
 // NewManager creates a new PLC manager
-func NewManager(configs []config.PLCConfig, influxDB *database.InfluxDB, alarmManager *alarm.Manager, logger *zap.Logger) *Manager {
+func NewManager(configs []config.PLCConfig, influxDB *database.InfluxDB, alarmManager *alarm.Manager, telemetryManager *telemetry.Manager, logger *zap.Logger) *Manager {
 	managerLogger := logger.With(zap.String("component", "plc_manager"))
 
 	manager := &Manager{
@@ -30,7 +31,7 @@ func NewManager(configs []config.PLCConfig, influxDB *database.InfluxDB, alarmMa
 	}
 
 	for _, cfg := range configs {
-		service := NewService(cfg, influxDB, alarmManager, logger)
+		service := NewService(cfg, influxDB, alarmManager, telemetryManager, logger)
 		manager.services[cfg.ID] = service
 	}
 
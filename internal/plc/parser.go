@@ -1,6 +1,7 @@
 package plc
 
 import (
+	"fmt"
 	"time"
 
 	"powerkonnekt/ems/internal/database"
@@ -8,12 +9,9 @@ import (
 )
 
 // parsePLCData parses raw Modbus data to PLCData structure
-func parsePLCData(data []byte, id int) database.PLCData {
+func parsePLCData(data []byte, id int) (database.PLCData, error) {
 	if len(data) < StatusDataLength*2 {
-		return database.PLCData{
-			Timestamp: time.Now(),
-			ID:        id,
-		}
+		return database.PLCData{}, fmt.Errorf("status data too short: got %d bytes, want %d", len(data), StatusDataLength*2)
 	}
 
 	// Parse circuit breaker positions (address 7)
@@ -31,7 +29,7 @@ func parsePLCData(data []byte, id int) database.PLCData {
 		CircuitBreakers:   parseCircuitBreakers(cbPositions),
 		MVCircuitBreakers: parseMVCircuitBreakers(mvCBPositions),
 		ProtectionRelays:  parseProtectionRelays(relayStatus),
-	}
+	}, nil
 }
 
 // parseCircuitBreakers extracts individual circuit breaker states from register value
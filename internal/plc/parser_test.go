@@ -0,0 +1,19 @@
+package plc
+
+import (
+	"testing"
+)
+
+// FuzzParsePLCData ensures the parser never panics on malformed frames and
+// rejects short input with an error
+func FuzzParsePLCData(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(make([]byte, StatusDataLength*2-1))
+	f.Add(make([]byte, StatusDataLength*2))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := parsePLCData(data, 1)
+		if (len(data) < StatusDataLength*2) != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+	})
+}
@@ -7,10 +7,25 @@ import (
 	"go.uber.org/zap"
 
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/pkg/modbus"
+	"powerkonnekt/ems/pkg/utils"
 )
 
 // pollLoop periodically reads data from the PLC
 func (s *Service) pollLoop() {
+	s.client.OnConnectionChange(func(connected bool) {
+		if connected {
+			s.log.Info("PLC connection established")
+		} else {
+			s.log.Warn("PLC connection lost, reconnecting in the background")
+		}
+	})
+	s.client.StartAutoReconnect(s.ctx, modbus.ReconnectSettings{
+		InitialDelay: s.config.ReconnectDelay,
+		MaxDelay:     s.config.ReconnectMaxDelay,
+		MaxRetries:   s.config.ReconnectMaxRetries,
+	})
+
 	if err := s.client.Connect(s.ctx); err != nil {
 		s.log.Warn("Initial Modbus connection failed", zap.Error(err))
 	}
@@ -27,9 +42,8 @@ func (s *Service) pollLoop() {
 		case <-s.ctx.Done():
 			return
 		case <-timer.C:
-			if !s.client.IsConnected() {
-				s.handleConnectionError()
-			} else {
+			finish := s.pollWatch.Begin()
+			if s.client.IsConnected() {
 				startTime := time.Now()
 				if err := s.readPLCData(); err != nil {
 					s.log.Error("Error reading data", zap.Error(err))
@@ -48,6 +62,7 @@ func (s *Service) pollLoop() {
 						zap.Duration("interval", interval))
 				}
 			}
+			finish()
 
 			// Calculate next aligned time and reset timer
 			nextTick = time.Now().Truncate(interval).Add(interval)
@@ -56,36 +71,6 @@ func (s *Service) pollLoop() {
 	}
 }
 
-// handleConnectionError attempts to reconnect to the PLC
-func (s *Service) handleConnectionError() {
-	s.log.Warn("PLC connection lost, initiating reconnection procedure")
-	s.client.Disconnect()
-
-	reconnectAttempts := 0
-	timer := time.NewTimer(s.config.ReconnectDelay)
-	defer timer.Stop()
-
-	for !s.client.IsConnected() {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-timer.C:
-			reconnectAttempts++
-			if err := s.client.Connect(s.ctx); err != nil {
-				s.log.Error("Failed to reconnect to PLC",
-					zap.Error(err),
-					zap.Int("attempt", reconnectAttempts))
-				timer.Reset(s.config.ReconnectDelay)
-			} else {
-				s.log.Info("Successfully reconnected to PLC",
-					zap.Int("total_attempts", reconnectAttempts),
-					zap.Duration("total_downtime", time.Duration(reconnectAttempts)*s.config.ReconnectDelay))
-				return
-			}
-		}
-	}
-}
-
 // readPLCData reads status data from the PLC
 func (s *Service) readPLCData() error {
 	// Read circuit breaker positions, MV circuit breakers, and protection relays
@@ -95,7 +80,23 @@ func (s *Service) readPLCData() error {
 		return fmt.Errorf("failed to read PLC registers: %w", err)
 	}
 
-	plcData := parsePLCData(data, s.config.ID)
+	plcData, err := parsePLCData(data, s.config.ID)
+	if err != nil {
+		return fmt.Errorf("failed to parse PLC data: %w", err)
+	}
+
+	// Read the transformer tap-changer position where the PLC exposes it
+	if s.config.TapPositionAddr > 0 {
+		tapData, err := s.client.ReadHoldingRegisters(s.ctx, s.config.TapPositionAddr, 1)
+		if err != nil {
+			return fmt.Errorf("failed to read tap position register: %w", err)
+		}
+		if len(tapData) < 2 {
+			return fmt.Errorf("tap position data too short: got %d bytes, want 2", len(tapData))
+		}
+		plcData.TapPosition = utils.FromBytes[int16](tapData[0:2])
+		plcData.TapPositionValid = true
+	}
 
 	s.mutex.Lock()
 	s.lastPLCData = plcData
@@ -104,9 +105,31 @@ func (s *Service) readPLCData() error {
 	// Check for protection relay faults and create alarms
 	s.checkProtectionRelayFaults(plcData)
 
+	// Record tap changes for coordination with the voltage controller
+	s.checkTapPositionChange(plcData)
+
+	// Run the auto-reclose state machine on the fresh breaker positions
+	s.checkAutoReclose(plcData)
+
 	return nil
 }
 
+// checkTapPositionChange records transformer tap-changer position changes
+func (s *Service) checkTapPositionChange(data database.PLCData) {
+	if !data.TapPositionValid {
+		return
+	}
+
+	if s.tapPositionKnown && data.TapPosition != s.previousTapPosition {
+		s.log.Info("Transformer tap position changed",
+			zap.Int16("previous_position", s.previousTapPosition),
+			zap.Int16("new_position", data.TapPosition))
+	}
+
+	s.previousTapPosition = data.TapPosition
+	s.tapPositionKnown = true
+}
+
 // checkProtectionRelayFaults checks for protection relay faults and creates alarms
 func (s *Service) checkProtectionRelayFaults(data database.PLCData) {
 	timestamp := time.Now()
@@ -0,0 +1,230 @@
+package plc
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// Auto-reclose fallbacks for settings left at zero in the configuration
+const (
+	defaultRecloseMaxAttempts = 3
+	defaultRecloseRetryDelay  = 30 * time.Second
+)
+
+// recloseBreaker describes one breaker the auto-reclose logic can operate:
+// how to read its position, which protection relay interlocks it, and how
+// to issue the close command
+type recloseBreaker struct {
+	name       string
+	relayName  string
+	isClosed   func(database.PLCData) bool
+	relayFault func(database.PLCData) bool
+	close      func() error
+}
+
+// recloseState tracks the reclose progress of one breaker. Reclosure is
+// only armed by an observed closed-to-open transition; a breaker found
+// open at startup may be open deliberately and is never touched.
+type recloseState struct {
+	known       bool
+	wasClosed   bool
+	armed       bool
+	lockedOut   bool
+	attempts    int
+	lastAttempt time.Time
+}
+
+// recloseTargets returns the breakers selected for auto-reclose in the
+// configuration
+func (s *Service) recloseTargets() []recloseBreaker {
+	available := map[string]recloseBreaker{
+		"auxiliary": {
+			name:       "auxiliary",
+			relayName:  "MV Aux Transformer Relay",
+			isClosed:   func(d database.PLCData) bool { return d.CircuitBreakers.AuxiliaryCB },
+			relayFault: func(d database.PLCData) bool { return d.ProtectionRelays.AuxTransformerFault },
+			close:      func() error { return s.ControlAuxiliaryCB(true) },
+		},
+		"mv_aux_transformer": {
+			name:       "mv_aux_transformer",
+			relayName:  "MV Aux Transformer Relay",
+			isClosed:   func(d database.PLCData) bool { return d.MVCircuitBreakers.AuxTransformerCB },
+			relayFault: func(d database.PLCData) bool { return d.ProtectionRelays.AuxTransformerFault },
+			close:      func() error { return s.ControlMVAuxTransformerCB(true) },
+		},
+		"transformer1": {
+			name:       "transformer1",
+			relayName:  "Transformer 1 Relay",
+			isClosed:   func(d database.PLCData) bool { return d.MVCircuitBreakers.Transformer1CB },
+			relayFault: func(d database.PLCData) bool { return d.ProtectionRelays.Transformer1Fault },
+			close:      func() error { return s.ControlTransformerCB(1, true) },
+		},
+		"transformer2": {
+			name:       "transformer2",
+			relayName:  "Transformer 2 Relay",
+			isClosed:   func(d database.PLCData) bool { return d.MVCircuitBreakers.Transformer2CB },
+			relayFault: func(d database.PLCData) bool { return d.ProtectionRelays.Transformer2Fault },
+			close:      func() error { return s.ControlTransformerCB(2, true) },
+		},
+		"transformer3": {
+			name:       "transformer3",
+			relayName:  "Transformer 3 Relay",
+			isClosed:   func(d database.PLCData) bool { return d.MVCircuitBreakers.Transformer3CB },
+			relayFault: func(d database.PLCData) bool { return d.ProtectionRelays.Transformer3Fault },
+			close:      func() error { return s.ControlTransformerCB(3, true) },
+		},
+		"transformer4": {
+			name:       "transformer4",
+			relayName:  "Transformer 4 Relay",
+			isClosed:   func(d database.PLCData) bool { return d.MVCircuitBreakers.Transformer4CB },
+			relayFault: func(d database.PLCData) bool { return d.ProtectionRelays.Transformer4Fault },
+			close:      func() error { return s.ControlTransformerCB(4, true) },
+		},
+	}
+
+	var targets []recloseBreaker
+	for _, name := range s.config.AutoReclose.Breakers {
+		if breaker, ok := available[name]; ok {
+			targets = append(targets, breaker)
+		}
+	}
+	return targets
+}
+
+// checkAutoReclose runs the auto-reclose state machine against the latest
+// breaker positions. Called from the poll path after every successful read.
+func (s *Service) checkAutoReclose(data database.PLCData) {
+	cfg := s.config.AutoReclose
+	if !cfg.Enabled {
+		return
+	}
+
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRecloseMaxAttempts
+	}
+	retryDelay := cfg.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = defaultRecloseRetryDelay
+	}
+
+	now := time.Now()
+	for _, breaker := range s.recloseTargets() {
+		state := s.recloseStates[breaker.name]
+		if state == nil {
+			state = &recloseState{}
+			s.recloseStates[breaker.name] = state
+		}
+
+		closed := breaker.isClosed(data)
+
+		if closed {
+			if state.armed && state.attempts > 0 {
+				s.journalReclose(breaker.name, state.attempts, "closed",
+					fmt.Sprintf("breaker closed after %d reclose attempt(s)", state.attempts))
+				s.log.Info("Auto-reclose succeeded",
+					zap.String("breaker", breaker.name),
+					zap.Int("attempts", state.attempts))
+			}
+			if state.lockedOut {
+				s.log.Info("Auto-reclose lockout cleared by manual close",
+					zap.String("breaker", breaker.name))
+			}
+			*state = recloseState{known: true, wasClosed: true}
+			continue
+		}
+
+		// Breaker is open: arm reclosure only on a closed-to-open transition
+		if state.known && state.wasClosed {
+			state.armed = true
+			state.attempts = 0
+			state.lastAttempt = now // first attempt one retry delay after the trip
+			s.log.Warn("Breaker trip detected, auto-reclose armed",
+				zap.String("breaker", breaker.name))
+		}
+		state.known = true
+		state.wasClosed = false
+
+		if !state.armed || state.lockedOut {
+			continue
+		}
+
+		// Interlock: never reclose while the protection relay reports a fault
+		if breaker.relayFault(data) {
+			s.log.Debug("Auto-reclose blocked by protection relay",
+				zap.String("breaker", breaker.name),
+				zap.String("relay", breaker.relayName))
+			continue
+		}
+
+		if state.attempts >= maxAttempts {
+			state.lockedOut = true
+			s.journalReclose(breaker.name, state.attempts, "lockout",
+				fmt.Sprintf("breaker still open after %d reclose attempt(s)", state.attempts))
+			s.submitRecloseLockoutAlarm(breaker.name)
+			s.log.Error("Auto-reclose locked out, manual intervention required",
+				zap.String("breaker", breaker.name),
+				zap.Int("attempts", state.attempts))
+			continue
+		}
+
+		if now.Sub(state.lastAttempt) < retryDelay {
+			continue
+		}
+
+		state.attempts++
+		state.lastAttempt = now
+		s.log.Warn("Attempting automatic reclosure",
+			zap.String("breaker", breaker.name),
+			zap.Int("attempt", state.attempts),
+			zap.Int("max_attempts", maxAttempts))
+
+		if err := breaker.close(); err != nil {
+			s.journalReclose(breaker.name, state.attempts, "failed", err.Error())
+			s.log.Error("Auto-reclose command failed",
+				zap.String("breaker", breaker.name),
+				zap.Int("attempt", state.attempts),
+				zap.Error(err))
+			continue
+		}
+
+		s.journalReclose(breaker.name, state.attempts, "attempt",
+			fmt.Sprintf("close command sent (attempt %d of %d)", state.attempts, maxAttempts))
+	}
+}
+
+// journalReclose records one auto-reclose event in the event journal
+func (s *Service) journalReclose(breaker string, attempt int, result, detail string) {
+	event := database.BreakerRecloseEvent{
+		Timestamp: time.Now(),
+		PLCID:     s.config.ID,
+		Breaker:   breaker,
+		Attempt:   attempt,
+		Result:    result,
+		Detail:    detail,
+	}
+
+	if err := s.influxDB.WriteBreakerRecloseEvent(event); err != nil {
+		s.log.Error("Failed to journal auto-reclose event", zap.Error(err))
+	}
+}
+
+// submitRecloseLockoutAlarm raises a HIGH alarm when a breaker locks out
+func (s *Service) submitRecloseLockoutAlarm(breaker string) {
+	if s.alarmManager == nil {
+		return
+	}
+
+	s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: fmt.Sprintf("PLC_%d_RECLOSE", s.config.ID),
+		AlarmCode: 1,
+		Message:   fmt.Sprintf("Auto-reclose lockout: %s CB", breaker),
+		Severity:  "HIGH",
+		Active:    true,
+	})
+}
@@ -2,6 +2,7 @@ package plc
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"go.uber.org/zap"
@@ -9,6 +10,8 @@ import (
 	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
+	"powerkonnekt/ems/pkg/loopwatch"
 	"powerkonnekt/ems/pkg/modbus"
 )
 
@@ -17,7 +20,9 @@ type Service struct {
 	config       config.PLCConfig
 	influxDB     *database.InfluxDB
 	alarmManager *alarm.Manager
+	telemetry    *telemetry.Manager
 	client       *modbus.Client
+	pollWatch    *loopwatch.Loop
 	ctx          context.Context
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
@@ -28,11 +33,24 @@ type Service struct {
 	mutex               sync.RWMutex
 	lastPLCData         database.PLCData
 	previousRelayStates map[string]bool
+	previousTapPosition int16
+	tapPositionKnown    bool
+	recloseStates       map[string]*recloseState
 }
 
 // NewService creates a new PLC service
-func NewService(cfg config.PLCConfig, influxDB *database.InfluxDB, alarmManager *alarm.Manager, logger *zap.Logger) *Service {
-	client := modbus.NewClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout)
+func NewService(cfg config.PLCConfig, influxDB *database.InfluxDB, alarmManager *alarm.Manager, telemetryManager *telemetry.Manager, logger *zap.Logger) *Service {
+	var client *modbus.Client
+	if cfg.Simulate {
+		client = modbus.NewSimulatedClient(cfg.SlaveID, cfg.Timeout)
+	} else {
+		client = modbus.NewSecureClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout, cfg.BindAddress, modbus.TLSSettings{
+			Enabled:  cfg.TLS.Enabled,
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.CAFile,
+		})
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Create service-specific logger
@@ -47,17 +65,23 @@ func NewService(cfg config.PLCConfig, influxDB *database.InfluxDB, alarmManager
 		config:              cfg,
 		influxDB:            influxDB,
 		alarmManager:        alarmManager,
+		telemetry:           telemetryManager,
 		client:              client,
+		pollWatch:           loopwatch.NewLoop(fmt.Sprintf("plc_%d_poll", cfg.ID), cfg.PollInterval),
 		ctx:                 ctx,
 		cancel:              cancel,
 		log:                 serviceLogger,
 		dataUpdateChan:      make(chan struct{}, 1),
 		previousRelayStates: make(map[string]bool),
+		recloseStates:       make(map[string]*recloseState),
 	}
 }
 
 // Start starts the PLC service
 func (s *Service) Start() error {
+	if s.config.Simulate {
+		s.wg.Go(s.simulateLoop)
+	}
 	s.wg.Go(s.pollLoop)
 	s.wg.Go(s.persistenceLoop)
 
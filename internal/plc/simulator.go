@@ -0,0 +1,71 @@
+package plc
+
+import (
+	"time"
+)
+
+const simStepInterval = time.Second
+
+// Control register to status bit mapping of the simulated switchgear
+var simControlledBreakers = []struct {
+	controlAddr uint16
+	statusAddr  uint16
+	bit         uint8
+}{
+	{AuxCBControlAddr, CircuitBreakerPositionsAddr, BitAuxiliaryCB},
+	{MVAuxTransformerCBAddr, MVCircuitBreakerAddr, BitMVAuxTransformerCB},
+	{Transformer1CBControlAddr, MVCircuitBreakerAddr, BitTransformer1CB},
+	{Transformer2CBControlAddr, MVCircuitBreakerAddr, BitTransformer2CB},
+	{Transformer3CBControlAddr, MVCircuitBreakerAddr, BitTransformer3CB},
+	{Transformer4CBControlAddr, MVCircuitBreakerAddr, BitTransformer4CB},
+	{AutoproducerCBControlAddr, MVCircuitBreakerAddr, BitAutoproducerCB},
+}
+
+// simulateLoop drives the built-in switchgear simulator against the
+// in-memory register bank of the simulated MODBUS client: all breakers
+// start closed, no protection relays trip, and control commands are applied
+// to the position bits like the PLC would
+func (s *Service) simulateLoop() {
+	s.log.Info("PLC simulator active, serving the in-memory device model")
+
+	// All breakers closed, relays clear
+	s.client.SimSetRegisters(CircuitBreakerPositionsAddr, 0x01FF, 0x003F, 0)
+	if s.config.TapPositionAddr > 0 {
+		s.client.SimSetRegister(s.config.TapPositionAddr, 5)
+	}
+
+	ticker := time.NewTicker(simStepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.simulateControls()
+		}
+	}
+}
+
+// simulateControls consumes pending breaker control commands and moves the
+// corresponding position bits
+func (s *Service) simulateControls() {
+	for _, breaker := range simControlledBreakers {
+		command := s.client.SimRegister(breaker.controlAddr)
+		if command == ControlNoOperation {
+			continue
+		}
+
+		positions := s.client.SimRegister(breaker.statusAddr)
+		switch command {
+		case ControlClose:
+			positions |= 1 << breaker.bit
+		case ControlOpen:
+			positions &^= 1 << breaker.bit
+		}
+		s.client.SimSetRegister(breaker.statusAddr, positions)
+
+		// The PLC resets the control register once the command is executed
+		s.client.SimSetRegister(breaker.controlAddr, ControlNoOperation)
+	}
+}
@@ -0,0 +1,117 @@
+// Package pollgroup isolates the register blocks of a device poll loop
+// from each other. Each group keeps its own schedule and consecutive
+// failure counter, and a persistently failing optional group is disabled
+// automatically so it cannot spam errors or delay the remaining groups.
+package pollgroup
+
+import (
+	"time"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// DefaultFailureThreshold is the number of consecutive failures after which
+// an optional group is disabled when no threshold is configured
+const DefaultFailureThreshold = 10
+
+// Settings describe one poll group of a device
+type Settings struct {
+	// Name identifies the group in configuration, logs and alarms
+	Name string
+
+	// Optional groups are disabled automatically after persistent failures;
+	// required groups keep being retried
+	Optional bool
+
+	// Interval between reads of this group; 0 runs it every poll cycle
+	Interval time.Duration
+
+	// FailureThreshold is the number of consecutive failures before an
+	// optional group is disabled (0 = DefaultFailureThreshold)
+	FailureThreshold int
+}
+
+// Group tracks the schedule and failure state of one register block
+type Group struct {
+	settings Settings
+	lastRun  time.Time
+	failures int
+	disabled bool
+}
+
+// New creates a poll group from its settings
+func New(settings Settings) *Group {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = DefaultFailureThreshold
+	}
+	return &Group{settings: settings}
+}
+
+// Build creates the poll groups of a device from its built-in defaults with
+// the configured per-group overrides applied; overrides naming unknown
+// groups are ignored
+func Build(defaults []Settings, overrides []config.PollGroupConfig) map[string]*Group {
+	groups := make(map[string]*Group, len(defaults))
+	for _, settings := range defaults {
+		for _, override := range overrides {
+			if override.Name != settings.Name {
+				continue
+			}
+			settings.Optional = override.Optional
+			if override.Interval > 0 {
+				settings.Interval = override.Interval
+			}
+			if override.FailureThreshold > 0 {
+				settings.FailureThreshold = override.FailureThreshold
+			}
+		}
+		groups[settings.Name] = New(settings)
+	}
+	return groups
+}
+
+// Name returns the group name
+func (g *Group) Name() string {
+	return g.settings.Name
+}
+
+// Due reports whether the group should run this cycle
+func (g *Group) Due(now time.Time) bool {
+	if g.disabled {
+		return false
+	}
+	if g.settings.Interval <= 0 {
+		return true
+	}
+	return now.Sub(g.lastRun) >= g.settings.Interval
+}
+
+// Disabled reports whether the group has been disabled after persistent
+// failures
+func (g *Group) Disabled() bool {
+	return g.disabled
+}
+
+// Failures returns the current consecutive failure count
+func (g *Group) Failures() int {
+	return g.failures
+}
+
+// RecordSuccess resets the failure counter after a successful read
+func (g *Group) RecordSuccess(now time.Time) {
+	g.lastRun = now
+	g.failures = 0
+}
+
+// RecordFailure counts a failed read and reports whether the group was
+// disabled by this failure, so the caller can alarm exactly once
+func (g *Group) RecordFailure(now time.Time) bool {
+	g.lastRun = now
+	g.failures++
+
+	if g.settings.Optional && !g.disabled && g.failures >= g.settings.FailureThreshold {
+		g.disabled = true
+		return true
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package powerquality
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/analyzer/ion7400"
+	"powerkonnekt/ems/internal/database"
+)
+
+// Module provides the power quality monitoring service to the Fx application
+var Module = fx.Module("powerquality",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides a power quality monitoring service
+// instance
+func ProvideService(
+	analyzer *ion7400.Service,
+	alarmManager *alarm.Manager,
+	influxDB *database.InfluxDB,
+	logger *zap.Logger,
+) *Service {
+	return NewService(analyzer, alarmManager, influxDB, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the power quality
+// monitoring service
+func RegisterLifecycle(lc fx.Lifecycle, service *Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,268 @@
+// Package powerquality monitors the power quality at the network connection
+// point against EN 50160. It samples the grid analyzer voltage THD and
+// computes the voltage unbalance, aggregates both over the 10-minute
+// observation windows the standard prescribes, persists the window
+// statistics and raises alarms on limit violations.
+package powerquality
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/analyzer/ion7400"
+	"powerkonnekt/ems/internal/database"
+)
+
+// sampleInterval is the cadence of the power quality sampling
+const sampleInterval = time.Second
+
+// windowLength is the EN 50160 observation window
+const windowLength = 10 * time.Minute
+
+// EN 50160 limits for the 10-minute mean values at LV/MV connection points
+const (
+	thdVoltageLimit = 8.0 // % of the fundamental
+	unbalanceLimit  = 2.0 // % negative sequence approximation
+)
+
+// Alarm identification for power quality violations
+const (
+	pqAlarmType        = "POWER_QUALITY"
+	THDAlarmCode       = 9300
+	UnbalanceAlarmCode = 9301
+)
+
+// windowStats accumulates one observation window
+type windowStats struct {
+	start        time.Time
+	samples      int
+	thdSum       float64
+	thdMax       float32
+	unbalanceSum float64
+	unbalanceMax float32
+}
+
+// Status is the current power quality state: the running window and the last
+// completed one
+type Status struct {
+	WindowStart    time.Time                  `json:"window_start"`
+	WindowSamples  int                        `json:"window_samples"`
+	THDVoltage     float32                    `json:"thd_voltage"` // latest, worst phase
+	Unbalance      float32                    `json:"unbalance"`   // latest
+	LastWindow     *database.PowerQualityData `json:"last_window,omitempty"`
+	THDLimit       float32                    `json:"thd_limit"`
+	UnbalanceLimit float32                    `json:"unbalance_limit"`
+}
+
+// Service runs the power quality monitoring
+type Service struct {
+	analyzer     *ion7400.Service
+	alarmManager *alarm.Manager
+	influxDB     *database.InfluxDB
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	log          *zap.Logger
+
+	mutex          sync.RWMutex
+	window         windowStats
+	lastTHD        float32
+	lastUnbalance  float32
+	lastWindow     *database.PowerQualityData
+	thdAlarm       bool
+	unbalanceAlarm bool
+}
+
+// NewService creates the power quality monitoring service
+func NewService(analyzer *ion7400.Service, alarmManager *alarm.Manager, influxDB *database.InfluxDB, logger *zap.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Service{
+		analyzer:     analyzer,
+		alarmManager: alarmManager,
+		influxDB:     influxDB,
+		ctx:          ctx,
+		cancel:       cancel,
+		log: logger.With(
+			zap.String("service", "powerquality"),
+		),
+	}
+}
+
+// Start starts the monitoring loop
+func (s *Service) Start() error {
+	s.wg.Go(s.sampleLoop)
+
+	s.log.Info("Power quality monitoring started",
+		zap.Duration("window", windowLength),
+		zap.Float64("thd_limit", thdVoltageLimit),
+		zap.Float64("unbalance_limit", unbalanceLimit))
+
+	return nil
+}
+
+// Stop stops the monitoring loop
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	s.log.Info("Power quality monitoring stopped")
+}
+
+// sampleLoop samples the analyzer and closes the observation windows
+func (s *Service) sampleLoop() {
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.sample(now)
+		}
+	}
+}
+
+// sample takes one power quality sample and closes the window when the
+// 10-minute boundary is crossed
+func (s *Service) sample(now time.Time) {
+	windowStart := now.Truncate(windowLength)
+
+	s.mutex.Lock()
+	if !s.window.start.Equal(windowStart) {
+		if s.window.samples > 0 {
+			s.closeWindow()
+		}
+		s.window = windowStats{start: windowStart}
+	}
+	s.mutex.Unlock()
+
+	if !s.analyzer.IsConnected() {
+		return
+	}
+
+	data := s.analyzer.GetLatestData()
+	thd := max3(data.THDVoltageL1, data.THDVoltageL2, data.THDVoltageL3)
+	unbalance := voltageUnbalance(data.VoltageL1L2, data.VoltageL2L3, data.VoltageL3L1)
+
+	s.mutex.Lock()
+	s.lastTHD = thd
+	s.lastUnbalance = unbalance
+	s.window.samples++
+	s.window.thdSum += float64(thd)
+	s.window.unbalanceSum += float64(unbalance)
+	if thd > s.window.thdMax {
+		s.window.thdMax = thd
+	}
+	if unbalance > s.window.unbalanceMax {
+		s.window.unbalanceMax = unbalance
+	}
+	s.mutex.Unlock()
+}
+
+// closeWindow finalizes the running window, persists the statistics and
+// updates the violation alarms; the caller holds the mutex
+func (s *Service) closeWindow() {
+	result := database.PowerQualityData{
+		Timestamp:      s.window.start.Add(windowLength),
+		THDVoltageMean: float32(s.window.thdSum / float64(s.window.samples)),
+		THDVoltageMax:  s.window.thdMax,
+		UnbalanceMean:  float32(s.window.unbalanceSum / float64(s.window.samples)),
+		UnbalanceMax:   s.window.unbalanceMax,
+		Samples:        s.window.samples,
+	}
+	result.THDCompliant = result.THDVoltageMean <= thdVoltageLimit
+	result.UnbalanceCompliant = result.UnbalanceMean <= unbalanceLimit
+	s.lastWindow = &result
+
+	if err := s.influxDB.WritePowerQualityData(result); err != nil {
+		s.log.Error("Failed to write power quality data", zap.Error(err))
+	}
+
+	s.updateAlarm(&s.thdAlarm, !result.THDCompliant, THDAlarmCode,
+		"Voltage THD 10-minute mean exceeds EN 50160 limit")
+	s.updateAlarm(&s.unbalanceAlarm, !result.UnbalanceCompliant, UnbalanceAlarmCode,
+		"Voltage unbalance 10-minute mean exceeds EN 50160 limit")
+
+	if !result.THDCompliant || !result.UnbalanceCompliant {
+		s.log.Warn("Power quality window non-compliant",
+			zap.Float32("thd_voltage_mean", result.THDVoltageMean),
+			zap.Float32("unbalance_mean", result.UnbalanceMean))
+	}
+}
+
+// updateAlarm raises or clears a violation alarm on state transitions
+func (s *Service) updateAlarm(state *bool, active bool, code uint16, message string) {
+	if *state == active {
+		return
+	}
+	*state = active
+
+	s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: pqAlarmType,
+		AlarmCode: code,
+		Message:   message,
+		Severity:  "MEDIUM",
+		Active:    active,
+	})
+}
+
+// GetStatus returns the current power quality state
+func (s *Service) GetStatus() Status {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return Status{
+		WindowStart:    s.window.start,
+		WindowSamples:  s.window.samples,
+		THDVoltage:     s.lastTHD,
+		Unbalance:      s.lastUnbalance,
+		LastWindow:     s.lastWindow,
+		THDLimit:       thdVoltageLimit,
+		UnbalanceLimit: unbalanceLimit,
+	}
+}
+
+// voltageUnbalance approximates the negative sequence voltage unbalance in
+// percent from the line-to-line voltages (NEMA/IEC approximation: maximum
+// deviation from the average over the average)
+func voltageUnbalance(l1l2, l2l3, l3l1 float32) float32 {
+	average := (l1l2 + l2l3 + l3l1) / 3
+	if average <= 0 {
+		return 0
+	}
+
+	deviation := maxDeviation(l1l2, average)
+	if d := maxDeviation(l2l3, average); d > deviation {
+		deviation = d
+	}
+	if d := maxDeviation(l3l1, average); d > deviation {
+		deviation = d
+	}
+
+	return deviation / average * 100
+}
+
+// maxDeviation returns the absolute deviation of a value from the average
+func maxDeviation(value, average float32) float32 {
+	if value >= average {
+		return value - average
+	}
+	return average - value
+}
+
+// max3 returns the largest of three values
+func max3(a, b, c float32) float32 {
+	if b > a {
+		a = b
+	}
+	if c > a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,49 @@
+package recorder
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/fcr"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/plc"
+	"powerkonnekt/ems/internal/site"
+)
+
+// Module provides the flight recorder to the Fx application
+var Module = fx.Module("recorder",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides a flight recorder service instance
+func ProvideService(
+	pcsManager *pcs.Manager,
+	bmsManager *bms.Manager,
+	plcManager *plc.Manager,
+	siteService *site.Service,
+	fcrController *fcr.Controller,
+	alarmManager *alarm.Manager,
+	postgreSQL *database.PostgreSQL,
+	logger *zap.Logger,
+) *Service {
+	return NewService(pcsManager, bmsManager, plcManager, siteService, fcrController, alarmManager, postgreSQL, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the flight recorder
+func RegisterLifecycle(lc fx.Lifecycle, service *Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+}
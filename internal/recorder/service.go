@@ -0,0 +1,301 @@
+// Package recorder keeps a continuously running high-resolution ring of the
+// key electrical signals — grid frequency, site power, per-PCS power, SOC
+// and breaker states — and freezes it around serious events. When a HIGH
+// alarm becomes active (which includes the PCS emergency stop), the window
+// from before the trigger to after it is persisted as a flight recording
+// that can be downloaded for incident forensics.
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/fcr"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/plc"
+	"powerkonnekt/ems/internal/site"
+	"powerkonnekt/ems/pkg/cache"
+)
+
+const (
+	// sampleInterval is the flight recorder resolution
+	sampleInterval = 250 * time.Millisecond
+
+	// preTriggerWindow and postTriggerWindow bound the persisted recording
+	// around the trigger
+	preTriggerWindow  = 60 * time.Second
+	postTriggerWindow = 60 * time.Second
+
+	// maxRecordings bounds the number of recordings kept in the database
+	maxRecordings = 50
+
+	// listLimit is how many recordings the API listing returns
+	listLimit = 100
+)
+
+// ringCapacity holds the full pre- and post-trigger window with some slack
+// for timing jitter
+const ringCapacity = int((preTriggerWindow+postTriggerWindow)/sampleInterval) + 16
+
+// Sample is one flight recorder snapshot of the key signals
+type Sample struct {
+	Timestamp   time.Time                               `json:"timestamp"`
+	FrequencyHz float32                                 `json:"frequency_hz"`
+	SitePowerKW float32                                 `json:"site_power_kw"`
+	PCSPowerKW  map[int]float32                         `json:"pcs_power_kw"`
+	BMSSOC      map[int]float32                         `json:"bms_soc"`
+	Breakers    map[int]database.CircuitBreakerStatus   `json:"breakers"`
+	MVBreakers  map[int]database.MVCircuitBreakerStatus `json:"mv_breakers"`
+}
+
+// Service runs the flight recorder
+type Service struct {
+	pcsManager    *pcs.Manager
+	bmsManager    *bms.Manager
+	plcManager    *plc.Manager
+	siteService   *site.Service
+	fcrController *fcr.Controller
+	alarmManager  *alarm.Manager
+	postgreSQL    *database.PostgreSQL
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+	log           *zap.Logger
+
+	ring *cache.Ring[Sample]
+
+	mutex           sync.Mutex
+	highAlarmKeys   map[string]bool // Active HIGH alarms seen in the last scan
+	pendingTrigger  string          // Reason of the recording being captured
+	pendingAt       time.Time       // Trigger time of the pending recording
+	pendingSeverity string
+}
+
+// NewService creates a new flight recorder service
+func NewService(pcsManager *pcs.Manager, bmsManager *bms.Manager, plcManager *plc.Manager, siteService *site.Service, fcrController *fcr.Controller, alarmManager *alarm.Manager, postgreSQL *database.PostgreSQL, logger *zap.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	serviceLogger := logger.With(
+		zap.String("component", "flight_recorder"),
+	)
+
+	return &Service{
+		pcsManager:    pcsManager,
+		bmsManager:    bmsManager,
+		plcManager:    plcManager,
+		siteService:   siteService,
+		fcrController: fcrController,
+		alarmManager:  alarmManager,
+		postgreSQL:    postgreSQL,
+		ctx:           ctx,
+		cancel:        cancel,
+		log:           serviceLogger,
+		ring:          cache.NewRing[Sample]("flight_recorder", ringCapacity),
+		highAlarmKeys: make(map[string]bool),
+	}
+}
+
+// Start starts the flight recorder sampling loop
+func (s *Service) Start() error {
+	s.wg.Go(s.sampleLoop)
+	s.log.Info("Flight recorder started",
+		zap.Duration("sample_interval", sampleInterval),
+		zap.Duration("pre_trigger_window", preTriggerWindow),
+		zap.Duration("post_trigger_window", postTriggerWindow))
+	return nil
+}
+
+// Stop stops the flight recorder
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	s.log.Info("Flight recorder stopped")
+}
+
+// sampleLoop continuously records samples and watches the triggers
+func (s *Service) sampleLoop() {
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.ring.Append(s.sample())
+			s.checkTriggers()
+		}
+	}
+}
+
+// sample captures one snapshot of the key signals
+func (s *Service) sample() Sample {
+	sample := Sample{
+		Timestamp:   time.Now(),
+		SitePowerKW: s.siteService.GetBalance().NetPowerKW,
+		PCSPowerKW:  make(map[int]float32),
+		BMSSOC:      make(map[int]float32),
+		Breakers:    make(map[int]database.CircuitBreakerStatus),
+		MVBreakers:  make(map[int]database.MVCircuitBreakerStatus),
+	}
+
+	if measurement := s.fcrController.GetLastFrequency(); measurement.Valid {
+		sample.FrequencyHz = measurement.Frequency
+	}
+
+	for id, service := range s.pcsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		sample.PCSPowerKW[id] = float32(service.GetLatestPCSGridData().LVGridActivePower)
+	}
+
+	for id, service := range s.bmsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		sample.BMSSOC[id] = service.GetLatestBMSData().SOC
+	}
+
+	for id, service := range s.plcManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		data := service.GetLatestPLCData()
+		sample.Breakers[id] = data.CircuitBreakers
+		sample.MVBreakers[id] = data.MVCircuitBreakers
+	}
+
+	return sample
+}
+
+// checkTriggers freezes the recorder on newly active HIGH alarms and
+// persists a pending recording once its post-trigger window has elapsed.
+// While a capture is pending, further triggers are folded into it — the
+// alarm burst of one incident yields one recording.
+func (s *Service) checkTriggers() {
+	active := s.alarmManager.GetActiveAlarmsBySeverity("HIGH")
+
+	now := time.Now()
+	seen := make(map[string]bool, len(active))
+	trigger := ""
+	for _, entry := range active {
+		key := fmt.Sprintf("%s_%d", entry.AlarmType, entry.AlarmCode)
+		seen[key] = true
+		if trigger == "" && !s.highAlarmKeys[key] {
+			trigger = entry.Message
+		}
+	}
+
+	s.mutex.Lock()
+	s.highAlarmKeys = seen
+
+	if trigger != "" && s.pendingTrigger == "" {
+		s.pendingTrigger = trigger
+		s.pendingSeverity = "HIGH"
+		s.pendingAt = now
+		s.mutex.Unlock()
+
+		s.log.Warn("Flight recorder triggered",
+			zap.String("reason", trigger))
+		return
+	}
+
+	done := s.pendingTrigger != "" && now.Sub(s.pendingAt) >= postTriggerWindow
+	reason := s.pendingTrigger
+	severity := s.pendingSeverity
+	triggeredAt := s.pendingAt
+	if done {
+		s.pendingTrigger = ""
+		s.pendingSeverity = ""
+	}
+	s.mutex.Unlock()
+
+	if done {
+		s.persistRecording(reason, severity, triggeredAt)
+	}
+}
+
+// persistRecording freezes the window around the trigger and stores it
+func (s *Service) persistRecording(reason, severity string, triggeredAt time.Time) {
+	var window []Sample
+	for _, sample := range s.ring.Snapshot() {
+		if sample.Timestamp.Before(triggeredAt.Add(-preTriggerWindow)) {
+			continue
+		}
+		if sample.Timestamp.After(triggeredAt.Add(postTriggerWindow)) {
+			continue
+		}
+		window = append(window, sample)
+	}
+
+	payload, err := json.Marshal(window)
+	if err != nil {
+		s.log.Error("Failed to encode flight recording", zap.Error(err))
+		return
+	}
+
+	if !s.postgreSQL.Available() {
+		s.log.Warn("Flight recording dropped, persistence unavailable",
+			zap.String("reason", reason))
+		return
+	}
+
+	record := database.FlightRecordingRecord{
+		TriggerReason: reason,
+		Severity:      severity,
+		TriggeredAt:   triggeredAt,
+		Samples:       string(payload),
+		CreatedAt:     time.Now(),
+	}
+	if err := s.postgreSQL.SaveFlightRecording(&record); err != nil {
+		s.log.Error("Failed to persist flight recording", zap.Error(err))
+		return
+	}
+
+	if err := s.postgreSQL.PruneFlightRecordings(maxRecordings); err != nil {
+		s.log.Error("Failed to prune flight recordings", zap.Error(err))
+	}
+
+	s.log.Info("Flight recording persisted",
+		zap.Uint("id", record.ID),
+		zap.String("reason", reason),
+		zap.Time("triggered_at", triggeredAt),
+		zap.Int("samples", len(window)))
+}
+
+// ListRecordings lists the stored flight recordings without their samples
+func (s *Service) ListRecordings() ([]database.FlightRecordingRecord, error) {
+	if !s.postgreSQL.Available() {
+		return nil, fmt.Errorf("persistence is unavailable")
+	}
+	return s.postgreSQL.GetFlightRecordings(listLimit)
+}
+
+// GetRecording returns one stored flight recording with its samples decoded
+func (s *Service) GetRecording(id uint) (database.FlightRecordingRecord, []Sample, error) {
+	if !s.postgreSQL.Available() {
+		return database.FlightRecordingRecord{}, nil, fmt.Errorf("persistence is unavailable")
+	}
+
+	record, err := s.postgreSQL.GetFlightRecording(id)
+	if err != nil {
+		return database.FlightRecordingRecord{}, nil, err
+	}
+
+	var samples []Sample
+	if err := json.Unmarshal([]byte(record.Samples), &samples); err != nil {
+		return database.FlightRecordingRecord{}, nil, fmt.Errorf("failed to decode flight recording %d: %w", id, err)
+	}
+	record.Samples = ""
+
+	return record, samples, nil
+}
@@ -0,0 +1,42 @@
+// Package registerdoc defines the structure of the generated Modbus register
+// documentation. Each device package describes its own register map from the
+// address constants in its protocol definition, so the emitted point list
+// cannot drift from what the code actually reads and writes.
+package registerdoc
+
+// Register access direction from the EMS point of view
+const (
+	DirectionRead  = "read"
+	DirectionWrite = "write"
+)
+
+// Point documents one individually addressed register
+type Point struct {
+	Address     int    `json:"address"`
+	Name        string `json:"name"`
+	Unit        string `json:"unit,omitempty"`
+	Scale       string `json:"scale,omitempty"`
+	Direction   string `json:"direction"`
+	Description string `json:"description,omitempty"`
+}
+
+// Block documents one contiguous register range. Bulk telemetry blocks are
+// documented at block granularity (the field layout lives in the parser);
+// individually named registers are listed as points.
+type Block struct {
+	Name        string  `json:"name"`
+	Table       string  `json:"table"` // holding or input
+	StartAddr   int     `json:"start_addr"`
+	Length      int     `json:"length,omitempty"`
+	UnitOffset  int     `json:"unit_offset,omitempty"` // address stride between device units
+	Direction   string  `json:"direction"`
+	Description string  `json:"description,omitempty"`
+	Points      []Point `json:"points,omitempty"`
+}
+
+// Device groups the register blocks of one device protocol
+type Device struct {
+	Device      string  `json:"device"`
+	Description string  `json:"description,omitempty"`
+	Blocks      []Block `json:"blocks"`
+}
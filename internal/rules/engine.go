@@ -0,0 +1,398 @@
+// Package rules embeds a sandboxed automation rule engine for site-specific
+// logic that does not belong in Go code. Site engineers define boolean CEL
+// expressions over the live telemetry in the configuration; the engine
+// evaluates them periodically and executes guarded actions — alarms or power
+// commands routed through the normal setpoint arbitration — under a global
+// command rate limit.
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cel.dev/cel-go/cel"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/site"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// RuleAlarmCodeBase is the alarm code of the first configured alarm rule;
+// subsequent rules count up from it
+const RuleAlarmCodeBase = 9100
+
+// ruleAlarmType groups rule-generated alarms in the alarm list
+const ruleAlarmType = "RULES"
+
+// compiledRule is one configured rule with its compiled CEL program
+type compiledRule struct {
+	config  config.RuleConfig
+	program cel.Program
+
+	active    bool // last evaluation result
+	fired     uint64
+	lastFired time.Time
+	lastError string
+}
+
+// RuleState describes one rule for diagnostics
+type RuleState struct {
+	Name       string    `json:"name"`
+	Expression string    `json:"expression"`
+	Action     string    `json:"action"`
+	Active     bool      `json:"active"`
+	Fired      uint64    `json:"fired"`
+	LastFired  time.Time `json:"last_fired"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// Engine evaluates the configured automation rules against the live
+// telemetry
+type Engine struct {
+	config          config.RulesConfig
+	bmsManager      *bms.Manager
+	pcsManager      *pcs.Manager
+	windFarmManager *windfarm.Manager
+	siteService     *site.Service
+	controlLogic    *control.Logic
+	alarmManager    *alarm.Manager
+	ctx             context.Context
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	log             *zap.Logger
+
+	mutex        sync.RWMutex
+	rules        []*compiledRule
+	commandTimes []time.Time // sliding window for the command rate limit
+}
+
+// NewEngine compiles the configured rules and creates the engine; a rule
+// that does not compile fails startup so broken rules cannot go unnoticed
+func NewEngine(cfg config.RulesConfig, bmsManager *bms.Manager, pcsManager *pcs.Manager, windFarmManager *windfarm.Manager, siteService *site.Service, controlLogic *control.Logic, alarmManager *alarm.Manager, logger *zap.Logger) (*Engine, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	engine := &Engine{
+		config:          cfg,
+		bmsManager:      bmsManager,
+		pcsManager:      pcsManager,
+		windFarmManager: windFarmManager,
+		siteService:     siteService,
+		controlLogic:    controlLogic,
+		alarmManager:    alarmManager,
+		ctx:             ctx,
+		cancel:          cancel,
+		log: logger.With(
+			zap.String("service", "rules"),
+		),
+	}
+
+	if cfg.Enabled {
+		env, err := newEnvironment()
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create rule environment: %w", err)
+		}
+
+		for _, ruleCfg := range cfg.Rules {
+			program, err := compileRule(env, ruleCfg)
+			if err != nil {
+				cancel()
+				return nil, fmt.Errorf("failed to compile rule %s: %w", ruleCfg.Name, err)
+			}
+			engine.rules = append(engine.rules, &compiledRule{
+				config:  ruleCfg,
+				program: program,
+			})
+		}
+	}
+
+	return engine, nil
+}
+
+// newEnvironment declares the telemetry variables available to rule
+// expressions
+func newEnvironment() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("mode", cel.StringType),
+		cel.Variable("site", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("bms", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("pcs", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("wind", cel.MapType(cel.StringType, cel.DynType)),
+	)
+}
+
+// compileRule compiles one rule expression and checks that it yields a
+// boolean
+func compileRule(env *cel.Env, cfg config.RuleConfig) (cel.Program, error) {
+	ast, issues := env.Compile(cfg.Expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("expression must evaluate to a boolean, got %s", ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// Start starts the evaluation loop when the engine is enabled
+func (e *Engine) Start() error {
+	if !e.config.Enabled {
+		e.log.Info("Rule engine disabled")
+		return nil
+	}
+
+	e.wg.Go(e.evalLoop)
+
+	e.log.Info("Rule engine started",
+		zap.Int("rules", len(e.rules)),
+		zap.Duration("eval_interval", e.config.EvalInterval))
+
+	return nil
+}
+
+// Stop stops the evaluation loop
+func (e *Engine) Stop() {
+	e.cancel()
+	e.wg.Wait()
+	if e.config.Enabled {
+		e.log.Info("Rule engine stopped")
+	}
+}
+
+// evalLoop periodically evaluates every rule
+func (e *Engine) evalLoop() {
+	ticker := time.NewTicker(e.config.EvalInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.evaluateAll(now)
+		}
+	}
+}
+
+// evaluateAll builds the telemetry snapshot once and evaluates every rule
+// against it
+func (e *Engine) evaluateAll(now time.Time) {
+	input := e.buildInput()
+
+	for i, rule := range e.rules {
+		e.evaluate(i, rule, input, now)
+	}
+}
+
+// buildInput assembles the telemetry snapshot the expressions are evaluated
+// against; device maps are keyed by the device ID as a string
+func (e *Engine) buildInput() map[string]interface{} {
+	balance := e.siteService.GetBalance()
+
+	bmsData := map[string]interface{}{}
+	for id, service := range e.bmsManager.GetAllServices() {
+		data := service.GetLatestBMSData()
+		bmsData[fmt.Sprintf("%d", id)] = map[string]interface{}{
+			"connected":            service.IsConnected(),
+			"soc":                  float64(data.SOC),
+			"soh":                  float64(data.SOH),
+			"power":                float64(data.Power),
+			"max_cell_temperature": float64(data.MaxCellTemperature),
+		}
+	}
+
+	pcsData := map[string]interface{}{}
+	for id, service := range e.pcsManager.GetAllServices() {
+		state := service.GetCommandState()
+		pcsData[fmt.Sprintf("%d", id)] = map[string]interface{}{
+			"connected":     service.IsConnected(),
+			"power_command": float64(state.ActivePowerCommand),
+		}
+	}
+
+	windData := map[string]interface{}{
+		"active_power_mw": float64(e.windFarmManager.GetTotalActivePower()),
+		"wind_speed":      float64(e.windFarmManager.GetAverageWindSpeed()),
+		"online":          e.windFarmManager.AreAllFCUsOnline(),
+	}
+
+	siteData := map[string]interface{}{
+		"net_power_kw":      float64(balance.NetPowerKW),
+		"bess_power_kw":     float64(balance.BESSPowerKW),
+		"wind_power_kw":     float64(balance.WindPowerKW),
+		"import_energy_kwh": balance.ImportEnergyKWh,
+		"export_energy_kwh": balance.ExportEnergyKWh,
+	}
+
+	return map[string]interface{}{
+		"mode": e.controlLogic.GetMode(),
+		"site": siteData,
+		"bms":  bmsData,
+		"pcs":  pcsData,
+		"wind": windData,
+	}
+}
+
+// evaluate runs one rule and executes its action on edges
+func (e *Engine) evaluate(index int, rule *compiledRule, input map[string]interface{}, now time.Time) {
+	result, _, err := rule.program.Eval(input)
+
+	e.mutex.Lock()
+	if err != nil {
+		if rule.lastError == "" {
+			e.log.Error("Rule evaluation failed",
+				zap.String("rule", rule.config.Name),
+				zap.Error(err))
+		}
+		rule.lastError = err.Error()
+		e.mutex.Unlock()
+		return
+	}
+	rule.lastError = ""
+
+	active, ok := result.Value().(bool)
+	if !ok {
+		e.mutex.Unlock()
+		return
+	}
+
+	changed := active != rule.active
+	rule.active = active
+	e.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	switch rule.config.Action {
+	case "alarm":
+		e.submitRuleAlarm(index, rule, active)
+	case "set_power":
+		if active {
+			e.firePowerCommand(rule, now)
+		}
+	}
+}
+
+// submitRuleAlarm raises or clears the alarm of an alarm rule
+func (e *Engine) submitRuleAlarm(index int, rule *compiledRule, active bool) {
+	if active {
+		e.mutex.Lock()
+		rule.fired++
+		rule.lastFired = time.Now()
+		e.mutex.Unlock()
+	}
+
+	message := rule.config.Message
+	if message == "" {
+		message = fmt.Sprintf("Rule %s triggered", rule.config.Name)
+	}
+	severity := rule.config.Severity
+	if severity == "" {
+		severity = "MEDIUM"
+	}
+
+	e.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: ruleAlarmType,
+		AlarmCode: uint16(RuleAlarmCodeBase + index),
+		Message:   message,
+		Severity:  severity,
+		Active:    active,
+	})
+}
+
+// firePowerCommand executes the power action of a rule on its rising edge,
+// guarded by the per-rule cooldown and the global command rate limit; the
+// command goes through the normal setpoint arbitration so higher-priority
+// sources and site limits always win
+func (e *Engine) firePowerCommand(rule *compiledRule, now time.Time) {
+	e.mutex.Lock()
+	if rule.config.Cooldown > 0 && !rule.lastFired.IsZero() && now.Sub(rule.lastFired) < rule.config.Cooldown {
+		e.mutex.Unlock()
+		return
+	}
+	if !e.commandBudgetAvailable(now) {
+		e.mutex.Unlock()
+		e.log.Warn("Rule command suppressed by rate limit",
+			zap.String("rule", rule.config.Name),
+			zap.Int("max_commands_per_minute", e.config.MaxCommandsPerMinute))
+		return
+	}
+	e.commandTimes = append(e.commandTimes, now)
+	rule.fired++
+	rule.lastFired = now
+	e.mutex.Unlock()
+
+	service, err := e.pcsManager.GetService(rule.config.PCSID)
+	if err != nil {
+		e.log.Error("Rule targets unknown PCS",
+			zap.String("rule", rule.config.Name),
+			zap.Int("pcs_id", rule.config.PCSID))
+		return
+	}
+
+	power, err := e.controlLogic.ArbitratePCSPowerCommand(rule.config.PCSID, control.SourceRules, rule.config.PowerKW)
+	if err != nil {
+		e.log.Warn("Rule command rejected by arbitration",
+			zap.String("rule", rule.config.Name),
+			zap.Error(err))
+		return
+	}
+
+	if err := service.SetActivePowerCommand(power); err != nil {
+		e.log.Error("Failed to execute rule power command",
+			zap.String("rule", rule.config.Name),
+			zap.Error(err))
+		return
+	}
+
+	e.log.Info("Rule power command executed",
+		zap.String("rule", rule.config.Name),
+		zap.Int("pcs_id", rule.config.PCSID),
+		zap.Float32("power", power))
+}
+
+// commandBudgetAvailable prunes the sliding window and reports whether
+// another command fits into the per-minute budget; the caller must hold the
+// mutex
+func (e *Engine) commandBudgetAvailable(now time.Time) bool {
+	cutoff := now.Add(-time.Minute)
+	kept := e.commandTimes[:0]
+	for _, t := range e.commandTimes {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	e.commandTimes = kept
+
+	return e.config.MaxCommandsPerMinute <= 0 || len(e.commandTimes) < e.config.MaxCommandsPerMinute
+}
+
+// GetRuleStates returns the current state of every rule for diagnostics
+func (e *Engine) GetRuleStates() []RuleState {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	states := make([]RuleState, 0, len(e.rules))
+	for _, rule := range e.rules {
+		states = append(states, RuleState{
+			Name:       rule.config.Name,
+			Expression: rule.config.Expression,
+			Action:     rule.config.Action,
+			Active:     rule.active,
+			Fired:      rule.fired,
+			LastFired:  rule.lastFired,
+			LastError:  rule.lastError,
+		})
+	}
+	return states
+}
@@ -0,0 +1,49 @@
+package rules
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/site"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// Module provides the automation rule engine to the Fx application
+var Module = fx.Module("rules",
+	fx.Provide(ProvideEngine),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideEngine creates and provides a rule engine instance
+func ProvideEngine(
+	cfg *config.Config,
+	bmsManager *bms.Manager,
+	pcsManager *pcs.Manager,
+	windFarmManager *windfarm.Manager,
+	siteService *site.Service,
+	controlLogic *control.Logic,
+	alarmManager *alarm.Manager,
+	logger *zap.Logger,
+) (*Engine, error) {
+	return NewEngine(cfg.Rules, bmsManager, pcsManager, windFarmManager, siteService, controlLogic, alarmManager, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the rule engine
+func RegisterLifecycle(lc fx.Lifecycle, engine *Engine) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return engine.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			engine.Stop()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,47 @@
+package schedule
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/fcr"
+	"powerkonnekt/ems/internal/pcs"
+)
+
+// Module provides the active power schedule to the Fx application
+var Module = fx.Module("schedule",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides a schedule service instance
+func ProvideService(
+	cfg *config.Config,
+	pcsManager *pcs.Manager,
+	bmsManager *bms.Manager,
+	controlLogic *control.Logic,
+	fcrController *fcr.Controller,
+	postgreSQL *database.PostgreSQL,
+	logger *zap.Logger,
+) *Service {
+	return NewService(cfg.EMS, cfg.FCR.Enabled, pcsManager, bmsManager, controlLogic, fcrController, postgreSQL, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the schedule service
+func RegisterLifecycle(lc fx.Lifecycle, service *Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,485 @@
+// Package schedule executes an explicit active power schedule: a list of
+// timed site-level setpoints or SOC targets with ramp rates, managed over
+// the API independently of any price-based dispatch. The plan is persisted
+// in PostgreSQL so a restart resumes it. Entries are executed through the
+// normal setpoint arbitration under the scheduler source, so safety, FCR
+// and SCADA always win.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/fcr"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/pkg/loopwatch"
+)
+
+const (
+	// tickInterval drives the schedule executor
+	tickInterval = time.Second
+
+	// maxEntries bounds the schedule so a misbehaving integration cannot
+	// exhaust memory
+	maxEntries = 500
+
+	// dispatchDeadbandKW suppresses re-dispatching targets that have not
+	// meaningfully changed
+	dispatchDeadbandKW = 0.01
+
+	// socTargetDeadband stops an SOC-target entry once the average SOC is
+	// within this band of the target (% SOC)
+	socTargetDeadband = 1.0
+)
+
+// Entry is one step of the active power schedule: from its start time the
+// site target moves to the setpoint, along the ramp rate when one is
+// given. An entry with an SOC target instead charges or discharges at the
+// setpoint magnitude until the average SOC reaches the target.
+type Entry struct {
+	ID         int       `json:"id"`
+	StartTime  time.Time `json:"start_time"`
+	SetpointKW float32   `json:"setpoint_kw"`             // Site level, positive discharges
+	RampRateKW float32   `json:"ramp_rate_kw_per_minute"` // 0 steps immediately
+	TargetSOC  float32   `json:"target_soc,omitempty"`    // 0 = plain setpoint entry
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// EffectivePoint is one sample of the merged effective schedule trajectory
+type EffectivePoint struct {
+	Time    time.Time `json:"time"`
+	PowerKW float32   `json:"power_kw"`
+	EntryID int       `json:"entry_id,omitempty"`
+}
+
+// Service manages and executes the active power schedule
+type Service struct {
+	config        config.EMSConfig
+	fcrEnabled    bool
+	pcsManager    *pcs.Manager
+	bmsManager    *bms.Manager
+	controlLogic  *control.Logic
+	fcrController *fcr.Controller
+	postgreSQL    *database.PostgreSQL
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+	log           *zap.Logger
+	loopWatch     *loopwatch.Loop
+
+	mutex       sync.RWMutex
+	entries     []Entry
+	nextID      int
+	dispatching bool
+	lastTarget  float32
+}
+
+// NewService creates a new schedule service
+func NewService(cfg config.EMSConfig, fcrEnabled bool, pcsManager *pcs.Manager, bmsManager *bms.Manager, controlLogic *control.Logic, fcrController *fcr.Controller, postgreSQL *database.PostgreSQL, logger *zap.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	serviceLogger := logger.With(
+		zap.String("component", "schedule"),
+	)
+
+	return &Service{
+		config:        cfg,
+		fcrEnabled:    fcrEnabled,
+		pcsManager:    pcsManager,
+		bmsManager:    bmsManager,
+		controlLogic:  controlLogic,
+		fcrController: fcrController,
+		postgreSQL:    postgreSQL,
+		ctx:           ctx,
+		cancel:        cancel,
+		log:           serviceLogger,
+		loopWatch:     loopwatch.NewLoop("schedule_execute", tickInterval),
+		nextID:        1,
+	}
+}
+
+// Start restores the persisted plan and starts the schedule executor
+func (s *Service) Start() error {
+	s.restoreEntries()
+	s.wg.Go(s.executeLoop)
+	s.log.Info("Schedule service started")
+	return nil
+}
+
+// restoreEntries reloads the persisted schedule so a restart resumes the
+// day-ahead plan
+func (s *Service) restoreEntries() {
+	records, err := s.postgreSQL.GetScheduleEntries()
+	if err != nil {
+		s.log.Warn("Failed to restore persisted schedule", zap.Error(err))
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, record := range records {
+		s.entries = append(s.entries, Entry{
+			ID:         record.ID,
+			StartTime:  record.StartTime,
+			SetpointKW: record.SetpointKW,
+			RampRateKW: record.RampRateKW,
+			TargetSOC:  record.TargetSOC,
+			CreatedAt:  record.CreatedAt,
+		})
+		if record.ID >= s.nextID {
+			s.nextID = record.ID + 1
+		}
+	}
+
+	if len(s.entries) > 0 {
+		s.log.Info("Persisted schedule restored",
+			zap.Int("entries", len(s.entries)))
+	}
+}
+
+// Stop stops the schedule executor
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	s.log.Info("Schedule service stopped")
+}
+
+// AddEntry validates and inserts a schedule entry. A target SOC above zero
+// makes the entry SOC-driven: the setpoint is then the power magnitude the
+// plant charges or discharges with until the target is reached.
+func (s *Service) AddEntry(startTime time.Time, setpointKW, rampRateKW, targetSOC float32) (Entry, error) {
+	if startTime.IsZero() {
+		return Entry{}, fmt.Errorf("start time is required")
+	}
+	if rampRateKW < 0 {
+		return Entry{}, fmt.Errorf("ramp rate must not be negative")
+	}
+	if targetSOC < 0 || targetSOC > 100 {
+		return Entry{}, fmt.Errorf("target SOC must be between 0 and 100")
+	}
+	if targetSOC > 0 && setpointKW <= 0 {
+		return Entry{}, fmt.Errorf("an SOC-target entry needs a positive setpoint magnitude")
+	}
+	if setpointKW > s.config.MaxDischargePower {
+		return Entry{}, fmt.Errorf("setpoint %.1f kW exceeds maximum discharge power %.1f kW", setpointKW, s.config.MaxDischargePower)
+	}
+	if -setpointKW > s.config.MaxChargePower {
+		return Entry{}, fmt.Errorf("setpoint %.1f kW exceeds maximum charge power %.1f kW", setpointKW, s.config.MaxChargePower)
+	}
+	if targetSOC > 0 && setpointKW > s.config.MaxChargePower {
+		return Entry{}, fmt.Errorf("setpoint %.1f kW exceeds maximum charge power %.1f kW", setpointKW, s.config.MaxChargePower)
+	}
+	if err := s.checkFCRConflict(setpointKW); err != nil {
+		return Entry{}, err
+	}
+	if targetSOC > 0 {
+		// An SOC entry may dispatch in either direction
+		if err := s.checkFCRConflict(-setpointKW); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.entries) >= maxEntries {
+		return Entry{}, fmt.Errorf("schedule is full (%d entries)", maxEntries)
+	}
+	for _, entry := range s.entries {
+		if entry.StartTime.Equal(startTime) {
+			return Entry{}, fmt.Errorf("an entry at %s already exists (id %d)", startTime.Format(time.RFC3339), entry.ID)
+		}
+	}
+
+	entry := Entry{
+		ID:         s.nextID,
+		StartTime:  startTime,
+		SetpointKW: setpointKW,
+		RampRateKW: rampRateKW,
+		TargetSOC:  targetSOC,
+		CreatedAt:  time.Now(),
+	}
+	s.nextID++
+
+	s.entries = append(s.entries, entry)
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].StartTime.Before(s.entries[j].StartTime)
+	})
+
+	if err := s.postgreSQL.SaveScheduleEntry(database.ScheduleEntryRecord{
+		ID:         entry.ID,
+		StartTime:  entry.StartTime,
+		SetpointKW: entry.SetpointKW,
+		RampRateKW: entry.RampRateKW,
+		TargetSOC:  entry.TargetSOC,
+		CreatedAt:  entry.CreatedAt,
+	}); err != nil {
+		s.log.Warn("Failed to persist schedule entry", zap.Error(err))
+	}
+
+	s.log.Info("Schedule entry added",
+		zap.Int("id", entry.ID),
+		zap.Time("start_time", entry.StartTime),
+		zap.Float32("setpoint_kw", entry.SetpointKW),
+		zap.Float32("ramp_rate_kw_per_minute", entry.RampRateKW),
+		zap.Float32("target_soc", entry.TargetSOC))
+	return entry, nil
+}
+
+// checkFCRConflict rejects setpoints that would eat into the capacity the
+// FCR controller has to keep in reserve for frequency response
+func (s *Service) checkFCRConflict(setpointKW float32) error {
+	if !s.fcrEnabled {
+		return nil
+	}
+
+	reserve := s.fcrController.GetActivationState().AvailableCapacityKW
+	if reserve <= 0 {
+		return nil
+	}
+
+	limit := s.config.MaxDischargePower
+	if setpointKW < 0 {
+		limit = s.config.MaxChargePower
+	}
+	if abs32(setpointKW)+reserve > limit {
+		return fmt.Errorf("setpoint %.1f kW conflicts with the %.1f kW FCR reservation (site limit %.1f kW)",
+			setpointKW, reserve, limit)
+	}
+	return nil
+}
+
+// DeleteEntry removes a schedule entry
+func (s *Service) DeleteEntry(id int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, entry := range s.entries {
+		if entry.ID == id {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			if err := s.postgreSQL.DeleteScheduleEntry(id); err != nil {
+				s.log.Warn("Failed to delete persisted schedule entry", zap.Error(err))
+			}
+			s.log.Info("Schedule entry deleted", zap.Int("id", id))
+			return nil
+		}
+	}
+	return fmt.Errorf("schedule entry %d not found", id)
+}
+
+// GetEntries returns the schedule entries, ordered by start time
+func (s *Service) GetEntries() []Entry {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]Entry(nil), s.entries...)
+}
+
+// GetEffectiveSchedule samples the merged target trajectory between start
+// and end for visualization, including the ramp transitions
+func (s *Service) GetEffectiveSchedule(start, end time.Time, step time.Duration) []EffectivePoint {
+	if step < time.Minute {
+		step = time.Minute
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var points []EffectivePoint
+	for t := start; !t.After(end); t = t.Add(step) {
+		power, entryID, active := s.targetAtLocked(t)
+		point := EffectivePoint{Time: t, PowerKW: power}
+		if active {
+			point.EntryID = entryID
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// targetAtLocked computes the scheduled site target at a point in time by
+// walking the entries, evaluating each ramp up to the start of its
+// successor; the caller must hold the mutex
+func (s *Service) targetAtLocked(t time.Time) (float32, int, bool) {
+	var level float32
+	entryID := 0
+	active := false
+
+	for i, entry := range s.entries {
+		if entry.StartTime.After(t) {
+			break
+		}
+
+		evalAt := t
+		if i+1 < len(s.entries) && s.entries[i+1].StartTime.Before(t) {
+			evalAt = s.entries[i+1].StartTime
+		}
+		level = rampValue(level, s.resolveSetpoint(entry), entry, evalAt)
+		entryID = entry.ID
+		active = true
+	}
+
+	return level, entryID, active
+}
+
+// resolveSetpoint returns the power an entry currently drives toward.
+// SOC-target entries charge or discharge at the setpoint magnitude until
+// the average SOC is within the deadband of the target, then hold zero.
+func (s *Service) resolveSetpoint(entry Entry) float32 {
+	if entry.TargetSOC <= 0 {
+		return entry.SetpointKW
+	}
+
+	soc, known := s.averageSOC()
+	if !known {
+		return 0
+	}
+
+	switch {
+	case soc < entry.TargetSOC-socTargetDeadband:
+		return -entry.SetpointKW // Charge toward the target
+	case soc > entry.TargetSOC+socTargetDeadband:
+		return entry.SetpointKW // Discharge toward the target
+	default:
+		return 0
+	}
+}
+
+// averageSOC is the mean SOC over the connected BMS strings
+func (s *Service) averageSOC() (float32, bool) {
+	sum, count := float32(0), 0
+	for _, service := range s.bmsManager.GetAllServices() {
+		if service.IsConnected() {
+			sum += float32(service.GetLatestBMSData().SOC)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float32(count), true
+}
+
+// rampValue moves from the previous level toward the resolved setpoint at
+// the entry ramp rate; entries without a ramp rate step immediately
+func rampValue(from, setpoint float32, entry Entry, t time.Time) float32 {
+	if entry.RampRateKW <= 0 {
+		return setpoint
+	}
+
+	maxDelta := entry.RampRateKW * float32(t.Sub(entry.StartTime).Minutes())
+	delta := setpoint - from
+	if delta > maxDelta {
+		return from + maxDelta
+	}
+	if delta < -maxDelta {
+		return from - maxDelta
+	}
+	return setpoint
+}
+
+// executeLoop periodically dispatches the scheduled target
+func (s *Service) executeLoop() {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			finish := s.loopWatch.Begin()
+			s.tick()
+			finish()
+		}
+	}
+}
+
+// tick dispatches the current scheduled target; a completed return to zero
+// releases the scheduler claim so lower-priority sources take over again
+func (s *Service) tick() {
+	now := time.Now()
+
+	s.mutex.Lock()
+	target, _, active := s.targetAtLocked(now)
+	if !active {
+		dispatching := s.dispatching
+		s.dispatching = false
+		s.mutex.Unlock()
+		if dispatching {
+			s.releaseAll()
+		}
+		return
+	}
+
+	if s.dispatching && target == 0 && s.lastTarget == 0 {
+		s.dispatching = false
+		s.mutex.Unlock()
+		s.releaseAll()
+		return
+	}
+
+	changed := !s.dispatching || abs32(target-s.lastTarget) > dispatchDeadbandKW
+	s.dispatching = true
+	s.lastTarget = target
+	s.mutex.Unlock()
+
+	if changed {
+		s.dispatch(target)
+	}
+}
+
+// dispatch splits the site target across the PCS units weighted by SOC and
+// headroom and applies it through the setpoint arbitration
+func (s *Service) dispatch(target float32) {
+	services := s.pcsManager.GetAllServices()
+	if len(services) == 0 {
+		return
+	}
+
+	pcsIDs := make([]int, 0, len(services))
+	for pcsID := range services {
+		pcsIDs = append(pcsIDs, pcsID)
+	}
+	shares := s.controlLogic.DispatchShares(target, pcsIDs)
+
+	for pcsID, service := range services {
+		power, err := s.controlLogic.ArbitratePCSPowerCommand(pcsID, control.SourceScheduler, shares[pcsID])
+		if err != nil {
+			s.log.Warn("Schedule dispatch rejected by arbitration",
+				zap.Int("pcs_id", pcsID),
+				zap.Error(err))
+			continue
+		}
+		if err := service.SetActivePowerCommand(power); err != nil {
+			s.log.Error("Failed to apply scheduled power command",
+				zap.Int("pcs_id", pcsID),
+				zap.Error(err))
+		}
+	}
+}
+
+// releaseAll drops the scheduler claim on every PCS
+func (s *Service) releaseAll() {
+	for pcsID := range s.pcsManager.GetAllServices() {
+		s.controlLogic.ReleasePCSSetpoint(pcsID, control.SourceScheduler)
+	}
+	s.log.Info("Schedule completed, scheduler claims released")
+}
+
+// abs32 returns the absolute value of a float32
+func abs32(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,10 @@
+package season
+
+import (
+	"go.uber.org/fx"
+)
+
+// Module provides seasonal operating profiles to the Fx application
+var Module = fx.Module("season",
+	fx.Provide(NewManager),
+)
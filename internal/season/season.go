@@ -0,0 +1,211 @@
+// Package season provides named seasonal operating profiles. A profile
+// bundles the SOC window, reserve level, FCR capacity cap and thermal power
+// limits of one season (summer, winter, high-price periods); the active
+// profile comes from the calendar or the configured default and can be
+// overridden at runtime through the API, though a runtime switch does not
+// survive a restart.
+package season
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// Manager resolves the active seasonal profile from the calendar and the
+// configuration with an optional runtime override
+type Manager struct {
+	config   config.SeasonConfig
+	profiles map[string]config.SeasonProfileConfig
+	location *time.Location
+	log      *zap.Logger
+
+	mutex    sync.RWMutex
+	override string
+}
+
+// NewManager creates a season profile manager from the configured profiles.
+// Calendar entries and the active profile must reference defined profiles.
+func NewManager(cfg *config.Config, logger *zap.Logger) (*Manager, error) {
+	profiles := make(map[string]config.SeasonProfileConfig, len(cfg.Season.Profiles))
+	for _, profile := range cfg.Season.Profiles {
+		if _, exists := profiles[profile.Name]; exists {
+			return nil, fmt.Errorf("duplicate season profile: %s", profile.Name)
+		}
+		profiles[profile.Name] = profile
+	}
+
+	if name := cfg.Season.ActiveProfile; name != "" {
+		if _, known := profiles[name]; !known {
+			return nil, fmt.Errorf("unknown active season profile: %s", name)
+		}
+	}
+	for _, entry := range cfg.Season.Calendar {
+		if _, known := profiles[entry.Profile]; !known {
+			return nil, fmt.Errorf("season calendar references unknown profile: %s", entry.Profile)
+		}
+	}
+
+	manager := &Manager{
+		config:   cfg.Season,
+		profiles: profiles,
+		location: cfg.EMS.MarketLocation(),
+		log: logger.With(
+			zap.String("component", "season"),
+		),
+	}
+
+	if cfg.Season.Enabled {
+		if profile, active := manager.Current(); active {
+			manager.log.Info("Seasonal operating profile active",
+				zap.String("profile", profile.Name),
+				zap.String("source", manager.Source()))
+		}
+	}
+
+	return manager, nil
+}
+
+// Current returns the active seasonal profile, preferring a runtime
+// override over the calendar and the configured default
+func (m *Manager) Current() (config.SeasonProfileConfig, bool) {
+	if !m.config.Enabled {
+		return config.SeasonProfileConfig{}, false
+	}
+
+	m.mutex.RLock()
+	override := m.override
+	m.mutex.RUnlock()
+
+	if override != "" {
+		return m.profiles[override], true
+	}
+
+	if name := m.calendarProfile(time.Now().In(m.location)); name != "" {
+		return m.profiles[name], true
+	}
+
+	if m.config.ActiveProfile != "" {
+		return m.profiles[m.config.ActiveProfile], true
+	}
+
+	return config.SeasonProfileConfig{}, false
+}
+
+// Source reports where the active profile comes from (runtime, calendar or
+// config)
+func (m *Manager) Source() string {
+	m.mutex.RLock()
+	override := m.override
+	m.mutex.RUnlock()
+
+	switch {
+	case override != "":
+		return "runtime"
+	case m.calendarProfile(time.Now().In(m.location)) != "":
+		return "calendar"
+	default:
+		return "config"
+	}
+}
+
+// SetProfile applies a runtime profile override
+func (m *Manager) SetProfile(name string) error {
+	if _, known := m.profiles[name]; !known {
+		return fmt.Errorf("unknown season profile: %s", name)
+	}
+
+	m.mutex.Lock()
+	m.override = name
+	m.mutex.Unlock()
+
+	m.log.Warn("Season profile changed at runtime",
+		zap.String("profile", name))
+
+	return nil
+}
+
+// ClearProfile removes the runtime override, falling back to the calendar
+// or the configured default
+func (m *Manager) ClearProfile() {
+	m.mutex.Lock()
+	m.override = ""
+	m.mutex.Unlock()
+
+	m.log.Info("Season profile override cleared")
+}
+
+// Profiles returns every configured profile for the diagnostics endpoint
+func (m *Manager) Profiles() []config.SeasonProfileConfig {
+	return m.config.Profiles
+}
+
+// ApplyLimits overlays the active profile onto a set of BMS limits; zero
+// profile values keep the base limits
+func (m *Manager) ApplyLimits(limits config.BMSLimits) config.BMSLimits {
+	profile, active := m.Current()
+	if !active {
+		return limits
+	}
+
+	if profile.MaxSOC > 0 {
+		limits.MaxSOC = profile.MaxSOC
+	}
+	if profile.MinSOC > 0 {
+		limits.MinSOC = profile.MinSOC
+	}
+	if profile.MaxChargePower > 0 {
+		limits.MaxChargePower = profile.MaxChargePower
+	}
+	if profile.MaxDischargePower > 0 {
+		limits.MaxDischargePower = profile.MaxDischargePower
+	}
+	return limits
+}
+
+// ReserveSOC returns the seasonal islanding reserve floor in percent SOC
+// (0 = none)
+func (m *Manager) ReserveSOC() float32 {
+	profile, active := m.Current()
+	if !active {
+		return 0
+	}
+	return profile.IslandingReserveSOC
+}
+
+// FCRCapacityCapKW returns the seasonal cap on marketed FCR capacity in kW
+// (0 = no cap)
+func (m *Manager) FCRCapacityCapKW() float32 {
+	profile, active := m.Current()
+	if !active {
+		return 0
+	}
+	return profile.FCRCapacityCapKW
+}
+
+// calendarProfile resolves the calendar entry covering the given date, if
+// any; the first matching entry wins
+func (m *Manager) calendarProfile(now time.Time) string {
+	monthDay := now.Format("01-02")
+	for _, entry := range m.config.Calendar {
+		if inSeasonRange(monthDay, entry.Start, entry.End) {
+			return entry.Profile
+		}
+	}
+	return ""
+}
+
+// inSeasonRange reports whether a month-day falls into a yearly recurring
+// range; a range whose end precedes its start wraps over the turn of the
+// year. Both bounds are inclusive. "MM-DD" strings compare correctly
+// lexicographically.
+func inSeasonRange(monthDay, start, end string) bool {
+	if start <= end {
+		return monthDay >= start && monthDay <= end
+	}
+	return monthDay >= start || monthDay <= end
+}
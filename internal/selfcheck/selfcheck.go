@@ -0,0 +1,233 @@
+// Package selfcheck implements the ems --check startup self-test: it loads
+// and validates configuration, verifies secrets are resolved, sanity-checks
+// the register map profiles and optionally probes connectivity to all
+// configured devices and databases, then exits with a structured report.
+// This surfaces bad configs before the service partially starts on site.
+package selfcheck
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/pkg/modbus"
+)
+
+// Check statuses
+const (
+	StatusOK   = "OK"
+	StatusFail = "FAIL"
+	StatusSkip = "SKIP"
+)
+
+const connectTimeout = 5 * time.Second
+
+// Result is the outcome of one self-test check
+type Result struct {
+	Name    string
+	Status  string
+	Message string
+}
+
+// Run executes the self-test and prints the report. It returns the process
+// exit code: 0 when every check passed, 1 otherwise.
+func Run(connect bool) int {
+	var results []Result
+
+	cfg, result := checkConfig()
+	results = append(results, result)
+
+	if cfg != nil {
+		results = append(results, checkSecrets(cfg)...)
+		results = append(results, checkDeviceIDs(cfg)...)
+		results = append(results, checkRegisterProfiles())
+
+		if connect {
+			results = append(results, checkConnectivity(cfg)...)
+		} else {
+			results = append(results, Result{
+				Name:    "connectivity",
+				Status:  StatusSkip,
+				Message: "pass --check-connect to probe devices and databases",
+			})
+		}
+	}
+
+	failed := 0
+	fmt.Println("EMS self-test report")
+	for _, result := range results {
+		fmt.Printf("  [%-4s] %-28s %s\n", result.Status, result.Name, result.Message)
+		if result.Status == StatusFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("self-test failed: %d of %d checks failed\n", failed, len(results))
+		return 1
+	}
+	fmt.Printf("self-test passed: %d checks\n", len(results))
+	return 0
+}
+
+// checkConfig loads and validates the configuration file
+func checkConfig() (*config.Config, Result) {
+	cfg, err := config.NewConfig(config.NewValidator())
+	if err != nil {
+		return nil, Result{Name: "config", Status: StatusFail, Message: err.Error()}
+	}
+	return cfg, Result{Name: "config", Status: StatusOK, Message: "loaded and validated"}
+}
+
+// checkSecrets verifies that database credentials resolved to non-empty
+// values (they may come from environment variables)
+func checkSecrets(cfg *config.Config) []Result {
+	var results []Result
+
+	if cfg.InfluxDB.Token == "" {
+		results = append(results, Result{Name: "influxdb_token", Status: StatusFail, Message: "not resolved"})
+	} else {
+		results = append(results, Result{Name: "influxdb_token", Status: StatusOK, Message: "resolved"})
+	}
+
+	if cfg.PostgreSQL.Password == "" {
+		results = append(results, Result{Name: "postgresql_password", Status: StatusFail, Message: "not resolved"})
+	} else {
+		results = append(results, Result{Name: "postgresql_password", Status: StatusOK, Message: "resolved"})
+	}
+
+	return results
+}
+
+// checkDeviceIDs verifies that device IDs are unique within each device type
+func checkDeviceIDs(cfg *config.Config) []Result {
+	var results []Result
+
+	check := func(name string, ids []int) {
+		seen := make(map[int]bool)
+		for _, id := range ids {
+			if seen[id] {
+				results = append(results, Result{
+					Name:    name + "_ids",
+					Status:  StatusFail,
+					Message: fmt.Sprintf("duplicate id %d", id),
+				})
+				return
+			}
+			seen[id] = true
+		}
+		results = append(results, Result{
+			Name:    name + "_ids",
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%d unique", len(ids)),
+		})
+	}
+
+	pcsIDs := make([]int, 0, len(cfg.PCS))
+	for _, device := range cfg.PCS {
+		pcsIDs = append(pcsIDs, device.ID)
+	}
+	check("pcs", pcsIDs)
+
+	bmsIDs := make([]int, 0, len(cfg.BMS))
+	for _, device := range cfg.BMS {
+		bmsIDs = append(bmsIDs, device.ID)
+	}
+	check("bms", bmsIDs)
+
+	plcIDs := make([]int, 0, len(cfg.PLC))
+	for _, device := range cfg.PLC {
+		plcIDs = append(plcIDs, device.ID)
+	}
+	check("plc", plcIDs)
+
+	windFarmIDs := make([]int, 0, len(cfg.WindFarm))
+	for _, device := range cfg.WindFarm {
+		windFarmIDs = append(windFarmIDs, device.ID)
+	}
+	check("windfarm", windFarmIDs)
+
+	return results
+}
+
+// checkRegisterProfiles verifies that every register block in the device
+// protocol profiles fits in a single Modbus read
+func checkRegisterProfiles() Result {
+	blocks := map[string]int{
+		"bms_status":      bms.BMSStatusDataLength,
+		"bms_data":        bms.BMSDataLength,
+		"bms_rack_status": bms.BMSRackStatusDataLength,
+		"bms_rack_data":   bms.BMSRackDataLength,
+		"pcs_equipment":   pcs.EquipmentDataLength,
+		"pcs_dc_source":   pcs.DCSourceDataLength,
+		"pcs_grid":        pcs.GridDataLength,
+		"pcs_counter":     pcs.CounterDataLength,
+	}
+
+	for name, length := range blocks {
+		if length > modbus.MaxRegistersPerRead {
+			return Result{
+				Name:    "register_profiles",
+				Status:  StatusFail,
+				Message: fmt.Sprintf("%s block (%d registers) exceeds max read size %d", name, length, modbus.MaxRegistersPerRead),
+			}
+		}
+	}
+
+	return Result{
+		Name:    "register_profiles",
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d register blocks within read limits", len(blocks)),
+	}
+}
+
+// checkConnectivity probes TCP reachability of every configured device and
+// database endpoint
+func checkConnectivity(cfg *config.Config) []Result {
+	var results []Result
+
+	probe := func(name, host string, port int) {
+		addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+		conn, err := net.DialTimeout("tcp", addr, connectTimeout)
+		if err != nil {
+			results = append(results, Result{Name: name, Status: StatusFail, Message: err.Error()})
+			return
+		}
+		conn.Close()
+		results = append(results, Result{Name: name, Status: StatusOK, Message: addr + " reachable"})
+	}
+
+	for _, device := range cfg.PCS {
+		probe(fmt.Sprintf("pcs_%d", device.ID), device.Host, device.Port)
+	}
+	for _, device := range cfg.BMS {
+		probe(fmt.Sprintf("bms_%d", device.ID), device.Host, device.Port)
+	}
+	for _, device := range cfg.PLC {
+		probe(fmt.Sprintf("plc_%d", device.ID), device.Host, device.Port)
+	}
+	for _, device := range cfg.WindFarm {
+		probe(fmt.Sprintf("windfarm_%d", device.ID), device.Host, device.Port)
+	}
+
+	if influxURL, err := url.Parse(cfg.InfluxDB.URL); err != nil {
+		results = append(results, Result{Name: "influxdb", Status: StatusFail, Message: err.Error()})
+	} else {
+		port := 80
+		if influxURL.Scheme == "https" {
+			port = 443
+		}
+		if influxURL.Port() != "" {
+			fmt.Sscanf(influxURL.Port(), "%d", &port)
+		}
+		probe("influxdb", influxURL.Hostname(), port)
+	}
+
+	probe("postgresql", cfg.PostgreSQL.Host, cfg.PostgreSQL.Port)
+
+	return results
+}
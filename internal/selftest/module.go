@@ -0,0 +1,43 @@
+package selftest
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/alarm"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/pcs"
+)
+
+// Module provides the control-chain self-test to the Fx application
+var Module = fx.Module("selftest",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides a self-test service instance
+func ProvideService(
+	cfg *config.Config,
+	pcsManager *pcs.Manager,
+	controlLogic *control.Logic,
+	alarmManager *alarm.Manager,
+	logger *zap.Logger,
+) *Service {
+	return NewService(cfg, pcsManager, controlLogic, alarmManager, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the self-test service
+func RegisterLifecycle(lc fx.Lifecycle, service *Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+}
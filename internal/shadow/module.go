@@ -0,0 +1,65 @@
+package shadow
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/site"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// Module provides the shadow evaluation service to the Fx application
+var Module = fx.Module("shadow",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides a shadow evaluation service instance
+func ProvideService(
+	cfg *config.Config,
+	bmsManager *bms.Manager,
+	pcsManager *pcs.Manager,
+	windFarmManager *windfarm.Manager,
+	siteService *site.Service,
+	controlLogic *control.Logic,
+	influxDB *database.InfluxDB,
+	logger *zap.Logger,
+) (*Service, error) {
+	var strategy Strategy
+	if cfg.Shadow.Enabled {
+		var err error
+		strategy, err = NewStrategy(cfg.Shadow.Strategy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shadow configuration: %w", err)
+		}
+	}
+
+	bmsLimits := make(map[int]config.BMSLimits, len(cfg.BMS))
+	for _, bmsCfg := range cfg.BMS {
+		bmsLimits[bmsCfg.ID] = cfg.LimitsForBMS(bmsCfg.ID)
+	}
+
+	return NewService(cfg.Shadow, bmsLimits, cfg.TopologyMap(), strategy, bmsManager, pcsManager, windFarmManager, siteService, controlLogic, influxDB, logger), nil
+}
+
+// RegisterLifecycle registers lifecycle hooks for the shadow evaluation
+// service
+func RegisterLifecycle(lc fx.Lifecycle, service *Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+}
@@ -0,0 +1,273 @@
+package shadow
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pcs"
+	"powerkonnekt/ems/internal/site"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// persistEvery persists one out of this many evaluations per PCS
+const persistEvery = 10
+
+// UnitDivergence summarizes the divergence of one PCS between the active
+// controller and the shadow strategy
+type UnitDivergence struct {
+	PCSID        int     `json:"pcs_id"`
+	ActualKW     float32 `json:"actual_kw"`
+	ShadowKW     float32 `json:"shadow_kw"`
+	DivergenceKW float32 `json:"divergence_kw"`
+	MeanAbsDivKW float32 `json:"mean_abs_div_kw"`
+	MaxAbsDivKW  float32 `json:"max_abs_div_kw"`
+	Samples      uint64  `json:"samples"`
+}
+
+// Status is the shadow evaluation state for reports
+type Status struct {
+	Enabled  bool             `json:"enabled"`
+	Strategy string           `json:"strategy"`
+	Units    []UnitDivergence `json:"units"`
+}
+
+// unitStats accumulates the divergence statistics of one PCS
+type unitStats struct {
+	actual    float32
+	shadow    float32
+	absDivSum float64
+	maxAbsDiv float32
+	samples   uint64
+}
+
+// Service runs the shadow strategy against the live telemetry and records
+// its would-be commands next to the active controller's commands
+type Service struct {
+	config          config.ShadowConfig
+	bmsLimits       map[int]config.BMSLimits
+	topology        map[int][]int // Connected BMS strings per PCS
+	strategy        Strategy
+	bmsManager      *bms.Manager
+	pcsManager      *pcs.Manager
+	windFarmManager *windfarm.Manager
+	siteService     *site.Service
+	controlLogic    *control.Logic
+	influxDB        *database.InfluxDB
+	ctx             context.Context
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	log             *zap.Logger
+
+	mutex sync.RWMutex
+	stats map[int]*unitStats
+}
+
+// NewService creates the shadow evaluation service
+func NewService(cfg config.ShadowConfig, bmsLimits map[int]config.BMSLimits, topology map[int][]int, strategy Strategy, bmsManager *bms.Manager, pcsManager *pcs.Manager, windFarmManager *windfarm.Manager, siteService *site.Service, controlLogic *control.Logic, influxDB *database.InfluxDB, logger *zap.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Service{
+		config:          cfg,
+		bmsLimits:       bmsLimits,
+		topology:        topology,
+		strategy:        strategy,
+		bmsManager:      bmsManager,
+		pcsManager:      pcsManager,
+		windFarmManager: windFarmManager,
+		siteService:     siteService,
+		controlLogic:    controlLogic,
+		influxDB:        influxDB,
+		ctx:             ctx,
+		cancel:          cancel,
+		stats:           make(map[int]*unitStats),
+		log: logger.With(
+			zap.String("service", "shadow"),
+		),
+	}
+}
+
+// Start starts the evaluation loop when shadow mode is enabled
+func (s *Service) Start() error {
+	if !s.config.Enabled {
+		s.log.Info("Shadow evaluation disabled")
+		return nil
+	}
+
+	s.wg.Go(s.evalLoop)
+
+	s.log.Info("Shadow evaluation started",
+		zap.String("strategy", s.strategy.Name()),
+		zap.Duration("eval_interval", s.config.EvalInterval))
+
+	return nil
+}
+
+// Stop stops the evaluation loop
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	if s.config.Enabled {
+		s.log.Info("Shadow evaluation stopped")
+	}
+}
+
+// evalLoop periodically evaluates the shadow strategy
+func (s *Service) evalLoop() {
+	ticker := time.NewTicker(s.config.EvalInterval)
+	defer ticker.Stop()
+
+	samples := 0
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			samples++
+			s.evaluate(now, samples%persistEvery == 0)
+		}
+	}
+}
+
+// evaluate runs one shadow evaluation: snapshot the live state, compute the
+// would-be commands and accumulate the divergence against the actual ones
+func (s *Service) evaluate(now time.Time, persist bool) {
+	input := s.buildInput()
+	commands := s.strategy.ComputeCommands(input)
+
+	s.mutex.Lock()
+	records := make([]database.ShadowCommandData, 0, len(input.Units))
+	for _, unit := range input.Units {
+		shadowKW := commands[unit.PCSID]
+		divergence := shadowKW - unit.ActualCommandKW
+
+		stats, ok := s.stats[unit.PCSID]
+		if !ok {
+			stats = &unitStats{}
+			s.stats[unit.PCSID] = stats
+		}
+		stats.actual = unit.ActualCommandKW
+		stats.shadow = shadowKW
+		stats.samples++
+		stats.absDivSum += float64(abs(divergence))
+		if abs(divergence) > stats.maxAbsDiv {
+			stats.maxAbsDiv = abs(divergence)
+		}
+
+		if persist {
+			records = append(records, database.ShadowCommandData{
+				Timestamp:    now,
+				PCSID:        unit.PCSID,
+				Strategy:     s.strategy.Name(),
+				ActualKW:     unit.ActualCommandKW,
+				ShadowKW:     shadowKW,
+				DivergenceKW: divergence,
+			})
+		}
+	}
+	s.mutex.Unlock()
+
+	for _, record := range records {
+		if err := s.influxDB.WriteShadowCommandData(record); err != nil {
+			s.log.Error("Failed to write shadow command data", zap.Error(err))
+		}
+	}
+}
+
+// buildInput snapshots the live state the strategy dispatches against. Each
+// PCS is paired with its BMS strings following the configured plant
+// topology, mirroring the pairing used by the active controller.
+func (s *Service) buildInput() Input {
+	balance := s.siteService.GetBalance()
+
+	input := Input{
+		Mode:        s.controlLogic.GetMode(),
+		SiteNetKW:   balance.NetPowerKW,
+		WindPowerKW: balance.WindPowerKW,
+	}
+
+	for id, service := range s.pcsManager.GetAllServices() {
+		unit := UnitInput{
+			PCSID:           id,
+			ActualCommandKW: service.GetCommandState().ActivePowerCommand,
+		}
+
+		// Average SOC and tightest limits over the attached BMS strings
+		var socSum float32
+		var socCount int
+		for _, bmsID := range s.topology[id] {
+			bmsService, err := s.bmsManager.GetService(bmsID)
+			if err != nil || !bmsService.IsConnected() {
+				continue
+			}
+			socSum += bmsService.GetLatestBMSData().SOC
+			socCount++
+
+			limits := s.bmsLimits[bmsID]
+			if unit.MaxDischargeKW == 0 || limits.MaxDischargePower < unit.MaxDischargeKW {
+				unit.MaxDischargeKW = limits.MaxDischargePower
+			}
+			if unit.MaxChargeKW == 0 || limits.MaxChargePower < unit.MaxChargeKW {
+				unit.MaxChargeKW = limits.MaxChargePower
+			}
+		}
+		if socCount > 0 {
+			unit.SOC = socSum / float32(socCount)
+		}
+
+		input.TotalKW += unit.ActualCommandKW
+		input.Units = append(input.Units, unit)
+	}
+
+	return input
+}
+
+// GetStatus returns the divergence statistics per PCS
+func (s *Service) GetStatus() Status {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	status := Status{
+		Enabled: s.config.Enabled,
+	}
+	if s.strategy != nil {
+		status.Strategy = s.strategy.Name()
+	}
+
+	for id, stats := range s.stats {
+		unit := UnitDivergence{
+			PCSID:        id,
+			ActualKW:     stats.actual,
+			ShadowKW:     stats.shadow,
+			DivergenceKW: stats.shadow - stats.actual,
+			MaxAbsDivKW:  stats.maxAbsDiv,
+			Samples:      stats.samples,
+		}
+		if stats.samples > 0 {
+			unit.MeanAbsDivKW = float32(stats.absDivSum / float64(stats.samples))
+		}
+		status.Units = append(status.Units, unit)
+	}
+
+	sort.Slice(status.Units, func(i, j int) bool {
+		return status.Units[i].PCSID < status.Units[j].PCSID
+	})
+
+	return status
+}
+
+// abs returns the absolute value of a float32
+func abs(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
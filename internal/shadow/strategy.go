@@ -0,0 +1,136 @@
+// Package shadow runs an alternative dispatch strategy against the live
+// telemetry without commanding anything. The would-be commands are recorded
+// next to the commands of the active controller and the divergence is
+// reported, so a new strategy can be validated on site before switching over.
+package shadow
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UnitInput is the live state of one BMS/PCS pair the strategies dispatch
+// against
+type UnitInput struct {
+	PCSID           int
+	SOC             float32 // average SOC of the attached BMS strings, %
+	ActualCommandKW float32 // power currently commanded by the active controller
+	MaxDischargeKW  float32
+	MaxChargeKW     float32
+}
+
+// Input is the telemetry snapshot one evaluation runs against
+type Input struct {
+	Mode        string
+	Units       []UnitInput
+	TotalKW     float32 // sum of the actual PCS commands
+	SiteNetKW   float32
+	WindPowerKW float32
+}
+
+// Strategy computes the per-PCS power commands an alternative controller
+// would issue for the same total dispatch
+type Strategy interface {
+	Name() string
+	ComputeCommands(input Input) map[int]float32
+}
+
+// NewStrategy returns the named built-in strategy
+func NewStrategy(name string) (Strategy, error) {
+	switch name {
+	case "equal_share":
+		return equalShareStrategy{}, nil
+	case "soc_proportional":
+		return socProportionalStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown shadow strategy: %s", name)
+	}
+}
+
+// equalShareStrategy splits the total dispatch equally across all units,
+// ignoring their SOC — the baseline the current controller effectively
+// implements
+type equalShareStrategy struct{}
+
+func (equalShareStrategy) Name() string { return "equal_share" }
+
+func (equalShareStrategy) ComputeCommands(input Input) map[int]float32 {
+	commands := make(map[int]float32, len(input.Units))
+	if len(input.Units) == 0 {
+		return commands
+	}
+
+	share := input.TotalKW / float32(len(input.Units))
+	for _, unit := range input.Units {
+		commands[unit.PCSID] = clampToUnit(share, unit)
+	}
+	return commands
+}
+
+// socProportionalStrategy weights the dispatch by SOC: discharge draws more
+// from full units, charge pushes more into empty units, equalizing the SOC
+// across the plant over time
+type socProportionalStrategy struct{}
+
+func (socProportionalStrategy) Name() string { return "soc_proportional" }
+
+func (socProportionalStrategy) ComputeCommands(input Input) map[int]float32 {
+	commands := make(map[int]float32, len(input.Units))
+	if len(input.Units) == 0 {
+		return commands
+	}
+
+	// Discharge weights by SOC, charge weights by remaining headroom
+	var weightSum float32
+	weights := make([]float32, len(input.Units))
+	for i, unit := range input.Units {
+		weight := unit.SOC
+		if input.TotalKW < 0 {
+			weight = 100 - unit.SOC
+		}
+		weights[i] = weight
+		weightSum += weight
+	}
+
+	if weightSum <= 0 {
+		return equalShareStrategy{}.ComputeCommands(input)
+	}
+
+	// Distribute in a stable order so clamp remainders land deterministically
+	order := make([]int, len(input.Units))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return input.Units[order[a]].PCSID < input.Units[order[b]].PCSID
+	})
+
+	remaining := input.TotalKW
+	remainingWeight := weightSum
+	for _, i := range order {
+		unit := input.Units[i]
+
+		share := float32(0)
+		if remainingWeight > 0 {
+			share = remaining * weights[i] / remainingWeight
+		}
+
+		command := clampToUnit(share, unit)
+		commands[unit.PCSID] = command
+		remaining -= command
+		remainingWeight -= weights[i]
+	}
+
+	return commands
+}
+
+// clampToUnit limits a command to the power limits of one unit
+func clampToUnit(command float32, unit UnitInput) float32 {
+	if unit.MaxDischargeKW > 0 && command > unit.MaxDischargeKW {
+		return unit.MaxDischargeKW
+	}
+	if unit.MaxChargeKW > 0 && command < -unit.MaxChargeKW {
+		return -unit.MaxChargeKW
+	}
+	return command
+}
@@ -0,0 +1,45 @@
+package site
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/analyzer/ion7400"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// Module provides the site balance service to the Fx application
+var Module = fx.Module("site",
+	fx.Provide(ProvideService),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideService creates and provides a site balance service instance
+func ProvideService(
+	cfg *config.Config,
+	bmsManager *bms.Manager,
+	windFarmManager *windfarm.Manager,
+	analyzer *ion7400.Service,
+	influxDB *database.InfluxDB,
+	logger *zap.Logger,
+) *Service {
+	return NewService(cfg.EMS, bmsManager, windFarmManager, analyzer, influxDB, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the site balance service
+func RegisterLifecycle(lc fx.Lifecycle, service *Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return service.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			service.Stop()
+			return nil
+		},
+	})
+}
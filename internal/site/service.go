@@ -0,0 +1,165 @@
+// Package site derives the site virtual device: the aggregated net power at
+// the network connection point and the cumulative import/export energy. Every
+// consumer (API, Modbus server, integrators) reads the same derived numbers
+// instead of re-deriving them from the individual subsystems.
+package site
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/analyzer/ion7400"
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/windfarm"
+)
+
+// balanceInterval is the cadence of the balance computation and energy
+// integration
+const balanceInterval = time.Second
+
+// persistEvery persists one out of this many balance samples
+const persistEvery = 10
+
+// Service computes the site energy balance from the BESS, wind farm and
+// auxiliary contributions, preferring the grid analyzer measurement at the
+// connection point when it is available
+type Service struct {
+	config          config.EMSConfig
+	bmsManager      *bms.Manager
+	windFarmManager *windfarm.Manager
+	analyzer        *ion7400.Service
+	influxDB        *database.InfluxDB
+	ctx             context.Context
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	log             *zap.Logger
+
+	mutex       sync.RWMutex
+	lastBalance database.SiteBalanceData
+	lastSample  time.Time
+	importKWh   float64
+	exportKWh   float64
+}
+
+// NewService creates the site balance service
+func NewService(cfg config.EMSConfig, bmsManager *bms.Manager, windFarmManager *windfarm.Manager, analyzer *ion7400.Service, influxDB *database.InfluxDB, logger *zap.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Service{
+		config:          cfg,
+		bmsManager:      bmsManager,
+		windFarmManager: windFarmManager,
+		analyzer:        analyzer,
+		influxDB:        influxDB,
+		ctx:             ctx,
+		cancel:          cancel,
+		log: logger.With(
+			zap.String("service", "site_balance"),
+		),
+	}
+}
+
+// Start starts the balance loop
+func (s *Service) Start() error {
+	s.wg.Go(s.balanceLoop)
+
+	s.log.Info("Site balance service started",
+		zap.Duration("interval", balanceInterval))
+
+	return nil
+}
+
+// Stop stops the balance loop
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	s.log.Info("Site balance service stopped")
+}
+
+// balanceLoop periodically recomputes the balance and persists every
+// persistEvery-th sample
+func (s *Service) balanceLoop() {
+	ticker := time.NewTicker(balanceInterval)
+	defer ticker.Stop()
+
+	samples := 0
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.update(now)
+
+			samples++
+			if samples >= persistEvery {
+				samples = 0
+				if err := s.influxDB.WriteSiteBalanceData(s.GetBalance()); err != nil {
+					s.log.Error("Failed to write site balance data", zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+// update recomputes the balance from the current subsystem state and
+// integrates the net power into the cumulative energy counters
+func (s *Service) update(now time.Time) {
+	bessPower := float32(0)
+	for _, service := range s.bmsManager.GetAllServices() {
+		if !service.IsConnected() {
+			continue
+		}
+		bessPower += float32(service.GetLatestBMSData().Power)
+	}
+
+	// Wind farm measuring data is reported in MW
+	windPower := s.windFarmManager.GetTotalActivePower() * 1000.0
+	auxPower := s.config.LossModel.AuxiliaryPower
+
+	balance := database.SiteBalanceData{
+		Timestamp:     now,
+		BESSPowerKW:   bessPower,
+		WindPowerKW:   windPower,
+		AuxPowerKW:    auxPower,
+		ComputedNetKW: bessPower + windPower - auxPower,
+	}
+
+	// Prefer the grid analyzer measurement at the connection point; the
+	// computed sum is the fallback when the analyzer is offline
+	if s.analyzer != nil && s.analyzer.IsConnected() {
+		balance.Measured = true
+		balance.MeasuredNetKW = s.analyzer.GetLatestData().ActivePowerSum / 1000.0 // W to kW
+	}
+	balance.NetPowerKW = balance.ComputedNetKW
+	if balance.Measured {
+		balance.NetPowerKW = balance.MeasuredNetKW
+	}
+
+	s.mutex.Lock()
+	if !s.lastSample.IsZero() {
+		hours := now.Sub(s.lastSample).Hours()
+		if energy := float64(balance.NetPowerKW) * hours; energy >= 0 {
+			s.exportKWh += energy
+		} else {
+			s.importKWh -= energy
+		}
+	}
+	s.lastSample = now
+	balance.ExportEnergyKWh = s.exportKWh
+	balance.ImportEnergyKWh = s.importKWh
+	s.lastBalance = balance
+	s.mutex.Unlock()
+}
+
+// GetBalance returns the latest site balance
+func (s *Service) GetBalance() database.SiteBalanceData {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.lastBalance
+}
@@ -0,0 +1,132 @@
+// Package telemetry provides bandwidth-aware telemetry profiles for sites
+// on metered links. A profile scales the persistence rate of every device
+// service and trims high-volume detail data; the profile defaults from
+// configuration and can be switched at runtime through the API, though a
+// runtime switch does not survive a restart.
+package telemetry
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// Known telemetry profiles
+const (
+	ProfileFull    = "full"
+	ProfileReduced = "reduced"
+	ProfileMinimal = "minimal"
+)
+
+// Profile describes the persistence behaviour of one telemetry profile
+type Profile struct {
+	Name string `json:"name"`
+
+	// PersistScale multiplies every configured persist interval
+	PersistScale int `json:"persist_scale"`
+
+	// IncludeDetail keeps secondary measurements (equipment, environment,
+	// per-rack status) in the persisted data
+	IncludeDetail bool `json:"include_detail"`
+
+	// IncludeCellData keeps the high-volume per-cell measurements
+	IncludeCellData bool `json:"include_cell_data"`
+}
+
+// profiles maps each profile name to its behaviour, from complete telemetry
+// down to the bare minimum for remote supervision over a small data cap
+var profiles = map[string]Profile{
+	ProfileFull:    {Name: ProfileFull, PersistScale: 1, IncludeDetail: true, IncludeCellData: true},
+	ProfileReduced: {Name: ProfileReduced, PersistScale: 4, IncludeDetail: true, IncludeCellData: false},
+	ProfileMinimal: {Name: ProfileMinimal, PersistScale: 12, IncludeDetail: false, IncludeCellData: false},
+}
+
+// Manager resolves the active telemetry profile from configuration with an
+// optional runtime override
+type Manager struct {
+	log *zap.Logger
+
+	mutex      sync.RWMutex
+	configured string
+	override   string
+}
+
+// NewManager creates a telemetry profile manager from the configured profile
+func NewManager(cfg *config.Config, logger *zap.Logger) *Manager {
+	manager := &Manager{
+		configured: cfg.EMS.TelemetryProfile,
+		log: logger.With(
+			zap.String("component", "telemetry"),
+		),
+	}
+
+	if manager.configured == "" {
+		manager.configured = ProfileFull
+	}
+
+	if manager.configured != ProfileFull {
+		manager.log.Info("Reduced telemetry profile configured",
+			zap.String("profile", manager.configured))
+	}
+
+	return manager
+}
+
+// Current returns the active telemetry profile, preferring a runtime
+// override over configuration
+func (m *Manager) Current() Profile {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	name := m.configured
+	if m.override != "" {
+		name = m.override
+	}
+	return profiles[name]
+}
+
+// Source reports where the active profile comes from (config or runtime)
+func (m *Manager) Source() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if m.override != "" {
+		return "runtime"
+	}
+	return "config"
+}
+
+// SetProfile applies a runtime profile override
+func (m *Manager) SetProfile(name string) error {
+	if _, known := profiles[name]; !known {
+		return fmt.Errorf("unknown telemetry profile: %s", name)
+	}
+
+	m.mutex.Lock()
+	m.override = name
+	m.mutex.Unlock()
+
+	m.log.Warn("Telemetry profile changed at runtime",
+		zap.String("profile", name))
+
+	return nil
+}
+
+// ClearProfile removes the runtime override, falling back to the configured
+// profile
+func (m *Manager) ClearProfile() {
+	m.mutex.Lock()
+	m.override = ""
+	m.mutex.Unlock()
+
+	m.log.Info("Telemetry profile override cleared",
+		zap.String("profile", m.configured))
+}
+
+// Profiles returns every known profile for the diagnostics endpoint
+func (m *Manager) Profiles() []Profile {
+	return []Profile{profiles[ProfileFull], profiles[ProfileReduced], profiles[ProfileMinimal]}
+}
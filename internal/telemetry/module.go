@@ -0,0 +1,10 @@
+package telemetry
+
+import (
+	"go.uber.org/fx"
+)
+
+// Module provides telemetry profiles to the Fx application
+var Module = fx.Module("telemetry",
+	fx.Provide(NewManager),
+)
@@ -0,0 +1,291 @@
+package warranty
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+)
+
+// Histogram binning of the residency exports
+const (
+	socBinWidth         = 5.0 // % SOC per bin
+	temperatureBinWidth = 5.0 // °C per bin
+)
+
+// Exporter assembles the end-of-warranty data package of one BMS string
+// from stored data: cycle history, temperature and SOC residency
+// histograms, alarm history and calibration records
+type Exporter struct {
+	config       *config.Config
+	influxDB     *database.InfluxDB
+	postgreSQL   *database.PostgreSQL
+	bmsManager   *bms.Manager
+	controlLogic *control.Logic
+	log          *zap.Logger
+}
+
+// NewExporter creates a new warranty data package exporter
+func NewExporter(cfg *config.Config, influxDB *database.InfluxDB, postgreSQL *database.PostgreSQL, bmsManager *bms.Manager, controlLogic *control.Logic, logger *zap.Logger) *Exporter {
+	exporterLogger := logger.With(
+		zap.String("component", "warranty_exporter"),
+	)
+
+	return &Exporter{
+		config:       cfg,
+		influxDB:     influxDB,
+		postgreSQL:   postgreSQL,
+		bmsManager:   bmsManager,
+		controlLogic: controlLogic,
+		log:          exporterLogger,
+	}
+}
+
+// manifest describes the assembled package
+type manifest struct {
+	ParkName          string            `json:"park_name"`
+	ConnectionPointID string            `json:"connection_point_id"`
+	BMSID             int               `json:"bms_id"`
+	RangeStart        time.Time         `json:"range_start"`
+	RangeEnd          time.Time         `json:"range_end"`
+	GeneratedAt       time.Time         `json:"generated_at"`
+	FirmwareVersions  map[string]string `json:"firmware_versions,omitempty"`
+	Files             []string          `json:"files"`
+}
+
+// WriteDataPackage assembles the warranty data package of one BMS string
+// for the given time range and writes it as a zip archive
+func (e *Exporter) WriteDataPackage(ctx context.Context, bmsID int, start, end time.Time, w io.Writer) error {
+	if _, err := e.bmsManager.GetService(bmsID); err != nil {
+		return fmt.Errorf("unknown BMS %d: %w", bmsID, err)
+	}
+
+	e.log.Info("Assembling warranty data package",
+		zap.Int("bms_id", bmsID),
+		zap.Time("start", start),
+		zap.Time("end", end))
+
+	zw := zip.NewWriter(w)
+	files := []string{}
+
+	writers := []struct {
+		name  string
+		write func(context.Context, int, time.Time, time.Time, io.Writer) error
+	}{
+		{"cycle_history.csv", e.writeCycleHistory},
+		{"soc_residency.csv", e.writeSOCResidency},
+		{"temperature_histogram.csv", e.writeTemperatureHistogram},
+		{"alarm_history.csv", e.writeAlarmHistory},
+		{"calibration.json", e.writeCalibrationRecords},
+	}
+
+	for _, file := range writers {
+		entry, err := zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s in package: %w", file.name, err)
+		}
+		if err := file.write(ctx, bmsID, start, end, entry); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+		files = append(files, file.name)
+	}
+
+	entry, err := zw.Create("manifest.json")
+	if err != nil {
+		return fmt.Errorf("failed to create manifest in package: %w", err)
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest{
+		ParkName:          e.config.EMS.ParkName,
+		ConnectionPointID: e.config.Plant.ConnectionPointID,
+		BMSID:             bmsID,
+		RangeStart:        start,
+		RangeEnd:          end,
+		GeneratedAt:       time.Now(),
+		FirmwareVersions:  e.config.Plant.FirmwareVersions,
+		Files:             files,
+	}); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return zw.Close()
+}
+
+// writeCycleHistory writes the daily charge/discharge throughput of the
+// string, integrated from the stored power series
+func (e *Exporter) writeCycleHistory(ctx context.Context, bmsID int, start, end time.Time, w io.Writer) error {
+	samples, err := e.influxDB.QueryDeviceFieldSeries(ctx, "bms", "power", bmsID, start, end)
+	if err != nil {
+		return err
+	}
+
+	type day struct {
+		chargeKWh    float64
+		dischargeKWh float64
+	}
+	days := make(map[string]*day)
+	var order []string
+
+	for i := 1; i < len(samples); i++ {
+		dt := samples[i].Timestamp.Sub(samples[i-1].Timestamp).Hours()
+		if dt <= 0 || dt > 1 {
+			continue // Gap in the stored data, do not extrapolate across it
+		}
+
+		key := samples[i].Timestamp.UTC().Format("2006-01-02")
+		entry, ok := days[key]
+		if !ok {
+			entry = &day{}
+			days[key] = entry
+			order = append(order, key)
+		}
+
+		energy := samples[i].Value * dt
+		if energy > 0 {
+			entry.dischargeKWh += energy
+		} else {
+			entry.chargeKWh += -energy
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "charge_kwh", "discharge_kwh"}); err != nil {
+		return err
+	}
+	for _, key := range order {
+		entry := days[key]
+		if err := cw.Write([]string{
+			key,
+			strconv.FormatFloat(entry.chargeKWh, 'f', 2, 64),
+			strconv.FormatFloat(entry.dischargeKWh, 'f', 2, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeSOCResidency writes the SOC residency histogram of the string
+func (e *Exporter) writeSOCResidency(ctx context.Context, bmsID int, start, end time.Time, w io.Writer) error {
+	samples, err := e.influxDB.QueryDeviceFieldSeries(ctx, "bms", "soc", bmsID, start, end)
+	if err != nil {
+		return err
+	}
+	return writeResidencyHistogram(w, samples, "soc_bin_percent", socBinWidth)
+}
+
+// writeTemperatureHistogram writes the maximum cell temperature residency
+// histogram of the string
+func (e *Exporter) writeTemperatureHistogram(ctx context.Context, bmsID int, start, end time.Time, w io.Writer) error {
+	samples, err := e.influxDB.QueryDeviceFieldSeries(ctx, "bms", "max_cell_temperature", bmsID, start, end)
+	if err != nil {
+		return err
+	}
+	return writeResidencyHistogram(w, samples, "temperature_bin_celsius", temperatureBinWidth)
+}
+
+// writeResidencyHistogram bins the samples by value and writes the
+// residency hours per bin, weighting each sample with the time until the
+// next one
+func writeResidencyHistogram(w io.Writer, samples []database.FieldSample, binHeader string, binWidth float64) error {
+	hours := make(map[int]float64)
+
+	for i := 1; i < len(samples); i++ {
+		dt := samples[i].Timestamp.Sub(samples[i-1].Timestamp).Hours()
+		if dt <= 0 || dt > 1 {
+			continue // Gap in the stored data, do not extrapolate across it
+		}
+		bin := int(samples[i-1].Value / binWidth)
+		hours[bin] += dt
+	}
+
+	bins := make([]int, 0, len(hours))
+	for bin := range hours {
+		bins = append(bins, bin)
+	}
+	sort.Ints(bins)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{binHeader, "residency_hours"}); err != nil {
+		return err
+	}
+	for _, bin := range bins {
+		if err := cw.Write([]string{
+			fmt.Sprintf("%.0f-%.0f", float64(bin)*binWidth, float64(bin+1)*binWidth),
+			strconv.FormatFloat(hours[bin], 'f', 2, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeAlarmHistory writes the alarm history of the string, including the
+// per-rack alarms
+func (e *Exporter) writeAlarmHistory(ctx context.Context, bmsID int, start, end time.Time, w io.Writer) error {
+	alarms, err := e.postgreSQL.GetAlarmsInTimeRange(ctx, start, end)
+	if err != nil {
+		return err
+	}
+
+	prefix := fmt.Sprintf("BMS_%d", bmsID)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"timestamp", "alarm_type", "alarm_code", "severity", "message", "active"}); err != nil {
+		return err
+	}
+	for _, alarm := range alarms {
+		if alarm.AlarmType != prefix && !strings.HasPrefix(alarm.AlarmType, prefix+"_") {
+			continue
+		}
+		if err := cw.Write([]string{
+			alarm.Timestamp.UTC().Format(time.RFC3339),
+			alarm.AlarmType,
+			strconv.Itoa(int(alarm.AlarmCode)),
+			alarm.Severity,
+			alarm.Message,
+			strconv.FormatBool(alarm.Active),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCalibrationRecords writes the SOC calibration state and the rack
+// throughput counters of the string
+func (e *Exporter) writeCalibrationRecords(ctx context.Context, bmsID int, start, end time.Time, w io.Writer) error {
+	service, err := e.bmsManager.GetService(bmsID)
+	if err != nil {
+		return err
+	}
+
+	record := struct {
+		Calibration    control.SOCCalibrationStatus `json:"soc_calibration"`
+		RackThroughput []bms.RackThroughput         `json:"rack_throughput"`
+	}{
+		Calibration:    e.controlLogic.GetSOCCalibrationStatus(),
+		RackThroughput: service.GetRackThroughput(),
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(record)
+}
@@ -0,0 +1,28 @@
+package warranty
+
+import (
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/bms"
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/control"
+	"powerkonnekt/ems/internal/database"
+)
+
+// Module provides the warranty data package exporter to the Fx application
+var Module = fx.Module("warranty",
+	fx.Provide(ProvideExporter),
+)
+
+// ProvideExporter creates and provides a warranty exporter instance
+func ProvideExporter(
+	cfg *config.Config,
+	influxDB *database.InfluxDB,
+	postgreSQL *database.PostgreSQL,
+	bmsManager *bms.Manager,
+	controlLogic *control.Logic,
+	logger *zap.Logger,
+) *Exporter {
+	return NewExporter(cfg, influxDB, postgreSQL, bmsManager, controlLogic, logger)
+}
@@ -0,0 +1,34 @@
+package weather
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// Module provides the secondary weather source to the Fx application
+var Module = fx.Module("weather",
+	fx.Provide(ProvideProvider),
+	fx.Invoke(RegisterLifecycle),
+)
+
+// ProvideProvider creates and provides the weather provider
+func ProvideProvider(cfg *config.Config, logger *zap.Logger) *Provider {
+	return NewProvider(cfg.Weather, logger)
+}
+
+// RegisterLifecycle registers lifecycle hooks for the weather provider
+func RegisterLifecycle(lc fx.Lifecycle, provider *Provider) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return provider.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			provider.Stop()
+			return nil
+		},
+	})
+}
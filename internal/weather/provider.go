@@ -0,0 +1,150 @@
+// Package weather polls a secondary weather source (a local station gateway
+// or an external weather API) over HTTP. The wind farm services fall back to
+// it when the FCU weather registers are frozen or implausible.
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// Observation is the current weather observation of the secondary source
+type Observation struct {
+	Timestamp           time.Time `json:"timestamp"`
+	WindSpeed           float32   `json:"wind_speed"`           // m/s
+	WindDirection       float32   `json:"wind_direction"`       // degrees
+	Temperature         float32   `json:"temperature"`          // °C
+	AtmosphericPressure uint16    `json:"atmospheric_pressure"` // mbar
+	Humidity            float32   `json:"humidity"`             // %
+}
+
+// Provider polls the configured weather endpoint and keeps the latest
+// observation available
+type Provider struct {
+	config config.WeatherConfig
+	client *http.Client
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	log    *zap.Logger
+
+	mutex           sync.RWMutex
+	lastObservation Observation
+	lastFetched     time.Time
+}
+
+// NewProvider creates a new weather provider
+func NewProvider(cfg config.WeatherConfig, logger *zap.Logger) *Provider {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Provider{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		ctx:    ctx,
+		cancel: cancel,
+		log: logger.With(
+			zap.String("component", "weather"),
+			zap.String("url", cfg.URL),
+		),
+	}
+}
+
+// Start launches the poll loop when the secondary source is enabled
+func (p *Provider) Start() error {
+	if !p.config.Enabled {
+		return nil
+	}
+
+	p.wg.Go(p.pollLoop)
+	p.log.Info("Secondary weather source started",
+		zap.Duration("refresh_interval", p.config.RefreshInterval))
+	return nil
+}
+
+// Stop stops the provider
+func (p *Provider) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// GetCurrent returns the latest observation and whether it is fresh enough
+// to substitute the FCU weather data
+func (p *Provider) GetCurrent() (Observation, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.lastFetched.IsZero() || time.Since(p.lastFetched) > 3*p.config.RefreshInterval {
+		return Observation{}, false
+	}
+	return p.lastObservation, true
+}
+
+// pollLoop fetches the observation on startup and then periodically
+func (p *Provider) pollLoop() {
+	p.fetch()
+
+	ticker := time.NewTicker(p.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.fetch()
+		}
+	}
+}
+
+// fetch retrieves the current observation from the configured endpoint
+func (p *Provider) fetch() {
+	observation, err := p.request()
+	if err != nil {
+		p.log.Warn("Failed to fetch secondary weather observation", zap.Error(err))
+		return
+	}
+
+	if observation.Timestamp.IsZero() {
+		observation.Timestamp = time.Now()
+	}
+
+	p.mutex.Lock()
+	p.lastObservation = observation
+	p.lastFetched = time.Now()
+	p.mutex.Unlock()
+}
+
+// request performs the HTTP request and decodes the observation
+func (p *Provider) request() (Observation, error) {
+	request, err := http.NewRequestWithContext(p.ctx, http.MethodGet, p.config.URL, nil)
+	if err != nil {
+		return Observation{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	if p.config.APIKey != "" {
+		request.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return Observation{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return Observation{}, fmt.Errorf("weather endpoint returned status %d", response.StatusCode)
+	}
+
+	var observation Observation
+	if err := json.NewDecoder(response.Body).Decode(&observation); err != nil {
+		return Observation{}, fmt.Errorf("failed to decode observation: %w", err)
+	}
+	return observation, nil
+}
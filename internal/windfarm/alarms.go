@@ -0,0 +1,101 @@
+package windfarm
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// fcuAlarmDefinitions maps the known ENERCON FCU alarm/event codes to their
+// messages and severities; codes missing here are still alarmed with a
+// generic message so new firmware codes are not lost
+var fcuAlarmDefinitions = map[uint16]struct {
+	Message  string
+	Severity string
+}{
+	101: {"FCU communication to WEC lost", "HIGH"},
+	102: {"FCU redundancy switchover", "MEDIUM"},
+	110: {"Grid protection trip", "HIGH"},
+	111: {"Grid overfrequency", "MEDIUM"},
+	112: {"Grid underfrequency", "MEDIUM"},
+	113: {"Grid overvoltage", "MEDIUM"},
+	114: {"Grid undervoltage", "MEDIUM"},
+	120: {"Setpoint out of range", "LOW"},
+	121: {"Setpoint timeout", "MEDIUM"},
+	130: {"Meteo station failure", "LOW"},
+	140: {"Rapid downward signal active", "MEDIUM"},
+	150: {"WEC availability degraded", "LOW"},
+}
+
+// GetFCUAlarmMessage returns the alarm message based on code
+func GetFCUAlarmMessage(code uint16) string {
+	if def, exists := fcuAlarmDefinitions[code]; exists {
+		return def.Message
+	}
+	return fmt.Sprintf("Unknown FCU alarm %d", code)
+}
+
+// GetFCUAlarmSeverity returns the alarm severity based on code
+func GetFCUAlarmSeverity(code uint16) string {
+	if def, exists := fcuAlarmDefinitions[code]; exists {
+		return def.Severity
+	}
+	return "MEDIUM"
+}
+
+// processAlarmList compares the FCU alarm list against the previous state,
+// submits edge-triggered alarms with wind farm attribution and persists the
+// raw entries for ENERCON support cases
+func (s *Service) processAlarmList(entries []database.WindFarmAlarmData) {
+	timestamp := time.Now()
+
+	activeCodes := make(map[uint16]bool, len(entries))
+	for _, entry := range entries {
+		if entry.Active {
+			activeCodes[entry.Code] = true
+		}
+	}
+
+	var changes []database.WindFarmAlarmData
+
+	s.mutex.Lock()
+	for _, entry := range entries {
+		if entry.Active && !s.previousAlarmStates[entry.Code] {
+			changes = append(changes, entry)
+		}
+	}
+	// Codes no longer listed as active are considered cleared
+	for code := range s.previousAlarmStates {
+		if !activeCodes[code] {
+			changes = append(changes, database.WindFarmAlarmData{
+				Timestamp: timestamp,
+				ID:        s.config.ID,
+				Code:      code,
+				Status:    FCUAlarmCleared,
+				Active:    false,
+			})
+		}
+	}
+	s.previousAlarmStates = activeCodes
+	s.mutex.Unlock()
+
+	for _, change := range changes {
+		s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+			Timestamp: change.Timestamp,
+			AlarmType: fmt.Sprintf("WINDFARM_%d", s.config.ID),
+			AlarmCode: change.Code,
+			Message:   GetFCUAlarmMessage(change.Code),
+			Severity:  GetFCUAlarmSeverity(change.Code),
+			Active:    change.Active,
+		})
+
+		if err := s.influxDB.WriteWindFarmAlarmData(change); err != nil {
+			s.log.Error("Failed to write FCU alarm entry",
+				zap.Uint16("code", change.Code),
+				zap.Error(err))
+		}
+	}
+}
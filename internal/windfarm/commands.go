@@ -59,6 +59,8 @@ func (s *Service) SetPowerSetpoint(setpoint float32) error {
 	s.commandState.LastUpdated = time.Now()
 	s.mutex.Unlock()
 
+	s.persistCommandState()
+
 	s.log.Info("Power setpoint set", zap.Float32("setpoint", setpoint))
 	return nil
 }
@@ -81,6 +83,8 @@ func (s *Service) SetReactivePowerSetpoint(setpoint float32) error {
 	s.commandState.LastUpdated = time.Now()
 	s.mutex.Unlock()
 
+	s.persistCommandState()
+
 	s.log.Info("Reactive power setpoint set", zap.Float32("setpoint", setpoint))
 	return nil
 }
@@ -103,6 +107,8 @@ func (s *Service) SetPowerFactorSetpoint(setpoint float32) error {
 	s.commandState.LastUpdated = time.Now()
 	s.mutex.Unlock()
 
+	s.persistCommandState()
+
 	s.log.Info("Power factor setpoint set", zap.Float32("setpoint", setpoint))
 	return nil
 }
@@ -118,6 +124,8 @@ func (s *Service) StartWindFarm() error {
 	s.commandState.LastUpdated = time.Now()
 	s.mutex.Unlock()
 
+	s.persistCommandState()
+
 	s.log.Info("Wind farm start command sent")
 	return nil
 }
@@ -133,6 +141,8 @@ func (s *Service) StopWindFarm() error {
 	s.commandState.LastUpdated = time.Now()
 	s.mutex.Unlock()
 
+	s.persistCommandState()
+
 	s.log.Info("Wind farm stop command sent")
 	return nil
 }
@@ -153,6 +163,8 @@ func (s *Service) SetRapidDownwardSignal(on bool) error {
 	s.commandState.LastUpdated = time.Now()
 	s.mutex.Unlock()
 
+	s.persistCommandState()
+
 	s.log.Info("Rapid downward signal set", zap.Bool("on", on))
 	return nil
 }
@@ -0,0 +1,52 @@
+package windfarm
+
+import (
+	"powerkonnekt/ems/internal/registerdoc"
+)
+
+// RegisterDocumentation describes the ENERCON FCU register map polled by the
+// wind farm service, derived from the protocol constants
+func RegisterDocumentation() registerdoc.Device {
+	return registerdoc.Device{
+		Device:      "windfarm",
+		Description: "ENERCON FCU (Farm Control Unit)",
+		Blocks: []registerdoc.Block{
+			{
+				Name:      "return_values",
+				Table:     "holding",
+				StartAddr: ReturnValuesStartAddr,
+				Length:    ReturnValuesLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:      "measuring_data",
+				Table:     "holding",
+				StartAddr: MeasuringDataStartAddr,
+				Length:    MeasuringDataLength,
+				Direction: registerdoc.DirectionRead,
+			},
+			{
+				Name:        "alarm_list",
+				Table:       "holding",
+				StartAddr:   AlarmListStartAddr,
+				Length:      AlarmListLength,
+				Direction:   registerdoc.DirectionRead,
+				Description: "Pairs of alarm code and status word, code 0 marks an empty slot",
+			},
+			{
+				Name:      "control",
+				Table:     "holding",
+				StartAddr: HeartbeatAddr,
+				Direction: registerdoc.DirectionWrite,
+				Points: []registerdoc.Point{
+					{Address: HeartbeatAddr, Name: "heartbeat", Direction: registerdoc.DirectionWrite, Description: "Incrementing EMS heartbeat counter"},
+					{Address: PSetpointAddr, Name: "active_power_setpoint", Unit: "%", Direction: registerdoc.DirectionWrite, Description: "Percent of rated power"},
+					{Address: QSetpointAddr, Name: "reactive_power_setpoint", Unit: "%", Direction: registerdoc.DirectionWrite},
+					{Address: PowerFactorSetpointAddr, Name: "power_factor_setpoint", Scale: "0.001", Direction: registerdoc.DirectionWrite},
+					{Address: WindFarmStartStopAddr, Name: "start_stop", Direction: registerdoc.DirectionWrite, Description: "0 = start, 1 = stop"},
+					{Address: RapidDownwardSignalAddr, Name: "rapid_downward", Direction: registerdoc.DirectionWrite, Description: "0 = off, 1 = on"},
+				},
+			},
+		},
+	}
+}
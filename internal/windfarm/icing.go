@@ -0,0 +1,108 @@
+package windfarm
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// IcingAdvisoryAlarmCode identifies the advisory alarm raised while probable
+// rotor icing is detected
+const IcingAdvisoryAlarmCode = 9008
+
+// Icing detection thresholds: icing is probable when the weather is in the
+// icing band, the wind would support production and the actual power falls
+// well short of the possible power at the measured wind speed.
+const (
+	icingTempMin       = -10.0 // °C
+	icingTempMax       = 3.0   // °C
+	icingHumidityMin   = 85.0  // %
+	icingWindSpeedMin  = 3.0   // m/s, around cut-in
+	icingPowerRatioMax = 0.7   // actual vs possible power
+	icingMinPossibleMW = 0.1   // MW, below this the ratio is meaningless
+)
+
+// icingDwellTime is how long the icing conditions have to persist before the
+// advisory is raised, filtering out short power-curve deviations
+const icingDwellTime = 10 * time.Minute
+
+// updateIcingDetection combines the weather data with the power-curve
+// deviation to detect probable icing. The advisory alarm is raised after the
+// conditions persist for icingDwellTime and cleared as soon as they end; the
+// result annotates the persisted measuring data.
+func (s *Service) updateIcingDetection(measuring database.WindFarmMeasuringData, weather database.WindFarmWeatherData) bool {
+	now := measuring.Timestamp
+	conditions := icingConditions(measuring, weather)
+
+	s.mutex.Lock()
+	if conditions {
+		if s.icingConditionSince.IsZero() {
+			s.icingConditionSince = now
+		}
+	} else {
+		s.icingConditionSince = time.Time{}
+	}
+
+	suspected := conditions && now.Sub(s.icingConditionSince) >= icingDwellTime
+	changed := suspected != s.icingSuspected
+	s.icingSuspected = suspected
+	s.mutex.Unlock()
+
+	if changed {
+		if suspected {
+			s.log.Warn("Probable icing detected",
+				zap.Float32("temperature", weather.OutsideTemperature),
+				zap.Float32("humidity", weather.AirHumidity),
+				zap.Float32("wind_speed", measuring.WindSpeed),
+				zap.Float32("actual_power", measuring.ActivePowerNCP),
+				zap.Float32("possible_power", measuring.PossibleWECPower))
+		} else {
+			s.log.Info("Icing conditions cleared")
+		}
+		s.submitIcingAlarm(suspected)
+	}
+
+	return suspected
+}
+
+// icingConditions reports whether the current sample matches the icing
+// pattern: cold and humid weather with the actual power falling short of the
+// possible power at the measured wind speed
+func icingConditions(measuring database.WindFarmMeasuringData, weather database.WindFarmWeatherData) bool {
+	if weather.OutsideTemperature < icingTempMin || weather.OutsideTemperature > icingTempMax {
+		return false
+	}
+	if weather.AirHumidity < icingHumidityMin {
+		return false
+	}
+	if measuring.WindSpeed < icingWindSpeedMin {
+		return false
+	}
+
+	// Prefer the configured power curve over the possible power reported by
+	// the FCU, which itself degrades under icing
+	reference := measuring.PossibleWECPower
+	if measuring.ExpectedPower > 0 {
+		reference = measuring.ExpectedPower
+	}
+	if reference < icingMinPossibleMW {
+		return false
+	}
+
+	return measuring.ActivePowerNCP < reference*icingPowerRatioMax
+}
+
+// submitIcingAlarm raises or clears the icing advisory alarm
+func (s *Service) submitIcingAlarm(active bool) {
+	s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+		Timestamp: time.Now(),
+		AlarmType: fmt.Sprintf("WINDFARM_%d", s.config.ID),
+		AlarmCode: IcingAdvisoryAlarmCode,
+		Message:   "Probable icing: power-curve deviation under icing weather conditions",
+		Severity:  "LOW",
+		Active:    active,
+	})
+}
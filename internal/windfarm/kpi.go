@@ -0,0 +1,107 @@
+package windfarm
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// KPIReport exposes the daily wind farm KPIs: the running values of the
+// current day and the finalized values of the previous day
+type KPIReport struct {
+	Current  database.WindFarmKPIData `json:"current"`
+	Previous database.WindFarmKPIData `json:"previous"`
+}
+
+// kpiDayOf returns the start of the KPI day the timestamp belongs to
+func kpiDayOf(timestamp time.Time) time.Time {
+	return time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, timestamp.Location())
+}
+
+// recordKPISample accumulates one measuring data sample into the daily KPI
+// counters. When the sample starts a new day the previous day is finalized,
+// persisted and kept available for reports.
+func (s *Service) recordKPISample(data database.WindFarmMeasuringData) {
+	day := kpiDayOf(data.Timestamp)
+
+	var finished *database.WindFarmKPIData
+
+	s.mutex.Lock()
+	if !s.kpiDay.IsZero() && !day.Equal(s.kpiDay) {
+		report := s.buildKPIReportLocked()
+		s.lastKPIReport = report
+		finished = &report
+
+		s.kpiSamples = 0
+		s.kpiAvailabilitySum = 0
+		s.kpiCommunicationSum = 0
+		s.kpiActualPowerSum = 0
+		s.kpiPossiblePowerSum = 0
+	}
+	s.kpiDay = day
+
+	// The performance reference is the configured power curve when present,
+	// otherwise the possible power reported by the FCU
+	possible := data.PossibleWECPower
+	if data.ExpectedPower > 0 {
+		possible = data.ExpectedPower
+	}
+
+	s.kpiSamples++
+	s.kpiAvailabilitySum += float64(data.WECAvailability)
+	s.kpiCommunicationSum += float64(data.WECCommunication)
+	s.kpiActualPowerSum += float64(data.ActivePowerNCP)
+	s.kpiPossiblePowerSum += float64(possible)
+	s.mutex.Unlock()
+
+	if finished != nil {
+		if err := s.influxDB.WriteWindFarmKPIData(*finished); err != nil {
+			s.log.Error("Failed to write wind farm KPI data", zap.Error(err))
+		}
+
+		s.log.Info("Daily wind farm KPIs finalized",
+			zap.Time("day", finished.Timestamp),
+			zap.Float32("availability", finished.Availability),
+			zap.Float32("performance_ratio", finished.PerformanceRatio),
+			zap.Float32("communication_quality", finished.CommunicationQuality),
+			zap.Uint64("samples", finished.Samples))
+	}
+}
+
+// buildKPIReportLocked computes the KPIs from the accumulated counters; the
+// caller must hold the mutex
+func (s *Service) buildKPIReportLocked() database.WindFarmKPIData {
+	report := database.WindFarmKPIData{
+		Timestamp: s.kpiDay,
+		ID:        s.config.ID,
+		Samples:   s.kpiSamples,
+	}
+
+	if s.kpiSamples > 0 {
+		samples := float64(s.kpiSamples)
+		report.Availability = float32(s.kpiAvailabilitySum / samples)
+		report.CommunicationQuality = float32(s.kpiCommunicationSum / samples)
+	}
+
+	// Performance ratio relates the produced energy to the possible energy;
+	// with equidistant samples the power sums cancel out to the same ratio
+	if s.kpiPossiblePowerSum > 0 {
+		report.PerformanceRatio = float32(s.kpiActualPowerSum / s.kpiPossiblePowerSum)
+	}
+
+	return report
+}
+
+// GetKPIReport returns the running KPIs of the current day and the finalized
+// KPIs of the previous day
+func (s *Service) GetKPIReport() KPIReport {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return KPIReport{
+		Current:  s.buildKPIReportLocked(),
+		Previous: s.lastKPIReport,
+	}
+}
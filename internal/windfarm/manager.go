@@ -7,8 +7,11 @@ import (
 
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
+	"powerkonnekt/ems/internal/weather"
 )
 
 // Manager manages multiple Wind Farm services
@@ -20,7 +23,7 @@ type Manager struct {
 }
 
 // NewManager creates a new Wind Farm manager
-func NewManager(configs []config.WindFarmConfig, influxDB *database.InfluxDB, logger *zap.Logger) *Manager {
+func NewManager(configs []config.WindFarmConfig, influxDB *database.InfluxDB, postgreSQL *database.PostgreSQL, alarmManager *alarm.Manager, telemetryManager *telemetry.Manager, weatherProvider *weather.Provider, logger *zap.Logger) *Manager {
 	managerLogger := logger.With(zap.String("component", "windfarm_manager"))
 
 	manager := &Manager{
@@ -29,7 +32,7 @@ func NewManager(configs []config.WindFarmConfig, influxDB *database.InfluxDB, lo
 	}
 
 	for _, cfg := range configs {
-		service := NewService(cfg, influxDB, logger)
+		service := NewService(cfg, influxDB, postgreSQL, alarmManager, telemetryManager, weatherProvider, logger)
 		manager.services[cfg.ID] = service
 	}
 
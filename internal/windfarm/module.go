@@ -6,8 +6,11 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/telemetry"
+	"powerkonnekt/ems/internal/weather"
 )
 
 // Module provides wind farm management functionality to the Fx application
@@ -20,9 +23,13 @@ var Module = fx.Module("windfarm",
 func ProvideManager(
 	cfg *config.Config,
 	influxDB *database.InfluxDB,
+	postgreSQL *database.PostgreSQL,
+	alarmManager *alarm.Manager,
+	telemetryManager *telemetry.Manager,
+	weatherProvider *weather.Provider,
 	logger *zap.Logger,
 ) *Manager {
-	return NewManager(cfg.WindFarm, influxDB, logger)
+	return NewManager(cfg.WindFarm, influxDB, postgreSQL, alarmManager, telemetryManager, weatherProvider, logger)
 }
 
 // RegisterLifecycle registers lifecycle hooks for the WindFarm manager
@@ -1,6 +1,7 @@
 package windfarm
 
 import (
+	"fmt"
 	"time"
 
 	"powerkonnekt/ems/internal/database"
@@ -9,9 +10,9 @@ import (
 
 // parseMeasuringData converts raw MODBUS data to WindFarmMeasuringData structure
 // Expects data starting from register 700 (MeasuringDataStartAddr)
-func parseMeasuringData(data []byte, id int) database.WindFarmMeasuringData {
+func parseMeasuringData(data []byte, id int) (database.WindFarmMeasuringData, error) {
 	if len(data) < MeasuringDataLength*2 {
-		return database.WindFarmMeasuringData{Timestamp: time.Now(), ID: id}
+		return database.WindFarmMeasuringData{}, fmt.Errorf("measuring data too short: got %d bytes, want %d", len(data), MeasuringDataLength*2)
 	}
 
 	return database.WindFarmMeasuringData{
@@ -34,14 +35,14 @@ func parseMeasuringData(data []byte, id int) database.WindFarmMeasuringData {
 		AbsoluteMinReactivePower:  utils.Scale(utils.FromBytes[int16](data[86:88]), float32(0.01)),  // 743 - Absolute min Q (MVar)
 		RelativeMaxReactivePower:  utils.Scale(utils.FromBytes[int16](data[88:90]), float32(0.01)),  // 744 - Relative max Q (%)
 		AbsoluteMaxReactivePower:  utils.Scale(utils.FromBytes[int16](data[90:92]), float32(0.01)),  // 745 - Absolute max Q (MVar)
-	}
+	}, nil
 }
 
 // parseStatusData converts raw MODBUS data to WindFarmStatusData structure
 // Expects data starting from register 649 (ReturnValuesStartAddr)
-func parseStatusData(data []byte, id int) database.WindFarmStatusData {
+func parseStatusData(data []byte, id int) (database.WindFarmStatusData, error) {
 	if len(data) < ReturnValuesLength*2 {
-		return database.WindFarmStatusData{Timestamp: time.Now(), ID: id}
+		return database.WindFarmStatusData{}, fmt.Errorf("status data too short: got %d bytes, want %d", len(data), ReturnValuesLength*2)
 	}
 
 	fcuOnlineStatus := utils.FromBytes[uint16](data[2:4])     // 650 - FCU online status
@@ -59,14 +60,14 @@ func parseStatusData(data []byte, id int) database.WindFarmStatusData {
 		ReactivePowerControlMode:  reactivePowerMode,
 		WindFarmRunning:           windFarmStatus == WindFarmStart,
 		RapidDownwardSignalActive: rapidDownward == RapidDownwardOn,
-	}
+	}, nil
 }
 
 // parseSetpointData converts raw MODBUS data to WindFarmSetpointData structure
 // Expects data starting from register 649 (ReturnValuesStartAddr)
-func parseSetpointData(data []byte, id int) database.WindFarmSetpointData {
+func parseSetpointData(data []byte, id int) (database.WindFarmSetpointData, error) {
 	if len(data) < ReturnValuesLength*2 {
-		return database.WindFarmSetpointData{Timestamp: time.Now(), ID: id}
+		return database.WindFarmSetpointData{}, fmt.Errorf("setpoint data too short: got %d bytes, want %d", len(data), ReturnValuesLength*2)
 	}
 
 	return database.WindFarmSetpointData{
@@ -89,14 +90,14 @@ func parseSetpointData(data []byte, id int) database.WindFarmSetpointData {
 		PowerFactorCurrent: utils.Scale(utils.FromBytes[int16](data[52:54]), float32(0.001)), // 675 - Power factor current
 		USetpointCurrent:   utils.Scale(utils.FromBytes[int16](data[54:56]), float32(0.01)),  // 676 - U setpoint current
 		QdUSetpointCurrent: utils.Scale(utils.FromBytes[int16](data[56:58]), float32(0.01)),  // 677 - Q(dU) setpoint current
-	}
+	}, nil
 }
 
 // parseWeatherData converts raw MODBUS data to WindFarmWeatherData structure
 // Expects data starting from register 699 (MeasuringDataStartAddr)
-func parseWeatherData(data []byte, id int) database.WindFarmWeatherData {
+func parseWeatherData(data []byte, id int) (database.WindFarmWeatherData, error) {
 	if len(data) < MeasuringDataLength*2 {
-		return database.WindFarmWeatherData{Timestamp: time.Now(), ID: id}
+		return database.WindFarmWeatherData{}, fmt.Errorf("weather data too short: got %d bytes, want %d", len(data), MeasuringDataLength*2)
 	}
 
 	return database.WindFarmWeatherData{
@@ -111,15 +112,45 @@ func parseWeatherData(data []byte, id int) database.WindFarmWeatherData {
 		SolarRadiation:           utils.Scale(utils.FromBytes[uint16](data[72:74]), float32(0.1)),  // 735 - Solar radiation (W/m²)
 		WindFarmCommunication:    utils.FromBytes[uint16](data[74:76]),                             // 736 - Wind farm comm (%)
 		WeatherMeasurementsCount: utils.FromBytes[uint16](data[76:78]),                             // 737 - Weather measurements count
-	}
+	}, nil
 }
 
 // parseFCUMode extracts FCU mode from measuring data
 // Expects data starting from register 699 (MeasuringDataStartAddr)
-func parseFCUMode(data []byte) uint16 {
+func parseFCUMode(data []byte) (uint16, error) {
 	if len(data) < MeasuringDataLength*2 {
-		return 0
+		return 0, fmt.Errorf("measuring data too short: got %d bytes, want %d", len(data), MeasuringDataLength*2)
 	}
 	// FCU mode is at register 758, which is offset 118 bytes from 699 (59 registers * 2 bytes)
-	return utils.FromBytes[uint16](data[118:120])
+	return utils.FromBytes[uint16](data[118:120]), nil
+}
+
+// parseAlarmList converts raw MODBUS data to FCU alarm list entries
+// Expects data starting from register 800 (AlarmListStartAddr); each entry
+// is an alarm code followed by its status word, code 0 marks an empty slot
+func parseAlarmList(data []byte, id int) ([]database.WindFarmAlarmData, error) {
+	if len(data) < AlarmListLength*2 {
+		return nil, fmt.Errorf("alarm list too short: got %d bytes, want %d", len(data), AlarmListLength*2)
+	}
+
+	timestamp := time.Now()
+	var entries []database.WindFarmAlarmData
+
+	for i := range AlarmListEntries {
+		code := utils.FromBytes[uint16](data[i*4 : i*4+2])
+		if code == 0 {
+			continue
+		}
+		status := utils.FromBytes[uint16](data[i*4+2 : i*4+4])
+
+		entries = append(entries, database.WindFarmAlarmData{
+			Timestamp: timestamp,
+			ID:        id,
+			Code:      code,
+			Status:    status,
+			Active:    status == FCUAlarmActive,
+		})
+	}
+
+	return entries, nil
 }
@@ -0,0 +1,52 @@
+package windfarm
+
+import (
+	"testing"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// fuzzParser runs a shared fuzz harness for the wind farm register parsers:
+// the parser must never panic, must reject frames shorter than the register
+// block with an error, and must accept full-length frames
+func fuzzParser[T any](f *testing.F, registerCount int, parse func(data []byte, id int) (T, error)) {
+	f.Helper()
+
+	f.Add([]byte{})
+	f.Add(make([]byte, registerCount*2-1))
+	f.Add(make([]byte, registerCount*2))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := parse(data, 1)
+		if (len(data) < registerCount*2) != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+	})
+}
+
+func FuzzParseMeasuringData(f *testing.F) {
+	fuzzParser[database.WindFarmMeasuringData](f, MeasuringDataLength, parseMeasuringData)
+}
+
+func FuzzParseStatusData(f *testing.F) {
+	fuzzParser[database.WindFarmStatusData](f, ReturnValuesLength, parseStatusData)
+}
+
+func FuzzParseSetpointData(f *testing.F) {
+	fuzzParser[database.WindFarmSetpointData](f, ReturnValuesLength, parseSetpointData)
+}
+
+func FuzzParseWeatherData(f *testing.F) {
+	fuzzParser[database.WindFarmWeatherData](f, MeasuringDataLength, parseWeatherData)
+}
+
+func FuzzParseFCUMode(f *testing.F) {
+	f.Add([]byte{})
+	f.Add(make([]byte, MeasuringDataLength*2-1))
+	f.Add(make([]byte, MeasuringDataLength*2))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, err := parseFCUMode(data)
+		if (len(data) < MeasuringDataLength*2) != (err != nil) {
+			t.Errorf("unexpected error state for %d-byte frame: %v", len(data), err)
+		}
+	})
+}
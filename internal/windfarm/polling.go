@@ -5,10 +5,33 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/config"
+	"powerkonnekt/ems/internal/pollgroup"
+	"powerkonnekt/ems/pkg/modbus"
 )
 
 // dataPollLoop periodically reads data from the Wind Farm FCU
 func (s *Service) dataPollLoop() {
+	s.client.OnConnectionChange(func(connected bool) {
+		if connected {
+			s.log.Info("Wind Farm FCU connection established")
+
+			// The FCU may have lost or changed setpoints during the
+			// outage; reconcile against the mirrors on the next read
+			s.mutex.Lock()
+			s.reconcilePending = true
+			s.mutex.Unlock()
+		} else {
+			s.log.Warn("Wind Farm FCU connection lost, reconnecting in the background")
+		}
+	})
+	s.client.StartAutoReconnect(s.ctx, modbus.ReconnectSettings{
+		InitialDelay: s.config.ReconnectDelay,
+		MaxDelay:     s.config.ReconnectMaxDelay,
+		MaxRetries:   s.config.ReconnectMaxRetries,
+	})
+
 	if err := s.client.Connect(s.ctx); err != nil {
 		s.log.Warn("Initial Modbus connection failed", zap.Error(err))
 	}
@@ -25,9 +48,8 @@ func (s *Service) dataPollLoop() {
 		case <-s.ctx.Done():
 			return
 		case <-timer.C:
-			if !s.client.IsConnected() {
-				s.handleConnectionError()
-			} else {
+			finish := s.pollWatch.Begin()
+			if s.client.IsConnected() {
 				startTime := time.Now()
 				if err := s.readAllData(); err != nil {
 					s.log.Error("Error reading data", zap.Error(err))
@@ -46,6 +68,7 @@ func (s *Service) dataPollLoop() {
 						zap.Duration("interval", interval))
 				}
 			}
+			finish()
 
 			// Calculate next aligned time and reset timer
 			nextTick = time.Now().Truncate(interval).Add(interval)
@@ -54,50 +77,75 @@ func (s *Service) dataPollLoop() {
 	}
 }
 
-// handleConnectionError attempts to reconnect to the FCU
-func (s *Service) handleConnectionError() {
-	s.log.Warn("Wind Farm FCU connection lost, initiating reconnection procedure")
-	s.client.Disconnect()
-
-	reconnectAttempts := 0
-	timer := time.NewTimer(s.config.ReconnectDelay)
-	defer timer.Stop()
-
-	for !s.client.IsConnected() {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-timer.C:
-			reconnectAttempts++
-			if err := s.client.Connect(s.ctx); err != nil {
-				s.log.Error("Failed to reconnect to Wind Farm FCU",
-					zap.Error(err),
-					zap.Int("attempt", reconnectAttempts),
-					zap.Duration("retry_delay", s.config.ReconnectDelay))
-				timer.Reset(s.config.ReconnectDelay)
-			} else {
-				s.log.Info("Successfully reconnected to Wind Farm FCU",
-					zap.Int("total_attempts", reconnectAttempts),
-					zap.Duration("total_downtime", time.Duration(reconnectAttempts)*s.config.ReconnectDelay))
-				return
-			}
-		}
+// defaultPollGroups lists the register blocks of the FCU poll cycle. The
+// measuring and status groups are required; the alarm list is optional
+// because older FCU firmware does not expose the block.
+func defaultPollGroups() []pollgroup.Settings {
+	return []pollgroup.Settings{
+		{Name: "measuring"},
+		{Name: "status"},
+		{Name: "alarms", Optional: true},
 	}
 }
 
-// readAllData reads all data from the FCU
+// newPollGroups builds the poll groups with the configured overrides
+func newPollGroups(cfg config.WindFarmConfig) map[string]*pollgroup.Group {
+	return pollgroup.Build(defaultPollGroups(), cfg.PollGroups)
+}
+
+// readAllData reads the due poll groups from the FCU; each group keeps its
+// own schedule and failure counter, so a failing optional group cannot
+// delay the other reads
 func (s *Service) readAllData() error {
+	now := time.Now()
+	var lastErr error
+
 	// Read measuring data (registers 700-759)
-	if err := s.readMeasuringData(); err != nil {
-		return fmt.Errorf("failed to read measuring data: %w", err)
-	}
+	s.runPollGroup(s.pollGroups["measuring"], now, func() error {
+		if err := s.readMeasuringData(); err != nil {
+			lastErr = fmt.Errorf("failed to read measuring data: %w", err)
+			return lastErr
+		}
+		return nil
+	})
 
 	// Read return values / status data (registers 649-689)
-	if err := s.readReturnValues(); err != nil {
-		return fmt.Errorf("failed to read return values: %w", err)
+	s.runPollGroup(s.pollGroups["status"], now, func() error {
+		if err := s.readReturnValues(); err != nil {
+			lastErr = fmt.Errorf("failed to read return values: %w", err)
+			return lastErr
+		}
+		return nil
+	})
+
+	// Read alarm/event list (registers 800-819)
+	s.runPollGroup(s.pollGroups["alarms"], now, func() error {
+		if err := s.readAlarmList(); err != nil {
+			lastErr = fmt.Errorf("failed to read alarm list: %w", err)
+			return lastErr
+		}
+		return nil
+	})
+
+	return lastErr
+}
+
+// runPollGroup executes one poll group when it is due and tracks its
+// failure state, logging once when an optional group is disabled
+func (s *Service) runPollGroup(group *pollgroup.Group, now time.Time, read func() error) {
+	if !group.Due(now) {
+		return
 	}
 
-	return nil
+	if err := read(); err != nil {
+		if group.RecordFailure(now) {
+			s.log.Error("Poll group disabled after persistent read failures",
+				zap.String("group", group.Name()),
+				zap.Int("failures", group.Failures()))
+		}
+		return
+	}
+	group.RecordSuccess(now)
 }
 
 // readMeasuringData reads measuring data from registers 700-759
@@ -107,12 +155,44 @@ func (s *Service) readMeasuringData() error {
 		return fmt.Errorf("failed to read measuring data registers: %w", err)
 	}
 
+	measuringData, err := parseMeasuringData(data, s.config.ID)
+	if err != nil {
+		return fmt.Errorf("failed to parse measuring data: %w", err)
+	}
+
+	weatherData, err := parseWeatherData(data, s.config.ID)
+	if err != nil {
+		return fmt.Errorf("failed to parse weather data: %w", err)
+	}
+
+	// Apply the weather quality checks and source selection
+	weatherData = s.selectWeatherData(weatherData)
+
+	// Compute the expected production from the configured power curve
+	if s.powerCurve != nil {
+		measuringData.ExpectedPower = s.powerCurve.ExpectedPower(measuringData.WindSpeed)
+		measuringData.PowerDeviation = measuringData.ExpectedPower - measuringData.ActivePowerNCP
+	}
+
+	// Annotate the measuring data with the icing detection result
+	measuringData.IcingSuspected = s.updateIcingDetection(measuringData, weatherData)
+
+	fcuMode, err := parseFCUMode(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse FCU mode: %w", err)
+	}
+
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	s.lastMeasuringData = measuringData
+	s.lastWeatherData = weatherData
+	s.lastStatusData.FCUMode = fcuMode
+	s.mutex.Unlock()
 
-	s.lastMeasuringData = parseMeasuringData(data, s.config.ID)
-	s.lastWeatherData = parseWeatherData(data, s.config.ID)
-	s.lastStatusData.FCUMode = parseFCUMode(data)
+	// Accumulate the sample into the daily KPIs and trend statistics
+	s.recordKPISample(measuringData)
+	s.trends.Observe("active_power_ncp", measuringData.ActivePowerNCP)
+	s.trends.Observe("frequency_ncp", measuringData.FrequencyNCP)
+	s.trends.Observe("wind_speed", measuringData.WindSpeed)
 
 	return nil
 }
@@ -124,16 +204,52 @@ func (s *Service) readReturnValues() error {
 		return fmt.Errorf("failed to read return values registers: %w", err)
 	}
 
+	statusData, err := parseStatusData(data, s.config.ID)
+	if err != nil {
+		return fmt.Errorf("failed to parse status data: %w", err)
+	}
+
+	setpointData, err := parseSetpointData(data, s.config.ID)
+	if err != nil {
+		return fmt.Errorf("failed to parse setpoint data: %w", err)
+	}
+
 	s.mutex.Lock()
-	defer s.mutex.Unlock()
 
 	// Parse status data (preserving FCUMode from measuring data)
-	fcuMode := s.lastStatusData.FCUMode
-	s.lastStatusData = parseStatusData(data, s.config.ID)
-	s.lastStatusData.FCUMode = fcuMode
+	statusData.FCUMode = s.lastStatusData.FCUMode
+	s.lastStatusData = statusData
 
 	// Parse setpoint data
-	s.lastSetpointData = parseSetpointData(data, s.config.ID)
+	s.lastSetpointData = setpointData
+
+	pending := s.reconcilePending
+	s.reconcilePending = false
+	commanded := s.commandState
+	s.mutex.Unlock()
+
+	// Compare the fresh mirrors against the last commanded setpoints after
+	// a reconnect or restart
+	if pending {
+		s.reconcileCommandState(commanded, setpointData)
+	}
+
+	return nil
+}
+
+// readAlarmList reads the FCU alarm/event list from registers 800-819
+func (s *Service) readAlarmList() error {
+	data, err := s.client.ReadHoldingRegisters(s.ctx, AlarmListStartAddr, AlarmListLength)
+	if err != nil {
+		return fmt.Errorf("failed to read alarm list registers: %w", err)
+	}
+
+	entries, err := parseAlarmList(data, s.config.ID)
+	if err != nil {
+		return fmt.Errorf("failed to parse alarm list: %w", err)
+	}
+
+	s.processAlarmList(entries)
 
 	return nil
 }
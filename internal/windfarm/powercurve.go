@@ -0,0 +1,61 @@
+package windfarm
+
+import (
+	"sort"
+
+	"powerkonnekt/ems/internal/config"
+)
+
+// powerCurve interpolates the expected wind farm power from the configured
+// wind speed support points
+type powerCurve struct {
+	points []config.PowerCurvePoint
+}
+
+// newPowerCurve builds the power curve from the configuration; fewer than
+// two points disable the expected-production calculation
+func newPowerCurve(points []config.PowerCurvePoint) *powerCurve {
+	if len(points) < 2 {
+		return nil
+	}
+
+	sorted := make([]config.PowerCurvePoint, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].WindSpeed < sorted[j].WindSpeed
+	})
+
+	return &powerCurve{points: sorted}
+}
+
+// ExpectedPower returns the expected power at the given wind speed by linear
+// interpolation between the support points; wind speeds outside the curve
+// are clamped to the first and last point
+func (c *powerCurve) ExpectedPower(windSpeed float32) float32 {
+	first := c.points[0]
+	if windSpeed <= first.WindSpeed {
+		return first.Power
+	}
+
+	last := c.points[len(c.points)-1]
+	if windSpeed >= last.WindSpeed {
+		return last.Power
+	}
+
+	for i := 1; i < len(c.points); i++ {
+		if windSpeed > c.points[i].WindSpeed {
+			continue
+		}
+
+		lower, upper := c.points[i-1], c.points[i]
+		span := upper.WindSpeed - lower.WindSpeed
+		if span <= 0 {
+			return upper.Power
+		}
+
+		fraction := (windSpeed - lower.WindSpeed) / span
+		return lower.Power + fraction*(upper.Power-lower.Power)
+	}
+
+	return last.Power
+}
@@ -29,6 +29,14 @@ const (
 	MeasuringDataLength    = 60
 )
 
+const (
+	// Alarm/event list: pairs of alarm code and status word per entry,
+	// code 0 marks an empty slot
+	AlarmListStartAddr = 800
+	AlarmListEntries   = 10
+	AlarmListLength    = AlarmListEntries * 2
+)
+
 // Wind Farm Control Commands
 const (
 	WindFarmStart = 0 // Start wind farm
@@ -46,3 +54,9 @@ const (
 	FCUOffline = 0 // FCU offline
 	FCUOnline  = 1 // FCU online
 )
+
+// FCU Alarm Status
+const (
+	FCUAlarmCleared = 0 // Alarm entry cleared
+	FCUAlarmActive  = 1 // Alarm entry active
+)
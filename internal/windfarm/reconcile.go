@@ -0,0 +1,173 @@
+package windfarm
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// ReconcileAlarmCode identifies a setpoint mirror mismatch that could not
+// be re-asserted after an FCU reconnect
+const ReconcileAlarmCode = 9600
+
+// Reconciliation tolerances per setpoint kind
+const (
+	reconcileSetpointTolerance    = 0.1   // % for P and Q setpoints
+	reconcilePowerFactorTolerance = 0.005 // Power factor
+)
+
+// ReconcileMismatch describes one setpoint whose FCU mirror disagreed with
+// the last commanded value
+type ReconcileMismatch struct {
+	Field      string  `json:"field"`
+	Commanded  float32 `json:"commanded"`
+	Mirror     float32 `json:"mirror"`
+	Reasserted bool    `json:"reasserted"`
+}
+
+// ReconcileResult reports the outcome of the setpoint reconciliation after
+// an FCU reconnect or a restart
+type ReconcileResult struct {
+	Timestamp  time.Time           `json:"timestamp"`
+	Mismatches []ReconcileMismatch `json:"mismatches"`
+}
+
+// restoreCommandState loads the persisted command state so the EMS does
+// not forget the last commanded setpoints across a restart; the mirrors
+// are reconciled on the first status read
+func (s *Service) restoreCommandState() {
+	if s.postgreSQL == nil {
+		return
+	}
+
+	record, err := s.postgreSQL.GetWindFarmCommandState(s.config.ID)
+	if err != nil {
+		s.log.Error("Failed to restore wind farm command state", zap.Error(err))
+		return
+	}
+	if record == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	s.commandState = database.WindFarmCommandState{
+		LastUpdated:         record.UpdatedAt,
+		PSetpoint:           record.PSetpoint,
+		QSetpoint:           record.QSetpoint,
+		PowerFactorSetpoint: record.PowerFactorSetpoint,
+		WindFarmStartStop:   record.WindFarmStartStop,
+		RapidDownwardSignal: record.RapidDownwardSignal,
+	}
+	s.reconcilePending = true
+	s.mutex.Unlock()
+
+	s.log.Info("Wind farm command state restored",
+		zap.Time("last_updated", record.UpdatedAt))
+}
+
+// persistCommandState saves the current command state so a restart or FCU
+// reconnect can reconcile against it. Persistence failures are logged but
+// never block the command path.
+func (s *Service) persistCommandState() {
+	if s.postgreSQL == nil {
+		return
+	}
+
+	s.mutex.RLock()
+	record := database.WindFarmCommandStateRecord{
+		ID:                  uint(s.config.ID),
+		PSetpoint:           s.commandState.PSetpoint,
+		QSetpoint:           s.commandState.QSetpoint,
+		PowerFactorSetpoint: s.commandState.PowerFactorSetpoint,
+		WindFarmStartStop:   s.commandState.WindFarmStartStop,
+		RapidDownwardSignal: s.commandState.RapidDownwardSignal,
+	}
+	s.mutex.RUnlock()
+
+	if err := s.postgreSQL.SaveWindFarmCommandState(record); err != nil {
+		s.log.Error("Failed to persist wind farm command state", zap.Error(err))
+	}
+}
+
+// GetReconcileResult returns the outcome of the last setpoint
+// reconciliation
+func (s *Service) GetReconcileResult() ReconcileResult {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.lastReconcile
+}
+
+// reconcileCommandState compares the last commanded setpoints against the
+// FCU mirrors, re-asserts disagreeing setpoints and raises an alarm when a
+// re-assert fails
+func (s *Service) reconcileCommandState(commanded database.WindFarmCommandState, mirrors database.WindFarmSetpointData) {
+	// Nothing was ever commanded, so there is nothing to reconcile against
+	if commanded.LastUpdated.IsZero() {
+		return
+	}
+
+	result := ReconcileResult{Timestamp: time.Now()}
+	failed := false
+
+	check := func(field string, commandedValue, mirror, tolerance float32, reassert func() error) {
+		delta := commandedValue - mirror
+		if delta < 0 {
+			delta = -delta
+		}
+		if delta <= tolerance {
+			return
+		}
+
+		mismatch := ReconcileMismatch{
+			Field:     field,
+			Commanded: commandedValue,
+			Mirror:    mirror,
+		}
+
+		s.log.Warn("Setpoint mirror disagrees after reconnect, re-asserting",
+			zap.String("field", field),
+			zap.Float32("commanded", commandedValue),
+			zap.Float32("mirror", mirror))
+
+		if err := reassert(); err != nil {
+			s.log.Error("Failed to re-assert setpoint after reconnect",
+				zap.String("field", field),
+				zap.Error(err))
+			failed = true
+		} else {
+			mismatch.Reasserted = true
+		}
+
+		result.Mismatches = append(result.Mismatches, mismatch)
+	}
+
+	check("p_setpoint", commanded.PSetpoint, mirrors.PSetpointMirror,
+		reconcileSetpointTolerance, func() error { return s.SetPowerSetpoint(commanded.PSetpoint) })
+	check("q_setpoint", commanded.QSetpoint, mirrors.QSetpointMirror,
+		reconcileSetpointTolerance, func() error { return s.SetReactivePowerSetpoint(commanded.QSetpoint) })
+	check("power_factor_setpoint", commanded.PowerFactorSetpoint, mirrors.PowerFactorMirror,
+		reconcilePowerFactorTolerance, func() error { return s.SetPowerFactorSetpoint(commanded.PowerFactorSetpoint) })
+
+	s.mutex.Lock()
+	s.lastReconcile = result
+	s.mutex.Unlock()
+
+	if len(result.Mismatches) == 0 {
+		s.log.Info("Setpoint mirrors reconciled, no mismatch")
+		return
+	}
+
+	if failed {
+		s.alarmManager.SubmitAlarm(database.BMSAlarmData{
+			Timestamp: time.Now(),
+			AlarmType: fmt.Sprintf("WINDFARM_%d", s.config.ID),
+			AlarmCode: ReconcileAlarmCode,
+			Message:   "Setpoint mirror mismatch after FCU reconnect could not be re-asserted",
+			Severity:  "HIGH",
+			Active:    true,
+		})
+	}
+}
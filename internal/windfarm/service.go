@@ -2,27 +2,49 @@ package windfarm
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
+	"powerkonnekt/ems/internal/alarm"
 	"powerkonnekt/ems/internal/config"
 	"powerkonnekt/ems/internal/database"
+	"powerkonnekt/ems/internal/pollgroup"
+	"powerkonnekt/ems/internal/telemetry"
+	"powerkonnekt/ems/internal/weather"
+	"powerkonnekt/ems/pkg/loopwatch"
 	"powerkonnekt/ems/pkg/modbus"
+	"powerkonnekt/ems/pkg/rolling"
 )
 
+// trendSampleCapacity bounds the samples kept per trend field; it covers the
+// longest rolling window at the 1 s poll rate
+const trendSampleCapacity = 1024
+
 // Service represents the Wind Farm (FCU) service
 type Service struct {
-	config   config.WindFarmConfig
-	influxDB *database.InfluxDB
-	client   *modbus.Client
-	ctx      context.Context
-	cancel   context.CancelFunc
-	wg       sync.WaitGroup
-	log      *zap.Logger
+	config          config.WindFarmConfig
+	influxDB        *database.InfluxDB
+	postgreSQL      *database.PostgreSQL
+	alarmManager    *alarm.Manager
+	telemetry       *telemetry.Manager
+	weatherProvider *weather.Provider
+	powerCurve      *powerCurve
+	client          *modbus.Client
+
+	pollGroups map[string]*pollgroup.Group
+	pollWatch  *loopwatch.Loop
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	log        *zap.Logger
 
 	dataUpdateChan chan struct{}
 
+	trends *rolling.Tracker // Rolling statistics of the key NCP fields
+
 	mutex             sync.RWMutex
 	lastMeasuringData database.WindFarmMeasuringData
 	lastStatusData    database.WindFarmStatusData
@@ -30,11 +52,43 @@ type Service struct {
 	lastWeatherData   database.WindFarmWeatherData
 	commandState      database.WindFarmCommandState
 	heartbeatCounter  uint16
+	reconcilePending  bool // Compare mirrors on the next status read
+	lastReconcile     ReconcileResult
+
+	previousAlarmStates map[uint16]bool
+
+	// Icing detection
+	icingConditionSince time.Time
+	icingSuspected      bool
+
+	// Weather data quality tracking
+	weatherSource     string
+	weatherLastRaw    database.WindFarmWeatherData
+	weatherLastChange time.Time
+
+	// Daily KPI accumulation
+	kpiDay              time.Time
+	kpiSamples          uint64
+	kpiAvailabilitySum  float64
+	kpiCommunicationSum float64
+	kpiActualPowerSum   float64
+	kpiPossiblePowerSum float64
+	lastKPIReport       database.WindFarmKPIData
 }
 
 // NewService creates a new Wind Farm service
-func NewService(cfg config.WindFarmConfig, influxDB *database.InfluxDB, logger *zap.Logger) *Service {
-	client := modbus.NewClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout)
+func NewService(cfg config.WindFarmConfig, influxDB *database.InfluxDB, postgreSQL *database.PostgreSQL, alarmManager *alarm.Manager, telemetryManager *telemetry.Manager, weatherProvider *weather.Provider, logger *zap.Logger) *Service {
+	var client *modbus.Client
+	if cfg.Simulate {
+		client = modbus.NewSimulatedClient(cfg.SlaveID, cfg.Timeout)
+	} else {
+		client = modbus.NewSecureClient(cfg.Host, cfg.Port, cfg.SlaveID, cfg.Timeout, cfg.BindAddress, modbus.TLSSettings{
+			Enabled:  cfg.TLS.Enabled,
+			CertFile: cfg.TLS.CertFile,
+			KeyFile:  cfg.TLS.KeyFile,
+			CAFile:   cfg.TLS.CAFile,
+		})
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Create service-specific logger
@@ -46,18 +100,34 @@ func NewService(cfg config.WindFarmConfig, influxDB *database.InfluxDB, logger *
 	)
 
 	return &Service{
-		config:         cfg,
-		influxDB:       influxDB,
-		client:         client,
-		ctx:            ctx,
-		cancel:         cancel,
-		log:            serviceLogger,
-		dataUpdateChan: make(chan struct{}, 1),
+		config:              cfg,
+		influxDB:            influxDB,
+		postgreSQL:          postgreSQL,
+		alarmManager:        alarmManager,
+		telemetry:           telemetryManager,
+		weatherProvider:     weatherProvider,
+		powerCurve:          newPowerCurve(cfg.PowerCurve),
+		pollGroups:          newPollGroups(cfg),
+		pollWatch:           loopwatch.NewLoop(fmt.Sprintf("windfarm_%d_poll", cfg.ID), cfg.PollInterval),
+		client:              client,
+		ctx:                 ctx,
+		cancel:              cancel,
+		log:                 serviceLogger,
+		dataUpdateChan:      make(chan struct{}, 1),
+		previousAlarmStates: make(map[uint16]bool),
+		weatherSource:       WeatherSourceFCU,
+		trends: rolling.NewTracker(fmt.Sprintf("windfarm_%d_trend", cfg.ID), trendSampleCapacity,
+			"active_power_ncp", "frequency_ncp", "wind_speed"),
 	}
 }
 
 // Start starts the Wind Farm service
 func (s *Service) Start() error {
+	s.restoreCommandState()
+
+	if s.config.Simulate {
+		s.wg.Go(s.simulateLoop)
+	}
 	s.wg.Go(s.dataPollLoop)
 	s.wg.Go(s.heartbeatLoop)
 	s.wg.Go(s.persistenceLoop)
@@ -75,6 +145,12 @@ func (s *Service) Stop() {
 	s.log.Info("Wind Farm service stopped")
 }
 
+// GetTrendStats returns the rolling min/max/average statistics of the key
+// NCP fields per reporting window
+func (s *Service) GetTrendStats() map[string]map[string]rolling.Stats {
+	return s.trends.Stats()
+}
+
 // GetLatestData returns the latest aggregated wind farm data
 func (s *Service) GetLatestData() database.WindFarmData {
 	s.mutex.RLock()
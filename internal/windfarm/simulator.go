@@ -0,0 +1,179 @@
+package windfarm
+
+import (
+	"math"
+	"time"
+)
+
+// Characteristics of the simulated wind farm
+const (
+	simRatedPowerMW       = 30.0 // Fallback rating when no power curve is configured
+	simRatedWindSpeed     = 12.0 // Wind speed reaching the fallback rating (m/s)
+	simMeanWindSpeed      = 8.0
+	simNCPVoltageKV       = 34.5
+	simRampDownRateMWPerS = 2.0 // Slew rate of the rapid downward signal
+	simStepInterval       = time.Second
+	simWindPeriod         = 20 * time.Minute // Period of the slow wind speed swell
+)
+
+// windFarmSimulator holds the state of the built-in wind farm model: the
+// wind speed follows a slow swell with gusts on top, the possible power
+// comes from the power curve and the produced power honours the active
+// power setpoint, start/stop and rapid downward commands
+type windFarmSimulator struct {
+	powerMW   float64
+	elapsed   time.Duration
+	heartbeat uint16
+}
+
+// simulateLoop drives the built-in wind farm simulator against the
+// in-memory register bank of the simulated MODBUS client, so the regular
+// poll groups, setpoint mirrors and FCU supervision run unchanged without
+// the farm control unit
+func (s *Service) simulateLoop() {
+	s.log.Info("Wind farm simulator active, serving the in-memory device model")
+
+	sim := &windFarmSimulator{}
+	sim.step(s, 0)
+
+	ticker := time.NewTicker(simStepInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			sim.step(s, now.Sub(last))
+			last = now
+		}
+	}
+}
+
+// step advances the wind farm model by dt and publishes the register image
+func (sim *windFarmSimulator) step(s *Service, dt time.Duration) {
+	sim.elapsed += dt
+	sim.heartbeat++
+	client := s.client
+
+	seconds := sim.elapsed.Seconds()
+	windSpeed := simMeanWindSpeed +
+		3*math.Sin(2*math.Pi*seconds/simWindPeriod.Seconds()) +
+		0.5*math.Sin(2*math.Pi*seconds/97)
+
+	possibleMW := sim.possiblePower(s, windSpeed)
+
+	// The produced power follows the possible power, bounded by the active
+	// power setpoint; stop and rapid downward commands override both
+	targetMW := possibleMW
+	if setpoint := float64(int16(client.SimRegister(PSetpointAddr))) / 100; setpoint > 0 && setpoint < targetMW {
+		targetMW = setpoint
+	}
+	if client.SimRegister(WindFarmStartStopAddr) == WindFarmStop {
+		targetMW = 0
+	}
+
+	if client.SimRegister(RapidDownwardSignalAddr) == RapidDownwardOn {
+		sim.powerMW = math.Max(0, sim.powerMW-simRampDownRateMWPerS*dt.Seconds())
+	} else {
+		sim.powerMW = targetMW
+	}
+
+	sim.writeStatusMirrors(s)
+	sim.writeMeasuringData(s, windSpeed, possibleMW)
+}
+
+// possiblePower returns the producible power at a wind speed, preferring the
+// configured power curve over the built-in cubic fallback
+func (sim *windFarmSimulator) possiblePower(s *Service, windSpeed float64) float64 {
+	if s.powerCurve != nil {
+		return float64(s.powerCurve.ExpectedPower(float32(windSpeed)))
+	}
+
+	fraction := math.Min(1, math.Pow(windSpeed/simRatedWindSpeed, 3))
+	return simRatedPowerMW * fraction
+}
+
+// writeStatusMirrors publishes the FCU status block: heartbeat, online flag
+// and the mirrors of the commanded setpoints and control signals
+func (sim *windFarmSimulator) writeStatusMirrors(s *Service) {
+	client := s.client
+
+	client.SimSetRegisters(ReturnValuesStartAddr, sim.heartbeat, FCUOnline)
+
+	// Setpoint mirrors and currently used setpoints echo the command registers
+	pSetpoint := client.SimRegister(PSetpointAddr)
+	qSetpoint := client.SimRegister(QSetpointAddr)
+	powerFactor := client.SimRegister(PowerFactorSetpointAddr)
+	client.SimSetRegisters(660, pSetpoint, qSetpoint, powerFactor)
+	client.SimSetRegisters(674, pSetpoint, qSetpoint, powerFactor)
+
+	// Active and reactive power control modes in use
+	client.SimSetRegisters(672, 1, 1)
+
+	// Start/stop and rapid downward signal mirrors
+	client.SimSetRegister(680, client.SimRegister(WindFarmStartStopAddr))
+	client.SimSetRegister(690, client.SimRegister(RapidDownwardSignalAddr))
+}
+
+// writeMeasuringData publishes the NCP measurement and weather block
+func (sim *windFarmSimulator) writeMeasuringData(s *Service, windSpeed, possibleMW float64) {
+	client := s.client
+
+	current := sim.powerMW * 1000 / (math.Sqrt(3) * simNCPVoltageKV)
+	frequency := 50 + 0.02*math.Sin(2*math.Pi*sim.elapsed.Seconds()/37)
+	rated := sim.possiblePower(s, simRatedWindSpeed)
+
+	relativeAvailability := 100.0
+	if rated > 0 {
+		relativeAvailability = math.Min(100, possibleMW/rated*100)
+	}
+
+	minReactivePct := int16(-3000)
+	minReactiveMVar := int16(-1000)
+
+	client.SimSetRegisters(MeasuringDataStartAddr,
+		uint16(int16(math.Round(sim.powerMW*100))), // 700 - Active power (MW)
+		0, // 701 - Reactive power
+		uint16(math.Round(simNCPVoltageKV*100)), // 702 - Voltage (kV)
+		uint16(math.Round(current*10)),          // 703 - Current (A)
+		1000,                                    // 704 - Power factor
+		100,                                     // 705 - WEC availability (%)
+		uint16(math.Round(frequency*100)))       // 706 - Frequency (Hz)
+
+	client.SimSetRegisters(720,
+		uint16(math.Round(windSpeed*100)), // Wind speed (m/s)
+		225,                               // Wind direction (°)
+		uint16(int16(math.Round(possibleMW*100)))) // Possible WEC power (MW)
+	client.SimSetRegister(724, 100) // WEC communication (%)
+
+	client.SimSetRegisters(740,
+		uint16(int16(math.Round(relativeAvailability*100))),
+		uint16(int16(math.Round(possibleMW*100))),
+		uint16(minReactivePct),  // Relative min reactive power (%)
+		uint16(minReactiveMVar), // Absolute min reactive power (MVar)
+		3000,                    // Relative max reactive power (%)
+		1000)                    // Absolute max reactive power (MVar)
+
+	// Weather station block
+	sim.writeWeatherData(s, windSpeed)
+
+	client.SimSetRegister(759, 1) // FCU mode
+}
+
+// writeWeatherData publishes the meteorological sensors of the FCU; the
+// measurement counter keeps increasing so the data quality tracking sees a
+// live station
+func (sim *windFarmSimulator) writeWeatherData(s *Service, windSpeed float64) {
+	s.client.SimSetRegisters(730,
+		uint16(math.Round(windSpeed*10)), // Wind speed meteo (m/s)
+		2250,                             // Wind direction meteo (°)
+		180,                              // Outside temperature (°C)
+		1013,                             // Atmospheric pressure (mbar)
+		600,                              // Air humidity (%)
+		0,                                // Rainfall
+		2000,                             // Solar radiation (W/m²)
+		100,                              // Wind farm communication (%)
+		sim.heartbeat)                    // Weather measurements count
+}
@@ -0,0 +1,92 @@
+package windfarm
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"powerkonnekt/ems/internal/database"
+)
+
+// Weather data sources recorded in the persisted weather data
+const (
+	WeatherSourceFCU       = "fcu"
+	WeatherSourceSecondary = "secondary"
+)
+
+// weatherStaleTimeout is how long the FCU weather registers may stay
+// unchanged before they are considered frozen
+const weatherStaleTimeout = 30 * time.Minute
+
+// weatherPlausible checks the FCU weather values against physical ranges
+func weatherPlausible(data database.WindFarmWeatherData) bool {
+	switch {
+	case data.WindSpeedMeteo < 0 || data.WindSpeedMeteo > 60:
+	case data.WindDirectionMeteo < 0 || data.WindDirectionMeteo > 360:
+	case data.OutsideTemperature < -40 || data.OutsideTemperature > 60:
+	case data.AtmosphericPressure != 0 && (data.AtmosphericPressure < 800 || data.AtmosphericPressure > 1100):
+	case data.AirHumidity < 0 || data.AirHumidity > 100:
+	default:
+		return true
+	}
+	return false
+}
+
+// weatherUnchanged reports whether two observations carry identical register
+// values, ignoring the local timestamp and attribution
+func weatherUnchanged(a, b database.WindFarmWeatherData) bool {
+	a.Timestamp, b.Timestamp = time.Time{}, time.Time{}
+	a.Source, b.Source = "", ""
+	return a == b
+}
+
+// selectWeatherData applies the quality checks to the FCU weather data and
+// switches to the secondary source while the FCU data is frozen or
+// implausible and a fresh secondary observation is available. The selected
+// source is recorded in the returned data for persistence.
+func (s *Service) selectWeatherData(fcuData database.WindFarmWeatherData) database.WindFarmWeatherData {
+	now := fcuData.Timestamp
+
+	s.mutex.Lock()
+	if s.weatherLastChange.IsZero() || !weatherUnchanged(fcuData, s.weatherLastRaw) {
+		s.weatherLastRaw = fcuData
+		s.weatherLastChange = now
+	}
+	stale := now.Sub(s.weatherLastChange) >= weatherStaleTimeout
+	s.mutex.Unlock()
+
+	suspect := stale || !weatherPlausible(fcuData)
+
+	source := WeatherSourceFCU
+	selected := fcuData
+
+	if suspect {
+		if observation, ok := s.weatherProvider.GetCurrent(); ok {
+			selected = database.WindFarmWeatherData{
+				Timestamp:           fcuData.Timestamp,
+				ID:                  s.config.ID,
+				WindSpeedMeteo:      observation.WindSpeed,
+				WindDirectionMeteo:  observation.WindDirection,
+				OutsideTemperature:  observation.Temperature,
+				AtmosphericPressure: observation.AtmosphericPressure,
+				AirHumidity:         observation.Humidity,
+			}
+			source = WeatherSourceSecondary
+		}
+	}
+	selected.Source = source
+
+	s.mutex.Lock()
+	changed := s.weatherSource != source
+	s.weatherSource = source
+	s.mutex.Unlock()
+
+	if changed {
+		s.log.Warn("Weather data source switched",
+			zap.String("source", source),
+			zap.Bool("stale", stale),
+			zap.Bool("plausible", weatherPlausible(fcuData)))
+	}
+
+	return selected
+}
@@ -0,0 +1,237 @@
+// Package cache provides bounded, instrumented in-memory stores: a
+// capacity-limited map with least-recently-used eviction and a fixed-size
+// ring buffer. Every store registers itself under a name so its statistics
+// can be collected and exported for sizing edge hardware.
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Stats is a snapshot of the counters of one store
+type Stats struct {
+	Name      string  `json:"name"`
+	Size      int     `json:"size"`
+	Capacity  int     `json:"capacity"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRate   float64 `json:"hit_rate"`
+}
+
+// instrumented is implemented by every registered store
+type instrumented interface {
+	Stats() Stats
+}
+
+var (
+	registryMutex sync.Mutex
+	registry      []instrumented
+)
+
+// register adds a store to the global registry
+func register(store instrumented) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry = append(registry, store)
+}
+
+// CollectStats returns the statistics of every registered store
+func CollectStats() []Stats {
+	registryMutex.Lock()
+	stores := make([]instrumented, len(registry))
+	copy(stores, registry)
+	registryMutex.Unlock()
+
+	stats := make([]Stats, len(stores))
+	for i, store := range stores {
+		stats[i] = store.Stats()
+	}
+	return stats
+}
+
+// Store is a bounded map; when the capacity is exceeded the least recently
+// used entry is evicted. All methods are safe for concurrent use.
+type Store[K comparable, V any] struct {
+	name     string
+	capacity int
+
+	mutex     sync.Mutex
+	entries   map[K]*list.Element
+	order     *list.List // Front is the most recently used
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// storeEntry is one entry of a Store
+type storeEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewStore creates and registers a bounded map store
+func NewStore[K comparable, V any](name string, capacity int) *Store[K, V] {
+	store := &Store[K, V]{
+		name:     name,
+		capacity: capacity,
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+	}
+	register(store)
+	return store
+}
+
+// Get returns the value of a key and marks it recently used
+func (s *Store[K, V]) Get(key K) (V, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, exists := s.entries[key]
+	if !exists {
+		s.misses++
+		var zero V
+		return zero, false
+	}
+
+	s.hits++
+	s.order.MoveToFront(element)
+	return element.Value.(*storeEntry[K, V]).value, true
+}
+
+// Put inserts or updates a key, evicting the least recently used entry when
+// the store is over capacity
+func (s *Store[K, V]) Put(key K, value V) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, exists := s.entries[key]; exists {
+		element.Value.(*storeEntry[K, V]).value = value
+		s.order.MoveToFront(element)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(&storeEntry[K, V]{key: key, value: value})
+
+	for s.capacity > 0 && s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*storeEntry[K, V]).key)
+		s.evictions++
+	}
+}
+
+// Delete removes a key
+func (s *Store[K, V]) Delete(key K) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, exists := s.entries[key]; exists {
+		s.order.Remove(element)
+		delete(s.entries, key)
+	}
+}
+
+// Len returns the number of entries
+func (s *Store[K, V]) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.order.Len()
+}
+
+// ForEach calls fn for every entry without touching the usage order or the
+// hit counters; iteration stops when fn returns false
+func (s *Store[K, V]) ForEach(fn func(K, V) bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for element := s.order.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*storeEntry[K, V])
+		if !fn(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+// Stats returns a snapshot of the store counters
+func (s *Store[K, V]) Stats() Stats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := Stats{
+		Name:      s.name,
+		Size:      s.order.Len(),
+		Capacity:  s.capacity,
+		Hits:      s.hits,
+		Misses:    s.misses,
+		Evictions: s.evictions,
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// Ring is a bounded ring buffer; appending beyond the capacity overwrites
+// the oldest entry. All methods are safe for concurrent use.
+type Ring[T any] struct {
+	name string
+
+	mutex     sync.Mutex
+	values    []T
+	start     int
+	size      int
+	evictions uint64
+}
+
+// NewRing creates and registers a bounded ring buffer
+func NewRing[T any](name string, capacity int) *Ring[T] {
+	ring := &Ring[T]{
+		name:   name,
+		values: make([]T, capacity),
+	}
+	register(ring)
+	return ring
+}
+
+// Append adds a value, overwriting the oldest one when the ring is full
+func (r *Ring[T]) Append(value T) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.size < len(r.values) {
+		r.values[(r.start+r.size)%len(r.values)] = value
+		r.size++
+		return
+	}
+
+	r.values[r.start] = value
+	r.start = (r.start + 1) % len(r.values)
+	r.evictions++
+}
+
+// Snapshot returns the buffered values, oldest first
+func (r *Ring[T]) Snapshot() []T {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	values := make([]T, r.size)
+	for i := range values {
+		values[i] = r.values[(r.start+i)%len(r.values)]
+	}
+	return values
+}
+
+// Stats returns a snapshot of the ring counters
+func (r *Ring[T]) Stats() Stats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return Stats{
+		Name:      r.name,
+		Size:      r.size,
+		Capacity:  len(r.values),
+		Evictions: r.evictions,
+	}
+}
@@ -0,0 +1,120 @@
+// Package loopwatch instruments periodic loops. Every loop registers
+// itself under a name and records each iteration, so a stalled loop — a
+// ticker goroutine that died or hangs — is detected from its missing
+// heartbeat within seconds instead of being discovered from stale data.
+package loopwatch
+
+import (
+	"sync"
+	"time"
+)
+
+// stalledSlack is added on top of three missed intervals before a loop is
+// reported stalled, so one slow iteration does not flap the health state
+const stalledSlack = time.Second
+
+// Stats is a snapshot of the counters of one loop
+type Stats struct {
+	Name         string        `json:"name"`
+	Interval     time.Duration `json:"interval"`
+	LastRun      time.Time     `json:"last_run"`
+	LastDuration time.Duration `json:"last_duration"`
+	Runs         uint64        `json:"runs"`
+	Overruns     uint64        `json:"overruns"`
+	Stalled      bool          `json:"stalled"`
+}
+
+var (
+	registryMutex sync.Mutex
+	registry      []*Loop
+)
+
+// Loop tracks the execution of one periodic loop. All methods are safe for
+// concurrent use.
+type Loop struct {
+	name     string
+	interval time.Duration
+
+	mutex        sync.Mutex
+	lastRun      time.Time
+	lastDuration time.Duration
+	runs         uint64
+	overruns     uint64
+}
+
+// NewLoop creates and registers a loop watch. The interval is the expected
+// time between iterations; an iteration exceeding it counts as overrun and
+// three missed intervals mark the loop stalled.
+func NewLoop(name string, interval time.Duration) *Loop {
+	loop := &Loop{
+		name:     name,
+		interval: interval,
+		lastRun:  time.Now(),
+	}
+
+	registryMutex.Lock()
+	registry = append(registry, loop)
+	registryMutex.Unlock()
+
+	return loop
+}
+
+// Begin marks the start of one iteration; the returned function records
+// its completion and duration
+func (l *Loop) Begin() func() {
+	start := time.Now()
+	return func() {
+		duration := time.Since(start)
+
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		l.lastRun = time.Now()
+		l.lastDuration = duration
+		l.runs++
+		if duration > l.interval {
+			l.overruns++
+		}
+	}
+}
+
+// Stats returns a snapshot of the loop counters
+func (l *Loop) Stats() Stats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return Stats{
+		Name:         l.name,
+		Interval:     l.interval,
+		LastRun:      l.lastRun,
+		LastDuration: l.lastDuration,
+		Runs:         l.runs,
+		Overruns:     l.overruns,
+		Stalled:      time.Since(l.lastRun) > 3*l.interval+stalledSlack,
+	}
+}
+
+// CollectStats returns the statistics of every registered loop
+func CollectStats() []Stats {
+	registryMutex.Lock()
+	loops := make([]*Loop, len(registry))
+	copy(loops, registry)
+	registryMutex.Unlock()
+
+	stats := make([]Stats, len(loops))
+	for i, loop := range loops {
+		stats[i] = loop.Stats()
+	}
+	return stats
+}
+
+// Stalled returns the names of every registered loop that has missed its
+// stall deadline
+func Stalled() []string {
+	var stalled []string
+	for _, stats := range CollectStats() {
+		if stats.Stalled {
+			stalled = append(stalled, stats.Name)
+		}
+	}
+	return stalled
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"sync"
 	"time"
 
@@ -19,14 +20,57 @@ const (
 type Client struct {
 	client  modbus.Client
 	handler *modbus.TCPClientHandler
+	sim     *simBackend // In-memory register bank, nil for real devices
 
-	mutex       sync.RWMutex
-	isConnected bool
+	mutex          sync.RWMutex
+	isConnected    bool
+	tlsErr         error // Certificate loading failure, reported on Connect
+	stateCallbacks []func(connected bool)
+	lostSignal     chan struct{} // Wakes the reconnect loop on a connection loss
+
+	exceptionMutex sync.Mutex
+	exceptions     map[exceptionKey]*ExceptionStat
+	exceptionTimes []time.Time // Rolling window for the exception rate
 }
 
 // NewClient creates a new MODBUS TCP client
 func NewClient(host string, port int, slaveID byte, timeout time.Duration) *Client {
-	handler := modbus.NewTCPClientHandler(fmt.Sprintf("%s:%d", host, port))
+	return NewBoundClient(host, port, slaveID, timeout, "")
+}
+
+// NewBoundClient creates a new MODBUS TCP client whose outgoing connections
+// are bound to the given local interface address. An empty local address
+// leaves the routing decision to the operating system.
+func NewBoundClient(host string, port int, slaveID byte, timeout time.Duration, localAddress string) *Client {
+	return NewSecureClient(host, port, slaveID, timeout, localAddress, TLSSettings{})
+}
+
+// NewSecureClient creates a new MODBUS TCP client with optional interface
+// binding and Modbus/TCP Security. With TLS enabled the Modbus frames are
+// tunneled through a mutually authenticated TLS session; certificate
+// loading errors surface on Connect so the usual reconnect handling
+// applies.
+func NewSecureClient(host string, port int, slaveID byte, timeout time.Duration, localAddress string, tlsSettings TLSSettings) *Client {
+	var options []modbus.TCPClientHandlerOption
+	if localAddress != "" {
+		dialer := &net.Dialer{
+			Timeout:   timeout,
+			LocalAddr: &net.TCPAddr{IP: net.ParseIP(localAddress)},
+		}
+		options = append(options, modbus.WithDialer(dialer.DialContext))
+	}
+
+	var tlsErr error
+	if tlsSettings.Enabled {
+		tlsConfig, err := buildTLSConfig(tlsSettings, host)
+		if err != nil {
+			tlsErr = err
+		} else {
+			options = append(options, modbus.WithTLSConfig(tlsConfig))
+		}
+	}
+
+	handler := modbus.NewTCPClientHandler(fmt.Sprintf("%s:%d", host, port), options...)
 	handler.SlaveID = slaveID
 	handler.Timeout = timeout
 	handler.ConnectDelay = 100 * time.Millisecond
@@ -34,8 +78,10 @@ func NewClient(host string, port int, slaveID byte, timeout time.Duration) *Clie
 	client := modbus.NewClient(handler)
 
 	return &Client{
-		client:  client,
-		handler: handler,
+		client:     client,
+		handler:    handler,
+		tlsErr:     tlsErr,
+		lostSignal: make(chan struct{}, 1),
 	}
 }
 
@@ -44,12 +90,22 @@ func (c *Client) Connect(ctx context.Context) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	if c.sim != nil {
+		c.setConnected(true)
+		return nil
+	}
+
+	if c.tlsErr != nil {
+		c.setConnected(false)
+		return fmt.Errorf("TLS configuration error: %w", c.tlsErr)
+	}
+
 	err := c.handler.Connect(ctx)
 	if err != nil {
-		c.isConnected = false
+		c.setConnected(false)
 		return err
 	}
-	c.isConnected = true
+	c.setConnected(true)
 	return nil
 }
 
@@ -58,8 +114,13 @@ func (c *Client) Disconnect() error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	if c.sim != nil {
+		c.setConnected(false)
+		return nil
+	}
+
 	err := c.handler.Close()
-	c.isConnected = false
+	c.setConnected(false)
 	return err
 }
 
@@ -93,12 +154,17 @@ func (c *Client) ReadHoldingRegisters(ctx context.Context, address, quantity uin
 		return nil, fmt.Errorf("modbus client not connected")
 	}
 
+	if err := c.injectFault("read_holding_registers"); err != nil {
+		return nil, err
+	}
+
 	data, err := c.client.ReadHoldingRegisters(ctx, address, quantity)
 	if err != nil {
 		c.handleConnectionError(err)
+		c.recordException("read_holding_registers", address, err)
 		return nil, err
 	}
-	return data, nil
+	return transformResponse("read_holding_registers", data), nil
 }
 
 // ReadInputRegisters reads input registers from the MODBUS server
@@ -110,12 +176,17 @@ func (c *Client) ReadInputRegisters(ctx context.Context, address, quantity uint1
 		return nil, fmt.Errorf("modbus client not connected")
 	}
 
+	if err := c.injectFault("read_input_registers"); err != nil {
+		return nil, err
+	}
+
 	data, err := c.client.ReadInputRegisters(ctx, address, quantity)
 	if err != nil {
 		c.handleConnectionError(err)
+		c.recordException("read_input_registers", address, err)
 		return nil, err
 	}
-	return data, nil
+	return transformResponse("read_input_registers", data), nil
 }
 
 // ReadDiscreteInputs reads discrete inputs from the MODBUS server
@@ -127,12 +198,17 @@ func (c *Client) ReadDiscreteInputs(ctx context.Context, address, quantity uint1
 		return nil, fmt.Errorf("modbus client not connected")
 	}
 
+	if err := c.injectFault("read_discrete_inputs"); err != nil {
+		return nil, err
+	}
+
 	data, err := c.client.ReadDiscreteInputs(ctx, address, quantity)
 	if err != nil {
 		c.handleConnectionError(err)
+		c.recordException("read_discrete_inputs", address, err)
 		return nil, err
 	}
-	return data, nil
+	return transformResponse("read_discrete_inputs", data), nil
 }
 
 // ReadCoils reads coils from the MODBUS server
@@ -144,12 +220,17 @@ func (c *Client) ReadCoils(ctx context.Context, address, quantity uint16) ([]byt
 		return nil, fmt.Errorf("modbus client not connected")
 	}
 
+	if err := c.injectFault("read_coils"); err != nil {
+		return nil, err
+	}
+
 	data, err := c.client.ReadCoils(ctx, address, quantity)
 	if err != nil {
 		c.handleConnectionError(err)
+		c.recordException("read_coils", address, err)
 		return nil, err
 	}
-	return data, nil
+	return transformResponse("read_coils", data), nil
 }
 
 // WriteSingleRegister writes a single register to the MODBUS server
@@ -161,9 +242,14 @@ func (c *Client) WriteSingleRegister(ctx context.Context, address, value uint16)
 		return fmt.Errorf("modbus client not connected")
 	}
 
+	if err := c.injectFault("write_single_register"); err != nil {
+		return err
+	}
+
 	_, err := c.client.WriteSingleRegister(ctx, address, value)
 	if err != nil {
 		c.handleConnectionError(err)
+		c.recordException("write_single_register", address, err)
 		return err
 	}
 	return nil
@@ -182,9 +268,14 @@ func (c *Client) WriteMultipleRegisters(ctx context.Context, address uint16, val
 		return fmt.Errorf("values must be even number of bytes, got %d", len(values))
 	}
 
+	if err := c.injectFault("write_multiple_registers"); err != nil {
+		return err
+	}
+
 	_, err := c.client.WriteMultipleRegisters(ctx, address, uint16(len(values)/2), values)
 	if err != nil {
 		c.handleConnectionError(err)
+		c.recordException("write_multiple_registers", address, err)
 		return err
 	}
 	return nil
@@ -199,9 +290,14 @@ func (c *Client) WriteSingleCoil(ctx context.Context, address uint16, value uint
 		return fmt.Errorf("modbus client not connected")
 	}
 
+	if err := c.injectFault("write_single_coil"); err != nil {
+		return err
+	}
+
 	_, err := c.client.WriteSingleCoil(ctx, address, value)
 	if err != nil {
 		c.handleConnectionError(err)
+		c.recordException("write_single_coil", address, err)
 		return err
 	}
 	return nil
@@ -216,9 +312,14 @@ func (c *Client) WriteMultipleCoils(ctx context.Context, address, quantity uint1
 		return fmt.Errorf("modbus client not connected")
 	}
 
+	if err := c.injectFault("write_multiple_coils"); err != nil {
+		return err
+	}
+
 	_, err := c.client.WriteMultipleCoils(ctx, address, quantity, values)
 	if err != nil {
 		c.handleConnectionError(err)
+		c.recordException("write_multiple_coils", address, err)
 		return err
 	}
 	return nil
@@ -253,8 +354,9 @@ func (c *Client) ReadHoldingRegistersWithSlaveID(ctx context.Context, slaveID by
 		data, err := c.client.ReadHoldingRegisters(ctx, address, quantity)
 		if err != nil {
 			if !c.isModbusProtocolError(err) {
-				c.isConnected = false
+				c.setConnected(false)
 			}
+			c.recordException("read_holding_registers", address, err)
 			return err
 		}
 		result = data
@@ -278,8 +380,9 @@ func (c *Client) ReadInputRegistersWithSlaveID(ctx context.Context, slaveID byte
 		data, err := c.client.ReadInputRegisters(ctx, address, quantity)
 		if err != nil {
 			if !c.isModbusProtocolError(err) {
-				c.isConnected = false
+				c.setConnected(false)
 			}
+			c.recordException("read_input_registers", address, err)
 			return err
 		}
 		result = data
@@ -303,8 +406,9 @@ func (c *Client) ReadDiscreteInputsWithSlaveID(ctx context.Context, slaveID byte
 		data, err := c.client.ReadDiscreteInputs(ctx, address, quantity)
 		if err != nil {
 			if !c.isModbusProtocolError(err) {
-				c.isConnected = false
+				c.setConnected(false)
 			}
+			c.recordException("read_discrete_inputs", address, err)
 			return err
 		}
 		result = data
@@ -328,8 +432,9 @@ func (c *Client) ReadCoilsWithSlaveID(ctx context.Context, slaveID byte, address
 		data, err := c.client.ReadCoils(ctx, address, quantity)
 		if err != nil {
 			if !c.isModbusProtocolError(err) {
-				c.isConnected = false
+				c.setConnected(false)
 			}
+			c.recordException("read_coils", address, err)
 			return err
 		}
 		result = data
@@ -351,8 +456,9 @@ func (c *Client) WriteSingleRegisterWithSlaveID(ctx context.Context, slaveID byt
 		_, err := c.client.WriteSingleRegister(ctx, address, value)
 		if err != nil {
 			if !c.isModbusProtocolError(err) {
-				c.isConnected = false
+				c.setConnected(false)
 			}
+			c.recordException("write_single_register", address, err)
 		}
 		return err
 	})
@@ -372,8 +478,9 @@ func (c *Client) WriteMultipleRegistersWithSlaveID(ctx context.Context, slaveID
 		_, err := c.client.WriteMultipleRegisters(ctx, address, uint16(len(values)/2), values)
 		if err != nil {
 			if !c.isModbusProtocolError(err) {
-				c.isConnected = false
+				c.setConnected(false)
 			}
+			c.recordException("write_multiple_registers", address, err)
 		}
 		return err
 	})
@@ -389,8 +496,9 @@ func (c *Client) WriteSingleCoilWithSlaveID(ctx context.Context, slaveID byte, a
 		_, err := c.client.WriteSingleCoil(ctx, address, value)
 		if err != nil {
 			if !c.isModbusProtocolError(err) {
-				c.isConnected = false
+				c.setConnected(false)
 			}
+			c.recordException("write_single_coil", address, err)
 		}
 		return err
 	})
@@ -406,8 +514,9 @@ func (c *Client) WriteMultipleCoilsWithSlaveID(ctx context.Context, slaveID byte
 		_, err := c.client.WriteMultipleCoils(ctx, address, quantity, values)
 		if err != nil {
 			if !c.isModbusProtocolError(err) {
-				c.isConnected = false
+				c.setConnected(false)
 			}
+			c.recordException("write_multiple_coils", address, err)
 		}
 		return err
 	})
@@ -430,5 +539,5 @@ func (c *Client) isModbusProtocolError(err error) bool {
 func (c *Client) markDisconnected() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	c.isConnected = false
+	c.setConnected(false)
 }
@@ -0,0 +1,106 @@
+package modbus
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/grid-x/modbus"
+)
+
+// exceptionRateWindow is the rolling window over which the exception rate
+// is counted
+const exceptionRateWindow = 5 * time.Minute
+
+// ExceptionStat aggregates the exception responses of one
+// function/address/exception-code combination
+type ExceptionStat struct {
+	Function      string    `json:"function"`
+	Address       uint16    `json:"address"`
+	ExceptionCode byte      `json:"exception_code"`
+	Count         uint64    `json:"count"`
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+}
+
+type exceptionKey struct {
+	function string
+	address  uint16
+	code     byte
+}
+
+// recordException tracks a Modbus exception response per function and
+// address; transport errors are not exception responses and are ignored here
+func (c *Client) recordException(function string, address uint16, err error) {
+	var modbusErr *modbus.Error
+	if !errors.As(err, &modbusErr) {
+		return
+	}
+
+	now := time.Now()
+
+	c.exceptionMutex.Lock()
+	defer c.exceptionMutex.Unlock()
+
+	if c.exceptions == nil {
+		c.exceptions = make(map[exceptionKey]*ExceptionStat)
+	}
+
+	key := exceptionKey{function: function, address: address, code: modbusErr.ExceptionCode}
+	stat, exists := c.exceptions[key]
+	if !exists {
+		stat = &ExceptionStat{
+			Function:      function,
+			Address:       address,
+			ExceptionCode: modbusErr.ExceptionCode,
+			FirstSeen:     now,
+		}
+		c.exceptions[key] = stat
+	}
+	stat.Count++
+	stat.LastSeen = now
+
+	c.exceptionTimes = append(c.exceptionTimes, now)
+	c.trimExceptionTimes(now)
+}
+
+// trimExceptionTimes drops rolling-window entries older than the rate
+// window. The caller must hold the exception mutex.
+func (c *Client) trimExceptionTimes(now time.Time) {
+	cutoff := now.Add(-exceptionRateWindow)
+	idx := 0
+	for idx < len(c.exceptionTimes) && c.exceptionTimes[idx].Before(cutoff) {
+		idx++
+	}
+	c.exceptionTimes = c.exceptionTimes[idx:]
+}
+
+// GetExceptionStats returns the accumulated exception statistics ordered by
+// function and address
+func (c *Client) GetExceptionStats() []ExceptionStat {
+	c.exceptionMutex.Lock()
+	defer c.exceptionMutex.Unlock()
+
+	stats := make([]ExceptionStat, 0, len(c.exceptions))
+	for _, stat := range c.exceptions {
+		stats = append(stats, *stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Function != stats[j].Function {
+			return stats[i].Function < stats[j].Function
+		}
+		return stats[i].Address < stats[j].Address
+	})
+	return stats
+}
+
+// ExceptionRate returns the number of exception responses inside the rolling
+// rate window
+func (c *Client) ExceptionRate() int {
+	c.exceptionMutex.Lock()
+	defer c.exceptionMutex.Unlock()
+
+	c.trimExceptionTimes(time.Now())
+	return len(c.exceptionTimes)
+}
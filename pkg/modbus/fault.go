@@ -0,0 +1,64 @@
+package modbus
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrConnectionDropped is returned by a fault hook to simulate a lost TCP
+// connection: the request fails and the client is marked disconnected so the
+// caller's reconnect logic kicks in
+var ErrConnectionDropped = errors.New("connection dropped by fault injection")
+
+// FaultHooks lets engineering tooling inject faults into every client in the
+// process. Hooks run on the request path and must be fast and thread-safe.
+type FaultHooks struct {
+	// BeforeRequest may delay or fail an outgoing request
+	BeforeRequest func(op string) error
+
+	// TransformResponse may corrupt a response frame before it is parsed
+	TransformResponse func(op string, data []byte) []byte
+}
+
+var (
+	faultMutex sync.RWMutex
+	faultHooks *FaultHooks
+)
+
+// SetFaultHooks installs process-wide fault hooks; nil removes them
+func SetFaultHooks(hooks *FaultHooks) {
+	faultMutex.Lock()
+	defer faultMutex.Unlock()
+	faultHooks = hooks
+}
+
+// currentFaultHooks returns the installed hooks, or nil
+func currentFaultHooks() *FaultHooks {
+	faultMutex.RLock()
+	defer faultMutex.RUnlock()
+	return faultHooks
+}
+
+// injectFault runs the before-request hook, marking the client disconnected
+// when the hook simulates a dropped connection
+func (c *Client) injectFault(op string) error {
+	hooks := currentFaultHooks()
+	if hooks == nil || hooks.BeforeRequest == nil {
+		return nil
+	}
+
+	err := hooks.BeforeRequest(op)
+	if errors.Is(err, ErrConnectionDropped) {
+		go c.markDisconnected()
+	}
+	return err
+}
+
+// transformResponse runs the response hook so tooling can corrupt frames
+func transformResponse(op string, data []byte) []byte {
+	hooks := currentFaultHooks()
+	if hooks == nil || hooks.TransformResponse == nil {
+		return data
+	}
+	return hooks.TransformResponse(op, data)
+}
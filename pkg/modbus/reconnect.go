@@ -0,0 +1,124 @@
+package modbus
+
+import (
+	"context"
+	"time"
+)
+
+// ReconnectSettings configures the automatic reconnection of a client
+type ReconnectSettings struct {
+	// InitialDelay is the first retry delay; every failed attempt doubles
+	// it up to MaxDelay
+	InitialDelay time.Duration
+
+	// MaxDelay caps the exponential backoff; zero uses ten times the
+	// initial delay
+	MaxDelay time.Duration
+
+	// MaxRetries gives up after this many consecutive failed attempts
+	// until the next connection loss; zero retries indefinitely
+	MaxRetries int
+
+	// Keepalive is the cadence of the background connection-state check
+	// that catches losses no request has run into yet; zero uses the
+	// initial delay
+	Keepalive time.Duration
+}
+
+// OnConnectionChange registers a callback invoked on every connection-state
+// transition. Callbacks run on their own goroutine, so they may use the
+// client freely.
+func (c *Client) OnConnectionChange(callback func(connected bool)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stateCallbacks = append(c.stateCallbacks, callback)
+}
+
+// StartAutoReconnect starts the background reconnection of the client: a
+// lost connection is re-established with exponential backoff, and a
+// keepalive check catches losses between requests. The goroutine stops when
+// the context is cancelled.
+func (c *Client) StartAutoReconnect(ctx context.Context, settings ReconnectSettings) {
+	if settings.InitialDelay <= 0 {
+		settings.InitialDelay = time.Second
+	}
+	if settings.MaxDelay <= 0 {
+		settings.MaxDelay = 10 * settings.InitialDelay
+	}
+	if settings.Keepalive <= 0 {
+		settings.Keepalive = settings.InitialDelay
+	}
+
+	go c.reconnectLoop(ctx, settings)
+}
+
+// reconnectLoop waits for a connection loss and re-establishes the
+// connection
+func (c *Client) reconnectLoop(ctx context.Context, settings ReconnectSettings) {
+	keepalive := time.NewTicker(settings.Keepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.lostSignal:
+		case <-keepalive.C:
+		}
+
+		// The loss signal may be stale when the keepalive already
+		// recovered the connection in between
+		if c.IsConnected() {
+			continue
+		}
+		c.reconnect(ctx, settings)
+	}
+}
+
+// reconnect retries Connect with exponential backoff until the connection
+// is back or the retry budget is spent
+func (c *Client) reconnect(ctx context.Context, settings ReconnectSettings) {
+	// Drop the half-open connection before dialing again
+	c.Disconnect()
+
+	delay := settings.InitialDelay
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		if err := c.Connect(ctx); err == nil {
+			return
+		}
+		if settings.MaxRetries > 0 && attempt >= settings.MaxRetries {
+			return
+		}
+		delay = min(delay*2, settings.MaxDelay)
+	}
+}
+
+// setConnected updates the connection state, fires the state callbacks on a
+// transition and wakes the reconnect loop on a loss; the caller must hold
+// the write lock
+func (c *Client) setConnected(connected bool) {
+	if c.isConnected == connected {
+		return
+	}
+	c.isConnected = connected
+
+	callbacks := append([]func(bool){}, c.stateCallbacks...)
+	go func() {
+		for _, callback := range callbacks {
+			callback(connected)
+		}
+	}()
+
+	if !connected {
+		select {
+		case c.lostSignal <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package modbus
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSSettings holds the certificate material for a Modbus/TCP Security
+// connection. The certificate and key identify this side of the link; the
+// CA file validates the peer certificate.
+type TLSSettings struct {
+	Enabled  bool
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// buildTLSConfig loads the certificate material and assembles the TLS
+// configuration for a Modbus/TCP Security connection
+func buildTLSConfig(settings TLSSettings, serverName string) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(settings.CertFile, settings.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	caPEM, err := os.ReadFile(settings.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", settings.CAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      caPool,
+		ServerName:   serverName,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,109 @@
+// Package rolling computes windowed min/max/average statistics over
+// timestamped samples kept in bounded ring buffers, so latest-data API
+// responses can carry short trend summaries without a history query.
+package rolling
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"powerkonnekt/ems/pkg/cache"
+)
+
+// Windows are the reporting windows of every tracker
+var Windows = []time.Duration{time.Minute, 15 * time.Minute}
+
+// Stats summarizes the samples of one field over one window
+type Stats struct {
+	Min     float32 `json:"min"`
+	Max     float32 `json:"max"`
+	Avg     float32 `json:"avg"`
+	Samples int     `json:"samples"`
+}
+
+// sample is one timestamped observation
+type sample struct {
+	at    time.Time
+	value float32
+}
+
+// Tracker keeps the recent samples of a set of named fields in one ring
+// buffer per field. Observations and statistics are safe for concurrent use.
+type Tracker struct {
+	fields map[string]*cache.Ring[sample]
+}
+
+// NewTracker creates a tracker for the given fields. The capacity bounds the
+// samples kept per field and should cover the longest window at the poll
+// rate of the data source.
+func NewTracker(name string, capacity int, fields ...string) *Tracker {
+	tracker := &Tracker{
+		fields: make(map[string]*cache.Ring[sample], len(fields)),
+	}
+	for _, field := range fields {
+		tracker.fields[field] = cache.NewRing[sample](fmt.Sprintf("%s_%s", name, field), capacity)
+	}
+	return tracker
+}
+
+// Observe records one sample of a field; unregistered fields are ignored
+func (t *Tracker) Observe(field string, value float32) {
+	ring, ok := t.fields[field]
+	if !ok {
+		return
+	}
+	ring.Append(sample{at: time.Now(), value: value})
+}
+
+// Stats returns the min/max/average of every field over every reporting
+// window, keyed by field name and window label
+func (t *Tracker) Stats() map[string]map[string]Stats {
+	now := time.Now()
+
+	stats := make(map[string]map[string]Stats, len(t.fields))
+	for field, ring := range t.fields {
+		samples := ring.Snapshot()
+
+		windows := make(map[string]Stats, len(Windows))
+		for _, window := range Windows {
+			windows[windowLabel(window)] = computeStats(samples, now.Add(-window))
+		}
+		stats[field] = windows
+	}
+	return stats
+}
+
+// computeStats aggregates the samples taken at or after the cutoff
+func computeStats(samples []sample, cutoff time.Time) Stats {
+	var stats Stats
+	var sum float64
+
+	for _, s := range samples {
+		if s.at.Before(cutoff) {
+			continue
+		}
+		if stats.Samples == 0 || s.value < stats.Min {
+			stats.Min = s.value
+		}
+		if stats.Samples == 0 || s.value > stats.Max {
+			stats.Max = s.value
+		}
+		sum += float64(s.value)
+		stats.Samples++
+	}
+
+	if stats.Samples > 0 {
+		stats.Avg = float32(sum / float64(stats.Samples))
+	}
+	return stats
+}
+
+// windowLabel renders a window duration without trailing zero units
+// (e.g. "1m" instead of "1m0s")
+func windowLabel(window time.Duration) string {
+	label := window.String()
+	label = strings.TrimSuffix(label, "0s")
+	label = strings.TrimSuffix(label, "0m")
+	return label
+}